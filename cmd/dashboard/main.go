@@ -4,7 +4,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
-	"path/filepath"
+	"runtime"
 	"strings"
 	"time"
 
@@ -14,14 +14,29 @@ import (
 func main() {
 	var (
 		inputFlag   = flag.String("input", "", "Input CSV metrics file")
-		outputFlag  = flag.String("output", "", "Output HTML file (default: input with .html extension)")
+		outputFlag  = flag.String("output", "", "Output file (default: input with -dashboard.html or -dashboard-data.json suffix)")
 		compareFlag = flag.String("compare", "", "Comma-separated list of CSV files to compare")
 		profileFlag = flag.String("profile", "", "Profile name (auto-detected from filename if not set)")
 		titleFlag   = flag.String("title", "Tempo Performance Test Report", "Dashboard title")
 		testType    = flag.String("test-type", "combined", "Test type: ingestion, query, combined")
+		normalize   = flag.String("normalize", "", "Comparison mode: also show key metrics, and chart their changes, per unit of achieved load (mbps or kspans)")
+		mixedRes    = flag.Bool("allow-mixed-resolution", false, "Comparison mode: compare files collected with different query steps or rate windows")
+		maxPoints   = flag.Int("max-points-per-series", 0, "Downsample series with more data points by averaging them into buckets of equal duration (0 keeps all)")
+		formatFlag  = flag.String("format", "html", "Output format: html, or data-json for the resolved dashboard data (sections, series, summaries)")
 	)
 	flag.Parse()
 
+	format, err := dashboard.ParseFormat(*formatFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	// Default output file suffix of the format
+	outputSuffix := "-dashboard.html"
+	if format == dashboard.FormatDataJSON {
+		outputSuffix = "-dashboard-data.json"
+	}
+
 	// Determine mode: single or comparison
 	if *compareFlag != "" {
 		// Comparison mode
@@ -44,18 +59,27 @@ func main() {
 			}
 		}
 
+		normalizeBy, err := dashboard.ParseNormalizeMode(*normalize)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
 		// Auto-detect output path
 		output := *outputFlag
 		if output == "" {
-			output = "comparison-dashboard.html"
+			output = "comparison" + outputSuffix
 		}
 
 		config := dashboard.DashboardConfig{
-			Title:       *titleFlag,
-			ProfileName: "comparison",
-			TestType:    *testType,
-			GeneratedAt: time.Now(),
-			CompareMode: true,
+			Title:                *titleFlag,
+			ProfileName:          "comparison",
+			TestType:             *testType,
+			GeneratedAt:          time.Now(),
+			CompareMode:          true,
+			NormalizeBy:          normalizeBy,
+			AllowMixedResolution: *mixedRes,
+			MaxPointsPerSeries:   *maxPoints,
 		}
 
 		fmt.Printf("Generating comparison dashboard from %d files...\n", len(csvPaths))
@@ -63,12 +87,17 @@ func main() {
 			fmt.Printf("  - %s\n", p)
 		}
 
-		if err := dashboard.GenerateComparison(csvPaths, output, config); err != nil {
+		generate := dashboard.GenerateComparison
+		if format == dashboard.FormatDataJSON {
+			generate = dashboard.GenerateComparisonDataJSON
+		}
+		if err := generate(csvPaths, output, config); err != nil {
 			fmt.Fprintf(os.Stderr, "Error generating comparison dashboard: %v\n", err)
 			os.Exit(1)
 		}
 
 		fmt.Printf("Dashboard generated: %s\n", output)
+		printMemoryUsage()
 		return
 	}
 
@@ -88,33 +117,46 @@ func main() {
 	// Auto-detect output path
 	output := *outputFlag
 	if output == "" {
-		// Remove .csv extension and -metrics suffix, then add -dashboard.html
+		// Remove .csv extension and -metrics suffix, then add the format suffix
 		base := strings.TrimSuffix(*inputFlag, ".csv")
 		base = strings.TrimSuffix(base, "-metrics")
-		output = base + "-dashboard.html"
+		output = base + outputSuffix
 	}
 
-	// Auto-detect profile name from filename (e.g., "small-metrics.csv" -> "small")
+	// Auto-detect profile name from filename (e.g., "small-abc123-metrics.csv" -> "small")
 	profile := *profileFlag
 	if profile == "" {
-		base := filepath.Base(*inputFlag)
-		profile = strings.TrimSuffix(base, "-metrics.csv")
-		profile = strings.TrimSuffix(profile, ".csv")
+		profile = dashboard.ProfileNameFromPath(*inputFlag)
 	}
 
 	config := dashboard.DashboardConfig{
-		Title:       *titleFlag,
-		ProfileName: profile,
-		TestType:    *testType,
-		GeneratedAt: time.Now(),
+		Title:              *titleFlag,
+		ProfileName:        profile,
+		TestType:           *testType,
+		GeneratedAt:        time.Now(),
+		MaxPointsPerSeries: *maxPoints,
 	}
 
 	fmt.Printf("Generating dashboard from %s...\n", *inputFlag)
 
-	if err := dashboard.Generate(*inputFlag, output, config); err != nil {
+	generate := dashboard.Generate
+	if format == dashboard.FormatDataJSON {
+		generate = dashboard.GenerateDataJSON
+	}
+	if err := generate(*inputFlag, output, config); err != nil {
 		fmt.Fprintf(os.Stderr, "Error generating dashboard: %v\n", err)
 		os.Exit(1)
 	}
 
 	fmt.Printf("Dashboard generated: %s\n", output)
+	printMemoryUsage()
+}
+
+// printMemoryUsage reports the memory obtained from the OS, an upper bound of the
+// peak memory used to generate the dashboard
+func printMemoryUsage() {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	fmt.Printf("Memory used: %.1f MiB (%.1f MiB allocated in total)\n",
+		float64(m.Sys)/(1<<20), float64(m.TotalAlloc)/(1<<20))
 }
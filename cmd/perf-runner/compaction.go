@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/metrics"
+	"github.com/redhat/perf-tests-tempo/test/framework/profile"
+)
+
+// checkCompactionBacklog returns the compactor backlog over the load from the
+// collected metrics, with the growth allowed by the profile. It returns nil when
+// the backlog was not collected and the profile sets no threshold.
+func checkCompactionBacklog(p *profile.Profile, metricsFile string) *metrics.CompactionBacklog {
+	var maxGrowth *int
+	if p.SLO != nil {
+		maxGrowth = p.SLO.MaxCompactionBacklogGrowth
+	}
+
+	results, err := metrics.LoadFromCSV(metricsFile)
+	if err != nil {
+		fmt.Printf("Warning: failed to load metrics for the compaction backlog: %v\n", err)
+		results = nil
+	}
+	backlog := metrics.NewCompactionBacklog(results)
+	backlog.MaxGrowth = maxGrowth
+
+	switch {
+	case backlog.Samples == 0 && maxGrowth == nil:
+		return nil
+	case backlog.Samples == 0:
+		fmt.Printf("Warning: no samples of %s, the compaction backlog SLO is not checked\n", metrics.CompactionBacklogMetric)
+	case backlog.Violated():
+		fmt.Printf("Compaction backlog grew by %.0f blocks (%.0f to %.0f, peak %.0f), more than the allowed %d\n",
+			backlog.Growth(), backlog.Start, backlog.End, backlog.Peak, *maxGrowth)
+	default:
+		fmt.Printf("Compaction backlog: %.0f to %.0f blocks (peak %.0f)\n", backlog.Start, backlog.End, backlog.Peak)
+	}
+	return backlog
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/redhat/perf-tests-tempo/test/framework"
+	"github.com/redhat/perf-tests-tempo/test/framework/concurrent"
+	"github.com/redhat/perf-tests-tempo/test/framework/k6"
+	"github.com/redhat/perf-tests-tempo/test/framework/metrics"
+	"github.com/redhat/perf-tests-tempo/test/framework/profile"
+	"github.com/redhat/perf-tests-tempo/test/framework/suite"
+)
+
+// runTenantIsolation runs the noisy-neighbor scenario of a profile: the victim tenant
+// runs the combined load alone (baseline), alongside the aggressor tenant (burst) and
+// alone again (recovery). The phase windows are recorded so collected metrics are
+// split per phase, and the k6 results of each tenant are kept in result.Isolation.
+// It stops at the first phase in which the victim fails.
+func runTenantIsolation(ctx context.Context, fw *framework.Framework, p *profile.Profile, opts *runOptions, result *RunResult, baseConfig *k6.Config, aggressorEndpoint, filePrefix string) (success, sloViolated bool, err error) {
+	ti := p.TenantIsolation
+	result.Isolation = &metrics.TenantIsolation{Victim: k6.DefaultTenant, Aggressor: suite.AggressorTenant}
+
+	phases := []struct{ name, duration string }{
+		{metrics.IsolationBaseline, ti.Baseline},
+		{metrics.IsolationBurst, ti.Burst},
+	}
+	if ti.Recovery != "" {
+		phases = append(phases, struct{ name, duration string }{metrics.IsolationRecovery, ti.Recovery})
+	}
+
+	for i, phase := range phases {
+		if ctx.Err() != nil {
+			return false, false, fmt.Errorf("interrupted before phase %s", phase.name)
+		}
+		fmt.Printf("\n--- Phase %d/%d: %s (%s) ---\n", i+1, len(phases), phase.name, phase.duration)
+
+		k6Config := *baseConfig
+		k6Config.Duration = phase.duration
+		phasePrefix := fmt.Sprintf("%s-%s", filePrefix, phase.name)
+
+		var aggressor *concurrent.Collector[*k6.ParallelResult]
+		if phase.name == metrics.IsolationBurst {
+			aggressorConfig := suite.AggressorK6Config(p, baseConfig, aggressorEndpoint)
+			fmt.Printf("Starting aggressor tenant %s at %.2f MB/s", suite.AggressorTenant, aggressorConfig.MBPerSecond)
+			if aggressorConfig.QueriesPerSecond > 0 {
+				fmt.Printf(" and %d queries/s", aggressorConfig.QueriesPerSecond)
+			}
+			fmt.Println()
+			aggressor = concurrent.NewCollector[*k6.ParallelResult]()
+			aggressor.Go(func() (*k6.ParallelResult, error) {
+				return runAggressor(fw, aggressorConfig, phasePrefix+"-aggressor")
+			})
+		}
+
+		victim, victimErr := runCombinedPhase(fw, p, opts, result, phase.name, &k6Config, phasePrefix)
+		if aggressor != nil {
+			// The aggressor runs for the duration of the burst, wait for it either way
+			aggressorResults, err := aggressor.Wait()
+			if err != nil && victimErr == nil {
+				return false, false, fmt.Errorf("phase %s: %w", phase.name, err)
+			}
+			if len(aggressorResults) > 0 {
+				result.Isolation.RecordLoad(suite.AggressorTenant, phase.name, aggressorResults[0].Ingestion, aggressorResults[0].Query)
+			}
+		}
+		if victimErr != nil {
+			return false, false, fmt.Errorf("phase %s: %w", phase.name, victimErr)
+		}
+		result.Isolation.RecordLoad(k6.DefaultTenant, phase.name, victim.Ingestion, victim.Query)
+
+		if !victim.Success() {
+			fmt.Printf("Phase %s did not succeed, skipping remaining phases\n", phase.name)
+			printIsolationImpact(result.Isolation)
+			return false, victim.ThresholdsFailed(), nil
+		}
+	}
+
+	printIsolationImpact(result.Isolation)
+	return true, false, nil
+}
+
+// runAggressor runs the load of the aggressor tenant: ingestion, plus queries when
+// the profile sets a query rate. Its thresholds do not fail the run, the aggressor
+// is expected to exceed the limits of its tenant.
+func runAggressor(fw *framework.Framework, k6Config *k6.Config, prefix string) (*k6.ParallelResult, error) {
+	if k6Config.QueriesPerSecond > 0 {
+		parallelResult, err := fw.RunK6ParallelTests(k6Config)
+		if err != nil {
+			if parallelResult != nil && parallelResult.Cancelled() {
+				saveK6Result(fw, parallelResult.Ingestion, prefix, k6.TestIngestion)
+				saveK6Result(fw, parallelResult.Query, prefix, k6.TestQuery)
+			}
+			return nil, fmt.Errorf("aggressor k6 tests failed: %w", err)
+		}
+		saveK6Result(fw, parallelResult.Ingestion, prefix, k6.TestIngestion)
+		saveK6Result(fw, parallelResult.Query, prefix, k6.TestQuery)
+		return parallelResult, nil
+	}
+
+	k6Result, err := fw.RunK6Test(k6.TestIngestion, k6Config)
+	if err != nil {
+		if k6Result != nil && k6Result.Cancelled {
+			saveK6Result(fw, k6Result, prefix, k6.TestIngestion)
+		}
+		return nil, fmt.Errorf("aggressor k6 test failed: %w", err)
+	}
+	saveK6Result(fw, k6Result, prefix, k6.TestIngestion)
+	return &k6.ParallelResult{Ingestion: k6Result}, nil
+}
+
+// printIsolationImpact prints the change of the p99 latencies of the victim from its
+// baseline in each later phase
+func printIsolationImpact(isolation *metrics.TenantIsolation) {
+	for _, phase := range []string{metrics.IsolationBurst, metrics.IsolationRecovery} {
+		if isolation.Load(isolation.Victim, phase) == nil {
+			continue
+		}
+		fmt.Printf("Tenant isolation (%s): %s ingestion p99 %s, query p99 %s from baseline\n", phase, isolation.Victim,
+			formatImpact(isolation.IngestionImpact(phase)), formatImpact(isolation.QueryImpact(phase)))
+	}
+}
+
+// formatImpact formats a change in percent, "n/a" when it is unknown
+func formatImpact(percent float64) string {
+	if math.IsNaN(percent) {
+		return "n/a"
+	}
+	return fmt.Sprintf("%+.1f%%", percent)
+}
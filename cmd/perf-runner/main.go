@@ -2,45 +2,146 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"maps"
+	"net/url"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"slices"
 	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
-	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/resource"
-
 	"github.com/redhat/perf-tests-tempo/test/framework"
+	"github.com/redhat/perf-tests-tempo/test/framework/config"
 	"github.com/redhat/perf-tests-tempo/test/framework/k6"
+	"github.com/redhat/perf-tests-tempo/test/framework/metrics"
 	"github.com/redhat/perf-tests-tempo/test/framework/metrics/dashboard"
+	"github.com/redhat/perf-tests-tempo/test/framework/notify"
 	"github.com/redhat/perf-tests-tempo/test/framework/profile"
+	"github.com/redhat/perf-tests-tempo/test/framework/retry"
+	"github.com/redhat/perf-tests-tempo/test/framework/suite"
+	"github.com/redhat/perf-tests-tempo/test/framework/upload"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "cleanup-orphans" {
+		runCleanupOrphans(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "preflight" {
+		runPreflight(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		runServe(os.Args[2:])
+		return
+	}
+
 	var (
 		profilesFlag      = flag.String("profiles", "", "Comma-separated list of profiles to run (e.g., small,medium)")
 		profilesDir       = flag.String("profiles-dir", "profiles", "Directory containing profile YAML files")
 		outputDir         = flag.String("output", "results", "Output directory for metrics")
-		testType          = flag.String("test-type", "combined", "Test type: ingestion, query, combined")
+		testType          = flag.String("test-type", "combined", "Test type: ingestion, query, combined, jaeger, replay")
 		dryRun            = flag.Bool("dry-run", false, "Print what would be executed without running")
+		renderDir         = flag.String("render-manifests", "", "Write the manifests each profile would apply to <dir>/<profile>/ for review, without connecting to the cluster (implies --dry-run)")
 		skipCleanup       = flag.Bool("skip-cleanup", false, "Skip cleanup after tests (useful for debugging)")
+		preserveOnFailure = flag.Bool("preserve-on-failure", false, "Clean up successful profiles but keep failed ones for debugging")
+		preserveTTL       = flag.Duration("preserve-ttl", 24*time.Hour, "How long a preserved namespace is kept before orphan cleanup may remove it")
+		cleanupExpired    = flag.Bool("cleanup-expired", true, "Delete preserved namespaces whose TTL has expired before running")
 		checkMetrics      = flag.Bool("check-metrics", false, "Check and report metric availability after collection")
-		generateDashboard = flag.Bool("generate-dashboard", true, "Generate HTML dashboard after metrics collection")
+		generateDashboard = flag.Bool("generate-dashboard", true, "Generate HTML dashboards after metrics collection (and a comparison dashboard when multiple profiles run)")
 		collectLogs       = flag.Bool("collect-logs", true, "Collect logs from all components after test")
 		nodeSelector      = flag.String("node-selector", "", "Node selector for Tempo pods (e.g., 'node-role.kubernetes.io/infra=')")
+		runID             = flag.String("run-id", "", "Unique ID for this run, used in namespace names, labels, and output files (default: random)")
+		operatorMetrics   = flag.Bool("collect-operator-metrics", false, "Also collect CPU/memory usage of the Tempo and OpenTelemetry operators")
+		verifyIngestion   = flag.Bool("verify-ingestion", true, "Compare spans sent by k6 with spans received and stored by Tempo after ingestion")
+		verifyQueries     = flag.Float64("verify-queries", framework.DefaultQueryVerifyPercent, "Percentage of the traces sampled from query results to fetch again after the query load and check for completeness (0 disables)")
+		minAchievedRate   = flag.Float64("min-achieved-rate", framework.DefaultMinAchievedRatePercent, "Percentage of the requested ingestion and query rates k6 must reach; ingestion below it flags the run as generator-limited")
+		allowUnsafe       = flag.Bool("allow-unsafe-cluster", false, "Run even if the cluster fails the safety guardrails (denylisted, production-labeled, or not allowlisted)")
+		compareBaseline   = flag.Bool("compare-baseline", false, "Compare each profile with the latest earlier run of the same profile, variant, and Tempo version")
+		baselineDir       = flag.String("baseline-dir", "", "Results directory to select baselines from (default: --output)")
+		baselineVersion   = flag.String("baseline-tempo-version", "", "Tempo version of the baseline, or 'any' (default: the version under test)")
+		samplingInterval  = flag.Duration("metrics-server-interval", metrics.DefaultSamplingInterval, "Interval for sampling Tempo CPU/memory from metrics-server, exported if Prometheus is unavailable (0 disables)")
+		scrapeInterval    = flag.Duration("tempo-scrape-interval", metrics.DefaultScrapeInterval, "Interval for scraping the Tempo /metrics endpoints through the API server, exported for the queries Prometheus has no data for (0 disables)")
+		storageInterval   = flag.Duration("storage-sampling-interval", metrics.DefaultStorageSamplingInterval, "Interval for measuring the bytes and objects in the trace storage, exported as the storage footprint series (0 disables)")
+		profileTimeout    = flag.Duration("profile-timeout", 0, "Deadline of each profile run from setup to report, overridden by the timeout of the profile; on expiry the logs and metrics so far are saved and the namespace cleaned up (0 disables)")
+		rollupInterval    = flag.Duration("metrics-rollup-interval", 0, "Write the metrics of each interval, e.g. 30m, to <run>-metrics-rollups while the test runs, so soak tests keep partial results (0 disables)")
+		metricsStep       = flag.Duration("metrics-step", 0, "Step of Prometheus range queries, pinned across runs to be compared (default: TEMPO_PERF_METRICS_STEP or 1m)")
+		metricsRateWindow = flag.Duration("metrics-rate-window", 0, "Range of all rate windows in the metric queries, e.g. 2m (default: TEMPO_PERF_METRICS_RATE_WINDOW or the ranges of the queries)")
+		metricsTimeout    = flag.Duration("metrics-query-timeout", 0, "Timeout of each Prometheus query of the metrics collection; a query that times out is reported and the others are still exported (default: TEMPO_PERF_METRICS_QUERY_TIMEOUT or 60s)")
+		kubeconfig        = flag.String("kubeconfig", "", "Path to the kubeconfig of the target cluster (default: in-cluster config, KUBECONFIG, or ~/.kube/config)")
+		kubeContext       = flag.String("context", "", "Kubeconfig context of the target cluster (default: the current context)")
+		smoke             = flag.Bool("smoke", false, "Run the short fixed smoke workload and compare key metrics with golden ranges, failing if they are out of range")
+		smokeGolden       = flag.String("smoke-golden", "", "Golden ranges of --smoke (default: <profiles-dir>/smoke/golden.yaml)")
+		smokeRecord       = flag.Bool("smoke-record", false, "With --smoke, record the golden ranges from this run instead of checking them")
+		matrix            = flag.String("matrix", "", "Run each profile once per deployment variant with identical k6 settings and compare them, e.g. variant=monolithic,stack")
+		profileSchema     = flag.Bool("profile-schema", false, "Print the JSON Schema of profile YAML files and exit")
+		validateOnly      = flag.Bool("validate", false, "Validate the profiles and exit without connecting to the cluster")
+		tui               = flag.Bool("tui", false, "Show a live terminal view of the progress of each profile, key metrics of the load and the recent output, saving the full output to <output>/perf-runner-<run-id>.log")
+		uploadURL         = flag.String("upload", "", "Upload the results of the run to s3://bucket/prefix or gs://bucket/prefix when it ends (credentials from TEMPO_PERF_UPLOAD_* or AWS_* env vars)")
+		remoteWriteURL    = flag.String("remote-write", "", "Push the collected metrics of each profile to a Prometheus remote write endpoint, e.g. http://mimir:8080/api/v1/push (token and tenant from TEMPO_PERF_REMOTE_WRITE_TOKEN and TEMPO_PERF_REMOTE_WRITE_TENANT)")
 	)
+	flag.BoolVar(generateDashboard, "dashboard", true, "Alias for --generate-dashboard")
 	flag.Parse()
 
+	if *profileSchema {
+		schema, err := profile.SchemaJSON()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		os.Stdout.Write(schema)
+		return
+	}
+
 	// Validate test type
 	tt := k6.TestType(*testType)
 	switch tt {
-	case k6.TestIngestion, k6.TestQuery, k6.TestCombined:
+	case k6.TestIngestion, k6.TestQuery, k6.TestCombined, k6.TestJaeger, k6.TestReplay:
 		// Valid
 	default:
-		fmt.Fprintf(os.Stderr, "Error: invalid test type %q. Must be ingestion, query, or combined\n", *testType)
+		fmt.Fprintf(os.Stderr, "Error: invalid test type %q. Must be ingestion, query, combined, jaeger, or replay\n", *testType)
+		os.Exit(1)
+	}
+
+	if *verifyQueries < 0 || *verifyQueries > 100 {
+		fmt.Fprintf(os.Stderr, "Error: --verify-queries must be between 0 and 100, got %v\n", *verifyQueries)
+		os.Exit(1)
+	}
+
+	// Results are uploaded with static credentials, checked before anything runs
+	var uploader *upload.Uploader
+	if *uploadURL != "" {
+		target, err := upload.ParseURL(*uploadURL)
+		if err == nil {
+			uploader, err = upload.New(target, upload.FromEnv())
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid --upload: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if *remoteWriteURL != "" {
+		if u, err := url.Parse(*remoteWriteURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			fmt.Fprintf(os.Stderr, "Error: invalid --remote-write: %q is not an http(s) URL\n", *remoteWriteURL)
+			os.Exit(1)
+		}
+	}
+
+	// Generate or validate the run ID
+	if *runID == "" {
+		*runID = framework.NewRunID()
+	} else if err := framework.ValidateRunID(*runID); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid run ID: %v\n", err)
 		os.Exit(1)
 	}
 
@@ -48,7 +149,32 @@ func main() {
 	var profiles []*profile.Profile
 	var err error
 
-	if *profilesFlag != "" {
+	// Smoke runs use the fixed smoke profile and its golden ranges
+	var golden *metrics.GoldenRanges
+	if *smokeRecord && !*smoke {
+		fmt.Fprintln(os.Stderr, "Error: --smoke-record requires --smoke")
+		os.Exit(1)
+	}
+	if *smoke {
+		if *profilesFlag != "" {
+			fmt.Fprintln(os.Stderr, "Error: --smoke runs the smoke profile and cannot be combined with --profiles")
+			os.Exit(1)
+		}
+		if *smokeGolden == "" {
+			*smokeGolden = defaultSmokeGoldenPath(*profilesDir)
+		}
+		golden, err = metrics.LoadGoldenRanges(*smokeGolden)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading smoke golden ranges: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if *smoke {
+		var p *profile.Profile
+		p, err = loadSmokeProfile(*profilesDir)
+		profiles = []*profile.Profile{p}
+	} else if *profilesFlag != "" {
 		names := strings.Split(*profilesFlag, ",")
 		profiles, err = profile.LoadByNames(*profilesDir, names)
 	} else {
@@ -65,25 +191,99 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Expand each profile into one profile per variant of the matrix
+	var matrixGroups []matrixGroup
+	if *matrix != "" {
+		if *smoke {
+			fmt.Fprintln(os.Stderr, "Error: --smoke checks the golden ranges of one deployment and cannot be combined with --matrix")
+			os.Exit(1)
+		}
+		variants, err := parseMatrix(*matrix)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid --matrix: %v\n", err)
+			os.Exit(1)
+		}
+		profiles, matrixGroups, err = expandMatrix(profiles, variants)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Check that every namespace name is valid before creating anything
+	for _, p := range profiles {
+		if _, err := framework.RunNamespace(p.Name, *runID); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if tt == k6.TestReplay && len(p.Phases) == 0 && p.K6.Replay == nil {
+			fmt.Fprintf(os.Stderr, "Error: profile %s has no k6.replay capture for the replay test\n", p.Name)
+			os.Exit(1)
+		}
+	}
+
+	if *validateOnly {
+		for _, p := range profiles {
+			fmt.Printf("✓ %s\n", p.Name)
+		}
+		fmt.Printf("%d profile(s) valid\n", len(profiles))
+		return
+	}
+
 	// Print summary
+	fmt.Printf("Run ID: %s\n", *runID)
+	if uploader != nil {
+		fmt.Printf("Results upload: %s\n", uploader.RunURL(*runID))
+	}
 	fmt.Printf("Loaded %d profile(s):\n", len(profiles))
 	for _, p := range profiles {
 		fmt.Printf("  - %s: %s\n", p.Name, p.Description)
 	}
 	fmt.Println()
 
-	if *dryRun {
+	// Estimate the suite duration from the profiles and the timings of past runs
+	historyDir := *baselineDir
+	if historyDir == "" {
+		historyDir = *outputDir
+	}
+	schedule := estimateSchedule(profiles, historyDir)
+
+	if *dryRun || *renderDir != "" {
 		fmt.Println("Dry run mode - would execute the following:")
 		for _, p := range profiles {
 			printProfileSummary(p, tt)
+			namespace, _ := framework.RunNamespace(p.Name, *runID) // validated above
+			fmt.Printf("  Namespace: %s\n", namespace)
+		}
+		fmt.Println()
+		printSchedule("Estimated schedule", schedule, time.Now())
+		if *renderDir != "" {
+			if err := renderManifests(profiles, tt, *runID, *renderDir, parseNodeSelector(*nodeSelector)); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: failed to render manifests: %v\n", err)
+				os.Exit(1)
+			}
 		}
 		return
 	}
 
+	printSchedule("Estimated schedule", schedule, time.Now())
+
 	// Setup context with signal handling
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// Webhook notifications are configured via TEMPO_PERF_NOTIFY_* env vars
+	// and can be overridden per profile
+	notifyConfig, err := notify.FromEnv()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	// running is the profile in progress, reported as failed on force exit
+	var running atomic.Pointer[profile.Profile]
+	// view is the terminal view of --tui, whose terminal is restored on force exit
+	var view atomic.Pointer[tuiView]
+
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
 	go func() {
@@ -92,7 +292,11 @@ func main() {
 		cancel()
 		// Second interrupt force-exits
 		<-sigCh
+		view.Load().restore()
 		fmt.Println("\nForce exit requested, terminating immediately...")
+		if p := running.Load(); p != nil {
+			notifyAborted(notifyConfig, p, fmt.Errorf("force exit requested"))
+		}
 		os.Exit(130) // 128 + SIGINT(2)
 	}()
 
@@ -102,30 +306,142 @@ func main() {
 		os.Exit(1)
 	}
 
+	// The view takes over the terminal until the profiles ran
+	if *tui {
+		v, err := startTUI(*runID, schedule, tuiLogPath(*outputDir, *runID))
+		if err != nil {
+			fmt.Printf("Warning: --tui disabled: %v\n", err)
+		} else {
+			view.Store(v)
+			defer v.stop()
+		}
+	}
+
 	// Parse node selector
 	nodeSelectorMap := parseNodeSelector(*nodeSelector)
 	if len(nodeSelectorMap) > 0 {
 		fmt.Printf("Using node selector: %v\n", nodeSelectorMap)
 	}
 
+	opts := &runOptions{
+		runID:             *runID,
+		testType:          tt,
+		outputDir:         *outputDir,
+		skipCleanup:       *skipCleanup,
+		preserveOnFailure: *preserveOnFailure,
+		preserveTTL:       *preserveTTL,
+		checkMetrics:      *checkMetrics,
+		generateDashboard: *generateDashboard,
+		collectLogs:       *collectLogs,
+		operatorMetrics:   *operatorMetrics,
+		verifyIngestion:   *verifyIngestion,
+		verifyQueries:     *verifyQueries,
+		minAchievedRate:   *minAchievedRate,
+		allowUnsafe:       *allowUnsafe,
+		compareBaseline:   *compareBaseline,
+		baselineDir:       *baselineDir,
+		baselineVersion:   *baselineVersion,
+		samplingInterval:  *samplingInterval,
+		scrapeInterval:    *scrapeInterval,
+		storageInterval:   *storageInterval,
+		profileTimeout:    *profileTimeout,
+		rollupInterval:    *rollupInterval,
+		metricsStep:       *metricsStep,
+		metricsRateWindow: *metricsRateWindow,
+		metricsTimeout:    *metricsTimeout,
+		kubeconfig:        *kubeconfig,
+		kubeContext:       *kubeContext,
+		nodeSelector:      nodeSelectorMap,
+		smokeGolden:       golden,
+		smokeGoldenPath:   *smokeGolden,
+		smokeRecord:       *smokeRecord,
+		uploader:          uploader,
+		remoteWriteURL:    *remoteWriteURL,
+		tui:               view.Load(),
+	}
+	if opts.baselineDir == "" {
+		opts.baselineDir = opts.outputDir
+	}
+
+	// Framework tracing is enabled by the standard OTEL_EXPORTER_OTLP_* env vars
+	if tracingEnabled() {
+		tp, err := newTracerProvider(ctx)
+		if err != nil {
+			fmt.Printf("Warning: framework tracing disabled: %v\n", err)
+		} else {
+			opts.tracerProvider = tp
+			defer func() {
+				if err := tp.Shutdown(context.Background()); err != nil {
+					fmt.Printf("Warning: failed to shut down tracing: %v\n", err)
+				}
+			}()
+		}
+	}
+
+	// Deferred cleanups are completed by the next invocation
+	if *cleanupExpired {
+		cleanupExpiredNamespaces(ctx, opts)
+	}
+
+	notifyRunStarted(notifyConfig, profiles, string(tt))
+
 	// Run profiles sequentially
 	results := make(map[string]*RunResult)
-	for _, p := range profiles {
+	for i, p := range profiles {
 		select {
 		case <-ctx.Done():
+			opts.tui.stop()
 			fmt.Println("Aborted by user")
+			for _, remaining := range profiles[i:] {
+				notifyAborted(notifyConfig, remaining, fmt.Errorf("run aborted by user"))
+			}
+			uploadResults(opts)
 			printSummary(results)
 			os.Exit(1)
 		default:
 		}
 
-		result := runProfile(ctx, p, tt, *outputDir, *skipCleanup, *checkMetrics, *generateDashboard, *collectLogs, nodeSelectorMap)
+		running.Store(p)
+		profileStart := time.Now()
+		result := runProfile(ctx, p, opts)
+		elapsed := time.Since(profileStart) // includes cleanup
+		running.Store(nil)
 		results[p.Name] = result
+		opts.tui.finishProfile(p.Name, result, elapsed)
+
+		if opts.smokeGolden != nil {
+			checkSmoke(result, opts)
+		}
 
 		if result.Error != nil {
 			fmt.Printf("Profile %s failed: %v\n", p.Name, result.Error)
 		}
+		if result.MetadataPath != "" {
+			recordRunDuration(result.MetadataPath, elapsed)
+		}
+		summary := writeRunReport(p, result, opts, notifyConfig)
+
+		// Re-estimate the remaining profiles from now
+		fmt.Printf("\nProfile %s took %s (estimated %s)\n", p.Name, elapsed.Round(time.Second), schedule[i].Estimate().Round(time.Minute))
+		printSchedule("Updated schedule", schedule[i+1:], time.Now())
+
+		notifyProfileResult(notify.New(profileNotifyConfig(notifyConfig, p)).WithReport(summary), result)
 	}
+	opts.tui.stop()
+
+	// Compare the variants of each profile of a matrix run
+	if len(matrixGroups) > 0 {
+		writeMatrixComparisons(matrixGroups, results, opts)
+	}
+
+	// Compare all profiles of this run side by side; a matrix of one profile
+	// already has its comparison
+	if opts.generateDashboard && len(matrixGroups) != 1 {
+		generateComparisonDashboard(profiles, results, opts)
+	}
+
+	// Upload after the comparison dashboard, so it is included
+	uploadErr := uploadResults(opts)
 
 	// Print summary
 	printSummary(results)
@@ -136,44 +452,192 @@ func main() {
 			os.Exit(1)
 		}
 	}
+	if uploadErr != nil {
+		os.Exit(1)
+	}
 }
 
 // RunResult holds the result of running a profile
 type RunResult struct {
-	Profile  string
-	Success  bool
-	Duration time.Duration
-	Error    error
+	Profile       string
+	Success       bool
+	Duration      time.Duration
+	Error         error
+	SLOViolated   bool
+	MetricsPath   string
+	DashboardPath string
+	// MetadataPath is the run metadata file, set when metrics were collected
+	MetadataPath string
+	// GeneratorLimited is set when k6 did not reach the requested ingestion rate
+	GeneratorLimited bool
+	// Attainment holds the requested vs achieved ingestion and query rates
+	Attainment metrics.LoadAttainment
+	// Isolation holds the per-tenant loads of a noisy-neighbor run
+	Isolation *metrics.TenantIsolation
+	// CompactionBacklog is the compactor backlog over the load, set when metrics
+	// were collected
+	CompactionBacklog *metrics.CompactionBacklog
+	// ReportPath is the Markdown summary of the run
+	ReportPath string
+	// TimedOut is set when the run exceeded its timeout in TimedOutPhase
+	TimedOut      bool
+	TimedOutPhase string
+	// Retries are the retried operations of the run, including cleanup
+	Retries []retry.OperationStats
+	// Phases are the recorded test phases of the run
+	Phases []metrics.Phase
 }
 
-func runProfile(ctx context.Context, p *profile.Profile, testType k6.TestType, outputDir string, skipCleanup, checkMetrics, generateDashboard, collectLogs bool, nodeSelector map[string]string) *RunResult {
+// runOptions holds the command-line settings shared by all profile runs
+type runOptions struct {
+	runID             string
+	testType          k6.TestType
+	outputDir         string
+	skipCleanup       bool
+	preserveOnFailure bool
+	preserveTTL       time.Duration
+	checkMetrics      bool
+	generateDashboard bool
+	collectLogs       bool
+	operatorMetrics   bool
+	verifyIngestion   bool
+	verifyQueries     float64
+	minAchievedRate   float64
+	allowUnsafe       bool
+	compareBaseline   bool
+	baselineDir       string
+	baselineVersion   string
+	samplingInterval  time.Duration
+	scrapeInterval    time.Duration
+	storageInterval   time.Duration
+	profileTimeout    time.Duration
+	rollupInterval    time.Duration
+	metricsStep       time.Duration
+	metricsRateWindow time.Duration
+	metricsTimeout    time.Duration
+	kubeconfig        string
+	kubeContext       string
+	nodeSelector      map[string]string
+	// smokeGolden are the golden ranges checked after --smoke runs (nil otherwise)
+	smokeGolden     *metrics.GoldenRanges
+	smokeGoldenPath string
+	smokeRecord     bool
+	// uploader pushes the results of the run to object storage (nil when disabled)
+	uploader *upload.Uploader
+	// remoteWriteURL is the remote write endpoint collected metrics are pushed to
+	// (empty when disabled)
+	remoteWriteURL string
+	// tracerProvider exports the spans of the framework itself (nil when disabled)
+	tracerProvider *sdktrace.TracerProvider
+	// tui is the live terminal view of --tui (nil when disabled)
+	tui *tuiView
+}
+
+func runProfile(ctx context.Context, p *profile.Profile, opts *runOptions) *RunResult {
+	testType := opts.testType
+	outputDir := opts.outputDir
+	checkMetrics := opts.checkMetrics
+	generateDashboard := opts.generateDashboard
+	collectLogs := opts.collectLogs
+	nodeSelector := opts.nodeSelector
+
 	startTime := time.Now()
 	result := &RunResult{Profile: p.Name}
 
-	namespace := fmt.Sprintf("tempo-perf-%s", p.Name)
+	namespace, err := framework.RunNamespace(p.Name, opts.runID)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	// Prefix for all output files of this profile, unique per run
+	filePrefix := fmt.Sprintf("%s/%s-%s", outputDir, p.Name, opts.runID)
 	fmt.Printf("\n========================================\n")
 	fmt.Printf("Running profile: %s\n", p.Name)
 	fmt.Printf("Namespace: %s\n", namespace)
 	fmt.Printf("========================================\n\n")
 
+	// Record the whole run, including cleanup, as one trace
+	ctx, span := startRunSpan(ctx, p, opts, namespace)
+	defer endRunSpan(span, result, opts)
+
+	// Bound the whole run, so a hung setup or k6 job cannot stall the suite.
+	// The grace period of a timed out run is derived from signalCtx instead, so an
+	// interrupt still aborts it.
+	signalCtx := ctx
+	timeout := suite.Timeout(p, opts.profileTimeout)
+	if timeout > 0 {
+		var cancelRun context.CancelFunc
+		ctx, cancelRun = context.WithTimeoutCause(ctx, timeout, errProfileTimeout)
+		defer cancelRun()
+	}
+	stage := stageSetup
+	opts.tui.setStage(p.Name, stage)
+	var testStartTime time.Time
+	// advance moves the run to the next stage, or reports that it timed out
+	advance := func(next string) bool {
+		if context.Cause(ctx) == errProfileTimeout {
+			return false
+		}
+		stage = next
+		opts.tui.setStage(p.Name, stage)
+		return true
+	}
+
 	// Create framework
-	fw, err := framework.New(ctx, namespace)
+	// Operator namespaces can also be set with TEMPO_PERF_OPERATOR_NAMESPACES
+	fwConfig := config.FromEnv()
+	if opts.operatorMetrics && len(fwConfig.OperatorNamespaces) == 0 {
+		fwConfig = fwConfig.WithOperatorNamespaces(config.DefaultOperatorNamespaces)
+	}
+	// Guardrails can also be overridden with TEMPO_PERF_ALLOW_UNSAFE_CLUSTER
+	if opts.allowUnsafe {
+		fwConfig = fwConfig.WithAllowUnsafeCluster(true)
+	}
+	// The query resolution can also be set with TEMPO_PERF_METRICS_STEP and TEMPO_PERF_METRICS_RATE_WINDOW
+	if opts.metricsStep > 0 {
+		fwConfig = fwConfig.WithMetricsResolution(opts.metricsStep, fwConfig.MetricsRateWindow)
+	}
+	if opts.metricsRateWindow > 0 {
+		fwConfig = fwConfig.WithMetricsResolution(fwConfig.MetricsQueryStep, opts.metricsRateWindow)
+	}
+	if opts.metricsTimeout > 0 {
+		fwConfig = fwConfig.WithMetricsQueryTimeout(opts.metricsTimeout)
+	}
+
+	fwOpts := []framework.Option{
+		framework.WithRunID(opts.runID),
+		framework.WithConfig(fwConfig),
+		framework.WithKubeconfig(opts.kubeconfig),
+		framework.WithKubeContext(opts.kubeContext),
+		framework.WithNamespaceQuota(suite.NamespaceQuota(p)),
+	}
+	if opts.tracerProvider != nil {
+		fwOpts = append(fwOpts, framework.WithTracerProvider(opts.tracerProvider))
+	}
+	fw, err := framework.New(ctx, namespace, fwOpts...)
 	if err != nil {
 		result.Error = fmt.Errorf("failed to create framework: %w", err)
 		result.Duration = time.Since(startTime)
 		return result
 	}
+	// Runs last, so the retries of the cleanup are included
+	defer func() {
+		result.Retries = fw.RetryStats().Snapshot()
+		result.Phases = fw.Phases()
+	}()
 
-	// Clean up any leftover resources from previous runs
-	fmt.Println("Cleaning up previous resources...")
-	if cleanupErr := fw.Cleanup(); cleanupErr != nil {
-		fmt.Printf("Warning: pre-cleanup failed (may be expected if namespace doesn't exist): %v\n", cleanupErr)
+	// Refuse to run against clusters that fail the safety guardrails, e.g. a
+	// production cluster selected by the wrong kubeconfig
+	if err := fw.CheckClusterSafety(); err != nil {
+		result.Error = fmt.Errorf("%w (use --allow-unsafe-cluster to override)", err)
+		result.Duration = time.Since(startTime)
+		return result
 	}
 
-	// Re-create framework after cleanup (namespace was deleted)
-	fw, err = framework.New(ctx, namespace)
-	if err != nil {
-		result.Error = fmt.Errorf("failed to re-create framework after cleanup: %w", err)
+	// Refuse to reuse a namespace from another run; checked before cleanup is
+	// deferred so a colliding run never deletes resources it does not own
+	if err := fw.CheckNamespaceAvailable(); err != nil {
+		result.Error = fmt.Errorf("%w (choose a different --run-id or clean up the namespace)", err)
 		result.Duration = time.Since(startTime)
 		return result
 	}
@@ -183,9 +647,17 @@ func runProfile(ctx context.Context, p *profile.Profile, testType k6.TestType, o
 		fw.SetTempoNodeSelector(nodeSelector)
 	}
 
-	// Cleanup after test unless skipped
-	if !skipCleanup {
+	// A timed out run collects its results and cleans up within a grace period
+	graceCancel := context.CancelFunc(func() {})
+	defer func() { graceCancel() }()
+
+	// Cleanup after test unless skipped, or preserve the environment of failed profiles
+	if !opts.skipCleanup {
 		defer func() {
+			if opts.preserveOnFailure && result.Error != nil {
+				preserveFailedProfile(fw, p, opts)
+				return
+			}
 			fmt.Printf("\nCleaning up namespace %s...\n", namespace)
 			if cleanupErr := fw.Cleanup(); cleanupErr != nil {
 				fmt.Printf("Warning: cleanup failed: %v\n", cleanupErr)
@@ -193,6 +665,20 @@ func runProfile(ctx context.Context, p *profile.Profile, testType k6.TestType, o
 		}()
 	}
 
+	// Save what a timed out run collected so far; this runs before the cleanup
+	defer func() {
+		if context.Cause(ctx) != errProfileTimeout {
+			return
+		}
+		var graceCtx context.Context
+		graceCtx, graceCancel = context.WithTimeout(signalCtx, timeoutGracePeriod)
+		fw.SetContext(graceCtx)
+		if stage != stageDone {
+			salvageTimedOutRun(fw, p, opts, result, stage, testStartTime, filePrefix, timeout)
+			result.Duration = time.Since(startTime)
+		}
+	}()
+
 	// Check prerequisites
 	fmt.Println("Checking prerequisites...")
 	prereqs, err := fw.CheckPrerequisites()
@@ -202,12 +688,31 @@ func runProfile(ctx context.Context, p *profile.Profile, testType k6.TestType, o
 		return result
 	}
 	if !prereqs.AllMet {
+		writePrerequisites(fw, filePrefix)
 		result.Error = fmt.Errorf("prerequisites not met: Tempo=%v, OTel=%v",
 			prereqs.TempoOperator.Installed, prereqs.OpenTelemetryOperator.Installed)
 		result.Duration = time.Since(startTime)
 		return result
 	}
 
+	// Compare the runner clock with the cluster, so metric windows match the samples
+	fmt.Println("Checking clock synchronization...")
+	checkClockSkew(fw)
+
+	// Record cluster events from setup on, so scheduling problems of Tempo show up too
+	eventsSaved := false
+	if _, err := fw.WatchEvents(); err != nil {
+		fmt.Printf("Warning: cluster events will not be recorded: %v\n", err)
+	} else {
+		// Save the events of runs that fail before the load finished, e.g. pods
+		// rejected by the namespace quota; this runs before the namespace cleanup
+		defer func() {
+			if !eventsSaved {
+				writeClusterEvents(fw, filePrefix)
+			}
+		}()
+	}
+
 	// Enable user workload monitoring for Tempo metrics collection
 	fmt.Println("Enabling user workload monitoring...")
 	if err := fw.EnableUserWorkloadMonitoring(); err != nil {
@@ -215,22 +720,31 @@ func runProfile(ctx context.Context, p *profile.Profile, testType k6.TestType, o
 		fmt.Println("Tempo metrics may not be available. Continuing anyway...")
 	}
 
-	// Setup MinIO with storage size from profile
-	minioConfig := getMinIOConfig(p)
-	if minioConfig != nil {
-		fmt.Printf("Setting up MinIO with %s storage...\n", minioConfig.StorageSize)
+	// Record the operators and monitoring state the run measures against
+	prerequisites := writePrerequisites(fw, filePrefix)
+
+	// Setup MinIO with storage size from profile (not needed when traces are stored on a PV)
+	if p.Storage.UsesPV() {
+		fmt.Println("Skipping MinIO: traces are stored on a persistent volume")
 	} else {
-		fmt.Println("Setting up MinIO...")
-	}
-	if err := fw.SetupMinIOWithConfig(minioConfig); err != nil {
-		result.Error = fmt.Errorf("failed to setup MinIO: %w", err)
-		result.Duration = time.Since(startTime)
-		return result
+		minioConfig := suite.MinIOConfig(p)
+		if minioConfig != nil && minioConfig.Replicas > 1 {
+			fmt.Printf("Setting up distributed MinIO with %d servers...\n", minioConfig.Replicas)
+		} else if minioConfig != nil && minioConfig.StorageSize != "" {
+			fmt.Printf("Setting up MinIO with %s storage...\n", minioConfig.StorageSize)
+		} else {
+			fmt.Println("Setting up MinIO...")
+		}
+		if err := fw.SetupMinIOWithConfig(minioConfig); err != nil {
+			result.Error = fmt.Errorf("failed to setup MinIO: %w", err)
+			result.Duration = time.Since(startTime)
+			return result
+		}
 	}
 
 	// Setup Tempo with profile resources
 	fmt.Printf("Setting up Tempo (%s)...\n", p.Tempo.Variant)
-	resourceConfig := profileToResourceConfig(p, nodeSelector)
+	resourceConfig := suite.ResourceConfig(p, nodeSelector)
 	if err := fw.SetupTempo(p.Tempo.Variant, resourceConfig); err != nil {
 		result.Error = fmt.Errorf("failed to setup Tempo: %w", err)
 		result.Duration = time.Since(startTime)
@@ -244,6 +758,18 @@ func runProfile(ctx context.Context, p *profile.Profile, testType k6.TestType, o
 		result.Duration = time.Since(startTime)
 		return result
 	}
+	// The aggressor of a noisy-neighbor run pushes through its own collector, which
+	// sets its tenant on the exported traces
+	var aggressorEndpoint string
+	if p.TenantIsolation != nil {
+		fmt.Printf("Setting up OTel Collector for tenant %s...\n", suite.AggressorTenant)
+		aggressorEndpoint, err = fw.SetupTenantCollector(p.Tempo.Variant, suite.AggressorTenant)
+		if err != nil {
+			result.Error = fmt.Errorf("failed to setup OTel Collector for tenant %s: %w", suite.AggressorTenant, err)
+			result.Duration = time.Since(startTime)
+			return result
+		}
+	}
 
 	// Setup Tempo monitoring (ServiceMonitor verification and PodMonitor fallback)
 	fmt.Println("Setting up Tempo monitoring...")
@@ -251,6 +777,12 @@ func runProfile(ctx context.Context, p *profile.Profile, testType k6.TestType, o
 		fmt.Printf("Warning: failed to setup Tempo monitoring: %v\n", err)
 		// Continue anyway - metrics may still work
 	}
+	if err := fw.SetupOTelCollectorMonitoring(); err != nil {
+		fmt.Printf("Warning: failed to setup OTel Collector monitoring: %v\n", err)
+	}
+	if err := fw.SetupAlertRules(); err != nil {
+		fmt.Printf("Warning: failed to setup alert rules: %v\n", err)
+	}
 
 	// Setup k6 Prometheus metrics export
 	fmt.Println("Setting up k6 Prometheus metrics...")
@@ -260,27 +792,92 @@ func runProfile(ctx context.Context, p *profile.Profile, testType k6.TestType, o
 		// Continue anyway - k6 will just not export to Prometheus
 	}
 
-	// Run k6 test(s)
-	testStartTime := time.Now()
-	k6Config := profileToK6Config(p)
+	// Sample resource usage from metrics-server in case Prometheus is unavailable
+	if opts.samplingInterval > 0 {
+		if err := fw.StartResourceSampler(opts.samplingInterval); err != nil {
+			fmt.Printf("Warning: resource sampling fallback disabled: %v\n", err)
+		}
+	}
+	// Scrape Tempo directly in case Prometheus does not scrape the namespace
+	if opts.scrapeInterval > 0 {
+		if err := fw.StartMetricsScraper(opts.scrapeInterval); err != nil {
+			fmt.Printf("Warning: Tempo metrics scrape fallback disabled: %v\n", err)
+		}
+	}
+	// Measure the object storage to relate its growth to the ingested bytes
+	if opts.storageInterval > 0 && !p.Storage.UsesPV() {
+		if err := fw.StartStorageSampler(opts.storageInterval, resourceConfig.Storage); err != nil {
+			fmt.Printf("Warning: storage footprint disabled: %v\n", err)
+		}
+	}
+
+	// Run k6 test(s); windows use cluster time so they match the metric timestamps
+	if !advance(stageLoad) {
+		return result
+	}
+	testStartTime = fw.Now()
+	opts.tui.watchMetrics(ctx, fw, p.Name)
+	if opts.rollupInterval > 0 {
+		if err := fw.StartMetricsRollups(testStartTime, fmt.Sprintf("%s-metrics.csv", filePrefix), opts.rollupInterval); err != nil {
+			fmt.Printf("Warning: metrics rollups disabled: %v\n", err)
+		}
+	}
+	k6Config := suite.K6Config(p)
 	k6Config.PrometheusRWURL = prometheusRWURL
 
-	var testSuccess bool
+	var testSuccess, sloViolated bool
 	var k6Metrics *k6.K6Metrics
-	if testType == k6.TestCombined {
+	if len(p.Phases) > 0 {
+		// Run the phases of the profile in order against the same deployment
+		fmt.Printf("Running %d test phases...\n", len(p.Phases))
+		testSuccess, sloViolated, err = runPhases(ctx, fw, p, opts, result, k6Config, filePrefix)
+		if err != nil {
+			result.Error = err
+			result.SLOViolated = sloViolated
+			result.Duration = time.Since(startTime)
+			return result
+		}
+	} else if p.TenantIsolation != nil {
+		// Run the victim alone, alongside the aggressor, and alone again
+		fmt.Println("Running tenant isolation test (noisy neighbor)...")
+		testSuccess, sloViolated, err = runTenantIsolation(ctx, fw, p, opts, result, k6Config, aggressorEndpoint, filePrefix)
+		if err != nil {
+			result.Error = err
+			result.SLOViolated = sloViolated
+			result.Duration = time.Since(startTime)
+			return result
+		}
+	} else if testType == k6.TestCombined {
 		// Run ingestion and query as separate parallel jobs
 		fmt.Println("Running parallel k6 tests (ingestion + query as separate jobs)...")
+		k6Config.BeforeQuery = queryCorpusHook(fw, p, filePrefix)
 		parallelResult, err := fw.RunK6ParallelTests(k6Config)
 		if err != nil {
+			// Keep the logs the cancelled jobs wrote before they were deleted
+			if parallelResult != nil && parallelResult.Cancelled() {
+				saveK6Result(fw, parallelResult.Ingestion, filePrefix, k6.TestIngestion)
+				saveK6Result(fw, parallelResult.Query, filePrefix, k6.TestQuery)
+			}
 			result.Error = fmt.Errorf("parallel k6 tests failed: %w", err)
 			result.Duration = time.Since(startTime)
 			return result
 		}
 		testSuccess = parallelResult.Success()
+		sloViolated = parallelResult.ThresholdsFailed()
+
+		// Verify before exporting so the report is included in the ingestion metrics file
+		if opts.verifyIngestion && parallelResult.Ingestion != nil {
+			verifyIngestionCompleteness(fw, parallelResult.Ingestion, p.Tempo.Variant, testStartTime)
+			checkIngestionRate(fw, result, parallelResult.Ingestion, k6Config.MBPerSecond, opts.minAchievedRate, testStartTime)
+		}
+		if parallelResult.Query != nil {
+			checkQueryRate(fw, result, parallelResult.Query, k6Config.QueriesPerSecond, opts.minAchievedRate)
+			verifyQueryCorrectness(fw, parallelResult.Query, p.Tempo.Variant, opts.verifyQueries)
+		}
 
 		// Save k6 logs to files and collect metrics
 		if parallelResult.Ingestion != nil && parallelResult.Ingestion.Output != "" {
-			logFile := fmt.Sprintf("%s/%s-k6-ingestion.log", outputDir, p.Name)
+			logFile := fmt.Sprintf("%s-k6-ingestion.log", filePrefix)
 			if err := os.WriteFile(logFile, []byte(parallelResult.Ingestion.Output), 0644); err != nil {
 				fmt.Printf("Warning: failed to save ingestion logs: %v\n", err)
 			} else {
@@ -288,14 +885,14 @@ func runProfile(ctx context.Context, p *profile.Profile, testType k6.TestType, o
 			}
 			// Export ingestion k6 metrics
 			if parallelResult.Ingestion.Metrics != nil {
-				metricsFile := fmt.Sprintf("%s/%s-k6-ingestion-metrics.json", outputDir, p.Name)
-				if err := fw.ExportK6Metrics(parallelResult.Ingestion.Metrics, metricsFile, "ingestion"); err != nil {
+				metricsFile := fmt.Sprintf("%s-k6-ingestion-metrics.json", filePrefix)
+				if err := fw.ExportK6Result(parallelResult.Ingestion, metricsFile, "ingestion"); err != nil {
 					fmt.Printf("Warning: failed to export ingestion k6 metrics: %v\n", err)
 				}
 			}
 		}
 		if parallelResult.Query != nil && parallelResult.Query.Output != "" {
-			logFile := fmt.Sprintf("%s/%s-k6-query.log", outputDir, p.Name)
+			logFile := fmt.Sprintf("%s-k6-query.log", filePrefix)
 			if err := os.WriteFile(logFile, []byte(parallelResult.Query.Output), 0644); err != nil {
 				fmt.Printf("Warning: failed to save query logs: %v\n", err)
 			} else {
@@ -304,8 +901,8 @@ func runProfile(ctx context.Context, p *profile.Profile, testType k6.TestType, o
 			// Export query k6 metrics
 			if parallelResult.Query.Metrics != nil {
 				k6Metrics = parallelResult.Query.Metrics // Keep for dashboard
-				metricsFile := fmt.Sprintf("%s/%s-k6-query-metrics.json", outputDir, p.Name)
-				if err := fw.ExportK6Metrics(parallelResult.Query.Metrics, metricsFile, "query"); err != nil {
+				metricsFile := fmt.Sprintf("%s-k6-query-metrics.json", filePrefix)
+				if err := fw.ExportK6Result(parallelResult.Query, metricsFile, "query"); err != nil {
 					fmt.Printf("Warning: failed to export query k6 metrics: %v\n", err)
 				}
 			}
@@ -313,18 +910,41 @@ func runProfile(ctx context.Context, p *profile.Profile, testType k6.TestType, o
 	} else {
 		// Run single test type
 		fmt.Printf("Running k6 %s test...\n", testType)
+		if testType == k6.TestQuery {
+			generateQueryCorpus(fw, p, k6Config, filePrefix)
+		}
 		k6Result, err := fw.RunK6Test(testType, k6Config)
 		if err != nil {
+			if k6Result != nil && k6Result.Cancelled {
+				saveK6Result(fw, k6Result, filePrefix, testType)
+			}
 			result.Error = fmt.Errorf("k6 test failed: %w", err)
+			result.SLOViolated = k6Result.ThresholdsFailed()
 			result.Duration = time.Since(startTime)
 			return result
 		}
 		testSuccess = k6Result.Success
+		sloViolated = k6Result.ThresholdsFailed()
 		k6Metrics = k6Result.Metrics
 
+		// Verify before exporting so the report is included in the k6 metrics file
+		if opts.verifyIngestion && (testType == k6.TestIngestion || testType == k6.TestReplay) {
+			verifyIngestionCompleteness(fw, k6Result, p.Tempo.Variant, testStartTime)
+			// The replay test follows the recorded pace and has no target rate
+			if testType == k6.TestIngestion {
+				checkIngestionRate(fw, result, k6Result, k6Config.MBPerSecond, opts.minAchievedRate, testStartTime)
+			}
+		}
+		if testType == k6.TestQuery || testType == k6.TestJaeger {
+			checkQueryRate(fw, result, k6Result, k6Config.QueriesPerSecond, opts.minAchievedRate)
+		}
+		if testType == k6.TestQuery {
+			verifyQueryCorrectness(fw, k6Result, p.Tempo.Variant, opts.verifyQueries)
+		}
+
 		// Save k6 logs to file
 		if k6Result.Output != "" {
-			logFile := fmt.Sprintf("%s/%s-k6-%s.log", outputDir, p.Name, testType)
+			logFile := fmt.Sprintf("%s-k6-%s.log", filePrefix, testType)
 			if err := os.WriteFile(logFile, []byte(k6Result.Output), 0644); err != nil {
 				fmt.Printf("Warning: failed to save k6 logs: %v\n", err)
 			} else {
@@ -334,8 +954,8 @@ func runProfile(ctx context.Context, p *profile.Profile, testType k6.TestType, o
 
 		// Export k6 metrics to JSON
 		if k6Metrics != nil {
-			metricsFile := fmt.Sprintf("%s/%s-k6-%s-metrics.json", outputDir, p.Name, testType)
-			if err := fw.ExportK6Metrics(k6Metrics, metricsFile, string(testType)); err != nil {
+			metricsFile := fmt.Sprintf("%s-k6-%s-metrics.json", filePrefix, testType)
+			if err := fw.ExportK6Result(k6Result, metricsFile, string(testType)); err != nil {
 				fmt.Printf("Warning: failed to export k6 metrics: %v\n", err)
 			}
 		}
@@ -346,52 +966,95 @@ func runProfile(ctx context.Context, p *profile.Profile, testType k6.TestType, o
 		fmt.Println("✅ k6 metrics parsed from JSON summary")
 	}
 
+	// Save cluster events before anything else, they often explain a failed run
+	events := writeClusterEvents(fw, filePrefix)
+	eventsSaved = true
+	writeAlerts(fw, filePrefix, testStartTime)
+
 	if !testSuccess {
+		// Only crossed thresholds are SLO violations; other k6 failures are plain failures
+		result.SLOViolated = sloViolated
 		result.Error = fmt.Errorf("k6 test did not succeed")
 		result.Duration = time.Since(startTime)
 		return result
 	}
 
+	testDuration := fw.Now().Sub(testStartTime)
+
 	// Collect metrics
-	metricsFile := fmt.Sprintf("%s/%s-metrics.csv", outputDir, p.Name)
+	if !advance(stageMetrics) {
+		return result
+	}
+	metricsFile := fmt.Sprintf("%s-metrics.csv", filePrefix)
 	fmt.Printf("Collecting metrics to %s...\n", metricsFile)
-	if err := fw.CollectMetrics(testStartTime, metricsFile); err != nil {
+	if err := collectMetrics(fw, p, testStartTime, metricsFile, opts.rollupInterval); err != nil {
 		fmt.Printf("Warning: failed to collect metrics: %v\n", err)
+	} else {
+		result.MetricsPath = metricsFile
+		result.CompactionBacklog = checkCompactionBacklog(p, metricsFile)
+		pushRemoteWrite(fw, p, opts, metricsFile)
+	}
+
+	// Collect logs from all components if requested. This runs before the run is
+	// recorded so the log error summary is part of the metadata and dashboard.
+	if !advance(stageLogs) {
+		return result
+	}
+	var logErrors *metrics.LogErrorSummary
+	if collectLogs {
+		fmt.Println("\nCollecting component logs...")
+		logConfig := &framework.LogCollectionConfig{
+			OutputDir: outputDir,
+		}
+		if logs, err := fw.CollectLogs(logConfig); err != nil {
+			fmt.Printf("Warning: failed to collect logs: %v\n", err)
+		} else {
+			logErrors = fw.AnalyzeLogs(logs)
+		}
+
+		// Dump Tempo CR for debugging/reference
+		if _, err := fw.DumpTempoCR(p.Tempo.Variant, outputDir); err != nil {
+			fmt.Printf("Warning: failed to dump Tempo CR: %v\n", err)
+		}
+	}
+
+	// Record the run so later runs can select it as a baseline
+	if !advance(stageReport) {
+		return result
+	}
+	var runMeta *metrics.RunMetadata
+	if result.MetricsPath != "" {
+		runMeta = recordRunMetadata(fw, p, opts, result, filePrefix, metricsFile, testStartTime, testDuration, logErrors)
+		result.MetadataPath = filePrefix + metrics.RunMetadataSuffix
 	}
 
 	// Check metric availability if requested
 	if checkMetrics {
 		fmt.Println("\nChecking metric availability...")
-		testDuration := time.Since(testStartTime)
-		report, err := fw.CheckMetricAvailability(testDuration)
+		report, err := fw.CheckMetricAvailability(fw.Now().Sub(testStartTime))
 		if err != nil {
 			fmt.Printf("Warning: failed to check metric availability: %v\n", err)
 		} else {
 			fw.PrintMetricAvailabilityReport(report)
-
-			// Print diagnostic hints if there are missing metrics
-			if report.MissingMetrics > 0 {
-				issues := fw.DiagnoseMetricIssues(report)
-				if len(issues) > 0 {
-					fmt.Println("\nDiagnostic hints:")
-					for _, issue := range issues {
-						fmt.Printf("  ⚠️  %s\n", issue)
-					}
-				}
-			}
+			printDiagnosticHints(fw, report)
 		}
 	}
 
 	// Generate dashboard if requested
 	if generateDashboard {
-		dashboardFile := fmt.Sprintf("%s/%s-dashboard.html", outputDir, p.Name)
+		dashboardFile := fmt.Sprintf("%s-dashboard.html", filePrefix)
 		fmt.Printf("Generating dashboard to %s...\n", dashboardFile)
 
 		dashConfig := dashboard.DashboardConfig{
-			Title:       "Tempo Performance Test Report",
-			ProfileName: p.Name,
-			TestType:    "combined",
-			GeneratedAt: time.Now(),
+			Title:         "Tempo Performance Test Report",
+			ProfileName:   p.Name,
+			TestType:      profileTestType(p, testType),
+			GeneratedAt:   time.Now(),
+			LogErrors:     logErrors,
+			Attainment:    runAttainment(result),
+			Events:        events,
+			Prerequisites: prerequisites,
+			Isolation:     result.Isolation,
 		}
 
 		// Add ingester config if present in profile
@@ -413,25 +1076,24 @@ func runProfile(ctx context.Context, p *profile.Profile, testType k6.TestType, o
 			fmt.Printf("Warning: failed to generate dashboard: %v\n", err)
 		} else {
 			fmt.Printf("Dashboard generated: %s\n", dashboardFile)
+			result.DashboardPath = dashboardFile
 		}
 	}
 
-	// Collect logs from all components if requested
-	if collectLogs {
-		fmt.Println("\nCollecting component logs...")
-		logConfig := &framework.LogCollectionConfig{
-			OutputDir: outputDir,
-		}
-		if _, err := fw.CollectLogs(logConfig); err != nil {
-			fmt.Printf("Warning: failed to collect logs: %v\n", err)
-		}
-
-		// Dump Tempo CR for debugging/reference
-		if _, err := fw.DumpTempoCR(p.Tempo.Variant, outputDir); err != nil {
-			fmt.Printf("Warning: failed to dump Tempo CR: %v\n", err)
-		}
+	// Compare with the latest matching earlier run
+	if opts.compareBaseline && runMeta != nil {
+		compareWithBaseline(fw, runMeta, opts, filePrefix)
 	}
 
+	if !advance(stageDone) {
+		return result
+	}
+	if backlog := result.CompactionBacklog; backlog.Violated() {
+		result.SLOViolated = true
+		result.Error = fmt.Errorf("compaction backlog grew by %.0f blocks, more than the allowed %d", backlog.Growth(), *backlog.MaxGrowth)
+		result.Duration = time.Since(startTime)
+		return result
+	}
 	result.Success = true
 	result.Duration = time.Since(startTime)
 	fmt.Printf("\nProfile %s completed successfully in %s\n", p.Name, result.Duration.Round(time.Second))
@@ -439,120 +1101,606 @@ func runProfile(ctx context.Context, p *profile.Profile, testType k6.TestType, o
 	return result
 }
 
-func profileToResourceConfig(p *profile.Profile, nodeSelector map[string]string) *framework.ResourceConfig {
-	config := &framework.ResourceConfig{}
-	hasConfig := false
+// runPhases runs the phases of a profile in order and records their time windows,
+// so collected metrics are split per phase. It stops at the first phase that fails.
+func runPhases(ctx context.Context, fw *framework.Framework, p *profile.Profile, opts *runOptions, result *RunResult, baseConfig *k6.Config, filePrefix string) (success, sloViolated bool, err error) {
+	for i, phase := range p.Phases {
+		fmt.Printf("\n--- Phase %d/%d: %s (%s, %s) ---\n", i+1, len(p.Phases), phase.Name, phase.Type, phase.Duration)
+		phaseStart := fw.Now()
+
+		if phase.Type == "idle" {
+			duration, _ := time.ParseDuration(phase.Duration) // validated by the profile loader
+			select {
+			case <-ctx.Done():
+				return false, false, fmt.Errorf("interrupted during phase %s", phase.Name)
+			case <-time.After(duration):
+			}
+			fw.RecordPhase(phase.Name, phaseStart, fw.Now())
+			continue
+		}
 
-	// Add resources if specified
-	if p.Tempo.HasResources() {
-		config.Resources = &corev1.ResourceRequirements{
-			Limits: corev1.ResourceList{
-				corev1.ResourceMemory: resource.MustParse(p.Tempo.Resources.Memory),
-				corev1.ResourceCPU:    resource.MustParse(p.Tempo.Resources.CPU),
-			},
-			Requests: corev1.ResourceList{
-				corev1.ResourceMemory: resource.MustParse(p.Tempo.Resources.Memory),
-				corev1.ResourceCPU:    resource.MustParse(p.Tempo.Resources.CPU),
-			},
+		k6Config := *baseConfig
+		k6Config.Duration = phase.Duration
+		phasePrefix := fmt.Sprintf("%s-%s", filePrefix, phase.Name)
+		success, sloViolated, err = runPhaseLoad(fw, p, opts, result, phase.Name, k6.TestType(phase.Type), &k6Config, phasePrefix)
+		if err != nil {
+			return false, sloViolated, fmt.Errorf("phase %s: %w", phase.Name, err)
+		}
+		if !success {
+			fmt.Printf("Phase %s did not succeed, skipping remaining phases\n", phase.Name)
+			return false, sloViolated, nil
 		}
-		hasConfig = true
 	}
+	return true, false, nil
+}
 
-	// Add replication factor if specified (only applies to TempoStack)
-	if p.Tempo.ReplicationFactor != nil {
-		config.ReplicationFactor = p.Tempo.ReplicationFactor
-		hasConfig = true
+// runPhaseLoad runs the k6 test of a phase, records the phase window, and saves the
+// k6 logs and metrics with the phase prefix
+func runPhaseLoad(fw *framework.Framework, p *profile.Profile, opts *runOptions, result *RunResult, phaseName string, testType k6.TestType, k6Config *k6.Config, phasePrefix string) (success, sloViolated bool, err error) {
+	if testType == k6.TestCombined {
+		parallelResult, err := runCombinedPhase(fw, p, opts, result, phaseName, k6Config, phasePrefix)
+		if err != nil {
+			return false, false, err
+		}
+		return parallelResult.Success(), parallelResult.ThresholdsFailed(), nil
 	}
 
-	// Get max traces per user from env var (takes precedence) or profile
-	maxTracesPerUser := getMaxTracesPerUser(p)
-	ingesterConfig := getIngesterConfig(p)
+	phaseStart := fw.Now()
+	if testType == k6.TestQuery {
+		generateQueryCorpus(fw, p, k6Config, phasePrefix)
+	}
+	k6Result, err := fw.RunK6Test(testType, k6Config)
+	if err != nil {
+		if k6Result != nil && k6Result.Cancelled {
+			saveK6Result(fw, k6Result, phasePrefix, testType)
+		}
+		return false, k6Result.ThresholdsFailed(), fmt.Errorf("k6 test failed: %w", err)
+	}
+	fw.RecordPhase(phaseName, phaseStart, fw.Now())
 
-	if maxTracesPerUser != nil || ingesterConfig != nil {
-		config.Overrides = &framework.TempoOverrides{
-			MaxTracesPerUser: maxTracesPerUser,
-			Ingester:         ingesterConfig,
+	if opts.verifyIngestion && (testType == k6.TestIngestion || testType == k6.TestReplay) {
+		verifyIngestionCompleteness(fw, k6Result, p.Tempo.Variant, phaseStart)
+		if testType == k6.TestIngestion {
+			checkIngestionRate(fw, result, k6Result, k6Config.MBPerSecond, opts.minAchievedRate, phaseStart)
 		}
-		hasConfig = true
 	}
+	if testType == k6.TestQuery || testType == k6.TestJaeger {
+		checkQueryRate(fw, result, k6Result, k6Config.QueriesPerSecond, opts.minAchievedRate)
+	}
+	if testType == k6.TestQuery {
+		verifyQueryCorrectness(fw, k6Result, p.Tempo.Variant, opts.verifyQueries)
+	}
+	saveK6Result(fw, k6Result, phasePrefix, testType)
+	return k6Result.Success, k6Result.ThresholdsFailed(), nil
+}
 
-	// Add node selector if specified
-	if len(nodeSelector) > 0 {
-		config.NodeSelector = nodeSelector
-		hasConfig = true
+// runCombinedPhase runs the parallel ingestion and query jobs of a phase, records
+// the phase window, and saves the k6 logs and metrics with the phase prefix
+func runCombinedPhase(fw *framework.Framework, p *profile.Profile, opts *runOptions, result *RunResult, phaseName string, k6Config *k6.Config, phasePrefix string) (*k6.ParallelResult, error) {
+	phaseStart := fw.Now()
+	k6Config.BeforeQuery = queryCorpusHook(fw, p, phasePrefix)
+	parallelResult, err := fw.RunK6ParallelTests(k6Config)
+	if err != nil {
+		if parallelResult != nil && parallelResult.Cancelled() {
+			saveK6Result(fw, parallelResult.Ingestion, phasePrefix, k6.TestIngestion)
+			saveK6Result(fw, parallelResult.Query, phasePrefix, k6.TestQuery)
+		}
+		return nil, fmt.Errorf("parallel k6 tests failed: %w", err)
 	}
+	// Record before verification so the settle time is not part of the phase
+	fw.RecordPhase(phaseName, phaseStart, fw.Now())
 
-	if !hasConfig {
-		return nil // Use operator defaults
+	if opts.verifyIngestion && parallelResult.Ingestion != nil {
+		verifyIngestionCompleteness(fw, parallelResult.Ingestion, p.Tempo.Variant, phaseStart)
+		checkIngestionRate(fw, result, parallelResult.Ingestion, k6Config.MBPerSecond, opts.minAchievedRate, phaseStart)
+	}
+	if parallelResult.Query != nil {
+		checkQueryRate(fw, result, parallelResult.Query, k6Config.QueriesPerSecond, opts.minAchievedRate)
+		verifyQueryCorrectness(fw, parallelResult.Query, p.Tempo.Variant, opts.verifyQueries)
 	}
-	return config
+	saveK6Result(fw, parallelResult.Ingestion, phasePrefix, k6.TestIngestion)
+	saveK6Result(fw, parallelResult.Query, phasePrefix, k6.TestQuery)
+	return parallelResult, nil
 }
 
-// getMaxTracesPerUser returns the max traces per user setting from env var or profile
-func getMaxTracesPerUser(p *profile.Profile) *int {
-	// Environment variable takes precedence
-	if envVal := os.Getenv("MAX_TRACES_PER_USER"); envVal != "" {
-		var val int
-		if _, err := fmt.Sscanf(envVal, "%d", &val); err == nil {
-			return &val
+// saveK6Result saves the logs of a k6 result to {prefix}-k6-{type}.log and exports
+// its metrics to {prefix}-k6-{type}-metrics.json
+func saveK6Result(fw *framework.Framework, k6Result *k6.Result, prefix string, testType k6.TestType) {
+	if k6Result == nil {
+		return
+	}
+
+	if k6Result.Output != "" {
+		logFile := fmt.Sprintf("%s-k6-%s.log", prefix, testType)
+		if err := os.WriteFile(logFile, []byte(k6Result.Output), 0644); err != nil {
+			fmt.Printf("Warning: failed to save %s logs: %v\n", testType, err)
+		} else {
+			fmt.Printf("Saved %s logs to %s\n", testType, logFile)
 		}
 	}
 
-	// Fall back to profile setting
-	if p.Tempo.Overrides != nil && p.Tempo.Overrides.MaxTracesPerUser != nil {
-		return p.Tempo.Overrides.MaxTracesPerUser
+	if k6Result.Metrics != nil {
+		metricsFile := fmt.Sprintf("%s-k6-%s-metrics.json", prefix, testType)
+		if err := fw.ExportK6Result(k6Result, metricsFile, string(testType)); err != nil {
+			fmt.Printf("Warning: failed to export %s k6 metrics: %v\n", testType, err)
+		}
 	}
+}
 
-	return nil
+// profileTestType returns the test type reported for a profile run: "phased" for
+// profiles with phases, "tenant-isolation" for noisy-neighbor profiles, otherwise
+// the selected test type
+func profileTestType(p *profile.Profile, testType k6.TestType) string {
+	if len(p.Phases) > 0 {
+		return "phased"
+	}
+	if p.TenantIsolation != nil {
+		return "tenant-isolation"
+	}
+	return string(testType)
+}
+
+// checkIngestionRate compares the ingestion rate k6 achieved with the requested rate,
+// prints the report, and flags the run as generator-limited when k6 fell short
+func checkIngestionRate(fw *framework.Framework, result *RunResult, ingestionResult *k6.Result, targetMBPerSecond, minPercent float64, testStart time.Time) {
+	c, err := fw.CheckIngestionRate(ingestionResult, targetMBPerSecond, minPercent, testStart)
+	if err != nil {
+		fmt.Printf("Warning: failed to check ingestion rate: %v\n", err)
+		return
+	}
+
+	fmt.Printf("  Requested rate:             %.2f MB/s\n", c.TargetMBPerSecond)
+	fmt.Printf("  Rate sent by k6:            %.2f MB/s (%.1f%% of requested, %.2f MB/s on the wire)\n", c.SentMBPerSecond, c.AchievedPercent, c.WireMBPerSecond)
+	if c.ReceivedError != nil {
+		fmt.Printf("  Warning: failed to get distributor rate: %v\n", c.ReceivedError)
+	} else {
+		fmt.Printf("  Rate received by Tempo:     %.2f MB/s\n", c.ReceivedMBPerSecond)
+	}
+	if c.UnderDelivered {
+		fmt.Printf("  ⚠️  k6 reached less than %.0f%% of the requested rate; the run is generator-limited and does not show Tempo handling %.2f MB/s\n", minPercent, c.TargetMBPerSecond)
+		result.GeneratorLimited = true
+	}
+	result.Attainment.RecordIngestion(c)
+}
+
+// checkQueryRate compares the query rate k6 achieved with the requested rate and
+// prints the report. A short query rate is not flagged as generator-limited: slow
+// queries occupying all VUs, i.e. Tempo, can limit it as well.
+func checkQueryRate(fw *framework.Framework, result *RunResult, queryResult *k6.Result, targetQueriesPerSecond int, minPercent float64) {
+	c, err := fw.CheckQueryRate(queryResult, float64(targetQueriesPerSecond), minPercent)
+	if err != nil {
+		fmt.Printf("Warning: failed to check query rate: %v\n", err)
+		return
+	}
+
+	fmt.Printf("  Requested query rate:       %.0f queries/s\n", c.TargetQueriesPerSecond)
+	fmt.Printf("  Query rate run by k6:       %.2f queries/s (%.1f%% of requested, %.0f dropped)\n", c.AchievedQueriesPerSecond, c.AchievedPercent, c.DroppedQueries)
+	if c.UnderDelivered {
+		fmt.Printf("  ⚠️  k6 reached less than %.0f%% of the requested query rate; the run does not show Tempo serving %.0f queries/s\n", minPercent, c.TargetQueriesPerSecond)
+	}
+	result.Attainment.RecordQueries(c)
+}
+
+// verifyQueryCorrectness fetches a percentage of the traces returned during the query
+// load again and prints how many were complete
+func verifyQueryCorrectness(fw *framework.Framework, queryResult *k6.Result, variant string, percent float64) {
+	if percent <= 0 {
+		return
+	}
+	fmt.Println("\nVerifying query results...")
+	c, err := fw.VerifyQueryCorrectness(queryResult, variant, percent)
+	if err != nil {
+		fmt.Printf("Warning: failed to verify query results: %v\n", err)
+		return
+	}
+
+	fmt.Printf("  Traces sampled by k6:       %d\n", c.TracesSampled)
+	fmt.Printf("  Traces fetched again:       %d (%d found, %d complete)\n", c.TracesChecked, c.TracesFound, c.TracesComplete)
+	fmt.Printf("  Correctness score:          %.1f%%\n", c.Score)
+	if c.LookupError != nil {
+		fmt.Printf("  Warning: %v\n", c.LookupError)
+	}
+	if c.TracesComplete < c.TracesChecked {
+		fmt.Printf("  ⚠️  Queries returned traces that are missing or incomplete (%d spans missing)\n", c.MissingSpans)
+	}
+}
+
+// queryCorpusLookback is the window a query corpus is sampled from, the window the
+// k6 query scripts search
+const queryCorpusLookback = time.Hour
+
+// queryCorpusWarmup is how long a combined test ingests before the query corpus is
+// sampled, so the first traces are indexed; the query Job starts after it
+const queryCorpusWarmup = 30 * time.Second
+
+// generateQueryCorpus points k6Config to a query corpus sampled from the data Tempo
+// ingested, when the profile generates its corpus. The built-in queries are run if
+// no corpus can be generated.
+func generateQueryCorpus(fw *framework.Framework, p *profile.Profile, k6Config *k6.Config, prefix string) {
+	if p.K6.Query.Corpus != profile.QueryCorpusGenerate {
+		return
+	}
+	corpusFile := prefix + k6.QueryCorpusSuffix
+	if _, err := fw.GenerateQueryCorpus(p.Tempo.Variant, fw.Now().Add(-queryCorpusLookback), corpusFile); err != nil {
+		fmt.Printf("Warning: running the built-in queries, failed to generate query corpus: %v\n", err)
+		return
+	}
+	k6Config.QueryCorpusFile = corpusFile
 }
 
-// getIngesterConfig returns the ingester tuning config from the profile
-func getIngesterConfig(p *profile.Profile) *framework.IngesterConfig {
-	if p.Tempo.Overrides == nil || p.Tempo.Overrides.Ingester == nil {
+// queryCorpusHook returns the BeforeQuery hook of a combined test, which generates
+// the query corpus once traces have been ingested for queryCorpusWarmup, or nil
+// when the profile does not generate its corpus
+func queryCorpusHook(fw *framework.Framework, p *profile.Profile, prefix string) func(*k6.Config) error {
+	if p.K6.Query.Corpus != profile.QueryCorpusGenerate {
 		return nil
 	}
+	return func(config *k6.Config) error {
+		fmt.Printf("Ingesting for %s before generating the query corpus...\n", queryCorpusWarmup)
+		select {
+		case <-fw.Context().Done():
+			return fw.Context().Err()
+		case <-time.After(queryCorpusWarmup):
+		}
+		corpusFile := prefix + k6.QueryCorpusSuffix
+		if _, err := fw.GenerateQueryCorpus(p.Tempo.Variant, fw.Now().Add(-queryCorpusLookback), corpusFile); err != nil {
+			return fmt.Errorf("failed to generate query corpus: %w", err)
+		}
+		config.QueryCorpusFile = corpusFile
+		return nil
+	}
+}
 
-	ing := p.Tempo.Overrides.Ingester
-	// Only return config if at least one field is set
-	if ing.FlushCheckPeriod == "" && ing.TraceIdlePeriod == "" &&
-		ing.MaxBlockDuration == "" && ing.ConcurrentFlushes == nil {
+// pushRemoteWrite pushes the collected metrics to the remote write endpoint, if
+// --remote-write is set, labeled with the run ID, profile and Tempo variant
+func pushRemoteWrite(fw *framework.Framework, p *profile.Profile, opts *runOptions, metricsFile string) {
+	if opts.remoteWriteURL == "" {
+		return
+	}
+	results, err := metrics.Load(metricsFile)
+	if err != nil {
+		fmt.Printf("Warning: failed to load metrics for remote write: %v\n", err)
+		return
+	}
+	fmt.Printf("Pushing metrics to %s...\n", opts.remoteWriteURL)
+	exporter := metrics.NewRemoteWriteExporter(opts.remoteWriteURL).WithLabels(map[string]string{
+		metrics.RunIDLabel:   opts.runID,
+		metrics.ProfileLabel: p.Name,
+		metrics.VariantLabel: p.Tempo.Variant,
+	})
+	// Not cancelled with the run, like the collection itself
+	if err := exporter.ExportContext(context.WithoutCancel(fw.Context()), results); err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+}
+
+// uploadResults uploads the files of the run in the output directory, if --upload
+// is set. Failed uploads are reported and returned, so CI notices missing results.
+func uploadResults(opts *runOptions) error {
+	if opts.uploader == nil {
 		return nil
 	}
+	fmt.Printf("\nUploading results to %s...\n", opts.uploader.RunURL(opts.runID))
+	// Not bound to the run context, so the results of aborted runs are uploaded too
+	result, err := opts.uploader.UploadRun(context.Background(), opts.outputDir, opts.runID)
+	if result != nil {
+		fmt.Printf("Uploaded %d file(s), %.1f MB\n", len(result.Keys), float64(result.Bytes)/(1024*1024))
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error uploading results: %v\n", err)
+		return err
+	}
+	return nil
+}
 
-	return &framework.IngesterConfig{
-		FlushCheckPeriod:  ing.FlushCheckPeriod,
-		TraceIdlePeriod:   ing.TraceIdlePeriod,
-		MaxBlockDuration:  ing.MaxBlockDuration,
-		ConcurrentFlushes: ing.ConcurrentFlushes,
+// checkClockSkew prints the skew between the runner and cluster clocks and warns
+// when it is beyond the threshold
+func checkClockSkew(fw *framework.Framework) {
+	skew, err := fw.CheckClockSkew()
+	if err != nil {
+		fmt.Printf("Warning: clock skew check failed, using runner time: %v\n", err)
+		return
+	}
+
+	if skew.APIServerError != nil {
+		fmt.Printf("  Warning: failed to read API server time: %v\n", skew.APIServerError)
+	} else {
+		fmt.Printf("  API server clock skew:      %v\n", skew.APIServer.Round(time.Millisecond))
+	}
+	if skew.PrometheusError != nil {
+		fmt.Printf("  Warning: failed to read Prometheus time: %v\n", skew.PrometheusError)
+	} else {
+		fmt.Printf("  Prometheus clock skew:      %v\n", skew.Prometheus.Round(time.Millisecond))
+	}
+	if skew.Exceeded() {
+		fmt.Printf("  ⚠️  Clock skew exceeds %v; metric windows use cluster time (offset %v)\n", skew.Threshold, skew.Offset().Round(time.Millisecond))
 	}
 }
 
-// getMinIOConfig returns MinIO configuration from the profile
-func getMinIOConfig(p *profile.Profile) *framework.MinIOConfig {
-	if p.Storage == nil || p.Storage.MinioSize == "" {
+// metricsCollectAttempts is how often an incomplete metrics collection is attempted;
+// each attempt resumes from the queries completed before, and the last one exports
+// partial results
+const metricsCollectAttempts = 3
+
+// collectMetrics collects the metrics of the test, retrying the queries that failed,
+// labeled with the ingestion protocol of the profile so runs can be compared on it.
+// A positive rollupInterval collects them through the rollups of the run.
+func collectMetrics(fw *framework.Framework, p *profile.Profile, testStart time.Time, metricsFile string, rollupInterval time.Duration) error {
+	collectOpts := []metrics.CollectOption{
+		metrics.WithLabels(map[string]string{metrics.IngestionProtocolLabel: p.K6.Ingestion.IngestionProtocol()}),
+	}
+	partial := metrics.CheckpointDir(metricsFile)
+	if rollupInterval > 0 {
+		collectOpts = append(collectOpts, metrics.WithRollups(rollupInterval))
+		partial = metrics.RollupDir(metricsFile)
+	}
+
+	// Not cancelled with the run, so metrics of an interrupted run are still collected
+	ctx := context.WithoutCancel(fw.Context())
+	attempt := 0
+	err := retry.Do(ctx, func(ctx context.Context) error {
+		attempt++
+		opts := collectOpts
+		if attempt == metricsCollectAttempts {
+			// Export what was collected rather than nothing once retries are exhausted
+			opts = append(slices.Clip(collectOpts), metrics.WithPartialResults())
+		}
+		return fw.CollectMetricsIncremental(testStart, metricsFile, opts...)
+	},
+		retry.WithName("metrics collection"),
+		retry.WithMetrics(fw.RetryStats()),
+		retry.WithMaxAttempts(metricsCollectAttempts),
+		retry.WithInitialDelay(10*time.Second),
+		retry.WithMultiplier(1),
+		retry.WithJitter(0),
+		retry.WithRetryIf(func(err error) bool { return errors.Is(err, metrics.ErrCollectionIncomplete) }),
+		retry.WithOnRetry(func(attempt int, err error, _ time.Duration) {
+			fmt.Printf("Warning: %v; retrying (attempt %d/%d)...\n", err, attempt+1, metricsCollectAttempts)
+		}),
+	)
+	if errors.Is(err, metrics.ErrCollectionIncomplete) {
+		return fmt.Errorf("%w; partial results are kept in %s", err, partial)
+	}
+	return err
+}
+
+// verifyIngestionCompleteness compares sent and stored data and prints the loss report
+func verifyIngestionCompleteness(fw *framework.Framework, ingestionResult *k6.Result, variant string, testStart time.Time) {
+	fmt.Println("\nVerifying ingestion completeness...")
+	c, err := fw.VerifyIngestionCompleteness(ingestionResult, variant, testStart)
+	if err != nil {
+		fmt.Printf("Warning: failed to verify ingestion completeness: %v\n", err)
+		return
+	}
+
+	fmt.Printf("  Spans sent by k6:           %.0f\n", c.SentSpans)
+	fmt.Printf("  Spans received by Tempo:    %.0f\n", c.ReceivedSpans)
+	fmt.Printf("  Span loss:                  %.2f%%\n", c.SpanLossPercent)
+	if c.TraceLookupError != nil {
+		fmt.Printf("  Warning: trace lookup sample failed: %v\n", c.TraceLookupError)
+	} else {
+		fmt.Printf("  Sampled traces found:       %d/%d (%.2f%% missing)\n", c.TracesFound, c.TracesSampled, c.TraceLossPercent)
+	}
+	if c.SpanLossPercent > 0 || c.TraceLossPercent > 0 {
+		fmt.Println("  ⚠️  Data was dropped during ingestion; throughput numbers overstate what Tempo stored")
+	}
+}
+
+// writeClusterEvents stops recording cluster events and saves them next to the
+// metrics file, returning the recorded events
+func writeClusterEvents(fw *framework.Framework, filePrefix string) []metrics.ClusterEvent {
+	w := fw.EventWatcher()
+	if w == nil {
 		return nil
 	}
-	return &framework.MinIOConfig{
-		StorageSize: p.Storage.MinioSize,
+	w.Stop()
+
+	events := w.Events()
+	eventsFile := filePrefix + metrics.ClusterEventsSuffix
+	if err := w.WriteJSON(eventsFile); err != nil {
+		fmt.Printf("Warning: failed to save cluster events: %v\n", err)
+		return events
 	}
+
+	warnings := 0
+	for _, e := range events {
+		if e.IsWarning() {
+			warnings++
+		}
+	}
+	fmt.Printf("Saved %d cluster events (%d warnings) to %s\n", len(events), warnings, eventsFile)
+	return events
 }
 
-func profileToK6Config(p *profile.Profile) *k6.Config {
-	// Get duration from DURATION env var, default to 5m
-	duration := os.Getenv("DURATION")
-	if duration == "" {
-		duration = "5m"
+// writeAlerts saves the alerts that fired since testStart next to the metrics file
+func writeAlerts(fw *framework.Framework, filePrefix string, testStart time.Time) {
+	alerts, err := fw.CollectAlerts(testStart)
+	if err != nil {
+		fmt.Printf("Warning: failed to collect alerts: %v\n", err)
+		return
+	}
+	alertsFile := filePrefix + metrics.AlertsSuffix
+	if err := metrics.WriteAlerts(alerts, alertsFile); err != nil {
+		fmt.Printf("Warning: failed to save alerts: %v\n", err)
+		return
+	}
+	for _, a := range alerts {
+		fmt.Printf("  🚨 %s fired at %s for %s\n", a.Name, a.Start.Format(time.TimeOnly), a.Duration().Round(time.Second))
+	}
+	fmt.Printf("Saved %d firing alerts to %s\n", len(alerts), alertsFile)
+}
+
+// writePrerequisites saves the operators, CRDs and monitoring state of the cluster
+// next to the metrics file
+func writePrerequisites(fw *framework.Framework, filePrefix string) *metrics.PrerequisitesReport {
+	prerequisites, err := fw.PrerequisitesReport()
+	if err != nil {
+		fmt.Printf("Warning: failed to report prerequisites: %v\n", err)
+		return nil
+	}
+	prerequisitesFile := filePrefix + metrics.PrerequisitesSuffix
+	if err := metrics.WritePrerequisitesReport(prerequisites, prerequisitesFile); err != nil {
+		fmt.Printf("Warning: failed to save prerequisites report: %v\n", err)
+		return prerequisites
+	}
+	fmt.Printf("Saved prerequisites report to %s\n", prerequisitesFile)
+	return prerequisites
+}
+
+// runBlockFormat returns the block format set by a profile, or nil when it keeps
+// the Tempo defaults
+func runBlockFormat(p *profile.Profile) *metrics.BlockFormat {
+	b := p.Tempo.Block
+	if b == nil {
+		return nil
+	}
+	return &metrics.BlockFormat{
+		Version:                   b.Version,
+		Encoding:                  b.Encoding,
+		BloomFilterFalsePositive:  b.BloomFilterFalsePositive,
+		BloomFilterShardSizeBytes: b.BloomFilterShardSizeBytes,
+	}
+}
+
+// runIngestionPath returns "kafka" when a profile buffers the traces in Kafka, or
+// empty when the collector sends them to Tempo directly
+func runIngestionPath(p *profile.Profile) string {
+	if p.Kafka != nil {
+		return "kafka"
+	}
+	return ""
+}
+
+// recordRunMetadata writes the metadata of a profile run next to its metrics file
+func recordRunMetadata(fw *framework.Framework, p *profile.Profile, opts *runOptions, result *RunResult, filePrefix, metricsFile string, testStart time.Time, testDuration time.Duration, logErrors *metrics.LogErrorSummary) *metrics.RunMetadata {
+	tempoVersion, err := fw.TempoVersion(p.Tempo.Variant)
+	if err != nil {
+		fmt.Printf("Warning: failed to get Tempo version: %v\n", err)
+	}
+
+	meta := &metrics.RunMetadata{
+		RunID:             opts.runID,
+		Profile:           p.Name,
+		Variant:           p.Tempo.Variant,
+		TempoVersion:      tempoVersion,
+		TestType:          profileTestType(p, opts.testType),
+		TempoEnv:          p.Tempo.Env,
+		TempoExtraConfig:  p.Tempo.ExtraConfig,
+		IngestionAuth:     p.Tempo.IngestionAuth,
+		IngestionProtocol: p.K6.Ingestion.IngestionProtocol(),
+		IngestionPath:     runIngestionPath(p),
+		MetricsGenerator:  p.Tempo.MetricsGenerator.EnabledProcessors(),
+		Block:             runBlockFormat(p),
+		Replicas:          p.Tempo.Replicas,
+		Images:            runImages(fw, p),
+		FrameworkSHA:      k6.FrameworkSHA(),
+		GeneratorLimited:  result.GeneratorLimited,
+		Attainment:        runAttainment(result),
+		Isolation:         result.Isolation,
+		CompactionBacklog: result.CompactionBacklog,
+		StartedAt:         testStart.UTC(),
+		TestDuration:      testDuration,
+		MetricsFile:       filepath.Base(metricsFile),
+		LogErrors:         logErrors,
+	}
+	metaFile := filePrefix + metrics.RunMetadataSuffix
+	if err := metrics.WriteRunMetadata(meta, metaFile); err != nil {
+		fmt.Printf("Warning: failed to write run metadata: %v\n", err)
+		return meta
+	}
+	fmt.Printf("Saved run metadata to %s (Tempo %s)\n", metaFile, tempoVersion)
+	return meta
+}
+
+// runImages returns the images of the run: the Tempo and collector images running
+// in the cluster, or the pinned ones when they cannot be read, and the k6 image
+func runImages(fw *framework.Framework, p *profile.Profile) map[string]string {
+	images, err := fw.RunningImages()
+	if err != nil {
+		fmt.Printf("Warning: failed to get the running images: %v\n", err)
+		images = make(map[string]string)
+	}
+	pinned := p.Images
+	if pinned == nil {
+		pinned = &profile.ImagesConfig{}
+	}
+	if _, ok := images["tempo"]; !ok && pinned.Tempo != "" {
+		images["tempo"] = pinned.Tempo
+	}
+	if _, ok := images["otel-collector"]; !ok && pinned.Collector != "" {
+		images["otel-collector"] = pinned.Collector
+	}
+	images["k6"] = k6.DefaultImage
+	if pinned.K6 != "" {
+		images["k6"] = pinned.K6
+	}
+	return images
+}
+
+// compareWithBaseline generates a dashboard comparing a run with the latest earlier
+// run of the same profile, variant, and Tempo version
+func compareWithBaseline(fw *framework.Framework, run *metrics.RunMetadata, opts *runOptions, filePrefix string) {
+	query := metrics.BaselineQuery{
+		Profile:      run.Profile,
+		Variant:      run.Variant,
+		TempoVersion: run.TempoVersion,
+		ExcludeRunID: run.RunID,
+	}
+	if opts.baselineVersion == "any" {
+		query.TempoVersion = ""
+	} else if opts.baselineVersion != "" {
+		query.TempoVersion = opts.baselineVersion
 	}
 
-	return &k6.Config{
-		TempoVariant:     k6.TempoVariant(p.Tempo.Variant),
-		MBPerSecond:      p.K6.Ingestion.MBPerSecond,
-		QueriesPerSecond: p.K6.Query.QueriesPerSecond,
-		Duration:         duration,
-		VUsMin:           p.K6.VUs.Min,
-		VUsMax:           p.K6.VUs.Max,
-		TraceProfile:     p.K6.Ingestion.TraceProfile,
+	baseline, err := metrics.FindBaseline(opts.baselineDir, query)
+	if err != nil {
+		fmt.Printf("Warning: no baseline to compare with: %v\n", err)
+		return
+	}
+
+	dashboardFile := fmt.Sprintf("%s-vs-baseline-dashboard.html", filePrefix)
+	fmt.Printf("Comparing with baseline run %s (Tempo %s) to %s...\n", baseline.RunID, baseline.TempoVersion, dashboardFile)
+
+	config := dashboard.DashboardConfig{
+		Title:       fmt.Sprintf("Tempo Performance Test: %s vs Baseline", run.Profile),
+		ProfileName: run.Profile,
+		TestType:    run.TestType,
+		GeneratedAt: time.Now(),
+		CompareMode: true,
+		RunNames: []string{
+			fmt.Sprintf("baseline %s (%s)", baseline.RunID, baseline.TempoVersion),
+			fmt.Sprintf("%s (%s)", run.RunID, run.TempoVersion),
+		},
+	}
+	metricsFile := filepath.Join(filepath.Dir(filePrefix), run.MetricsFile)
+	if err := fw.GenerateComparisonDashboard([]string{baseline.MetricsFile, metricsFile}, dashboardFile, config); err != nil {
+		fmt.Printf("Warning: failed to generate baseline comparison dashboard: %v\n", err)
+		return
+	}
+	fmt.Printf("Baseline comparison dashboard generated: %s\n", dashboardFile)
+}
+
+// preserveFailedProfile collects diagnostics for a failed profile and keeps its
+// namespace, annotated with a TTL so orphan cleanup can remove it later.
+func preserveFailedProfile(fw *framework.Framework, p *profile.Profile, opts *runOptions) {
+	fmt.Printf("\nPreserving namespace %s of failed profile %s for debugging...\n", fw.Namespace(), p.Name)
+
+	// Failed runs return before the end-of-run log collection, so gather diagnostics here
+	fmt.Println("Collecting diagnostics...")
+	if logs, err := fw.CollectLogs(&framework.LogCollectionConfig{OutputDir: opts.outputDir}); err != nil {
+		fmt.Printf("Warning: failed to collect logs: %v\n", err)
+	} else {
+		fw.AnalyzeLogs(logs)
+	}
+	if _, err := fw.DumpTempoCR(p.Tempo.Variant, opts.outputDir); err != nil {
+		fmt.Printf("Warning: failed to dump Tempo CR: %v\n", err)
+	}
+
+	if err := fw.Preserve(opts.preserveTTL); err != nil {
+		fmt.Printf("Warning: failed to mark namespace for preservation: %v\n", err)
+		return
 	}
+	fmt.Printf("Namespace %s preserved for %s\n", fw.Namespace(), opts.preserveTTL)
 }
 
 func printProfileSummary(p *profile.Profile, testType k6.TestType) {
@@ -564,19 +1712,74 @@ func printProfileSummary(p *profile.Profile, testType k6.TestType) {
 
 	fmt.Printf("\nProfile: %s\n", p.Name)
 	fmt.Printf("  Description: %s\n", p.Description)
+	if p.Extends != "" {
+		fmt.Printf("  Extends: %s\n", p.Extends)
+	}
 	fmt.Printf("  Tempo:\n")
 	fmt.Printf("    Variant: %s\n", p.Tempo.Variant)
 	if p.Tempo.ReplicationFactor != nil {
 		fmt.Printf("    ReplicationFactor: %d\n", *p.Tempo.ReplicationFactor)
 	}
+	if p.Tempo.Replicas > 1 {
+		fmt.Printf("    Replicas: %d\n", p.Tempo.Replicas)
+	}
+	if p.Tempo.IngestionAuth != "" {
+		fmt.Printf("    IngestionAuth: %s\n", p.Tempo.IngestionAuth)
+	}
+	if p.K6.Ingestion.Protocol != "" {
+		fmt.Printf("    IngestionProtocol: %s\n", p.K6.Ingestion.Protocol)
+	}
+	if processors := p.Tempo.MetricsGenerator.EnabledProcessors(); len(processors) > 0 {
+		fmt.Printf("    MetricsGenerator: %s\n", strings.Join(processors, ", "))
+	}
+	if block := runBlockFormat(p); block != nil {
+		fmt.Printf("    Block: %s\n", block)
+	}
+	if p.Images != nil && p.Images.Tempo != "" {
+		fmt.Printf("    Image: %s\n", p.Images.Tempo)
+	}
+	if p.Images != nil && p.Images.Collector != "" {
+		fmt.Printf("    Collector image: %s\n", p.Images.Collector)
+	}
+	if k := p.Kafka; k != nil {
+		brokers := "deployed in the namespace"
+		if len(k.Brokers) > 0 {
+			brokers = strings.Join(k.Brokers, ", ")
+		}
+		fmt.Printf("    Kafka: %s\n", brokers)
+	}
+	if quota := suite.NamespaceQuota(p); quota != nil {
+		fmt.Printf("    Namespace quota: %s\n", quota)
+	}
 	if p.Tempo.HasResources() {
 		fmt.Printf("    Resources: %s memory, %s CPU\n", p.Tempo.Resources.Memory, p.Tempo.Resources.CPU)
 	} else {
 		fmt.Printf("    Resources: (operator defaults)\n")
 	}
+	if p.Storage.UsesPV() {
+		size := p.Storage.Size
+		if size == "" {
+			size = "10Gi"
+		}
+		storageClass := p.Storage.StorageClass
+		if storageClass == "" {
+			storageClass = "cluster default"
+		}
+		fmt.Printf("    Storage: %s persistent volume (%s StorageClass)\n", size, storageClass)
+	}
+	if len(p.Tempo.Env) > 0 {
+		fmt.Printf("    Env:\n")
+		for _, name := range slices.Sorted(maps.Keys(p.Tempo.Env)) {
+			fmt.Printf("      %s=%s\n", name, p.Tempo.Env[name])
+		}
+	}
+	if len(p.Tempo.ExtraConfig) > 0 {
+		extraConfig, _ := json.Marshal(p.Tempo.ExtraConfig)
+		fmt.Printf("    ExtraConfig: %s\n", extraConfig)
+	}
 
 	// Show max traces per user setting
-	maxTraces := getMaxTracesPerUser(p)
+	maxTraces := suite.MaxTracesPerUser(p)
 	if maxTraces != nil {
 		if *maxTraces == 0 {
 			fmt.Printf("    MaxTracesPerUser: 0 (unlimited)\n")
@@ -605,12 +1808,76 @@ func printProfileSummary(p *profile.Profile, testType k6.TestType) {
 		}
 	}
 
-	fmt.Printf("  K6 (%s test):\n", testType)
-	fmt.Printf("    Duration: %s\n", duration)
+	if len(p.Phases) > 0 {
+		fmt.Printf("  K6 (phased test):\n")
+		for _, phase := range p.Phases {
+			fmt.Printf("    Phase %s: %s for %s\n", phase.Name, phase.Type, phase.Duration)
+		}
+	} else {
+		fmt.Printf("  K6 (%s test):\n", testType)
+		fmt.Printf("    Duration: %s\n", duration)
+	}
 	fmt.Printf("    VUs: %d-%d\n", p.K6.VUs.Min, p.K6.VUs.Max)
 	fmt.Printf("    Ingestion: %.1f MB/s\n", p.K6.Ingestion.MBPerSecond)
 	fmt.Printf("    Queries/sec: %d\n", p.K6.Query.QueriesPerSecond)
+	if p.K6.Query.Corpus != "" {
+		fmt.Printf("    Query corpus: %s\n", p.K6.Query.Corpus)
+	}
 	fmt.Printf("    Trace profile: %s\n", p.K6.Ingestion.TraceProfile)
+	if p.Images != nil && p.Images.K6 != "" {
+		fmt.Printf("    Image: %s\n", p.Images.K6)
+	}
+	if r := p.K6.Replay; r != nil {
+		capture := r.File
+		if r.PVC != "" {
+			capture = fmt.Sprintf("PVC %s", r.PVC)
+		}
+		speedup := r.Speedup
+		if speedup == 0 {
+			speedup = 1
+		}
+		fmt.Printf("    Replay: %s at %gx\n", capture, speedup)
+	}
+}
+
+// generateComparisonDashboard writes a dashboard comparing the metrics of all
+// profiles that collected metrics in this run, next to their CSV files
+func generateComparisonDashboard(profiles []*profile.Profile, results map[string]*RunResult, opts *runOptions) {
+	var csvPaths, runNames []string
+	for _, p := range profiles {
+		if r, ok := results[p.Name]; ok && r.MetricsPath != "" {
+			csvPaths = append(csvPaths, r.MetricsPath)
+			runNames = append(runNames, p.Name)
+		}
+	}
+	if len(csvPaths) < 2 {
+		return
+	}
+
+	dashboardFile := fmt.Sprintf("%s/comparison-%s-dashboard.html", opts.outputDir, opts.runID)
+	fmt.Printf("\nGenerating comparison dashboard for %d profiles to %s...\n", len(csvPaths), dashboardFile)
+
+	config := dashboard.DashboardConfig{
+		Title:       "Tempo Performance Test Comparison",
+		ProfileName: "comparison",
+		TestType:    string(opts.testType),
+		GeneratedAt: time.Now(),
+		CompareMode: true,
+		RunNames:    runNames,
+	}
+	if err := dashboard.GenerateComparison(csvPaths, dashboardFile, config); err != nil {
+		fmt.Printf("Warning: failed to generate comparison dashboard: %v\n", err)
+		return
+	}
+	fmt.Printf("Comparison dashboard generated: %s\n", dashboardFile)
+}
+
+// runAttainment returns the load attainment of a run, or nil when no rate was checked
+func runAttainment(result *RunResult) *metrics.LoadAttainment {
+	if result.Attainment.IsEmpty() {
+		return nil
+	}
+	return &result.Attainment
 }
 
 func printSummary(results map[string]*RunResult) {
@@ -621,18 +1888,99 @@ func printSummary(results map[string]*RunResult) {
 	var passed, failed int
 	for name, r := range results {
 		status := "PASS"
-		if r.Error != nil {
+		if r.TimedOut {
+			status = fmt.Sprintf("TIMEOUT during %s", r.TimedOutPhase)
+			failed++
+		} else if r.Error != nil {
 			status = "FAIL"
 			failed++
 		} else {
 			passed++
 		}
+		if r.GeneratorLimited {
+			status += ", generator-limited"
+		}
+		if a := r.Attainment.Ingestion; a != nil {
+			status += fmt.Sprintf(", ingestion %.0f%% of %.2f MB/s", a.AchievedPercent, a.Target)
+		}
+		if a := r.Attainment.Queries; a != nil {
+			status += fmt.Sprintf(", queries %.0f%% of %.0f/s", a.AchievedPercent, a.Target)
+		}
 		fmt.Printf("  %s: %s (%s)\n", name, status, r.Duration.Round(time.Second))
 	}
 
 	fmt.Printf("\nTotal: %d passed, %d failed\n", passed, failed)
 }
 
+// profileNotifyConfig applies the profile's notification settings on top of the env config
+func profileNotifyConfig(base *notify.Config, p *profile.Profile) *notify.Config {
+	if p.Notifications == nil {
+		return base
+	}
+	return base.Merge(&notify.Config{
+		WebhookURL:       p.Notifications.WebhookURL,
+		Format:           notify.Format(p.Notifications.Format),
+		DashboardBaseURL: p.Notifications.DashboardBaseURL,
+	})
+}
+
+// notifyContext returns a context for sending notifications. It does not derive from
+// the run context so that interrupted runs can still be reported.
+func notifyContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), notify.DefaultTimeout)
+}
+
+// notifyRunStarted sends one run start notification per distinct notification config,
+// listing the profiles whose settings resolve to it
+func notifyRunStarted(base *notify.Config, profiles []*profile.Profile, testType string) {
+	var configs []*notify.Config
+	names := make(map[notify.Config][]string)
+	for _, p := range profiles {
+		cfg := profileNotifyConfig(base, p)
+		if _, ok := names[*cfg]; !ok {
+			configs = append(configs, cfg)
+		}
+		names[*cfg] = append(names[*cfg], p.Name)
+	}
+
+	for _, cfg := range configs {
+		ctx, cancel := notifyContext()
+		err := notify.New(cfg).RunStarted(ctx, names[*cfg], testType)
+		cancel()
+		if err != nil {
+			fmt.Printf("Warning: failed to send run start notification: %v\n", err)
+		}
+	}
+}
+
+// notifyAborted sends a failure notification for a profile that was interrupted or never ran
+func notifyAborted(base *notify.Config, p *profile.Profile, reason error) {
+	ctx, cancel := notifyContext()
+	defer cancel()
+	if err := notify.New(profileNotifyConfig(base, p)).Failure(ctx, p.Name, 0, reason, ""); err != nil {
+		fmt.Printf("Warning: failed to send notification for profile %s: %v\n", p.Name, err)
+	}
+}
+
+// notifyProfileResult sends the completion, SLO violation, or failure notification for a profile
+func notifyProfileResult(n *notify.Notifier, r *RunResult) {
+	ctx, cancel := notifyContext()
+	defer cancel()
+
+	var err error
+	switch {
+	case r.Error == nil:
+		err = n.ProfileCompleted(ctx, r.Profile, r.Duration, r.DashboardPath)
+	case r.SLOViolated:
+		err = n.SLOViolation(ctx, r.Profile, r.Error.Error(), r.DashboardPath)
+	default:
+		err = n.Failure(ctx, r.Profile, r.Duration, r.Error, r.DashboardPath)
+	}
+	if err != nil {
+		fmt.Printf("Warning: failed to send notification for profile %s: %v\n", r.Profile, err)
+	}
+}
+
 // parseNodeSelector parses a node selector string in the format "key=value,key2=value2"
 // or "key=" for empty value selectors (common for node roles)
 func parseNodeSelector(s string) map[string]string {
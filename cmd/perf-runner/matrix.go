@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/metrics"
+	"github.com/redhat/perf-tests-tempo/test/framework/metrics/dashboard"
+	"github.com/redhat/perf-tests-tempo/test/framework/profile"
+	"github.com/redhat/perf-tests-tempo/test/framework/report"
+)
+
+// matrixGroup is a profile expanded by --matrix into one profile per variant
+type matrixGroup struct {
+	Profile  string
+	Variants []*profile.Profile
+}
+
+// parseMatrix parses a --matrix value, e.g. "variant=monolithic,stack", into the
+// variants to run. Only the deployment variant can be varied.
+func parseMatrix(spec string) ([]string, error) {
+	key, values, ok := strings.Cut(spec, "=")
+	if !ok || strings.TrimSpace(key) != "variant" {
+		return nil, fmt.Errorf("expected variant=<variant>,<variant>, got %q", spec)
+	}
+	var variants []string
+	seen := make(map[string]bool)
+	for _, v := range strings.Split(values, ",") {
+		v = strings.TrimSpace(v)
+		if v != "monolithic" && v != "stack" {
+			return nil, fmt.Errorf("variant must be 'monolithic' or 'stack', got %q", v)
+		}
+		if seen[v] {
+			return nil, fmt.Errorf("variant %s is listed twice", v)
+		}
+		seen[v] = true
+		variants = append(variants, v)
+	}
+	if len(variants) < 2 {
+		return nil, fmt.Errorf("at least two variants are needed for a comparison, got %q", values)
+	}
+	return variants, nil
+}
+
+// expandMatrix returns a copy of each profile per variant, named
+// <profile>-<variant>, with identical k6 settings. Copies that are invalid for
+// their variant, e.g. a monolithic-only setting in a stack copy, are an error.
+func expandMatrix(profiles []*profile.Profile, variants []string) ([]*profile.Profile, []matrixGroup, error) {
+	var expanded []*profile.Profile
+	groups := make([]matrixGroup, 0, len(profiles))
+	for _, p := range profiles {
+		group := matrixGroup{Profile: p.Name}
+		for _, variant := range variants {
+			c := *p
+			c.Name = p.Name + "-" + variant
+			c.Tempo.Variant = variant
+			if err := profile.Validate(&c); err != nil {
+				return nil, nil, fmt.Errorf("profile %s cannot run as %s: %w", p.Name, variant, err)
+			}
+			group.Variants = append(group.Variants, &c)
+			expanded = append(expanded, &c)
+		}
+		groups = append(groups, group)
+	}
+	return expanded, groups, nil
+}
+
+// writeMatrixComparisons writes a comparison dashboard and a Markdown delta report
+// of the variants of each profile expanded by --matrix. The first variant is the
+// reference of the deltas.
+func writeMatrixComparisons(groups []matrixGroup, results map[string]*RunResult, opts *runOptions) {
+	for _, g := range groups {
+		filePrefix := fmt.Sprintf("%s/%s-%s-matrix", opts.outputDir, g.Profile, opts.runID)
+
+		var compared []report.ComparedRun
+		var csvPaths, runNames []string
+		for _, p := range g.Variants {
+			r, ok := results[p.Name]
+			if !ok {
+				continue
+			}
+			run := report.ComparedRun{
+				Name: p.Tempo.Variant,
+				Run: &report.Run{
+					Profile:     p.Name,
+					RunID:       opts.runID,
+					Variant:     p.Tempo.Variant,
+					TestType:    profileTestType(p, opts.testType),
+					Duration:    r.Duration,
+					Error:       r.Error,
+					SLOViolated: r.SLOViolated,
+				},
+			}
+			if r.MetricsPath != "" {
+				loaded, err := metrics.Load(r.MetricsPath)
+				if err != nil {
+					fmt.Printf("Warning: failed to load metrics of %s for the comparison: %v\n", p.Name, err)
+				} else {
+					run.Results = loaded
+					csvPaths = append(csvPaths, r.MetricsPath)
+					runNames = append(runNames, p.Tempo.Variant)
+				}
+			}
+			compared = append(compared, run)
+		}
+		if len(compared) < 2 {
+			continue
+		}
+
+		reportFile := filePrefix + ".md"
+		summary := report.GenerateComparisonMarkdown(g.Profile, compared)
+		if err := os.WriteFile(reportFile, []byte(summary), 0644); err != nil {
+			fmt.Printf("Warning: failed to write variant comparison of %s: %v\n", g.Profile, err)
+		} else {
+			fmt.Printf("Variant comparison report written: %s\n", reportFile)
+		}
+
+		if !opts.generateDashboard || len(csvPaths) < 2 {
+			continue
+		}
+		dashboardFile := filePrefix + "-dashboard.html"
+		config := dashboard.DashboardConfig{
+			Title:       fmt.Sprintf("Tempo Performance Test: %s by Variant", g.Profile),
+			ProfileName: g.Profile,
+			TestType:    profileTestType(g.Variants[0], opts.testType),
+			GeneratedAt: time.Now(),
+			CompareMode: true,
+			RunNames:    runNames,
+		}
+		if err := dashboard.GenerateComparison(csvPaths, dashboardFile, config); err != nil {
+			fmt.Printf("Warning: failed to generate variant comparison dashboard of %s: %v\n", g.Profile, err)
+			continue
+		}
+		fmt.Printf("Variant comparison dashboard generated: %s\n", dashboardFile)
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/redhat/perf-tests-tempo/test/framework"
+	"github.com/redhat/perf-tests-tempo/test/framework/config"
+)
+
+// runCleanupOrphans implements the cleanup-orphans subcommand, which deletes
+// stale resources left behind by aborted or preserved runs
+func runCleanupOrphans(args []string) {
+	fs := flag.NewFlagSet("cleanup-orphans", flag.ExitOnError)
+	var (
+		maxAge      = fs.Duration("max-age", framework.DefaultOrphanMaxAge, "Delete managed resources older than this (preserved namespaces use their expiry annotation instead)")
+		dryRun      = fs.Bool("dry-run", false, "List stale resources without deleting them")
+		allowUnsafe = fs.Bool("allow-unsafe-cluster", false, "Delete even if the cluster fails the safety guardrails")
+		kubeconfig  = fs.String("kubeconfig", "", "Path to the kubeconfig of the target cluster (default: in-cluster config, KUBECONFIG, or ~/.kube/config)")
+		kubeContext = fs.String("context", "", "Kubeconfig context of the target cluster (default: the current context)")
+	)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: perf-runner cleanup-orphans [flags]\n\n")
+		fmt.Fprintf(fs.Output(), "Deletes namespaces, ClusterRoles, ClusterRoleBindings, and PVs labeled\n")
+		fmt.Fprintf(fs.Output(), "%s=%s that are older than --max-age.\n\n", framework.LabelManagedBy, framework.LabelManagedByValue)
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	if *dryRun {
+		fmt.Printf("Dry run - listing managed resources older than %s...\n", *maxAge)
+	} else {
+		fmt.Printf("Cleaning up managed resources older than %s...\n", *maxAge)
+	}
+
+	fwConfig := config.FromEnv()
+	if *allowUnsafe {
+		fwConfig = fwConfig.WithAllowUnsafeCluster(true)
+	}
+
+	result, err := framework.CleanupOrphans(ctx, framework.OrphanCleanupOptions{
+		MaxAge: *maxAge,
+		DryRun: *dryRun,
+	}, framework.WithConfig(fwConfig), framework.WithKubeconfig(*kubeconfig), framework.WithKubeContext(*kubeContext))
+	if result != nil {
+		printOrphans("Namespaces", result.Namespaces)
+		printOrphans("ClusterRoles", result.ClusterRoles)
+		printOrphans("ClusterRoleBindings", result.ClusterRoleBindings)
+		printOrphans("PersistentVolumes", result.PersistentVolumes)
+
+		verb := "Deleted"
+		if *dryRun {
+			verb = "Found"
+		}
+		fmt.Printf("\n%s %d stale resource(s)\n", verb, result.Total())
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error cleaning up orphans: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// cleanupExpiredNamespaces deletes the namespaces preserved by earlier runs whose
+// TTL has expired, with their cluster-scoped resources. Failures only warn, as they
+// must not prevent the run.
+func cleanupExpiredNamespaces(ctx context.Context, opts *runOptions) {
+	fwConfig := config.FromEnv()
+	if opts.allowUnsafe {
+		fwConfig = fwConfig.WithAllowUnsafeCluster(true)
+	}
+
+	result, err := framework.CleanupOrphans(ctx, framework.OrphanCleanupOptions{
+		ExpiredOnly: true,
+	}, framework.WithConfig(fwConfig), framework.WithKubeconfig(opts.kubeconfig), framework.WithKubeContext(opts.kubeContext))
+	if result != nil && len(result.Namespaces) > 0 {
+		fmt.Println("Deleted expired namespaces of earlier runs:")
+		for _, name := range result.Namespaces {
+			fmt.Printf("  - %s\n", name)
+		}
+	}
+	if err != nil {
+		fmt.Printf("Warning: failed to clean up expired namespaces: %v\n", err)
+	}
+}
+
+// printOrphans prints the names of stale resources of one kind
+func printOrphans(kind string, names []string) {
+	if len(names) == 0 {
+		return
+	}
+	fmt.Printf("\n%s:\n", kind)
+	for _, name := range names {
+		fmt.Printf("  - %s\n", name)
+	}
+}
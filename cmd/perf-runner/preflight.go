@@ -0,0 +1,254 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework"
+	"github.com/redhat/perf-tests-tempo/test/framework/config"
+	"github.com/redhat/perf-tests-tempo/test/framework/metrics"
+	"github.com/redhat/perf-tests-tempo/test/framework/suite"
+)
+
+// preflightProfile names the namespace of preflight runs
+const preflightProfile = "preflight"
+
+// preflightScrapeWait is how long the preflight waits after the load for the last
+// samples to be scraped before checking metric availability
+const preflightScrapeWait = 45 * time.Second
+
+// preflightCheck is the outcome of one step of the preflight
+type preflightCheck struct {
+	Name   string
+	Passed bool
+	Detail string
+}
+
+// runPreflight implements the preflight subcommand, which checks that the metrics of
+// a run will be available before committing to a long run: it deploys the smoke
+// profile, runs a short combined load and reports which metrics Prometheus has
+func runPreflight(args []string) {
+	fs := flag.NewFlagSet("preflight", flag.ExitOnError)
+	var (
+		profilesDir = fs.String("profiles-dir", "profiles", "Directory containing profile YAML files; the workload is the smoke profile")
+		duration    = fs.Duration("duration", 2*time.Minute, "Duration of the synthetic combined load")
+		runID       = fs.String("run-id", "", "Unique ID of the preflight namespace tempo-perf-preflight-<run-id> (default: random)")
+		skipCleanup = fs.Bool("skip-cleanup", false, "Keep the preflight namespace after the checks")
+		allowUnsafe = fs.Bool("allow-unsafe-cluster", false, "Run even if the cluster fails the safety guardrails")
+		kubeconfig  = fs.String("kubeconfig", "", "Path to the kubeconfig of the target cluster (default: in-cluster config, KUBECONFIG, or ~/.kube/config)")
+		kubeContext = fs.String("context", "", "Kubeconfig context of the target cluster (default: the current context)")
+	)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: perf-runner preflight [flags]\n\n")
+		fmt.Fprintf(fs.Output(), "Checks Thanos access, user workload monitoring, the Tempo ServiceMonitors and\n")
+		fmt.Fprintf(fs.Output(), "k6 remote write against a short synthetic load, then prints the metric\n")
+		fmt.Fprintf(fs.Output(), "availability report. Exits with 1 if any check fails.\n\n")
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+
+	if *runID == "" {
+		*runID = framework.NewRunID()
+	} else if err := framework.ValidateRunID(*runID); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid run ID: %v\n", err)
+		os.Exit(1)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	checks, err := preflight(ctx, *profilesDir, *runID, *duration, *skipCleanup, *allowUnsafe, *kubeconfig, *kubeContext)
+
+	fmt.Println("\nPreflight checks:")
+	failed := 0
+	for _, c := range checks {
+		status := "PASS"
+		if !c.Passed {
+			status = "FAIL"
+			failed++
+		}
+		fmt.Printf("  [%s] %-28s %s\n", status, c.Name, c.Detail)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: preflight failed: %v\n", err)
+		os.Exit(1)
+	}
+	if failed > 0 {
+		fmt.Fprintf(os.Stderr, "Error: %d preflight check(s) failed\n", failed)
+		os.Exit(1)
+	}
+	fmt.Println("\nPreflight passed: metrics will be available for runs on this cluster")
+}
+
+// preflight deploys the smoke profile, runs the synthetic load and checks the metric
+// pipeline. Failed checks are returned; an error means the preflight could not run.
+func preflight(ctx context.Context, profilesDir, runID string, duration time.Duration, skipCleanup, allowUnsafe bool, kubeconfig, kubeContext string) (checks []preflightCheck, err error) {
+	check := func(name string, checkErr error, detail string) {
+		c := preflightCheck{Name: name, Passed: checkErr == nil, Detail: detail}
+		if checkErr != nil {
+			c.Detail = checkErr.Error()
+		}
+		checks = append(checks, c)
+	}
+
+	p, err := loadSmokeProfile(profilesDir)
+	if err != nil {
+		return checks, fmt.Errorf("failed to load the smoke profile: %w", err)
+	}
+	namespace, err := framework.RunNamespace(preflightProfile, runID)
+	if err != nil {
+		return checks, err
+	}
+
+	fwConfig := config.FromEnv()
+	if allowUnsafe {
+		fwConfig = fwConfig.WithAllowUnsafeCluster(true)
+	}
+	fw, err := framework.New(ctx, namespace,
+		framework.WithRunID(runID),
+		framework.WithConfig(fwConfig),
+		framework.WithKubeconfig(kubeconfig),
+		framework.WithKubeContext(kubeContext),
+		framework.WithNamespaceQuota(suite.NamespaceQuota(p)),
+	)
+	if err != nil {
+		return checks, fmt.Errorf("failed to create framework: %w", err)
+	}
+	if err := fw.CheckClusterSafety(); err != nil {
+		return checks, fmt.Errorf("%w (use --allow-unsafe-cluster to override)", err)
+	}
+	if err := fw.CheckNamespaceAvailable(); err != nil {
+		return checks, fmt.Errorf("%w (choose a different --run-id or clean up the namespace)", err)
+	}
+
+	fmt.Printf("Running preflight in namespace %s...\n", namespace)
+	if !skipCleanup {
+		defer func() {
+			fmt.Printf("\nCleaning up namespace %s...\n", namespace)
+			if cleanupErr := fw.Cleanup(); cleanupErr != nil {
+				fmt.Printf("Warning: cleanup failed: %v\n", cleanupErr)
+			}
+		}()
+	}
+
+	// Thanos access: the clock check queries the server time through the metrics client
+	fmt.Println("Checking Thanos access...")
+	skew, err := fw.CheckClockSkew()
+	if skew != nil && skew.PrometheusError != nil {
+		check("Thanos access", skew.PrometheusError, "")
+	} else if err != nil {
+		check("Thanos access", err, "")
+	} else {
+		check("Thanos access", nil, fmt.Sprintf("clock skew %s", skew.Prometheus.Round(time.Millisecond)))
+	}
+
+	fmt.Println("Checking prerequisites...")
+	prereqs, err := fw.CheckPrerequisites()
+	if err != nil {
+		return checks, fmt.Errorf("failed to check prerequisites: %w", err)
+	}
+	if !prereqs.AllMet {
+		return checks, fmt.Errorf("prerequisites not met: Tempo=%v, OTel=%v",
+			prereqs.TempoOperator.Installed, prereqs.OpenTelemetryOperator.Installed)
+	}
+
+	fmt.Println("Enabling user workload monitoring...")
+	check("User workload monitoring", userWorkloadMonitoring(fw), "enabled")
+
+	if !p.Storage.UsesPV() {
+		fmt.Println("Setting up MinIO...")
+		if err := fw.SetupMinIOWithConfig(suite.MinIOConfig(p)); err != nil {
+			return checks, fmt.Errorf("failed to setup MinIO: %w", err)
+		}
+	}
+	fmt.Printf("Setting up Tempo (%s)...\n", p.Tempo.Variant)
+	if err := fw.SetupTempo(p.Tempo.Variant, suite.ResourceConfig(p, nil)); err != nil {
+		return checks, fmt.Errorf("failed to setup Tempo: %w", err)
+	}
+	fmt.Println("Setting up OTel Collector...")
+	if err := fw.SetupOTelCollector(p.Tempo.Variant); err != nil {
+		return checks, fmt.Errorf("failed to setup OTel Collector: %w", err)
+	}
+
+	fmt.Println("Setting up Tempo monitoring...")
+	check("Tempo ServiceMonitors", fw.SetupTempoMonitoring(p.Tempo.Variant), "ServiceMonitor or PodMonitor in place")
+	check("OTel Collector monitoring", fw.SetupOTelCollectorMonitoring(), "ServiceMonitor or PodMonitor in place")
+
+	fmt.Println("Setting up k6 Prometheus metrics...")
+	prometheusRWURL, rwErr := fw.SetupK6PrometheusMetrics()
+	check("k6 remote write", rwErr, prometheusRWURL)
+
+	// The synthetic load: the smoke workload, shortened to the preflight duration
+	k6Config := suite.K6Config(p)
+	k6Config.Duration = duration.String()
+	k6Config.PrometheusRWURL = prometheusRWURL
+
+	fmt.Printf("Running the synthetic combined load for %s...\n", duration)
+	testStart := fw.Now()
+	result, err := fw.RunK6ParallelTests(k6Config)
+	if err != nil {
+		return checks, fmt.Errorf("synthetic load failed: %w", err)
+	}
+	if !result.Success() {
+		check("Synthetic load", fmt.Errorf("k6 jobs did not succeed"), "")
+	} else {
+		check("Synthetic load", nil, fmt.Sprintf("%s combined load", duration))
+	}
+
+	fmt.Printf("Waiting %s for the last samples to be scraped...\n", preflightScrapeWait)
+	select {
+	case <-ctx.Done():
+		return checks, ctx.Err()
+	case <-time.After(preflightScrapeWait):
+	}
+
+	report, err := fw.CheckMetricAvailability(fw.Now().Sub(testStart))
+	if err != nil {
+		check("Metric availability", err, "")
+		return checks, nil
+	}
+	fw.PrintMetricAvailabilityReport(report)
+	issues := printDiagnosticHints(fw, report)
+	if len(issues) > 0 {
+		check("Metric availability", fmt.Errorf("%d/%d metrics available, %d diagnostic hint(s)",
+			report.AvailableMetrics, report.TotalMetrics, len(issues)), "")
+	} else {
+		check("Metric availability", nil, fmt.Sprintf("%d/%d metrics available", report.AvailableMetrics, report.TotalMetrics))
+	}
+	return checks, nil
+}
+
+// userWorkloadMonitoring enables user workload monitoring and checks that it is on
+func userWorkloadMonitoring(fw *framework.Framework) error {
+	if err := fw.EnableUserWorkloadMonitoring(); err != nil {
+		return err
+	}
+	enabled, err := fw.IsUserWorkloadMonitoringEnabled()
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		return fmt.Errorf("user workload monitoring is not enabled")
+	}
+	return nil
+}
+
+// printDiagnosticHints prints the likely causes of missing metrics and returns them
+func printDiagnosticHints(fw *framework.Framework, report *metrics.AvailabilityReport) []string {
+	if report.MissingMetrics == 0 {
+		return nil
+	}
+	issues := fw.DiagnoseMetricIssues(report)
+	if len(issues) > 0 {
+		fmt.Println("\nDiagnostic hints:")
+		for _, issue := range issues {
+			fmt.Printf("  ⚠️  %s\n", issue)
+		}
+	}
+	return issues
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/redhat/perf-tests-tempo/test/framework"
+	"github.com/redhat/perf-tests-tempo/test/framework/k6"
+	"github.com/redhat/perf-tests-tempo/test/framework/profile"
+	"github.com/redhat/perf-tests-tempo/test/framework/suite"
+
+	"k8s.io/client-go/rest"
+)
+
+// renderManifests writes the manifests each profile would apply to <dir>/<profile>/.
+// The framework gets an unused REST config, so nothing connects to the cluster.
+func renderManifests(profiles []*profile.Profile, testType k6.TestType, runID, dir string, nodeSelector map[string]string) error {
+	fmt.Println("\nRendered manifests:")
+	for _, p := range profiles {
+		namespace, err := framework.RunNamespace(p.Name, runID)
+		if err != nil {
+			return err
+		}
+		fw, err := framework.New(context.Background(), namespace,
+			framework.WithRunID(runID),
+			framework.WithRESTConfig(&rest.Config{}),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create framework: %w", err)
+		}
+		paths, err := fw.RenderManifests(suite.ManifestConfig(p, testType, nodeSelector), filepath.Join(dir, p.Name))
+		if err != nil {
+			return fmt.Errorf("profile %s: %w", p.Name, err)
+		}
+		for _, path := range paths {
+			fmt.Printf("  %s\n", path)
+		}
+	}
+	return nil
+}
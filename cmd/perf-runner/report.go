@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/metrics"
+	"github.com/redhat/perf-tests-tempo/test/framework/notify"
+	"github.com/redhat/perf-tests-tempo/test/framework/profile"
+	"github.com/redhat/perf-tests-tempo/test/framework/report"
+	"github.com/redhat/perf-tests-tempo/test/framework/suite"
+)
+
+// reportSuffix is the file name suffix of the Markdown summary of a profile run
+const reportSuffix = "-summary.md"
+
+// summarySuffix is the file name suffix of the JSON summary of a profile run
+const summarySuffix = "-summary.json"
+
+// writeRunReport writes the Markdown and JSON summaries of a profile run next to
+// its other output files and returns the Markdown one. Artifacts are linked under
+// the dashboard base URL of the notifications when one is set.
+func writeRunReport(p *profile.Profile, result *RunResult, opts *runOptions, notifyConfig *notify.Config) string {
+	filePrefix := fmt.Sprintf("%s/%s-%s", opts.outputDir, p.Name, opts.runID)
+	reportFile := filePrefix + reportSuffix
+	summaryFile := filePrefix + summarySuffix
+
+	run := &report.Run{
+		Profile:         p.Name,
+		RunID:           opts.runID,
+		Variant:         p.Tempo.Variant,
+		TestType:        profileTestType(p, opts.testType),
+		Duration:        result.Duration,
+		Error:           result.Error,
+		SLOViolated:     result.SLOViolated,
+		Phases:          result.Phases,
+		Retries:         result.Retries,
+		ArtifactBaseURL: profileNotifyConfig(notifyConfig, p).DashboardBaseURL,
+	}
+	if result.TimedOut {
+		run.TimedOutStage = result.TimedOutPhase
+	}
+	if quota := suite.NamespaceQuota(p); quota != nil {
+		run.Quota = quota.String()
+	}
+	// Quota-exceeded events are in the events of runs that failed too
+	if events, err := metrics.LoadClusterEvents(filePrefix + metrics.ClusterEventsSuffix); err == nil {
+		for _, e := range events {
+			if e.IsQuotaExceeded() {
+				run.QuotaExceeded = append(run.QuotaExceeded, e)
+			}
+		}
+	}
+	// Missing for runs that failed before the load
+	if alerts, err := metrics.LoadAlerts(filePrefix + metrics.AlertsSuffix); err == nil {
+		run.Alerts = alerts
+	}
+	if result.MetadataPath != "" {
+		meta, err := metrics.LoadRunMetadata(result.MetadataPath)
+		if err != nil {
+			fmt.Printf("Warning: failed to load run metadata for the report: %v\n", err)
+		} else {
+			run.Metadata = meta
+			// The recorded duration includes cleanup
+			if meta.Duration > 0 {
+				run.Duration = meta.Duration
+			}
+		}
+	}
+
+	files, _ := filepath.Glob(filePrefix + "-*")
+	for _, file := range files {
+		if file == reportFile || file == summaryFile {
+			continue
+		}
+		name := strings.TrimPrefix(filepath.Base(file), filepath.Base(filePrefix)+"-")
+		run.Artifacts = append(run.Artifacts, report.Artifact{Name: name, Path: file})
+	}
+
+	var results []metrics.MetricResult
+	if result.MetricsPath != "" {
+		var err error
+		if results, err = metrics.Load(result.MetricsPath); err != nil {
+			fmt.Printf("Warning: failed to load metrics for the report: %v\n", err)
+		}
+	}
+
+	if err := report.WriteSummary(report.GenerateSummary(run, results), summaryFile); err != nil {
+		fmt.Printf("Warning: failed to write JSON summary: %v\n", err)
+	} else {
+		fmt.Printf("JSON summary written: %s\n", summaryFile)
+	}
+
+	summary := report.GenerateMarkdown(run, results)
+	if err := os.WriteFile(reportFile, []byte(summary), 0644); err != nil {
+		fmt.Printf("Warning: failed to write summary report: %v\n", err)
+		return summary
+	}
+	fmt.Printf("Summary report written: %s\n", reportFile)
+	result.ReportPath = reportFile
+	return summary
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/metrics"
+	"github.com/redhat/perf-tests-tempo/test/framework/profile"
+	"github.com/redhat/perf-tests-tempo/test/framework/suite"
+)
+
+// defaultProfileOverhead is the estimated time a profile spends outside load
+// generation (deployment, verification, metrics collection, reports and cleanup)
+// when no past run records it
+const defaultProfileOverhead = 10 * time.Minute
+
+// scheduleEntry is the estimated run time of a profile
+type scheduleEntry struct {
+	Profile  string
+	Load     time.Duration
+	Overhead time.Duration
+	// Basis describes where the overhead estimate comes from
+	Basis string
+}
+
+// Estimate returns the estimated run time of the profile
+func (e scheduleEntry) Estimate() time.Duration {
+	return e.Load + e.Overhead
+}
+
+// estimateSchedule estimates the run time of each profile as its load duration plus
+// the average overhead of the finished runs in historyDir: runs of the same profile
+// and variant, else runs of any profile, else defaultProfileOverhead
+func estimateSchedule(profiles []*profile.Profile, historyDir string) []scheduleEntry {
+	runs, err := metrics.ListRuns(historyDir)
+	if err != nil {
+		fmt.Printf("Warning: failed to read past run timings: %v\n", err)
+	}
+
+	var all []time.Duration
+	byProfile := make(map[string][]time.Duration)
+	for _, r := range runs {
+		if r.Duration <= 0 || r.Duration < r.TestDuration {
+			continue
+		}
+		overhead := r.Duration - r.TestDuration
+		all = append(all, overhead)
+		byProfile[r.Profile+"/"+r.Variant] = append(byProfile[r.Profile+"/"+r.Variant], overhead)
+	}
+
+	entries := make([]scheduleEntry, 0, len(profiles))
+	for _, p := range profiles {
+		entry := scheduleEntry{
+			Profile:  p.Name,
+			Load:     profileLoadDuration(p),
+			Overhead: defaultProfileOverhead,
+			Basis:    "default overhead",
+		}
+		if overheads := byProfile[p.Name+"/"+p.Tempo.Variant]; len(overheads) > 0 {
+			entry.Overhead = averageDuration(overheads)
+			entry.Basis = fmt.Sprintf("%d past run(s)", len(overheads))
+		} else if len(all) > 0 {
+			entry.Overhead = averageDuration(all)
+			entry.Basis = "past runs of other profiles"
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// profileLoadDuration returns the time a profile generates load: the sum of its
+// phases, or the k6 duration
+func profileLoadDuration(p *profile.Profile) time.Duration {
+	if len(p.Phases) > 0 {
+		var total time.Duration
+		for _, phase := range p.Phases {
+			d, _ := time.ParseDuration(phase.Duration) // validated by the profile loader
+			total += d
+		}
+		return total
+	}
+
+	d, err := time.ParseDuration(suite.K6Config(p).Duration)
+	if err != nil {
+		return 5 * time.Minute
+	}
+	return d
+}
+
+// averageDuration returns the mean of durations
+func averageDuration(durations []time.Duration) time.Duration {
+	var total time.Duration
+	for _, d := range durations {
+		total += d
+	}
+	return total / time.Duration(len(durations))
+}
+
+// printSchedule prints the estimated start and end of each profile when run in
+// order from start, and the estimated end of the suite
+func printSchedule(title string, entries []scheduleEntry, start time.Time) {
+	if len(entries) == 0 {
+		return
+	}
+
+	fmt.Printf("%s:\n", title)
+	at := start
+	for _, e := range entries {
+		end := at.Add(e.Estimate())
+		fmt.Printf("  %-20s %s - %s  (%s: %s load + %s overhead, %s)\n",
+			e.Profile, at.Format("15:04"), end.Format("15:04"),
+			e.Estimate().Round(time.Minute), e.Load.Round(time.Second), e.Overhead.Round(time.Minute), e.Basis)
+		at = end
+	}
+	fmt.Printf("  Total: %s, estimated to finish at %s\n\n", at.Sub(start).Round(time.Minute), at.Format("2006-01-02 15:04 MST"))
+}
+
+// recordRunDuration adds the wall time of a finished profile run to its run
+// metadata, so later runs can estimate their schedule from it
+func recordRunDuration(metadataPath string, duration time.Duration) {
+	meta, err := metrics.LoadRunMetadata(metadataPath)
+	if err != nil {
+		fmt.Printf("Warning: failed to record run duration: %v\n", err)
+		return
+	}
+	meta.Duration = duration
+	if err := metrics.WriteRunMetadata(meta, metadataPath); err != nil {
+		fmt.Printf("Warning: failed to record run duration: %v\n", err)
+	}
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework"
+	"github.com/redhat/perf-tests-tempo/test/framework/k6"
+	"github.com/redhat/perf-tests-tempo/test/framework/metrics"
+	"github.com/redhat/perf-tests-tempo/test/framework/notify"
+	"github.com/redhat/perf-tests-tempo/test/framework/profile"
+	"github.com/redhat/perf-tests-tempo/test/framework/schedule"
+	"github.com/redhat/perf-tests-tempo/test/framework/upload"
+)
+
+// runServe implements the serve subcommand, which runs the profiles of a schedule
+// file on their cron schedules until interrupted and serves their status over HTTP
+func runServe(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	var (
+		schedulePath    = fs.String("schedule", "", "Schedule file listing the profiles to run and their cron expressions (required)")
+		listen          = fs.String("listen", ":8080", "Address of the status endpoint (/status, /runs, /healthz); empty disables it")
+		profilesDir     = fs.String("profiles-dir", "profiles", "Directory containing profile YAML files")
+		outputDir       = fs.String("output", "results", "Output directory of the runs, where the schedule status is kept")
+		nodeSelector    = fs.String("node-selector", "", "Node selector for Tempo pods (e.g., 'node-role.kubernetes.io/infra=')")
+		compareBaseline = fs.Bool("compare-baseline", true, "Compare each profile with the latest earlier run of the same profile, variant, and Tempo version")
+		allowUnsafe     = fs.Bool("allow-unsafe-cluster", false, "Run even if the cluster fails the safety guardrails")
+		uploadURL       = fs.String("upload", "", "Upload the results of each run to s3://bucket/prefix or gs://bucket/prefix (credentials from TEMPO_PERF_UPLOAD_* or AWS_* env vars)")
+		kubeconfig      = fs.String("kubeconfig", "", "Path to the kubeconfig of the target cluster (default: in-cluster config, KUBECONFIG, or ~/.kube/config)")
+		kubeContext     = fs.String("context", "", "Kubeconfig context of the target cluster (default: the current context)")
+		profileTimeout  = fs.Duration("profile-timeout", 0, "Deadline of each profile run, overridden by the timeout of the profile (0 disables)")
+	)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: perf-runner serve --schedule <file> [flags]\n\n")
+		fmt.Fprintf(fs.Output(), "Runs the profiles of the schedule file when their cron expressions fire, one\n")
+		fmt.Fprintf(fs.Output(), "run at a time, until interrupted. Each run gets its own run ID and writes its\n")
+		fmt.Fprintf(fs.Output(), "results to --output like a regular run.\n\n")
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+
+	if *schedulePath == "" {
+		fmt.Fprintln(os.Stderr, "Error: --schedule is required")
+		fs.Usage()
+		os.Exit(1)
+	}
+	config, err := schedule.Load(*schedulePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	// Fail on missing profiles now rather than at the first run
+	for _, e := range config.Schedules {
+		if _, err := profile.LoadByNames(*profilesDir, e.Profiles); err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading profiles of schedule %s: %v\n", e.Name, err)
+			os.Exit(1)
+		}
+	}
+
+	var uploader *upload.Uploader
+	if *uploadURL != "" {
+		target, err := upload.ParseURL(*uploadURL)
+		if err == nil {
+			uploader, err = upload.New(target, upload.FromEnv())
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid --upload: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if err := os.MkdirAll(*outputDir, 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating output directory: %v\n", err)
+		os.Exit(1)
+	}
+	tracker, err := schedule.NewTracker(config, filepath.Join(*outputDir, schedule.StatusFile))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	if *listen != "" {
+		server := &http.Server{Addr: *listen, Handler: tracker.Handler(*outputDir), ReadHeaderTimeout: 10 * time.Second}
+		go func() {
+			if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				fmt.Fprintf(os.Stderr, "Error: status endpoint failed: %v\n", err)
+				cancel()
+			}
+		}()
+		defer server.Close()
+		fmt.Printf("Serving the schedule status on %s\n", *listen)
+	}
+
+	base := &runOptions{
+		outputDir:         *outputDir,
+		preserveTTL:       24 * time.Hour,
+		generateDashboard: true,
+		collectLogs:       true,
+		verifyIngestion:   true,
+		verifyQueries:     framework.DefaultQueryVerifyPercent,
+		minAchievedRate:   framework.DefaultMinAchievedRatePercent,
+		allowUnsafe:       *allowUnsafe,
+		compareBaseline:   *compareBaseline,
+		baselineDir:       *outputDir,
+		samplingInterval:  metrics.DefaultSamplingInterval,
+		scrapeInterval:    metrics.DefaultScrapeInterval,
+		storageInterval:   metrics.DefaultStorageSamplingInterval,
+		profileTimeout:    *profileTimeout,
+		kubeconfig:        *kubeconfig,
+		kubeContext:       *kubeContext,
+		nodeSelector:      parseNodeSelector(*nodeSelector),
+		uploader:          uploader,
+	}
+	notifyConfig, err := notify.FromEnv()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	for _, e := range config.Schedules {
+		fmt.Printf("Schedule %s (%s): %v, next run %s\n", e.Name, e.Cron, e.Profiles, config.Next(&e, time.Now()).Format(time.RFC3339))
+	}
+	schedule.Run(ctx, config, tracker, func(ctx context.Context, e *schedule.Entry) {
+		runScheduled(ctx, e, *profilesDir, base, notifyConfig, tracker)
+	})
+	fmt.Println("Scheduler stopped")
+}
+
+// runScheduled runs the profiles of a schedule entry as one run with a new run ID,
+// like a regular run of those profiles, and records the outcome in the tracker
+func runScheduled(ctx context.Context, e *schedule.Entry, profilesDir string, base *runOptions, notifyConfig *notify.Config, tracker *schedule.Tracker) {
+	opts := *base
+	opts.runID = framework.NewRunID()
+	opts.testType = k6.TestType(e.TestType)
+
+	fmt.Printf("\nScheduled run %s of %s started (run ID %s)\n", e.Name, e.Cron, opts.runID)
+	tracker.Start(e.Name, opts.runID, time.Now())
+	err := runScheduledProfiles(ctx, e, profilesDir, &opts, notifyConfig, tracker)
+	if err != nil {
+		fmt.Printf("Scheduled run %s failed: %v\n", e.Name, err)
+	}
+	if err := tracker.Finish(e.Name, time.Now(), err); err != nil {
+		fmt.Printf("Warning: failed to persist the schedule status: %v\n", err)
+	}
+}
+
+// runScheduledProfiles runs the profiles of a schedule entry in order. Profiles are
+// loaded at each run, so edits apply without restarting the scheduler.
+func runScheduledProfiles(ctx context.Context, e *schedule.Entry, profilesDir string, opts *runOptions, notifyConfig *notify.Config, tracker *schedule.Tracker) error {
+	profiles, err := profile.LoadByNames(profilesDir, e.Profiles)
+	if err != nil {
+		return fmt.Errorf("failed to load profiles: %w", err)
+	}
+	for _, p := range profiles {
+		if _, err := framework.RunNamespace(p.Name, opts.runID); err != nil {
+			return err
+		}
+	}
+
+	// Deferred cleanups of earlier runs are completed first
+	cleanupExpiredNamespaces(ctx, opts)
+	notifyRunStarted(notifyConfig, profiles, string(opts.testType))
+
+	results := make(map[string]*RunResult)
+	for _, p := range profiles {
+		if ctx.Err() != nil {
+			notifyAborted(notifyConfig, p, fmt.Errorf("scheduler stopped"))
+			break
+		}
+
+		profileStart := time.Now()
+		result := runProfile(ctx, p, opts)
+		elapsed := time.Since(profileStart)
+		results[p.Name] = result
+
+		if result.Error != nil {
+			fmt.Printf("Profile %s failed: %v\n", p.Name, result.Error)
+		}
+		if result.MetadataPath != "" {
+			recordRunDuration(result.MetadataPath, elapsed)
+		}
+		summary := writeRunReport(p, result, opts, notifyConfig)
+		notifyProfileResult(notify.New(profileNotifyConfig(notifyConfig, p)).WithReport(summary), result)
+
+		status := schedule.ProfileStatus{
+			Name:        p.Name,
+			Success:     result.Error == nil,
+			SLOViolated: result.SLOViolated,
+			Duration:    elapsed,
+			MetricsPath: result.MetricsPath,
+			ReportPath:  result.ReportPath,
+		}
+		if result.TimedOut {
+			status.TimedOutPhase = result.TimedOutPhase
+		}
+		if result.Error != nil {
+			status.Error = result.Error.Error()
+		}
+		tracker.ProfileDone(e.Name, status)
+	}
+
+	if opts.generateDashboard {
+		generateComparisonDashboard(profiles, results, opts)
+	}
+	uploadErr := uploadResults(opts)
+	printSummary(results)
+	if ctx.Err() != nil {
+		return fmt.Errorf("scheduler stopped during the run")
+	}
+	return uploadErr
+}
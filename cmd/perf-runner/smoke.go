@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/metrics"
+	"github.com/redhat/perf-tests-tempo/test/framework/profile"
+)
+
+// smokeDir is the directory of the smoke profile and its golden ranges, relative to
+// the profiles directory. It is a subdirectory so runs of all profiles skip it.
+const smokeDir = "smoke"
+
+// smokeRecordFactor widens recorded golden ranges to value/3 .. value*3
+const smokeRecordFactor = 3
+
+// loadSmokeProfile loads the fixed workload of smoke runs
+func loadSmokeProfile(profilesDir string) (*profile.Profile, error) {
+	return profile.Load(filepath.Join(profilesDir, smokeDir, "profile.yaml"))
+}
+
+// defaultSmokeGoldenPath returns the golden ranges stored next to the smoke profile
+func defaultSmokeGoldenPath(profilesDir string) string {
+	return filepath.Join(profilesDir, smokeDir, "golden.yaml")
+}
+
+// checkSmoke compares the metrics of a smoke run with the golden ranges, failing the
+// run when a metric is missing or out of range. With --smoke-record the bounds are
+// re-recorded from the run instead.
+func checkSmoke(result *RunResult, opts *runOptions) {
+	if result.Error != nil {
+		return
+	}
+	if result.MetricsPath == "" {
+		result.Success = false
+		result.Error = fmt.Errorf("smoke check failed: no metrics were collected")
+		return
+	}
+
+	results, err := metrics.LoadFromCSV(result.MetricsPath)
+	if err != nil {
+		result.Success = false
+		result.Error = fmt.Errorf("smoke check failed: %w", err)
+		return
+	}
+
+	if opts.smokeRecord {
+		recorded := metrics.RecordGoldenRanges(results, opts.smokeGolden, smokeRecordFactor)
+		if err := metrics.WriteGoldenRanges(recorded, opts.smokeGoldenPath); err != nil {
+			result.Success = false
+			result.Error = fmt.Errorf("failed to record golden ranges: %w", err)
+			return
+		}
+		fmt.Printf("\nRecorded golden ranges to %s:\n", opts.smokeGoldenPath)
+		for _, check := range metrics.CheckGoldenRanges(results, recorded) {
+			fmt.Printf("  %s\n", check)
+		}
+		return
+	}
+
+	fmt.Printf("\nComparing smoke metrics with golden ranges from %s:\n", opts.smokeGoldenPath)
+	failed := 0
+	for _, check := range metrics.CheckGoldenRanges(results, opts.smokeGolden) {
+		fmt.Printf("  %s\n", check)
+		if !check.Passed {
+			failed++
+		}
+	}
+	if failed > 0 {
+		result.Success = false
+		result.Error = fmt.Errorf("smoke check failed: %d metric(s) outside golden ranges", failed)
+		return
+	}
+	fmt.Println("Smoke check passed")
+}
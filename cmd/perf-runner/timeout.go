@@ -0,0 +1,62 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework"
+	"github.com/redhat/perf-tests-tempo/test/framework/profile"
+)
+
+// errProfileTimeout is the cause of the run context of a profile that exceeded
+// its timeout, which tells it apart from an interrupt
+var errProfileTimeout = errors.New("profile timeout exceeded")
+
+// timeoutGracePeriod bounds the collection of results and the cleanup of a run
+// that exceeded its timeout
+const timeoutGracePeriod = 10 * time.Minute
+
+// Stages of a profile run, reported when it exceeds its timeout
+const (
+	stageSetup   = "setup"
+	stageLoad    = "load"
+	stageMetrics = "metrics collection"
+	stageLogs    = "log collection"
+	stageReport  = "reporting"
+	stageDone    = "done"
+)
+
+// salvageTimedOutRun marks a run that exceeded its timeout during stage and saves
+// the alerts, metrics and logs collected so far. It runs with the grace context
+// of the framework, before the namespace is cleaned up. Runs that time out before
+// reporting are not recorded, so they never become baselines.
+func salvageTimedOutRun(fw *framework.Framework, p *profile.Profile, opts *runOptions, result *RunResult, stage string, testStart time.Time, filePrefix string, timeout time.Duration) {
+	fmt.Printf("\nProfile %s timed out after %s during %s, saving the results collected so far...\n", p.Name, timeout, stage)
+	result.Success = false
+	result.SLOViolated = false
+	result.TimedOut = true
+	result.TimedOutPhase = stage
+	result.Error = fmt.Errorf("profile timed out after %s during %s", timeout, stage)
+
+	// Metrics and alerts only exist once the load started
+	if !testStart.IsZero() && result.MetricsPath == "" {
+		writeAlerts(fw, filePrefix, testStart)
+		metricsFile := fmt.Sprintf("%s-metrics.csv", filePrefix)
+		fmt.Printf("Collecting metrics to %s...\n", metricsFile)
+		if err := collectMetrics(fw, p, testStart, metricsFile, opts.rollupInterval); err != nil {
+			fmt.Printf("Warning: failed to collect metrics: %v\n", err)
+		} else {
+			result.MetricsPath = metricsFile
+		}
+	}
+
+	// A preserved namespace gets its diagnostics collected on preservation
+	if opts.preserveOnFailure && !opts.skipCleanup {
+		return
+	}
+	fmt.Println("Collecting component logs...")
+	if _, err := fw.CollectLogs(&framework.LogCollectionConfig{OutputDir: opts.outputDir}); err != nil {
+		fmt.Printf("Warning: failed to collect logs: %v\n", err)
+	}
+}
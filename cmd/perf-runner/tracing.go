@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/profile"
+	"github.com/redhat/perf-tests-tempo/test/framework/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracingServiceName is the service name of the spans of the runner
+const tracingServiceName = "tempo-perf-runner"
+
+// tracingFlushTimeout bounds exporting the spans of a profile
+const tracingFlushTimeout = 30 * time.Second
+
+// tracingEnabled returns true if an OTLP endpoint is configured for the traces
+// of the framework itself
+func tracingEnabled() bool {
+	return os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != ""
+}
+
+// newTracerProvider creates the provider exporting the spans of the framework over
+// OTLP/HTTP. The exporter is configured with the standard OTEL_EXPORTER_OTLP_*
+// environment variables.
+func newTracerProvider(ctx context.Context) (*sdktrace.TracerProvider, error) {
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+	// OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES override the defaults
+	res, err := resource.New(ctx,
+		resource.WithAttributes(attribute.String("service.name", tracingServiceName)),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace resource: %w", err)
+	}
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	), nil
+}
+
+// startRunSpan starts the root span of a profile run, if tracing is enabled
+func startRunSpan(ctx context.Context, p *profile.Profile, opts *runOptions, namespace string) (context.Context, trace.Span) {
+	if opts.tracerProvider == nil {
+		return ctx, trace.SpanFromContext(ctx)
+	}
+	ctx = tracing.ContextWithTracerProvider(ctx, opts.tracerProvider)
+	return tracing.Start(ctx, "perf-run",
+		attribute.String("tempo_perf.profile", p.Name),
+		attribute.String("tempo_perf.run_id", opts.runID),
+		attribute.String("tempo_perf.test_type", string(opts.testType)),
+		attribute.String("k8s.namespace.name", namespace),
+	)
+}
+
+// endRunSpan ends the root span of a profile run with its result and exports its
+// spans, so they are not lost if a later profile force-exits
+func endRunSpan(span trace.Span, result *RunResult, opts *runOptions) {
+	if opts.tracerProvider == nil {
+		return
+	}
+	span.SetAttributes(attribute.Bool("tempo_perf.slo_violated", result.SLOViolated))
+	tracing.End(span, result.Error)
+
+	ctx, cancel := context.WithTimeout(context.Background(), tracingFlushTimeout)
+	defer cancel()
+	if err := opts.tracerProvider.ForceFlush(ctx); err != nil {
+		fmt.Printf("Warning: failed to export framework traces: %v\n", err)
+	}
+}
@@ -0,0 +1,428 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/redhat/perf-tests-tempo/test/framework"
+	"github.com/redhat/perf-tests-tempo/test/framework/metrics"
+
+	"golang.org/x/term"
+)
+
+const (
+	// tuiRefreshInterval is how often the terminal view is redrawn
+	tuiRefreshInterval = time.Second
+	// tuiMetricsInterval is how often the live metrics of the load are queried
+	tuiMetricsInterval = 15 * time.Second
+	// tuiOutputLines is how many recent output lines the view keeps
+	tuiOutputLines = 200
+	// tuiBarWidth is the width of the progress bars
+	tuiBarWidth = 24
+)
+
+// ANSI sequences of the terminal view
+const (
+	ansiHome       = "\x1b[H"
+	ansiClearLine  = "\x1b[K"
+	ansiClearBelow = "\x1b[J"
+	ansiHideCursor = "\x1b[?25l"
+	ansiShowCursor = "\x1b[?25h"
+)
+
+// tuiProfile is the progress of a profile in the terminal view
+type tuiProfile struct {
+	name     string
+	estimate time.Duration
+	load     time.Duration
+	// stage is empty until the profile starts
+	stage       string
+	started     time.Time
+	loadStarted time.Time
+	// elapsed and failed are set once the profile finished
+	elapsed time.Duration
+	failed  bool
+	done    bool
+}
+
+// tuiView is the live terminal view of --tui: the progress of each profile, key
+// metrics of the running load and the recent output. While it runs, the output of
+// the runner is redirected into the view and saved to a log file.
+type tuiView struct {
+	mu       sync.Mutex
+	terminal *os.File
+	stderr   *os.File
+	pipe     *os.File
+	logFile  *os.File
+	runID    string
+	start    time.Time
+	profiles []*tuiProfile
+	lines    []string
+
+	// Live metrics of the running profile; NaN until queried
+	metricsProfile string
+	mbPerSecond    float64
+	refusedSpans   float64
+	metricsErr     error
+
+	stopOnce sync.Once
+	done     chan struct{}
+	wg       sync.WaitGroup
+}
+
+// startTUI takes over the terminal with the view of the profiles of schedule,
+// saving the output of the run to logPath
+func startTUI(runID string, schedule []scheduleEntry, logPath string) (*tuiView, error) {
+	if !term.IsTerminal(int(os.Stdout.Fd())) {
+		return nil, errors.New("stdout is not a terminal")
+	}
+	logFile, err := os.Create(logPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create output log: %w", err)
+	}
+	r, w, err := os.Pipe()
+	if err != nil {
+		logFile.Close()
+		return nil, fmt.Errorf("failed to redirect output: %w", err)
+	}
+
+	v := &tuiView{
+		terminal:     os.Stdout,
+		stderr:       os.Stderr,
+		pipe:         w,
+		logFile:      logFile,
+		runID:        runID,
+		start:        time.Now(),
+		mbPerSecond:  math.NaN(),
+		refusedSpans: math.NaN(),
+		done:         make(chan struct{}),
+	}
+	for _, e := range schedule {
+		v.profiles = append(v.profiles, &tuiProfile{name: e.Profile, estimate: e.Estimate(), load: e.Load})
+	}
+
+	// fmt.Print* and the standard logger write to the pipe from now on
+	os.Stdout = w
+	os.Stderr = w
+	log.SetOutput(w)
+
+	v.wg.Add(2)
+	go v.readOutput(r)
+	go v.refresh()
+	fmt.Fprint(v.terminal, ansiHideCursor)
+	return v, nil
+}
+
+// readOutput keeps the recent lines written to the pipe and saves them to the log
+func (v *tuiView) readOutput(r *os.File) {
+	defer v.wg.Done()
+	defer r.Close()
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		fmt.Fprintln(v.logFile, line)
+		// Progress output rewrites its line with \r; keep the last state
+		if i := strings.LastIndex(line, "\r"); i >= 0 {
+			line = line[i+1:]
+		}
+		v.mu.Lock()
+		v.lines = append(v.lines, line)
+		if len(v.lines) > tuiOutputLines {
+			v.lines = v.lines[len(v.lines)-tuiOutputLines:]
+		}
+		v.mu.Unlock()
+	}
+}
+
+// refresh redraws the view until it is stopped
+func (v *tuiView) refresh() {
+	defer v.wg.Done()
+	ticker := time.NewTicker(tuiRefreshInterval)
+	defer ticker.Stop()
+	for {
+		v.draw()
+		select {
+		case <-v.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// draw renders the view on the terminal
+func (v *tuiView) draw() {
+	width, height, err := term.GetSize(int(v.terminal.Fd()))
+	if err != nil || width <= 0 || height <= 0 {
+		width, height = 100, 30
+	}
+	v.mu.Lock()
+	frame := v.frame(width, height, time.Now())
+	v.mu.Unlock()
+	// Overwrite the previous frame in place rather than clearing the screen, which flickers
+	frame = strings.ReplaceAll(frame, "\n", ansiClearLine+"\n")
+	fmt.Fprint(v.terminal, ansiHome+frame+ansiClearLine+ansiClearBelow)
+}
+
+// stop restores the terminal and the output of the runner, leaving the last view
+// on the screen. It can be called more than once.
+func (v *tuiView) stop() {
+	if v == nil {
+		return
+	}
+	v.stopOnce.Do(func() {
+		os.Stdout = v.terminal
+		os.Stderr = v.stderr
+		log.SetOutput(v.stderr)
+		v.pipe.Close()
+		close(v.done)
+		v.wg.Wait()
+		v.draw()
+		fmt.Fprint(v.terminal, ansiShowCursor)
+		v.logFile.Close()
+		fmt.Printf("\nOutput of the run saved to %s\n", v.logFile.Name())
+	})
+}
+
+// restore gives the terminal back without waiting for the view, on force exit
+func (v *tuiView) restore() {
+	if v == nil {
+		return
+	}
+	fmt.Fprint(v.terminal, ansiShowCursor+"\n")
+}
+
+// profile returns the progress of the named profile; the caller holds v.mu
+func (v *tuiView) profile(name string) *tuiProfile {
+	for _, p := range v.profiles {
+		if p.name == name {
+			return p
+		}
+	}
+	p := &tuiProfile{name: name}
+	v.profiles = append(v.profiles, p)
+	return p
+}
+
+// setStage records that a profile entered a stage of its run
+func (v *tuiView) setStage(name, stage string) {
+	if v == nil {
+		return
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	p := v.profile(name)
+	now := time.Now()
+	if p.started.IsZero() {
+		p.started = now
+	}
+	if stage == stageLoad {
+		p.loadStarted = now
+	}
+	p.stage = stage
+}
+
+// finishProfile records the result of a profile
+func (v *tuiView) finishProfile(name string, result *RunResult, elapsed time.Duration) {
+	if v == nil {
+		return
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	p := v.profile(name)
+	p.done = true
+	p.failed = result.Error != nil
+	p.elapsed = elapsed
+}
+
+// watchMetrics queries the ingested bytes and refused spans of the profile while
+// its load runs
+func (v *tuiView) watchMetrics(ctx context.Context, fw *framework.Framework, name string) {
+	if v == nil {
+		return
+	}
+	v.mu.Lock()
+	v.metricsProfile = name
+	v.mbPerSecond, v.refusedSpans, v.metricsErr = math.NaN(), math.NaN(), nil
+	v.mu.Unlock()
+
+	go func() {
+		querier, err := metrics.NewLiveQuerier(ctx, fw)
+		if err != nil {
+			v.mu.Lock()
+			v.metricsErr = err
+			v.mu.Unlock()
+			return
+		}
+		ticker := time.NewTicker(tuiMetricsInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-v.done:
+				return
+			case <-ticker.C:
+			}
+			v.mu.Lock()
+			loading := v.profile(name).stage == stageLoad
+			v.mu.Unlock()
+			if !loading {
+				return
+			}
+
+			bytes, bytesErr := querier.Value(ctx, "bytes_received_rate")
+			refused, refusedErr := querier.Value(ctx, "refused_spans_rate")
+			v.mu.Lock()
+			v.mbPerSecond, v.refusedSpans, v.metricsErr = math.NaN(), math.NaN(), nil
+			if bytesErr == nil {
+				v.mbPerSecond = bytes / (1024 * 1024)
+			}
+			if refusedErr == nil {
+				v.refusedSpans = refused
+			}
+			// No data yet is expected at the start of the load
+			for _, err := range []error{bytesErr, refusedErr} {
+				if err != nil && !errors.Is(err, metrics.ErrNoData) {
+					v.metricsErr = err
+				}
+			}
+			v.mu.Unlock()
+		}
+	}()
+}
+
+// frame returns the text of the view for a terminal of width x height; the
+// caller holds v.mu
+func (v *tuiView) frame(width, height int, now time.Time) string {
+	var lines []string
+	add := func(format string, args ...interface{}) {
+		lines = append(lines, fmt.Sprintf(format, args...))
+	}
+
+	finished := 0
+	for _, p := range v.profiles {
+		if p.done {
+			finished++
+		}
+	}
+	add("Tempo perf-runner | run %s | %s elapsed | %d/%d profiles done", v.runID, now.Sub(v.start).Round(time.Second), finished, len(v.profiles))
+	add("")
+	add("PROFILES")
+	nameWidth := 8
+	for _, p := range v.profiles {
+		nameWidth = max(nameWidth, utf8.RuneCountInString(p.name))
+	}
+	for _, p := range v.profiles {
+		add("%s %-*s %s %s", profileMarker(p), nameWidth, p.name, progressBar(profileProgress(p, now), tuiBarWidth), profileStatus(p, now))
+	}
+
+	if v.metricsProfile != "" {
+		add("")
+		add("LIVE METRICS (%s)", v.metricsProfile)
+		add("  Ingested        %s", formatLive(v.mbPerSecond, "MB/s"))
+		add("  Refused spans   %s", formatLive(v.refusedSpans, "spans/s"))
+		if v.metricsErr != nil {
+			add("  %v", v.metricsErr)
+		}
+	}
+
+	add("")
+	add("RECENT OUTPUT")
+	// The output fills the rest of the screen
+	room := height - len(lines) - 1
+	recent := v.lines
+	if room < len(recent) {
+		recent = recent[len(recent)-max(room, 0):]
+	}
+	for _, line := range recent {
+		lines = append(lines, "  "+line)
+	}
+
+	for i, line := range lines {
+		lines[i] = truncate(line, width)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// profileMarker returns the marker of the state of a profile
+func profileMarker(p *tuiProfile) string {
+	switch {
+	case p.done && p.failed:
+		return "✗"
+	case p.done:
+		return "✓"
+	case p.stage != "":
+		return "▶"
+	default:
+		return " "
+	}
+}
+
+// profileProgress returns the estimated fraction of a profile that has run
+func profileProgress(p *tuiProfile, now time.Time) float64 {
+	switch {
+	case p.done:
+		return 1
+	case p.started.IsZero() || p.estimate <= 0:
+		return 0
+	}
+	// Estimates are rough; a running profile is never shown complete
+	return min(now.Sub(p.started).Seconds()/p.estimate.Seconds(), 0.99)
+}
+
+// profileStatus returns the stage of a profile with its timings
+func profileStatus(p *tuiProfile, now time.Time) string {
+	switch {
+	case p.done && p.failed:
+		return fmt.Sprintf("failed after %s", p.elapsed.Round(time.Second))
+	case p.done:
+		return fmt.Sprintf("done in %s", p.elapsed.Round(time.Second))
+	case p.stage == "":
+		return fmt.Sprintf("pending (est %s)", p.estimate.Round(time.Minute))
+	case p.stage == stageLoad && p.load > 0:
+		loaded := now.Sub(p.loadStarted)
+		return fmt.Sprintf("load %s/%s (%.0f%%)", loaded.Round(time.Second), p.load, min(100*loaded.Seconds()/p.load.Seconds(), 100))
+	default:
+		return fmt.Sprintf("%s, %s (est %s)", p.stage, now.Sub(p.started).Round(time.Second), p.estimate.Round(time.Minute))
+	}
+}
+
+// progressBar renders fraction as a bar of width characters
+func progressBar(fraction float64, width int) string {
+	filled := int(math.Round(fraction * float64(width)))
+	filled = min(max(filled, 0), width)
+	return "[" + strings.Repeat("#", filled) + strings.Repeat("-", width-filled) + "]"
+}
+
+// formatLive formats a live metric value, "-" until it has been queried
+func formatLive(value float64, unit string) string {
+	if math.IsNaN(value) {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f %s", value, unit)
+}
+
+// truncate shortens line to width runes
+func truncate(line string, width int) string {
+	if utf8.RuneCountInString(line) <= width {
+		return line
+	}
+	runes := []rune(line)
+	return string(runes[:max(width, 0)])
+}
+
+// tuiLogPath returns the file the output of a --tui run is saved to
+func tuiLogPath(outputDir, runID string) string {
+	return filepath.Join(outputDir, fmt.Sprintf("perf-runner-%s.log", runID))
+}
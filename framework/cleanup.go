@@ -1,22 +1,78 @@
 package framework
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"sync"
 	"time"
 
 	"github.com/redhat/perf-tests-tempo/test/framework/gvr"
+	"github.com/redhat/perf-tests-tempo/test/framework/k6"
+	"github.com/redhat/perf-tests-tempo/test/framework/tracing"
+	"github.com/redhat/perf-tests-tempo/test/framework/wait"
 
+	"go.opentelemetry.io/otel/attribute"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 )
 
-// Cleanup removes all resources created by the framework
-func (f *Framework) Cleanup() error {
+// cleanupOptions configures Cleanup
+type cleanupOptions struct {
+	ttl time.Duration
+}
+
+// CleanupOption configures Cleanup
+type CleanupOption func(*cleanupOptions)
+
+// WithTTL defers the cleanup: instead of deleting its resources, the namespace is
+// preserved (see Preserve) and annotated to expire after ttl. Expired namespaces are
+// deleted by orphan cleanup, which perf-runner also runs when it starts.
+func WithTTL(ttl time.Duration) CleanupOption {
+	return func(o *cleanupOptions) {
+		o.ttl = ttl
+	}
+}
+
+// Cleanup removes all resources created by the framework, or defers their removal
+// when called with WithTTL
+func (f *Framework) Cleanup(opts ...CleanupOption) (err error) {
+	var o cleanupOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	_, span := f.startOperation("framework.Cleanup", attribute.Bool("cleanup.deferred", o.ttl > 0))
+	defer func() { tracing.End(span, err) }()
+
+	if f.resourceSampler != nil {
+		f.resourceSampler.Stop()
+	}
+	if f.metricsScraper != nil {
+		f.metricsScraper.Stop()
+	}
+	if f.storageSampler != nil {
+		f.storageSampler.Stop()
+	}
+	if f.rollupWriter != nil {
+		f.rollupWriter.Stop()
+	}
+	if w := f.EventWatcher(); w != nil {
+		w.Stop()
+	}
+
+	if o.ttl > 0 {
+		if err := f.Preserve(o.ttl); err != nil {
+			return fmt.Errorf("failed to defer cleanup: %w", err)
+		}
+		f.logger.Info("cleanup deferred", "namespace", f.namespace, "ttl", o.ttl)
+		return nil
+	}
+
 	f.logger.Info("starting cleanup", "namespace", f.namespace)
 
 	// 1. Delete CRs first (let operators clean up their managed resources)
@@ -50,6 +106,65 @@ func (f *Framework) Cleanup() error {
 	return nil
 }
 
+// CleanupLoadOnly removes the load generators (k6 Jobs, their pods and ConfigMaps)
+// but keeps Tempo, MinIO and the OTel Collector running, so load variations can run
+// against the same deployment without redeploying it
+func (f *Framework) CleanupLoadOnly() (err error) {
+	op, span := f.startOperation("framework.CleanupLoadOnly")
+	defer func() { tracing.End(span, err) }()
+
+	f.logger.Info("cleaning up load generators", "namespace", f.namespace)
+
+	if err := k6.Cleanup(op); err != nil {
+		return fmt.Errorf("failed to cleanup load generators: %w", err)
+	}
+
+	// Wait until no generator sends load, so the next test starts from a quiet Tempo
+	selector, err := labels.Parse(k6.LoadSelector)
+	if err != nil {
+		return fmt.Errorf("invalid k6 selector: %w", err)
+	}
+	if err := wait.ForPodsTerminated(op, selector, 0); err != nil {
+		return fmt.Errorf("k6 pods did not terminate: %w", err)
+	}
+
+	f.logger.Info("load generators cleaned up", "namespace", f.namespace)
+	return nil
+}
+
+// preserveTimeout bounds the namespace patch issued by Preserve
+const preserveTimeout = 30 * time.Second
+
+// Preserve keeps the namespace for debugging instead of cleaning it up.
+// The namespace is labeled as managed by the framework and annotated with an
+// expiry time (now + ttl) so that orphan cleanup can remove it later.
+// It does not use the framework context, which is usually cancelled when a run
+// is interrupted, so that interrupted runs are still marked for expiry.
+func (f *Framework) Preserve(ttl time.Duration) error {
+	expiresAt := time.Now().Add(ttl).UTC().Format(time.RFC3339)
+	f.logger.Info("preserving namespace", "namespace", f.namespace, "expiresAt", expiresAt)
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels":      f.GetManagedLabels(),
+			"annotations": map[string]string{AnnotationExpiresAt: expiresAt},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to build preservation patch: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), preserveTimeout)
+	defer cancel()
+
+	_, err = f.client.CoreV1().Namespaces().Patch(ctx, f.namespace, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to annotate namespace %s for preservation: %w", f.namespace, err)
+	}
+
+	return nil
+}
+
 // cleanupCRs deletes all tracked custom resources in parallel
 func (f *Framework) cleanupCRs() error {
 	trackedCRs := f.GetTrackedCRs()
@@ -0,0 +1,110 @@
+package framework
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPreserve_CancelledContext(t *testing.T) {
+	f := newOrphanTestFramework(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tempo-perf-small-abc"}})
+	f.namespace = "tempo-perf-small-abc"
+	f.runID = "abc"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	f.ctx = ctx
+
+	if err := f.Preserve(time.Hour); err != nil {
+		t.Fatalf("Preserve() error = %v", err)
+	}
+
+	ns, err := f.client.CoreV1().Namespaces().Get(context.Background(), f.namespace, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get namespace: %v", err)
+	}
+	if ns.Labels[LabelManagedBy] != LabelManagedByValue || ns.Labels[LabelRunID] != "abc" {
+		t.Errorf("namespace labels = %v, want managed labels", ns.Labels)
+	}
+	expiresAt, err := time.Parse(time.RFC3339, ns.Annotations[AnnotationExpiresAt])
+	if err != nil {
+		t.Fatalf("invalid %s annotation: %v", AnnotationExpiresAt, err)
+	}
+	if until := time.Until(expiresAt); until < 58*time.Minute || until > time.Hour {
+		t.Errorf("expires-at is %s from now, want about 1h", until)
+	}
+}
+
+func TestCleanup_WithTTL(t *testing.T) {
+	const ns = "tempo-perf-small-abc"
+	f := newOrphanTestFramework(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "tempo-simplest-config", Namespace: ns}},
+	)
+	f.namespace = ns
+
+	// Without a dynamic client, deleting the CRs would fail
+	if err := f.Cleanup(WithTTL(2 * time.Hour)); err != nil {
+		t.Fatalf("Cleanup(WithTTL) error = %v", err)
+	}
+
+	ctx := context.Background()
+	namespace, err := f.client.CoreV1().Namespaces().Get(ctx, ns, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected the namespace to be kept: %v", err)
+	}
+	expiresAt, err := time.Parse(time.RFC3339, namespace.Annotations[AnnotationExpiresAt])
+	if err != nil {
+		t.Fatalf("invalid %s annotation: %v", AnnotationExpiresAt, err)
+	}
+	if until := time.Until(expiresAt); until < 119*time.Minute || until > 2*time.Hour {
+		t.Errorf("expires-at is %s from now, want about 2h", until)
+	}
+	if _, err := f.client.CoreV1().ConfigMaps(ns).Get(ctx, "tempo-simplest-config", metav1.GetOptions{}); err != nil {
+		t.Errorf("expected the namespace resources to be kept: %v", err)
+	}
+}
+
+func TestCleanupLoadOnly(t *testing.T) {
+	const ns = "tempo-perf-small-abc"
+	k6Meta := func(name string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Name: name, Namespace: ns, Labels: map[string]string{"app": "k6-perf-test"}}
+	}
+	tempoMeta := func(name string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Name: name, Namespace: ns, Labels: map[string]string{"app.kubernetes.io/name": "tempo"}}
+	}
+
+	f := newOrphanTestFramework(
+		&batchv1.Job{ObjectMeta: k6Meta("k6-ingestion-small")},
+		&corev1.Pod{ObjectMeta: k6Meta("k6-ingestion-small-x7k2p")},
+		&corev1.ConfigMap{ObjectMeta: k6Meta("k6-scripts")},
+		&corev1.ServiceAccount{ObjectMeta: k6Meta("k6-query-sa")},
+		&corev1.Pod{ObjectMeta: tempoMeta("tempo-simplest-0")},
+		&corev1.ConfigMap{ObjectMeta: tempoMeta("tempo-simplest-config")},
+	)
+	f.namespace = ns
+
+	if err := f.CleanupLoadOnly(); err != nil {
+		t.Fatalf("CleanupLoadOnly() error = %v", err)
+	}
+
+	ctx := context.Background()
+	if jobs, _ := f.client.BatchV1().Jobs(ns).List(ctx, metav1.ListOptions{}); len(jobs.Items) != 0 {
+		t.Errorf("expected k6 jobs to be deleted, got %d", len(jobs.Items))
+	}
+	pods, _ := f.client.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{})
+	if len(pods.Items) != 1 || pods.Items[0].Name != "tempo-simplest-0" {
+		t.Errorf("expected only the Tempo pod to remain, got %v", pods.Items)
+	}
+	configMaps, _ := f.client.CoreV1().ConfigMaps(ns).List(ctx, metav1.ListOptions{})
+	if len(configMaps.Items) != 1 || configMaps.Items[0].Name != "tempo-simplest-config" {
+		t.Errorf("expected only the Tempo ConfigMap to remain, got %v", configMaps.Items)
+	}
+	if _, err := f.client.CoreV1().ServiceAccounts(ns).Get(ctx, "k6-query-sa", metav1.GetOptions{}); err != nil {
+		t.Errorf("expected the k6 ServiceAccount to be kept: %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/config"
+	"github.com/redhat/perf-tests-tempo/test/framework/metrics"
+
+	"k8s.io/client-go/rest"
+)
+
+// ClockSkew is how far the cluster clocks are ahead of the runner clock.
+// Negative values mean the cluster clock is behind.
+type ClockSkew struct {
+	// APIServer is the skew of the API server, read from the Date header of a
+	// request, so it is only accurate to about half a second
+	APIServer      time.Duration
+	APIServerError error
+	// Prometheus is the skew of Prometheus (or the Thanos Querier), which
+	// timestamps the collected samples
+	Prometheus      time.Duration
+	PrometheusError error
+	// Threshold is the skew above which the clocks are out of sync
+	Threshold time.Duration
+}
+
+// Offset returns the offset of the cluster clock used for collection windows: the
+// Prometheus skew when measured, otherwise the API server skew
+func (s *ClockSkew) Offset() time.Duration {
+	if s.PrometheusError == nil {
+		return s.Prometheus
+	}
+	if s.APIServerError == nil {
+		return s.APIServer
+	}
+	return 0
+}
+
+// Exceeded reports whether a measured skew is larger than the threshold
+func (s *ClockSkew) Exceeded() bool {
+	return (s.APIServerError == nil && absDuration(s.APIServer) > s.Threshold) ||
+		(s.PrometheusError == nil && absDuration(s.Prometheus) > s.Threshold)
+}
+
+// CheckClockSkew compares the runner clock with the API server and Prometheus clocks.
+// Afterwards Now returns cluster-side time, so test windows recorded with it match
+// the timestamps of the collected metrics. A skew above the configured
+// ClockSkewThreshold is logged as a warning; an error is only returned when
+// neither clock could be read.
+func (f *Framework) CheckClockSkew() (*ClockSkew, error) {
+	skew := &ClockSkew{Threshold: f.config.ClockSkewThreshold}
+	if skew.Threshold <= 0 {
+		skew.Threshold = config.DefaultClockSkewThreshold
+	}
+
+	skew.APIServer, skew.APIServerError = apiServerClockOffset(f.ctx, f.restConfig)
+	skew.Prometheus, skew.PrometheusError = metrics.PrometheusClockOffset(f)
+	if skew.APIServerError != nil && skew.PrometheusError != nil {
+		return skew, fmt.Errorf("failed to read the cluster clock: %w", errors.Join(skew.APIServerError, skew.PrometheusError))
+	}
+
+	f.clockOffset = skew.Offset()
+	if skew.Exceeded() {
+		f.logger.Warn("runner clock is out of sync with the cluster, using cluster time for collection windows",
+			"apiServerSkew", skew.APIServer, "prometheusSkew", skew.Prometheus, "threshold", skew.Threshold)
+	}
+	return skew, nil
+}
+
+// Now returns the current cluster-side time: the runner time corrected by the clock
+// skew measured with CheckClockSkew. Use it for the test windows metrics are
+// collected over.
+func (f *Framework) Now() time.Time {
+	return time.Now().Add(f.clockOffset)
+}
+
+// apiServerClockOffset returns how far the API server clock is ahead of the runner,
+// from the Date header of a /version request. The header has a resolution of one
+// second, so the server time is taken as the middle of that second.
+func apiServerClockOffset(ctx context.Context, cfg *rest.Config) (time.Duration, error) {
+	if cfg == nil {
+		return 0, fmt.Errorf("no REST config")
+	}
+	client, err := rest.HTTPClientFor(cfg)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create API server client: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(cfg.Host, "/")+"/version", nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	before := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query API server: %w", err)
+	}
+	after := time.Now()
+	resp.Body.Close()
+
+	date, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return 0, fmt.Errorf("API server returned no valid Date header: %w", err)
+	}
+	return metrics.ClockOffset(before, after, date.Add(500*time.Millisecond)), nil
+}
+
+// absDuration returns the absolute value of d
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
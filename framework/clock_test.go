@@ -0,0 +1,61 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/rest"
+)
+
+func TestAPIServerClockOffset(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+		_, _ = w.Write([]byte(`{"major":"1","minor":"32"}`))
+	}))
+	defer server.Close()
+
+	offset, err := apiServerClockOffset(context.Background(), &rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d := absDuration(offset - time.Hour); d > time.Second {
+		t.Errorf("expected an offset of about 1h, got %v", offset)
+	}
+}
+
+func TestClockSkew_OffsetAndExceeded(t *testing.T) {
+	unavailable := errors.New("unavailable")
+	tests := []struct {
+		name     string
+		skew     ClockSkew
+		offset   time.Duration
+		exceeded bool
+	}{
+		{"in sync", ClockSkew{APIServer: 300 * time.Millisecond, Prometheus: -200 * time.Millisecond}, -200 * time.Millisecond, false},
+		{"prometheus ahead", ClockSkew{APIServer: 5 * time.Second, Prometheus: 5 * time.Second}, 5 * time.Second, true},
+		{"runner ahead", ClockSkew{APIServer: -3 * time.Second, PrometheusError: unavailable}, -3 * time.Second, true},
+		{"unmeasured", ClockSkew{APIServer: time.Hour, APIServerError: unavailable, PrometheusError: unavailable}, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.skew.Threshold = 2 * time.Second
+			if got := tt.skew.Offset(); got != tt.offset {
+				t.Errorf("Offset() = %v, want %v", got, tt.offset)
+			}
+			if got := tt.skew.Exceeded(); got != tt.exceeded {
+				t.Errorf("Exceeded() = %v, want %v", got, tt.exceeded)
+			}
+		})
+	}
+}
+
+func TestNow_AppliesClockOffset(t *testing.T) {
+	f := &Framework{clockOffset: -time.Minute}
+	if d := time.Until(f.Now()); d > -59*time.Second || d < -61*time.Second {
+		t.Errorf("expected Now() about 1m behind the runner clock, got %v", d)
+	}
+}
@@ -0,0 +1,162 @@
+package framework
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/k6"
+	"github.com/redhat/perf-tests-tempo/test/framework/metrics"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// DefaultTraceSampleSize is the number of traces looked up by VerifyIngestionCompleteness
+const DefaultTraceSampleSize = 20
+
+// queryFrontendHTTPPort is the HTTP port of the Tempo query frontend
+const queryFrontendHTTPPort = 3200
+
+// completenessSettleTime is how long VerifyIngestionCompleteness waits after k6 exits
+// before querying Prometheus: one 30s ServiceMonitor scrape interval plus time for
+// the distributors to process the last batches, so the final spans are counted.
+const completenessSettleTime = 45 * time.Second
+
+// VerifyIngestionCompleteness checks that the data sent by an ingestion test was
+// actually stored by Tempo. It compares the spans k6 reported as sent with the
+// increase of tempo_distributor_spans_received_total since testStart, and looks up
+// a sample of trace IDs through the query frontend. The report is also stored in
+// result.Completeness.
+//
+// It waits completenessSettleTime before querying, as the last spans sent are only
+// visible in Prometheus after the next scrape.
+//
+// k6 does not export the IDs of the traces it sent, so the sample is taken from a
+// search over the test window; it detects traces that were indexed but can no longer
+// be retrieved. A failed trace lookup is recorded in TraceLookupError instead of
+// failing the verification.
+func (f *Framework) VerifyIngestionCompleteness(result *k6.Result, variant string, testStart time.Time) (*k6.IngestionCompleteness, error) {
+	if result == nil || result.Metrics == nil {
+		return nil, fmt.Errorf("no k6 metrics available to verify ingestion")
+	}
+	if result.Metrics.IngestionSpansTotal <= 0 {
+		return nil, fmt.Errorf("k6 did not report any sent spans")
+	}
+
+	f.logger.Info("waiting for final distributor metrics to be scraped", "duration", completenessSettleTime)
+	select {
+	case <-time.After(completenessSettleTime):
+	case <-f.ctx.Done():
+		return nil, fmt.Errorf("interrupted while waiting for metrics: %w", f.ctx.Err())
+	}
+
+	end := f.Now()
+	received, err := metrics.DistributorSpansReceived(f, testStart, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get received spans: %w", err)
+	}
+
+	completeness := &k6.IngestionCompleteness{
+		SentSpans:       result.Metrics.IngestionSpansTotal,
+		ReceivedSpans:   received,
+		SpanLossPercent: lossPercent(result.Metrics.IngestionSpansTotal, received),
+	}
+
+	service, err := queryFrontendService(variant)
+	if err == nil {
+		completeness.TracesSampled, completeness.TracesFound, err = f.sampleTraceLookups(service, testStart, end, DefaultTraceSampleSize)
+	}
+	if err != nil {
+		completeness.TraceLookupError = err
+	} else {
+		completeness.TraceLossPercent = lossPercent(float64(completeness.TracesSampled), float64(completeness.TracesFound))
+	}
+
+	result.Completeness = completeness
+	return completeness, nil
+}
+
+// lossPercent returns the percentage of expected items that are missing.
+// Received counts above the expected count (e.g. from counter extrapolation) are no loss.
+func lossPercent(expected, received float64) float64 {
+	if expected <= 0 || received >= expected {
+		return 0
+	}
+	return (expected - received) / expected * 100
+}
+
+// queryFrontendService returns the name of the Service exposing the query frontend
+func queryFrontendService(variant string) (string, error) {
+	switch variant {
+	case "monolithic":
+		return "tempo-" + k6.MonolithicCRName, nil
+	case "stack":
+		return fmt.Sprintf("tempo-%s-query-frontend", k6.StackCRName), nil
+	default:
+		return "", fmt.Errorf("invalid tempo variant: %s (must be 'monolithic' or 'stack')", variant)
+	}
+}
+
+// tempoSearchResponse holds the fields of a Tempo search response used for sampling
+type tempoSearchResponse struct {
+	Traces []struct {
+		TraceID string `json:"traceID"`
+	} `json:"traces"`
+}
+
+// sampleTraceLookups searches for up to limit traces ingested between start and end
+// and retrieves each of them by ID through the API server service proxy.
+// It returns the number of sampled and found traces.
+func (f *Framework) sampleTraceLookups(service string, start, end time.Time, limit int) (sampled, found int, err error) {
+	body, err := f.queryFrontendGet(service, "api/search", map[string]string{
+		"q":     "{}",
+		"limit": strconv.Itoa(limit),
+		"start": strconv.FormatInt(start.Unix(), 10),
+		"end":   strconv.FormatInt(end.Unix(), 10),
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to search traces: %w", err)
+	}
+
+	var search tempoSearchResponse
+	if err := json.Unmarshal(body, &search); err != nil {
+		return 0, 0, fmt.Errorf("failed to parse search response: %w", err)
+	}
+	if len(search.Traces) == 0 {
+		return 0, 0, fmt.Errorf("search returned no traces between %s and %s", start.Format(time.RFC3339), end.Format(time.RFC3339))
+	}
+
+	for _, trace := range search.Traces {
+		sampled++
+		_, err := f.queryFrontendGet(service, "api/traces/"+trace.TraceID, nil)
+		if err == nil {
+			found++
+			continue
+		}
+		if !apierrors.IsNotFound(err) {
+			return sampled, found, fmt.Errorf("failed to look up trace %s: %w", trace.TraceID, err)
+		}
+		f.logger.Debug("sampled trace not found", "traceID", trace.TraceID)
+	}
+
+	return sampled, found, nil
+}
+
+// queryFrontendGet sends a GET request for the tenant used by the tests to the query frontend
+func (f *Framework) queryFrontendGet(service, path string, params map[string]string) ([]byte, error) {
+	req := f.client.CoreV1().RESTClient().Get().
+		Namespace(f.namespace).
+		Resource("services").
+		Name(fmt.Sprintf("http:%s:%d", service, queryFrontendHTTPPort)).
+		SubResource("proxy").
+		Suffix(path).
+		SetHeader("X-Scope-OrgID", k6.DefaultTenant).
+		SetHeader("Accept", "application/json").
+		Timeout(30 * time.Second)
+	for k, v := range params {
+		req = req.Param(k, v)
+	}
+
+	return req.DoRaw(f.ctx)
+}
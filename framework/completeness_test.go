@@ -0,0 +1,74 @@
+package framework
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/k6"
+)
+
+func TestLossPercent(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected float64
+		received float64
+		want     float64
+	}{
+		{"no loss", 1000, 1000, 0},
+		{"partial loss", 1000, 950, 5},
+		{"total loss", 1000, 0, 100},
+		{"extrapolated above expected", 1000, 1010, 0},
+		{"nothing expected", 0, 10, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lossPercent(tt.expected, tt.received); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestQueryFrontendService(t *testing.T) {
+	if got, _ := queryFrontendService("monolithic"); got != "tempo-simplest" {
+		t.Errorf("unexpected monolithic service %q", got)
+	}
+	if got, _ := queryFrontendService("stack"); got != "tempo-tempostack-query-frontend" {
+		t.Errorf("unexpected stack service %q", got)
+	}
+	if _, err := queryFrontendService("unknown"); err == nil {
+		t.Error("expected error for unknown variant")
+	}
+}
+
+func TestVerifyIngestionCompleteness_RequiresSentSpans(t *testing.T) {
+	f := newOrphanTestFramework()
+
+	if _, err := f.VerifyIngestionCompleteness(&k6.Result{}, "monolithic", time.Now()); err == nil {
+		t.Error("expected error without k6 metrics")
+	}
+	result := &k6.Result{Metrics: &k6.K6Metrics{IngestionTracesTotal: 10}}
+	if _, err := f.VerifyIngestionCompleteness(result, "monolithic", time.Now()); err == nil {
+		t.Error("expected error without sent spans")
+	}
+	if result.Completeness != nil {
+		t.Error("expected no completeness report on error")
+	}
+}
+
+func TestVerifyIngestionCompleteness_CancelledWhileSettling(t *testing.T) {
+	f := newOrphanTestFramework()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	f.ctx = ctx
+
+	result := &k6.Result{Metrics: &k6.K6Metrics{IngestionSpansTotal: 1000}}
+	if _, err := f.VerifyIngestionCompleteness(result, "monolithic", time.Now()); err == nil {
+		t.Error("expected error when interrupted before querying")
+	}
+	if result.Completeness != nil {
+		t.Error("expected no completeness report when interrupted")
+	}
+}
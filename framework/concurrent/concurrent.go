@@ -3,6 +3,7 @@ package concurrent
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sync"
 )
 
@@ -21,7 +22,7 @@ func ForEach[T any](items []T, fn func(T) error) error {
 		wg.Add(1)
 		go func(item T) {
 			defer wg.Done()
-			if err := fn(item); err != nil {
+			if err := call(func() error { return fn(item) }); err != nil {
 				errCh <- err
 			}
 		}(item)
@@ -66,7 +67,7 @@ func ForEachWithContext[T any](ctx context.Context, items []T, fn func(context.C
 				return
 			default:
 			}
-			if err := fn(ctx, item); err != nil {
+			if err := call(func() error { return fn(ctx, item) }); err != nil {
 				errCh <- err
 			}
 		}(item)
@@ -124,7 +125,7 @@ func ForEachWithLimit[T any](ctx context.Context, items []T, limit int, fn func(
 			default:
 			}
 
-			if err := fn(ctx, item); err != nil {
+			if err := call(func() error { return fn(ctx, item) }); err != nil {
 				errCh <- err
 			}
 		}(item)
@@ -146,6 +147,73 @@ func ForEachWithLimit[T any](ctx context.Context, items []T, limit int, fn func(
 	return nil
 }
 
+// ForEachIndexed executes fn for each item in items concurrently.
+// Returns the errors aligned with items: errs[i] is the error of items[i], or nil.
+// Pass the result to errors.Join for a single error, or to ItemErrors to pair
+// failures with their items.
+func ForEachIndexed[T any](items []T, fn func(int, T) error) []error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	errs := make([]error, len(items))
+	var wg sync.WaitGroup
+
+	for i, item := range items {
+		wg.Add(1)
+		go func(i int, item T) {
+			defer wg.Done()
+			errs[i] = call(func() error { return fn(i, item) })
+		}(i, item)
+	}
+
+	wg.Wait()
+
+	return errs
+}
+
+// ForEachWithResults applies fn to each item concurrently.
+// Returns the results and errors aligned with items.
+func ForEachWithResults[T, R any](items []T, fn func(T) (R, error)) ([]R, []error) {
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	results := make([]R, len(items))
+	errs := ForEachIndexed(items, func(i int, item T) error {
+		var err error
+		results[i], err = fn(item)
+		return err
+	})
+
+	return results, errs
+}
+
+// ItemError is the error of a single item
+type ItemError[T any] struct {
+	Item T
+	Err  error
+}
+
+func (e ItemError[T]) Error() string {
+	return fmt.Sprintf("%v: %v", e.Item, e.Err)
+}
+
+func (e ItemError[T]) Unwrap() error {
+	return e.Err
+}
+
+// ItemErrors pairs the non-nil errors of errs with the items at the same index
+func ItemErrors[T any](items []T, errs []error) []ItemError[T] {
+	var failed []ItemError[T]
+	for i, err := range errs {
+		if err != nil && i < len(items) {
+			failed = append(failed, ItemError[T]{Item: items[i], Err: err})
+		}
+	}
+	return failed
+}
+
 // Map applies fn to each item concurrently and returns the results.
 // Order of results matches order of items.
 func Map[T, R any](items []T, fn func(T) (R, error)) ([]R, error) {
@@ -161,7 +229,10 @@ func Map[T, R any](items []T, fn func(T) (R, error)) ([]R, error) {
 		wg.Add(1)
 		go func(i int, item T) {
 			defer wg.Done()
-			results[i], errs[i] = fn(item)
+			errs[i] = call(func() (err error) {
+				results[i], err = fn(item)
+				return err
+			})
 		}(i, item)
 	}
 
@@ -221,7 +292,10 @@ func MapWithLimit[T, R any](ctx context.Context, items []T, limit int, fn func(c
 			default:
 			}
 
-			results[i], errs[i] = fn(ctx, item)
+			errs[i] = call(func() (err error) {
+				results[i], err = fn(ctx, item)
+				return err
+			})
 		}(i, item)
 	}
 
@@ -243,7 +317,8 @@ func MapWithLimit[T, R any](ctx context.Context, items []T, limit int, fn func(c
 }
 
 // Filter returns items for which fn returns true, processing concurrently.
-// Order of results matches order of input items.
+// Order of results matches order of input items. Filter has no error to return,
+// so a panic of fn is raised again in the calling goroutine as a *PanicError.
 func Filter[T any](items []T, fn func(T) bool) []T {
 	if len(items) == 0 {
 		return nil
@@ -251,18 +326,28 @@ func Filter[T any](items []T, fn func(T) bool) []T {
 
 	// Store keep decisions for each index
 	keep := make([]bool, len(items))
+	errs := make([]error, len(items))
 	var wg sync.WaitGroup
 
 	for i, item := range items {
 		wg.Add(1)
 		go func(i int, item T) {
 			defer wg.Done()
-			keep[i] = fn(item)
+			errs[i] = call(func() error {
+				keep[i] = fn(item)
+				return nil
+			})
 		}(i, item)
 	}
 
 	wg.Wait()
 
+	for _, err := range errs {
+		if err != nil {
+			panic(err)
+		}
+	}
+
 	// Collect results in original order
 	results := make([]T, 0, len(items))
 	for i, item := range items {
@@ -293,7 +378,11 @@ func (c *Collector[T]) Go(fn func() (T, error)) {
 	c.wg.Add(1)
 	go func() {
 		defer c.wg.Done()
-		result, err := fn()
+		var result T
+		err := call(func() (err error) {
+			result, err = fn()
+			return err
+		})
 		c.mu.Lock()
 		defer c.mu.Unlock()
 		if err != nil {
@@ -283,3 +283,71 @@ func TestCollector_WithErrors(t *testing.T) {
 		t.Errorf("expected 1 error, got %d", len(errs))
 	}
 }
+
+func TestForEachIndexed_AlignedErrors(t *testing.T) {
+	items := []string{"a", "b", "c", "d"}
+	testErr := errors.New("test error")
+
+	errs := ForEachIndexed(items, func(i int, item string) error {
+		if item != items[i] {
+			t.Errorf("expected item %q at index %d, got %q", items[i], i, item)
+		}
+		if i%2 == 1 {
+			return testErr
+		}
+		return nil
+	})
+
+	if len(errs) != len(items) {
+		t.Fatalf("expected %d errors, got %d", len(items), len(errs))
+	}
+	for i, err := range errs {
+		if (i%2 == 1) != (err != nil) {
+			t.Errorf("unexpected errs[%d] = %v", i, err)
+		}
+	}
+
+	failed := ItemErrors(items, errs)
+	if len(failed) != 2 || failed[0].Item != "b" || failed[1].Item != "d" {
+		t.Fatalf("unexpected item errors %v", failed)
+	}
+	if !errors.Is(failed[0], testErr) {
+		t.Error("expected ItemError to unwrap to the item error")
+	}
+	if failed[0].Error() != "b: test error" {
+		t.Errorf("unexpected error message %q", failed[0].Error())
+	}
+}
+
+func TestForEachIndexed_EmptySlice(t *testing.T) {
+	errs := ForEachIndexed([]int{}, func(i, item int) error {
+		t.Error("should not be called")
+		return nil
+	})
+
+	if errs != nil {
+		t.Errorf("expected no errors, got %v", errs)
+	}
+}
+
+func TestForEachWithResults(t *testing.T) {
+	items := []int{1, 2, 3}
+	testErr := errors.New("test error")
+
+	results, errs := ForEachWithResults(items, func(item int) (int, error) {
+		if item == 2 {
+			return 0, testErr
+		}
+		return item * 10, nil
+	})
+
+	if results[0] != 10 || results[2] != 30 {
+		t.Errorf("expected partial results, got %v", results)
+	}
+	if errs[0] != nil || errs[1] != testErr || errs[2] != nil {
+		t.Errorf("expected only errs[1] to be set, got %v", errs)
+	}
+	if errors.Join(errs...) == nil {
+		t.Error("expected joined error")
+	}
+}
@@ -17,6 +17,19 @@
 //	    return processItem(ctx, item)
 //	})
 //
+// # ForEachIndexed
+//
+// Get the error of each item, aligned with the input:
+//
+//	errs := concurrent.ForEachIndexed(items, func(i int, item string) error {
+//	    return processItem(item)
+//	})
+//	for _, failed := range concurrent.ItemErrors(items, errs) {
+//	    log.Printf("%s failed: %v", failed.Item, failed.Err)
+//	}
+//
+// ForEachWithResults also returns the results aligned with the input.
+//
 // # Map
 //
 // Transform items concurrently while preserving order:
@@ -52,7 +65,22 @@
 //
 // # Error Handling
 //
-// All functions aggregate errors using errors.Join and continue processing
-// all items even if some fail. This allows you to see all failures rather
-// than just the first one.
+// All functions continue processing all items even if some fail. This allows
+// you to see all failures rather than just the first one. Most aggregate errors
+// using errors.Join; ForEachIndexed and ForEachWithResults return them per item.
+//
+// # Panics
+//
+// A panic in a goroutine would crash the whole process, so a panic of fn is
+// recovered and returned as a *PanicError with the panic value and the stack
+// trace of the goroutine, alongside the errors of the other items:
+//
+//	var panicErr *concurrent.PanicError
+//	if errors.As(err, &panicErr) {
+//	    log.Printf("worker panicked: %v\n%s", panicErr.Value, panicErr.Stack)
+//	}
+//
+// Filter has no error to return and raises the *PanicError again in the
+// calling goroutine. Callers that prefer a crash, e.g. to debug with a core
+// dump, call concurrent.CrashOnPanic(true).
 package concurrent
@@ -0,0 +1,49 @@
+package concurrent
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync/atomic"
+)
+
+// PanicError is the error of a function that panicked in a goroutine of this package
+type PanicError struct {
+	// Value is the value passed to panic
+	Value any
+	// Stack is the stack trace of the panicking goroutine
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v\n\n%s", e.Value, e.Stack)
+}
+
+// Unwrap returns the panic value if it is an error
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// crashOnPanic disables the recovery of panics, see CrashOnPanic
+var crashOnPanic atomic.Bool
+
+// CrashOnPanic sets whether a panic of a function run by this package crashes
+// the process, as in a plain goroutine, instead of being returned as a
+// *PanicError. Crashing is easier to debug with a core dump or GOTRACEBACK.
+func CrashOnPanic(crash bool) {
+	crashOnPanic.Store(crash)
+}
+
+// call runs fn, returning a panic of fn as a *PanicError unless CrashOnPanic is set
+func call(fn func() error) (err error) {
+	if !crashOnPanic.Load() {
+		defer func() {
+			if r := recover(); r != nil {
+				err = &PanicError{Value: r, Stack: debug.Stack()}
+			}
+		}()
+	}
+	return fn()
+}
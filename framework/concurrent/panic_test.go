@@ -0,0 +1,114 @@
+package concurrent
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestForEach_RecoversPanic(t *testing.T) {
+	items := []int{1, 2, 3}
+
+	err := ForEach(items, func(item int) error {
+		if item == 2 {
+			panic("worker failed")
+		}
+		return nil
+	})
+
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("expected a *PanicError, got %v", err)
+	}
+	if panicErr.Value != "worker failed" {
+		t.Errorf("expected panic value 'worker failed', got %v", panicErr.Value)
+	}
+	if !strings.Contains(err.Error(), "panic: worker failed") || !strings.Contains(err.Error(), "panic_test.go") {
+		t.Errorf("expected the panic value and its stack trace in the error, got %q", err.Error())
+	}
+}
+
+func TestMapWithLimit_RecoversPanic(t *testing.T) {
+	items := []int{1, 2, 3}
+
+	results, err := MapWithLimit(context.Background(), items, 2, func(ctx context.Context, item int) (int, error) {
+		if item == 3 {
+			var m map[string]int
+			m["boom"] = item
+		}
+		return item * 2, nil
+	})
+
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("expected a *PanicError, got %v", err)
+	}
+	if results[0] != 2 || results[1] != 4 {
+		t.Errorf("expected the results of the other items, got %v", results)
+	}
+}
+
+func TestForEachIndexed_RecoversPanic(t *testing.T) {
+	errs := ForEachIndexed([]string{"a", "b"}, func(i int, item string) error {
+		if item == "b" {
+			panic(io.ErrUnexpectedEOF)
+		}
+		return nil
+	})
+
+	if errs[0] != nil {
+		t.Errorf("expected no error for a, got %v", errs[0])
+	}
+	if !errors.Is(errs[1], io.ErrUnexpectedEOF) {
+		t.Errorf("expected the panic error to unwrap to its value, got %v", errs[1])
+	}
+}
+
+func TestCollector_RecoversPanic(t *testing.T) {
+	collector := NewCollector[int]()
+	collector.Go(func() (int, error) { return 1, nil })
+	collector.Go(func() (int, error) { panic("collector failed") })
+
+	results, err := collector.Wait()
+
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("expected a *PanicError, got %v", err)
+	}
+	if len(results) != 1 {
+		t.Errorf("expected 1 successful result, got %d", len(results))
+	}
+}
+
+func TestFilter_RaisesPanicInCaller(t *testing.T) {
+	defer func() {
+		r := recover()
+		if _, ok := r.(*PanicError); !ok {
+			t.Errorf("expected a *PanicError panic in the caller, got %v", r)
+		}
+	}()
+
+	Filter([]int{1, 2}, func(item int) bool {
+		if item == 2 {
+			panic("filter failed")
+		}
+		return true
+	})
+	t.Error("expected Filter to panic")
+}
+
+func TestCrashOnPanic(t *testing.T) {
+	CrashOnPanic(true)
+	defer CrashOnPanic(false)
+
+	defer func() {
+		if r := recover(); r != "not recovered" {
+			t.Errorf("expected the panic to propagate, got %v", r)
+		}
+	}()
+
+	_ = call(func() error { panic("not recovered") })
+	t.Error("expected call to panic")
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -17,9 +18,13 @@ const (
 	// DefaultPodReadyTimeout is the default timeout for waiting for pods to be ready
 	DefaultPodReadyTimeout = 120 * time.Second
 
-	// DefaultPodReadyPollInterval is the default interval for polling pod readiness
+	// DefaultPodReadyPollInterval is the default interval for polling pod and resource readiness
 	DefaultPodReadyPollInterval = 5 * time.Second
 
+	// DefaultCRReadyTimeout is the default timeout for waiting for a custom resource
+	// field to reach its expected value
+	DefaultCRReadyTimeout = 300 * time.Second
+
 	// DefaultNamespaceTimeout is the default timeout for namespace operations
 	DefaultNamespaceTimeout = 120 * time.Second
 
@@ -36,21 +41,60 @@ const (
 	DefaultHTTPTimeout = 60 * time.Second
 
 	// DefaultMetricsQueryStep is the default step for Prometheus range queries
-	DefaultMetricsQueryStep = 15 * time.Second
+	DefaultMetricsQueryStep = 60 * time.Second
 
 	// DefaultMaxConcurrentQueries is the default max concurrent Prometheus queries
 	DefaultMaxConcurrentQueries = 5
+
+	// DefaultMetricsQueryTimeout is the default timeout of each Prometheus query of
+	// a metrics collection
+	DefaultMetricsQueryTimeout = 60 * time.Second
+
+	// DefaultClockSkewThreshold is the default clock difference between the runner
+	// and the cluster above which a warning is printed
+	DefaultClockSkewThreshold = 2 * time.Second
 )
 
 // Environment variable names for configuration overrides
 const (
 	EnvCRDeletionTimeout  = "TEMPO_PERF_CR_DELETION_TIMEOUT"
 	EnvPodReadyTimeout    = "TEMPO_PERF_POD_READY_TIMEOUT"
+	EnvPodReadyInterval   = "TEMPO_PERF_POD_READY_POLL_INTERVAL"
+	EnvCRReadyTimeout     = "TEMPO_PERF_CR_READY_TIMEOUT"
 	EnvJobTimeout         = "TEMPO_PERF_JOB_TIMEOUT"
 	EnvHTTPTimeout        = "TEMPO_PERF_HTTP_TIMEOUT"
 	EnvMaxConcurrentQuery = "TEMPO_PERF_MAX_CONCURRENT_QUERIES"
+	EnvMetricsQueryStep   = "TEMPO_PERF_METRICS_STEP"
+	EnvMetricsRateWindow  = "TEMPO_PERF_METRICS_RATE_WINDOW"
+	EnvMetricsTimeout     = "TEMPO_PERF_METRICS_QUERY_TIMEOUT"
+	EnvOperatorNamespaces = "TEMPO_PERF_OPERATOR_NAMESPACES"
+	EnvClockSkewThreshold = "TEMPO_PERF_CLOCK_SKEW_THRESHOLD"
+
+	// Cluster safety guardrails
+	EnvClusterAllowlist     = "TEMPO_PERF_CLUSTER_ALLOWLIST"
+	EnvClusterDenylist      = "TEMPO_PERF_CLUSTER_DENYLIST"
+	EnvRequiredClusterLabel = "TEMPO_PERF_REQUIRED_CLUSTER_LABEL"
+	EnvAllowUnsafeCluster   = "TEMPO_PERF_ALLOW_UNSAFE_CLUSTER"
+
+	// Prometheus access for metrics collection
+	EnvPrometheusMode         = "TEMPO_PERF_PROMETHEUS_MODE"
+	EnvPrometheusURL          = "TEMPO_PERF_PROMETHEUS_URL"
+	EnvPrometheusToken        = "TEMPO_PERF_PROMETHEUS_TOKEN"
+	EnvPrometheusNamespace    = "TEMPO_PERF_PROMETHEUS_NAMESPACE"
+	EnvPrometheusService      = "TEMPO_PERF_PROMETHEUS_SERVICE"
+	EnvPrometheusServiceProxy = "TEMPO_PERF_PROMETHEUS_SERVICE_PROXY"
+	EnvPrometheusTenant       = "TEMPO_PERF_PROMETHEUS_TENANT"
 )
 
+// DefaultOperatorNamespaces are the namespaces the Tempo and OpenTelemetry operators
+// are installed in by OLM on OpenShift and by the upstream manifests
+var DefaultOperatorNamespaces = []string{
+	"openshift-tempo-operator",
+	"openshift-opentelemetry-operator",
+	"tempo-operator-system",
+	"opentelemetry-operator-system",
+}
+
 // Config holds framework configuration with optional overrides
 type Config struct {
 	// Timeouts
@@ -58,6 +102,7 @@ type Config struct {
 	CRDeletionPollInterval time.Duration
 	PodReadyTimeout        time.Duration
 	PodReadyPollInterval   time.Duration
+	CRReadyTimeout         time.Duration
 	NamespaceTimeout       time.Duration
 	NamespacePollInterval  time.Duration
 	JobTimeout             time.Duration
@@ -67,6 +112,48 @@ type Config struct {
 	// Metrics
 	MetricsQueryStep     time.Duration
 	MaxConcurrentQueries int
+	// MetricsRateWindow replaces the range of the range selectors in the metric
+	// queries (e.g. [1m]). Zero keeps the ranges of the queries.
+	MetricsRateWindow time.Duration
+	// MetricsQueryTimeout bounds each query of a metrics collection; a query that
+	// times out is recorded as failed and the others are still collected
+	MetricsQueryTimeout time.Duration
+	// ClockSkewThreshold is the clock difference between the runner and the API
+	// server or Prometheus above which CheckClockSkew warns
+	ClockSkewThreshold time.Duration
+
+	// OperatorNamespaces enables collection of operator pod resource usage
+	// from these namespaces. Empty disables operator metrics.
+	OperatorNamespaces []string
+
+	// Cluster safety guardrails. Clusters are identified by their OpenShift
+	// infrastructure name or API server host.
+	// ClusterAllowlist restricts runs to the listed clusters when not empty.
+	ClusterAllowlist []string
+	// ClusterDenylist refuses runs against the listed clusters.
+	ClusterDenylist []string
+	// RequiredClusterLabel ("key=value") must be set on the cluster object.
+	RequiredClusterLabel string
+	// AllowUnsafeCluster overrides all guardrails.
+	AllowUnsafeCluster bool
+
+	// Prometheus access. Empty values are auto-discovered.
+	// PrometheusMode is "openshift" (default), "kubernetes" or "remote".
+	PrometheusMode string
+	// PrometheusURL overrides the discovered Prometheus/Thanos URL.
+	PrometheusURL string
+	// PrometheusToken overrides the generated bearer token.
+	PrometheusToken string
+	// PrometheusNamespace overrides the monitoring namespace.
+	PrometheusNamespace string
+	// PrometheusService overrides the Prometheus Service name in kubernetes mode.
+	PrometheusService string
+	// PrometheusServiceProxy forces (true) or disables (false) the API server
+	// service proxy in kubernetes mode. Nil uses it when running outside the cluster.
+	PrometheusServiceProxy *bool
+	// PrometheusTenant is sent as the X-Scope-OrgID header to multi-tenant
+	// backends such as Cortex and Mimir.
+	PrometheusTenant string
 }
 
 // Default returns a Config with all default values
@@ -76,6 +163,7 @@ func Default() *Config {
 		CRDeletionPollInterval: DefaultCRDeletionPollInterval,
 		PodReadyTimeout:        DefaultPodReadyTimeout,
 		PodReadyPollInterval:   DefaultPodReadyPollInterval,
+		CRReadyTimeout:         DefaultCRReadyTimeout,
 		NamespaceTimeout:       DefaultNamespaceTimeout,
 		NamespacePollInterval:  DefaultNamespacePollInterval,
 		JobTimeout:             DefaultJobTimeout,
@@ -83,6 +171,8 @@ func Default() *Config {
 		HTTPTimeout:            DefaultHTTPTimeout,
 		MetricsQueryStep:       DefaultMetricsQueryStep,
 		MaxConcurrentQueries:   DefaultMaxConcurrentQueries,
+		MetricsQueryTimeout:    DefaultMetricsQueryTimeout,
+		ClockSkewThreshold:     DefaultClockSkewThreshold,
 	}
 }
 
@@ -102,6 +192,18 @@ func FromEnv() *Config {
 		}
 	}
 
+	if v := os.Getenv(EnvPodReadyInterval); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			cfg.PodReadyPollInterval = d
+		}
+	}
+
+	if v := os.Getenv(EnvCRReadyTimeout); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			cfg.CRReadyTimeout = d
+		}
+	}
+
 	if v := os.Getenv(EnvJobTimeout); v != "" {
 		if d, err := time.ParseDuration(v); err == nil {
 			cfg.JobTimeout = d
@@ -120,9 +222,77 @@ func FromEnv() *Config {
 		}
 	}
 
+	if v := os.Getenv(EnvMetricsQueryStep); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			cfg.MetricsQueryStep = d
+		}
+	}
+
+	if v := os.Getenv(EnvMetricsRateWindow); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			cfg.MetricsRateWindow = d
+		}
+	}
+
+	if v := os.Getenv(EnvMetricsTimeout); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			cfg.MetricsQueryTimeout = d
+		}
+	}
+
+	if v := os.Getenv(EnvClockSkewThreshold); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			cfg.ClockSkewThreshold = d
+		}
+	}
+
+	if v := os.Getenv(EnvOperatorNamespaces); v != "" {
+		cfg.OperatorNamespaces = splitList(v)
+	}
+
+	if v := os.Getenv(EnvClusterAllowlist); v != "" {
+		cfg.ClusterAllowlist = splitList(v)
+	}
+
+	if v := os.Getenv(EnvClusterDenylist); v != "" {
+		cfg.ClusterDenylist = splitList(v)
+	}
+
+	cfg.RequiredClusterLabel = strings.TrimSpace(os.Getenv(EnvRequiredClusterLabel))
+
+	if v := os.Getenv(EnvAllowUnsafeCluster); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			cfg.AllowUnsafeCluster = b
+		}
+	}
+
+	cfg.PrometheusMode = strings.ToLower(strings.TrimSpace(os.Getenv(EnvPrometheusMode)))
+	cfg.PrometheusURL = os.Getenv(EnvPrometheusURL)
+	cfg.PrometheusToken = os.Getenv(EnvPrometheusToken)
+	cfg.PrometheusNamespace = os.Getenv(EnvPrometheusNamespace)
+	cfg.PrometheusService = os.Getenv(EnvPrometheusService)
+	cfg.PrometheusTenant = os.Getenv(EnvPrometheusTenant)
+
+	if v := os.Getenv(EnvPrometheusServiceProxy); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			cfg.PrometheusServiceProxy = &b
+		}
+	}
+
 	return cfg
 }
 
+// splitList parses a comma-separated list, dropping empty entries
+func splitList(v string) []string {
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 // WithCRDeletionTimeout returns a copy with updated CR deletion timeout
 func (c *Config) WithCRDeletionTimeout(d time.Duration) *Config {
 	cp := *c
@@ -137,6 +307,13 @@ func (c *Config) WithPodReadyTimeout(d time.Duration) *Config {
 	return &cp
 }
 
+// WithCRReadyTimeout returns a copy with updated custom resource ready timeout
+func (c *Config) WithCRReadyTimeout(d time.Duration) *Config {
+	cp := *c
+	cp.CRReadyTimeout = d
+	return &cp
+}
+
 // WithJobTimeout returns a copy with updated job timeout
 func (c *Config) WithJobTimeout(d time.Duration) *Config {
 	cp := *c
@@ -151,9 +328,38 @@ func (c *Config) WithHTTPTimeout(d time.Duration) *Config {
 	return &cp
 }
 
+// WithOperatorNamespaces returns a copy with updated operator namespaces
+func (c *Config) WithOperatorNamespaces(namespaces []string) *Config {
+	cp := *c
+	cp.OperatorNamespaces = namespaces
+	return &cp
+}
+
+// WithAllowUnsafeCluster returns a copy with the cluster safety override set
+func (c *Config) WithAllowUnsafeCluster(allow bool) *Config {
+	cp := *c
+	cp.AllowUnsafeCluster = allow
+	return &cp
+}
+
 // WithMaxConcurrentQueries returns a copy with updated max concurrent queries
 func (c *Config) WithMaxConcurrentQueries(n int) *Config {
 	cp := *c
 	cp.MaxConcurrentQueries = n
 	return &cp
 }
+
+// WithMetricsResolution returns a copy with updated range query step and rate window
+func (c *Config) WithMetricsResolution(step, rateWindow time.Duration) *Config {
+	cp := *c
+	cp.MetricsQueryStep = step
+	cp.MetricsRateWindow = rateWindow
+	return &cp
+}
+
+// WithMetricsQueryTimeout returns a copy with updated metrics query timeout
+func (c *Config) WithMetricsQueryTimeout(timeout time.Duration) *Config {
+	cp := *c
+	cp.MetricsQueryTimeout = timeout
+	return &cp
+}
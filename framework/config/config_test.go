@@ -24,6 +24,9 @@ func TestDefault(t *testing.T) {
 	if cfg.MaxConcurrentQueries != DefaultMaxConcurrentQueries {
 		t.Errorf("expected MaxConcurrentQueries %d, got %d", DefaultMaxConcurrentQueries, cfg.MaxConcurrentQueries)
 	}
+	if cfg.MetricsQueryTimeout != DefaultMetricsQueryTimeout {
+		t.Errorf("expected MetricsQueryTimeout %v, got %v", DefaultMetricsQueryTimeout, cfg.MetricsQueryTimeout)
+	}
 }
 
 func TestFromEnv_Defaults(t *testing.T) {
@@ -45,15 +48,43 @@ func TestFromEnv_CustomValues(t *testing.T) {
 	// Set custom env vars
 	os.Setenv(EnvCRDeletionTimeout, "5m")
 	os.Setenv(EnvPodReadyTimeout, "3m")
+	os.Setenv(EnvPodReadyInterval, "10s")
+	os.Setenv(EnvCRReadyTimeout, "8m")
 	os.Setenv(EnvJobTimeout, "1h")
 	os.Setenv(EnvHTTPTimeout, "2m")
 	os.Setenv(EnvMaxConcurrentQuery, "10")
+	os.Setenv(EnvMetricsQueryStep, "30s")
+	os.Setenv(EnvMetricsRateWindow, "2m")
+	os.Setenv(EnvMetricsTimeout, "3m")
+	os.Setenv(EnvClockSkewThreshold, "5s")
+	os.Setenv(EnvOperatorNamespaces, "tempo-operator-system, opentelemetry-operator-system")
+	os.Setenv(EnvClusterDenylist, "prod-east,,api.prod.example.com")
+	os.Setenv(EnvRequiredClusterLabel, " tempo-perf-test.io/allowed=true ")
+	os.Setenv(EnvAllowUnsafeCluster, "true")
+	os.Setenv(EnvPrometheusMode, "Kubernetes")
+	os.Setenv(EnvPrometheusService, "prometheus-operated")
+	os.Setenv(EnvPrometheusServiceProxy, "false")
+	os.Setenv(EnvPrometheusTenant, "perf")
 	defer func() {
 		os.Unsetenv(EnvCRDeletionTimeout)
 		os.Unsetenv(EnvPodReadyTimeout)
+		os.Unsetenv(EnvPodReadyInterval)
+		os.Unsetenv(EnvCRReadyTimeout)
 		os.Unsetenv(EnvJobTimeout)
 		os.Unsetenv(EnvHTTPTimeout)
 		os.Unsetenv(EnvMaxConcurrentQuery)
+		os.Unsetenv(EnvMetricsQueryStep)
+		os.Unsetenv(EnvMetricsRateWindow)
+		os.Unsetenv(EnvMetricsTimeout)
+		os.Unsetenv(EnvClockSkewThreshold)
+		os.Unsetenv(EnvOperatorNamespaces)
+		os.Unsetenv(EnvClusterDenylist)
+		os.Unsetenv(EnvRequiredClusterLabel)
+		os.Unsetenv(EnvAllowUnsafeCluster)
+		os.Unsetenv(EnvPrometheusMode)
+		os.Unsetenv(EnvPrometheusService)
+		os.Unsetenv(EnvPrometheusServiceProxy)
+		os.Unsetenv(EnvPrometheusTenant)
 	}()
 
 	cfg := FromEnv()
@@ -70,9 +101,45 @@ func TestFromEnv_CustomValues(t *testing.T) {
 	if cfg.HTTPTimeout != 2*time.Minute {
 		t.Errorf("expected HTTPTimeout 2m, got %v", cfg.HTTPTimeout)
 	}
+	if cfg.PodReadyPollInterval != 10*time.Second || cfg.CRReadyTimeout != 8*time.Minute {
+		t.Errorf("expected readiness polling 10s/8m, got %v/%v", cfg.PodReadyPollInterval, cfg.CRReadyTimeout)
+	}
+	if cfg.ClockSkewThreshold != 5*time.Second {
+		t.Errorf("expected ClockSkewThreshold 5s, got %v", cfg.ClockSkewThreshold)
+	}
 	if cfg.MaxConcurrentQueries != 10 {
 		t.Errorf("expected MaxConcurrentQueries 10, got %d", cfg.MaxConcurrentQueries)
 	}
+	if cfg.MetricsQueryStep != 30*time.Second || cfg.MetricsRateWindow != 2*time.Minute {
+		t.Errorf("expected metrics resolution 30s/2m, got %v/%v", cfg.MetricsQueryStep, cfg.MetricsRateWindow)
+	}
+	if cfg.MetricsQueryTimeout != 3*time.Minute {
+		t.Errorf("expected MetricsQueryTimeout 3m, got %v", cfg.MetricsQueryTimeout)
+	}
+	if len(cfg.OperatorNamespaces) != 2 || cfg.OperatorNamespaces[1] != "opentelemetry-operator-system" {
+		t.Errorf("expected 2 trimmed OperatorNamespaces, got %v", cfg.OperatorNamespaces)
+	}
+	if len(cfg.ClusterDenylist) != 2 || cfg.ClusterDenylist[1] != "api.prod.example.com" {
+		t.Errorf("expected 2 ClusterDenylist entries, got %v", cfg.ClusterDenylist)
+	}
+	if cfg.RequiredClusterLabel != "tempo-perf-test.io/allowed=true" {
+		t.Errorf("expected trimmed RequiredClusterLabel, got %q", cfg.RequiredClusterLabel)
+	}
+	if !cfg.AllowUnsafeCluster {
+		t.Error("expected AllowUnsafeCluster to be true")
+	}
+	if cfg.PrometheusMode != "kubernetes" {
+		t.Errorf("expected lowercased PrometheusMode, got %q", cfg.PrometheusMode)
+	}
+	if cfg.PrometheusService != "prometheus-operated" {
+		t.Errorf("expected PrometheusService override, got %q", cfg.PrometheusService)
+	}
+	if cfg.PrometheusServiceProxy == nil || *cfg.PrometheusServiceProxy {
+		t.Errorf("expected PrometheusServiceProxy false, got %v", cfg.PrometheusServiceProxy)
+	}
+	if cfg.PrometheusTenant != "perf" {
+		t.Errorf("expected PrometheusTenant override, got %q", cfg.PrometheusTenant)
+	}
 }
 
 func TestFromEnv_InvalidValues(t *testing.T) {
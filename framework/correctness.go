@@ -0,0 +1,132 @@
+package framework
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/k6"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// DefaultQueryVerifyPercent is the percentage of the trace IDs sampled during the
+// query load that VerifyQueryCorrectness fetches again
+const DefaultQueryVerifyPercent = 10
+
+// maxQueryVerifyTraces bounds the traces fetched after a run, as each lookup goes
+// through the API server service proxy
+const maxQueryVerifyTraces = 200
+
+// VerifyQueryCorrectness checks the results returned during the query load. The query
+// scripts log a sample of the trace IDs their searches returned, with the span
+// count Tempo reported for each; percent of them are fetched by ID through the query
+// frontend after the run. A trace is complete when it is found with at least the
+// reported span count. The report is also stored in result.QueryCorrectness.
+//
+// Span counts come from the serviceStats of the search results; for results without
+// them only the existence of the trace is checked. A failed lookup stops the checks
+// and is recorded in LookupError.
+func (f *Framework) VerifyQueryCorrectness(result *k6.Result, variant string, percent float64) (*k6.QueryCorrectness, error) {
+	if result == nil || result.Output == "" {
+		return nil, fmt.Errorf("no k6 output available to verify query results")
+	}
+	if percent <= 0 || percent > 100 {
+		return nil, fmt.Errorf("invalid percentage of traces to verify: %v", percent)
+	}
+	samples := k6.ParseQuerySamples(result.Output)
+	if len(samples) == 0 {
+		return nil, fmt.Errorf("k6 did not log any sampled query results")
+	}
+	service, err := queryFrontendService(variant)
+	if err != nil {
+		return nil, err
+	}
+
+	correctness := &k6.QueryCorrectness{TracesSampled: len(samples)}
+	for _, sample := range selectQuerySamples(samples, percent, maxQueryVerifyTraces) {
+		body, err := f.queryFrontendGet(service, "api/traces/"+sample.TraceID, nil)
+		if err != nil && !apierrors.IsNotFound(err) {
+			correctness.LookupError = fmt.Errorf("failed to fetch trace %s: %w", sample.TraceID, err)
+			break
+		}
+		correctness.TracesChecked++
+		if err != nil {
+			f.logger.Debug("queried trace not found", "traceID", sample.TraceID)
+			continue
+		}
+		correctness.TracesFound++
+
+		spans, err := countTraceSpans(body)
+		if err != nil {
+			correctness.LookupError = fmt.Errorf("failed to parse trace %s: %w", sample.TraceID, err)
+			break
+		}
+		if spans >= sample.Spans {
+			correctness.TracesComplete++
+			continue
+		}
+		correctness.MissingSpans += sample.Spans - spans
+		f.logger.Debug("queried trace incomplete", "traceID", sample.TraceID, "expected", sample.Spans, "found", spans)
+	}
+	if correctness.TracesChecked > 0 {
+		correctness.Score = float64(correctness.TracesComplete) / float64(correctness.TracesChecked) * 100
+	}
+
+	result.QueryCorrectness = correctness
+	return correctness, nil
+}
+
+// selectQuerySamples picks percent of samples, at most limit, spread evenly over the
+// run. At least one sample is picked.
+func selectQuerySamples(samples []k6.QuerySample, percent float64, limit int) []k6.QuerySample {
+	n := int(math.Ceil(float64(len(samples)) * percent / 100))
+	if n > limit {
+		n = limit
+	}
+	if n >= len(samples) {
+		return samples
+	}
+	if n < 1 {
+		n = 1
+	}
+
+	selected := make([]k6.QuerySample, 0, n)
+	step := float64(len(samples)) / float64(n)
+	for i := 0; i < n; i++ {
+		selected = append(selected, samples[int(float64(i)*step)])
+	}
+	return selected
+}
+
+// tempoTraceResponse holds the spans of a Tempo trace by ID response. Tempo versions
+// before 2.0 return instrumentationLibrarySpans instead of scopeSpans.
+type tempoTraceResponse struct {
+	Batches []struct {
+		ScopeSpans []struct {
+			Spans []json.RawMessage `json:"spans"`
+		} `json:"scopeSpans"`
+		InstrumentationLibrarySpans []struct {
+			Spans []json.RawMessage `json:"spans"`
+		} `json:"instrumentationLibrarySpans"`
+	} `json:"batches"`
+}
+
+// countTraceSpans returns the number of spans in a trace by ID response
+func countTraceSpans(body []byte) (int, error) {
+	var trace tempoTraceResponse
+	if err := json.Unmarshal(body, &trace); err != nil {
+		return 0, err
+	}
+
+	spans := 0
+	for _, batch := range trace.Batches {
+		for _, scope := range batch.ScopeSpans {
+			spans += len(scope.Spans)
+		}
+		for _, scope := range batch.InstrumentationLibrarySpans {
+			spans += len(scope.Spans)
+		}
+	}
+	return spans, nil
+}
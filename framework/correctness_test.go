@@ -0,0 +1,77 @@
+package framework
+
+import (
+	"testing"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/k6"
+)
+
+func TestParseQuerySamples(t *testing.T) {
+	output := `time="2024-06-01T12:00:00Z" level=info msg="QUERY_SAMPLE traceID=ABC123 spans=5" source=console
+time="2024-06-01T12:00:01Z" level=info msg="QUERY_SAMPLE traceID=def456 spans=0" source=console
+time="2024-06-01T12:00:02Z" level=info msg="QUERY_SAMPLE traceID=abc123 spans=7" source=console
+`
+	samples := k6.ParseQuerySamples(output)
+	if len(samples) != 2 {
+		t.Fatalf("expected 2 distinct traces, got %+v", samples)
+	}
+	if samples[0].TraceID != "abc123" || samples[0].Spans != 7 {
+		t.Errorf("expected the highest span count of a repeated trace, got %+v", samples[0])
+	}
+	if samples[1].TraceID != "def456" || samples[1].Spans != 0 {
+		t.Errorf("unexpected sample %+v", samples[1])
+	}
+}
+
+func TestSelectQuerySamples(t *testing.T) {
+	samples := make([]k6.QuerySample, 100)
+	for i := range samples {
+		samples[i].Spans = i
+	}
+
+	selected := selectQuerySamples(samples, 10, maxQueryVerifyTraces)
+	if len(selected) != 10 || selected[0].Spans != 0 || selected[9].Spans != 90 {
+		t.Errorf("expected every 10th sample, got %+v", selected)
+	}
+	if got := selectQuerySamples(samples, 10, 5); len(got) != 5 {
+		t.Errorf("expected the limit of 5 samples, got %d", len(got))
+	}
+	if got := selectQuerySamples(samples[:3], 1, maxQueryVerifyTraces); len(got) != 1 {
+		t.Errorf("expected at least one sample, got %d", len(got))
+	}
+}
+
+func TestCountTraceSpans(t *testing.T) {
+	body := []byte(`{"batches":[
+		{"scopeSpans":[{"spans":[{"spanId":"a"},{"spanId":"b"}]},{"spans":[{"spanId":"c"}]}]},
+		{"instrumentationLibrarySpans":[{"spans":[{"spanId":"d"}]}]}
+	]}`)
+	spans, err := countTraceSpans(body)
+	if err != nil {
+		t.Fatalf("countTraceSpans() error = %v", err)
+	}
+	if spans != 4 {
+		t.Errorf("expected 4 spans, got %d", spans)
+	}
+	if _, err := countTraceSpans([]byte("not json")); err == nil {
+		t.Error("expected error for an invalid response")
+	}
+}
+
+func TestVerifyQueryCorrectness_RequiresSamples(t *testing.T) {
+	f := newOrphanTestFramework()
+
+	if _, err := f.VerifyQueryCorrectness(&k6.Result{}, "monolithic", 10); err == nil {
+		t.Error("expected error without k6 output")
+	}
+	result := &k6.Result{Output: "no samples here"}
+	if _, err := f.VerifyQueryCorrectness(result, "monolithic", 10); err == nil {
+		t.Error("expected error without sampled results")
+	}
+	if _, err := f.VerifyQueryCorrectness(result, "monolithic", 0); err == nil {
+		t.Error("expected error for a zero percentage")
+	}
+	if result.QueryCorrectness != nil {
+		t.Error("expected no correctness report on error")
+	}
+}
@@ -58,9 +58,11 @@
 //
 // # Metrics Collection
 //
-// The framework can collect and export Prometheus metrics:
+// The framework can collect and export Prometheus metrics. CheckClockSkew measures
+// the cluster clock, so test windows taken with fw.Now match the sample timestamps:
 //
-//	testStart := time.Now()
+//	fw.CheckClockSkew()
+//	testStart := fw.Now()
 //	// ... run tests ...
 //	fw.CollectMetrics(testStart, "results/metrics.csv")
 //
@@ -75,6 +77,7 @@
 //   - concurrent: Concurrent execution helpers for parallel operations
 //   - gvr: Centralized GroupVersionResource definitions
 //   - k6: k6 load test execution
+//   - kafka: Kafka deployment for the Kafka ingestion path
 //   - metrics: Prometheus metrics collection and export
 //   - minio: MinIO object storage deployment
 //   - otel: OpenTelemetry Collector deployment
@@ -10,6 +10,9 @@ var (
 	// ErrNamespaceRequired indicates that a namespace was not provided
 	ErrNamespaceRequired = errors.New("namespace is required")
 
+	// ErrNamespaceInUse indicates that the namespace already exists, e.g. from another run
+	ErrNamespaceInUse = errors.New("namespace already in use")
+
 	// ErrCRDeletionTimeout indicates that CR deletion timed out
 	ErrCRDeletionTimeout = errors.New("CR deletion timed out")
 
@@ -43,6 +46,9 @@ var (
 	// ErrClusterConnection indicates failure to connect to the cluster
 	ErrClusterConnection = errors.New("failed to connect to cluster")
 
+	// ErrUnsafeCluster indicates that the cluster failed the safety guardrails
+	ErrUnsafeCluster = errors.New("refusing to operate on unsafe cluster")
+
 	// ErrContextCancelled indicates the operation was cancelled
 	ErrContextCancelled = errors.New("operation cancelled")
 )
@@ -132,6 +132,7 @@ func TestSentinelErrors(t *testing.T) {
 	// Ensure sentinel errors are distinct
 	errs := []error{
 		ErrNamespaceRequired,
+		ErrNamespaceInUse,
 		ErrCRDeletionTimeout,
 		ErrFinalizerRemoval,
 		ErrOperatorNotInstalled,
@@ -143,6 +144,7 @@ func TestSentinelErrors(t *testing.T) {
 		ErrPrometheusQuery,
 		ErrResourceNotFound,
 		ErrClusterConnection,
+		ErrUnsafeCluster,
 		ErrContextCancelled,
 	}
 
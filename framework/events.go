@@ -0,0 +1,232 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/metrics"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+)
+
+// eventSyncTimeout bounds the initial listing of events and pods
+const eventSyncTimeout = 30 * time.Second
+
+// notableNormalReasons are the Normal events recorded besides all warnings: restarts,
+// preemption and scaling change the capacity serving the load
+var notableNormalReasons = map[string]bool{
+	"Killing":           true,
+	"Preempting":        true,
+	"SuccessfulRescale": true,
+	"ScalingReplicaSet": true,
+}
+
+// EventWatcher records the warning Events of the test namespace (FailedScheduling,
+// Evicted, Unhealthy probes, BackOff, ...) and container terminations such as
+// OOMKilled, which the kubelet reports in the pod status rather than as an Event.
+// Events expire from the API server after an hour, so long runs must watch them.
+type EventWatcher struct {
+	since time.Time
+
+	mu     sync.Mutex
+	events map[string]metrics.ClusterEvent
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// WatchEvents starts recording the cluster events of the test namespace until
+// Cleanup, or until Stop is called on the returned watcher. Calling it again
+// returns the running watcher.
+func (f *Framework) WatchEvents() (*EventWatcher, error) {
+	if w := f.EventWatcher(); w != nil {
+		return w, nil
+	}
+
+	w := &EventWatcher{
+		// Event timestamps have a resolution of one second
+		since:  f.Now().Truncate(time.Second),
+		events: make(map[string]metrics.ClusterEvent),
+		stop:   make(chan struct{}),
+	}
+
+	factory := informers.NewSharedInformerFactoryWithOptions(f.client, 0, informers.WithNamespace(f.namespace))
+	eventInformer := factory.Core().V1().Events().Informer()
+	if _, err := eventInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    w.onEvent,
+		UpdateFunc: func(_, obj interface{}) { w.onEvent(obj) },
+	}); err != nil {
+		return nil, fmt.Errorf("failed to watch events: %w", err)
+	}
+	podInformer := factory.Core().V1().Pods().Informer()
+	if _, err := podInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: w.onPodUpdate,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to watch pods: %w", err)
+	}
+	factory.Start(w.stop)
+
+	ctx, cancel := context.WithTimeout(f.ctx, eventSyncTimeout)
+	defer cancel()
+	if !cache.WaitForCacheSync(ctx.Done(), eventInformer.HasSynced, podInformer.HasSynced) {
+		w.Stop()
+		return nil, fmt.Errorf("failed to list events and pods of namespace %s", f.namespace)
+	}
+
+	// Stop with the framework context, like the resource sampler
+	go func() {
+		select {
+		case <-f.ctx.Done():
+			w.Stop()
+		case <-w.stop:
+		}
+	}()
+
+	f.mu.Lock()
+	f.eventWatcher = w
+	f.mu.Unlock()
+	f.logger.Info("Watching cluster events", "namespace", f.namespace)
+	return w, nil
+}
+
+// EventWatcher returns the watcher started with WatchEvents, or nil
+func (f *Framework) EventWatcher() *EventWatcher {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.eventWatcher
+}
+
+// Stop stops watching; the recorded events remain available
+func (w *EventWatcher) Stop() {
+	w.stopOnce.Do(func() { close(w.stop) })
+}
+
+// Events returns the recorded events in time order
+func (w *EventWatcher) Events() []metrics.ClusterEvent {
+	w.mu.Lock()
+	events := make([]metrics.ClusterEvent, 0, len(w.events))
+	for _, e := range w.events {
+		events = append(events, e)
+	}
+	w.mu.Unlock()
+
+	sort.Slice(events, func(i, j int) bool {
+		if !events[i].Time.Equal(events[j].Time) {
+			return events[i].Time.Before(events[j].Time)
+		}
+		return events[i].Object < events[j].Object
+	})
+	return events
+}
+
+// WriteJSON writes the recorded events to outputPath
+func (w *EventWatcher) WriteJSON(outputPath string) error {
+	return metrics.WriteClusterEvents(w.Events(), outputPath)
+}
+
+// onEvent records a Kubernetes Event; updates of a repeating event replace it
+func (w *EventWatcher) onEvent(obj interface{}) {
+	ev, ok := obj.(*corev1.Event)
+	if !ok {
+		return
+	}
+	e := clusterEvent(ev)
+	if (!e.IsWarning() && !notableNormalReasons[e.Reason]) || e.Time.Before(w.since) {
+		return
+	}
+	w.mu.Lock()
+	w.events[string(ev.UID)] = e
+	w.mu.Unlock()
+}
+
+// onPodUpdate records the container terminations that caused restarts
+func (w *EventWatcher) onPodUpdate(oldObj, newObj interface{}) {
+	oldPod, ok := oldObj.(*corev1.Pod)
+	if !ok {
+		return
+	}
+	newPod, ok := newObj.(*corev1.Pod)
+	if !ok {
+		return
+	}
+	for key, e := range containerTerminations(oldPod, newPod) {
+		w.mu.Lock()
+		w.events[key] = e
+		w.mu.Unlock()
+	}
+}
+
+// clusterEvent converts a Kubernetes Event. Events recorded with the events.k8s.io
+// API carry their time and count in EventTime and Series instead.
+func clusterEvent(ev *corev1.Event) metrics.ClusterEvent {
+	e := metrics.ClusterEvent{
+		Type:    ev.Type,
+		Reason:  ev.Reason,
+		Object:  fmt.Sprintf("%s/%s", ev.InvolvedObject.Kind, ev.InvolvedObject.Name),
+		Message: ev.Message,
+		Count:   ev.Count,
+	}
+
+	switch {
+	case ev.Series != nil && !ev.Series.LastObservedTime.IsZero():
+		e.Time = ev.Series.LastObservedTime.Time
+	case !ev.LastTimestamp.IsZero():
+		e.Time = ev.LastTimestamp.Time
+	case !ev.EventTime.IsZero():
+		e.Time = ev.EventTime.Time
+	default:
+		e.Time = ev.CreationTimestamp.Time
+	}
+	if !ev.FirstTimestamp.IsZero() {
+		e.FirstTime = ev.FirstTimestamp.Time
+	} else if !ev.EventTime.IsZero() {
+		e.FirstTime = ev.EventTime.Time
+	}
+	if e.FirstTime.Equal(e.Time) {
+		e.FirstTime = time.Time{}
+	}
+
+	if ev.Series != nil && ev.Series.Count > e.Count {
+		e.Count = ev.Series.Count
+	}
+	if e.Count == 0 {
+		e.Count = 1
+	}
+	return e
+}
+
+// containerTerminations returns an event for each container of newPod that was
+// restarted since oldPod, keyed by pod, container and restart count
+func containerTerminations(oldPod, newPod *corev1.Pod) map[string]metrics.ClusterEvent {
+	restarts := make(map[string]int32, len(oldPod.Status.ContainerStatuses))
+	for _, cs := range oldPod.Status.ContainerStatuses {
+		restarts[cs.Name] = cs.RestartCount
+	}
+
+	events := make(map[string]metrics.ClusterEvent)
+	for _, cs := range newPod.Status.ContainerStatuses {
+		terminated := cs.LastTerminationState.Terminated
+		if terminated == nil || cs.RestartCount <= restarts[cs.Name] {
+			continue
+		}
+		reason := terminated.Reason
+		if reason == "" {
+			reason = "Terminated"
+		}
+		key := fmt.Sprintf("%s/%s/%d", newPod.Name, cs.Name, cs.RestartCount)
+		events[key] = metrics.ClusterEvent{
+			Time:    terminated.FinishedAt.Time,
+			Type:    corev1.EventTypeWarning,
+			Reason:  reason,
+			Object:  "Pod/" + newPod.Name,
+			Message: fmt.Sprintf("container %s terminated with exit code %d (restart %d)", cs.Name, terminated.ExitCode, cs.RestartCount),
+			Count:   1,
+		}
+	}
+	return events
+}
@@ -0,0 +1,120 @@
+package framework
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/config"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestClusterEvent(t *testing.T) {
+	first := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	ev := &corev1.Event{
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "tempo-simplest-0"},
+		Type:           corev1.EventTypeWarning,
+		Reason:         "Unhealthy",
+		Message:        "Readiness probe failed",
+		FirstTimestamp: metav1.NewTime(first),
+		LastTimestamp:  metav1.NewTime(first.Add(time.Minute)),
+		Count:          3,
+	}
+	e := clusterEvent(ev)
+	if e.Object != "Pod/tempo-simplest-0" || e.Reason != "Unhealthy" || !e.IsWarning() {
+		t.Errorf("unexpected event %+v", e)
+	}
+	if !e.Time.Equal(first.Add(time.Minute)) || !e.FirstTime.Equal(first) || e.Count != 3 {
+		t.Errorf("expected the last occurrence, first occurrence and count, got %+v", e)
+	}
+
+	// events.k8s.io events only set EventTime and Series
+	ev = &corev1.Event{
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "k6-0"},
+		Type:           corev1.EventTypeWarning,
+		Reason:         "FailedScheduling",
+		EventTime:      metav1.NewMicroTime(first),
+		Series:         &corev1.EventSeries{Count: 5, LastObservedTime: metav1.NewMicroTime(first.Add(time.Minute))},
+	}
+	e = clusterEvent(ev)
+	if !e.Time.Equal(first.Add(time.Minute)) || !e.FirstTime.Equal(first) || e.Count != 5 {
+		t.Errorf("expected the series time and count, got %+v", e)
+	}
+}
+
+func TestContainerTerminations(t *testing.T) {
+	finished := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	oldPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "tempo-simplest-0"},
+		Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+			{Name: "tempo", RestartCount: 0},
+			{Name: "jaeger-query", RestartCount: 1},
+		}},
+	}
+	newPod := oldPod.DeepCopy()
+	newPod.Status.ContainerStatuses[0].RestartCount = 1
+	newPod.Status.ContainerStatuses[0].LastTerminationState.Terminated = &corev1.ContainerStateTerminated{
+		Reason:     "OOMKilled",
+		ExitCode:   137,
+		FinishedAt: metav1.NewTime(finished),
+	}
+	// An earlier restart is not reported again
+	newPod.Status.ContainerStatuses[1].LastTerminationState.Terminated = &corev1.ContainerStateTerminated{Reason: "Error"}
+
+	events := containerTerminations(oldPod, newPod)
+	if len(events) != 1 {
+		t.Fatalf("expected 1 termination, got %+v", events)
+	}
+	e, ok := events["tempo-simplest-0/tempo/1"]
+	if !ok || e.Reason != "OOMKilled" || !e.IsWarning() || !e.Time.Equal(finished) {
+		t.Errorf("unexpected termination %+v", events)
+	}
+}
+
+func TestWatchEvents(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	f := &Framework{
+		client:    client,
+		namespace: "perf",
+		ctx:       ctx,
+		logger:    slog.Default(),
+		config:    config.Default(),
+	}
+
+	w, err := f.WatchEvents()
+	if err != nil {
+		t.Fatalf("WatchEvents() error = %v", err)
+	}
+	if again, _ := f.WatchEvents(); again != w {
+		t.Errorf("expected the running watcher to be returned")
+	}
+
+	now := metav1.NewTime(time.Now().Add(time.Second))
+	for _, ev := range []*corev1.Event{
+		{ObjectMeta: metav1.ObjectMeta{Name: "evicted", UID: "1"}, Type: corev1.EventTypeWarning, Reason: "Evicted", LastTimestamp: now},
+		{ObjectMeta: metav1.ObjectMeta{Name: "pulled", UID: "2"}, Type: corev1.EventTypeNormal, Reason: "Pulled", LastTimestamp: now},
+		{ObjectMeta: metav1.ObjectMeta{Name: "old", UID: "3"}, Type: corev1.EventTypeWarning, Reason: "BackOff", LastTimestamp: metav1.NewTime(now.Add(-time.Hour))},
+	} {
+		if _, err := client.CoreV1().Events("perf").Create(ctx, ev, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("failed to create event: %v", err)
+		}
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(w.Events()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+	events := w.Events()
+	if len(events) != 1 || events[0].Reason != "Evicted" {
+		t.Errorf("expected only the Evicted warning of the run, got %+v", events)
+	}
+
+	w.Stop()
+}
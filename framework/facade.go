@@ -5,14 +5,19 @@ import (
 	"time"
 
 	"github.com/redhat/perf-tests-tempo/test/framework/k6"
+	"github.com/redhat/perf-tests-tempo/test/framework/kafka"
 	"github.com/redhat/perf-tests-tempo/test/framework/metrics"
 	"github.com/redhat/perf-tests-tempo/test/framework/metrics/dashboard"
 	"github.com/redhat/perf-tests-tempo/test/framework/minio"
 	"github.com/redhat/perf-tests-tempo/test/framework/otel"
 	"github.com/redhat/perf-tests-tempo/test/framework/tempo"
+	"github.com/redhat/perf-tests-tempo/test/framework/tracing"
 	"github.com/redhat/perf-tests-tempo/test/framework/wait"
 
+	"go.opentelemetry.io/otel/attribute"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 // MinIOConfig holds MinIO configuration options
@@ -20,6 +25,18 @@ type MinIOConfig struct {
 	// StorageSize is the PVC size for MinIO (e.g., "10Gi")
 	// Default: "2Gi"
 	StorageSize string
+
+	// Replicas is the number of MinIO servers: 1, or 4 to 16 for distributed MinIO
+	// with one PVC of StorageSize per server
+	// Default: 1
+	Replicas int
+
+	// StorageClass is the StorageClass of the MinIO PVCs.
+	// If empty, the default StorageClass of the cluster is used.
+	StorageClass string
+
+	// Resources are the resource requirements of the MinIO containers (optional)
+	Resources *corev1.ResourceRequirements
 }
 
 // SetupMinIO deploys MinIO with PVC and waits for it to be ready
@@ -28,70 +45,239 @@ func (f *Framework) SetupMinIO() error {
 }
 
 // SetupMinIOWithConfig deploys MinIO with custom configuration
-func (f *Framework) SetupMinIOWithConfig(config *MinIOConfig) error {
+func (f *Framework) SetupMinIOWithConfig(config *MinIOConfig) (err error) {
+	op, span := f.startOperation("framework.SetupMinIO")
+	defer func() { tracing.End(span, err) }()
+
 	if err := f.EnsureNamespace(); err != nil {
 		return err
 	}
-	var minioConfig *minio.Config
-	if config != nil {
-		minioConfig = &minio.Config{
-			StorageSize: config.StorageSize,
-		}
+	return minio.Setup(op, toMinIOConfig(config))
+}
+
+// toMinIOConfig converts a MinIOConfig to a minio.Config
+func toMinIOConfig(config *MinIOConfig) *minio.Config {
+	if config == nil {
+		return nil
+	}
+	return &minio.Config{
+		StorageSize:  config.StorageSize,
+		Replicas:     config.Replicas,
+		StorageClass: config.StorageClass,
+		Resources:    config.Resources,
 	}
-	return minio.Setup(f, minioConfig)
 }
 
 // SetupTempo deploys Tempo (monolithic or stack) with optional resource configuration
 // variant: "monolithic" or "stack"
 // resources: optional resource configuration
-func (f *Framework) SetupTempo(variant string, resources *ResourceConfig) error {
-	// Convert framework.ResourceConfig to tempo.ResourceConfig
-	var tempoConfig *tempo.ResourceConfig
+func (f *Framework) SetupTempo(variant string, resources *ResourceConfig) (err error) {
+	op, span := f.startOperation("framework.SetupTempo", attribute.String("tempo.variant", variant))
+	defer func() { tracing.End(span, err) }()
+
+	tempoConfig := toTempoConfig(resources)
 	if resources != nil {
-		tempoConfig = &tempo.ResourceConfig{
-			Profile:           resources.Profile,
-			Resources:         resources.Resources,
-			ReplicationFactor: resources.ReplicationFactor,
-			NodeSelector:      resources.NodeSelector,
+		// Store the node selector for use in anti-affinity for generator pods
+		if len(resources.NodeSelector) > 0 {
+			f.SetTempoNodeSelector(resources.NodeSelector)
 		}
-		if resources.Overrides != nil {
-			tempoConfig.Overrides = &tempo.TempoOverrides{
-				MaxTracesPerUser: resources.Overrides.MaxTracesPerUser,
-			}
-			// Convert ingester config if present
-			if resources.Overrides.Ingester != nil {
-				tempoConfig.Overrides.Ingester = &tempo.IngesterConfig{
-					FlushCheckPeriod:  resources.Overrides.Ingester.FlushCheckPeriod,
-					TraceIdlePeriod:   resources.Overrides.Ingester.TraceIdlePeriod,
-					MaxBlockDuration:  resources.Overrides.Ingester.MaxBlockDuration,
-					ConcurrentFlushes: resources.Overrides.Ingester.ConcurrentFlushes,
-				}
-			}
+		// Store the ingestion auth for the OTel Collector setup
+		f.SetIngestionAuth(resources.IngestionAuth)
+		// Store the pinned collector image for the OTel Collector setup
+		f.SetCollectorImage(resources.CollectorImage)
+		// Store the exporter protocol for the OTel Collector setup
+		f.SetIngestionProtocol(resources.IngestionProtocol)
+		// Store the Kafka of the ingestion path for the OTel Collector setup
+		f.SetKafka(resources.Kafka)
+	}
+	if err := tempo.Setup(op, variant, tempoConfig); err != nil {
+		return err
+	}
+	if resources != nil && resources.Image != "" {
+		return f.verifyPinnedImage("Tempo", tempoPodSelector, tempoContainer, resources.Image)
+	}
+	return nil
+}
+
+// toTempoConfig converts a framework ResourceConfig to a tempo.ResourceConfig
+func toTempoConfig(resources *ResourceConfig) *tempo.ResourceConfig {
+	if resources == nil {
+		return nil
+	}
+	tempoConfig := &tempo.ResourceConfig{
+		Profile:            resources.Profile,
+		Resources:          resources.Resources,
+		ReplicationFactor:  resources.ReplicationFactor,
+		NodeSelector:       resources.NodeSelector,
+		Env:                resources.Env,
+		IngestionAuth:      resources.IngestionAuth,
+		ExtraConfig:        resources.ExtraConfig,
+		Image:              resources.Image,
+		Replicas:           resources.Replicas,
+		Tenants:            resources.Tenants,
+		ComponentResources: resources.ComponentResources,
+	}
+	if resources.Overrides != nil {
+		tempoConfig.Overrides = &tempo.TempoOverrides{
+			MaxTracesPerUser: resources.Overrides.MaxTracesPerUser,
 		}
-		if resources.Storage != nil {
-			tempoConfig.Storage = &tempo.StorageConfig{
-				Type:            resources.Storage.Type,
-				SecretName:      resources.Storage.SecretName,
-				Endpoint:        resources.Storage.Endpoint,
-				Bucket:          resources.Storage.Bucket,
-				Region:          resources.Storage.Region,
-				AccessKeyID:     resources.Storage.AccessKeyID,
-				SecretAccessKey: resources.Storage.SecretAccessKey,
-				Insecure:        resources.Storage.Insecure,
+		// Convert ingester config if present
+		if resources.Overrides.Ingester != nil {
+			tempoConfig.Overrides.Ingester = &tempo.IngesterConfig{
+				FlushCheckPeriod:  resources.Overrides.Ingester.FlushCheckPeriod,
+				TraceIdlePeriod:   resources.Overrides.Ingester.TraceIdlePeriod,
+				MaxBlockDuration:  resources.Overrides.Ingester.MaxBlockDuration,
+				ConcurrentFlushes: resources.Overrides.Ingester.ConcurrentFlushes,
 			}
 		}
-		// Store the node selector for use in anti-affinity for generator pods
-		if len(resources.NodeSelector) > 0 {
-			f.SetTempoNodeSelector(resources.NodeSelector)
+	}
+	if resources.Storage != nil {
+		tempoConfig.Storage = &tempo.StorageConfig{
+			Type:             resources.Storage.Type,
+			SecretName:       resources.Storage.SecretName,
+			Endpoint:         resources.Storage.Endpoint,
+			Bucket:           resources.Storage.Bucket,
+			Region:           resources.Storage.Region,
+			AccessKeyID:      resources.Storage.AccessKeyID,
+			SecretAccessKey:  resources.Storage.SecretAccessKey,
+			Insecure:         resources.Storage.Insecure,
+			Size:             resources.Storage.Size,
+			StorageClassName: resources.Storage.StorageClassName,
 		}
 	}
-	return tempo.Setup(f, variant, tempoConfig)
+	for _, ef := range resources.ExtraFiles {
+		tempoConfig.ExtraFiles = append(tempoConfig.ExtraFiles, tempo.ExtraConfigFile{
+			Name:       ef.Name,
+			Secret:     ef.Secret,
+			Files:      ef.Files,
+			MountPath:  ef.MountPath,
+			References: ef.References,
+		})
+	}
+	if resources.MetricsGenerator != nil {
+		tempoConfig.MetricsGenerator = &tempo.MetricsGeneratorConfig{
+			Processors:     resources.MetricsGenerator.Processors,
+			RemoteWriteURL: resources.MetricsGenerator.RemoteWriteURL,
+		}
+	}
+	if resources.Block != nil {
+		tempoConfig.Block = &tempo.BlockConfig{
+			Version:                   resources.Block.Version,
+			Encoding:                  resources.Block.Encoding,
+			BloomFilterFalsePositive:  resources.Block.BloomFilterFalsePositive,
+			BloomFilterShardSizeBytes: resources.Block.BloomFilterShardSizeBytes,
+		}
+	}
+	if resources.Autoscaling != nil {
+		tempoConfig.Autoscaling = &tempo.AutoscalingConfig{
+			Components:              resources.Autoscaling.Components,
+			MinReplicas:             resources.Autoscaling.MinReplicas,
+			MaxReplicas:             resources.Autoscaling.MaxReplicas,
+			TargetCPUUtilization:    resources.Autoscaling.TargetCPUUtilization,
+			TargetMemoryUtilization: resources.Autoscaling.TargetMemoryUtilization,
+		}
+	}
+	return tempoConfig
+}
+
+// TempoVersion returns the version of the deployed Tempo
+func (f *Framework) TempoVersion(variant string) (string, error) {
+	return tempo.GetVersion(f, variant)
 }
 
 // SetupOTelCollector deploys OpenTelemetry Collector with RBAC
-// tempoVariant should be "monolithic" or "stack" to configure the correct Tempo gateway endpoint
-func (f *Framework) SetupOTelCollector(tempoVariant string) error {
-	return otel.SetupCollector(f, tempoVariant)
+// tempoVariant should be "monolithic" or "stack" to configure the correct Tempo gateway endpoint.
+// The collector authenticates as configured by the IngestionAuth of SetupTempo.
+// With the Kafka of SetupTempo, it deploys Kafka when no brokers are set, and the
+// collectors writing the traces to Kafka and forwarding them to Tempo.
+func (f *Framework) SetupOTelCollector(tempoVariant string) (err error) {
+	op, span := f.startOperation("framework.SetupOTelCollector", attribute.String("tempo.variant", tempoVariant))
+	defer func() { tracing.End(span, err) }()
+
+	if config := f.GetKafka(); config != nil {
+		err = f.setupKafkaCollectors(op, tempoVariant, config)
+	} else {
+		err = otel.SetupCollector(op, tempoVariant)
+	}
+	if err != nil {
+		return err
+	}
+	if image := f.GetCollectorImage(); image != "" {
+		return f.verifyPinnedImage("OTel Collector", collectorPodSelector, collectorContainerName, image)
+	}
+	return nil
+}
+
+// setupKafkaCollectors deploys Kafka when config sets no brokers, and the collectors
+// of the Kafka ingestion path
+func (f *Framework) setupKafkaCollectors(op operation, tempoVariant string, config *KafkaConfig) error {
+	kafkaConfig := toOTelKafkaConfig(f.namespace, f.runID, config)
+	if len(config.Brokers) == 0 {
+		if err := kafka.Setup(op, toKafkaConfig(config)); err != nil {
+			return err
+		}
+	}
+	return otel.SetupKafkaCollectors(op, tempoVariant, kafkaConfig)
+}
+
+// toKafkaConfig converts a KafkaConfig to the kafka.Config of the deployed broker
+func toKafkaConfig(config *KafkaConfig) *kafka.Config {
+	return &kafka.Config{
+		Partitions: config.Partitions,
+		Resources:  config.Resources,
+	}
+}
+
+// toOTelKafkaConfig converts a KafkaConfig to the otel.KafkaConfig of the collectors,
+// with the brokers deployed in namespace when it sets none.
+//
+// Existing brokers may be shared by concurrent and past runs. Runs sharing a consumer
+// group split the partitions of the topic, each forwarding part of the traces of the
+// others, and a new group reading a shared topic from the earliest offset replays the
+// traces of past runs. With existing brokers, the default topic and the group are
+// therefore suffixed with the run ID, or the namespace without one.
+func toOTelKafkaConfig(namespace, runID string, config *KafkaConfig) *otel.KafkaConfig {
+	if len(config.Brokers) > 0 {
+		suffix := runID
+		if suffix == "" {
+			suffix = namespace
+		}
+		topic := config.Topic
+		if topic == "" {
+			topic = otel.DefaultKafkaTopic + "-" + suffix
+		}
+		return &otel.KafkaConfig{
+			Brokers: config.Brokers,
+			Topic:   topic,
+			GroupID: otel.KafkaCollectorName + "-" + suffix,
+		}
+	}
+	return &otel.KafkaConfig{
+		Brokers:         kafka.Brokers(namespace),
+		Topic:           config.Topic,
+		MaxMessageBytes: kafka.MaxMessageBytes,
+	}
+}
+
+// SetupTenantCollector deploys another OTel Collector sending traces as tenant,
+// after SetupOTelCollector, and returns its OTLP endpoint for k6, for the
+// IngestionProtocol of SetupTempo. The tenant
+// must be one of the Tenants of SetupTempo.
+func (f *Framework) SetupTenantCollector(tempoVariant, tenant string) (endpoint string, err error) {
+	op, span := f.startOperation("framework.SetupTenantCollector",
+		attribute.String("tempo.variant", tempoVariant), attribute.String("tempo.tenant", tenant))
+	defer func() { tracing.End(span, err) }()
+
+	if err := otel.SetupTenantCollector(op, tempoVariant, tenant); err != nil {
+		return "", err
+	}
+	return otel.CollectorEndpoint(f.namespace, otel.TenantCollectorName(tenant), f.GetIngestionProtocol()), nil
+}
+
+// SetupOTelCollectorMonitoring makes sure the collector's own metrics are scraped,
+// creating a PodMonitor when the operator did not create a ServiceMonitor
+func (f *Framework) SetupOTelCollectorMonitoring() error {
+	return otel.SetupCollectorMonitoring(f)
 }
 
 // SetupTempoMonitoring verifies ServiceMonitors and creates PodMonitor fallback if needed
@@ -99,9 +285,20 @@ func (f *Framework) SetupTempoMonitoring(variant string) error {
 	return tempo.SetupTempoMonitoring(f, variant)
 }
 
+// SetupAlertRules deploys the performance alert rules (tempo.DefaultAlertRules),
+// whose firing alerts CollectAlerts returns
+func (f *Framework) SetupAlertRules() error {
+	return tempo.SetupAlertRules(f, nil)
+}
+
 // SetupK6PrometheusMetrics enables k6 to export metrics to Prometheus
 // Returns the remote write URL to configure in k6.Config.PrometheusRWURL
+// The cluster-wide ConfigMap is only edited on clusters that pass CheckClusterSafety.
 func (f *Framework) SetupK6PrometheusMetrics() (string, error) {
+	if err := f.CheckClusterSafety(); err != nil {
+		return "", fmt.Errorf("refusing to edit %s: %w", k6.UserWorkloadConfigMapName, err)
+	}
+
 	url, err := k6.SetupK6PrometheusMetrics(f.ctx, f.client)
 	if err != nil {
 		return "", fmt.Errorf("failed to setup k6 Prometheus metrics: %w", err)
@@ -109,39 +306,124 @@ func (f *Framework) SetupK6PrometheusMetrics() (string, error) {
 	return url, nil
 }
 
-// RunK6Test deploys and runs a k6 test as a Kubernetes Job
-func (f *Framework) RunK6Test(testType k6.TestType, config *k6.Config) (*k6.Result, error) {
-	return k6.RunTest(f, testType, config)
+// RunK6Test deploys and runs a k6 test as a Kubernetes Job, or through the configured LoadRunner
+func (f *Framework) RunK6Test(testType k6.TestType, config *k6.Config) (result *k6.Result, err error) {
+	_, span := f.startOperation("framework.RunK6Test", attribute.String("k6.test_type", string(testType)))
+	defer func() { tracing.End(span, err) }()
+
+	return f.LoadRunner().RunTest(f, testType, config)
 }
 
 // RunK6IngestionTest runs the ingestion performance test
 func (f *Framework) RunK6IngestionTest(size k6.Size) (*k6.Result, error) {
-	return k6.RunIngestionTest(f, size)
+	return f.RunK6Test(k6.TestIngestion, &k6.Config{Size: size})
 }
 
 // RunK6QueryTest runs the query performance test
 func (f *Framework) RunK6QueryTest(size k6.Size) (*k6.Result, error) {
-	return k6.RunQueryTest(f, size)
+	return f.RunK6Test(k6.TestQuery, &k6.Config{Size: size})
 }
 
 // RunK6CombinedTest runs the combined ingestion+query performance test
 func (f *Framework) RunK6CombinedTest(size k6.Size) (*k6.Result, error) {
-	return k6.RunCombinedTest(f, size)
+	return f.RunK6Test(k6.TestCombined, &k6.Config{Size: size})
+}
+
+// RunK6ParallelTests runs ingestion and query tests as separate parallel Kubernetes Jobs,
+// or through the configured LoadRunner
+func (f *Framework) RunK6ParallelTests(config *k6.Config) (result *k6.ParallelResult, err error) {
+	_, span := f.startOperation("framework.RunK6ParallelTests")
+	defer func() { tracing.End(span, err) }()
+
+	return f.LoadRunner().RunParallelTests(f, config)
+}
+
+// CollectMetrics collects performance metrics for the test namespace and exports to CSV,
+// using the configured MetricsProvider
+func (f *Framework) CollectMetrics(testStart time.Time, outputPath string) (err error) {
+	_, span := f.startOperation("framework.CollectMetrics")
+	defer func() { tracing.End(span, err) }()
+
+	return f.MetricsProvider().CollectMetrics(f, testStart, outputPath)
+}
+
+// CollectMetricsIncremental collects metrics from Prometheus like CollectMetrics,
+// checkpointing each completed query so calling it again after a failure resumes
+// from the queries that did not complete
+func (f *Framework) CollectMetricsIncremental(testStart time.Time, outputPath string, opts ...metrics.CollectOption) (err error) {
+	op, span := f.startOperation("framework.CollectMetrics")
+	defer func() { tracing.End(span, err) }()
+
+	return metrics.CollectMetricsIncremental(op, testStart, outputPath, opts...)
+}
+
+// StartResourceSampler starts polling metrics-server for the CPU and memory usage of
+// the Tempo pods. When Prometheus is unavailable, CollectMetrics exports the samples
+// instead, so resource charts are still produced. A non-positive interval uses
+// metrics.DefaultSamplingInterval.
+func (f *Framework) StartResourceSampler(interval time.Duration) error {
+	sampler := metrics.NewResourceSampler(f.dynamicClient, f.namespace, interval)
+	if err := sampler.Start(f.ctx); err != nil {
+		return fmt.Errorf("metrics-server is not available: %w", err)
+	}
+	f.resourceSampler = sampler
+	return nil
 }
 
-// RunK6ParallelTests runs ingestion and query tests as separate parallel Kubernetes Jobs
-func (f *Framework) RunK6ParallelTests(config *k6.Config) (*k6.ParallelResult, error) {
-	return k6.RunParallelTests(f, config)
+// ResourceSampler returns the sampler started with StartResourceSampler, or nil
+func (f *Framework) ResourceSampler() *metrics.ResourceSampler {
+	return f.resourceSampler
 }
 
-// CollectMetrics collects performance metrics for the test namespace and exports to CSV
-func (f *Framework) CollectMetrics(testStart time.Time, outputPath string) error {
-	return metrics.CollectMetrics(f, testStart, outputPath)
+// StartMetricsScraper starts scraping the /metrics endpoints of the Tempo pods through
+// the API server pod proxy. When Prometheus does not scrape the namespace, or is
+// unavailable, CollectMetrics exports the scraped Tempo metrics for the queries
+// without data. A non-positive interval uses metrics.DefaultScrapeInterval.
+func (f *Framework) StartMetricsScraper(interval time.Duration) error {
+	scraper := metrics.NewMetricsScraper(f.client, f.namespace, interval)
+	if err := scraper.Start(f.ctx); err != nil {
+		return fmt.Errorf("failed to start Tempo metrics scraper: %w", err)
+	}
+	f.metricsScraper = scraper
+	return nil
 }
 
-// CollectMetricsWithDuration collects metrics for a specific duration (counting back from now)
-func (f *Framework) CollectMetricsWithDuration(duration time.Duration, outputPath string) error {
-	return metrics.CollectMetricsWithDuration(f, duration, outputPath)
+// MetricsScraper returns the scraper started with StartMetricsScraper, or nil
+func (f *Framework) MetricsScraper() *metrics.MetricsScraper {
+	return f.metricsScraper
+}
+
+// StartMetricsRollups writes the metrics of each interval since testStart to
+// metrics.RollupDir(outputPath) while the test runs, so partial results of long soak
+// tests survive an aborted run. Collecting the metrics with metrics.WithRollups and
+// the same interval stops the writer and reuses the rollups.
+func (f *Framework) StartMetricsRollups(testStart time.Time, outputPath string, interval time.Duration) error {
+	w := metrics.NewRollupWriter(f, testStart, outputPath, interval)
+	if err := w.Start(f.ctx); err != nil {
+		return err
+	}
+	f.rollupWriter = w
+	return nil
+}
+
+// RollupWriter returns the writer started with StartMetricsRollups, or nil
+func (f *Framework) RollupWriter() *metrics.RollupWriter {
+	return f.rollupWriter
+}
+
+// CollectMetricsWithDuration collects metrics for a specific duration (counting back from now).
+// Options are only supported by the Prometheus collection, which is used when set.
+func (f *Framework) CollectMetricsWithDuration(duration time.Duration, outputPath string, opts ...metrics.CollectOption) error {
+	if len(opts) > 0 {
+		return metrics.CollectMetricsWithDuration(f, duration, outputPath, opts...)
+	}
+	return f.CollectMetrics(f.Now().Add(-duration), outputPath)
+}
+
+// CollectAlerts returns the intervals during which alerts of the namespace were
+// firing since testStart
+func (f *Framework) CollectAlerts(testStart time.Time) ([]metrics.FiringAlert, error) {
+	return metrics.CollectAlerts(f, testStart, f.Now())
 }
 
 // ExportK6Metrics exports k6 metrics to a JSON file
@@ -149,6 +431,28 @@ func (f *Framework) ExportK6Metrics(k6Metrics *k6.K6Metrics, outputPath string,
 	return metrics.ExportK6Metrics(k6Metrics, outputPath, testType)
 }
 
+// ExportK6Result exports the k6 metrics of a result, including its ingestion
+// completeness report, to a JSON file
+func (f *Framework) ExportK6Result(result *k6.Result, outputPath string, testType string) error {
+	return metrics.ExportK6Result(result, outputPath, testType)
+}
+
+// WaitFor polls condition at the readiness poll interval of the framework
+// configuration until it returns true, returns an error, or timeout passes.
+// A zero timeout uses the configured PodReadyTimeout.
+func (f *Framework) WaitFor(timeout time.Duration, condition func() (bool, error)) error {
+	if timeout <= 0 {
+		timeout = f.config.PodReadyTimeout
+	}
+	return wait.ForCondition(f.ctx, f.config.PodReadyPollInterval, timeout, condition)
+}
+
+// WaitForCR waits until the value at jsonpath of a custom resource in the namespace
+// equals expected, within the configured CRReadyTimeout
+func (f *Framework) WaitForCR(resource schema.GroupVersionResource, name, jsonpath, expected string) error {
+	return wait.ForCR(f, resource, name, jsonpath, expected)
+}
+
 // WaitForPodsReady waits for pods matching the selector to be ready
 func (f *Framework) WaitForPodsReady(selector labels.Selector, timeout time.Duration, minReady int) error {
 	return wait.ForPodsReady(f, selector, timeout, minReady)
@@ -169,6 +473,13 @@ func (f *Framework) WaitForTempoPodsReady(timeout time.Duration) error {
 	return wait.ForTempoPodsReady(f, timeout)
 }
 
+// WaitForTempoStackReady waits for the Ready condition of a TempoStack and the
+// rollout of all its components, returning a per-component report. A zero timeout
+// uses the configured CRReadyTimeout.
+func (f *Framework) WaitForTempoStackReady(name string, timeout time.Duration) (*wait.StackReport, error) {
+	return wait.ForTempoStackReady(f, name, timeout)
+}
+
 // GenerateDashboard generates an HTML dashboard from a metrics CSV file
 func (f *Framework) GenerateDashboard(csvPath, outputPath, profileName string) error {
 	config := dashboard.DashboardConfig{
@@ -177,12 +488,19 @@ func (f *Framework) GenerateDashboard(csvPath, outputPath, profileName string) e
 		TestType:    "combined",
 		GeneratedAt: time.Now(),
 	}
-	return dashboard.Generate(csvPath, outputPath, config)
+	return f.GenerateDashboardWithConfig(csvPath, outputPath, config)
 }
 
-// GenerateDashboardWithConfig generates an HTML dashboard with custom config
+// GenerateDashboardWithConfig generates an HTML dashboard with custom config,
+// using the configured ReportGenerator
 func (f *Framework) GenerateDashboardWithConfig(csvPath, outputPath string, config dashboard.DashboardConfig) error {
-	return dashboard.Generate(csvPath, outputPath, config)
+	return f.ReportGenerator().Generate(csvPath, outputPath, config)
+}
+
+// GenerateComparisonDashboard generates a dashboard comparing several metrics files,
+// using the configured ReportGenerator
+func (f *Framework) GenerateComparisonDashboard(csvPaths []string, outputPath string, config dashboard.DashboardConfig) error {
+	return f.ReportGenerator().GenerateComparison(csvPaths, outputPath, config)
 }
 
 // CheckMetricAvailability checks which metrics are available in Prometheus
@@ -4,15 +4,21 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/redhat/perf-tests-tempo/test/framework/config"
+	"github.com/redhat/perf-tests-tempo/test/framework/metrics"
+	"github.com/redhat/perf-tests-tempo/test/framework/retry"
+	"github.com/redhat/perf-tests-tempo/test/framework/tracing"
 
+	"go.opentelemetry.io/otel/trace"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
-	"k8s.io/client-go/tools/clientcmd"
 )
 
 // Framework is the main deployment framework for performance tests
@@ -21,6 +27,7 @@ type Framework struct {
 	dynamicClient dynamic.Interface
 	restConfig    *rest.Config
 	namespace     string
+	runID         string
 	ctx           context.Context
 	logger        *slog.Logger
 	config        *config.Config
@@ -30,9 +37,65 @@ type Framework struct {
 	trackedCRs              []TrackedResource
 	trackedClusterResources []TrackedResource
 
+	// Time windows of phased tests, used to split collected metrics
+	phases []metrics.Phase
+
+	// Fallback source of resource metrics when Prometheus is unavailable
+	resourceSampler *metrics.ResourceSampler
+
+	// Fallback source of Tempo metrics when Prometheus does not scrape the namespace
+	metricsScraper *metrics.MetricsScraper
+
+	// Source of the storage footprint series, started by StartStorageSampler
+	storageSampler *metrics.StorageSampler
+
+	// Writer of per-interval metric rollups, started by StartMetricsRollups
+	rollupWriter *metrics.RollupWriter
+
+	// Recorder of cluster events, started by WatchEvents
+	eventWatcher *EventWatcher
+
+	// Retries of the operations of the run, see RetryStats
+	retryStats *retry.Stats
+
+	// Offset of the cluster clock from the runner clock, set by CheckClockSkew
+	clockOffset time.Duration
+
 	// Node scheduling - stores the node selector used for Tempo
 	// Used to create anti-affinity for generator pods (k6, MinIO, OTel)
 	tempoNodeSelector map[string]string
+
+	// Ingestion authentication of the OTel Collector, set by SetupTempo since it
+	// decides whether Tempo is deployed with the gateway
+	ingestionAuth string
+
+	// Pinned OTel Collector image, set by SetupTempo from the resource config
+	collectorImage string
+
+	// OTLP protocol the OTel Collector exports to Tempo with, set by SetupTempo
+	// from the resource config
+	ingestionProtocol string
+
+	// Kafka the OTel Collector routes the ingestion through, set by SetupTempo
+	// from the resource config
+	kafka *KafkaConfig
+
+	// ResourceQuota and LimitRange of the namespace, applied by EnsureNamespace
+	namespaceQuota        *NamespaceQuota
+	namespaceQuotaApplied bool
+
+	// Pluggable subsystems; nil uses the default implementation
+	metricsProvider  MetricsProvider
+	metricsCollector metrics.Collector
+	loadRunner       LoadRunner
+	reportGenerator  ReportGenerator
+
+	// Cluster selection; empty uses in-cluster config or the default kubeconfig
+	kubeconfigPath string
+	kubeContext    string
+
+	// Provider of the spans of framework operations; nil records none
+	tracerProvider trace.TracerProvider
 }
 
 // Option is a function that configures the Framework
@@ -45,6 +108,13 @@ func WithLogger(logger *slog.Logger) Option {
 	}
 }
 
+// WithRunID sets the run ID added to the managed labels of all created resources
+func WithRunID(runID string) Option {
+	return func(f *Framework) {
+		f.runID = runID
+	}
+}
+
 // WithConfig sets a custom configuration for the framework
 func WithConfig(cfg *config.Config) Option {
 	return func(f *Framework) {
@@ -56,54 +126,113 @@ func WithConfig(cfg *config.Config) Option {
 // The context is used for all Kubernetes operations and should be cancelled
 // to stop any in-progress operations.
 func New(ctx context.Context, namespace string, opts ...Option) (*Framework, error) {
+	if err := validateNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	return newFramework(ctx, namespace, opts...)
+}
+
+// validateNamespace checks that namespace is a valid namespace name: a DNS label
+// of at most 63 characters
+func validateNamespace(namespace string) error {
 	if namespace == "" {
-		return nil, ErrNamespaceRequired
+		return ErrNamespaceRequired
 	}
+	if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+		return fmt.Errorf("invalid namespace %q: %s", namespace, strings.Join(errs, "; "))
+	}
+	return nil
+}
 
+// newFramework connects to the cluster and creates a Framework.
+// An empty namespace yields a cluster-level instance for operations spanning namespaces.
+func newFramework(ctx context.Context, namespace string, opts ...Option) (*Framework, error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
 
-	restConfig, err := rest.InClusterConfig()
-	if err != nil {
-		// Use KUBECONFIG env var if set, otherwise fall back to ~/.kube/config
-		loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
-		configOverrides := &clientcmd.ConfigOverrides{}
-		kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, configOverrides)
-		restConfig, err = kubeConfig.ClientConfig()
+	f := &Framework{
+		namespace:               namespace,
+		ctx:                     ctx,
+		logger:                  slog.Default(),
+		config:                  config.FromEnv(),
+		trackedCRs:              make([]TrackedResource, 0),
+		trackedClusterResources: make([]TrackedResource, 0),
+		retryStats:              retry.NewStats(),
+	}
+
+	// Apply options before connecting, since they may select the cluster
+	for _, opt := range opts {
+		opt(f)
+	}
+	if f.tracerProvider != nil {
+		f.ctx = tracing.ContextWithTracerProvider(f.ctx, f.tracerProvider)
+	}
+
+	if f.restConfig == nil {
+		restConfig, err := loadRESTConfig(f.kubeconfigPath, f.kubeContext)
 		if err != nil {
 			return nil, fmt.Errorf("%w: %v", ErrClusterConnection, err)
 		}
+		f.restConfig = restConfig
 	}
 
-	client, err := kubernetes.NewForConfig(restConfig)
+	client, err := kubernetes.NewForConfig(f.restConfig)
 	if err != nil {
 		return nil, fmt.Errorf("%w: failed to create kubernetes client: %v", ErrClusterConnection, err)
 	}
 
-	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	dynamicClient, err := dynamic.NewForConfig(f.restConfig)
 	if err != nil {
 		return nil, fmt.Errorf("%w: failed to create dynamic client: %v", ErrClusterConnection, err)
 	}
 
-	f := &Framework{
-		client:                  client,
-		dynamicClient:           dynamicClient,
-		restConfig:              restConfig,
+	f.client = client
+	f.dynamicClient = dynamicClient
+	return f, nil
+}
+
+// forNamespace returns a Framework scoped to another namespace that shares
+// clients, context, logger, and configuration with f
+func (f *Framework) forNamespace(namespace string) *Framework {
+	return &Framework{
+		client:                  f.client,
+		dynamicClient:           f.dynamicClient,
+		restConfig:              f.restConfig,
 		namespace:               namespace,
-		ctx:                     ctx,
-		logger:                  slog.Default(),
-		config:                  config.FromEnv(),
+		ctx:                     f.ctx,
+		logger:                  f.logger,
+		config:                  f.config,
 		trackedCRs:              make([]TrackedResource, 0),
 		trackedClusterResources: make([]TrackedResource, 0),
+		metricsProvider:         f.metricsProvider,
+		metricsCollector:        f.metricsCollector,
+		loadRunner:              f.loadRunner,
+		reportGenerator:         f.reportGenerator,
+		tracerProvider:          f.tracerProvider,
+		retryStats:              f.retryStats,
 	}
+}
 
-	// Apply options
-	for _, opt := range opts {
-		opt(f)
+// CloneForNamespace returns a Framework targeting another namespace, e.g. to deploy
+// several independent Tempo instances from one orchestrator. The clone shares the
+// clients, context, configuration, pluggable subsystems, run ID, namespace quota,
+// Tempo node selector and retry stats of f, without reading the kubeconfig again.
+// It tracks its own resources, so its Cleanup only removes what was created
+// through it.
+func (f *Framework) CloneForNamespace(namespace string) (*Framework, error) {
+	if err := validateNamespace(namespace); err != nil {
+		return nil, err
 	}
-
-	return f, nil
+	clone := f.forNamespace(namespace)
+	clone.runID = f.runID
+	clone.tempoNodeSelector = f.GetTempoNodeSelector()
+	clone.namespaceQuota = f.namespaceQuota
+	clone.clockOffset = f.clockOffset
+	clone.kubeconfigPath = f.kubeconfigPath
+	clone.kubeContext = f.kubeContext
+	return clone, nil
 }
 
 // Namespace returns the namespace used by this framework instance
@@ -111,6 +240,11 @@ func (f *Framework) Namespace() string {
 	return f.namespace
 }
 
+// RunID returns the run ID of this framework instance, or an empty string if none was set
+func (f *Framework) RunID() string {
+	return f.runID
+}
+
 // Client returns the Kubernetes client
 func (f *Framework) Client() kubernetes.Interface {
 	return f.client
@@ -136,6 +270,21 @@ func (f *Framework) Context() context.Context {
 	return f.ctx
 }
 
+// SetContext replaces the context of later operations, e.g. to collect results
+// and clean up after the run context expired
+func (f *Framework) SetContext(ctx context.Context) {
+	if f.tracerProvider != nil {
+		ctx = tracing.ContextWithTracerProvider(ctx, f.tracerProvider)
+	}
+	f.ctx = ctx
+}
+
+// RetryStats returns the retry stats of the run. Pass it to retry.WithMetrics to
+// include the retries of an operation in the run report.
+func (f *Framework) RetryStats() *retry.Stats {
+	return f.retryStats
+}
+
 // Logger returns the logger
 func (f *Framework) Logger() *slog.Logger {
 	return f.logger
@@ -143,10 +292,14 @@ func (f *Framework) Logger() *slog.Logger {
 
 // GetManagedLabels returns the labels that should be applied to all resources created by this framework
 func (f *Framework) GetManagedLabels() map[string]string {
-	return map[string]string{
+	labels := map[string]string{
 		LabelManagedBy: LabelManagedByValue,
 		LabelInstance:  f.namespace,
 	}
+	if f.runID != "" {
+		labels[LabelRunID] = f.runID
+	}
+	return labels
 }
 
 // TrackCR adds a custom resource to the tracked resources list
@@ -211,3 +364,63 @@ func (f *Framework) GetTempoNodeSelector() map[string]string {
 	}
 	return result
 }
+
+// SetIngestionAuth stores how the OTel Collector authenticates to Tempo
+func (f *Framework) SetIngestionAuth(auth string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.ingestionAuth = auth
+}
+
+// GetIngestionAuth returns how the OTel Collector authenticates to Tempo.
+// Empty means the default ServiceAccount token.
+func (f *Framework) GetIngestionAuth() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.ingestionAuth
+}
+
+// SetCollectorImage pins the image of the OTel Collector
+func (f *Framework) SetCollectorImage(image string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.collectorImage = image
+}
+
+// GetCollectorImage returns the pinned OTel Collector image.
+// Empty means the default of the OpenTelemetry operator.
+func (f *Framework) GetCollectorImage() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.collectorImage
+}
+
+// SetIngestionProtocol stores the OTLP protocol the OTel Collector exports to Tempo with
+func (f *Framework) SetIngestionProtocol(protocol string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.ingestionProtocol = protocol
+}
+
+// GetIngestionProtocol returns the OTLP protocol the OTel Collector exports to
+// Tempo with. Empty means gRPC.
+func (f *Framework) GetIngestionProtocol() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.ingestionProtocol
+}
+
+// SetKafka stores the Kafka the OTel Collector routes the ingestion through
+func (f *Framework) SetKafka(kafka *KafkaConfig) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.kafka = kafka
+}
+
+// GetKafka returns the Kafka the OTel Collector routes the ingestion through.
+// Nil means the collector sends the traces to Tempo directly.
+func (f *Framework) GetKafka() *KafkaConfig {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.kafka
+}
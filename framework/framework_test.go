@@ -0,0 +1,64 @@
+package framework
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/gvr"
+)
+
+func TestCloneForNamespace(t *testing.T) {
+	f := newOrphanTestFramework()
+	f.runID = "a1b2c3"
+	f.clockOffset = 2 * time.Second
+	WithNamespaceQuota(&NamespaceQuota{Pods: 40})(f)
+	f.SetTempoNodeSelector(map[string]string{"node-role.kubernetes.io/infra": ""})
+	f.TrackCR(gvr.TempoStack, "tempo-perf-a", "tempostack")
+
+	clone, err := f.CloneForNamespace("tempo-perf-b")
+	if err != nil {
+		t.Fatalf("CloneForNamespace() error = %v", err)
+	}
+	if clone.Namespace() != "tempo-perf-b" || f.Namespace() != "" {
+		t.Errorf("expected only the clone to target tempo-perf-b, got %q and %q", clone.Namespace(), f.Namespace())
+	}
+	if clone.Client() != f.Client() || clone.FrameworkConfig() != f.FrameworkConfig() || clone.RetryStats() != f.RetryStats() {
+		t.Error("expected the clone to share the client, configuration and retry stats")
+	}
+	if labels := clone.GetManagedLabels(); labels[LabelRunID] != "a1b2c3" || labels[LabelInstance] != "tempo-perf-b" {
+		t.Errorf("expected the run ID and the namespace of the clone in its labels, got %v", labels)
+	}
+	if clone.namespaceQuota == nil || clone.namespaceQuotaApplied {
+		t.Errorf("expected the quota to be kept but not yet applied, got %+v", clone.namespaceQuota)
+	}
+	if _, ok := clone.GetTempoNodeSelector()["node-role.kubernetes.io/infra"]; !ok {
+		t.Errorf("expected the Tempo node selector of the original, got %v", clone.GetTempoNodeSelector())
+	}
+	if clone.clockOffset != f.clockOffset {
+		t.Errorf("expected the clock offset %s, got %s", f.clockOffset, clone.clockOffset)
+	}
+
+	clone.TrackCR(gvr.TempoMonolithic, "tempo-perf-b", "simplest")
+	if got := f.GetTrackedCRs(); len(got) != 1 || got[0].Name != "tempostack" {
+		t.Errorf("expected the original to keep its own tracked CRs, got %+v", got)
+	}
+	if got := clone.GetTrackedCRs(); len(got) != 1 || got[0].Name != "simplest" {
+		t.Errorf("expected the clone to track only its own CRs, got %+v", got)
+	}
+}
+
+func TestCloneForNamespace_EmptyNamespace(t *testing.T) {
+	if _, err := newOrphanTestFramework().CloneForNamespace(""); !errors.Is(err, ErrNamespaceRequired) {
+		t.Errorf("expected ErrNamespaceRequired, got %v", err)
+	}
+}
+
+func TestCloneForNamespace_InvalidNamespace(t *testing.T) {
+	for _, namespace := range []string{"Tempo-Perf", "tempo_perf", "tempo-perf-", strings.Repeat("a", 64)} {
+		if _, err := newOrphanTestFramework().CloneForNamespace(namespace); err == nil {
+			t.Errorf("expected an error for namespace %q", namespace)
+		}
+	}
+}
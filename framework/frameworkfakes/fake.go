@@ -0,0 +1,305 @@
+// Package frameworkfakes provides a test double of framework.Interface, to unit
+// test orchestration code built on the framework without a cluster.
+package frameworkfakes
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework"
+	"github.com/redhat/perf-tests-tempo/test/framework/k6"
+	"github.com/redhat/perf-tests-tempo/test/framework/metrics"
+)
+
+// Call is a recorded call of a Fake method
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+// Fake is a test double of framework.Interface. Each method records its call and
+// returns the result of the matching Func field when it is set. Otherwise it
+// succeeds: setup and collection return nil, the k6 tests return successful
+// results and the prerequisites are met. A Fake is safe for concurrent use.
+type Fake struct {
+	// NamespaceValue and RunIDValue are returned by Namespace and RunID
+	NamespaceValue string
+	RunIDValue     string
+	// Ctx is returned by Context. Default: context.Background()
+	Ctx context.Context
+
+	EnsureNamespaceFunc            func() error
+	CheckPrerequisitesFunc         func() (*framework.PrerequisitesResult, error)
+	SetupMinIOFunc                 func(config *framework.MinIOConfig) error
+	SetupTempoFunc                 func(variant string, resources *framework.ResourceConfig) error
+	SetupOTelCollectorFunc         func(tempoVariant string) error
+	SetupTenantCollectorFunc       func(tempoVariant, tenant string) (string, error)
+	SetupAlertRulesFunc            func() error
+	RunK6TestFunc                  func(testType k6.TestType, config *k6.Config) (*k6.Result, error)
+	RunK6ParallelTestsFunc         func(config *k6.Config) (*k6.ParallelResult, error)
+	CollectMetricsFunc             func(testStart time.Time, outputPath string) error
+	CollectMetricsWithDurationFunc func(duration time.Duration, outputPath string) error
+	CollectAlertsFunc              func(testStart time.Time) ([]metrics.FiringAlert, error)
+	CollectLogsFunc                func(config *framework.LogCollectionConfig) (*framework.LogCollectionResult, error)
+	GenerateDashboardFunc          func(csvPath, outputPath, profileName string) error
+	CleanupLoadOnlyFunc            func() error
+	CleanupFunc                    func() error
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+// Fake implements framework.Interface
+var _ framework.Interface = (*Fake)(nil)
+
+// New returns a Fake for namespace
+func New(namespace string) *Fake {
+	return &Fake{NamespaceValue: namespace}
+}
+
+// record records a call of method
+func (f *Fake) record(method string, args ...interface{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, Call{Method: method, Args: args})
+}
+
+// Calls returns the recorded calls in call order
+func (f *Fake) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	calls := make([]Call, len(f.calls))
+	copy(calls, f.calls)
+	return calls
+}
+
+// Methods returns the names of the called methods in call order, e.g. to check
+// that setup runs before the load
+func (f *Fake) Methods() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	methods := make([]string, len(f.calls))
+	for i, c := range f.calls {
+		methods[i] = c.Method
+	}
+	return methods
+}
+
+// CallCount returns how many times method was called
+func (f *Fake) CallCount(method string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	count := 0
+	for _, c := range f.calls {
+		if c.Method == method {
+			count++
+		}
+	}
+	return count
+}
+
+// Namespace returns NamespaceValue
+func (f *Fake) Namespace() string {
+	return f.NamespaceValue
+}
+
+// RunID returns RunIDValue
+func (f *Fake) RunID() string {
+	return f.RunIDValue
+}
+
+// Context returns Ctx, or context.Background() when it is not set
+func (f *Fake) Context() context.Context {
+	if f.Ctx == nil {
+		return context.Background()
+	}
+	return f.Ctx
+}
+
+// EnsureNamespace records the call and returns EnsureNamespaceFunc()
+func (f *Fake) EnsureNamespace() error {
+	f.record("EnsureNamespace")
+	if f.EnsureNamespaceFunc != nil {
+		return f.EnsureNamespaceFunc()
+	}
+	return nil
+}
+
+// CheckPrerequisites records the call and returns CheckPrerequisitesFunc(), or
+// met prerequisites
+func (f *Fake) CheckPrerequisites() (*framework.PrerequisitesResult, error) {
+	f.record("CheckPrerequisites")
+	if f.CheckPrerequisitesFunc != nil {
+		return f.CheckPrerequisitesFunc()
+	}
+	return &framework.PrerequisitesResult{AllMet: true}, nil
+}
+
+// SetupMinIO records the call and returns SetupMinIOFunc(nil)
+func (f *Fake) SetupMinIO() error {
+	f.record("SetupMinIO")
+	if f.SetupMinIOFunc != nil {
+		return f.SetupMinIOFunc(nil)
+	}
+	return nil
+}
+
+// SetupMinIOWithConfig records the call and returns SetupMinIOFunc(config)
+func (f *Fake) SetupMinIOWithConfig(config *framework.MinIOConfig) error {
+	f.record("SetupMinIOWithConfig", config)
+	if f.SetupMinIOFunc != nil {
+		return f.SetupMinIOFunc(config)
+	}
+	return nil
+}
+
+// SetupTempo records the call and returns SetupTempoFunc(variant, resources)
+func (f *Fake) SetupTempo(variant string, resources *framework.ResourceConfig) error {
+	f.record("SetupTempo", variant, resources)
+	if f.SetupTempoFunc != nil {
+		return f.SetupTempoFunc(variant, resources)
+	}
+	return nil
+}
+
+// SetupOTelCollector records the call and returns SetupOTelCollectorFunc(tempoVariant)
+func (f *Fake) SetupOTelCollector(tempoVariant string) error {
+	f.record("SetupOTelCollector", tempoVariant)
+	if f.SetupOTelCollectorFunc != nil {
+		return f.SetupOTelCollectorFunc(tempoVariant)
+	}
+	return nil
+}
+
+// SetupTenantCollector records the call and returns
+// SetupTenantCollectorFunc(tempoVariant, tenant), or an empty endpoint
+func (f *Fake) SetupTenantCollector(tempoVariant, tenant string) (string, error) {
+	f.record("SetupTenantCollector", tempoVariant, tenant)
+	if f.SetupTenantCollectorFunc != nil {
+		return f.SetupTenantCollectorFunc(tempoVariant, tenant)
+	}
+	return "", nil
+}
+
+// SetupAlertRules records the call and returns SetupAlertRulesFunc()
+func (f *Fake) SetupAlertRules() error {
+	f.record("SetupAlertRules")
+	if f.SetupAlertRulesFunc != nil {
+		return f.SetupAlertRulesFunc()
+	}
+	return nil
+}
+
+// RunK6Test records the call and returns RunK6TestFunc(testType, config), or a
+// successful result
+func (f *Fake) RunK6Test(testType k6.TestType, config *k6.Config) (*k6.Result, error) {
+	f.record("RunK6Test", testType, config)
+	return f.runK6Test(testType, config)
+}
+
+// RunK6IngestionTest records the call and runs an ingestion test of size like RunK6Test
+func (f *Fake) RunK6IngestionTest(size k6.Size) (*k6.Result, error) {
+	f.record("RunK6IngestionTest", size)
+	return f.runK6Test(k6.TestIngestion, &k6.Config{Size: size})
+}
+
+// RunK6QueryTest records the call and runs a query test of size like RunK6Test
+func (f *Fake) RunK6QueryTest(size k6.Size) (*k6.Result, error) {
+	f.record("RunK6QueryTest", size)
+	return f.runK6Test(k6.TestQuery, &k6.Config{Size: size})
+}
+
+// RunK6CombinedTest records the call and runs a combined test of size like RunK6Test
+func (f *Fake) RunK6CombinedTest(size k6.Size) (*k6.Result, error) {
+	f.record("RunK6CombinedTest", size)
+	return f.runK6Test(k6.TestCombined, &k6.Config{Size: size})
+}
+
+// runK6Test returns RunK6TestFunc(testType, config), or a successful result
+func (f *Fake) runK6Test(testType k6.TestType, config *k6.Config) (*k6.Result, error) {
+	if f.RunK6TestFunc != nil {
+		return f.RunK6TestFunc(testType, config)
+	}
+	return &k6.Result{Success: true}, nil
+}
+
+// RunK6ParallelTests records the call and returns RunK6ParallelTestsFunc(config),
+// or successful ingestion and query results
+func (f *Fake) RunK6ParallelTests(config *k6.Config) (*k6.ParallelResult, error) {
+	f.record("RunK6ParallelTests", config)
+	if f.RunK6ParallelTestsFunc != nil {
+		return f.RunK6ParallelTestsFunc(config)
+	}
+	return &k6.ParallelResult{Ingestion: &k6.Result{Success: true}, Query: &k6.Result{Success: true}}, nil
+}
+
+// CollectMetrics records the call and returns CollectMetricsFunc(testStart, outputPath).
+// No file is written unless the func writes one.
+func (f *Fake) CollectMetrics(testStart time.Time, outputPath string) error {
+	f.record("CollectMetrics", testStart, outputPath)
+	if f.CollectMetricsFunc != nil {
+		return f.CollectMetricsFunc(testStart, outputPath)
+	}
+	return nil
+}
+
+// CollectMetricsWithDuration records the call and returns
+// CollectMetricsWithDurationFunc(duration, outputPath); the options are ignored
+func (f *Fake) CollectMetricsWithDuration(duration time.Duration, outputPath string, opts ...metrics.CollectOption) error {
+	f.record("CollectMetricsWithDuration", duration, outputPath)
+	if f.CollectMetricsWithDurationFunc != nil {
+		return f.CollectMetricsWithDurationFunc(duration, outputPath)
+	}
+	return nil
+}
+
+// CollectAlerts records the call and returns CollectAlertsFunc(testStart), or no alerts
+func (f *Fake) CollectAlerts(testStart time.Time) ([]metrics.FiringAlert, error) {
+	f.record("CollectAlerts", testStart)
+	if f.CollectAlertsFunc != nil {
+		return f.CollectAlertsFunc(testStart)
+	}
+	return nil, nil
+}
+
+// CollectLogs records the call and returns CollectLogsFunc(config), or no logs
+func (f *Fake) CollectLogs(config *framework.LogCollectionConfig) (*framework.LogCollectionResult, error) {
+	f.record("CollectLogs", config)
+	if f.CollectLogsFunc != nil {
+		return f.CollectLogsFunc(config)
+	}
+	result := &framework.LogCollectionResult{Namespace: f.NamespaceValue, Timestamp: time.Now()}
+	if config != nil {
+		result.OutputDir = config.OutputDir
+	}
+	return result, nil
+}
+
+// GenerateDashboard records the call and returns
+// GenerateDashboardFunc(csvPath, outputPath, profileName)
+func (f *Fake) GenerateDashboard(csvPath, outputPath, profileName string) error {
+	f.record("GenerateDashboard", csvPath, outputPath, profileName)
+	if f.GenerateDashboardFunc != nil {
+		return f.GenerateDashboardFunc(csvPath, outputPath, profileName)
+	}
+	return nil
+}
+
+// CleanupLoadOnly records the call and returns CleanupLoadOnlyFunc()
+func (f *Fake) CleanupLoadOnly() error {
+	f.record("CleanupLoadOnly")
+	if f.CleanupLoadOnlyFunc != nil {
+		return f.CleanupLoadOnlyFunc()
+	}
+	return nil
+}
+
+// Cleanup records the call and returns CleanupFunc(); the options are ignored
+func (f *Fake) Cleanup(opts ...framework.CleanupOption) error {
+	f.record("Cleanup")
+	if f.CleanupFunc != nil {
+		return f.CleanupFunc()
+	}
+	return nil
+}
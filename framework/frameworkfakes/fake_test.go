@@ -0,0 +1,80 @@
+package frameworkfakes
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/redhat/perf-tests-tempo/test/framework"
+	"github.com/redhat/perf-tests-tempo/test/framework/k6"
+)
+
+// setupAndRun is orchestration code written against framework.Interface
+func setupAndRun(fw framework.Interface) (*k6.Result, error) {
+	if err := fw.SetupTempo("monolithic", nil); err != nil {
+		return nil, err
+	}
+	defer fw.Cleanup()
+	return fw.RunK6IngestionTest(k6.SizeSmall)
+}
+
+func TestFake_Defaults(t *testing.T) {
+	fake := New("perf-test")
+
+	result, err := setupAndRun(fake)
+	if err != nil {
+		t.Fatalf("setupAndRun() error = %v", err)
+	}
+	if !result.Success {
+		t.Error("expected a successful default k6 result")
+	}
+	if fake.Namespace() != "perf-test" || fake.Context() == nil {
+		t.Errorf("expected namespace perf-test and a context, got %q", fake.Namespace())
+	}
+
+	methods := fake.Methods()
+	want := []string{"SetupTempo", "RunK6IngestionTest", "Cleanup"}
+	if len(methods) != len(want) {
+		t.Fatalf("expected calls %v, got %v", want, methods)
+	}
+	for i := range want {
+		if methods[i] != want[i] {
+			t.Errorf("expected call %d to be %s, got %s", i, want[i], methods[i])
+		}
+	}
+	if args := fake.Calls()[0].Args; args[0] != "monolithic" {
+		t.Errorf("expected SetupTempo to record variant monolithic, got %v", args)
+	}
+}
+
+func TestFake_Funcs(t *testing.T) {
+	setupErr := errors.New("tempo not ready")
+	fake := New("perf-test")
+	fake.SetupTempoFunc = func(variant string, resources *framework.ResourceConfig) error {
+		return setupErr
+	}
+
+	if _, err := setupAndRun(fake); !errors.Is(err, setupErr) {
+		t.Errorf("expected the SetupTempoFunc error, got %v", err)
+	}
+	if fake.CallCount("RunK6IngestionTest") != 0 || fake.CallCount("Cleanup") != 0 {
+		t.Errorf("expected no load or cleanup after a failed setup, got %v", fake.Methods())
+	}
+}
+
+func TestFake_RunK6TestFuncCoversSizedTests(t *testing.T) {
+	fake := New("perf-test")
+	var gotType k6.TestType
+	var gotSize k6.Size
+	fake.RunK6TestFunc = func(testType k6.TestType, config *k6.Config) (*k6.Result, error) {
+		gotType, gotSize = testType, config.Size
+		return &k6.Result{Success: false}, nil
+	}
+
+	result, err := fake.RunK6QueryTest(k6.SizeMedium)
+	if err != nil || result.Success {
+		t.Fatalf("expected the failed RunK6TestFunc result, got %+v, %v", result, err)
+	}
+	if gotType != k6.TestQuery || gotSize != k6.SizeMedium {
+		t.Errorf("expected a medium query test, got %s %s", gotSize, gotType)
+	}
+}
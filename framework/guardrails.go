@@ -0,0 +1,152 @@
+package framework
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/config"
+	"github.com/redhat/perf-tests-tempo/test/framework/gvr"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ProductionClusterLabels mark a cluster as production. Clusters whose
+// Infrastructure object (or kube-system namespace) carries one of them are refused.
+var ProductionClusterLabels = []string{
+	"environment=production",
+	"env=production",
+}
+
+// ClusterIdentity identifies the cluster the framework is connected to
+type ClusterIdentity struct {
+	// Name is the OpenShift infrastructure name, empty on other clusters
+	Name string
+	// APIServer is the host of the API server
+	APIServer string
+	// Labels are the labels of the Infrastructure object on OpenShift,
+	// or of the kube-system namespace on other clusters
+	Labels map[string]string
+	// LabelsUnavailable is set when neither object could be read due to missing
+	// permissions; the cluster is then only identified by its API server
+	LabelsUnavailable bool
+}
+
+// String returns a human-readable description of the cluster
+func (c *ClusterIdentity) String() string {
+	if c.Name != "" {
+		return fmt.Sprintf("%s (%s)", c.Name, c.APIServer)
+	}
+	return c.APIServer
+}
+
+// ClusterIdentity looks up the identity of the cluster the framework is connected to.
+// Users without access to the Infrastructure object fall back to the kube-system
+// namespace; without access to either, only the API server host is known.
+func (f *Framework) ClusterIdentity() (*ClusterIdentity, error) {
+	id := &ClusterIdentity{}
+	if f.restConfig != nil {
+		id.APIServer = apiServerHost(f.restConfig.Host)
+	}
+
+	infra, err := f.dynamicClient.Resource(gvr.Infrastructure).Get(f.ctx, "cluster", metav1.GetOptions{})
+	if err == nil {
+		id.Name, _, _ = unstructured.NestedString(infra.Object, "status", "infrastructureName")
+		id.Labels = infra.GetLabels()
+		return id, nil
+	}
+	if apierrors.IsForbidden(err) {
+		f.logger.Warn("no access to the cluster infrastructure, using kube-system labels", "error", err)
+	} else if !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get cluster infrastructure: %w", err)
+	}
+
+	// Not OpenShift: fall back to the labels of kube-system
+	ns, err := f.client.CoreV1().Namespaces().Get(f.ctx, "kube-system", metav1.GetOptions{})
+	if apierrors.IsForbidden(err) {
+		f.logger.Warn("no access to the kube-system namespace, identifying cluster by API server only", "error", err)
+		id.LabelsUnavailable = true
+		return id, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get kube-system namespace: %w", err)
+	}
+	id.Labels = ns.Labels
+	return id, nil
+}
+
+// CheckClusterSafety refuses to operate on clusters that are denylisted, labeled
+// as production, missing from a configured allowlist, or missing the required label.
+// Setting AllowUnsafeCluster in the config overrides the check.
+func (f *Framework) CheckClusterSafety() error {
+	if f.config.AllowUnsafeCluster {
+		f.logger.Warn("cluster safety guardrails overridden")
+		return nil
+	}
+
+	id, err := f.ClusterIdentity()
+	if err != nil {
+		return fmt.Errorf("%w: cannot identify cluster: %v", ErrUnsafeCluster, err)
+	}
+
+	return checkClusterSafety(id, f.config)
+}
+
+// checkClusterSafety applies the guardrails of cfg to a cluster identity
+func checkClusterSafety(id *ClusterIdentity, cfg *config.Config) error {
+	if matchesCluster(id, cfg.ClusterDenylist) {
+		return fmt.Errorf("%w: cluster %s is denylisted", ErrUnsafeCluster, id)
+	}
+
+	// Production labels cannot be ruled out, so only an explicitly allowlisted cluster is safe
+	if id.LabelsUnavailable && !matchesCluster(id, cfg.ClusterAllowlist) {
+		return fmt.Errorf("%w: cannot read the labels of cluster %s; grant get on infrastructures.config.openshift.io or the kube-system namespace, or add the cluster to the allowlist", ErrUnsafeCluster, id)
+	}
+
+	for _, label := range ProductionClusterLabels {
+		if hasLabel(id.Labels, label) {
+			return fmt.Errorf("%w: cluster %s is labeled %s", ErrUnsafeCluster, id, label)
+		}
+	}
+
+	if len(cfg.ClusterAllowlist) > 0 && !matchesCluster(id, cfg.ClusterAllowlist) {
+		return fmt.Errorf("%w: cluster %s is not in the allowlist", ErrUnsafeCluster, id)
+	}
+
+	if cfg.RequiredClusterLabel != "" && !hasLabel(id.Labels, cfg.RequiredClusterLabel) {
+		return fmt.Errorf("%w: cluster %s is missing required label %s", ErrUnsafeCluster, id, cfg.RequiredClusterLabel)
+	}
+
+	return nil
+}
+
+// matchesCluster reports whether the cluster name or API server host is in the list
+func matchesCluster(id *ClusterIdentity, list []string) bool {
+	for _, entry := range list {
+		entry = apiServerHost(entry)
+		if (id.Name != "" && strings.EqualFold(entry, id.Name)) || strings.EqualFold(entry, id.APIServer) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasLabel reports whether labels contain a "key=value" pair, or the key for a bare "key"
+func hasLabel(labels map[string]string, label string) bool {
+	key, value, hasValue := strings.Cut(label, "=")
+	actual, ok := labels[key]
+	if !ok {
+		return false
+	}
+	return !hasValue || actual == value
+}
+
+// apiServerHost strips the scheme and path from an API server URL
+func apiServerHost(server string) string {
+	if u, err := url.Parse(server); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return server
+}
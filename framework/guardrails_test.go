@@ -0,0 +1,205 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/config"
+	"github.com/redhat/perf-tests-tempo/test/framework/k6"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestCheckClusterSafety(t *testing.T) {
+	id := &ClusterIdentity{
+		Name:      "perf-lab-x7k2p",
+		APIServer: "api.perf-lab.example.com:6443",
+		Labels:    map[string]string{"tempo-perf-test.io/allowed": "true"},
+	}
+
+	tests := []struct {
+		name   string
+		id     *ClusterIdentity
+		cfg    *config.Config
+		unsafe bool
+	}{
+		{"no guardrails configured", id, &config.Config{}, false},
+		{"denylisted by name", id, &config.Config{ClusterDenylist: []string{"PERF-LAB-X7K2P"}}, true},
+		{"denylisted by API server URL", id, &config.Config{ClusterDenylist: []string{"https://api.perf-lab.example.com:6443"}}, true},
+		{"production label", &ClusterIdentity{APIServer: "api.prod", Labels: map[string]string{"environment": "production"}}, &config.Config{}, true},
+		{"allowlisted", id, &config.Config{ClusterAllowlist: []string{"api.perf-lab.example.com:6443"}}, false},
+		{"not allowlisted", id, &config.Config{ClusterAllowlist: []string{"other-cluster"}}, true},
+		{"required label present", id, &config.Config{RequiredClusterLabel: "tempo-perf-test.io/allowed=true"}, false},
+		{"required label key only", id, &config.Config{RequiredClusterLabel: "tempo-perf-test.io/allowed"}, false},
+		{"required label wrong value", id, &config.Config{RequiredClusterLabel: "tempo-perf-test.io/allowed=yes"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkClusterSafety(tt.id, tt.cfg)
+			if tt.unsafe && !errors.Is(err, ErrUnsafeCluster) {
+				t.Errorf("expected ErrUnsafeCluster, got %v", err)
+			}
+			if !tt.unsafe && err != nil {
+				t.Errorf("expected cluster to be safe, got %v", err)
+			}
+		})
+	}
+}
+
+func TestClusterIdentity_OpenShift(t *testing.T) {
+	infra := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "config.openshift.io/v1",
+		"kind":       "Infrastructure",
+		"metadata": map[string]interface{}{
+			"name":   "cluster",
+			"labels": map[string]interface{}{"environment": "production"},
+		},
+		"status": map[string]interface{}{"infrastructureName": "prod-east-4fz8q"},
+	}}
+
+	f := &Framework{
+		client:        fake.NewSimpleClientset(),
+		dynamicClient: dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), infra),
+		restConfig:    &rest.Config{Host: "https://api.prod-east.example.com:6443"},
+		ctx:           context.Background(),
+		logger:        slog.Default(),
+		config:        config.Default(),
+	}
+
+	id, err := f.ClusterIdentity()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if id.Name != "prod-east-4fz8q" || id.APIServer != "api.prod-east.example.com:6443" {
+		t.Errorf("unexpected identity %+v", id)
+	}
+	if err := f.CheckClusterSafety(); !errors.Is(err, ErrUnsafeCluster) {
+		t.Errorf("expected production cluster to be refused, got %v", err)
+	}
+
+	f.config = f.config.WithAllowUnsafeCluster(true)
+	if err := f.CheckClusterSafety(); err != nil {
+		t.Errorf("expected override to allow cluster, got %v", err)
+	}
+}
+
+func TestClusterIdentity_KubernetesFallback(t *testing.T) {
+	f := &Framework{
+		client: fake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   "kube-system",
+			Labels: map[string]string{"tempo-perf-test.io/allowed": "true"},
+		}}),
+		dynamicClient: dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), nil),
+		ctx:           context.Background(),
+		logger:        slog.Default(),
+		config:        &config.Config{RequiredClusterLabel: "tempo-perf-test.io/allowed=true"},
+	}
+
+	id, err := f.ClusterIdentity()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if id.Name != "" || id.Labels["tempo-perf-test.io/allowed"] != "true" {
+		t.Errorf("expected kube-system labels without a name, got %+v", id)
+	}
+	if err := f.CheckClusterSafety(); err != nil {
+		t.Errorf("expected labeled cluster to be safe, got %v", err)
+	}
+}
+
+// forbidGet makes get requests for a resource fail with Forbidden
+func forbidGet(resource string) k8stesting.ReactionFunc {
+	return func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: resource}, "", errors.New("access denied"))
+	}
+}
+
+func TestClusterIdentity_InfrastructureForbidden(t *testing.T) {
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), nil)
+	dynamicClient.PrependReactor("get", "infrastructures", forbidGet("infrastructures"))
+	f := &Framework{
+		client: fake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   "kube-system",
+			Labels: map[string]string{"environment": "production"},
+		}}),
+		dynamicClient: dynamicClient,
+		ctx:           context.Background(),
+		logger:        slog.Default(),
+		config:        config.Default(),
+	}
+
+	id, err := f.ClusterIdentity()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if id.LabelsUnavailable || id.Labels["environment"] != "production" {
+		t.Errorf("expected kube-system labels, got %+v", id)
+	}
+	if err := f.CheckClusterSafety(); !errors.Is(err, ErrUnsafeCluster) {
+		t.Errorf("expected production cluster to be refused, got %v", err)
+	}
+}
+
+func TestClusterIdentity_AllForbidden(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("get", "namespaces", forbidGet("namespaces"))
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), nil)
+	dynamicClient.PrependReactor("get", "infrastructures", forbidGet("infrastructures"))
+	f := &Framework{
+		client:        client,
+		dynamicClient: dynamicClient,
+		restConfig:    &rest.Config{Host: "https://api.perf-lab.example.com:6443"},
+		ctx:           context.Background(),
+		logger:        slog.Default(),
+		config:        config.Default(),
+	}
+
+	id, err := f.ClusterIdentity()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !id.LabelsUnavailable || id.APIServer != "api.perf-lab.example.com:6443" {
+		t.Errorf("expected identity by API server only, got %+v", id)
+	}
+	if err := f.CheckClusterSafety(); !errors.Is(err, ErrUnsafeCluster) {
+		t.Errorf("expected unidentifiable cluster to be refused, got %v", err)
+	}
+
+	f.config = &config.Config{ClusterAllowlist: []string{"api.perf-lab.example.com:6443"}}
+	if err := f.CheckClusterSafety(); err != nil {
+		t.Errorf("expected allowlisted cluster to be safe, got %v", err)
+	}
+}
+
+func TestSetupK6PrometheusMetrics_RefusesUnsafeCluster(t *testing.T) {
+	f := &Framework{
+		client: fake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   "kube-system",
+			Labels: map[string]string{"environment": "production"},
+		}}),
+		dynamicClient: dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), nil),
+		ctx:           context.Background(),
+		logger:        slog.Default(),
+		config:        config.Default(),
+	}
+
+	if _, err := f.SetupK6PrometheusMetrics(); !errors.Is(err, ErrUnsafeCluster) {
+		t.Fatalf("expected ErrUnsafeCluster, got %v", err)
+	}
+	_, err := f.client.CoreV1().ConfigMaps(k6.OpenShiftMonitoringNamespace).Get(context.Background(), k6.UserWorkloadConfigMapName, metav1.GetOptions{})
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("expected %s to be left untouched, got %v", k6.UserWorkloadConfigMapName, err)
+	}
+}
@@ -151,6 +151,16 @@ var (
 	}
 )
 
+// OpenShift config resources
+var (
+	// Infrastructure is the GVR for the cluster-scoped OpenShift Infrastructure config
+	Infrastructure = schema.GroupVersionResource{
+		Group:    "config.openshift.io",
+		Version:  "v1",
+		Resource: "infrastructures",
+	}
+)
+
 // API Extensions
 var (
 	// CustomResourceDefinition is the GVR for CRD resources
@@ -161,6 +171,23 @@ var (
 	}
 )
 
+// OLM resources
+var (
+	// Subscription is the GVR for OLM Subscription resources
+	Subscription = schema.GroupVersionResource{
+		Group:    "operators.coreos.com",
+		Version:  "v1alpha1",
+		Resource: "subscriptions",
+	}
+
+	// ClusterServiceVersion is the GVR for OLM ClusterServiceVersion resources
+	ClusterServiceVersion = schema.GroupVersionResource{
+		Group:    "operators.coreos.com",
+		Version:  "v1alpha1",
+		Resource: "clusterserviceversions",
+	}
+)
+
 // Monitoring resources
 var (
 	// ServiceMonitor is the GVR for Prometheus ServiceMonitor resources
@@ -176,6 +203,23 @@ var (
 		Version:  "v1",
 		Resource: "podmonitors",
 	}
+
+	// PrometheusRule is the GVR for Prometheus recording and alerting rules
+	PrometheusRule = schema.GroupVersionResource{
+		Group:    "monitoring.coreos.com",
+		Version:  "v1",
+		Resource: "prometheusrules",
+	}
+)
+
+// Resource metrics
+var (
+	// PodMetrics is the GVR for pod resource usage served by metrics-server
+	PodMetrics = schema.GroupVersionResource{
+		Group:    "metrics.k8s.io",
+		Version:  "v1beta1",
+		Resource: "pods",
+	}
 )
 
 // CRD names for prerequisite checks
@@ -90,6 +90,31 @@ func TestRouteGVR(t *testing.T) {
 	}
 }
 
+func TestInfrastructureGVR(t *testing.T) {
+	if Infrastructure.Group != "config.openshift.io" {
+		t.Errorf("expected Group 'config.openshift.io', got %q", Infrastructure.Group)
+	}
+	if Infrastructure.Resource != "infrastructures" {
+		t.Errorf("expected Resource 'infrastructures', got %q", Infrastructure.Resource)
+	}
+}
+
+func TestOLMGVRs(t *testing.T) {
+	if Subscription.Group != "operators.coreos.com" {
+		t.Errorf("expected Group 'operators.coreos.com', got %q", Subscription.Group)
+	}
+	if Subscription.Version != "v1alpha1" {
+		t.Errorf("expected Version 'v1alpha1', got %q", Subscription.Version)
+	}
+	if Subscription.Resource != "subscriptions" {
+		t.Errorf("expected Resource 'subscriptions', got %q", Subscription.Resource)
+	}
+
+	if ClusterServiceVersion.Resource != "clusterserviceversions" {
+		t.Errorf("expected Resource 'clusterserviceversions', got %q", ClusterServiceVersion.Resource)
+	}
+}
+
 func TestCRDConstants(t *testing.T) {
 	if TempoMonolithicCRD != "tempomonolithics.tempo.grafana.com" {
 		t.Errorf("expected TempoMonolithicCRD 'tempomonolithics.tempo.grafana.com', got %q", TempoMonolithicCRD)
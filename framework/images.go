@@ -0,0 +1,125 @@
+package framework
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/wait"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Pods and containers of the images that can be pinned
+const (
+	tempoPodSelector       = "app.kubernetes.io/managed-by=tempo-operator"
+	tempoContainer         = "tempo"
+	collectorPodSelector   = "app.kubernetes.io/managed-by=opentelemetry-operator"
+	collectorContainerName = "otc-container"
+)
+
+// verifyPinnedImage waits until the container of every pod matching selector runs
+// the pinned image, and fails when a pod still runs another one after the
+// PodReadyTimeout, e.g. because the operator substituted its own image
+func (f *Framework) verifyPinnedImage(component, selector, container, image string) error {
+	var mismatch string
+	err := wait.ForCondition(f.ctx, f.config.PodReadyPollInterval, f.config.PodReadyTimeout, func() (bool, error) {
+		statuses, err := f.containerStatuses(selector, container)
+		if err != nil {
+			return false, err
+		}
+		mismatch = ""
+		for pod, cs := range statuses {
+			if !imageMatches(image, cs.Image, cs.ImageID) {
+				mismatch = fmt.Sprintf("pod %s runs %s", pod, runningImage(cs))
+				return false, nil
+			}
+		}
+		return len(statuses) > 0, nil
+	})
+	if errors.Is(err, wait.ErrTimeout) {
+		if mismatch == "" {
+			return fmt.Errorf("no running %s pods to verify the pinned image %s", component, image)
+		}
+		return fmt.Errorf("%s does not run the pinned image %s: %s", component, image, mismatch)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to verify the %s image: %w", component, err)
+	}
+
+	f.logger.Info("Verified pinned image", "component", component, "image", image)
+	return nil
+}
+
+// RunningImages returns the images run by the Tempo and OTel Collector containers,
+// with the digest the kubelet resolved, keyed by "tempo" and "otel-collector".
+// Components without running pods are omitted.
+func (f *Framework) RunningImages() (map[string]string, error) {
+	images := make(map[string]string)
+	for component, target := range map[string][2]string{
+		"tempo":          {tempoPodSelector, tempoContainer},
+		"otel-collector": {collectorPodSelector, collectorContainerName},
+	} {
+		statuses, err := f.containerStatuses(target[0], target[1])
+		if err != nil {
+			return nil, err
+		}
+		for _, cs := range statuses {
+			images[component] = runningImage(cs)
+			break
+		}
+	}
+	return images, nil
+}
+
+// containerStatuses returns the status of container in each running pod matching
+// selector, keyed by pod name. Terminating pods are skipped.
+func (f *Framework) containerStatuses(selector, container string) (map[string]corev1.ContainerStatus, error) {
+	pods, err := f.client.CoreV1().Pods(f.namespace).List(f.ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	statuses := make(map[string]corev1.ContainerStatus)
+	for _, pod := range pods.Items {
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.Name == container && cs.ImageID != "" {
+				statuses[pod.Name] = cs
+			}
+		}
+	}
+	return statuses, nil
+}
+
+// imageMatches reports whether a container with the given image and image ID runs
+// pinned. A digest is matched against the resolved image ID, a tag against the
+// image reference.
+func imageMatches(pinned, image, imageID string) bool {
+	if _, digest, ok := strings.Cut(pinned, "@"); ok {
+		return strings.HasSuffix(imageID, "@"+digest) || normalizeImage(image) == normalizeImage(pinned)
+	}
+	return normalizeImage(image) == normalizeImage(pinned)
+}
+
+// runningImage returns the image of a container with the digest it resolved to,
+// e.g. quay.io/org/tempo:2.6.0@sha256:...
+func runningImage(cs corev1.ContainerStatus) string {
+	if strings.Contains(cs.Image, "@") {
+		return cs.Image
+	}
+	if _, digest, ok := strings.Cut(cs.ImageID, "@"); ok {
+		return cs.Image + "@" + digest
+	}
+	return cs.Image
+}
+
+// normalizeImage drops the implicit Docker Hub registry and library namespace the
+// kubelet may add to an image reference
+func normalizeImage(image string) string {
+	image = strings.TrimPrefix(image, "docker.io/")
+	return strings.TrimPrefix(image, "library/")
+}
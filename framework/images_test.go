@@ -0,0 +1,90 @@
+package framework
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/config"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const testDigest = "sha256:3f1c9a7e0b5d2c4e6f8a0b1c2d3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a8b9c0d1e"
+
+func TestImageMatches(t *testing.T) {
+	tests := []struct {
+		name           string
+		pinned         string
+		image, imageID string
+		want           bool
+	}{
+		{"same tag", "quay.io/org/tempo:2.6.0", "quay.io/org/tempo:2.6.0", "quay.io/org/tempo@" + testDigest, true},
+		{"other tag", "quay.io/org/tempo:2.6.0", "quay.io/org/tempo:2.5.0", "quay.io/org/tempo@" + testDigest, false},
+		{"implicit registry", "grafana/tempo:2.6.0", "docker.io/grafana/tempo:2.6.0", "", true},
+		{"resolved digest", "quay.io/org/tempo@" + testDigest, "quay.io/org/tempo:2.6.0", "quay.io/org/tempo@" + testDigest, true},
+		{"other digest", "quay.io/org/tempo@" + testDigest, "quay.io/org/tempo:2.6.0", "quay.io/org/tempo@sha256:0000", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := imageMatches(tt.pinned, tt.image, tt.imageID); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// newImageTestFramework creates a framework with a Tempo pod running image
+func newImageTestFramework(image string) *Framework {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "tempo-simplest-0",
+			Namespace: "perf",
+			Labels:    map[string]string{"app.kubernetes.io/managed-by": "tempo-operator"},
+		},
+		Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+			Name:    "tempo",
+			Image:   image,
+			ImageID: "quay.io/org/tempo@" + testDigest,
+		}}},
+	}
+	cfg := config.Default().WithPodReadyTimeout(20 * time.Millisecond)
+	cfg.PodReadyPollInterval = time.Millisecond
+	return &Framework{
+		client:    fake.NewSimpleClientset(pod),
+		namespace: "perf",
+		ctx:       context.Background(),
+		logger:    slog.Default(),
+		config:    cfg,
+	}
+}
+
+func TestVerifyPinnedImage_Substituted(t *testing.T) {
+	f := newImageTestFramework("registry.redhat.io/rhosdt/tempo-rhel8:0.15")
+	err := f.verifyPinnedImage("Tempo", tempoPodSelector, tempoContainer, "quay.io/org/tempo:2.6.0")
+	if err == nil || !strings.Contains(err.Error(), "tempo-rhel8") {
+		t.Errorf("expected the substituted image to be reported, got %v", err)
+	}
+
+	if err := f.verifyPinnedImage("Tempo", tempoPodSelector, tempoContainer, "registry.redhat.io/rhosdt/tempo-rhel8:0.15"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestRunningImages(t *testing.T) {
+	f := newImageTestFramework("quay.io/org/tempo:2.6.0")
+	images, err := f.RunningImages()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if images["tempo"] != "quay.io/org/tempo:2.6.0@"+testDigest {
+		t.Errorf("expected the Tempo image with its digest, got %v", images)
+	}
+	if _, ok := images["otel-collector"]; ok {
+		t.Errorf("expected no collector image without collector pods, got %v", images)
+	}
+}
@@ -0,0 +1,47 @@
+package framework
+
+import (
+	"context"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/k6"
+	"github.com/redhat/perf-tests-tempo/test/framework/metrics"
+)
+
+// Interface is the surface of a Framework used to orchestrate a run: setting up
+// the test stack, running the load, collecting the results and cleaning up.
+// Orchestration code that accepts it instead of *Framework can be unit tested
+// with frameworkfakes.Fake, without a cluster.
+type Interface interface {
+	Namespace() string
+	RunID() string
+	Context() context.Context
+
+	EnsureNamespace() error
+	CheckPrerequisites() (*PrerequisitesResult, error)
+
+	SetupMinIO() error
+	SetupMinIOWithConfig(config *MinIOConfig) error
+	SetupTempo(variant string, resources *ResourceConfig) error
+	SetupOTelCollector(tempoVariant string) error
+	SetupTenantCollector(tempoVariant, tenant string) (string, error)
+	SetupAlertRules() error
+
+	RunK6Test(testType k6.TestType, config *k6.Config) (*k6.Result, error)
+	RunK6IngestionTest(size k6.Size) (*k6.Result, error)
+	RunK6QueryTest(size k6.Size) (*k6.Result, error)
+	RunK6CombinedTest(size k6.Size) (*k6.Result, error)
+	RunK6ParallelTests(config *k6.Config) (*k6.ParallelResult, error)
+
+	CollectMetrics(testStart time.Time, outputPath string) error
+	CollectMetricsWithDuration(duration time.Duration, outputPath string, opts ...metrics.CollectOption) error
+	CollectAlerts(testStart time.Time) ([]metrics.FiringAlert, error)
+	CollectLogs(config *LogCollectionConfig) (*LogCollectionResult, error)
+	GenerateDashboard(csvPath, outputPath, profileName string) error
+
+	CleanupLoadOnly() error
+	Cleanup(opts ...CleanupOption) error
+}
+
+// Framework implements Interface
+var _ Interface = (*Framework)(nil)
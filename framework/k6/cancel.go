@@ -0,0 +1,63 @@
+package k6
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// ErrCancelled is returned when the test context is cancelled while a k6 Job runs
+var ErrCancelled = errors.New("k6 test cancelled")
+
+// cancelTimeout bounds the log capture and Job deletion of a cancelled test, which
+// run after the test context is done
+const cancelTimeout = 2 * time.Minute
+
+// isCancelled returns true if the test context was cancelled, as opposed to the
+// Job wait timing out
+func isCancelled(c Clients) bool {
+	return c.Context().Err() != nil
+}
+
+// cancelJob stops an interrupted k6 Job: it captures the logs written so far, then
+// deletes the Job with foreground propagation and waits until it is gone, so no
+// generator keeps sending load to Tempo. It returns the captured logs.
+func cancelJob(c Clients, jobName string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), cancelTimeout)
+	defer cancel()
+
+	fmt.Printf("🛑 Test cancelled, stopping k6 Job %s...\n", jobName)
+	logs, err := getJobLogs(ctx, c, jobName)
+	if err != nil {
+		fmt.Printf("Warning: failed to get Job logs: %v\n", err)
+	}
+
+	jobs := c.Client().BatchV1().Jobs(c.Namespace())
+	propagation := metav1.DeletePropagationForeground
+	err = jobs.Delete(ctx, jobName, metav1.DeleteOptions{PropagationPolicy: &propagation})
+	if err != nil && !apierrors.IsNotFound(err) {
+		fmt.Printf("Warning: failed to delete k6 Job %s: %v\n", jobName, err)
+		return logs
+	}
+
+	// With foreground propagation the Job is only removed once its pods are gone
+	err = wait.PollUntilContextCancel(ctx, 2*time.Second, true, func(ctx context.Context) (bool, error) {
+		_, err := jobs.Get(ctx, jobName, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	})
+	if err != nil {
+		fmt.Printf("Warning: k6 Job %s may still be running: %v\n", jobName, err)
+		return logs
+	}
+
+	fmt.Printf("🛑 Deleted k6 Job %s\n", jobName)
+	return logs
+}
@@ -0,0 +1,68 @@
+package k6
+
+import (
+	"errors"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// LoadSelector selects the Jobs, pods and ConfigMaps of the k6 tests
+const LoadSelector = "app=k6-perf-test"
+
+// Cleanup deletes the k6 Jobs, their pods, and the k6 ConfigMaps (scripts, service CA,
+// replay data, query corpus) in the namespace. The ServiceAccount and RBAC of the query tests are
+// kept, so later tests against the same deployment reuse them.
+func Cleanup(c Clients) error {
+	namespace := c.Namespace()
+	client := c.Client()
+	ctx := c.Context()
+	listOptions := metav1.ListOptions{LabelSelector: LoadSelector}
+	propagation := metav1.DeletePropagationBackground
+	deleteOptions := metav1.DeleteOptions{PropagationPolicy: &propagation}
+
+	var errs []error
+	deleted := 0
+
+	jobs, err := client.BatchV1().Jobs(namespace).List(ctx, listOptions)
+	if err != nil {
+		return fmt.Errorf("failed to list k6 jobs: %w", err)
+	}
+	for _, job := range jobs.Items {
+		if err := client.BatchV1().Jobs(namespace).Delete(ctx, job.Name, deleteOptions); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("failed to delete job %s: %w", job.Name, err))
+			continue
+		}
+		deleted++
+	}
+
+	// Pods are removed with their Job in the background; delete them now so no
+	// generator keeps sending load
+	pods, err := client.CoreV1().Pods(namespace).List(ctx, listOptions)
+	if err != nil {
+		return fmt.Errorf("failed to list k6 pods: %w", err)
+	}
+	for _, pod := range pods.Items {
+		if err := client.CoreV1().Pods(namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("failed to delete pod %s: %w", pod.Name, err))
+		}
+	}
+
+	configMaps, err := client.CoreV1().ConfigMaps(namespace).List(ctx, listOptions)
+	if err != nil {
+		return fmt.Errorf("failed to list k6 ConfigMaps: %w", err)
+	}
+	for _, cm := range configMaps.Items {
+		if err := client.CoreV1().ConfigMaps(namespace).Delete(ctx, cm.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("failed to delete ConfigMap %s: %w", cm.Name, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	fmt.Printf("🧹 Deleted %d k6 jobs and %d ConfigMaps\n", deleted, len(configMaps.Items))
+	return nil
+}
@@ -30,6 +30,7 @@ func GetPrometheusRemoteWriteURL() string {
 
 // EnablePrometheusRemoteWriteReceiver enables the remote write receiver in user workload monitoring
 // This allows k6 to push metrics directly to Prometheus
+// It edits a cluster-wide ConfigMap; callers must check the cluster is safe to modify first
 func EnablePrometheusRemoteWriteReceiver(ctx context.Context, client kubernetes.Interface) error {
 	configMapName := UserWorkloadConfigMapName
 	namespace := OpenShiftMonitoringNamespace
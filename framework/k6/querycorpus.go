@@ -0,0 +1,148 @@
+package k6
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// QueryCorpusConfigMap is the name of the ConfigMap holding the query corpus
+	QueryCorpusConfigMap = "k6-query-corpus"
+
+	// QueryCorpusSuffix is the file name suffix of a generated query corpus in a
+	// results directory
+	QueryCorpusSuffix = "-query-corpus.json"
+
+	// queryCorpusMountDir is where the corpus is mounted in the k6 container
+	queryCorpusMountDir = "/query-corpus"
+)
+
+// CorpusQuery is a TraceQL search run by the query test
+type CorpusQuery struct {
+	Query string `json:"query"`
+	// Limit is the maximum number of traces returned by the search
+	Limit int `json:"limit"`
+}
+
+// QueryCorpus is a set of TraceQL searches the query test picks from at random,
+// instead of its built-in queries
+type QueryCorpus struct {
+	// GeneratedAt is when the corpus was sampled from Tempo, if it was generated
+	GeneratedAt time.Time     `json:"generated_at,omitempty"`
+	Queries     []CorpusQuery `json:"queries"`
+}
+
+// WriteQueryCorpus writes a query corpus as JSON
+func WriteQueryCorpus(corpus *QueryCorpus, outputPath string) error {
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	data, err := json.MarshalIndent(corpus, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode query corpus: %w", err)
+	}
+	if err := os.WriteFile(outputPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write query corpus: %w", err)
+	}
+	return nil
+}
+
+// LoadQueryCorpus reads and validates a query corpus written by WriteQueryCorpus
+func LoadQueryCorpus(corpusPath string) (*QueryCorpus, error) {
+	data, err := os.ReadFile(corpusPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read query corpus: %w", err)
+	}
+	var corpus QueryCorpus
+	if err := json.Unmarshal(data, &corpus); err != nil {
+		return nil, fmt.Errorf("failed to parse query corpus %s: %w", corpusPath, err)
+	}
+	if err := validateQueryCorpus(&corpus); err != nil {
+		return nil, fmt.Errorf("invalid query corpus %s: %w", corpusPath, err)
+	}
+	return &corpus, nil
+}
+
+// validateQueryCorpus checks that a corpus has queries the query test can run
+func validateQueryCorpus(corpus *QueryCorpus) error {
+	if len(corpus.Queries) == 0 {
+		return fmt.Errorf("no queries")
+	}
+	for i, q := range corpus.Queries {
+		if q.Query == "" {
+			return fmt.Errorf("query %d is empty", i)
+		}
+		if q.Limit <= 0 {
+			return fmt.Errorf("query %d (%s) has no positive limit", i, q.Query)
+		}
+	}
+	return nil
+}
+
+// usesQueryCorpus reports whether a test runs the searches of the query corpus
+func usesQueryCorpus(testType TestType, config *Config) bool {
+	return config.QueryCorpusFile != "" && (testType == TestQuery || testType == TestCombined)
+}
+
+// createQueryCorpusConfigMap uploads a query corpus file to the query corpus ConfigMap
+func createQueryCorpusConfigMap(c Clients, corpusPath string) error {
+	corpus, err := LoadQueryCorpus(corpusPath)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(corpus)
+	if err != nil {
+		return fmt.Errorf("failed to encode query corpus: %w", err)
+	}
+
+	namespace := c.Namespace()
+	client := c.Client()
+	ctx := c.Context()
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      QueryCorpusConfigMap,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app":       "k6-perf-test",
+				"component": "query-corpus",
+			},
+		},
+		Data: map[string]string{
+			filepath.Base(corpusPath): string(data),
+		},
+	}
+
+	// Delete existing ConfigMap if it exists
+	_ = client.CoreV1().ConfigMaps(namespace).Delete(ctx, QueryCorpusConfigMap, metav1.DeleteOptions{})
+
+	if _, err := client.CoreV1().ConfigMaps(namespace).Create(ctx, configMap, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to create ConfigMap: %w", err)
+	}
+
+	fmt.Printf("🔎 Created ConfigMap %s with %d queries\n", QueryCorpusConfigMap, len(corpus.Queries))
+	return nil
+}
+
+// queryCorpusVolume returns the volume holding the query corpus, its mount in the
+// k6 container and the environment pointing the query scripts to it
+func queryCorpusVolume(config *Config) (corev1.Volume, corev1.VolumeMount, corev1.EnvVar) {
+	volume := corev1.Volume{
+		Name: "query-corpus",
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: QueryCorpusConfigMap},
+			},
+		},
+	}
+	mount := corev1.VolumeMount{Name: "query-corpus", MountPath: queryCorpusMountDir, ReadOnly: true}
+	env := corev1.EnvVar{Name: "QUERY_CORPUS_FILE", Value: path.Join(queryCorpusMountDir, filepath.Base(config.QueryCorpusFile))}
+	return volume, mount, env
+}
@@ -0,0 +1,59 @@
+package k6
+
+import (
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+)
+
+// BuildJob returns the Job RunTest creates for testType in namespace, without
+// touching the cluster. The defaults of RunTest are applied to a copy of config.
+func BuildJob(namespace string, tempoNodeSelector map[string]string, testType TestType, config *Config) (*batchv1.Job, error) {
+	cfg := copyConfig(config)
+	setTestDefaults(cfg, namespace)
+	if testType == TestReplay {
+		if err := validateReplayConfig(cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	checksum, err := renderedScriptsChecksum()
+	if err != nil {
+		return nil, err
+	}
+	return buildJob(namespace, tempoNodeSelector, jobName(testType, cfg), testType, cfg, checksum), nil
+}
+
+// BuildParallelJobs returns the ingestion and query Jobs RunParallelTests creates in
+// namespace, without touching the cluster
+func BuildParallelJobs(namespace string, tempoNodeSelector map[string]string, config *Config) ([]*batchv1.Job, error) {
+	cfg := copyConfig(config)
+	setDefaults(cfg, namespace)
+
+	checksum, err := renderedScriptsChecksum()
+	if err != nil {
+		return nil, err
+	}
+	return []*batchv1.Job{
+		buildJob(namespace, tempoNodeSelector, jobName(TestIngestion, cfg), TestIngestion, cfg, checksum),
+		buildJob(namespace, tempoNodeSelector, jobName(TestQuery, cfg), TestQuery, cfg, checksum),
+	}, nil
+}
+
+// copyConfig returns a copy of config, or the default config if it is nil
+func copyConfig(config *Config) *Config {
+	if config == nil {
+		return &Config{Size: SizeMedium}
+	}
+	cfg := *config
+	return &cfg
+}
+
+// renderedScriptsChecksum returns the checksum the scripts ConfigMap would be labeled with
+func renderedScriptsChecksum() (string, error) {
+	data, err := readScripts()
+	if err != nil {
+		return "", fmt.Errorf("failed to read k6 scripts: %w", err)
+	}
+	return ScriptsChecksum(data), nil
+}
@@ -0,0 +1,189 @@
+package k6
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ReplayConfigMap is the name of the ConfigMap holding a local trace capture
+	ReplayConfigMap = "k6-replay-data"
+
+	// ReplayConfigMapMaxBytes is the largest capture uploaded in a ConfigMap.
+	// ConfigMaps are limited to 1MiB including metadata; use a PVC for larger captures.
+	ReplayConfigMapMaxBytes = 1000 * 1024
+
+	// DefaultReplayPath is the path of the capture within the replay PVC
+	DefaultReplayPath = "traces.json"
+
+	// replayMountDir is where the capture is mounted in the k6 container
+	replayMountDir = "/replay"
+)
+
+// ReplayCapture summarizes an OTLP JSON trace capture
+type ReplayCapture struct {
+	Documents int
+	Traces    int
+	Spans     int
+}
+
+// otlpTraceDocument is the part of an OTLP JSON ExportTraceServiceRequest needed to
+// count traces and spans
+type otlpTraceDocument struct {
+	ResourceSpans []struct {
+		ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+		// InstrumentationLibrarySpans is the pre-1.0 name of scopeSpans
+		InstrumentationLibrarySpans []otlpScopeSpans `json:"instrumentationLibrarySpans"`
+	} `json:"resourceSpans"`
+}
+
+type otlpScopeSpans struct {
+	Spans []struct {
+		TraceID string `json:"traceId"`
+	} `json:"spans"`
+}
+
+// ParseReplayCapture checks that r holds OTLP JSON traces: one ExportTraceServiceRequest
+// document, or one per line as written by the OpenTelemetry Collector file exporter.
+// Protobuf captures are not supported; the file exporter writes JSON by default.
+func ParseReplayCapture(r io.Reader) (*ReplayCapture, error) {
+	var capture ReplayCapture
+	traceIDs := make(map[string]bool)
+
+	decoder := json.NewDecoder(r)
+	for {
+		var doc otlpTraceDocument
+		err := decoder.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			if capture.Documents == 0 {
+				return nil, fmt.Errorf("not an OTLP JSON trace capture (protobuf captures are not supported): %w", err)
+			}
+			return nil, fmt.Errorf("invalid OTLP JSON document %d: %w", capture.Documents+1, err)
+		}
+		capture.Documents++
+
+		for _, rs := range doc.ResourceSpans {
+			for _, ss := range append(rs.ScopeSpans, rs.InstrumentationLibrarySpans...) {
+				for _, span := range ss.Spans {
+					traceIDs[span.TraceID] = true
+					capture.Spans++
+				}
+			}
+		}
+	}
+
+	if capture.Spans == 0 {
+		return nil, fmt.Errorf("trace capture has no spans")
+	}
+	capture.Traces = len(traceIDs)
+	return &capture, nil
+}
+
+// validateReplayConfig checks that the replay test has exactly one capture source
+func validateReplayConfig(config *Config) error {
+	if (config.ReplayFile == "") == (config.ReplayPVC == "") {
+		return fmt.Errorf("the replay test needs either a replay file or a replay PVC")
+	}
+	if config.ReplaySpeedup < 0 {
+		return fmt.Errorf("replay speedup cannot be negative")
+	}
+	return nil
+}
+
+// createReplayConfigMap uploads a local trace capture to the replay ConfigMap
+func createReplayConfigMap(c Clients, capturePath string) error {
+	data, err := os.ReadFile(capturePath)
+	if err != nil {
+		return fmt.Errorf("failed to read trace capture: %w", err)
+	}
+	if len(data) > ReplayConfigMapMaxBytes {
+		return fmt.Errorf("trace capture %s is %d bytes, more than the %d bytes a ConfigMap can hold; store it in a PVC instead",
+			capturePath, len(data), ReplayConfigMapMaxBytes)
+	}
+
+	capture, err := ParseReplayCapture(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("invalid trace capture %s: %w", capturePath, err)
+	}
+
+	namespace := c.Namespace()
+	client := c.Client()
+	ctx := c.Context()
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ReplayConfigMap,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app":       "k6-perf-test",
+				"component": "replay-data",
+			},
+		},
+		BinaryData: map[string][]byte{
+			filepath.Base(capturePath): data,
+		},
+	}
+
+	// Delete existing ConfigMap if it exists
+	_ = client.CoreV1().ConfigMaps(namespace).Delete(ctx, ReplayConfigMap, metav1.DeleteOptions{})
+
+	if _, err := client.CoreV1().ConfigMaps(namespace).Create(ctx, configMap, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to create ConfigMap: %w", err)
+	}
+
+	fmt.Printf("📼 Created ConfigMap %s with %d traces (%d spans)\n", ReplayConfigMap, capture.Traces, capture.Spans)
+	return nil
+}
+
+// replayVolume returns the volume holding the trace capture, its mount in the k6
+// container and the path of the capture in the container
+func replayVolume(config *Config) (corev1.Volume, corev1.VolumeMount, string) {
+	volume := corev1.Volume{Name: "replay-data"}
+	mount := corev1.VolumeMount{Name: "replay-data", MountPath: replayMountDir, ReadOnly: true}
+
+	if config.ReplayPVC != "" {
+		volume.VolumeSource = corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: config.ReplayPVC,
+				ReadOnly:  true,
+			},
+		}
+		capturePath := config.ReplayPath
+		if capturePath == "" {
+			capturePath = DefaultReplayPath
+		}
+		return volume, mount, path.Join(replayMountDir, capturePath)
+	}
+
+	volume.VolumeSource = corev1.VolumeSource{
+		ConfigMap: &corev1.ConfigMapVolumeSource{
+			LocalObjectReference: corev1.LocalObjectReference{Name: ReplayConfigMap},
+		},
+	}
+	return volume, mount, path.Join(replayMountDir, filepath.Base(config.ReplayFile))
+}
+
+// replayEnv returns the environment of the replay test
+func replayEnv(config *Config, capturePath string) []corev1.EnvVar {
+	env := []corev1.EnvVar{
+		{Name: "REPLAY_FILE", Value: capturePath},
+		{Name: "OTLP_HTTP_ENDPOINT", Value: config.OTLPHTTPEndpoint},
+	}
+	if config.ReplaySpeedup > 0 {
+		env = append(env, corev1.EnvVar{Name: "REPLAY_SPEEDUP", Value: strconv.FormatFloat(config.ReplaySpeedup, 'f', -1, 64)})
+	}
+	return env
+}
@@ -7,15 +7,20 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/redhat/perf-tests-tempo/test/framework/gvr"
+
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 )
@@ -29,6 +34,8 @@ type Clients interface {
 	// GetTempoNodeSelector returns the node selector used for Tempo pods.
 	// Used to create anti-affinity for k6 jobs.
 	GetTempoNodeSelector() map[string]string
+	TrackClusterResource(gvr schema.GroupVersionResource, name string)
+	GetManagedLabels() map[string]string
 }
 
 // buildNodeAntiAffinity creates a NodeAffinity that prevents scheduling on nodes
@@ -80,46 +87,71 @@ func RunTest(c Clients, testType TestType, config *Config) (*Result, error) {
 	if config == nil {
 		config = &Config{Size: SizeMedium}
 	}
-	if config.Size == "" {
-		config.Size = SizeMedium
-	}
-	if config.Image == "" {
-		config.Image = DefaultImage
-	}
 
 	namespace := c.Namespace()
-
-	// Set default endpoints based on Tempo variant (using gateway for multitenancy)
-	if config.TempoEndpoint == "" || config.TempoQueryEndpoint == "" {
-		ingestion, query := getDefaultEndpoints(config.TempoVariant, namespace)
-		if config.TempoEndpoint == "" {
-			config.TempoEndpoint = ingestion
-		}
-		if config.TempoQueryEndpoint == "" {
-			config.TempoQueryEndpoint = query
-		}
-	}
-	// Default tenant for multitenancy mode
-	if config.TempoTenant == "" {
-		config.TempoTenant = DefaultTenant
-	}
+	setTestDefaults(config, namespace)
 
 	fmt.Printf("\n🚀 Deploying k6 %s test (size: %s)\n", testType, config.Size)
 	fmt.Printf("   Namespace: %s\n", namespace)
 	fmt.Printf("   Tempo Variant: %s\n", config.TempoVariant)
 	fmt.Printf("   Image: %s\n", config.Image)
-	fmt.Printf("   Ingestion Endpoint: %s\n", config.TempoEndpoint)
+	fmt.Printf("   Ingestion Endpoint: %s (OTLP %s)\n", config.TempoEndpoint, config.Protocol)
 	fmt.Printf("   Query Endpoint: %s\n", config.TempoQueryEndpoint)
+	if testType == TestJaeger {
+		fmt.Printf("   Jaeger Endpoint: %s\n", config.JaegerQueryEndpoint)
+	}
+	if usesQueryCorpus(testType, config) {
+		fmt.Printf("   Query Corpus: %s\n", config.QueryCorpusFile)
+	}
+	if testType == TestReplay {
+		fmt.Printf("   OTLP/HTTP Endpoint: %s\n", config.OTLPHTTPEndpoint)
+		if config.ReplayPVC != "" {
+			fmt.Printf("   Replay Capture: PVC %s\n", config.ReplayPVC)
+		} else {
+			fmt.Printf("   Replay Capture: %s\n", config.ReplayFile)
+		}
+	}
 	fmt.Printf("   Tenant: %s\n\n", config.TempoTenant)
 
+	if testType == TestReplay {
+		if err := validateReplayConfig(config); err != nil {
+			return nil, err
+		}
+	}
+
 	// Create ConfigMap with k6 scripts
-	if err := createScriptsConfigMap(c); err != nil {
+	checksum, err := createScriptsConfigMap(c)
+	if err != nil {
 		return nil, fmt.Errorf("failed to create k6 scripts ConfigMap: %w", err)
 	}
 
+	if usesQueryCorpus(testType, config) {
+		if err := createQueryCorpusConfigMap(c, config.QueryCorpusFile); err != nil {
+			return nil, fmt.Errorf("failed to create query corpus ConfigMap: %w", err)
+		}
+	}
+
+	// A local capture is uploaded to a ConfigMap; a PVC is mounted as is
+	if testType == TestReplay && config.ReplayFile != "" {
+		if err := createReplayConfigMap(c, config.ReplayFile); err != nil {
+			return nil, fmt.Errorf("failed to create replay ConfigMap: %w", err)
+		}
+	}
+
+	// The Jaeger API is only served through the gateway, which needs the service CA
+	// and a ServiceAccount allowed to read traces
+	if testType == TestJaeger {
+		if err := createServiceCAConfigMap(c); err != nil {
+			return nil, fmt.Errorf("failed to create service CA ConfigMap: %w", err)
+		}
+		if err := setupK6RBAC(c, config.TempoTenant); err != nil {
+			return nil, fmt.Errorf("failed to setup k6 RBAC: %w", err)
+		}
+	}
+
 	// Create and run k6 Job
-	jobName := fmt.Sprintf("k6-%s-%s", testType, config.Size)
-	if err := createJob(c, jobName, testType, config); err != nil {
+	jobName := jobName(testType, config)
+	if err := createJob(c, jobName, testType, config, checksum); err != nil {
 		return nil, fmt.Errorf("failed to create k6 Job: %w", err)
 	}
 
@@ -128,11 +160,25 @@ func RunTest(c Clients, testType TestType, config *Config) (*Result, error) {
 	fmt.Printf("⏳ Waiting for k6 Job to complete (timeout: %s)...\n", timeout)
 	success, err := waitForJob(c, jobName, timeout)
 	if err != nil {
-		return nil, fmt.Errorf("error waiting for k6 Job: %w", err)
+		if !isCancelled(c) {
+			return nil, fmt.Errorf("error waiting for k6 Job: %w", err)
+		}
+		// Stop the load generator instead of leaving it running in the cluster
+		logs := cancelJob(c, jobName)
+		result := &Result{
+			Cancelled:       true,
+			Output:          logs,
+			Duration:        time.Since(startTime),
+			Metrics:         ParseK6Metrics(logs),
+			ScriptsChecksum: checksum,
+			FrameworkSHA:    FrameworkSHA(),
+		}
+		result.Error = fmt.Errorf("%w after %s", ErrCancelled, result.Duration.Round(time.Second))
+		return result, result.Error
 	}
 
 	// Get logs from Job pod
-	logs, err := getJobLogs(c, jobName)
+	logs, err := getJobLogs(c.Context(), c, jobName)
 	if err != nil {
 		fmt.Printf("Warning: failed to get Job logs: %v\n", err)
 		logs = "(logs unavailable)"
@@ -144,10 +190,12 @@ func RunTest(c Clients, testType TestType, config *Config) (*Result, error) {
 	k6Metrics := ParseK6Metrics(logs)
 
 	result := &Result{
-		Success:  success,
-		Output:   logs,
-		Duration: duration,
-		Metrics:  k6Metrics,
+		Success:         success,
+		Output:          logs,
+		Duration:        duration,
+		Metrics:         k6Metrics,
+		ScriptsChecksum: checksum,
+		FrameworkSHA:    FrameworkSHA(),
 	}
 
 	if !success {
@@ -164,7 +212,16 @@ func RunTest(c Clients, testType TestType, config *Config) (*Result, error) {
 		}
 		if k6Metrics.IngestionTracesTotal > 0 {
 			fmt.Printf("   Traces Ingested: %.0f\n", k6Metrics.IngestionTracesTotal)
-			fmt.Printf("   Ingestion Rate: %.2f MB/s\n", k6Metrics.IngestionRateBPS/1024/1024)
+			// The replay test sends at the recorded pace and has no target rate
+			if k6Metrics.IngestionRateBPS > 0 {
+				fmt.Printf("   Ingestion Rate: %.2f MB/s\n", k6Metrics.IngestionRateBPS/1024/1024)
+			}
+		}
+		if k6Metrics.JaegerRequestsTotal > 0 {
+			fmt.Printf("   Jaeger Requests: %.0f (failures: %.0f)\n", k6Metrics.JaegerRequestsTotal, k6Metrics.JaegerFailuresTotal)
+			for _, endpoint := range sortedEndpoints(k6Metrics.JaegerRequestDuration) {
+				fmt.Printf("   Jaeger %s Latency P99: %.3fs\n", endpoint, k6Metrics.JaegerRequestDuration[endpoint].P99)
+			}
 		}
 	}
 
@@ -194,32 +251,68 @@ type ParallelResult struct {
 	Duration  time.Duration
 }
 
+// Cancelled returns true if either test was cancelled
+func (p *ParallelResult) Cancelled() bool {
+	return (p.Ingestion != nil && p.Ingestion.Cancelled) || (p.Query != nil && p.Query.Cancelled)
+}
+
 // Success returns true if both tests succeeded
 func (p *ParallelResult) Success() bool {
 	return p.Ingestion != nil && p.Query != nil &&
 		p.Ingestion.Success && p.Query.Success
 }
 
+// ThresholdsFailed returns true if either test crossed a k6 threshold
+func (p *ParallelResult) ThresholdsFailed() bool {
+	return p.Ingestion.ThresholdsFailed() || p.Query.ThresholdsFailed()
+}
+
 // ServiceCAConfigMap is the name of the ConfigMap for OpenShift service CA
 const ServiceCAConfigMap = "k6-service-ca"
 
+// serviceCAKey is the key OpenShift injects the service CA bundle under
+const serviceCAKey = "service-ca.crt"
+
 // K6ServiceAccount is the name of the ServiceAccount for k6 pods
 const K6ServiceAccount = "k6-query-sa"
 
-// setupK6RBAC creates ServiceAccount and RBAC for k6 query pods to access Tempo
-func setupK6RBAC(c Clients) error {
+// rbacLabels returns the labels for k6 RBAC resources. The managed labels let
+// cleanup and orphan cleanup find the cluster-scoped ones.
+func rbacLabels(c Clients) map[string]string {
+	labels := map[string]string{
+		"app": "k6-perf-test",
+	}
+	for k, v := range c.GetManagedLabels() {
+		labels[k] = v
+	}
+	return labels
+}
+
+// jobName returns the name of the Job of a k6 test. The Jobs of tenants other than
+// DefaultTenant are suffixed with the tenant, so the loads of several tenants can
+// run at once.
+func jobName(testType TestType, config *Config) string {
+	name := fmt.Sprintf("k6-%s-%s", testType, config.Size)
+	if config.TempoTenant != "" && config.TempoTenant != DefaultTenant {
+		name += "-" + config.TempoTenant
+	}
+	return name
+}
+
+// setupK6RBAC creates ServiceAccount and RBAC for k6 query pods to read the traces
+// of a tenant
+func setupK6RBAC(c Clients, tenant string) error {
 	namespace := c.Namespace()
 	client := c.Client()
 	ctx := c.Context()
+	labels := rbacLabels(c)
 
 	// Create ServiceAccount
 	sa := &corev1.ServiceAccount{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      K6ServiceAccount,
 			Namespace: namespace,
-			Labels: map[string]string{
-				"app": "k6-perf-test",
-			},
+			Labels:    labels,
 		},
 	}
 	_, err := client.CoreV1().ServiceAccounts(namespace).Create(ctx, sa, metav1.CreateOptions{})
@@ -227,19 +320,20 @@ func setupK6RBAC(c Clients) error {
 		return fmt.Errorf("failed to create ServiceAccount: %w", err)
 	}
 
-	// Create ClusterRole for reading traces from tenant-1
+	// Create ClusterRole for reading traces from the tenant
 	clusterRoleName := fmt.Sprintf("allow-read-traces-%s", namespace)
+	if tenant != DefaultTenant {
+		clusterRoleName = fmt.Sprintf("allow-read-traces-%s-%s", tenant, namespace)
+	}
 	clusterRole := &rbacv1.ClusterRole{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: clusterRoleName,
-			Labels: map[string]string{
-				"app": "k6-perf-test",
-			},
+			Name:   clusterRoleName,
+			Labels: labels,
 		},
 		Rules: []rbacv1.PolicyRule{
 			{
 				APIGroups:     []string{"tempo.grafana.com"},
-				Resources:     []string{DefaultTenant}, // tenant-1
+				Resources:     []string{tenant},
 				ResourceNames: []string{"traces"},
 				Verbs:         []string{"get"},
 			},
@@ -249,15 +343,14 @@ func setupK6RBAC(c Clients) error {
 	if err != nil && !apierrors.IsAlreadyExists(err) {
 		return fmt.Errorf("failed to create ClusterRole: %w", err)
 	}
+	c.TrackClusterResource(gvr.ClusterRole, clusterRoleName)
 
 	// Create ClusterRoleBinding
-	clusterRoleBindingName := fmt.Sprintf("allow-read-traces-%s", namespace)
+	clusterRoleBindingName := clusterRoleName
 	clusterRoleBinding := &rbacv1.ClusterRoleBinding{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: clusterRoleBindingName,
-			Labels: map[string]string{
-				"app": "k6-perf-test",
-			},
+			Name:   clusterRoleBindingName,
+			Labels: labels,
 		},
 		RoleRef: rbacv1.RoleRef{
 			APIGroup: "rbac.authorization.k8s.io",
@@ -276,8 +369,9 @@ func setupK6RBAC(c Clients) error {
 	if err != nil && !apierrors.IsAlreadyExists(err) {
 		return fmt.Errorf("failed to create ClusterRoleBinding: %w", err)
 	}
+	c.TrackClusterResource(gvr.ClusterRoleBinding, clusterRoleBindingName)
 
-	fmt.Printf("🔐 Created RBAC for k6 query (ServiceAccount: %s)\n", K6ServiceAccount)
+	fmt.Printf("🔐 Created RBAC for k6 query (ServiceAccount: %s, tenant: %s)\n", K6ServiceAccount, tenant)
 	return nil
 }
 
@@ -289,40 +383,21 @@ func RunParallelTests(c Clients, config *Config) (*ParallelResult, error) {
 	if config == nil {
 		config = &Config{Size: SizeMedium}
 	}
-	if config.Size == "" {
-		config.Size = SizeMedium
-	}
-	if config.Image == "" {
-		config.Image = DefaultImage
-	}
 
 	namespace := c.Namespace()
-
-	// Set default endpoints based on Tempo variant (using gateway for multitenancy)
-	if config.TempoEndpoint == "" || config.TempoQueryEndpoint == "" {
-		ingestion, query := getDefaultEndpoints(config.TempoVariant, namespace)
-		if config.TempoEndpoint == "" {
-			config.TempoEndpoint = ingestion
-		}
-		if config.TempoQueryEndpoint == "" {
-			config.TempoQueryEndpoint = query
-		}
-	}
-	// Default tenant for multitenancy mode
-	if config.TempoTenant == "" {
-		config.TempoTenant = DefaultTenant
-	}
+	setDefaults(config, namespace)
 
 	fmt.Printf("\n🚀 Deploying parallel k6 tests (ingestion + query)\n")
 	fmt.Printf("   Namespace: %s\n", namespace)
 	fmt.Printf("   Tempo Variant: %s\n", config.TempoVariant)
 	fmt.Printf("   Image: %s\n", config.Image)
-	fmt.Printf("   Ingestion Endpoint: %s\n", config.TempoEndpoint)
+	fmt.Printf("   Ingestion Endpoint: %s (OTLP %s)\n", config.TempoEndpoint, config.Protocol)
 	fmt.Printf("   Query Endpoint: %s\n", config.TempoQueryEndpoint)
 	fmt.Printf("   Tenant: %s\n\n", config.TempoTenant)
 
 	// Create ConfigMap with k6 scripts
-	if err := createScriptsConfigMap(c); err != nil {
+	checksum, err := createScriptsConfigMap(c)
+	if err != nil {
 		return nil, fmt.Errorf("failed to create k6 scripts ConfigMap: %w", err)
 	}
 
@@ -332,19 +407,36 @@ func RunParallelTests(c Clients, config *Config) (*ParallelResult, error) {
 	}
 
 	// Setup RBAC for k6 query pods
-	if err := setupK6RBAC(c); err != nil {
+	if err := setupK6RBAC(c, config.TempoTenant); err != nil {
 		return nil, fmt.Errorf("failed to setup k6 RBAC: %w", err)
 	}
 
 	// Create both jobs
-	ingestionJobName := fmt.Sprintf("k6-ingestion-%s", config.Size)
-	queryJobName := fmt.Sprintf("k6-query-%s", config.Size)
+	ingestionJobName := jobName(TestIngestion, config)
+	queryJobName := jobName(TestQuery, config)
 
-	if err := createJob(c, ingestionJobName, TestIngestion, config); err != nil {
+	if err := createJob(c, ingestionJobName, TestIngestion, config, checksum); err != nil {
 		return nil, fmt.Errorf("failed to create ingestion Job: %w", err)
 	}
 
-	if err := createJob(c, queryJobName, TestQuery, config); err != nil {
+	// The query corpus may be generated once traces are being ingested
+	if config.BeforeQuery != nil {
+		if err := config.BeforeQuery(config); err != nil {
+			fmt.Printf("Warning: running the built-in queries: %v\n", err)
+			config.QueryCorpusFile = ""
+		}
+	}
+	// The ingestion Job is stopped when the query Job cannot be started, rather
+	// than left running without a test waiting for it
+	if usesQueryCorpus(TestQuery, config) {
+		if err := createQueryCorpusConfigMap(c, config.QueryCorpusFile); err != nil {
+			cancelJob(c, ingestionJobName)
+			return nil, fmt.Errorf("failed to create query corpus ConfigMap: %w", err)
+		}
+	}
+
+	if err := createJob(c, queryJobName, TestQuery, config, checksum); err != nil {
+		cancelJob(c, ingestionJobName)
 		return nil, fmt.Errorf("failed to create query Job: %w", err)
 	}
 
@@ -353,35 +445,42 @@ func RunParallelTests(c Clients, config *Config) (*ParallelResult, error) {
 	fmt.Printf("⏳ Waiting for both k6 Jobs to complete (timeout: %s)...\n", timeout)
 
 	type jobResult struct {
-		name    string
-		success bool
-		logs    string
-		err     error
+		name      string
+		success   bool
+		cancelled bool
+		logs      string
+		err       error
 	}
 
 	results := make(chan jobResult, 2)
 
-	// Wait for ingestion job
-	go func() {
-		success, err := waitForJob(c, ingestionJobName, timeout)
-		logs, _ := getJobLogs(c, ingestionJobName)
-		results <- jobResult{name: "ingestion", success: success, logs: logs, err: err}
-	}()
+	// waitAndCollect waits for a job and collects its logs, deleting it if the
+	// test is cancelled
+	waitAndCollect := func(name, jobName string) {
+		success, err := waitForJob(c, jobName, timeout)
+		if err != nil && isCancelled(c) {
+			logs := cancelJob(c, jobName)
+			results <- jobResult{name: name, cancelled: true, logs: logs, err: fmt.Errorf("k6 %s test: %w", name, ErrCancelled)}
+			return
+		}
+		logs, _ := getJobLogs(c.Context(), c, jobName)
+		results <- jobResult{name: name, success: success, logs: logs, err: err}
+	}
 
-	// Wait for query job
-	go func() {
-		success, err := waitForJob(c, queryJobName, timeout)
-		logs, _ := getJobLogs(c, queryJobName)
-		results <- jobResult{name: "query", success: success, logs: logs, err: err}
-	}()
+	go waitAndCollect("ingestion", ingestionJobName)
+	go waitAndCollect("query", queryJobName)
 
 	// Collect results
 	parallelResult := &ParallelResult{}
 	for i := 0; i < 2; i++ {
 		r := <-results
 		result := &Result{
-			Success: r.success,
-			Output:  r.logs,
+			Success:         r.success,
+			Cancelled:       r.cancelled,
+			Output:          r.logs,
+			Metrics:         ParseK6Metrics(r.logs),
+			ScriptsChecksum: checksum,
+			FrameworkSHA:    FrameworkSHA(),
 		}
 		if r.err != nil {
 			result.Error = r.err
@@ -391,16 +490,22 @@ func RunParallelTests(c Clients, config *Config) (*ParallelResult, error) {
 
 		if r.name == "ingestion" {
 			parallelResult.Ingestion = result
-			if r.success {
+			switch {
+			case r.success:
 				fmt.Printf("✅ Ingestion test completed\n")
-			} else {
+			case r.cancelled:
+				fmt.Printf("🛑 Ingestion test cancelled\n")
+			default:
 				fmt.Printf("❌ Ingestion test failed\n")
 			}
 		} else {
 			parallelResult.Query = result
-			if r.success {
+			switch {
+			case r.success:
 				fmt.Printf("✅ Query test completed\n")
-			} else {
+			case r.cancelled:
+				fmt.Printf("🛑 Query test cancelled\n")
+			default:
 				fmt.Printf("❌ Query test failed\n")
 			}
 		}
@@ -408,6 +513,10 @@ func RunParallelTests(c Clients, config *Config) (*ParallelResult, error) {
 
 	parallelResult.Duration = time.Since(startTime)
 
+	if parallelResult.Cancelled() {
+		return parallelResult, fmt.Errorf("%w after %s", ErrCancelled, parallelResult.Duration.Round(time.Second))
+	}
+
 	if parallelResult.Success() {
 		fmt.Printf("\n✅ Both tests completed successfully in %s\n", parallelResult.Duration.Round(time.Second))
 	} else {
@@ -417,13 +526,55 @@ func RunParallelTests(c Clients, config *Config) (*ParallelResult, error) {
 	return parallelResult, nil
 }
 
-// createScriptsConfigMap creates a ConfigMap with all k6 test scripts
-func createScriptsConfigMap(c Clients) error {
-	scriptsDir := scriptsPath()
+// createScriptsConfigMap creates a ConfigMap with all k6 test scripts and returns the
+// checksum of its contents. A ConfigMap with the same scripts is kept, since Jobs of
+// another test may be mounting it.
+func createScriptsConfigMap(c Clients) (string, error) {
 	namespace := c.Namespace()
 	client := c.Client()
 	ctx := c.Context()
 
+	data, err := readScripts()
+	if err != nil {
+		return "", err
+	}
+	checksum := ScriptsChecksum(data)
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ScriptsConfigMap,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app":                "k6-perf-test",
+				"component":          "scripts",
+				LabelScriptsChecksum: scriptsChecksumLabel(checksum),
+			},
+		},
+		Data: data,
+	}
+
+	existing, err := client.CoreV1().ConfigMaps(namespace).Get(ctx, ScriptsConfigMap, metav1.GetOptions{})
+	if err == nil && existing.Labels[LabelScriptsChecksum] == scriptsChecksumLabel(checksum) {
+		fmt.Printf("📦 Reusing ConfigMap %s with k6 scripts (checksum %s)\n", ScriptsConfigMap, scriptsChecksumLabel(checksum))
+		return checksum, nil
+	}
+
+	// Delete existing ConfigMap if it exists
+	_ = client.CoreV1().ConfigMaps(namespace).Delete(ctx, ScriptsConfigMap, metav1.DeleteOptions{})
+
+	// Create new ConfigMap
+	_, err = client.CoreV1().ConfigMaps(namespace).Create(ctx, configMap, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to create ConfigMap: %w", err)
+	}
+
+	fmt.Printf("📦 Created ConfigMap %s with k6 scripts (checksum %s)\n", ScriptsConfigMap, scriptsChecksumLabel(checksum))
+	return checksum, nil
+}
+
+// readScripts reads the k6 scripts, keyed by their ConfigMap key
+func readScripts() (map[string]string, error) {
+	scriptsDir := scriptsPath()
 	data := make(map[string]string)
 
 	// Read all JavaScript files from the k6 scripts directory
@@ -433,50 +584,37 @@ func createScriptsConfigMap(c Clients) error {
 		"ingestion-test.js",
 		"query-test.js",
 		"combined-test.js",
+		"jaeger-test.js",
+		"replay-test.js",
 	}
 
 	for _, file := range files {
 		filePath := filepath.Join(scriptsDir, file)
 		content, err := os.ReadFile(filePath)
 		if err != nil {
-			return fmt.Errorf("failed to read %s: %w", filePath, err)
+			return nil, fmt.Errorf("failed to read %s: %w", filePath, err)
 		}
 		// Use flat key names for ConfigMap (replace / with -)
 		key := strings.ReplaceAll(file, "/", "-")
 		data[key] = string(content)
 	}
-
-	configMap := &corev1.ConfigMap{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      ScriptsConfigMap,
-			Namespace: namespace,
-			Labels: map[string]string{
-				"app":       "k6-perf-test",
-				"component": "scripts",
-			},
-		},
-		Data: data,
-	}
-
-	// Delete existing ConfigMap if it exists
-	_ = client.CoreV1().ConfigMaps(namespace).Delete(ctx, ScriptsConfigMap, metav1.DeleteOptions{})
-
-	// Create new ConfigMap
-	_, err := client.CoreV1().ConfigMaps(namespace).Create(ctx, configMap, metav1.CreateOptions{})
-	if err != nil {
-		return fmt.Errorf("failed to create ConfigMap: %w", err)
-	}
-
-	fmt.Printf("📦 Created ConfigMap %s with k6 scripts\n", ScriptsConfigMap)
-	return nil
+	return data, nil
 }
 
-// createServiceCAConfigMap creates a ConfigMap that OpenShift will inject with the service CA
+// createServiceCAConfigMap creates a ConfigMap that OpenShift will inject with the service CA.
+// A ConfigMap the CA was already injected into is kept, since Jobs of another test
+// may be mounting it.
 func createServiceCAConfigMap(c Clients) error {
 	namespace := c.Namespace()
 	client := c.Client()
 	ctx := c.Context()
 
+	existing, err := client.CoreV1().ConfigMaps(namespace).Get(ctx, ServiceCAConfigMap, metav1.GetOptions{})
+	if err == nil && existing.Data[serviceCAKey] != "" {
+		fmt.Printf("📦 Reusing ConfigMap %s for service CA\n", ServiceCAConfigMap)
+		return nil
+	}
+
 	configMap := &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      ServiceCAConfigMap,
@@ -499,7 +637,7 @@ func createServiceCAConfigMap(c Clients) error {
 	time.Sleep(1 * time.Second)
 
 	// Create new ConfigMap
-	_, err := client.CoreV1().ConfigMaps(namespace).Create(ctx, configMap, metav1.CreateOptions{})
+	_, err = client.CoreV1().ConfigMaps(namespace).Create(ctx, configMap, metav1.CreateOptions{})
 	if err != nil {
 		return fmt.Errorf("failed to create service CA ConfigMap: %w", err)
 	}
@@ -511,8 +649,9 @@ func createServiceCAConfigMap(c Clients) error {
 	return nil
 }
 
-// createJob creates a Kubernetes Job to run the k6 test
-func createJob(c Clients, jobName string, testType TestType, config *Config) error {
+// createJob creates a Kubernetes Job to run the k6 test, labeled with the checksum
+// of the scripts and the framework commit
+func createJob(c Clients, jobName string, testType TestType, config *Config, scriptsChecksum string) error {
 	namespace := c.Namespace()
 	client := c.Client()
 	ctx := c.Context()
@@ -528,19 +667,36 @@ func createJob(c Clients, jobName string, testType TestType, config *Config) err
 	// Wait for job to be deleted
 	time.Sleep(2 * time.Second)
 
+	job := buildJob(namespace, c.GetTempoNodeSelector(), jobName, testType, config, scriptsChecksum)
+	_, err := client.BatchV1().Jobs(namespace).Create(ctx, job, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create Job: %w", err)
+	}
+
+	fmt.Printf("📋 Created Job %s\n", jobName)
+	return nil
+}
+
+// buildJob builds the Job running a k6 test, labeled with the checksum of the
+// scripts and the framework commit
+func buildJob(namespace string, tempoNodeSelector map[string]string, jobName string, testType TestType, config *Config, scriptsChecksum string) *batchv1.Job {
 	// Build environment variables
 	// The service CA is mounted from the ConfigMap at /etc/ssl/certs/service-ca.crt
 	serviceCAMountPath := "/etc/ssl/certs/service-ca.crt"
 	env := []corev1.EnvVar{
 		{Name: "SIZE", Value: string(config.Size)},
 		{Name: "TEMPO_ENDPOINT", Value: config.TempoEndpoint},
+		{Name: "INGESTION_PROTOCOL", Value: string(config.Protocol)},
 		{Name: "TEMPO_QUERY_ENDPOINT", Value: config.TempoQueryEndpoint},
 		// TLS configuration for query (gateway) - ingestion goes through OTel Collector (no TLS)
-		{Name: "TEMPO_QUERY_TLS_ENABLED", Value: "true"},
+		{Name: "TEMPO_QUERY_TLS_ENABLED", Value: strconv.FormatBool(!config.NoGateway)},
 		{Name: "TEMPO_TLS_CA_FILE", Value: serviceCAMountPath},
 		{Name: "TEMPO_TOKEN_FILE", Value: ServiceAccountTokenPath},
 	}
 
+	if config.JaegerQueryEndpoint != "" {
+		env = append(env, corev1.EnvVar{Name: "JAEGER_QUERY_ENDPOINT", Value: config.JaegerQueryEndpoint})
+	}
 	if config.TempoTenant != "" {
 		env = append(env, corev1.EnvVar{Name: "TEMPO_TENANT", Value: config.TempoTenant})
 	}
@@ -575,6 +731,22 @@ func createJob(c Clients, jobName string, testType TestType, config *Config) err
 		)
 	}
 
+	// The replay test reads its capture from a ConfigMap or PVC
+	var extraVolumes []corev1.Volume
+	var extraMounts []corev1.VolumeMount
+	if testType == TestReplay {
+		volume, mount, capturePath := replayVolume(config)
+		extraVolumes = append(extraVolumes, volume)
+		extraMounts = append(extraMounts, mount)
+		env = append(env, replayEnv(config, capturePath)...)
+	}
+	if usesQueryCorpus(testType, config) {
+		volume, mount, corpusEnv := queryCorpusVolume(config)
+		extraVolumes = append(extraVolumes, volume)
+		extraMounts = append(extraMounts, mount)
+		env = append(env, corpusEnv)
+	}
+
 	// Build the script path inside the container
 	scriptName := fmt.Sprintf("%s-test.js", testType)
 
@@ -582,32 +754,35 @@ func createJob(c Clients, jobName string, testType TestType, config *Config) err
 	// Always export summary to JSON for metrics parsing
 	k6RunCmd := fmt.Sprintf("k6 run --summary-export=/tmp/summary.json %s", scriptName)
 	if config.PrometheusRWURL != "" {
-		k6RunCmd = fmt.Sprintf("k6 run -o experimental-prometheus-rw --summary-export=/tmp/summary.json %s", scriptName)
+		// Remote-written samples carry no namespace; tag them so they can be queried per namespace
+		k6RunCmd = fmt.Sprintf("k6 run -o experimental-prometheus-rw --tag namespace=%s --summary-export=/tmp/summary.json %s", namespace, scriptName)
 	}
 
 	backoffLimit := int32(0)
 	ttlSeconds := int32(3600) // Keep job for 1 hour after completion
 
+	labels := map[string]string{
+		"app":                "k6-perf-test",
+		"test-type":          string(testType),
+		"size":               string(config.Size),
+		LabelScriptsChecksum: scriptsChecksumLabel(scriptsChecksum),
+	}
+	if sha := FrameworkSHA(); sha != "" {
+		labels[LabelFrameworkSHA] = sha
+	}
+
 	job := &batchv1.Job{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      jobName,
 			Namespace: namespace,
-			Labels: map[string]string{
-				"app":       "k6-perf-test",
-				"test-type": string(testType),
-				"size":      string(config.Size),
-			},
+			Labels:    labels,
 		},
 		Spec: batchv1.JobSpec{
 			BackoffLimit:            &backoffLimit,
 			TTLSecondsAfterFinished: &ttlSeconds,
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: map[string]string{
-						"app":       "k6-perf-test",
-						"test-type": string(testType),
-						"size":      string(config.Size),
-					},
+					Labels: labels,
 				},
 				Spec: corev1.PodSpec{
 					RestartPolicy:      corev1.RestartPolicyNever,
@@ -695,20 +870,35 @@ func createJob(c Clients, jobName string, testType TestType, config *Config) err
 		},
 	}
 
+	podSpec := &job.Spec.Template.Spec
+	podSpec.Volumes = append(podSpec.Volumes, extraVolumes...)
+	podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, extraMounts...)
+
 	// Apply anti-affinity to avoid Tempo nodes if node selector is set
-	if nodeSelector := c.GetTempoNodeSelector(); len(nodeSelector) > 0 {
+	if len(tempoNodeSelector) > 0 {
 		job.Spec.Template.Spec.Affinity = &corev1.Affinity{
-			NodeAffinity: buildNodeAntiAffinity(nodeSelector),
+			NodeAffinity: buildNodeAntiAffinity(tempoNodeSelector),
 		}
 	}
+	applyPodConfig(podSpec, config.Pod)
+	return job
+}
 
-	_, err := client.BatchV1().Jobs(namespace).Create(ctx, job, metav1.CreateOptions{})
-	if err != nil {
-		return fmt.Errorf("failed to create Job: %w", err)
+// applyPodConfig applies the resources and placement overrides of the k6 pods
+func applyPodConfig(podSpec *corev1.PodSpec, pod *PodConfig) {
+	if pod == nil {
+		return
+	}
+	if pod.Resources != nil {
+		podSpec.Containers[0].Resources = *pod.Resources
+	}
+	if len(pod.NodeSelector) > 0 {
+		podSpec.NodeSelector = pod.NodeSelector
+	}
+	podSpec.Tolerations = append(podSpec.Tolerations, pod.Tolerations...)
+	if pod.Affinity != nil {
+		podSpec.Affinity = pod.Affinity
 	}
-
-	fmt.Printf("📋 Created Job %s\n", jobName)
-	return nil
 }
 
 // waitForJob waits for the k6 Job to complete
@@ -749,10 +939,9 @@ func waitForJob(c Clients, jobName string, timeout time.Duration) (bool, error)
 }
 
 // getJobLogs retrieves logs from the k6 Job pod
-func getJobLogs(c Clients, jobName string) (string, error) {
+func getJobLogs(ctx context.Context, c Clients, jobName string) (string, error) {
 	namespace := c.Namespace()
 	client := c.Client()
-	ctx := c.Context()
 
 	// Find the pod created by the job
 	pods, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
@@ -790,12 +979,55 @@ func getJobLogs(c Clients, jobName string) (string, error) {
 	return logs.String(), nil
 }
 
+// setDefaults fills in the size, image, Tempo endpoints and tenant a config leaves empty
+func setDefaults(config *Config, namespace string) {
+	if config.Size == "" {
+		config.Size = SizeMedium
+	}
+	if config.Image == "" {
+		config.Image = DefaultImage
+	}
+
+	if config.Protocol == "" {
+		config.Protocol = ProtocolGRPC
+	}
+
+	// Default tenant for multitenancy mode
+	if config.TempoTenant == "" {
+		config.TempoTenant = DefaultTenant
+	}
+
+	// Set default endpoints based on Tempo variant (using gateway for multitenancy)
+	if config.TempoEndpoint == "" || config.TempoQueryEndpoint == "" {
+		ingestion, query := getDefaultEndpoints(config.TempoVariant, namespace, config.TempoTenant, config.Protocol, config.NoGateway)
+		if config.TempoEndpoint == "" {
+			config.TempoEndpoint = ingestion
+		}
+		if config.TempoQueryEndpoint == "" {
+			config.TempoQueryEndpoint = query
+		}
+	}
+}
+
+// setTestDefaults fills in the defaults of setDefaults and the Jaeger and OTLP/HTTP
+// endpoints of a single test
+func setTestDefaults(config *Config, namespace string) {
+	setDefaults(config, namespace)
+	if config.JaegerQueryEndpoint == "" {
+		config.JaegerQueryEndpoint = getDefaultJaegerEndpoint(config.TempoVariant, namespace, config.TempoTenant, config.NoGateway)
+	}
+	if config.OTLPHTTPEndpoint == "" {
+		config.OTLPHTTPEndpoint = fmt.Sprintf("http://otel-collector-collector.%s.svc.cluster.local:4318", namespace)
+	}
+}
+
 // getDefaultEndpoints returns the default ingestion and query endpoints
 // based on the Tempo deployment variant.
 //
 // Ingestion goes through the OpenTelemetry Collector (no TLS needed in-cluster)
-// Queries go directly to the Tempo gateway (with TLS/auth and multitenancy path)
-func getDefaultEndpoints(variant TempoVariant, namespace string) (ingestion, query string) {
+// Queries go directly to the Tempo gateway (with TLS/auth and the path of the
+// tenant), or to the Tempo HTTP API of a TempoMonolithic without the gateway
+func getDefaultEndpoints(variant TempoVariant, namespace, tenant string, protocol Protocol, noGateway bool) (ingestion, query string) {
 	var crName string
 	switch variant {
 	case TempoStack:
@@ -809,12 +1041,45 @@ func getDefaultEndpoints(variant TempoVariant, namespace string) (ingestion, que
 	// Ingestion through OpenTelemetry Collector (handles auth to Tempo)
 	otelCollectorHost := fmt.Sprintf("otel-collector-collector.%s.svc.cluster.local", namespace)
 	ingestion = fmt.Sprintf("%s:4317", otelCollectorHost)
+	if protocol == ProtocolHTTP {
+		ingestion = fmt.Sprintf("http://%s:4318", otelCollectorHost)
+	}
+
+	if noGateway {
+		return ingestion, fmt.Sprintf("http://tempo-%s.%s.svc.cluster.local:3200", crName, namespace)
+	}
 
 	// Query through Tempo gateway (with TLS/auth)
 	// For multitenancy, the Observatorium API routes are:
 	// /api/traces/v1/{tenant}/tempo/api/... for Tempo native API
 	gatewayHost := fmt.Sprintf("tempo-%s-gateway.%s.svc.cluster.local", crName, namespace)
-	query = fmt.Sprintf("https://%s:8080/api/traces/v1/%s/tempo", gatewayHost, DefaultTenant)
+	query = fmt.Sprintf("https://%s:8080/api/traces/v1/%s/tempo", gatewayHost, tenant)
 
 	return ingestion, query
 }
+
+// getDefaultJaegerEndpoint returns the base URL of the Jaeger HTTP API of the Tempo
+// deployment. The gateway serves the API of tempo-query (the Jaeger query component
+// enabled on both variants) under the tenant path, e.g. .../api/traces/v1/{tenant}/api/services.
+// Without the gateway, the Jaeger UI service of a TempoMonolithic serves it at the root.
+func getDefaultJaegerEndpoint(variant TempoVariant, namespace, tenant string, noGateway bool) string {
+	crName := MonolithicCRName
+	if variant == TempoStack {
+		crName = StackCRName
+	}
+	if noGateway {
+		return fmt.Sprintf("http://tempo-%s-jaegerui.%s.svc.cluster.local:16686", crName, namespace)
+	}
+	gatewayHost := fmt.Sprintf("tempo-%s-gateway.%s.svc.cluster.local", crName, namespace)
+	return fmt.Sprintf("https://%s:8080/api/traces/v1/%s", gatewayHost, tenant)
+}
+
+// sortedEndpoints returns the endpoints of per-endpoint latencies in order
+func sortedEndpoints(durations map[string]MetricStats) []string {
+	endpoints := make([]string, 0, len(durations))
+	for endpoint := range durations {
+		endpoints = append(endpoints, endpoint)
+	}
+	sort.Strings(endpoints)
+	return endpoints
+}
@@ -2,8 +2,14 @@ package k6
 
 import (
 	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
+
+	corev1 "k8s.io/api/core/v1"
 )
 
 // TestType represents the type of k6 test to run
@@ -13,6 +19,10 @@ const (
 	TestIngestion TestType = "ingestion"
 	TestQuery     TestType = "query"
 	TestCombined  TestType = "combined"
+	// TestJaeger queries the Jaeger HTTP API served by tempo-query
+	TestJaeger TestType = "jaeger"
+	// TestReplay replays an OTLP JSON trace capture through the OTel Collector
+	TestReplay TestType = "replay"
 )
 
 // Size represents t-shirt sizes for k6 tests
@@ -35,6 +45,16 @@ const (
 	TempoStack TempoVariant = "stack"
 )
 
+// Protocol is the OTLP protocol traces are sent to the OTel Collector with
+type Protocol string
+
+const (
+	// ProtocolGRPC sends OTLP over gRPC (port 4317)
+	ProtocolGRPC Protocol = "grpc"
+	// ProtocolHTTP sends OTLP over HTTP (port 4318)
+	ProtocolHTTP Protocol = "http"
+)
+
 // CR names used by the framework
 const (
 	// MonolithicCRName is the name of the TempoMonolithic CR created by the framework
@@ -92,6 +112,39 @@ type Config struct {
 	TempoTenant        string
 	TempoToken         string
 
+	// Protocol is the OTLP protocol of the ingestion client. Default: ProtocolGRPC.
+	// The default TempoEndpoint is the receiver of the collector for this protocol.
+	Protocol Protocol
+
+	// NoGateway queries Tempo directly over plain HTTP, for a TempoMonolithic deployed
+	// without the multitenancy gateway (ingestion auth "mtls" or "none")
+	NoGateway bool
+
+	// JaegerQueryEndpoint is the base URL of the Jaeger HTTP API (/api/services, /api/traces)
+	// used by the jaeger test
+	JaegerQueryEndpoint string
+
+	// OTLPHTTPEndpoint is the base URL of the OTLP/HTTP receiver used by the replay test
+	// (auto-discovered if empty)
+	OTLPHTTPEndpoint string
+
+	// Replay test capture: a local OTLP JSON file uploaded in a ConfigMap, or a file
+	// at ReplayPath (default traces.json) on the PVC ReplayPVC
+	ReplayFile string
+	ReplayPVC  string
+	ReplayPath string
+	// ReplaySpeedup replays the capture faster (>1) or slower (<1) than recorded (default 1)
+	ReplaySpeedup float64
+
+	// QueryCorpusFile is a local query corpus (see QueryCorpus) whose searches the
+	// query and combined tests run instead of their built-in queries
+	QueryCorpusFile string
+
+	// BeforeQuery is called by RunParallelTests once the ingestion Job is created and
+	// before the query Job is, e.g. to set QueryCorpusFile to a corpus generated from
+	// the ingested data. The built-in queries are run if it fails.
+	BeforeQuery func(config *Config) error
+
 	// Prometheus metrics export configuration
 	// If set, k6 will export metrics to Prometheus via remote write
 	PrometheusRWURL string
@@ -99,6 +152,25 @@ type Config struct {
 	// Timeout is the maximum time to wait for the job to complete
 	// If not set, it's calculated as Duration + JobTimeoutBuffer
 	Timeout time.Duration
+
+	// Pod overrides the resources and placement of the k6 Job pods (optional)
+	Pod *PodConfig
+}
+
+// PodConfig overrides the resources and placement of the k6 Job pods, e.g. to give
+// high-rate loads bigger pods pinned to dedicated load generation nodes
+type PodConfig struct {
+	// Resources replaces the default requests (500m CPU, 512Mi) and limits (2 CPU, 2Gi)
+	Resources *corev1.ResourceRequirements
+
+	// NodeSelector pins the pods to nodes with these labels
+	NodeSelector map[string]string
+
+	// Tolerations let the pods run on tainted nodes
+	Tolerations []corev1.Toleration
+
+	// Affinity replaces the default anti-affinity to the Tempo nodes
+	Affinity *corev1.Affinity
 }
 
 // GetTimeout returns the job timeout, calculating from Duration if not explicitly set
@@ -124,6 +196,140 @@ type Result struct {
 	Duration time.Duration
 	Error    error
 	Metrics  *K6Metrics
+
+	// Cancelled is set when the test context was cancelled while the Job ran; the
+	// Job was deleted and Output holds the logs captured before
+	Cancelled bool
+
+	// Completeness is set by framework.VerifyIngestionCompleteness
+	Completeness *IngestionCompleteness
+
+	// RateCheck is set by framework.CheckIngestionRate
+	RateCheck *IngestionRateCheck
+
+	// QueryRateCheck is set by framework.CheckQueryRate
+	QueryRateCheck *QueryRateCheck
+
+	// QueryCorrectness is set by framework.VerifyQueryCorrectness
+	QueryCorrectness *QueryCorrectness
+
+	// ScriptsChecksum is the SHA-256 of the k6 scripts the test ran
+	ScriptsChecksum string
+	// FrameworkSHA is the git commit of the framework, empty if unknown
+	FrameworkSHA string
+}
+
+// ThresholdsFailed returns true if k6 reported at least one crossed threshold
+func (r *Result) ThresholdsFailed() bool {
+	return r != nil && r.Metrics != nil && len(r.Metrics.FailedThresholds) > 0
+}
+
+// IngestionCompleteness reports how much of the data sent by k6 was stored by Tempo
+type IngestionCompleteness struct {
+	// SentSpans is the number of spans k6 reported as sent
+	SentSpans float64
+	// ReceivedSpans is the increase of tempo_distributor_spans_received_total during the test
+	ReceivedSpans float64
+	// SpanLossPercent is the percentage of sent spans the distributors did not receive
+	SpanLossPercent float64
+
+	// TracesSampled is the number of trace IDs looked up through the query frontend
+	TracesSampled int
+	// TracesFound is the number of sampled traces that could be retrieved by ID
+	TracesFound int
+	// TraceLossPercent is the percentage of sampled traces that could not be retrieved
+	TraceLossPercent float64
+	// TraceLookupError is set when no trace sample could be taken
+	TraceLookupError error
+}
+
+// IngestionRateCheck compares the ingestion rate a profile requested with the rate
+// the generator achieved, so runs limited by the generator are not mistaken for
+// runs where Tempo handled the requested load
+type IngestionRateCheck struct {
+	// TargetMBPerSecond is the requested rate (profile k6.ingestion.mbPerSecond)
+	TargetMBPerSecond float64
+	// SentMBPerSecond is the payload rate k6 achieved
+	SentMBPerSecond float64
+	// WireMBPerSecond is the rate of k6 data_sent, including protocol overhead
+	WireMBPerSecond float64
+	// ReceivedMBPerSecond is the rate of tempo_distributor_bytes_received_total over
+	// the same load time; zero when ReceivedError is set
+	ReceivedMBPerSecond float64
+	ReceivedError       error
+	// AchievedPercent is SentMBPerSecond as a percentage of TargetMBPerSecond
+	AchievedPercent float64
+	// UnderDelivered is true when AchievedPercent is below the minimum percentage:
+	// the generator never reached the requested load
+	UnderDelivered bool
+}
+
+// QueryRateCheck compares the query rate a profile requested with the rate k6
+// achieved. The arrival-rate executor skips queries when no VU is free, so a slow
+// Tempo can leave the requested rate unreached.
+type QueryRateCheck struct {
+	// TargetQueriesPerSecond is the requested rate (profile k6.query.queriesPerSecond)
+	TargetQueriesPerSecond float64
+	// AchievedQueriesPerSecond is the rate of queries k6 ran
+	AchievedQueriesPerSecond float64
+	// DroppedQueries is the number of queries k6 skipped for lack of a free VU
+	DroppedQueries float64
+	// AchievedPercent is AchievedQueriesPerSecond as a percentage of TargetQueriesPerSecond
+	AchievedPercent float64
+	// UnderDelivered is true when AchievedPercent is below the minimum percentage
+	UnderDelivered bool
+}
+
+// QueryCorrectness reports whether the traces returned by searches during the query
+// load can be retrieved completely after the run
+type QueryCorrectness struct {
+	// TracesSampled is the number of distinct trace IDs k6 recorded from search results
+	TracesSampled int
+	// TracesChecked is the number of sampled traces fetched by ID after the run
+	TracesChecked int
+	// TracesFound is the number of checked traces that could be retrieved
+	TracesFound int
+	// TracesComplete is the number of found traces with at least the span count
+	// the search reported
+	TracesComplete int
+	// MissingSpans is the number of spans the incomplete traces lack
+	MissingSpans int
+	// Score is the percentage of checked traces that were found complete
+	Score float64
+	// LookupError is set when the checks stopped early; the counts cover the
+	// traces checked before
+	LookupError error
+}
+
+// QuerySample is a trace returned by a search of the query load
+type QuerySample struct {
+	TraceID string
+	// Spans is the span count the search reported for the trace, 0 when unknown
+	Spans int
+}
+
+// querySamplePattern matches the lines the query scripts log for sampled results
+var querySamplePattern = regexp.MustCompile(`QUERY_SAMPLE traceID=([0-9a-fA-F]+) spans=(\d+)`)
+
+// ParseQuerySamples extracts the sampled search results from the k6 output in the
+// order they were logged. A trace returned by several searches is listed once, with
+// the highest span count reported.
+func ParseQuerySamples(output string) []QuerySample {
+	var samples []QuerySample
+	index := make(map[string]int)
+	for _, m := range querySamplePattern.FindAllStringSubmatch(output, -1) {
+		spans, _ := strconv.Atoi(m[2])
+		traceID := strings.ToLower(m[1])
+		if i, ok := index[traceID]; ok {
+			if spans > samples[i].Spans {
+				samples[i].Spans = spans
+			}
+			continue
+		}
+		index[traceID] = len(samples)
+		samples = append(samples, QuerySample{TraceID: traceID, Spans: spans})
+	}
+	return samples
 }
 
 // K6Metrics holds parsed metrics from k6 JSON summary output
@@ -137,8 +343,35 @@ type K6Metrics struct {
 	// Ingestion metrics from xk6-tempo
 	IngestionBytesTotal  float64
 	IngestionTracesTotal float64
+	IngestionSpansTotal  float64
 	IngestionRateBPS     float64
 	IngestionDuration    MetricStats
+	// IngestionBytesRate is the average rate of tempo_ingestion_bytes_total over the
+	// test in bytes per second, i.e. the payload rate the generator achieved
+	IngestionBytesRate float64
+
+	// DataSentTotal and DataSentRate are k6's data_sent: all bytes sent on the wire,
+	// including protocol overhead
+	DataSentTotal float64
+	DataSentRate  float64
+
+	// IterationsTotal and IterationsRate are k6's iterations. The query and jaeger
+	// tests run one query per iteration, so IterationsRate is their query rate.
+	IterationsTotal float64
+	IterationsRate  float64
+	// DroppedIterations is k6's dropped_iterations: iterations an arrival-rate
+	// executor could not start because no VU was free
+	DroppedIterations float64
+
+	// Jaeger query API metrics (jaeger test)
+	JaegerRequestsTotal float64
+	JaegerFailuresTotal float64
+	// JaegerRequestDuration holds the latency in seconds of each Jaeger endpoint
+	// (find_traces, get_trace, services, operations)
+	JaegerRequestDuration map[string]MetricStats
+
+	// FailedThresholds lists the crossed thresholds as "metric: expression"
+	FailedThresholds []string
 }
 
 // MetricStats holds statistical values for a metric
@@ -235,10 +468,25 @@ func ParseK6Metrics(output string) *K6Metrics {
 	// Extract ingestion metrics
 	if m, ok := summary.Metrics["tempo_ingestion_bytes_total"]; ok {
 		metrics.IngestionBytesTotal = m.Values.Count
+		metrics.IngestionBytesRate = m.Values.Rate
+	}
+	if m, ok := summary.Metrics["data_sent"]; ok {
+		metrics.DataSentTotal = m.Values.Count
+		metrics.DataSentRate = m.Values.Rate
+	}
+	if m, ok := summary.Metrics["iterations"]; ok {
+		metrics.IterationsTotal = m.Values.Count
+		metrics.IterationsRate = m.Values.Rate
+	}
+	if m, ok := summary.Metrics["dropped_iterations"]; ok {
+		metrics.DroppedIterations = m.Values.Count
 	}
 	if m, ok := summary.Metrics["tempo_ingestion_traces_total"]; ok {
 		metrics.IngestionTracesTotal = m.Values.Count
 	}
+	if m, ok := summary.Metrics["tempo_ingestion_spans_total"]; ok {
+		metrics.IngestionSpansTotal = m.Values.Count
+	}
 	if m, ok := summary.Metrics["tempo_ingestion_rate_bytes_per_sec"]; ok {
 		metrics.IngestionRateBPS = m.Values.Value
 	}
@@ -254,5 +502,42 @@ func ParseK6Metrics(output string) *K6Metrics {
 		}
 	}
 
+	// Extract Jaeger query metrics; the latency of each endpoint is exported as a
+	// submetric, e.g. jaeger_request_duration_seconds{endpoint:find_traces}
+	if m, ok := summary.Metrics["jaeger_requests"]; ok {
+		metrics.JaegerRequestsTotal = m.Values.Count
+	}
+	if m, ok := summary.Metrics["jaeger_failures"]; ok {
+		metrics.JaegerFailuresTotal = m.Values.Count
+	}
+	for name, m := range summary.Metrics {
+		endpoint, ok := strings.CutPrefix(name, "jaeger_request_duration_seconds{endpoint:")
+		if !ok {
+			continue
+		}
+		if metrics.JaegerRequestDuration == nil {
+			metrics.JaegerRequestDuration = make(map[string]MetricStats)
+		}
+		metrics.JaegerRequestDuration[strings.TrimSuffix(endpoint, "}")] = MetricStats{
+			Avg: m.Values.Avg,
+			Min: m.Values.Min,
+			Med: m.Values.Med,
+			Max: m.Values.Max,
+			P90: m.Values.P90,
+			P95: m.Values.P95,
+			P99: m.Values.P99,
+		}
+	}
+
+	// Extract crossed thresholds (true means the threshold failed)
+	for name, m := range summary.Metrics {
+		for expr, failed := range m.Thresholds {
+			if failed {
+				metrics.FailedThresholds = append(metrics.FailedThresholds, fmt.Sprintf("%s: %s", name, expr))
+			}
+		}
+	}
+	sort.Strings(metrics.FailedThresholds)
+
 	return metrics
 }
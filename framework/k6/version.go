@@ -0,0 +1,95 @@
+package k6
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os/exec"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Labels of the k6 Jobs and pods identifying the scripts and framework version
+const (
+	LabelScriptsChecksum = "scripts-checksum"
+	LabelFrameworkSHA    = "framework-sha"
+)
+
+// GitSHA is the git commit of the framework. It can be set at build time with
+// -ldflags "-X github.com/redhat/perf-tests-tempo/test/framework/k6.GitSHA=<sha>";
+// otherwise FrameworkSHA reads it from the build info or the git checkout.
+var GitSHA string
+
+var (
+	frameworkSHAOnce sync.Once
+	frameworkSHA     string
+)
+
+// FrameworkSHA returns the git commit of the framework: GitSHA, the VCS revision
+// stamped by go build (with a -dirty suffix for uncommitted changes), or the HEAD of
+// the git checkout the runner is started from, as with go run. Empty if unknown.
+func FrameworkSHA() string {
+	frameworkSHAOnce.Do(func() {
+		frameworkSHA = resolveFrameworkSHA()
+	})
+	return frameworkSHA
+}
+
+// resolveFrameworkSHA looks up the git commit of the framework
+func resolveFrameworkSHA() string {
+	if GitSHA != "" {
+		return GitSHA
+	}
+
+	if info, ok := debug.ReadBuildInfo(); ok {
+		var revision, modified string
+		for _, s := range info.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				revision = s.Value
+			case "vcs.modified":
+				modified = s.Value
+			}
+		}
+		if revision != "" {
+			if modified == "true" {
+				revision += "-dirty"
+			}
+			return revision
+		}
+	}
+
+	out, err := exec.Command("git", "rev-parse", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// ScriptsChecksum returns the SHA-256 of the k6 scripts in a ConfigMap data map,
+// over its keys in order, so the same scripts always have the same checksum
+func ScriptsChecksum(data map[string]string) string {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	for _, k := range keys {
+		h.Write([]byte(k))
+		h.Write([]byte{0})
+		h.Write([]byte(data[k]))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// scriptsChecksumLabel shortens a checksum to fit a label value (63 characters)
+func scriptsChecksumLabel(checksum string) string {
+	if len(checksum) > 16 {
+		return checksum[:16]
+	}
+	return checksum
+}
@@ -0,0 +1,288 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/wait"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Clients provides access to Kubernetes clients needed for Kafka setup
+type Clients interface {
+	Client() kubernetes.Interface
+	Context() context.Context
+	Namespace() string
+	Logger() *slog.Logger
+	// GetTempoNodeSelector returns the node selector used for Tempo pods.
+	// Used to create anti-affinity for Kafka.
+	GetTempoNodeSelector() map[string]string
+}
+
+// Config holds Kafka configuration options
+type Config struct {
+	// Partitions is the number of partitions of the topics the broker creates,
+	// which bounds how many partitions the consuming collector reads in parallel
+	// Default: 1
+	Partitions int
+
+	// Resources are the resource requirements of the Kafka container (optional)
+	Resources *corev1.ResourceRequirements
+}
+
+// DefaultPartitions is the default number of partitions of the topics
+const DefaultPartitions = 1
+
+// MaxMessageBytes is the largest message the broker accepts. It is above the 1MB
+// default of Kafka, so a batch of large traces fits in one message.
+const MaxMessageBytes = 10 * 1024 * 1024
+
+// Names and ports of the Kafka objects
+const (
+	name           = "kafka"
+	image          = "docker.io/apache/kafka:3.9.0"
+	brokerPort     = 9092
+	controllerPort = 9093
+)
+
+// Brokers returns the bootstrap address of the Kafka Setup deploys in namespace
+func Brokers(namespace string) []string {
+	return []string{fmt.Sprintf("%s.%s.svc.cluster.local:%d", name, namespace, brokerPort)}
+}
+
+// Setup deploys a single Kafka broker in KRaft mode, acting as its own controller,
+// and waits for it to be ready. Topics are created on first use. The log is kept in
+// an emptyDir: the broker only buffers the traces of a run.
+// Note: EnsureNamespace should be called before this function
+func Setup(c Clients, config *Config) error {
+	namespace := c.Namespace()
+	client := c.Client()
+	ctx := c.Context()
+
+	cfg, err := resolveConfig(config)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("📦 Setting up Kafka with %d partition(s) per topic\n", cfg.Partitions)
+
+	_, err = client.CoreV1().Services(namespace).Create(ctx, buildService(namespace), metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create Kafka service: %w", err)
+	}
+
+	deployment := buildDeployment(namespace, c.GetTempoNodeSelector(), cfg)
+	_, err = client.AppsV1().Deployments(namespace).Create(ctx, deployment, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create Kafka deployment: %w", err)
+	}
+
+	selector, err := labels.Parse("app.kubernetes.io/name=" + name)
+	if err != nil {
+		return fmt.Errorf("failed to parse selector: %w", err)
+	}
+
+	// The timeout is the PodReadyTimeout of the framework configuration
+	return wait.ForPodsReady(c, selector, 0, 1)
+}
+
+// Manifests returns the objects Setup creates in namespace, in the order they are
+// created, without touching the cluster
+func Manifests(namespace string, tempoNodeSelector map[string]string, config *Config) ([]runtime.Object, error) {
+	cfg, err := resolveConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return []runtime.Object{
+		buildService(namespace),
+		buildDeployment(namespace, tempoNodeSelector, cfg),
+	}, nil
+}
+
+// resolveConfig applies the defaults to config and validates it
+func resolveConfig(config *Config) (*Config, error) {
+	cfg := &Config{Partitions: DefaultPartitions}
+	if config != nil {
+		if config.Partitions < 0 {
+			return nil, fmt.Errorf("Kafka partitions cannot be negative, got %d", config.Partitions)
+		}
+		if config.Partitions > 0 {
+			cfg.Partitions = config.Partitions
+		}
+		cfg.Resources = config.Resources
+	}
+	return cfg, nil
+}
+
+// buildService returns the Service of the Kafka broker listener
+func buildService(namespace string) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "broker",
+					Port:       brokerPort,
+					Protocol:   corev1.ProtocolTCP,
+					TargetPort: intstr.FromInt32(brokerPort),
+				},
+			},
+			Selector: podLabels(),
+			Type:     corev1.ServiceTypeClusterIP,
+		},
+	}
+}
+
+// buildDeployment returns the Deployment of the Kafka broker
+func buildDeployment(namespace string, tempoNodeSelector map[string]string, cfg *Config) *appsv1.Deployment {
+	enableServiceLinks := false
+	container := corev1.Container{
+		Name:  name,
+		Image: image,
+		Env:   brokerEnv(namespace, cfg),
+		Ports: []corev1.ContainerPort{
+			{
+				Name:          "broker",
+				ContainerPort: brokerPort,
+			},
+			{
+				Name:          "controller",
+				ContainerPort: controllerPort,
+			},
+		},
+		ReadinessProbe: &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				TCPSocket: &corev1.TCPSocketAction{
+					Port: intstr.FromInt32(brokerPort),
+				},
+			},
+			PeriodSeconds: 5,
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      "data",
+				MountPath: "/var/lib/kafka/data",
+			},
+		},
+	}
+	if cfg.Resources != nil {
+		container.Resources = *cfg.Resources
+	}
+
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: podLabels(),
+			},
+			Strategy: appsv1.DeploymentStrategy{
+				Type: appsv1.RecreateDeploymentStrategyType,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: podLabels(),
+				},
+				Spec: corev1.PodSpec{
+					// The image reads every KAFKA_* variable as a broker setting, including
+					// the KAFKA_PORT variable Kubernetes would add for the kafka Service
+					EnableServiceLinks: &enableServiceLinks,
+					Containers:         []corev1.Container{container},
+					Volumes: []corev1.Volume{
+						{
+							Name: "data",
+							VolumeSource: corev1.VolumeSource{
+								EmptyDir: &corev1.EmptyDirVolumeSource{},
+							},
+						},
+					},
+					Affinity: affinity(tempoNodeSelector),
+				},
+			},
+		},
+	}
+}
+
+// brokerEnv returns the settings of a single KRaft node acting as broker and
+// controller, advertising the address of the Service
+func brokerEnv(namespace string, cfg *Config) []corev1.EnvVar {
+	settings := [][2]string{
+		{"KAFKA_NODE_ID", "1"},
+		{"KAFKA_PROCESS_ROLES", "broker,controller"},
+		{"KAFKA_LISTENERS", fmt.Sprintf("PLAINTEXT://:%d,CONTROLLER://:%d", brokerPort, controllerPort)},
+		{"KAFKA_ADVERTISED_LISTENERS", "PLAINTEXT://" + Brokers(namespace)[0]},
+		{"KAFKA_CONTROLLER_LISTENER_NAMES", "CONTROLLER"},
+		{"KAFKA_LISTENER_SECURITY_PROTOCOL_MAP", "CONTROLLER:PLAINTEXT,PLAINTEXT:PLAINTEXT"},
+		{"KAFKA_CONTROLLER_QUORUM_VOTERS", fmt.Sprintf("1@localhost:%d", controllerPort)},
+		{"KAFKA_LOG_DIRS", "/var/lib/kafka/data"},
+		{"KAFKA_NUM_PARTITIONS", strconv.Itoa(cfg.Partitions)},
+		{"KAFKA_MESSAGE_MAX_BYTES", strconv.Itoa(MaxMessageBytes)},
+		{"KAFKA_REPLICA_FETCH_MAX_BYTES", strconv.Itoa(MaxMessageBytes)},
+		// A single broker cannot replicate the internal topics
+		{"KAFKA_OFFSETS_TOPIC_REPLICATION_FACTOR", "1"},
+		{"KAFKA_TRANSACTION_STATE_LOG_REPLICATION_FACTOR", "1"},
+		{"KAFKA_TRANSACTION_STATE_LOG_MIN_ISR", "1"},
+		{"KAFKA_GROUP_INITIAL_REBALANCE_DELAY_MS", "0"},
+	}
+	env := make([]corev1.EnvVar, 0, len(settings))
+	for _, s := range settings {
+		env = append(env, corev1.EnvVar{Name: s[0], Value: s[1]})
+	}
+	return env
+}
+
+// podLabels returns the labels of the Kafka pod
+func podLabels() map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/name": name,
+	}
+}
+
+// affinity returns the anti-affinity of the Kafka pod to Tempo nodes, or nil if
+// the Tempo node selector is not set
+func affinity(tempoNodeSelector map[string]string) *corev1.Affinity {
+	if len(tempoNodeSelector) == 0 {
+		return nil
+	}
+
+	var matchExpressions []corev1.NodeSelectorRequirement
+	for key, value := range tempoNodeSelector {
+		if value == "" {
+			matchExpressions = append(matchExpressions, corev1.NodeSelectorRequirement{
+				Key:      key,
+				Operator: corev1.NodeSelectorOpDoesNotExist,
+			})
+		} else {
+			matchExpressions = append(matchExpressions, corev1.NodeSelectorRequirement{
+				Key:      key,
+				Operator: corev1.NodeSelectorOpNotIn,
+				Values:   []string{value},
+			})
+		}
+	}
+
+	return &corev1.Affinity{
+		NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{
+					{MatchExpressions: matchExpressions},
+				},
+			},
+		},
+	}
+}
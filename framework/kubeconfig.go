@@ -0,0 +1,46 @@
+package framework
+
+import (
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// WithKubeconfig connects to the cluster of a kubeconfig file instead of the
+// in-cluster config or the default kubeconfig (KUBECONFIG or ~/.kube/config)
+func WithKubeconfig(path string) Option {
+	return func(f *Framework) {
+		f.kubeconfigPath = path
+	}
+}
+
+// WithKubeContext connects to the cluster of a kubeconfig context instead of the
+// current context
+func WithKubeContext(name string) Option {
+	return func(f *Framework) {
+		f.kubeContext = name
+	}
+}
+
+// WithRESTConfig connects with a REST config, ignoring WithKubeconfig and WithKubeContext
+func WithRESTConfig(cfg *rest.Config) Option {
+	return func(f *Framework) {
+		f.restConfig = cfg
+	}
+}
+
+// loadRESTConfig returns the REST config of a kubeconfig path and context.
+// Without either, the in-cluster config is used when available; otherwise the
+// KUBECONFIG env var, falling back to ~/.kube/config.
+func loadRESTConfig(kubeconfigPath, kubeContext string) (*rest.Config, error) {
+	if kubeconfigPath == "" && kubeContext == "" {
+		if restConfig, err := rest.InClusterConfig(); err == nil {
+			return restConfig, nil
+		}
+	}
+
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = kubeconfigPath
+	configOverrides := &clientcmd.ConfigOverrides{CurrentContext: kubeContext}
+	kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, configOverrides)
+	return kubeConfig.ClientConfig()
+}
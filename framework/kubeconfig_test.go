@@ -0,0 +1,78 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/client-go/rest"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: dev
+  cluster:
+    server: https://dev.example.com:6443
+- name: perf
+  cluster:
+    server: https://perf.example.com:6443
+contexts:
+- name: dev
+  context:
+    cluster: dev
+    user: tester
+- name: perf
+  context:
+    cluster: perf
+    user: tester
+current-context: dev
+users:
+- name: tester
+  user:
+    token: secret
+`
+
+func writeTestKubeconfig(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "kubeconfig")
+	if err := os.WriteFile(path, []byte(testKubeconfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestNew_WithKubeconfig(t *testing.T) {
+	path := writeTestKubeconfig(t)
+
+	tests := []struct {
+		name string
+		opts []Option
+		host string
+	}{
+		{"current context", []Option{WithKubeconfig(path)}, "https://dev.example.com:6443"},
+		{"explicit context", []Option{WithKubeconfig(path), WithKubeContext("perf")}, "https://perf.example.com:6443"},
+		{"REST config wins", []Option{WithKubeconfig(path), WithRESTConfig(&rest.Config{Host: "https://other.example.com"})}, "https://other.example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fw, err := New(context.Background(), "perf-test", tt.opts...)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			if fw.Config().Host != tt.host {
+				t.Errorf("host = %s, want %s", fw.Config().Host, tt.host)
+			}
+		})
+	}
+}
+
+func TestNew_WithUnknownKubeContext(t *testing.T) {
+	_, err := New(context.Background(), "perf-test", WithKubeconfig(writeTestKubeconfig(t)), WithKubeContext("prod"))
+	if !errors.Is(err, ErrClusterConnection) {
+		t.Errorf("expected ErrClusterConnection, got %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+package framework
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/metrics"
+)
+
+// logErrorSamples is the number of sample lines kept per log and pattern
+const logErrorSamples = 3
+
+// logErrorSampleLength truncates long sample lines
+const logErrorSampleLength = 300
+
+// LogErrorPattern is an error pattern searched for in the component logs
+type LogErrorPattern struct {
+	Name   string
+	Regexp *regexp.Regexp
+}
+
+// DefaultLogErrorPatterns are the patterns AnalyzeLogs searches for
+var DefaultLogErrorPatterns = []LogErrorPattern{
+	{Name: "panic", Regexp: regexp.MustCompile(`\bpanic:|fatal error:|goroutine \d+ \[running\]`)},
+	{Name: "oom", Regexp: regexp.MustCompile(`(?i)out of memory|OOMKilled|cannot allocate memory`)},
+	{Name: "deadline-exceeded", Regexp: regexp.MustCompile(`context deadline exceeded`)},
+	{Name: "rate-limited", Regexp: regexp.MustCompile(`(?i)RATE_LIMITED|rate limit|too many requests`)},
+}
+
+// AnalyzeLogs scans the logs collected by CollectLogs for DefaultLogErrorPatterns
+// and prints a summary of the logs with matches
+func (f *Framework) AnalyzeLogs(result *LogCollectionResult) *metrics.LogErrorSummary {
+	summary := analyzeLogs(result.Logs, DefaultLogErrorPatterns)
+
+	if summary.Total == 0 {
+		fmt.Println("🔎 No error patterns found in component logs")
+		return summary
+	}
+
+	fmt.Printf("🔎 Found %d log lines matching error patterns:\n", summary.Total)
+	for _, l := range summary.Logs {
+		counts := make([]string, 0, len(l.Found))
+		for _, m := range l.Found {
+			counts = append(counts, fmt.Sprintf("%s=%d", m.Pattern, m.Count))
+		}
+		fmt.Printf("   ⚠️  %s: %s\n", l.File, strings.Join(counts, ", "))
+	}
+	return summary
+}
+
+// analyzeLogs counts the lines of each log matching the patterns. A line is counted
+// once per pattern it matches.
+func analyzeLogs(logs []ComponentLogs, patterns []LogErrorPattern) *metrics.LogErrorSummary {
+	summary := &metrics.LogErrorSummary{Patterns: make([]string, 0, len(patterns))}
+	for _, p := range patterns {
+		summary.Patterns = append(summary.Patterns, p.Name)
+	}
+
+	for _, log := range logs {
+		if log.Error != nil || log.Logs == "" {
+			continue
+		}
+
+		matches := make([]metrics.LogErrorMatch, len(patterns))
+		total := 0
+		scanner := bufio.NewScanner(strings.NewReader(log.Logs))
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := scanner.Text()
+			for i, p := range patterns {
+				if !p.Regexp.MatchString(line) {
+					continue
+				}
+				matches[i].Count++
+				total++
+				if len(matches[i].Samples) < logErrorSamples {
+					matches[i].Samples = append(matches[i].Samples, truncateLine(line))
+				}
+			}
+		}
+		if total == 0 {
+			continue
+		}
+
+		entry := metrics.ComponentLogErrors{
+			Component: log.Component,
+			Pod:       log.Pod,
+			Container: log.Container,
+			File:      logFileName(log),
+			Total:     total,
+		}
+		for i, m := range matches {
+			if m.Count == 0 {
+				continue
+			}
+			m.Pattern = patterns[i].Name
+			entry.Found = append(entry.Found, m)
+		}
+		summary.Logs = append(summary.Logs, entry)
+		summary.Total += total
+	}
+
+	sort.SliceStable(summary.Logs, func(i, j int) bool {
+		return summary.Logs[i].Total > summary.Logs[j].Total
+	})
+	return summary
+}
+
+// truncateLine shortens a sample line to logErrorSampleLength bytes
+func truncateLine(line string) string {
+	if len(line) <= logErrorSampleLength {
+		return line
+	}
+	return line[:logErrorSampleLength] + "..."
+}
@@ -0,0 +1,77 @@
+package framework
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAnalyzeLogs(t *testing.T) {
+	logs := []ComponentLogs{
+		{
+			Component: "tempo-ingester",
+			Pod:       "tempo-ingester-0",
+			Container: "tempo",
+			Logs: strings.Join([]string{
+				`level=info msg="flushing block"`,
+				`level=error msg="failed to push" err="context deadline exceeded"`,
+				`level=warn msg="RATE_LIMITED: ingestion rate limit (15000000 bytes) exceeded"`,
+				`level=error msg="flush failed" err="context deadline exceeded"`,
+			}, "\n"),
+		},
+		{
+			Component: "tempo-querier",
+			Pod:       "tempo-querier-0",
+			Logs:      "panic: runtime error: invalid memory address\ngoroutine 42 [running]:\n",
+		},
+		{Component: "minio", Pod: "minio-0", Logs: "API: SYSTEM.storage ready\n"},
+		{Component: "k6", Pod: "k6-0", Error: errors.New("failed to stream logs")},
+	}
+
+	summary := analyzeLogs(logs, DefaultLogErrorPatterns)
+	if summary.Total != 5 {
+		t.Errorf("expected 5 matching lines, got %d", summary.Total)
+	}
+	if len(summary.Patterns) != len(DefaultLogErrorPatterns) {
+		t.Errorf("expected %d patterns, got %v", len(DefaultLogErrorPatterns), summary.Patterns)
+	}
+	if len(summary.Logs) != 2 {
+		t.Fatalf("expected 2 logs with matches, got %+v", summary.Logs)
+	}
+
+	ingester := summary.Logs[0]
+	if ingester.Component != "tempo-ingester" || ingester.File != "tempo-ingester-tempo-ingester-0-tempo.log" {
+		t.Errorf("expected the ingester log first, got %+v", ingester)
+	}
+	if ingester.Count("deadline-exceeded") != 2 || ingester.Count("rate-limited") != 1 || ingester.Count("panic") != 0 {
+		t.Errorf("unexpected ingester counts %+v", ingester.Found)
+	}
+	if got := ingester.Found[0].Samples; len(got) != 2 || !strings.Contains(got[0], "failed to push") {
+		t.Errorf("unexpected samples %v", got)
+	}
+
+	if querier := summary.Logs[1]; querier.Count("panic") != 2 {
+		t.Errorf("expected 2 panic lines in the querier log, got %+v", querier.Found)
+	}
+}
+
+func TestAnalyzeLogs_SamplesLimited(t *testing.T) {
+	line := "fatal error: out of memory " + strings.Repeat("x", 2*logErrorSampleLength)
+	logs := []ComponentLogs{{Component: "tempo", Pod: "tempo-0", Logs: strings.Repeat(line+"\n", 10)}}
+
+	summary := analyzeLogs(logs, DefaultLogErrorPatterns)
+	if len(summary.Logs) != 1 {
+		t.Fatalf("expected 1 log with matches, got %+v", summary.Logs)
+	}
+	for _, m := range summary.Logs[0].Found {
+		if m.Count != 10 {
+			t.Errorf("expected 10 %s lines, got %d", m.Pattern, m.Count)
+		}
+		if len(m.Samples) != logErrorSamples {
+			t.Errorf("expected %d samples, got %d", logErrorSamples, len(m.Samples))
+		}
+		if len(m.Samples[0]) != logErrorSampleLength+len("...") {
+			t.Errorf("expected truncated samples, got %d bytes", len(m.Samples[0]))
+		}
+	}
+}
@@ -100,12 +100,7 @@ func (f *Framework) CollectLogs(config *LogCollectionConfig) (*LogCollectionResu
 			continue
 		}
 
-		filename := fmt.Sprintf("%s-%s.log", log.Component, log.Pod)
-		if log.Container != "" && log.Container != log.Component {
-			filename = fmt.Sprintf("%s-%s-%s.log", log.Component, log.Pod, log.Container)
-		}
-		// Sanitize filename
-		filename = strings.ReplaceAll(filename, "/", "-")
+		filename := logFileName(log)
 		filepath := filepath.Join(logDir, filename)
 
 		if err := os.WriteFile(filepath, []byte(log.Logs), 0644); err != nil {
@@ -127,6 +122,16 @@ func (f *Framework) CollectLogs(config *LogCollectionConfig) (*LogCollectionResu
 	return result, nil
 }
 
+// logFileName returns the name of the file CollectLogs writes a container's logs to
+func logFileName(log ComponentLogs) string {
+	filename := fmt.Sprintf("%s-%s.log", log.Component, log.Pod)
+	if log.Container != "" && log.Container != log.Component {
+		filename = fmt.Sprintf("%s-%s-%s.log", log.Component, log.Pod, log.Container)
+	}
+	// Sanitize filename
+	return strings.ReplaceAll(filename, "/", "-")
+}
+
 // collectPodsLogs collects logs from pods matching the selector
 func (f *Framework) collectPodsLogs(component, selector string, config *LogCollectionConfig) []ComponentLogs {
 	var results []ComponentLogs
@@ -0,0 +1,138 @@
+package metrics
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/config"
+)
+
+// AlertsSuffix is the file name suffix of the firing alerts of a run in a results directory
+const AlertsSuffix = "-alerts.json"
+
+// alertGapFactor is how many query steps an alert may be missing from the ALERTS
+// series before the next sample starts a new firing interval
+const alertGapFactor = 2
+
+// FiringAlert is an interval during which a Prometheus alert of the test namespace
+// was firing. An alert that fired several times has one FiringAlert per interval.
+type FiringAlert struct {
+	Name     string `json:"name"`
+	Severity string `json:"severity,omitempty"`
+	// Labels are the labels of the alert, without alertname, alertstate and severity
+	Labels map[string]string `json:"labels,omitempty"`
+	// Start and End are the first and last evaluations that found the alert firing
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	// Active is set when the alert was still firing at the end of the window
+	Active bool `json:"active,omitempty"`
+}
+
+// Duration returns how long the alert was seen firing
+func (a FiringAlert) Duration() time.Duration {
+	return a.End.Sub(a.Start)
+}
+
+// CollectAlerts returns the intervals during which alerts of the test namespace,
+// such as those of tempo.SetupAlertRules, were firing between start and end,
+// ordered by start. They are read from the ALERTS series of Prometheus.
+func CollectAlerts(np NamespaceProvider, start, end time.Time) ([]FiringAlert, error) {
+	ctx := traceContextFor(np)
+	c, err := newCollectionFor(ctx, np)
+	if err != nil {
+		return nil, err
+	}
+
+	step := c.resolution.Step
+	if step <= 0 {
+		step = config.DefaultMetricsQueryStep
+	}
+	query := MetricQuery{
+		Name:     "alerts",
+		Query:    fmt.Sprintf(`ALERTS{namespace="%s", alertstate="firing"}`, np.Namespace()),
+		Category: "alerts",
+	}
+	results, err := c.collectMetric(ctx, query, start, end, step)
+	if errors.Is(err, ErrNoData) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query alerts: %w", err)
+	}
+	return buildFiringAlerts(results, step, end), nil
+}
+
+// buildFiringAlerts splits the ALERTS series into firing intervals: a sample more
+// than alertGapFactor steps after the previous one starts a new interval
+func buildFiringAlerts(results []MetricResult, step time.Duration, end time.Time) []FiringAlert {
+	var alerts []FiringAlert
+	for _, r := range results {
+		labels := make(map[string]string, len(r.Labels))
+		for k, v := range r.Labels {
+			switch k {
+			case "alertname", "alertstate", "severity", "__name__":
+			default:
+				labels[k] = v
+			}
+		}
+
+		var current *FiringAlert
+		for _, dp := range r.DataPoints {
+			if current != nil && dp.Timestamp.Sub(current.End) <= alertGapFactor*step {
+				current.End = dp.Timestamp
+				continue
+			}
+			alerts = append(alerts, FiringAlert{
+				Name:     r.Labels["alertname"],
+				Severity: r.Labels["severity"],
+				Labels:   labels,
+				Start:    dp.Timestamp,
+				End:      dp.Timestamp,
+			})
+			current = &alerts[len(alerts)-1]
+		}
+		if current != nil && end.Sub(current.End) <= alertGapFactor*step {
+			current.Active = true
+		}
+	}
+
+	sort.SliceStable(alerts, func(i, j int) bool { return alerts[i].Start.Before(alerts[j].Start) })
+	return alerts
+}
+
+// WriteAlerts writes the firing alerts of a run as JSON
+func WriteAlerts(alerts []FiringAlert, outputPath string) error {
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	if alerts == nil {
+		alerts = []FiringAlert{}
+	}
+	data, err := json.MarshalIndent(alerts, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode alerts: %w", err)
+	}
+	if err := os.WriteFile(outputPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write alerts: %w", err)
+	}
+	return nil
+}
+
+// LoadAlerts reads firing alerts written by WriteAlerts
+func LoadAlerts(path string) ([]FiringAlert, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read alerts: %w", err)
+	}
+	var alerts []FiringAlert
+	if err := json.Unmarshal(data, &alerts); err != nil {
+		return nil, fmt.Errorf("failed to parse alerts %s: %w", path, err)
+	}
+	return alerts, nil
+}
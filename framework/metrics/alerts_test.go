@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBuildFiringAlerts(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	step := 30 * time.Second
+	at := func(steps int) DataPoint {
+		return DataPoint{Timestamp: start.Add(time.Duration(steps) * step), Value: 1}
+	}
+	results := []MetricResult{
+		{
+			Labels: map[string]string{
+				"alertname": "TempoPerfHighRefusedSpans", "alertstate": "firing", "severity": "warning", "namespace": "perf",
+			},
+			// Two intervals: the alert resolved between steps 3 and 10
+			DataPoints: []DataPoint{at(2), at(3), at(10), at(11), at(12)},
+		},
+		{
+			Labels:     map[string]string{"alertname": "TempoPerfQueryQueueSaturated", "alertstate": "firing"},
+			DataPoints: []DataPoint{at(0), at(1)},
+		},
+	}
+
+	alerts := buildFiringAlerts(results, step, start.Add(12*step))
+	if len(alerts) != 3 {
+		t.Fatalf("expected 3 firing intervals, got %+v", alerts)
+	}
+	if alerts[0].Name != "TempoPerfQueryQueueSaturated" || alerts[0].Duration() != step || alerts[0].Active {
+		t.Errorf("expected the resolved queue alert first, got %+v", alerts[0])
+	}
+	refused := alerts[1]
+	if refused.Severity != "warning" || refused.Duration() != step || refused.Active {
+		t.Errorf("expected a resolved 30s warning interval, got %+v", refused)
+	}
+	if len(refused.Labels) != 1 || refused.Labels["namespace"] != "perf" {
+		t.Errorf("expected only the namespace label to be kept, got %v", refused.Labels)
+	}
+	if !alerts[2].Active || !alerts[2].Start.Equal(start.Add(10*step)) || alerts[2].Duration() != 2*step {
+		t.Errorf("expected the second refused interval to be still firing, got %+v", alerts[2])
+	}
+}
+
+func TestWriteAlerts_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run"+AlertsSuffix)
+	if err := WriteAlerts(nil, path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	alerts, err := LoadAlerts(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if alerts == nil || len(alerts) != 0 {
+		t.Errorf("expected an empty, non-nil list of alerts, got %#v", alerts)
+	}
+}
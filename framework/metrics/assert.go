@@ -0,0 +1,200 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/config"
+)
+
+// assertRecentValues is the number of recent values per series shown when an assertion fails
+const assertRecentValues = 5
+
+// assertMaxSeries is the number of series shown when an assertion fails
+const assertMaxSeries = 10
+
+// Threshold is the type of an assertion threshold. Durations are compared in seconds,
+// the unit of the duration metrics (e.g. query_duration_p99).
+type Threshold interface {
+	time.Duration | float64 | int
+}
+
+// MetricAssertionError reports a metric that crossed its threshold, with the worst
+// value and the recent values of every series
+type MetricAssertionError struct {
+	Metric     string
+	Query      string
+	Comparison string // "below" or "above"
+	Threshold  float64
+	Window     time.Duration
+	// Worst is the value furthest past the threshold, Labels the labels of its series
+	Worst  DataPoint
+	Labels map[string]string
+	// Results are the series queried over the window
+	Results []MetricResult
+
+	// duration formats the values as durations
+	duration bool
+}
+
+// Error describes the violation, the query and the recent values of each series
+func (e *MetricAssertionError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "metric %s is not %s %s over the last %s: reached %s at %s",
+		e.Metric, e.Comparison, e.format(e.Threshold), e.Window,
+		e.format(e.Worst.Value), e.Worst.Timestamp.Format(time.RFC3339))
+	if labels := formatLabels(e.Labels); labels != "" {
+		fmt.Fprintf(&b, " {%s}", labels)
+	}
+	fmt.Fprintf(&b, "\n  query: %s\n  recent values:", e.Query)
+
+	for i, r := range e.Results {
+		if i == assertMaxSeries {
+			fmt.Fprintf(&b, "\n    ... %d more series", len(e.Results)-assertMaxSeries)
+			break
+		}
+		points := r.DataPoints
+		if len(points) > assertRecentValues {
+			points = points[len(points)-assertRecentValues:]
+		}
+		values := make([]string, 0, len(points))
+		for _, p := range points {
+			values = append(values, e.format(p.Value))
+		}
+		fmt.Fprintf(&b, "\n    {%s}: %s", formatLabels(r.Labels), strings.Join(values, ", "))
+	}
+	return b.String()
+}
+
+// format formats a metric value in the unit of the threshold
+func (e *MetricAssertionError) format(v float64) string {
+	if e.duration && !math.IsNaN(v) && !math.IsInf(v, 0) {
+		return time.Duration(v * float64(time.Second)).Round(time.Millisecond).String()
+	}
+	return fmt.Sprintf("%g", v)
+}
+
+// ExpectMetricBelow checks that every value of the named metric (see GetAllQueries)
+// stayed below threshold over the last window, e.g.
+//
+//	ExpectMetricBelow(fw, "query_duration_p99", 2*time.Second, 5*time.Minute)
+//
+// It returns a *MetricAssertionError with the worst and recent values when the
+// threshold was reached. In Ginkgo specs, assert it with Expect(err).To(Succeed()).
+func ExpectMetricBelow[T Threshold](np NamespaceProvider, metric string, threshold T, window time.Duration) error {
+	return expectMetric(np, metric, "below", thresholdValue(threshold), isDuration(threshold), window)
+}
+
+// ExpectMetricAbove checks that every value of the named metric stayed above
+// threshold over the last window, e.g. ExpectMetricAbove(fw, "accepted_spans_rate", 1000, window)
+func ExpectMetricAbove[T Threshold](np NamespaceProvider, metric string, threshold T, window time.Duration) error {
+	return expectMetric(np, metric, "above", thresholdValue(threshold), isDuration(threshold), window)
+}
+
+// thresholdValue converts a threshold to the unit of the metric values
+func thresholdValue[T Threshold](threshold T) float64 {
+	if d, ok := any(threshold).(time.Duration); ok {
+		return d.Seconds()
+	}
+	return float64(threshold)
+}
+
+// isDuration reports whether values are formatted as durations
+func isDuration[T Threshold](threshold T) bool {
+	_, ok := any(threshold).(time.Duration)
+	return ok
+}
+
+// expectMetric queries the metric over the last window and checks it against the threshold
+func expectMetric(np NamespaceProvider, metric, comparison string, threshold float64, duration bool, window time.Duration) error {
+	if window <= 0 {
+		return fmt.Errorf("assertion window for %s must be positive", metric)
+	}
+
+	query, err := lookupQuery(np.Namespace(), metric)
+	if err != nil {
+		return err
+	}
+
+	ctx := traceContextFor(np)
+	c, err := newCollectionFor(ctx, np)
+	if err != nil {
+		return err
+	}
+
+	step := c.resolution.Step
+	if step <= 0 {
+		step = config.DefaultMetricsQueryStep
+	}
+	query.Query = applyRateWindow(query.Query, c.resolution.RateWindow)
+
+	end := nowFor(np)
+	results, err := c.collectMetric(ctx, query, end.Add(-window), end, step)
+	if err != nil {
+		return fmt.Errorf("failed to query %s: %w", metric, err)
+	}
+
+	return checkThreshold(query, results, comparison, threshold, duration, window)
+}
+
+// lookupQuery returns the range query named metric
+func lookupQuery(namespace, metric string) (MetricQuery, error) {
+	for _, q := range GetAllQueries(namespace) {
+		if q.Name == metric {
+			return q, nil
+		}
+	}
+	return MetricQuery{}, fmt.Errorf("unknown metric %q", metric)
+}
+
+// checkThreshold returns a *MetricAssertionError when a value of results is not
+// strictly below (or above) threshold. NaN values, e.g. quantiles without samples,
+// are ignored; results without any other value are an error.
+func checkThreshold(query MetricQuery, results []MetricResult, comparison string, threshold float64, duration bool, window time.Duration) error {
+	above := comparison == "above"
+
+	var (
+		worst     DataPoint
+		labels    map[string]string
+		found     bool
+		hasValues bool
+	)
+	for _, r := range results {
+		for _, p := range r.DataPoints {
+			if math.IsNaN(p.Value) {
+				continue
+			}
+			hasValues = true
+			crossed := p.Value >= threshold
+			if above {
+				crossed = p.Value <= threshold
+			}
+			if !crossed {
+				continue
+			}
+			if !found || (above && p.Value < worst.Value) || (!above && p.Value > worst.Value) {
+				worst, labels, found = p, r.Labels, true
+			}
+		}
+	}
+
+	if !hasValues {
+		return fmt.Errorf("no values of %s over the last %s", query.Name, window)
+	}
+	if !found {
+		return nil
+	}
+	return &MetricAssertionError{
+		Metric:     query.Name,
+		Query:      query.Query,
+		Comparison: comparison,
+		Threshold:  threshold,
+		Window:     window,
+		Worst:      worst,
+		Labels:     labels,
+		Results:    results,
+		duration:   duration,
+	}
+}
@@ -0,0 +1,115 @@
+package metrics
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func assertResults(values ...float64) []MetricResult {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	points := make([]DataPoint, 0, len(values))
+	for i, v := range values {
+		points = append(points, DataPoint{Timestamp: start.Add(time.Duration(i) * time.Minute), Value: v})
+	}
+	return []MetricResult{{
+		MetricName: "query_duration_p99",
+		Labels:     map[string]string{"route": "search"},
+		DataPoints: points,
+	}}
+}
+
+func TestCheckThreshold_Below(t *testing.T) {
+	query := MetricQuery{Name: "query_duration_p99", Query: "histogram_quantile(0.99, x)"}
+
+	if err := checkThreshold(query, assertResults(0.5, 1.2, math.NaN()), "below", 2, true, 5*time.Minute); err != nil {
+		t.Errorf("expected values below the threshold to pass, got %v", err)
+	}
+
+	err := checkThreshold(query, assertResults(0.5, 3.2, 2.5), "below", 2, true, 5*time.Minute)
+	var assertErr *MetricAssertionError
+	if !errors.As(err, &assertErr) {
+		t.Fatalf("expected a MetricAssertionError, got %v", err)
+	}
+	if assertErr.Worst.Value != 3.2 {
+		t.Errorf("expected worst value 3.2, got %v", assertErr.Worst.Value)
+	}
+}
+
+func TestCheckThreshold_Above(t *testing.T) {
+	query := MetricQuery{Name: "accepted_spans_rate"}
+
+	if err := checkThreshold(query, assertResults(1500, 1200), "above", 1000, false, time.Minute); err != nil {
+		t.Errorf("expected values above the threshold to pass, got %v", err)
+	}
+
+	err := checkThreshold(query, assertResults(1500, 800, 900), "above", 1000, false, time.Minute)
+	var assertErr *MetricAssertionError
+	if !errors.As(err, &assertErr) {
+		t.Fatalf("expected a MetricAssertionError, got %v", err)
+	}
+	if assertErr.Worst.Value != 800 {
+		t.Errorf("expected worst value 800, got %v", assertErr.Worst.Value)
+	}
+}
+
+func TestCheckThreshold_NoValues(t *testing.T) {
+	err := checkThreshold(MetricQuery{Name: "query_duration_p99"}, assertResults(math.NaN()), "below", 2, true, time.Minute)
+	if err == nil {
+		t.Fatal("expected an error without values")
+	}
+	var assertErr *MetricAssertionError
+	if errors.As(err, &assertErr) {
+		t.Errorf("expected a plain error without values, got %v", err)
+	}
+}
+
+func TestMetricAssertionError_Message(t *testing.T) {
+	query := MetricQuery{Name: "query_duration_p99", Query: "histogram_quantile(0.99, x)"}
+	err := checkThreshold(query, assertResults(0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 3.2), "below", 2, true, 5*time.Minute)
+	if err == nil {
+		t.Fatal("expected an assertion error")
+	}
+
+	msg := err.Error()
+	for _, want := range []string{
+		"metric query_duration_p99 is not below 2s over the last 5m0s",
+		"reached 3.2s at 2024-01-01T12:06:00Z {route=search}",
+		"query: histogram_quantile(0.99, x)",
+		"{route=search}: 300ms, 400ms, 500ms, 600ms, 3.2s",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected message to contain %q, got:\n%s", want, msg)
+		}
+	}
+	if strings.Contains(msg, "200ms") {
+		t.Errorf("expected only the %d most recent values, got:\n%s", assertRecentValues, msg)
+	}
+}
+
+func TestThresholdValue(t *testing.T) {
+	if got := thresholdValue(1500 * time.Millisecond); got != 1.5 {
+		t.Errorf("expected a duration threshold in seconds, got %v", got)
+	}
+	if got := thresholdValue(1000); got != 1000 {
+		t.Errorf("expected 1000, got %v", got)
+	}
+	if !isDuration(time.Second) || isDuration(0.5) {
+		t.Error("expected only durations to be formatted as durations")
+	}
+}
+
+func TestLookupQuery(t *testing.T) {
+	q, err := lookupQuery("tempo-perf", "query_duration_p99")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(q.Query, `namespace="tempo-perf"`) {
+		t.Errorf("expected the query for the namespace, got %s", q.Query)
+	}
+	if _, err := lookupQuery("tempo-perf", "no_such_metric"); err == nil {
+		t.Error("expected an error for an unknown metric")
+	}
+}
@@ -0,0 +1,65 @@
+package metrics
+
+import "github.com/redhat/perf-tests-tempo/test/framework/k6"
+
+// LoadAttainment records how much of the requested load k6 generated. It is
+// reported in the run metadata and the dashboard summary, so results of runs that
+// never reached their load are not read as Tempo handling it.
+type LoadAttainment struct {
+	// Ingestion rates are in MB/s
+	Ingestion *RateAttainment `json:"ingestion,omitempty"`
+	// Queries rates are in queries per second
+	Queries *RateAttainment `json:"queries,omitempty"`
+}
+
+// RateAttainment compares a requested rate with the rate k6 achieved
+type RateAttainment struct {
+	Target   float64 `json:"target"`
+	Achieved float64 `json:"achieved"`
+	// Observed is the rate Tempo received (bytes_received_rate), zero when unknown
+	Observed        float64 `json:"observed,omitempty"`
+	AchievedPercent float64 `json:"achieved_percent"`
+	UnderDelivered  bool    `json:"under_delivered"`
+}
+
+// RecordIngestion records an ingestion rate check. Of several checks, e.g. one per
+// phase, the one with the lowest attainment is kept.
+func (a *LoadAttainment) RecordIngestion(c *k6.IngestionRateCheck) {
+	if c == nil {
+		return
+	}
+	a.Ingestion = lowerAttainment(a.Ingestion, &RateAttainment{
+		Target:          c.TargetMBPerSecond,
+		Achieved:        c.SentMBPerSecond,
+		Observed:        c.ReceivedMBPerSecond,
+		AchievedPercent: c.AchievedPercent,
+		UnderDelivered:  c.UnderDelivered,
+	})
+}
+
+// RecordQueries records a query rate check, keeping the lowest attainment like
+// RecordIngestion
+func (a *LoadAttainment) RecordQueries(c *k6.QueryRateCheck) {
+	if c == nil {
+		return
+	}
+	a.Queries = lowerAttainment(a.Queries, &RateAttainment{
+		Target:          c.TargetQueriesPerSecond,
+		Achieved:        c.AchievedQueriesPerSecond,
+		AchievedPercent: c.AchievedPercent,
+		UnderDelivered:  c.UnderDelivered,
+	})
+}
+
+// IsEmpty reports whether no rate was checked
+func (a *LoadAttainment) IsEmpty() bool {
+	return a == nil || (a.Ingestion == nil && a.Queries == nil)
+}
+
+// lowerAttainment returns the attainment with the lower achieved percentage
+func lowerAttainment(current, next *RateAttainment) *RateAttainment {
+	if current != nil && current.AchievedPercent <= next.AchievedPercent {
+		return current
+	}
+	return next
+}
@@ -0,0 +1,30 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/k6"
+)
+
+func TestLoadAttainment_KeepsLowest(t *testing.T) {
+	var a LoadAttainment
+	if !a.IsEmpty() {
+		t.Error("expected a new attainment to be empty")
+	}
+
+	a.RecordIngestion(&k6.IngestionRateCheck{TargetMBPerSecond: 10, SentMBPerSecond: 9.8, ReceivedMBPerSecond: 9.7, AchievedPercent: 98})
+	a.RecordIngestion(&k6.IngestionRateCheck{TargetMBPerSecond: 20, SentMBPerSecond: 15, AchievedPercent: 75, UnderDelivered: true})
+	a.RecordIngestion(&k6.IngestionRateCheck{TargetMBPerSecond: 5, SentMBPerSecond: 5, AchievedPercent: 100})
+	a.RecordQueries(&k6.QueryRateCheck{TargetQueriesPerSecond: 20, AchievedQueriesPerSecond: 19, AchievedPercent: 95})
+	a.RecordQueries(nil)
+
+	if a.IsEmpty() {
+		t.Fatal("expected the attainment not to be empty")
+	}
+	if a.Ingestion.Target != 20 || a.Ingestion.AchievedPercent != 75 || !a.Ingestion.UnderDelivered {
+		t.Errorf("expected the lowest ingestion attainment to be kept, got %+v", *a.Ingestion)
+	}
+	if a.Queries.Achieved != 19 || a.Queries.AchievedPercent != 95 {
+		t.Errorf("unexpected query attainment %+v", *a.Queries)
+	}
+}
@@ -1,13 +1,9 @@
 package metrics
 
 import (
-	"context"
 	"fmt"
 	"strings"
 	"time"
-
-	"k8s.io/client-go/rest"
-	"k8s.io/client-go/tools/clientcmd"
 )
 
 // MetricAvailability represents the availability status of a metric
@@ -38,47 +34,19 @@ type CategoryAvailability struct {
 
 // CheckMetricAvailability checks which metrics are available in Prometheus
 func CheckMetricAvailability(np NamespaceProvider, duration time.Duration) (*AvailabilityReport, error) {
-	ctx := context.Background()
+	ctx := traceContextFor(np)
 	namespace := np.Namespace()
 
-	// Get KubeConfig
-	var kubeConfig *rest.Config
-	if cp, ok := np.(ConfigProvider); ok {
-		kubeConfig = cp.Config()
-	} else {
-		var err error
-		kubeConfig, err = rest.InClusterConfig()
-		if err != nil {
-			// Use KUBECONFIG env var if set, otherwise fall back to ~/.kube/config
-			loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
-			configOverrides := &clientcmd.ConfigOverrides{}
-			clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, configOverrides)
-			kubeConfig, err = clientConfig.ClientConfig()
-			if err != nil {
-				return nil, fmt.Errorf("failed to get kube config: %w", err)
-			}
-		}
-	}
-
-	// Create client
-	config := &ClientConfig{
-		Namespace:           namespace,
-		AutoDiscover:        true,
-		MonitoringNamespace: "openshift-monitoring",
-		ServiceAccountName:  "prometheus-k8s",
-		KubeConfig:          kubeConfig,
-	}
-
-	client, err := NewClient(ctx, config)
+	c, err := newCollectionFor(ctx, np)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create Prometheus client: %w", err)
+		return nil, err
 	}
 
 	// Get all queries
 	queries := GetAllQueries(namespace)
 
 	// Calculate time range
-	end := time.Now()
+	end := nowFor(np)
 	start := end.Add(-duration)
 
 	report := &AvailabilityReport{
@@ -98,7 +66,7 @@ func CheckMetricAvailability(np NamespaceProvider, duration time.Duration) (*Ava
 		}
 
 		// Execute query to check if data exists
-		result, err := client.QueryRange(ctx, query.Query, start, end, 60*time.Second)
+		result, err := c.collector.QueryRange(ctx, query.Query, start, end, 60*time.Second)
 		if err != nil {
 			avail.Error = err.Error()
 		} else if len(result.Data.Result) == 0 {
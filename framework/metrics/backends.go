@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+)
+
+// ThanosCollector queries the Thanos Querier of the OpenShift monitoring stack,
+// discovering its Route and generating a prometheus-k8s token unless they are set
+type ThanosCollector struct {
+	*Client
+}
+
+// NewThanosCollector creates a ThanosCollector
+func NewThanosCollector(ctx context.Context, config *ClientConfig) (*ThanosCollector, error) {
+	config.Mode = ModeOpenShift
+	client, err := NewClient(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	return &ThanosCollector{Client: client}, nil
+}
+
+// PrometheusCollector queries a Prometheus Service in the cluster, through the API
+// server service proxy when running outside of it, or the Prometheus at ThanosURL
+type PrometheusCollector struct {
+	*Client
+}
+
+// NewPrometheusCollector creates a PrometheusCollector
+func NewPrometheusCollector(ctx context.Context, config *ClientConfig) (*PrometheusCollector, error) {
+	config.Mode = ModeKubernetes
+	client, err := NewClient(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	return &PrometheusCollector{Client: client}, nil
+}
+
+// RemoteCollector queries a user-provided Prometheus-compatible endpoint at
+// ThanosURL, e.g. the Prometheus API of Cortex or Mimir, with the optional Token and
+// Tenant of the config
+type RemoteCollector struct {
+	*Client
+}
+
+// NewRemoteCollector creates a RemoteCollector
+func NewRemoteCollector(ctx context.Context, config *ClientConfig) (*RemoteCollector, error) {
+	config.Mode = ModeRemote
+	client, err := NewClient(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	return &RemoteCollector{Client: client}, nil
+}
+
+// NewCollector creates the Collector of the mode of config
+func NewCollector(ctx context.Context, config *ClientConfig) (Collector, error) {
+	switch config.Mode {
+	case ModeKubernetes:
+		return NewPrometheusCollector(ctx, config)
+	case ModeRemote:
+		return NewRemoteCollector(ctx, config)
+	case ModeOpenShift, "":
+		return NewThanosCollector(ctx, config)
+	default:
+		return nil, fmt.Errorf("unknown Prometheus mode %q", config.Mode)
+	}
+}
@@ -0,0 +1,105 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/config"
+)
+
+func TestDefaultClientConfig_RemoteMode(t *testing.T) {
+	fwConfig := config.Default()
+	fwConfig.PrometheusMode = string(ModeRemote)
+	fwConfig.PrometheusURL = "https://mimir.example.com/prometheus"
+	fwConfig.PrometheusTenant = "perf"
+
+	clientConfig := DefaultClientConfig("tempo-perf-small", nil, fwConfig)
+
+	if clientConfig.Mode != ModeRemote || clientConfig.AutoDiscover {
+		t.Errorf("expected remote mode without discovery, got %+v", clientConfig)
+	}
+	if clientConfig.ThanosURL != fwConfig.PrometheusURL || clientConfig.Tenant != "perf" {
+		t.Errorf("expected the URL and tenant of the framework config, got %+v", clientConfig)
+	}
+}
+
+func TestNewCollector_Remote(t *testing.T) {
+	var tenant, auth, path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenant, auth, path = r.Header.Get(TenantHeader), r.Header.Get("Authorization"), r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+	}))
+	defer server.Close()
+
+	collector, err := NewCollector(context.Background(), &ClientConfig{
+		Mode:      ModeRemote,
+		ThanosURL: server.URL + "/prometheus/",
+		Token:     "secret",
+		Tenant:    "perf",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := collector.(*RemoteCollector); !ok {
+		t.Fatalf("expected a RemoteCollector, got %T", collector)
+	}
+
+	if _, err := collector.Query(context.Background(), "up", time.Time{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tenant != "perf" || auth != "Bearer secret" || path != "/prometheus/api/v1/query" {
+		t.Errorf("unexpected request: tenant %q, authorization %q, path %q", tenant, auth, path)
+	}
+}
+
+func TestNewCollector_RemoteRequiresURL(t *testing.T) {
+	if _, err := NewCollector(context.Background(), &ClientConfig{Mode: ModeRemote}); err == nil {
+		t.Error("expected an error without a URL in remote mode")
+	}
+}
+
+// staticCollector answers every query with one series of value 1
+type staticCollector struct {
+	queries int
+}
+
+func (c *staticCollector) QueryRange(ctx context.Context, query string, start, end time.Time, step time.Duration) (*PrometheusResponse, error) {
+	c.queries++
+	resp := &PrometheusResponse{Status: "success"}
+	resp.Data.Result = []PrometheusResult{{Metric: map[string]string{}, Values: [][]interface{}{{float64(start.Unix()), "1"}}}}
+	return resp, nil
+}
+
+func (c *staticCollector) Query(ctx context.Context, query string, evalTime time.Time) (*PrometheusResponse, error) {
+	resp := &PrometheusResponse{Status: "success"}
+	resp.Data.Result = []PrometheusResult{{Metric: map[string]string{}, Value: []interface{}{float64(evalTime.Unix()), "1"}}}
+	return resp, nil
+}
+
+type collectorTestProvider struct {
+	collector Collector
+}
+
+func (collectorTestProvider) Namespace() string               { return "ns" }
+func (p collectorTestProvider) MetricsCollector() Collector   { return p.collector }
+func (collectorTestProvider) FrameworkConfig() *config.Config { return config.Default() }
+
+func TestCollectFromPrometheus_ProvidedCollector(t *testing.T) {
+	collector := &staticCollector{}
+	end := time.Date(2024, 6, 1, 12, 10, 0, 0, time.UTC)
+
+	results, summary, err := collectFromPrometheus(context.Background(), collectorTestProvider{collector}, end.Add(-10*time.Minute), end, "", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if total := len(GetAllQueries("ns")); collector.queries != total || len(results) != total {
+		t.Errorf("expected %d queries through the provided collector, got %d queries and %d results", total, collector.queries, len(results))
+	}
+	if len(summary) != len(GetSummaryQueries("ns")) {
+		t.Errorf("expected the summary metrics, got %d", len(summary))
+	}
+}
@@ -0,0 +1,222 @@
+package metrics
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// RunMetadataSuffix is the file name suffix of run metadata files in a results directory
+const RunMetadataSuffix = "-run.json"
+
+// ErrNoBaseline is returned by FindBaseline when no earlier run matches
+var ErrNoBaseline = errors.New("no matching baseline run")
+
+// RunMetadata describes a profile run whose metrics are stored in a results directory.
+// It is written next to the metrics file so later runs can select it as a baseline.
+type RunMetadata struct {
+	RunID        string    `json:"run_id"`
+	Profile      string    `json:"profile"`
+	Variant      string    `json:"variant"`
+	TempoVersion string    `json:"tempo_version,omitempty"`
+	TestType     string    `json:"test_type,omitempty"`
+	StartedAt    time.Time `json:"started_at"`
+
+	// TempoEnv is the environment set in the Tempo containers (e.g. GOGC and
+	// GOMEMLIMIT), so runs with different Go runtime tuning can be told apart
+	TempoEnv map[string]string `json:"tempo_env,omitempty"`
+	// TempoExtraConfig is the raw Tempo configuration of the profile merged into
+	// the extraConfig of the Tempo CR
+	TempoExtraConfig map[string]interface{} `json:"tempo_extra_config,omitempty"`
+	// IngestionAuth is how the OTel Collector authenticated to Tempo; empty is the
+	// default ServiceAccount token
+	IngestionAuth string `json:"ingestion_auth,omitempty"`
+	// IngestionProtocol is the OTLP protocol traces were sent with, from k6 to the
+	// OTel Collector and from the collector to Tempo: "grpc" or "http"
+	IngestionProtocol string `json:"ingestion_protocol,omitempty"`
+	// IngestionPath is "kafka" when the traces were buffered in Kafka between two
+	// OTel Collectors; empty when the collector sent them to Tempo directly
+	IngestionPath string `json:"ingestion_path,omitempty"`
+	// MetricsGenerator are the processors of the Tempo metrics-generator; empty
+	// when the generator was not enabled
+	MetricsGenerator []string `json:"metrics_generator,omitempty"`
+	// Block is the block format set by the profile; nil when Tempo wrote its
+	// default format
+	Block *BlockFormat `json:"block,omitempty"`
+	// Replicas is the number of TempoMonolithic pods of an HA monolithic run;
+	// zero is a single pod
+	Replicas int `json:"replicas,omitempty"`
+	// Images are the images run by the Tempo, OTel Collector and k6 containers,
+	// with their resolved digest when known, keyed by "tempo", "otel-collector" and "k6"
+	Images map[string]string `json:"images,omitempty"`
+	// FrameworkSHA is the git commit of the framework that ran the test
+	FrameworkSHA string `json:"framework_sha,omitempty"`
+
+	// GeneratorLimited is true when k6 did not reach the requested ingestion rate,
+	// so the run does not show that Tempo handled that rate
+	GeneratorLimited bool `json:"generator_limited,omitempty"`
+	// Attainment compares the requested ingestion and query rates with the rates
+	// k6 achieved, when they were checked
+	Attainment *LoadAttainment `json:"attainment,omitempty"`
+	// Isolation holds the per-tenant loads of a noisy-neighbor run
+	Isolation *TenantIsolation `json:"isolation,omitempty"`
+	// CompactionBacklog is the compactor backlog over the load, with the growth
+	// allowed by the profile when it sets one
+	CompactionBacklog *CompactionBacklog `json:"compaction_backlog,omitempty"`
+
+	// TestDuration is the time spent generating load
+	TestDuration time.Duration `json:"test_duration,omitempty"`
+	// Duration is the wall time of the whole profile run including setup and
+	// cleanup, recorded once the run finishes
+	Duration time.Duration `json:"duration,omitempty"`
+
+	// MetricsFile is the file name of the metrics CSV, relative to the metadata file
+	MetricsFile string `json:"metrics_file"`
+
+	// LogErrors summarizes the error patterns found in the component logs, when
+	// logs were collected
+	LogErrors *LogErrorSummary `json:"log_errors,omitempty"`
+}
+
+// BlockFormat is the storage.trace.block configuration of a run. Unset fields
+// are the defaults of the Tempo version.
+type BlockFormat struct {
+	Version                   string   `json:"version,omitempty"`
+	Encoding                  string   `json:"encoding,omitempty"`
+	BloomFilterFalsePositive  *float64 `json:"bloom_filter_false_positive,omitempty"`
+	BloomFilterShardSizeBytes *int     `json:"bloom_filter_shard_size_bytes,omitempty"`
+}
+
+// String describes the block format, e.g. "v2, zstd, bloom FP 0.05, bloom shard 102400 B"
+func (b *BlockFormat) String() string {
+	if b == nil {
+		return ""
+	}
+	var parts []string
+	if b.Version != "" {
+		parts = append(parts, b.Version)
+	}
+	if b.Encoding != "" {
+		parts = append(parts, b.Encoding)
+	}
+	if b.BloomFilterFalsePositive != nil {
+		parts = append(parts, fmt.Sprintf("bloom FP %g", *b.BloomFilterFalsePositive))
+	}
+	if b.BloomFilterShardSizeBytes != nil {
+		parts = append(parts, fmt.Sprintf("bloom shard %d B", *b.BloomFilterShardSizeBytes))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// BaselineQuery selects the baseline run to compare against
+type BaselineQuery struct {
+	Profile string
+	Variant string
+	// TempoVersion restricts the baseline to runs of a Tempo version; empty matches any
+	TempoVersion string
+	// ExcludeRunID skips the runs of the current run ID
+	ExcludeRunID string
+}
+
+// WriteRunMetadata writes run metadata to a JSON file
+func WriteRunMetadata(meta *RunMetadata, outputPath string) error {
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode run metadata: %w", err)
+	}
+	if err := os.WriteFile(outputPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write run metadata: %w", err)
+	}
+	return nil
+}
+
+// LoadRunMetadata reads run metadata from a JSON file
+func LoadRunMetadata(path string) (*RunMetadata, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read run metadata: %w", err)
+	}
+
+	var meta RunMetadata
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("failed to parse run metadata %s: %w", path, err)
+	}
+	return &meta, nil
+}
+
+// ListRuns returns the metadata of the runs in dir. The MetricsFile of each run is
+// joined with dir. Unreadable metadata files are skipped.
+func ListRuns(dir string) ([]*RunMetadata, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+RunMetadataSuffix))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list run metadata in %s: %w", dir, err)
+	}
+
+	var runs []*RunMetadata
+	for _, path := range paths {
+		meta, err := LoadRunMetadata(path)
+		if err != nil {
+			continue
+		}
+		if meta.MetricsFile != "" {
+			meta.MetricsFile = filepath.Join(dir, meta.MetricsFile)
+		}
+		runs = append(runs, meta)
+	}
+	return runs, nil
+}
+
+// FindBaseline returns the most recent run in dir matching the query whose metrics
+// file still exists. The MetricsFile of the returned metadata is joined with dir.
+// Unreadable metadata files are skipped.
+func FindBaseline(dir string, q BaselineQuery) (*RunMetadata, error) {
+	runs, err := ListRuns(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var best *RunMetadata
+	for _, meta := range runs {
+		if !q.matches(meta) {
+			continue
+		}
+		if _, err := os.Stat(meta.MetricsFile); err != nil {
+			continue
+		}
+		if best == nil || meta.StartedAt.After(best.StartedAt) {
+			best = meta
+		}
+	}
+
+	if best == nil {
+		return nil, fmt.Errorf("%w in %s for profile %s (%s, Tempo %s)", ErrNoBaseline, dir, q.Profile, q.Variant, valueOrAny(q.TempoVersion))
+	}
+	return best, nil
+}
+
+// matches reports whether a run satisfies the query
+func (q BaselineQuery) matches(meta *RunMetadata) bool {
+	if meta.MetricsFile == "" || (q.ExcludeRunID != "" && meta.RunID == q.ExcludeRunID) {
+		return false
+	}
+	if meta.Profile != q.Profile || meta.Variant != q.Variant {
+		return false
+	}
+	return q.TempoVersion == "" || strings.EqualFold(meta.TempoVersion, q.TempoVersion)
+}
+
+// valueOrAny returns v, or "any" if v is empty
+func valueOrAny(v string) string {
+	if v == "" {
+		return "any"
+	}
+	return v
+}
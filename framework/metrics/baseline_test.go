@@ -0,0 +1,95 @@
+package metrics
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeTestRun(t *testing.T, dir string, meta RunMetadata, withMetrics bool) {
+	t.Helper()
+	meta.MetricsFile = meta.Profile + "-" + meta.RunID + "-metrics.csv"
+	if withMetrics {
+		if err := os.WriteFile(filepath.Join(dir, meta.MetricsFile), []byte("header\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	path := filepath.Join(dir, meta.Profile+"-"+meta.RunID+RunMetadataSuffix)
+	if err := WriteRunMetadata(&meta, path); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFindBaseline(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	writeTestRun(t, dir, RunMetadata{RunID: "old", Profile: "small", Variant: "stack", TempoVersion: "2.7.0", StartedAt: start}, true)
+	writeTestRun(t, dir, RunMetadata{RunID: "new", Profile: "small", Variant: "stack", TempoVersion: "2.7.0", StartedAt: start.Add(time.Hour)}, true)
+	writeTestRun(t, dir, RunMetadata{RunID: "gone", Profile: "small", Variant: "stack", TempoVersion: "2.7.0", StartedAt: start.Add(2 * time.Hour)}, false)
+	writeTestRun(t, dir, RunMetadata{RunID: "mono", Profile: "small", Variant: "monolithic", TempoVersion: "2.7.0", StartedAt: start.Add(3 * time.Hour)}, true)
+	writeTestRun(t, dir, RunMetadata{RunID: "v28", Profile: "small", Variant: "stack", TempoVersion: "2.8.0", StartedAt: start.Add(4 * time.Hour)}, true)
+	writeTestRun(t, dir, RunMetadata{RunID: "current", Profile: "small", Variant: "stack", TempoVersion: "2.7.0", StartedAt: start.Add(5 * time.Hour)}, true)
+
+	tests := []struct {
+		name  string
+		query BaselineQuery
+		want  string
+	}{
+		{"latest with metrics, excluding current run", BaselineQuery{Profile: "small", Variant: "stack", TempoVersion: "2.7.0", ExcludeRunID: "current"}, "new"},
+		{"other version", BaselineQuery{Profile: "small", Variant: "stack", TempoVersion: "2.8.0", ExcludeRunID: "current"}, "v28"},
+		{"any version", BaselineQuery{Profile: "small", Variant: "stack", ExcludeRunID: "current"}, "v28"},
+		{"variant", BaselineQuery{Profile: "small", Variant: "monolithic"}, "mono"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			baseline, err := FindBaseline(dir, tt.query)
+			if err != nil {
+				t.Fatalf("FindBaseline() error = %v", err)
+			}
+			if baseline.RunID != tt.want {
+				t.Errorf("baseline = %s, want %s", baseline.RunID, tt.want)
+			}
+			if _, err := os.Stat(baseline.MetricsFile); err != nil {
+				t.Errorf("baseline metrics file not resolved: %v", err)
+			}
+		})
+	}
+}
+
+func TestFindBaseline_NoMatch(t *testing.T) {
+	dir := t.TempDir()
+	writeTestRun(t, dir, RunMetadata{RunID: "a", Profile: "small", Variant: "stack", TempoVersion: "2.7.0"}, true)
+	if err := os.WriteFile(filepath.Join(dir, "broken"+RunMetadataSuffix), []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := FindBaseline(dir, BaselineQuery{Profile: "medium", Variant: "stack"})
+	if !errors.Is(err, ErrNoBaseline) {
+		t.Errorf("expected ErrNoBaseline, got %v", err)
+	}
+}
+
+func TestListRuns(t *testing.T) {
+	dir := t.TempDir()
+	writeTestRun(t, dir, RunMetadata{RunID: "a", Profile: "small", Variant: "stack", TestDuration: 5 * time.Minute, Duration: 20 * time.Minute}, false)
+	if err := os.WriteFile(filepath.Join(dir, "broken"+RunMetadataSuffix), []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	runs, err := ListRuns(dir)
+	if err != nil {
+		t.Fatalf("ListRuns() error = %v", err)
+	}
+	if len(runs) != 1 {
+		t.Fatalf("expected 1 run, got %d", len(runs))
+	}
+	if runs[0].Duration != 20*time.Minute || runs[0].TestDuration != 5*time.Minute {
+		t.Errorf("unexpected durations %s, %s", runs[0].Duration, runs[0].TestDuration)
+	}
+	if runs[0].MetricsFile != filepath.Join(dir, "small-a-metrics.csv") {
+		t.Errorf("metrics file not joined with dir: %s", runs[0].MetricsFile)
+	}
+}
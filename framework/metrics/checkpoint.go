@@ -0,0 +1,199 @@
+package metrics
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// ErrCollectionIncomplete is returned by CollectMetricsIncremental when queries failed.
+// The completed queries are kept in the checkpoint, so collecting again resumes.
+var ErrCollectionIncomplete = errors.New("metrics collection incomplete")
+
+// checkpointStateFile describes the collection a checkpoint belongs to
+const checkpointStateFile = "checkpoint.json"
+
+// CheckpointDir returns the checkpoint directory of a metrics file:
+// results/run-metrics.csv has results/run-metrics-checkpoint
+func CheckpointDir(metricsPath string) string {
+	return metricsPath[:len(metricsPath)-len(filepath.Ext(metricsPath))] + "-checkpoint"
+}
+
+// checkpoint stores the results of each completed query of a collection, so a
+// failed collection resumes from the queries that did not complete
+type checkpoint struct {
+	dir   string
+	state checkpointState
+}
+
+// checkpointState identifies a collection. A resumed collection keeps the end of
+// the first attempt so all queries cover the same window.
+type checkpointState struct {
+	Namespace  string     `json:"namespace"`
+	Start      time.Time  `json:"start"`
+	End        time.Time  `json:"end"`
+	Resolution Resolution `json:"resolution"`
+}
+
+// checkpointQuery holds the results of a completed query. Values are stored as
+// strings since JSON cannot represent the NaN of quantiles without samples.
+type checkpointQuery struct {
+	QueryID string             `json:"query_id"`
+	Error   string             `json:"error,omitempty"`
+	Series  []checkpointSeries `json:"series"`
+}
+
+type checkpointSeries struct {
+	QueryID     string            `json:"query_id"`
+	MetricName  string            `json:"metric_name"`
+	Description string            `json:"description"`
+	Category    string            `json:"category"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Timestamps  []int64           `json:"timestamps"`
+	Values      []string          `json:"values"`
+}
+
+// openCheckpoint resumes the checkpoint in dir when it belongs to the same collection
+// (namespace, start and resolution), and otherwise starts a new one
+func openCheckpoint(dir string, state checkpointState) (*checkpoint, bool, error) {
+	cp := &checkpoint{dir: dir, state: state}
+
+	data, err := os.ReadFile(filepath.Join(dir, checkpointStateFile))
+	if err == nil {
+		var previous checkpointState
+		if json.Unmarshal(data, &previous) == nil &&
+			previous.Namespace == state.Namespace &&
+			previous.Start.Equal(state.Start) &&
+			previous.Resolution == state.Resolution {
+			cp.state = previous
+			return cp, true, nil
+		}
+	}
+
+	// Results of another collection cannot be reused
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, false, fmt.Errorf("failed to remove stale checkpoint: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, false, fmt.Errorf("failed to create checkpoint directory: %w", err)
+	}
+	data, err = json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to encode checkpoint state: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, checkpointStateFile), data, 0644); err != nil {
+		return nil, false, fmt.Errorf("failed to write checkpoint state: %w", err)
+	}
+	return cp, false, nil
+}
+
+// queryPath returns the file of a query's results
+func (cp *checkpoint) queryPath(queryID string) string {
+	return filepath.Join(cp.dir, "query-"+queryID+".json")
+}
+
+// completed reports whether the query completed in an earlier attempt
+func (cp *checkpoint) completed(queryID string) bool {
+	if cp == nil {
+		return false
+	}
+	_, err := os.Stat(cp.queryPath(queryID))
+	return err == nil
+}
+
+// save stores the results of a completed query; queryErr is ErrNoData or nil
+func (cp *checkpoint) save(query MetricQuery, results []MetricResult, queryErr error) error {
+	entry := checkpointQuery{QueryID: query.ID, Series: make([]checkpointSeries, 0, len(results))}
+	if queryErr != nil {
+		entry.Error = queryErr.Error()
+	}
+	for _, r := range results {
+		series := checkpointSeries{
+			QueryID:     r.QueryID,
+			MetricName:  r.MetricName,
+			Description: r.Description,
+			Category:    r.Category,
+			Labels:      r.Labels,
+			Timestamps:  make([]int64, 0, len(r.DataPoints)),
+			Values:      make([]string, 0, len(r.DataPoints)),
+		}
+		for _, dp := range r.DataPoints {
+			series.Timestamps = append(series.Timestamps, dp.Timestamp.Unix())
+			series.Values = append(series.Values, strconv.FormatFloat(dp.Value, 'g', -1, 64))
+		}
+		entry.Series = append(entry.Series, series)
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint of %s: %w", query.Name, err)
+	}
+
+	// Write and rename, so an interrupted write does not mark the query completed
+	path := cp.queryPath(query.ID)
+	if err := os.WriteFile(path+".tmp", data, 0644); err != nil {
+		return fmt.Errorf("failed to write checkpoint of %s: %w", query.Name, err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return fmt.Errorf("failed to write checkpoint of %s: %w", query.Name, err)
+	}
+	return nil
+}
+
+// load returns the results of a completed query. A query without data is returned
+// as a result with Error set, as the collection reports it.
+func (cp *checkpoint) load(query MetricQuery) ([]MetricResult, error) {
+	queryID := query.ID
+	data, err := os.ReadFile(cp.queryPath(queryID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint of query %s: %w", queryID, err)
+	}
+
+	var entry checkpointQuery
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint of query %s: %w", queryID, err)
+	}
+
+	if entry.Error != "" {
+		return []MetricResult{{
+			QueryID:     query.ID,
+			MetricName:  query.Name,
+			Description: query.Description,
+			Category:    query.Category,
+			Labels:      map[string]string{},
+			DataPoints:  []DataPoint{},
+			Error:       ErrNoData,
+		}}, nil
+	}
+
+	results := make([]MetricResult, 0, len(entry.Series))
+	for _, s := range entry.Series {
+		if len(s.Timestamps) != len(s.Values) {
+			return nil, fmt.Errorf("invalid checkpoint of query %s: %d timestamps for %d values", queryID, len(s.Timestamps), len(s.Values))
+		}
+		result := MetricResult{
+			QueryID:     s.QueryID,
+			MetricName:  s.MetricName,
+			Description: s.Description,
+			Category:    s.Category,
+			Labels:      s.Labels,
+			DataPoints:  make([]DataPoint, 0, len(s.Values)),
+		}
+		if result.Labels == nil {
+			result.Labels = map[string]string{}
+		}
+		for i, v := range s.Values {
+			value, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid checkpoint of query %s: %w", queryID, err)
+			}
+			result.DataPoints = append(result.DataPoints, DataPoint{Timestamp: time.Unix(s.Timestamps[i], 0), Value: value})
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
@@ -0,0 +1,183 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCheckpointDir(t *testing.T) {
+	if got := CheckpointDir("results/small-metrics.csv"); got != "results/small-metrics-checkpoint" {
+		t.Errorf("unexpected checkpoint dir %q", got)
+	}
+}
+
+func TestCheckpoint_SaveLoad(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	cp, resumed, err := openCheckpoint(filepath.Join(t.TempDir(), "cp"), checkpointState{Namespace: "ns", Start: start, End: start.Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resumed {
+		t.Error("expected a new checkpoint")
+	}
+
+	query := MetricQuery{ID: "40", Name: "query_duration_p99", Category: "query_performance"}
+	if cp.completed(query.ID) {
+		t.Fatal("expected the query to be pending")
+	}
+	results := []MetricResult{{
+		QueryID:    "40",
+		MetricName: "query_duration_p99",
+		Category:   "query_performance",
+		Labels:     map[string]string{"pod": "querier-0"},
+		DataPoints: []DataPoint{{Timestamp: start, Value: 0.25}, {Timestamp: start.Add(time.Minute), Value: math.NaN()}},
+	}}
+	if err := cp.save(query, results, nil); err != nil {
+		t.Fatalf("unexpected save error: %v", err)
+	}
+	if !cp.completed(query.ID) {
+		t.Fatal("expected the query to be completed")
+	}
+
+	loaded, err := cp.load(query)
+	if err != nil {
+		t.Fatalf("unexpected load error: %v", err)
+	}
+	if len(loaded) != 1 || len(loaded[0].DataPoints) != 2 {
+		t.Fatalf("expected 1 series with 2 points, got %+v", loaded)
+	}
+	if loaded[0].Labels["pod"] != "querier-0" || loaded[0].DataPoints[0].Value != 0.25 {
+		t.Errorf("unexpected series %+v", loaded[0])
+	}
+	if !math.IsNaN(loaded[0].DataPoints[1].Value) {
+		t.Errorf("expected NaN to round-trip, got %v", loaded[0].DataPoints[1].Value)
+	}
+}
+
+func TestCheckpoint_SaveLoadNoData(t *testing.T) {
+	cp, _, err := openCheckpoint(filepath.Join(t.TempDir(), "cp"), checkpointState{Namespace: "ns"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	query := MetricQuery{ID: "7", Name: "discarded_spans", Category: "ingestion"}
+	if err := cp.save(query, nil, ErrNoData); err != nil {
+		t.Fatalf("unexpected save error: %v", err)
+	}
+	loaded, err := cp.load(query)
+	if err != nil {
+		t.Fatalf("unexpected load error: %v", err)
+	}
+	if len(loaded) != 1 || !errors.Is(loaded[0].Error, ErrNoData) || loaded[0].MetricName != "discarded_spans" {
+		t.Errorf("expected a no-data result, got %+v", loaded)
+	}
+}
+
+func TestOpenCheckpoint_ResumesSameCollection(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "cp")
+	start := time.Unix(1700000000, 0)
+	state := checkpointState{Namespace: "ns", Start: start, End: start.Add(time.Hour), Resolution: Resolution{Step: time.Minute}}
+
+	cp, _, err := openCheckpoint(dir, state)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := cp.save(MetricQuery{ID: "1"}, nil, nil); err != nil {
+		t.Fatalf("unexpected save error: %v", err)
+	}
+
+	// A retry has a later end, but keeps the window of the first attempt
+	retry := state
+	retry.End = start.Add(2 * time.Hour)
+	cp, resumed, err := openCheckpoint(dir, retry)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resumed || !cp.completed("1") {
+		t.Fatal("expected the checkpoint to be resumed")
+	}
+	if !cp.state.End.Equal(state.End) {
+		t.Errorf("expected the end of the first attempt, got %v", cp.state.End)
+	}
+
+	// Another collection starts over
+	other := state
+	other.Start = start.Add(time.Minute)
+	cp, resumed, err = openCheckpoint(dir, other)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resumed || cp.completed("1") {
+		t.Error("expected a stale checkpoint to be discarded")
+	}
+}
+
+func TestCollectAllMetrics_ResumesFromCheckpoint(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests int
+		failing  = true
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		if failing && strings.Contains(r.URL.Query().Get("query"), "tempo_request_duration_seconds") {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[{"metric":{},"values":[[1700000000,"1"]]}]}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(context.Background(), &ClientConfig{Mode: ModeKubernetes, Namespace: "ns", ThanosURL: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	start := time.Unix(1700000000, 0)
+	end := start.Add(time.Hour)
+	cp, _, err := openCheckpoint(filepath.Join(t.TempDir(), "cp"), checkpointState{Namespace: "ns", Start: start, End: end})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := newCollection(client, client.config).collectAllMetrics(context.Background(), start, end, cp); !errors.Is(err, ErrCollectionIncomplete) {
+		t.Fatalf("expected ErrCollectionIncomplete, got %v", err)
+	}
+	total := len(GetAllQueries("ns"))
+	failed := 0
+	for _, q := range GetAllQueries("ns") {
+		if strings.Contains(q.Query, "tempo_request_duration_seconds") {
+			failed++
+		}
+	}
+	if failed == 0 {
+		t.Fatal("expected queries on tempo_request_duration_seconds")
+	}
+
+	mu.Lock()
+	failing = false
+	requests = 0
+	mu.Unlock()
+
+	results, err := newCollection(client, client.config).collectAllMetrics(context.Background(), start, end, cp)
+	if err != nil {
+		t.Fatalf("unexpected error on resume: %v", err)
+	}
+	if requests != failed {
+		t.Errorf("expected only the %d failed queries to be repeated, got %d requests", failed, requests)
+	}
+	if len(results) != total {
+		t.Errorf("expected %d results, got %d", total, len(results))
+	}
+}
@@ -8,9 +8,12 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/redhat/perf-tests-tempo/test/framework/config"
 	"github.com/redhat/perf-tests-tempo/test/framework/gvr"
 
 	authenticationv1 "k8s.io/api/authentication/v1"
@@ -21,6 +24,36 @@ import (
 	"k8s.io/client-go/rest"
 )
 
+// Mode selects how the client locates the Prometheus API
+type Mode string
+
+const (
+	// ModeOpenShift discovers the thanos-querier Route and authenticates with a
+	// prometheus-k8s service account token (default)
+	ModeOpenShift Mode = "openshift"
+
+	// ModeKubernetes uses a Prometheus Service in the cluster, defaulting to the
+	// kube-prometheus-stack naming. A token is optional in this mode.
+	ModeKubernetes Mode = "kubernetes"
+
+	// ModeRemote uses a user-provided Prometheus-compatible endpoint, e.g. a
+	// Prometheus outside the cluster or the Prometheus API of Cortex or Mimir
+	// (https://mimir.example.com/prometheus). The URL is required; a token and a
+	// tenant are optional.
+	ModeRemote Mode = "remote"
+)
+
+// TenantHeader is the header selecting the tenant of multi-tenant backends such as
+// Cortex and Mimir
+const TenantHeader = "X-Scope-OrgID"
+
+// Defaults for ModeKubernetes (kube-prometheus-stack Helm chart)
+const (
+	DefaultKubernetesMonitoringNamespace = "monitoring"
+	DefaultKubernetesPrometheusService   = "kube-prometheus-stack-prometheus"
+	DefaultKubernetesPrometheusPort      = 9090
+)
+
 // ClientConfig holds configuration for the Prometheus client
 type ClientConfig struct {
 	Namespace           string
@@ -32,6 +65,97 @@ type ClientConfig struct {
 
 	// KubeConfig is optional; if provided, it will be used for auto-discovery
 	KubeConfig *rest.Config
+
+	// Mode selects OpenShift (default) or vanilla Kubernetes discovery, or a remote
+	// endpoint
+	Mode Mode
+
+	// Tenant is sent as the X-Scope-OrgID header, for multi-tenant backends
+	Tenant string
+
+	// PrometheusService and PrometheusPort identify the Prometheus Service in
+	// ModeKubernetes. Defaults to kube-prometheus-stack-prometheus:9090.
+	PrometheusService string
+	PrometheusPort    int
+
+	// UseServiceProxy reaches the Prometheus Service through the API server
+	// service proxy instead of cluster DNS. This works from outside the
+	// cluster without a separate port-forward. Only applies to ModeKubernetes.
+	UseServiceProxy bool
+
+	// OperatorNamespaces adds the "operators" category with the resource usage of
+	// operator pods in these namespaces. Empty disables operator metrics.
+	OperatorNamespaces []string
+
+	// Resolution is the step and rate window of range queries. A zero step uses
+	// config.DefaultMetricsQueryStep.
+	Resolution Resolution
+
+	// QueryTimeout bounds each query of a metrics collection. Zero uses
+	// config.DefaultMetricsQueryTimeout.
+	QueryTimeout time.Duration
+}
+
+// DefaultClientConfig returns an auto-discovering ClientConfig for the namespace.
+// The Prometheus settings of fwConfig (TEMPO_PERF_PROMETHEUS_* environment
+// variables) select the mode and override discovered values; a nil fwConfig
+// reads them from the environment.
+//
+// In ModeKubernetes outside the cluster, Prometheus is reached through the API
+// server service proxy instead of a port-forward, authenticated with the
+// kubeconfig credentials.
+func DefaultClientConfig(namespace string, kubeConfig *rest.Config, fwConfig *config.Config) *ClientConfig {
+	if fwConfig == nil {
+		fwConfig = config.FromEnv()
+	}
+
+	clientConfig := &ClientConfig{
+		Namespace:           namespace,
+		AutoDiscover:        true,
+		MonitoringNamespace: "openshift-monitoring",
+		ServiceAccountName:  "prometheus-k8s",
+		KubeConfig:          kubeConfig,
+		Mode:                ModeOpenShift,
+		OperatorNamespaces:  fwConfig.OperatorNamespaces,
+		Resolution:          ResolutionFromConfig(fwConfig),
+		QueryTimeout:        fwConfig.MetricsQueryTimeout,
+	}
+
+	if Mode(fwConfig.PrometheusMode) == ModeKubernetes {
+		clientConfig.Mode = ModeKubernetes
+		clientConfig.MonitoringNamespace = DefaultKubernetesMonitoringNamespace
+		clientConfig.ServiceAccountName = ""
+		// Cluster DNS is only reachable from inside the cluster
+		if _, err := rest.InClusterConfig(); err != nil {
+			clientConfig.UseServiceProxy = true
+		}
+	}
+
+	if Mode(fwConfig.PrometheusMode) == ModeRemote {
+		clientConfig.Mode = ModeRemote
+		clientConfig.AutoDiscover = false
+		clientConfig.MonitoringNamespace = ""
+		clientConfig.ServiceAccountName = ""
+	}
+
+	if fwConfig.PrometheusURL != "" {
+		clientConfig.ThanosURL = fwConfig.PrometheusURL
+	}
+	clientConfig.Tenant = fwConfig.PrometheusTenant
+	if fwConfig.PrometheusToken != "" {
+		clientConfig.Token = fwConfig.PrometheusToken
+	}
+	if fwConfig.PrometheusNamespace != "" {
+		clientConfig.MonitoringNamespace = fwConfig.PrometheusNamespace
+	}
+	if fwConfig.PrometheusService != "" {
+		clientConfig.PrometheusService = fwConfig.PrometheusService
+	}
+	if fwConfig.PrometheusServiceProxy != nil {
+		clientConfig.UseServiceProxy = *fwConfig.PrometheusServiceProxy
+	}
+
+	return clientConfig
 }
 
 // Client represents a Prometheus/Thanos client
@@ -39,6 +163,15 @@ type Client struct {
 	config     *ClientConfig
 	httpClient *http.Client
 	baseURL    string
+
+	// serviceProxy is set when requests go through the API server service proxy,
+	// which authenticates them with the kubeconfig credentials
+	serviceProxy bool
+
+	// tokenGenerated is set when the token was created by the client, which then
+	// refreshes it when it expires during a collection
+	tokenGenerated bool
+	tokenMu        sync.RWMutex
 }
 
 // PrometheusResponse represents the response from Prometheus API
@@ -52,25 +185,50 @@ type PrometheusResponse struct {
 	Error     string `json:"error,omitempty"`
 }
 
-// PrometheusResult represents a single result from Prometheus
+// PrometheusResult represents a single result from Prometheus.
+// Series of native histograms return their samples in Histograms (range queries)
+// or Histogram (instant queries) instead of Values and Value.
 type PrometheusResult struct {
-	Metric map[string]string `json:"metric"`
-	Values [][]interface{}   `json:"values"`
-	Value  []interface{}     `json:"value,omitempty"`
+	Metric     map[string]string `json:"metric"`
+	Values     [][]interface{}   `json:"values"`
+	Value      []interface{}     `json:"value,omitempty"`
+	Histograms []HistogramSample `json:"histograms,omitempty"`
+	Histogram  *HistogramSample  `json:"histogram,omitempty"`
 }
 
 // NewClient creates a new Prometheus client
 func NewClient(ctx context.Context, config *ClientConfig) (*Client, error) {
+	// Queries are bounded by the query timeout, which may exceed the request timeout
+	timeout := 60 * time.Second
+	if config.QueryTimeout > timeout {
+		timeout = config.QueryTimeout
+	}
 	client := &Client{
 		config: config,
 		httpClient: &http.Client{
-			Timeout: 60 * time.Second,
+			Timeout: timeout,
 			Transport: &http.Transport{
 				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
 			},
 		},
 	}
 
+	if config.Mode == ModeKubernetes {
+		if err := client.setupKubernetes(); err != nil {
+			return nil, err
+		}
+		return client, nil
+	}
+
+	if config.Mode == ModeRemote {
+		if config.ThanosURL == "" {
+			return nil, fmt.Errorf("Prometheus URL is required in remote mode")
+		}
+		client.baseURL = strings.TrimSuffix(config.ThanosURL, "/")
+		fmt.Printf("✅ Using remote Prometheus API: %s\n", client.baseURL)
+		return client, nil
+	}
+
 	// Auto-discover Thanos URL and token if needed
 	if config.AutoDiscover {
 		if config.KubeConfig == nil {
@@ -92,6 +250,7 @@ func NewClient(ctx context.Context, config *ClientConfig) (*Client, error) {
 				return nil, fmt.Errorf("failed to generate token: %w", err)
 			}
 			client.config.Token = token
+			client.tokenGenerated = true
 			fmt.Printf("✅ Generated authentication token\n")
 		}
 	}
@@ -109,6 +268,127 @@ func NewClient(ctx context.Context, config *ClientConfig) (*Client, error) {
 	return client, nil
 }
 
+// setupKubernetes configures the client for a vanilla Kubernetes Prometheus.
+// An explicit ThanosURL takes precedence over the Service; the token is optional.
+func (c *Client) setupKubernetes() error {
+	if c.config.ThanosURL != "" {
+		c.baseURL = strings.TrimSuffix(c.config.ThanosURL, "/")
+		return nil
+	}
+
+	if !c.config.AutoDiscover {
+		return fmt.Errorf("Prometheus URL is required when auto-discovery is disabled")
+	}
+
+	namespace := c.config.MonitoringNamespace
+	if namespace == "" {
+		namespace = DefaultKubernetesMonitoringNamespace
+	}
+	service := c.config.PrometheusService
+	if service == "" {
+		service = DefaultKubernetesPrometheusService
+	}
+	port := c.config.PrometheusPort
+	if port == 0 {
+		port = DefaultKubernetesPrometheusPort
+	}
+
+	if !c.config.UseServiceProxy {
+		c.baseURL = fmt.Sprintf("http://%s.%s.svc:%d", service, namespace, port)
+		fmt.Printf("✅ Using Prometheus service: %s\n", c.baseURL)
+		return nil
+	}
+
+	if c.config.KubeConfig == nil {
+		return fmt.Errorf("KubeConfig is required for the service proxy")
+	}
+
+	// The API server authenticates the request, so reuse the kubeconfig transport
+	transport, err := rest.TransportFor(c.config.KubeConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create API server transport: %w", err)
+	}
+	c.httpClient.Transport = transport
+	c.serviceProxy = true
+	c.baseURL = fmt.Sprintf("%s/api/v1/namespaces/%s/services/http:%s:%d/proxy",
+		strings.TrimSuffix(c.config.KubeConfig.Host, "/"), namespace, service, port)
+	fmt.Printf("✅ Using Prometheus service %s/%s:%d via API server proxy\n", namespace, service, port)
+
+	return nil
+}
+
+// setAuthorization adds the bearer token to a request if one is configured.
+// Requests through the service proxy keep the kubeconfig credentials set by the
+// transport, since a Prometheus token would replace them at the API server.
+func (c *Client) setAuthorization(req *http.Request) {
+	if token := c.token(); token != "" && !c.serviceProxy {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+}
+
+// token returns the current bearer token
+func (c *Client) token() string {
+	c.tokenMu.RLock()
+	defer c.tokenMu.RUnlock()
+	return c.config.Token
+}
+
+// refreshToken generates a new service account token, unless a concurrent request
+// already replaced the stale one
+func (c *Client) refreshToken(ctx context.Context, stale string) error {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+	if c.config.Token != stale {
+		return nil
+	}
+
+	token, err := c.generateToken(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to refresh token: %w", err)
+	}
+	c.config.Token = token
+	fmt.Printf("🔑 Refreshed authentication token\n")
+	return nil
+}
+
+// get sends a GET request to the Prometheus API and returns the response body.
+// A generated token rejected as expired is refreshed and the request retried once.
+func (c *Client) get(ctx context.Context, apiURL string) ([]byte, error) {
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		token := c.token()
+		c.setAuthorization(req)
+		if c.config.Tenant != "" {
+			req.Header.Set(TenantHeader, c.config.Tenant)
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute request: %w", err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response body: %w", err)
+		}
+
+		if resp.StatusCode == http.StatusUnauthorized && c.tokenGenerated && !c.serviceProxy && attempt == 0 {
+			if err := c.refreshToken(ctx, token); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+		}
+		return body, nil
+	}
+}
+
 // discoverThanosURL discovers the Thanos Querier URL from OpenShift using Kubernetes client
 func (c *Client) discoverThanosURL(ctx context.Context) (string, error) {
 	dynamicClient, err := dynamic.NewForConfig(c.config.KubeConfig)
@@ -192,26 +472,9 @@ func (c *Client) QueryRange(ctx context.Context, query string, start, end time.T
 
 	apiURL := fmt.Sprintf("%s/api/v1/query_range?%s", c.baseURL, params.Encode())
 
-	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+	body, err := c.get(ctx, apiURL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+c.config.Token)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+		return nil, err
 	}
 
 	var promResp PrometheusResponse
@@ -226,34 +489,20 @@ func (c *Client) QueryRange(ctx context.Context, query string, start, end time.T
 	return &promResp, nil
 }
 
-// Query executes an instant query against Prometheus
+// Query executes an instant query against Prometheus.
+// A zero evalTime evaluates the query at the current time of the server.
 func (c *Client) Query(ctx context.Context, query string, evalTime time.Time) (*PrometheusResponse, error) {
 	params := url.Values{}
 	params.Add("query", query)
-	params.Add("time", fmt.Sprintf("%d", evalTime.Unix()))
-
-	apiURL := fmt.Sprintf("%s/api/v1/query?%s", c.baseURL, params.Encode())
-
-	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	if !evalTime.IsZero() {
+		params.Add("time", fmt.Sprintf("%d", evalTime.Unix()))
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.config.Token)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
-	}
-	defer resp.Body.Close()
+	apiURL := fmt.Sprintf("%s/api/v1/query?%s", c.baseURL, params.Encode())
 
-	body, err := io.ReadAll(resp.Body)
+	body, err := c.get(ctx, apiURL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+		return nil, err
 	}
 
 	var promResp PrometheusResponse
@@ -267,3 +516,30 @@ func (c *Client) Query(ctx context.Context, query string, evalTime time.Time) (*
 
 	return &promResp, nil
 }
+
+// ServerTime returns the current time of the Prometheus (or Thanos Querier) server,
+// the evaluation time of an instant query without a time
+func (c *Client) ServerTime(ctx context.Context) (time.Time, error) {
+	return ServerTime(ctx, c)
+}
+
+// ServerTime returns the current time of the backend of a Collector
+func ServerTime(ctx context.Context, collector Collector) (time.Time, error) {
+	resp, err := collector.Query(ctx, "vector(time())", time.Time{})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to query server time: %w", err)
+	}
+	if len(resp.Data.Result) == 0 || len(resp.Data.Result[0].Value) < 2 {
+		return time.Time{}, fmt.Errorf("no server time returned")
+	}
+
+	valueStr, ok := resp.Data.Result[0].Value[1].(string)
+	if !ok {
+		return time.Time{}, fmt.Errorf("unexpected value type %T", resp.Data.Result[0].Value[1])
+	}
+	seconds, err := strconv.ParseFloat(valueStr, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid server time %q: %w", valueStr, err)
+	}
+	return time.UnixMilli(int64(seconds * 1000)), nil
+}
@@ -0,0 +1,235 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/config"
+
+	"k8s.io/client-go/rest"
+)
+
+func TestNewClient_KubernetesServiceURL(t *testing.T) {
+	client, err := NewClient(context.Background(), &ClientConfig{
+		Mode:         ModeKubernetes,
+		AutoDiscover: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "http://kube-prometheus-stack-prometheus.monitoring.svc:9090"
+	if client.baseURL != expected {
+		t.Errorf("expected base URL %q, got %q", expected, client.baseURL)
+	}
+}
+
+func TestNewClient_KubernetesServiceProxy(t *testing.T) {
+	client, err := NewClient(context.Background(), &ClientConfig{
+		Mode:                ModeKubernetes,
+		AutoDiscover:        true,
+		MonitoringNamespace: "prom",
+		PrometheusService:   "prometheus-server",
+		PrometheusPort:      80,
+		UseServiceProxy:     true,
+		KubeConfig:          &rest.Config{Host: "https://api.example.com:6443/"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "https://api.example.com:6443/api/v1/namespaces/prom/services/http:prometheus-server:80/proxy"
+	if client.baseURL != expected {
+		t.Errorf("expected base URL %q, got %q", expected, client.baseURL)
+	}
+}
+
+func TestNewClient_KubernetesExplicitURLWithoutToken(t *testing.T) {
+	var authHeader string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeader = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(context.Background(), &ClientConfig{
+		Mode:      ModeKubernetes,
+		ThanosURL: server.URL + "/",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := client.Query(context.Background(), "up", time.Now()); err != nil {
+		t.Fatalf("unexpected query error: %v", err)
+	}
+	if authHeader != "" {
+		t.Errorf("expected no Authorization header without token, got %q", authHeader)
+	}
+}
+
+func TestNewClient_OpenShiftRequiresToken(t *testing.T) {
+	_, err := NewClient(context.Background(), &ClientConfig{
+		ThanosURL: "https://thanos.example.com",
+	})
+	if err == nil {
+		t.Error("expected error when token is missing in OpenShift mode")
+	}
+}
+
+func TestDefaultClientConfig_KubernetesMode(t *testing.T) {
+	fwConfig := config.Default()
+	fwConfig.PrometheusMode = string(ModeKubernetes)
+	fwConfig.PrometheusService = "prometheus-operated"
+	fwConfig.OperatorNamespaces = []string{"tempo-operator-system"}
+
+	clientConfig := DefaultClientConfig("tempo-perf-small", nil, fwConfig)
+
+	if clientConfig.Mode != ModeKubernetes {
+		t.Errorf("expected mode %q, got %q", ModeKubernetes, clientConfig.Mode)
+	}
+	if clientConfig.MonitoringNamespace != DefaultKubernetesMonitoringNamespace {
+		t.Errorf("expected monitoring namespace %q, got %q", DefaultKubernetesMonitoringNamespace, clientConfig.MonitoringNamespace)
+	}
+	if clientConfig.PrometheusService != "prometheus-operated" {
+		t.Errorf("expected service override, got %q", clientConfig.PrometheusService)
+	}
+	if len(clientConfig.OperatorNamespaces) != 1 {
+		t.Errorf("expected operator namespaces from framework config, got %v", clientConfig.OperatorNamespaces)
+	}
+}
+
+func TestDefaultClientConfig_ServiceProxyOverride(t *testing.T) {
+	disabled := false
+	fwConfig := config.Default()
+	fwConfig.PrometheusMode = string(ModeKubernetes)
+	fwConfig.PrometheusServiceProxy = &disabled
+
+	clientConfig := DefaultClientConfig("tempo-perf-small", nil, fwConfig)
+
+	if clientConfig.UseServiceProxy {
+		t.Error("expected service proxy to be disabled by the framework config")
+	}
+}
+
+func TestClient_ServiceProxySkipsBearerToken(t *testing.T) {
+	var authHeader string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeader = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(context.Background(), &ClientConfig{
+		Mode:            ModeKubernetes,
+		AutoDiscover:    true,
+		Token:           "prometheus-token",
+		UseServiceProxy: true,
+		KubeConfig:      &rest.Config{Host: server.URL, BearerToken: "kube-token"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := client.Query(context.Background(), "up", time.Now()); err != nil {
+		t.Fatalf("unexpected query error: %v", err)
+	}
+	if authHeader != "Bearer kube-token" {
+		t.Errorf("expected kubeconfig credentials through the proxy, got %q", authHeader)
+	}
+}
+
+func TestClient_RefreshesExpiredGeneratedToken(t *testing.T) {
+	var tokenRequests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/v1/namespaces/openshift-monitoring/serviceaccounts/prometheus-k8s/token" {
+			tokenRequests++
+			_, _ = w.Write([]byte(`{"kind":"TokenRequest","apiVersion":"authentication.k8s.io/v1","status":{"token":"fresh-token"}}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer fresh-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		config:         &ClientConfig{Token: "expired-token", KubeConfig: &rest.Config{Host: server.URL}},
+		httpClient:     server.Client(),
+		baseURL:        server.URL,
+		tokenGenerated: true,
+	}
+
+	if _, err := client.Query(context.Background(), "up", time.Now()); err != nil {
+		t.Fatalf("unexpected query error: %v", err)
+	}
+	if tokenRequests != 1 {
+		t.Errorf("expected 1 token request, got %d", tokenRequests)
+	}
+	if client.token() != "fresh-token" {
+		t.Errorf("expected the refreshed token, got %q", client.token())
+	}
+}
+
+func TestClient_DoesNotRefreshConfiguredToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		config:     &ClientConfig{Token: "configured-token"},
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+	}
+
+	if _, err := client.Query(context.Background(), "up", time.Now()); err == nil {
+		t.Fatal("expected an error for a rejected configured token")
+	}
+	if client.token() != "configured-token" {
+		t.Errorf("expected the configured token to be kept, got %q", client.token())
+	}
+}
+
+func TestClient_ServerTime(t *testing.T) {
+	var query, evalTime string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query().Get("query")
+		evalTime = r.URL.Query().Get("time")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1700000000.25,"1700000000.25"]}]}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(context.Background(), &ClientConfig{Mode: ModeKubernetes, ThanosURL: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	serverTime, err := client.ServerTime(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := time.UnixMilli(1700000000250); !serverTime.Equal(want) {
+		t.Errorf("expected %v, got %v", want, serverTime)
+	}
+	if query != "vector(time())" || evalTime != "" {
+		t.Errorf("expected vector(time()) at the server time, got %q at %q", query, evalTime)
+	}
+}
+
+func TestClockOffset(t *testing.T) {
+	before := time.Unix(1700000000, 0)
+	after := before.Add(200 * time.Millisecond)
+	if got := ClockOffset(before, after, before.Add(5*time.Second)); got != 4900*time.Millisecond {
+		t.Errorf("expected 4.9s, got %v", got)
+	}
+}
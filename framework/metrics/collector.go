@@ -2,14 +2,23 @@ package metrics
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"sync"
 	"time"
 
 	"github.com/redhat/perf-tests-tempo/test/framework/config"
+	"github.com/redhat/perf-tests-tempo/test/framework/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// ErrNoData is returned for a query without any series, e.g. of a metric the
+// deployment does not expose
+var ErrNoData = errors.New("no data returned (metric may not exist)")
+
 // DataPoint represents a single time-series data point
 type DataPoint struct {
 	Timestamp time.Time
@@ -27,13 +36,81 @@ type MetricResult struct {
 	Error       error
 }
 
+// Collector runs the PromQL queries of a metrics collection against a backend with
+// the Prometheus HTTP API. ThanosCollector queries the OpenShift monitoring stack,
+// PrometheusCollector a Prometheus in the cluster, and RemoteCollector a
+// user-provided endpoint such as Cortex or Mimir. Other backends can be plugged in
+// through a CollectorProvider.
+type Collector interface {
+	// QueryRange executes a range query
+	QueryRange(ctx context.Context, query string, start, end time.Time, step time.Duration) (*PrometheusResponse, error)
+	// Query executes an instant query. A zero evalTime evaluates the query at the
+	// current time of the server.
+	Query(ctx context.Context, query string, evalTime time.Time) (*PrometheusResponse, error)
+}
+
+// CollectorProvider optionally provides the Collector the metrics are queried with,
+// instead of the one selected by the framework configuration
+type CollectorProvider interface {
+	MetricsCollector() Collector
+}
+
+// collection collects the metrics of a namespace through a Collector
+type collection struct {
+	collector          Collector
+	namespace          string
+	operatorNamespaces []string
+	resolution         Resolution
+	queryTimeout       time.Duration
+	// stats records the duration and outcome of each query (nil disables it)
+	stats *queryStatsRecorder
+}
+
+// newCollection creates a collection of the metrics of a namespace, with the
+// operator namespaces, resolution and query timeout of the client configuration
+func newCollection(collector Collector, clientConfig *ClientConfig) *collection {
+	queryTimeout := clientConfig.QueryTimeout
+	if queryTimeout <= 0 {
+		queryTimeout = config.DefaultMetricsQueryTimeout
+	}
+	return &collection{
+		collector:          collector,
+		namespace:          clientConfig.Namespace,
+		operatorNamespaces: clientConfig.OperatorNamespaces,
+		resolution:         clientConfig.Resolution,
+		queryTimeout:       queryTimeout,
+	}
+}
+
 // CollectAllMetrics collects all metrics for the given time range using concurrent queries
 func (c *Client) CollectAllMetrics(ctx context.Context, start, end time.Time) ([]MetricResult, error) {
-	queries := GetAllQueries(c.config.Namespace)
-	step := 60 * time.Second // 1-minute intervals
+	return newCollection(c, c.config).collectAllMetrics(ctx, start, end, nil)
+}
+
+// CollectAll collects all metrics of a namespace for the given time range through
+// collector, like Client.CollectAllMetrics
+func CollectAll(ctx context.Context, collector Collector, namespace string, start, end time.Time) ([]MetricResult, error) {
+	return newCollection(collector, &ClientConfig{Namespace: namespace}).collectAllMetrics(ctx, start, end, nil)
+}
+
+// collectAllMetrics collects all metrics for the given time range. With a checkpoint,
+// queries completed in an earlier attempt are loaded from it, each completed query is
+// saved to it, and an error wrapping ErrCollectionIncomplete is returned when a query
+// failed for another reason than ErrNoData.
+func (c *collection) collectAllMetrics(ctx context.Context, start, end time.Time, cp *checkpoint) ([]MetricResult, error) {
+	queries := GetAllQueries(c.namespace)
+	queries = append(queries, GetOperatorQueries(c.operatorNamespaces)...)
+
+	step := c.resolution.Step
+	if step <= 0 {
+		step = config.DefaultMetricsQueryStep
+	}
+	for i := range queries {
+		queries[i].Query = applyRateWindow(queries[i].Query, c.resolution.RateWindow)
+	}
 
 	maxConcurrentQueries := config.DefaultMaxConcurrentQueries
-	fmt.Printf("📈 Collecting %d metrics (concurrency: %d)...\n\n", len(queries), maxConcurrentQueries)
+	fmt.Printf("📈 Collecting %d metrics (concurrency: %d, %s)...\n\n", len(queries), maxConcurrentQueries, Resolution{Step: step, RateWindow: c.resolution.RateWindow})
 
 	var (
 		results   []MetricResult
@@ -41,6 +118,7 @@ func (c *Client) CollectAllMetrics(ctx context.Context, start, end time.Time) ([
 		wg        sync.WaitGroup
 		sem       = make(chan struct{}, maxConcurrentQueries)
 		completed int32
+		failed    int
 	)
 
 	for _, query := range queries {
@@ -57,13 +135,37 @@ func (c *Client) CollectAllMetrics(ctx context.Context, start, end time.Time) ([
 				return
 			}
 
+			if cp.completed(q.ID) {
+				metricResults, err := cp.load(q)
+				if err == nil {
+					c.stats.recordResumed(q, len(metricResults))
+					mu.Lock()
+					defer mu.Unlock()
+					completed++
+					results = append(results, metricResults...)
+					fmt.Printf("[%d/%d] ♻️  %s: resumed from checkpoint\n", completed, len(queries), q.Name)
+					return
+				}
+				fmt.Printf("⚠️  Warning: %v, collecting %s again\n", err, q.Name)
+			}
+
+			began := time.Now()
 			metricResults, err := c.collectMetric(ctx, q, start, end, step)
+			c.stats.record(q, false, time.Since(began), len(metricResults), err)
+			if cp != nil && (err == nil || errors.Is(err, ErrNoData)) {
+				if saveErr := cp.save(q, metricResults, err); saveErr != nil {
+					fmt.Printf("⚠️  Warning: %v\n", saveErr)
+				}
+			}
 
 			mu.Lock()
 			defer mu.Unlock()
 
 			completed++
 			if err != nil {
+				if !errors.Is(err, ErrNoData) {
+					failed++
+				}
 				fmt.Printf("[%d/%d] ⚠️  %s: %v\n", completed, len(queries), q.Name, err)
 				results = append(results, MetricResult{
 					QueryID:     q.ID,
@@ -86,21 +188,34 @@ func (c *Client) CollectAllMetrics(ctx context.Context, start, end time.Time) ([
 	wg.Wait()
 
 	fmt.Println()
+	if cp != nil {
+		if err := ctx.Err(); err != nil {
+			return results, fmt.Errorf("%w: %v", ErrCollectionIncomplete, err)
+		}
+		if failed > 0 {
+			return results, fmt.Errorf("%w: %d of %d queries failed", ErrCollectionIncomplete, failed, len(queries))
+		}
+	}
 	return results, nil
 }
 
 // collectMetric collects a single metric using range query
-func (c *Client) collectMetric(ctx context.Context, query MetricQuery, start, end time.Time, step time.Duration) ([]MetricResult, error) {
-	resp, err := c.QueryRange(ctx, query.Query, start, end, step)
+func (c *collection) collectMetric(ctx context.Context, query MetricQuery, start, end time.Time, step time.Duration) (results []MetricResult, err error) {
+	ctx, span := startQuerySpan(ctx, "metrics.QueryRange", query, attribute.String("prometheus.step", step.String()))
+	defer func() { endQuerySpan(span, results, err) }()
+
+	queryCtx, cancel := c.withQueryTimeout(ctx)
+	defer cancel()
+	resp, err := c.collector.QueryRange(queryCtx, query.Query, start, end, step)
 	if err != nil {
-		return nil, fmt.Errorf("query failed: %w", err)
+		return nil, c.queryError(queryCtx, err)
 	}
 
 	if len(resp.Data.Result) == 0 {
-		return nil, fmt.Errorf("no data returned (metric may not exist)")
+		return nil, ErrNoData
 	}
 
-	results := make([]MetricResult, 0, len(resp.Data.Result))
+	results = make([]MetricResult, 0, len(resp.Data.Result))
 
 	for _, result := range resp.Data.Result {
 		dataPoints := make([]DataPoint, 0, len(result.Values))
@@ -131,6 +246,12 @@ func (c *Client) collectMetric(ctx context.Context, query MetricQuery, start, en
 			})
 		}
 
+		// Native histogram series are converted to quantile series
+		results = append(results, histogramResults(query, result.Metric, result.Histograms)...)
+		if len(result.Histograms) > 0 && len(result.Values) == 0 {
+			continue
+		}
+
 		results = append(results, MetricResult{
 			QueryID:     query.ID,
 			MetricName:  query.Name,
@@ -144,6 +265,40 @@ func (c *Client) collectMetric(ctx context.Context, query MetricQuery, start, en
 	return results, nil
 }
 
+// withQueryTimeout bounds a query by the query timeout of the collection
+func (c *collection) withQueryTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeoutCause(ctx, c.queryTimeout, ErrQueryTimeout)
+}
+
+// queryError returns the error of a failed query, wrapping ErrQueryTimeout when
+// the query timeout expired
+func (c *collection) queryError(ctx context.Context, err error) error {
+	if errors.Is(context.Cause(ctx), ErrQueryTimeout) {
+		return fmt.Errorf("%w after %s", ErrQueryTimeout, c.queryTimeout)
+	}
+	return fmt.Errorf("query failed: %w", err)
+}
+
+// startQuerySpan starts the span of a Prometheus query of a metric
+func startQuerySpan(ctx context.Context, name string, query MetricQuery, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs,
+		attribute.String("metric.name", query.Name),
+		attribute.String("metric.query_id", query.ID),
+		attribute.String("prometheus.query", query.Query),
+	)
+	return tracing.Start(ctx, name, attrs...)
+}
+
+// endQuerySpan ends the span of a query with its number of series. A query
+// without data is not recorded as an error, since many metrics are optional.
+func endQuerySpan(span trace.Span, results []MetricResult, err error) {
+	span.SetAttributes(attribute.Int("metric.series", len(results)))
+	if errors.Is(err, ErrNoData) {
+		err = nil
+	}
+	tracing.End(span, err)
+}
+
 // countDataPoints counts total data points across all metric results
 func countDataPoints(results []MetricResult) int {
 	total := 0
@@ -155,7 +310,12 @@ func countDataPoints(results []MetricResult) int {
 
 // CollectSummaryMetrics collects summary metrics (P99/max/avg over full test duration) using instant queries
 func (c *Client) CollectSummaryMetrics(ctx context.Context, evalTime time.Time) ([]MetricResult, error) {
-	queries := GetSummaryQueries(c.config.Namespace)
+	return newCollection(c, c.config).collectSummaryMetrics(ctx, evalTime)
+}
+
+// collectSummaryMetrics collects the summary metrics of the namespace at evalTime
+func (c *collection) collectSummaryMetrics(ctx context.Context, evalTime time.Time) ([]MetricResult, error) {
+	queries := GetSummaryQueries(c.namespace)
 
 	fmt.Printf("📊 Collecting %d summary metrics...\n", len(queries))
 
@@ -166,7 +326,9 @@ func (c *Client) CollectSummaryMetrics(ctx context.Context, evalTime time.Time)
 			return results, ctx.Err()
 		}
 
+		began := time.Now()
 		metricResults, err := c.collectInstantMetric(ctx, query, evalTime)
+		c.stats.record(query, true, time.Since(began), len(metricResults), err)
 		if err != nil {
 			fmt.Printf("[%d/%d] ⚠️  %s: %v\n", i+1, len(queries), query.Name, err)
 			results = append(results, MetricResult{
@@ -190,19 +352,29 @@ func (c *Client) CollectSummaryMetrics(ctx context.Context, evalTime time.Time)
 }
 
 // collectInstantMetric collects a single metric using instant query
-func (c *Client) collectInstantMetric(ctx context.Context, query MetricQuery, evalTime time.Time) ([]MetricResult, error) {
-	resp, err := c.Query(ctx, query.Query, evalTime)
+func (c *collection) collectInstantMetric(ctx context.Context, query MetricQuery, evalTime time.Time) (results []MetricResult, err error) {
+	ctx, span := startQuerySpan(ctx, "metrics.Query", query)
+	defer func() { endQuerySpan(span, results, err) }()
+
+	queryCtx, cancel := c.withQueryTimeout(ctx)
+	defer cancel()
+	resp, err := c.collector.Query(queryCtx, query.Query, evalTime)
 	if err != nil {
-		return nil, fmt.Errorf("query failed: %w", err)
+		return nil, c.queryError(queryCtx, err)
 	}
 
 	if len(resp.Data.Result) == 0 {
-		return nil, fmt.Errorf("no data returned (metric may not exist)")
+		return nil, ErrNoData
 	}
 
-	results := make([]MetricResult, 0, len(resp.Data.Result))
+	results = make([]MetricResult, 0, len(resp.Data.Result))
 
 	for _, result := range resp.Data.Result {
+		if result.Histogram != nil {
+			results = append(results, histogramResults(query, result.Metric, []HistogramSample{*result.Histogram})...)
+			continue
+		}
+
 		// Instant queries return a single value in result.Value
 		if len(result.Value) < 2 {
 			continue
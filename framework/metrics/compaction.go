@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+)
+
+// CompactionBacklogMetric is the metric of the blocks waiting to be compacted
+const CompactionBacklogMetric = "compactor_outstanding_blocks"
+
+// CompactionBacklog summarizes the outstanding blocks of the compactor over the
+// load of a run. A backlog that keeps growing means compaction falls behind
+// ingestion, which the load of a short run does not show otherwise.
+type CompactionBacklog struct {
+	// Samples is the number of samples of the backlog; zero when it was not collected
+	Samples int     `json:"samples"`
+	Start   float64 `json:"start"`
+	End     float64 `json:"end"`
+	Peak    float64 `json:"peak"`
+	// MaxGrowth is the growth allowed by the profile; nil when it is not checked
+	MaxGrowth *int `json:"max_growth,omitempty"`
+}
+
+// NewCompactionBacklog returns the compaction backlog of results, from the first
+// to the last sample of CompactionBacklogMetric. Samples of several series, e.g.
+// one per phase, are merged in time order; NaN samples are skipped.
+func NewCompactionBacklog(results []MetricResult) *CompactionBacklog {
+	var points []DataPoint
+	for _, r := range results {
+		if r.MetricName != CompactionBacklogMetric || r.Error != nil {
+			continue
+		}
+		for _, p := range r.DataPoints {
+			if !math.IsNaN(p.Value) {
+				points = append(points, p)
+			}
+		}
+	}
+
+	b := &CompactionBacklog{Samples: len(points)}
+	if len(points) == 0 {
+		return b
+	}
+	sort.SliceStable(points, func(i, j int) bool { return points[i].Timestamp.Before(points[j].Timestamp) })
+	b.Start = points[0].Value
+	b.End = points[len(points)-1].Value
+	for _, p := range points {
+		b.Peak = math.Max(b.Peak, p.Value)
+	}
+	return b
+}
+
+// Growth returns the change of the backlog from the start to the end of the load
+func (b *CompactionBacklog) Growth() float64 {
+	return b.End - b.Start
+}
+
+// Checked reports whether the growth was checked against a threshold, which
+// needs both the threshold and samples of the backlog
+func (b *CompactionBacklog) Checked() bool {
+	return b != nil && b.MaxGrowth != nil && b.Samples > 0
+}
+
+// Violated reports whether the backlog grew more than allowed
+func (b *CompactionBacklog) Violated() bool {
+	return b.Checked() && b.Growth() > float64(*b.MaxGrowth)
+}
@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestNewCompactionBacklog(t *testing.T) {
+	start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	at := func(minutes int, value float64) DataPoint {
+		return DataPoint{Timestamp: start.Add(time.Duration(minutes) * time.Minute), Value: value}
+	}
+	results := []MetricResult{
+		// Series of two phases, the later one first
+		{MetricName: CompactionBacklogMetric, DataPoints: []DataPoint{at(10, 30), at(15, 22), at(20, math.NaN())}},
+		{MetricName: CompactionBacklogMetric, DataPoints: []DataPoint{at(0, math.NaN()), at(1, 4), at(5, 12)}},
+		{MetricName: "compactor_blocks_compacted", DataPoints: []DataPoint{at(0, 100)}},
+		{MetricName: CompactionBacklogMetric, Error: errors.New("query failed"), DataPoints: []DataPoint{at(0, 1000)}},
+	}
+
+	backlog := NewCompactionBacklog(results)
+	if backlog.Samples != 4 || backlog.Start != 4 || backlog.End != 22 || backlog.Peak != 30 {
+		t.Fatalf("expected 4 samples from 4 to 22 blocks with a peak of 30, got %+v", backlog)
+	}
+	if backlog.Growth() != 18 {
+		t.Errorf("expected a growth of 18 blocks, got %v", backlog.Growth())
+	}
+	if backlog.Checked() || backlog.Violated() {
+		t.Error("expected no check without a threshold")
+	}
+
+	maxGrowth := 20
+	backlog.MaxGrowth = &maxGrowth
+	if !backlog.Checked() || backlog.Violated() {
+		t.Errorf("expected a growth of 18 blocks to pass a threshold of 20")
+	}
+	maxGrowth = 10
+	if !backlog.Violated() {
+		t.Errorf("expected a growth of 18 blocks to violate a threshold of 10")
+	}
+}
+
+func TestNewCompactionBacklog_NoSamples(t *testing.T) {
+	maxGrowth := 0
+	backlog := NewCompactionBacklog(nil)
+	backlog.MaxGrowth = &maxGrowth
+	if backlog.Samples != 0 || backlog.Checked() || backlog.Violated() {
+		t.Errorf("expected an unchecked backlog without samples, got %+v", backlog)
+	}
+
+	var unset *CompactionBacklog
+	if unset.Violated() {
+		t.Error("expected a nil backlog not to be violated")
+	}
+}
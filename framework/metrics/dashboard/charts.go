@@ -5,6 +5,8 @@ type CategoryChartConfig struct {
 	Title       string
 	Description string
 	Charts      []ChartDefinition
+	// Optional categories are omitted when no metrics were collected for them
+	Optional bool
 }
 
 // ChartDefinition defines which metrics go into a chart
@@ -14,17 +16,38 @@ type ChartDefinition struct {
 	Description string
 	Type        ChartType
 	Options     ChartOptions
+	// Series defines a derived chart that combines series from any category.
+	// When set, MetricNames is ignored.
+	Series []SeriesRef
+}
+
+// SeriesRef selects metric series for a derived chart
+type SeriesRef struct {
+	MetricName string
+	// Labels restricts the series to those matching all given labels
+	Labels map[string]string
+	// Axis is the Y axis ID the series is plotted against ("y" by default)
+	Axis string
 }
 
 // GetCategoryOrder returns the ordered list of category names
 func GetCategoryOrder() []string {
 	return []string{
 		"ingestion",
+		"backpressure",
+		"tenants",
+		"collector",
+		"metrics_generator",
 		"compactor",
 		"storage",
 		"resources",
+		"nodes",
+		"go_runtime",
+		"operators",
+		"autoscaling",
 		"query_performance",
 		"querier",
+		"jaeger_query",
 	}
 }
 
@@ -42,6 +65,13 @@ func GetCategoryChartConfigs() map[string]CategoryChartConfig {
 					Type:        ChartTypeLine,
 					Options:     ChartOptions{YAxisLabel: "spans/sec", ShowLegend: true},
 				},
+				{
+					MetricNames: []string{"accepted_spans_rate_by_pod"},
+					Title:       "Accepted Spans per Pod",
+					Description: "Rate of spans accepted by each Tempo pod, to check the load balance across replicas",
+					Type:        ChartTypeLine,
+					Options:     ChartOptions{YAxisLabel: "spans/sec", ShowLegend: true},
+				},
 				{
 					MetricNames: []string{"bytes_received_rate"},
 					Title:       "Bytes Received Rate",
@@ -125,6 +155,38 @@ func GetCategoryChartConfigs() map[string]CategoryChartConfig {
 					Type:        ChartTypeLine,
 					Options:     ChartOptions{YAxisLabel: "blocks"},
 				},
+				{
+					MetricNames: []string{"compaction_duration_p50", "compaction_duration_p99"},
+					Title:       "Compaction Duration",
+					Description: "P50 and P99 duration of a compaction cycle. Cycles getting longer under steady load precede a growing backlog",
+					Type:        ChartTypeLine,
+					Options:     ChartOptions{YAxisLabel: "seconds", YAxisUnit: "seconds", ShowLegend: true},
+				},
+				{
+					MetricNames: []string{"compaction_errors_rate"},
+					Title:       "Compaction Errors",
+					Description: "Rate of failed compactions, whose blocks stay in the backlog",
+					Type:        ChartTypeLine,
+					Options:     ChartOptions{YAxisLabel: "errors/sec"},
+				},
+				{
+					Title:       "Compaction Backlog vs Ingestion",
+					Description: "Outstanding blocks against ingestion rate and compactor CPU. A backlog that keeps growing while ingestion is steady means compaction is not keeping up",
+					Type:        ChartTypeLine,
+					Series: []SeriesRef{
+						{MetricName: "compactor_outstanding_blocks", Axis: "y"},
+						{MetricName: "accepted_spans_rate", Axis: "y2"},
+						{MetricName: "cpu_usage_by_component", Labels: map[string]string{"component": "compactor"}, Axis: "y3"},
+					},
+					Options: ChartOptions{
+						YAxisLabel: "blocks",
+						ShowLegend: true,
+						SecondaryAxes: []AxisOptions{
+							{ID: "y2", Label: "spans/sec"},
+							{ID: "y3", Label: "cores", Unit: "cores"},
+						},
+					},
+				},
 				{
 					MetricNames: []string{"retention_deleted_total", "retention_marked_for_deletion"},
 					Title:       "Retention Activity",
@@ -159,6 +221,20 @@ func GetCategoryChartConfigs() map[string]CategoryChartConfig {
 					Type:        ChartTypeLine,
 					Options:     ChartOptions{YAxisLabel: "blocks", ShowLegend: true},
 				},
+				{
+					MetricNames: []string{"storage_footprint_bytes"},
+					Title:       "Storage Footprint",
+					Description: "Bytes stored in the trace storage; MinIO updates its bucket usage in a background scan, so it lags writes",
+					Type:        ChartTypeArea,
+					Options:     ChartOptions{YAxisLabel: "bytes", YAxisUnit: "bytes"},
+				},
+				{
+					MetricNames: []string{"storage_footprint_objects"},
+					Title:       "Stored Objects",
+					Description: "Number of objects in the trace storage",
+					Type:        ChartTypeLine,
+					Options:     ChartOptions{YAxisLabel: "objects"},
+				},
 			},
 		},
 		"resources": {
@@ -237,6 +313,324 @@ func GetCategoryChartConfigs() map[string]CategoryChartConfig {
 				},
 			},
 		},
+		"tenants": {
+			Title:       "Tenants",
+			Description: "Ingestion and query load of each tenant; in a noisy-neighbor run they show the load of the aggressor next to the victim, and which tenant Tempo discarded spans of",
+			Optional:    true,
+			Charts: []ChartDefinition{
+				{
+					MetricNames: []string{"tenant_bytes_received_rate"},
+					Title:       "Bytes Received by Tenant",
+					Description: "Bytes received per second by the distributors, by tenant",
+					Type:        ChartTypeLine,
+					Options:     ChartOptions{YAxisLabel: "bytes/sec", YAxisUnit: "bytes", ShowLegend: true},
+				},
+				{
+					MetricNames: []string{"tenant_spans_received_rate"},
+					Title:       "Spans Received by Tenant",
+					Description: "Spans received per second by the distributors, by tenant",
+					Type:        ChartTypeLine,
+					Options:     ChartOptions{YAxisLabel: "spans/sec", ShowLegend: true},
+				},
+				{
+					MetricNames: []string{"tenant_discarded_spans_rate"},
+					Title:       "Discarded Spans by Tenant",
+					Description: "Spans discarded per second, by tenant and reason",
+					Type:        ChartTypeLine,
+					Options:     ChartOptions{YAxisLabel: "spans/sec", ShowLegend: true, ColorScheme: "red"},
+				},
+				{
+					MetricNames: []string{"tenant_queries_rate"},
+					Title:       "Queries by Tenant",
+					Description: "Queries handled per second by the query frontend, by tenant",
+					Type:        ChartTypeLine,
+					Options:     ChartOptions{YAxisLabel: "queries/sec", ShowLegend: true},
+				},
+				{
+					MetricNames: []string{"tenant_live_traces"},
+					Title:       "Live Traces by Tenant",
+					Description: "Live traces held by the ingesters, by tenant",
+					Type:        ChartTypeLine,
+					Options:     ChartOptions{YAxisLabel: "traces", ShowLegend: true},
+				},
+			},
+		},
+		"nodes": {
+			Title:       "Node I/O",
+			Description: "Disk and network throughput of the nodes running Tempo pods, including the traffic of other workloads sharing them; storage or NIC saturation there often explains a throughput ceiling",
+			Optional:    true,
+			Charts: []ChartDefinition{
+				{
+					MetricNames: []string{"node_disk_written_bytes_rate"},
+					Title:       "Node Disk Write Throughput",
+					Description: "Bytes written per second to the disks of the nodes running Tempo pods",
+					Type:        ChartTypeLine,
+					Options:     ChartOptions{YAxisLabel: "bytes/sec", YAxisUnit: "bytes", ShowLegend: true},
+				},
+				{
+					MetricNames: []string{"node_disk_read_bytes_rate"},
+					Title:       "Node Disk Read Throughput",
+					Description: "Bytes read per second from the disks of the nodes running Tempo pods",
+					Type:        ChartTypeLine,
+					Options:     ChartOptions{YAxisLabel: "bytes/sec", YAxisUnit: "bytes", ShowLegend: true},
+				},
+				{
+					MetricNames: []string{"node_disk_io_utilization"},
+					Title:       "Node Disk Utilization",
+					Description: "Fraction of time the busiest disk of each node was busy; near 100% the disk is saturated",
+					Type:        ChartTypeLine,
+					Options:     ChartOptions{YAxisLabel: "busy", YAxisUnit: "percent", ShowLegend: true},
+				},
+				{
+					MetricNames: []string{"node_network_transmit_bytes_rate"},
+					Title:       "Node Network Transmit Throughput",
+					Description: "Bytes sent per second by the physical interfaces of the nodes running Tempo pods",
+					Type:        ChartTypeLine,
+					Options:     ChartOptions{YAxisLabel: "bytes/sec", YAxisUnit: "bytes", ShowLegend: true},
+				},
+				{
+					MetricNames: []string{"node_network_receive_bytes_rate"},
+					Title:       "Node Network Receive Throughput",
+					Description: "Bytes received per second by the physical interfaces of the nodes running Tempo pods",
+					Type:        ChartTypeLine,
+					Options:     ChartOptions{YAxisLabel: "bytes/sec", YAxisUnit: "bytes", ShowLegend: true},
+				},
+			},
+		},
+		"go_runtime": {
+			Title:       "Go Runtime",
+			Description: "Heap, GC activity, goroutines, restarts and OOM kills of the Tempo containers, to compare Go runtime tuning (tempo.env GOGC and GOMEMLIMIT) across runs. GC thrash, many short cycles as the heap nears its target, often explains ingester latency spikes",
+			Optional:    true,
+			Charts: []ChartDefinition{
+				{
+					MetricNames: []string{"go_heap_inuse"},
+					Title:       "Go Heap In Use",
+					Description: "Go heap bytes in use by each Tempo container",
+					Type:        ChartTypeLine,
+					Options:     ChartOptions{YAxisLabel: "bytes", YAxisUnit: "bytes", ShowLegend: true},
+				},
+				{
+					MetricNames: []string{"go_next_gc_bytes"},
+					Title:       "GC Target Heap Size",
+					Description: "Heap size that triggers the next GC cycle, set by GOGC and capped by GOMEMLIMIT. A heap in use close to it means GC runs continuously",
+					Type:        ChartTypeLine,
+					Options:     ChartOptions{YAxisLabel: "bytes", YAxisUnit: "bytes", ShowLegend: true},
+				},
+				{
+					MetricNames: []string{"go_gc_cycles_rate"},
+					Title:       "GC Cycles",
+					Description: "GC cycles per second; a sharp rise at a steady load is GC thrash",
+					Type:        ChartTypeLine,
+					Options:     ChartOptions{YAxisLabel: "cycles/sec", ShowLegend: true},
+				},
+				{
+					MetricNames: []string{"go_gc_pause_rate"},
+					Title:       "GC Pause Time",
+					Description: "Time spent in GC stop-the-world pauses per second; lower GOGC values trade CPU and pauses for memory",
+					Type:        ChartTypeLine,
+					Options:     ChartOptions{YAxisLabel: "seconds/sec", YAxisUnit: "seconds", ShowLegend: true},
+				},
+				{
+					MetricNames: []string{"go_gc_pause_max"},
+					Title:       "Max GC Pause",
+					Description: "Longest recent stop-the-world pause, which adds directly to request latency",
+					Type:        ChartTypeLine,
+					Options:     ChartOptions{YAxisLabel: "seconds", YAxisUnit: "seconds", ShowLegend: true},
+				},
+				{
+					MetricNames: []string{"go_goroutines"},
+					Title:       "Goroutines",
+					Description: "Goroutines of each Tempo container; steady growth under constant load points to blocked requests",
+					Type:        ChartTypeLine,
+					Options:     ChartOptions{YAxisLabel: "goroutines", ShowLegend: true},
+				},
+				{
+					MetricNames: []string{"tempo_container_restarts"},
+					Title:       "Tempo Container Restarts",
+					Description: "Restarts of the Tempo container of each pod since it was created",
+					Type:        ChartTypeLine,
+					Options:     ChartOptions{YAxisLabel: "restarts", ShowLegend: true},
+				},
+				{
+					MetricNames: []string{"tempo_oom_killed"},
+					Title:       "OOM Kills",
+					Description: "1 while the last termination of the Tempo container of a pod was an OOM kill",
+					Type:        ChartTypeLine,
+					Options:     ChartOptions{YAxisLabel: "OOM killed", ShowLegend: true, ColorScheme: "red"},
+				},
+			},
+		},
+		"backpressure": {
+			Title:       "Ingest Backpressure",
+			Description: "Queues along the ingest pipeline in order, from k6 to the ingester flush queue, on a shared time axis. The first stage whose queue grows is the one that saturated; later stages only see the load it lets through",
+			Optional:    true,
+			Charts: []ChartDefinition{
+				{
+					Title:       "k6 In-Flight Requests",
+					Description: "Busy VUs against dropped iterations. Dropped iterations mean every VU was waiting on Tempo and the target rate was not sent",
+					Type:        ChartTypeLine,
+					Series: []SeriesRef{
+						{MetricName: "k6_active_vus", Axis: "y"},
+						{MetricName: "k6_dropped_iterations_rate", Axis: "y2"},
+					},
+					Options: ChartOptions{
+						YAxisLabel:     "VUs",
+						ShowLegend:     true,
+						SharedTimeAxis: true,
+						SecondaryAxes:  []AxisOptions{{ID: "y2", Label: "iterations/sec"}},
+					},
+				},
+				{
+					Title:       "Collector Exporter Queue",
+					Description: "Batches queued by the OTel Collector exporters against the queue capacity; a full queue drops spans",
+					Type:        ChartTypeLine,
+					Series: []SeriesRef{
+						{MetricName: "collector_exporter_queue_size"},
+						{MetricName: "collector_exporter_queue_capacity"},
+					},
+					Options: ChartOptions{YAxisLabel: "batches", ShowLegend: true, SharedTimeAxis: true},
+				},
+				{
+					Title:       "Kafka Consumer Lag",
+					Description: "Messages buffered in Kafka that the consuming collector has not read yet; a growing lag means Tempo ingests slower than the load",
+					Type:        ChartTypeLine,
+					Series:      []SeriesRef{{MetricName: "collector_kafka_consumer_lag"}},
+					Options:     ChartOptions{YAxisLabel: "messages", SharedTimeAxis: true},
+				},
+				{
+					Title:       "Distributor Push Latency P99",
+					Description: "99th percentile latency of pushes to the distributor, which waits for the ingesters to acknowledge",
+					Type:        ChartTypeLine,
+					Series:      []SeriesRef{{MetricName: "distributor_push_duration_p99"}},
+					Options:     ChartOptions{YAxisLabel: "seconds", YAxisUnit: "seconds", SharedTimeAxis: true},
+				},
+				{
+					Title:       "Ingester Flush Queue",
+					Description: "Traces and blocks waiting to be flushed by each ingester",
+					Type:        ChartTypeLine,
+					Series:      []SeriesRef{{MetricName: "ingester_flush_queue_length"}},
+					Options:     ChartOptions{YAxisLabel: "items", ShowLegend: true, SharedTimeAxis: true},
+				},
+			},
+		},
+		"collector": {
+			Title:       "OTel Collector Pipeline",
+			Description: "Health of the OpenTelemetry Collector between k6 and Tempo. Collector backpressure often looks like Tempo slowness: refused or dropped spans here never reached Tempo",
+			Optional:    true,
+			Charts: []ChartDefinition{
+				{
+					MetricNames: []string{"collector_accepted_spans_rate", "collector_sent_spans_rate"},
+					Title:       "Collector Span Throughput",
+					Description: "Spans accepted by the receivers against spans sent to Tempo by the exporters",
+					Type:        ChartTypeLine,
+					Options:     ChartOptions{YAxisLabel: "spans/sec", ShowLegend: true},
+				},
+				{
+					MetricNames: []string{"collector_refused_spans_rate", "collector_send_failed_spans_rate", "collector_enqueue_failed_spans_rate"},
+					Title:       "Collector Span Failures",
+					Description: "Spans refused by the receivers, failed exports to Tempo, and spans dropped by a full sending queue",
+					Type:        ChartTypeLine,
+					Options:     ChartOptions{YAxisLabel: "spans/sec", ShowLegend: true, ColorScheme: "red"},
+				},
+				{
+					Title:       "Collector Exporter Queue",
+					Description: "Batches queued by the exporters against the queue capacity; a full queue drops spans",
+					Type:        ChartTypeLine,
+					Series: []SeriesRef{
+						{MetricName: "collector_exporter_queue_size"},
+						{MetricName: "collector_exporter_queue_capacity"},
+					},
+					Options: ChartOptions{YAxisLabel: "batches", ShowLegend: true},
+				},
+				{
+					MetricNames: []string{"collector_batch_send_size_avg"},
+					Title:       "Collector Batch Size",
+					Description: "Average spans per batch sent by the batch processor, when the pipeline has one",
+					Type:        ChartTypeLine,
+					Options:     ChartOptions{YAxisLabel: "spans"},
+				},
+			},
+		},
+		"metrics_generator": {
+			Title:       "Metrics-Generator",
+			Description: "Span metrics and service graphs derived from the ingested spans (tempo.metricsGenerator). Compare against a run without the generator to measure its overhead on ingestion and resources",
+			Optional:    true,
+			Charts: []ChartDefinition{
+				{
+					Title:       "Generator Span Throughput",
+					Description: "Spans accepted by the distributors against spans received by the metrics-generator",
+					Type:        ChartTypeLine,
+					Series: []SeriesRef{
+						{MetricName: "accepted_spans_rate"},
+						{MetricName: "generator_spans_received_rate"},
+					},
+					Options: ChartOptions{YAxisLabel: "spans/sec", ShowLegend: true},
+				},
+				{
+					MetricNames: []string{"generator_spans_discarded_rate", "generator_push_failures_rate"},
+					Title:       "Generator Span Losses",
+					Description: "Spans discarded by the metrics-generator, by reason, and failed pushes from the distributors",
+					Type:        ChartTypeLine,
+					Options:     ChartOptions{YAxisLabel: "per sec", ShowLegend: true, ColorScheme: "red"},
+				},
+				{
+					MetricNames: []string{"generator_active_series"},
+					Title:       "Generator Active Series",
+					Description: "Series held by the metrics-generator registry; their number drives its memory usage",
+					Type:        ChartTypeLine,
+					Options:     ChartOptions{YAxisLabel: "series", ShowLegend: true},
+				},
+				{
+					MetricNames: []string{"generator_remote_write_samples_rate", "generator_remote_write_failed_samples_rate"},
+					Title:       "Generator Remote Write",
+					Description: "Generated samples sent to and failed to send to the remote write endpoint, when one is configured",
+					Type:        ChartTypeLine,
+					Options:     ChartOptions{YAxisLabel: "samples/sec", ShowLegend: true},
+				},
+			},
+		},
+		"operators": {
+			Title:       "Operator Overhead",
+			Description: "CPU and memory usage of the Tempo and OpenTelemetry operators (collected with --collect-operator-metrics)",
+			Optional:    true,
+			Charts: []ChartDefinition{
+				{
+					MetricNames: []string{"operator_memory_usage"},
+					Title:       "Operator Memory Usage",
+					Description: "Memory working set bytes used by the operator pods",
+					Type:        ChartTypeLine,
+					Options:     ChartOptions{YAxisLabel: "bytes", YAxisUnit: "bytes", ShowLegend: true},
+				},
+				{
+					MetricNames: []string{"operator_cpu_usage"},
+					Title:       "Operator CPU Usage",
+					Description: "CPU cores used by the operator pods; spikes indicate reconcile storms",
+					Type:        ChartTypeLine,
+					Options:     ChartOptions{YAxisLabel: "cores", ShowLegend: true},
+				},
+			},
+		},
+		"autoscaling": {
+			Title:       "Autoscaling",
+			Description: "Replica counts of the TempoStack components scaled by HorizontalPodAutoscalers (tempo.autoscaling)",
+			Optional:    true,
+			Charts: []ChartDefinition{
+				{
+					MetricNames: []string{"hpa_current_replicas"},
+					Title:       "Current Replicas",
+					Description: "Replica count of each autoscaled component over time",
+					Type:        ChartTypeLine,
+					Options:     ChartOptions{YAxisLabel: "replicas", ShowLegend: true},
+				},
+				{
+					MetricNames: []string{"hpa_desired_replicas"},
+					Title:       "Desired Replicas",
+					Description: "Replica count each HPA wants to scale to; a gap to the current count shows scaling lag",
+					Type:        ChartTypeLine,
+					Options:     ChartOptions{YAxisLabel: "replicas", ShowLegend: true},
+				},
+			},
+		},
 		"query_performance": {
 			Title:       "Query Performance",
 			Description: "Query throughput and latency metrics",
@@ -291,6 +685,41 @@ func GetCategoryChartConfigs() map[string]CategoryChartConfig {
 				},
 			},
 		},
+		"jaeger_query": {
+			Title:       "Jaeger Query",
+			Description: "Latency and load of the Jaeger HTTP API served by tempo-query (--test-type=jaeger), whose performance differs from the native Tempo API",
+			Optional:    true,
+			Charts: []ChartDefinition{
+				{
+					MetricNames: []string{"jaeger_request_duration_p99"},
+					Title:       "Jaeger API Latency P99",
+					Description: "99th percentile latency of each Jaeger endpoint (find_traces, get_trace, services, operations)",
+					Type:        ChartTypeLine,
+					Options:     ChartOptions{YAxisLabel: "seconds", YAxisUnit: "seconds", ShowLegend: true},
+				},
+				{
+					MetricNames: []string{"jaeger_requests_rate"},
+					Title:       "Jaeger API Requests",
+					Description: "Requests per second to each Jaeger endpoint",
+					Type:        ChartTypeLine,
+					Options:     ChartOptions{YAxisLabel: "requests/sec", ShowLegend: true},
+				},
+				{
+					MetricNames: []string{"tempo_query_memory_usage"},
+					Title:       "tempo-query Memory Usage",
+					Description: "Memory working set bytes of the tempo-query containers",
+					Type:        ChartTypeLine,
+					Options:     ChartOptions{YAxisLabel: "bytes", YAxisUnit: "bytes", ShowLegend: true},
+				},
+				{
+					MetricNames: []string{"tempo_query_cpu_usage"},
+					Title:       "tempo-query CPU Usage",
+					Description: "CPU cores used by the tempo-query containers",
+					Type:        ChartTypeLine,
+					Options:     ChartOptions{YAxisLabel: "cores", ShowLegend: true},
+				},
+			},
+		},
 	}
 }
 
@@ -307,15 +736,33 @@ func GetMetricUnit(metricName string) string {
 		"cpu_usage_by_component":            "cores",
 		"cpu_max_total":                     "cores",
 		"cpu_max_by_component":              "cores",
+		"operator_memory_usage":             "bytes",
+		"operator_cpu_usage":                "cores",
+		"node_disk_written_bytes_rate":      "bytes",
+		"node_disk_read_bytes_rate":         "bytes",
+		"node_disk_io_utilization":          "percent",
+		"node_network_transmit_bytes_rate":  "bytes",
+		"node_network_receive_bytes_rate":   "bytes",
 		"bytes_received_rate":               "bytes",
+		"tenant_bytes_received_rate":        "bytes",
+		"storage_footprint_bytes":           "bytes",
 		"compactor_bytes_written":           "bytes",
 		"query_frontend_bytes_inspected":    "bytes",
 		"distributor_push_duration_p99":     "seconds",
 		"backend_read_latency_p99":          "seconds",
 		"blocklist_poll_duration_p99":       "seconds",
+		"compaction_duration_p50":           "seconds",
+		"compaction_duration_p99":           "seconds",
 		"query_frontend_queue_duration_p99": "seconds",
 		"query_duration_p99":                "seconds",
 		"query_duration_p50":                "seconds",
+		"jaeger_request_duration_p99":       "seconds",
+		"tempo_query_memory_usage":          "bytes",
+		"tempo_query_cpu_usage":             "cores",
+		"go_heap_inuse":                     "bytes",
+		"go_next_gc_bytes":                  "bytes",
+		"go_gc_pause_rate":                  "seconds",
+		"go_gc_pause_max":                   "seconds",
 	}
 
 	if unit, ok := unitMap[metricName]; ok {
@@ -331,6 +778,7 @@ func GetMetricQuery(metricName string) string {
 		// Ingestion metrics
 		"accepted_spans_rate":         `sum(rate(tempo_receiver_accepted_spans{namespace="{namespace}"}[1m]))`,
 		"refused_spans_rate":          `sum(rate(tempo_receiver_refused_spans{namespace="{namespace}"}[1m]))`,
+		"accepted_spans_rate_by_pod":  `sum(rate(tempo_receiver_accepted_spans{namespace="{namespace}"}[1m])) by (pod)`,
 		"bytes_received_rate":         `sum(rate(tempo_distributor_bytes_received_total{namespace="{namespace}"}[1m])) by (status)`,
 		"distributor_push_duration_p99": `histogram_quantile(0.99, sum(rate(tempo_distributor_push_duration_seconds_bucket{namespace="{namespace}"}[1m])) by (le))`,
 		"ingester_append_failures":    `sum(rate(tempo_ingester_failed_flushes_total{namespace="{namespace}"}[1m]))`,
@@ -341,17 +789,29 @@ func GetMetricQuery(metricName string) string {
 		"ingester_traces_created":     `sum(tempo_ingester_traces_created_total{namespace="{namespace}"})`,
 		"distributor_spans_received":  `sum(tempo_distributor_spans_received_total{namespace="{namespace}"})`,
 
+		// Tenant metrics
+		"tenant_bytes_received_rate":  `sum(rate(tempo_distributor_bytes_received_total{namespace="{namespace}"}[1m])) by (tenant)`,
+		"tenant_spans_received_rate":  `sum(rate(tempo_distributor_spans_received_total{namespace="{namespace}"}[1m])) by (tenant)`,
+		"tenant_discarded_spans_rate": `sum(rate(tempo_discarded_spans_total{namespace="{namespace}"}[1m])) by (tenant, reason)`,
+		"tenant_queries_rate":         `sum(rate(tempo_query_frontend_queries_total{namespace="{namespace}"}[1m])) by (tenant)`,
+		"tenant_live_traces":          `sum(tempo_ingester_live_traces{namespace="{namespace}"}) by (tenant)`,
+
 		// Compactor metrics
 		"compactor_blocks_compacted":       `sum(rate(tempodb_compaction_blocks_total{namespace="{namespace}"}[1m]))`,
 		"compactor_bytes_written":          `sum(rate(tempodb_compaction_bytes_written_total{namespace="{namespace}"}[1m]))`,
 		"compactor_outstanding_blocks":     `sum(tempodb_compaction_outstanding_blocks{namespace="{namespace}"})`,
 		"retention_deleted_total":          `sum(tempodb_retention_deleted_total{namespace="{namespace}"})`,
 		"retention_marked_for_deletion":    `sum(tempodb_retention_marked_for_deletion_total{namespace="{namespace}"})`,
+		"compaction_duration_p50":          `histogram_quantile(0.50, sum(rate(tempodb_compaction_duration_seconds_bucket{namespace="{namespace}"}[5m])) by (le))`,
+		"compaction_duration_p99":          `histogram_quantile(0.99, sum(rate(tempodb_compaction_duration_seconds_bucket{namespace="{namespace}"}[5m])) by (le))`,
+		"compaction_errors_rate":           `sum(rate(tempodb_compaction_errors_total{namespace="{namespace}"}[1m]))`,
 
 		// Storage metrics
 		"query_frontend_bytes_inspected": `sum(rate(tempo_query_frontend_bytes_inspected_total{namespace="{namespace}"}[1m]))`,
 		"backend_read_latency_p99":       `histogram_quantile(0.99, sum(rate(tempodb_backend_request_duration_seconds_bucket{namespace="{namespace}"}[1m])) by (le))`,
 		"blocklist_poll_duration_p99":   `histogram_quantile(0.99, sum(rate(tempodb_blocklist_poll_duration_seconds_bucket{namespace="{namespace}"}[1m])) by (le))`,
+		"storage_footprint_bytes":       `sum(minio_bucket_usage_total_bytes{namespace="{namespace}"})`,
+		"storage_footprint_objects":     `sum(minio_bucket_usage_object_total{namespace="{namespace}"})`,
 		"blocklist_length":              `sum(tempodb_blocklist_length{namespace="{namespace}"}) by (tenant)`,
 
 		// Resource metrics
@@ -366,6 +826,44 @@ func GetMetricQuery(metricName string) string {
 		"memory_max_by_component":      `max by (component) (max_over_time(...container_memory_working_set_bytes...)[5m:])`,
 		"cpu_max_by_component":         `max by (component) (max_over_time(...container_cpu_usage_seconds_total...)[5m:])`,
 
+		// Backpressure metrics
+		"k6_active_vus":                     `sum(k6_vus{namespace="{namespace}"})`,
+		"k6_dropped_iterations_rate":        `sum(rate(k6_dropped_iterations_total{namespace="{namespace}"}[1m]))`,
+		"collector_exporter_queue_size":     `sum(otelcol_exporter_queue_size{namespace="{namespace}"}) by (exporter)`,
+		"collector_exporter_queue_capacity": `max(otelcol_exporter_queue_capacity{namespace="{namespace}"}) by (exporter)`,
+		"collector_kafka_consumer_lag":      `sum(otelcol_kafka_receiver_offset_lag{namespace="{namespace}"}) by (topic)`,
+
+		// Collector pipeline metrics
+		"collector_accepted_spans_rate":       `sum(rate(otelcol_receiver_accepted_spans_total{namespace="{namespace}"}[1m])) by (receiver)`,
+		"collector_refused_spans_rate":        `sum(rate(otelcol_receiver_refused_spans_total{namespace="{namespace}"}[1m])) by (receiver)`,
+		"collector_sent_spans_rate":           `sum(rate(otelcol_exporter_sent_spans_total{namespace="{namespace}"}[1m])) by (exporter)`,
+		"collector_send_failed_spans_rate":    `sum(rate(otelcol_exporter_send_failed_spans_total{namespace="{namespace}"}[1m])) by (exporter)`,
+		"collector_enqueue_failed_spans_rate": `sum(rate(otelcol_exporter_enqueue_failed_spans_total{namespace="{namespace}"}[1m])) by (exporter)`,
+		"collector_batch_send_size_avg":       `sum(rate(otelcol_processor_batch_batch_send_size_sum{namespace="{namespace}"}[1m])) / sum(rate(otelcol_processor_batch_batch_send_size_count{namespace="{namespace}"}[1m]))`,
+
+		// Metrics-generator metrics
+		"generator_spans_received_rate":              `sum(rate(tempo_metrics_generator_spans_received_total{namespace="{namespace}"}[1m]))`,
+		"generator_spans_discarded_rate":             `sum(rate(tempo_metrics_generator_spans_discarded_total{namespace="{namespace}"}[1m])) by (reason)`,
+		"generator_active_series":                    `sum(tempo_metrics_generator_registry_active_series{namespace="{namespace}"}) by (tenant)`,
+		"generator_remote_write_samples_rate":        `sum(rate(prometheus_remote_storage_samples_total{namespace="{namespace}", container=~"tempo.*"}[1m]))`,
+		"generator_remote_write_failed_samples_rate": `sum(rate(prometheus_remote_storage_samples_failed_total{namespace="{namespace}", container=~"tempo.*"}[1m]))`,
+		"generator_push_failures_rate":               `sum(rate(tempo_distributor_metrics_generator_pushes_failures_total{namespace="{namespace}"}[1m]))`,
+
+		// Node metrics
+		"node_disk_written_bytes_rate": `sum(label_replace(rate(node_disk_written_bytes_total{device!~"dm-.*"}[1m]), "node", "$1", "instance", "(.*)")) by (node) and on (node) max(kube_pod_info{namespace="{namespace}", pod=~"tempo-.*"}) by (node)`,
+		"node_disk_read_bytes_rate": `sum(label_replace(rate(node_disk_read_bytes_total{device!~"dm-.*"}[1m]), "node", "$1", "instance", "(.*)")) by (node) and on (node) max(kube_pod_info{namespace="{namespace}", pod=~"tempo-.*"}) by (node)`,
+		"node_disk_io_utilization": `max(label_replace(rate(node_disk_io_time_seconds_total{device!~"dm-.*"}[1m]), "node", "$1", "instance", "(.*)")) by (node) and on (node) max(kube_pod_info{namespace="{namespace}", pod=~"tempo-.*"}) by (node)`,
+		"node_network_transmit_bytes_rate": `sum(label_replace(rate(node_network_transmit_bytes_total{device!~"lo|veth.*|br-.*|ovs-.*|ovn-.*|genev_sys_.*|vxlan_sys_.*|tun.*"}[1m]), "node", "$1", "instance", "(.*)")) by (node) and on (node) max(kube_pod_info{namespace="{namespace}", pod=~"tempo-.*"}) by (node)`,
+		"node_network_receive_bytes_rate": `sum(label_replace(rate(node_network_receive_bytes_total{device!~"lo|veth.*|br-.*|ovs-.*|ovn-.*|genev_sys_.*|vxlan_sys_.*|tun.*"}[1m]), "node", "$1", "instance", "(.*)")) by (node) and on (node) max(kube_pod_info{namespace="{namespace}", pod=~"tempo-.*"}) by (node)`,
+
+		// Operator metrics
+		"operator_memory_usage": `sum(container_memory_working_set_bytes{namespace=~"{operator_namespaces}", container!=""}) by (namespace)`,
+		"operator_cpu_usage":    `sum(rate(container_cpu_usage_seconds_total{namespace=~"{operator_namespaces}", container!=""}[5m])) by (namespace)`,
+
+		// Autoscaling metrics
+		"hpa_current_replicas": `sum(kube_horizontalpodautoscaler_status_current_replicas{namespace="{namespace}"}) by (horizontalpodautoscaler)`,
+		"hpa_desired_replicas": `sum(kube_horizontalpodautoscaler_status_desired_replicas{namespace="{namespace}"}) by (horizontalpodautoscaler)`,
+
 		// Query performance metrics
 		"queries_per_second":              `sum(rate(tempo_query_frontend_queries_total{namespace="{namespace}"}[1m]))`,
 		"query_duration_p99":              `histogram_quantile(0.99, sum(rate(tempo_request_duration_seconds_bucket{namespace="{namespace}", route=~".*search.*|.*Search.*"}[5m])) by (le))`,
@@ -376,6 +874,14 @@ func GetMetricQuery(metricName string) string {
 		// Querier metrics
 		"querier_queue_length":      `sum(tempo_query_frontend_queue_length{namespace="{namespace}"}) by (pod)`,
 		"querier_jobs_in_progress":  `sum(rate(tempo_query_frontend_queries_total{namespace="{namespace}"}[1m])) by (pod)`,
+
+		// Go runtime metrics
+		"go_heap_inuse":     `sum(go_memstats_heap_inuse_bytes{namespace="{namespace}", container=~"tempo.*"}) by (pod, container)`,
+		"go_next_gc_bytes":  `sum(go_memstats_next_gc_bytes{namespace="{namespace}", container=~"tempo.*"}) by (pod, container)`,
+		"go_gc_cycles_rate": `sum(rate(go_gc_duration_seconds_count{namespace="{namespace}", container=~"tempo.*"}[1m])) by (pod, container)`,
+		"go_gc_pause_rate":  `sum(rate(go_gc_duration_seconds_sum{namespace="{namespace}", container=~"tempo.*"}[1m])) by (pod, container)`,
+		"go_gc_pause_max":   `max(go_gc_duration_seconds{namespace="{namespace}", container=~"tempo.*", quantile="1"}) by (pod, container)`,
+		"go_goroutines":     `sum(go_goroutines{namespace="{namespace}", container=~"tempo.*"}) by (pod, container)`,
 	}
 
 	if query, ok := queryMap[metricName]; ok {
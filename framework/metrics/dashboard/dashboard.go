@@ -1,16 +1,17 @@
 package dashboard
 
 import (
-	"encoding/csv"
+	"errors"
 	"fmt"
 	"html/template"
 	"math"
 	"os"
 	"path/filepath"
 	"sort"
-	"strconv"
 	"strings"
 	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/metrics"
 )
 
 // Generator creates HTML dashboards from CSV metrics
@@ -36,19 +37,30 @@ func NewGenerator(config DashboardConfig) (*Generator, error) {
 
 // GenerateFromCSV reads CSV and generates HTML dashboard
 func (g *Generator) GenerateFromCSV(csvPath, outputPath string) error {
+	data, err := g.BuildData(csvPath)
+	if err != nil {
+		return err
+	}
+	return g.render(data, outputPath)
+}
+
+// BuildData reads CSV and returns the resolved data of its dashboard
+func (g *Generator) BuildData(csvPath string) (*DashboardData, error) {
 	// Parse CSV
-	metrics, err := parseCSV(csvPath)
+	metrics, err := loadMetrics(csvPath, g.config.MaxPointsPerSeries)
 	if err != nil {
-		return fmt.Errorf("failed to parse CSV: %w", err)
+		return nil, fmt.Errorf("failed to parse CSV: %w", err)
 	}
 
 	if len(metrics) == 0 {
-		return fmt.Errorf("no metrics found in CSV file")
+		return nil, fmt.Errorf("no metrics found in CSV file")
 	}
 
-	// Build dashboard data
-	data := g.buildDashboardData(metrics, "")
+	return g.buildDashboardData(metrics, ""), nil
+}
 
+// render writes the HTML dashboard of data to outputPath
+func (g *Generator) render(data *DashboardData, outputPath string) error {
 	// Create output directory if needed
 	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
 		return fmt.Errorf("failed to create output directory: %w", err)
@@ -71,8 +83,26 @@ func (g *Generator) GenerateFromCSV(csvPath, outputPath string) error {
 
 // GenerateComparison generates a comparison dashboard from multiple CSV files
 func (g *Generator) GenerateComparison(csvPaths []string, outputPath string) error {
+	data, err := g.BuildComparisonData(csvPaths)
+	if err != nil {
+		return err
+	}
+	return g.render(data, outputPath)
+}
+
+// BuildComparisonData reads multiple CSV files and returns the resolved data of
+// their comparison dashboard
+func (g *Generator) BuildComparisonData(csvPaths []string) (*DashboardData, error) {
 	if len(csvPaths) < 2 {
-		return fmt.Errorf("comparison requires at least 2 CSV files")
+		return nil, fmt.Errorf("comparison requires at least 2 CSV files")
+	}
+
+	// Rates over different windows or steps are not comparable
+	if _, err := metrics.CheckResolutions(csvPaths); err != nil {
+		if !g.config.AllowMixedResolution || !errors.Is(err, metrics.ErrResolutionMismatch) {
+			return nil, err
+		}
+		fmt.Printf("⚠️  Warning: %v\n", err)
 	}
 
 	// Update config for comparison mode
@@ -88,10 +118,11 @@ func (g *Generator) GenerateComparison(csvPaths []string, outputPath string) err
 
 	// Parse all CSVs
 	var allMetrics []MetricSeries
+	loads := make(map[string]float64)
 	for i, csvPath := range csvPaths {
-		metrics, err := parseCSV(csvPath)
+		metrics, err := loadMetrics(csvPath, g.config.MaxPointsPerSeries)
 		if err != nil {
-			return fmt.Errorf("failed to parse CSV %s: %w", csvPath, err)
+			return nil, fmt.Errorf("failed to parse CSV %s: %w", csvPath, err)
 		}
 
 		runName := g.config.RunNames[i]
@@ -99,132 +130,85 @@ func (g *Generator) GenerateComparison(csvPaths []string, outputPath string) err
 			metrics[j].Labels["_run"] = runName
 		}
 		allMetrics = append(allMetrics, metrics...)
+
+		if g.config.NormalizeBy != NormalizeNone {
+			load, err := achievedLoad(g.config.NormalizeBy, csvPath, metrics)
+			if err != nil {
+				return nil, fmt.Errorf("failed to determine achieved load for run %s: %w", runName, err)
+			}
+			loads[runName] = load
+		}
 	}
 
 	if len(allMetrics) == 0 {
-		return fmt.Errorf("no metrics found in any CSV file")
+		return nil, fmt.Errorf("no metrics found in any CSV file")
 	}
 
 	// Build dashboard data
 	data := g.buildDashboardData(allMetrics, "")
-	data.ComparisonSummary = g.buildComparisonSummary(allMetrics)
-
-	// Create output directory if needed
-	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
-		return fmt.Errorf("failed to create output directory: %w", err)
-	}
-
-	// Create output file
-	file, err := os.Create(outputPath)
-	if err != nil {
-		return fmt.Errorf("failed to create output file: %w", err)
-	}
-	defer file.Close()
-
-	// Render template
-	if err := g.templates.ExecuteTemplate(file, "dashboard.html", data); err != nil {
-		return fmt.Errorf("failed to render template: %w", err)
+	data.ComparisonSummary = g.buildComparisonSummary(allMetrics, loads)
+	if deltas := g.buildDeltaSection(allMetrics, loads); deltas != nil {
+		data.Categories = append([]CategorySection{*deltas}, data.Categories...)
 	}
-
-	return nil
+	return data, nil
 }
 
-// parseCSV reads the metrics CSV file
-func parseCSV(csvPath string) ([]MetricSeries, error) {
-	file, err := os.Open(csvPath)
-	if err != nil {
-		return nil, err
-	}
-	defer file.Close()
-
-	reader := csv.NewReader(file)
-	records, err := reader.ReadAll()
+// loadMetrics streams the metrics CSV file with metrics.LoadFromCSVWithOptions,
+// downsampling series to maxPoints points (0 keeps all), and converts the results
+// to series. NaN and Inf values are dropped since they cannot be serialized to JSON.
+func loadMetrics(csvPath string, maxPoints int) ([]MetricSeries, error) {
+	results, stats, err := metrics.LoadFromCSVWithOptions(csvPath, metrics.CSVLoadOptions{MaxPointsPerSeries: maxPoints})
 	if err != nil {
 		return nil, err
 	}
-
-	if len(records) < 2 {
-		return nil, fmt.Errorf("CSV file is empty or has only headers")
+	stats.PrintSkipped(csvPath)
+	if stats.DownsampledSeries > 0 {
+		fmt.Printf("📉 Downsampled %d of %d series in %s to at most %d points (%d rows, %d points kept)\n",
+			stats.DownsampledSeries, stats.Series, filepath.Base(csvPath), maxPoints, stats.Rows, stats.Points)
 	}
 
-	// Skip header, group by query_id + labels
-	metricsMap := make(map[string]*MetricSeries)
-
-	for i, record := range records {
-		if i == 0 { // skip header
-			continue
-		}
-
-		if len(record) < 7 {
-			continue // skip malformed rows
-		}
-
-		// Parse: query_id, metric_name, category, description, timestamp, value, labels
-		ts, err := time.Parse("2006-01-02T15:04:05Z", record[4])
-		if err != nil {
-			continue // skip rows with invalid timestamps
-		}
-
-		val, err := strconv.ParseFloat(record[5], 64)
-		if err != nil {
-			continue // skip rows with invalid values
-		}
-
-		// Skip NaN and Inf values (can't be serialized to JSON)
-		if math.IsNaN(val) || math.IsInf(val, 0) {
-			continue
-		}
-
-		labels := parseLabels(record[6])
-		key := fmt.Sprintf("%s:%s", record[0], record[6]) // query_id:labels
-
-		if _, exists := metricsMap[key]; !exists {
-			metricsMap[key] = &MetricSeries{
-				QueryID:     record[0],
-				Name:        record[1],
-				Category:    record[2],
-				Description: record[3],
-				Labels:      labels,
-				DataPoints:  []DataPoint{},
+	series := make([]MetricSeries, 0, len(results))
+	for _, r := range results {
+		points := make([]DataPoint, 0, len(r.DataPoints))
+		for _, dp := range r.DataPoints {
+			if math.IsNaN(dp.Value) || math.IsInf(dp.Value, 0) {
+				continue
 			}
+			points = append(points, DataPoint{Timestamp: dp.Timestamp, Value: dp.Value})
 		}
 
-		metricsMap[key].DataPoints = append(metricsMap[key].DataPoints, DataPoint{
-			Timestamp: ts,
-			Value:     val,
+		series = append(series, MetricSeries{
+			QueryID:     r.QueryID,
+			Name:        r.MetricName,
+			Category:    r.Category,
+			Description: r.Description,
+			Labels:      r.Labels,
+			DataPoints:  points,
 		})
 	}
 
-	// Convert to slice and sort data points
-	result := make([]MetricSeries, 0, len(metricsMap))
-	for _, m := range metricsMap {
-		// Sort data points by timestamp
-		sort.Slice(m.DataPoints, func(i, j int) bool {
-			return m.DataPoints[i].Timestamp.Before(m.DataPoints[j].Timestamp)
-		})
-		result = append(result, *m)
-	}
-
-	return result, nil
+	return series, nil
 }
 
-// parseLabels parses label string into map
-func parseLabels(labelStr string) map[string]string {
-	labels := make(map[string]string)
-	if labelStr == "" {
-		return labels
-	}
+// ProfileNameFromPath derives the profile name from a metrics CSV written by the
+// runner (<profile>-<run-id>-metrics.csv). The run ID is read from the run_id
+// label of the metrics, since both profile names and run IDs may contain dashes.
+// Files without run ID labels (<profile>-metrics.csv) keep their base name.
+func ProfileNameFromPath(csvPath string) string {
+	name := strings.TrimSuffix(filepath.Base(csvPath), ".csv")
+	name = strings.TrimSuffix(name, "-metrics")
 
-	// Handle quoted labels (CSV can have quoted strings with commas)
-	parts := strings.Split(labelStr, ",")
-	for _, part := range parts {
-		kv := strings.SplitN(part, "=", 2)
-		if len(kv) == 2 {
-			labels[kv[0]] = kv[1]
+	// Only the labels are needed, so keep a single point per series
+	results, _, err := metrics.LoadFromCSVWithOptions(csvPath, metrics.CSVLoadOptions{MaxPointsPerSeries: 1})
+	if err != nil {
+		return name
+	}
+	for _, r := range results {
+		if runID := r.Labels[metrics.RunIDLabel]; runID != "" && strings.HasSuffix(name, "-"+runID) {
+			return strings.TrimSuffix(name, "-"+runID)
 		}
 	}
-
-	return labels
+	return name
 }
 
 // buildDashboardData organizes metrics into dashboard structure
@@ -244,12 +228,18 @@ func (g *Generator) buildDashboardData(metrics []MetricSeries, runName string) *
 	// Calculate resource statistics
 	resourceSummary := g.buildResourceSummary(metrics)
 
-	return &DashboardData{
+	data := &DashboardData{
 		Config:          g.config,
 		Summary:         summary,
 		Categories:      sections,
 		ResourceSummary: resourceSummary,
+		PhaseSummary:    buildPhaseSummary(metrics),
+		CollectionGaps:  buildCollectionGaps(metrics),
 	}
+	if !g.config.CompareMode {
+		data.EventSegments = buildEventSegments(metrics, g.config.Events)
+	}
+	return data
 }
 
 // buildSummary calculates summary statistics
@@ -291,7 +281,6 @@ func (g *Generator) buildCategorySections(categoryMetrics map[string][]MetricSer
 	order := GetCategoryOrder()
 
 	var sections []CategorySection
-	chartID := 0
 
 	for _, categoryName := range order {
 		catConfig, ok := configs[categoryName]
@@ -300,6 +289,9 @@ func (g *Generator) buildCategorySections(categoryMetrics map[string][]MetricSer
 		}
 
 		metrics, hasData := categoryMetrics[categoryName]
+		if catConfig.Optional && !hasData {
+			continue
+		}
 
 		section := CategorySection{
 			Name:        categoryName,
@@ -308,10 +300,10 @@ func (g *Generator) buildCategorySections(categoryMetrics map[string][]MetricSer
 			Charts:      []ChartConfig{},
 		}
 
+		anchors := make(map[string]int)
 		for _, chartDef := range catConfig.Charts {
-			chartID++
 			chart := ChartConfig{
-				ID:          fmt.Sprintf("%s-%d", categoryName, chartID),
+				ID:          chartAnchor(categoryName, chartDef.Title, anchors),
 				Title:       chartDef.Title,
 				Description: chartDef.Description,
 				Type:        chartDef.Type,
@@ -320,6 +312,20 @@ func (g *Generator) buildCategorySections(categoryMetrics map[string][]MetricSer
 				MetricInfo:  []MetricQueryInfo{},
 			}
 
+			// Derived charts pull series from any category
+			if len(chartDef.Series) > 0 {
+				for _, ref := range chartDef.Series {
+					chart.MetricInfo = append(chart.MetricInfo, MetricQueryInfo{
+						Name:  ref.MetricName,
+						Query: GetMetricQuery(ref.MetricName),
+					})
+					chart.Series = append(chart.Series, g.findSeries(categoryMetrics, ref, runName)...)
+				}
+				chart.Stats = chartStats(chart, g.config.CompareMode)
+				section.Charts = append(section.Charts, chart)
+				continue
+			}
+
 			// Add metric query info for each metric in this chart
 			for _, metricName := range chartDef.MetricNames {
 				query := GetMetricQuery(metricName)
@@ -354,6 +360,7 @@ func (g *Generator) buildCategorySections(categoryMetrics map[string][]MetricSer
 				}
 			}
 
+			chart.Stats = chartStats(chart, g.config.CompareMode)
 			section.Charts = append(section.Charts, chart)
 		}
 
@@ -363,18 +370,77 @@ func (g *Generator) buildCategorySections(categoryMetrics map[string][]MetricSer
 	return sections
 }
 
-// buildComparisonSummary builds comparison summary for multi-run dashboards
-func (g *Generator) buildComparisonSummary(metrics []MetricSeries) *ComparisonSummary {
-	if !g.config.CompareMode {
-		return nil
+// chartAnchor returns the stable anchor of a chart: the slugs of its category and
+// title, suffixed with a counter when a title repeats within the category
+func chartAnchor(category, title string, seen map[string]int) string {
+	anchor := slugify(category) + "-" + slugify(title)
+	seen[anchor]++
+	if n := seen[anchor]; n > 1 {
+		return fmt.Sprintf("%s-%d", anchor, n)
+	}
+	return anchor
+}
+
+// slugify lowercases s and replaces each run of non-alphanumeric characters with a hyphen
+func slugify(s string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			hyphen = false
+			continue
+		}
+		hyphen = true
 	}
+	return b.String()
+}
+
+// findSeries returns the series matching a SeriesRef across all categories
+func (g *Generator) findSeries(categoryMetrics map[string][]MetricSeries, ref SeriesRef, runName string) []SeriesData {
+	var result []SeriesData
+	for _, metrics := range categoryMetrics {
+		for _, m := range metrics {
+			if m.Name != ref.MetricName || !matchLabels(m.Labels, ref.Labels) {
+				continue
+			}
 
-	// Key metrics to compare
-	keyMetricNames := []string{
-		"memory_usage_total",
-		"cpu_usage_total",
-		"accepted_spans_rate",
-		"query_latency_p99",
+			series := SeriesData{
+				Name:    m.Name,
+				Labels:  m.Labels,
+				Data:    m.DataPoints,
+				RunName: runName,
+				Axis:    ref.Axis,
+			}
+			if g.config.CompareMode {
+				if rn, ok := m.Labels["_run"]; ok {
+					series.RunName = rn
+				}
+			}
+			result = append(result, series)
+		}
+	}
+	return result
+}
+
+// matchLabels returns true if labels contain all key/value pairs in selector
+func matchLabels(labels, selector map[string]string) bool {
+	for k, v := range selector {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// buildComparisonSummary builds comparison summary for multi-run dashboards.
+// If loads is non-empty, load-dependent metrics are also reported per unit of achieved load.
+func (g *Generator) buildComparisonSummary(metrics []MetricSeries, loads map[string]float64) *ComparisonSummary {
+	if !g.config.CompareMode {
+		return nil
 	}
 
 	summary := &ComparisonSummary{
@@ -382,6 +448,14 @@ func (g *Generator) buildComparisonSummary(metrics []MetricSeries) *ComparisonSu
 		RunNames: g.config.RunNames,
 	}
 
+	if len(loads) > 0 {
+		summary.LoadUnit = g.config.NormalizeBy.LoadUnit()
+		for _, runName := range g.config.RunNames {
+			summary.Loads = append(summary.Loads, ComparisonValue{RunName: runName, Value: loads[runName]})
+		}
+		setChanges(summary.Loads, g.config.RunNames[0])
+	}
+
 	// Group metrics by name and run
 	metricsByNameAndRun := make(map[string]map[string][]float64)
 	for _, m := range metrics {
@@ -400,7 +474,7 @@ func (g *Generator) buildComparisonSummary(metrics []MetricSeries) *ComparisonSu
 	}
 
 	// Calculate averages for key metrics
-	for _, metricName := range keyMetricNames {
+	for _, metricName := range comparisonMetrics {
 		runData, ok := metricsByNameAndRun[metricName]
 		if !ok {
 			continue
@@ -411,8 +485,7 @@ func (g *Generator) buildComparisonSummary(metrics []MetricSeries) *ComparisonSu
 			Unit: GetMetricUnit(metricName),
 		}
 
-		var firstAvg float64
-		for i, runName := range g.config.RunNames {
+		for _, runName := range g.config.RunNames {
 			values := runData[runName]
 			if len(values) == 0 {
 				continue
@@ -425,21 +498,20 @@ func (g *Generator) buildComparisonSummary(metrics []MetricSeries) *ComparisonSu
 			}
 			avg := sum / float64(len(values))
 
-			if i == 0 {
-				firstAvg = avg
-			}
-
-			change := 0.0
-			if firstAvg > 0 && i > 0 {
-				change = ((avg - firstAvg) / firstAvg) * 100
-			}
-
 			cm.Values = append(cm.Values, ComparisonValue{
 				RunName: runName,
 				Value:   avg,
-				Change:  change,
 			})
+
+			if load := loads[runName]; load > 0 && normalizedMetrics[metricName] {
+				cm.Normalized = append(cm.Normalized, ComparisonValue{
+					RunName: runName,
+					Value:   avg / load,
+				})
+			}
 		}
+		setChanges(cm.Values, g.config.RunNames[0])
+		setChanges(cm.Normalized, g.config.RunNames[0])
 
 		if len(cm.Values) > 0 {
 			summary.KeyMetrics = append(summary.KeyMetrics, cm)
@@ -449,6 +521,20 @@ func (g *Generator) buildComparisonSummary(metrics []MetricSeries) *ComparisonSu
 	return summary
 }
 
+// setChanges sets each value's percentage change relative to the baseline run's value.
+// Changes stay zero if the baseline run has no value.
+func setChanges(values []ComparisonValue, baselineRun string) {
+	if len(values) == 0 || values[0].RunName != baselineRun {
+		return
+	}
+	first := values[0].Value
+	for i := 1; i < len(values); i++ {
+		if first > 0 {
+			values[i].Change = ((values[i].Value - first) / first) * 100
+		}
+	}
+}
+
 // Generate is a convenience function that creates a generator and produces a dashboard
 func Generate(csvPath, outputPath string, config DashboardConfig) error {
 	gen, err := NewGenerator(config)
@@ -509,10 +595,11 @@ func (g *Generator) buildResourceSummary(metrics []MetricSeries) *ResourceSummar
 		if len(values) == 0 {
 			continue
 		}
-		stats := calculateStats(values)
-		stats.Component = component
-		stats.Unit = "bytes"
-		summary.Memory = append(summary.Memory, stats)
+		summary.Memory = append(summary.Memory, ComponentStats{
+			Component: component,
+			Stats:     calculateStats(values),
+			Unit:      "bytes",
+		})
 	}
 
 	// Calculate stats for each CPU component
@@ -520,10 +607,11 @@ func (g *Generator) buildResourceSummary(metrics []MetricSeries) *ResourceSummar
 		if len(values) == 0 {
 			continue
 		}
-		stats := calculateStats(values)
-		stats.Component = component
-		stats.Unit = "cores"
-		summary.CPU = append(summary.CPU, stats)
+		summary.CPU = append(summary.CPU, ComponentStats{
+			Component: component,
+			Stats:     calculateStats(values),
+			Unit:      "cores",
+		})
 	}
 
 	// Calculate "total" as sum of component stats (for capacity planning)
@@ -573,10 +661,148 @@ func (g *Generator) buildResourceSummary(metrics []MetricSeries) *ResourceSummar
 	return summary
 }
 
+// phaseSummaryMetrics are the key metrics summarized per phase
+var phaseSummaryMetrics = []string{
+	"accepted_spans_rate",
+	"query_latency_p99",
+	"cpu_usage_total",
+	"memory_usage_total",
+}
+
+// buildPhaseSummary calculates key metric statistics per phase from series labeled
+// with the phase. Phases are ordered by their first data point; returns nil if no
+// series carries a phase label.
+func buildPhaseSummary(series []MetricSeries) *PhaseSummary {
+	valuesByPhase := make(map[string]map[string][]float64)
+	ranges := make(map[string]*TimeRange)
+	for _, m := range series {
+		phase := m.Labels[metrics.PhaseLabel]
+		if phase == "" {
+			continue
+		}
+		if _, ok := valuesByPhase[phase]; !ok {
+			valuesByPhase[phase] = make(map[string][]float64)
+			ranges[phase] = &TimeRange{}
+		}
+
+		r := ranges[phase]
+		for _, dp := range m.DataPoints {
+			if r.Start.IsZero() || dp.Timestamp.Before(r.Start) {
+				r.Start = dp.Timestamp
+			}
+			if dp.Timestamp.After(r.End) {
+				r.End = dp.Timestamp
+			}
+			valuesByPhase[phase][m.Name] = append(valuesByPhase[phase][m.Name], dp.Value)
+		}
+	}
+	if len(valuesByPhase) == 0 {
+		return nil
+	}
+
+	summary := &PhaseSummary{MetricNames: phaseSummaryMetrics}
+	for phase, values := range valuesByPhase {
+		stats := PhaseStats{Name: phase, TimeRange: *ranges[phase]}
+		for _, name := range phaseSummaryMetrics {
+			ms := PhaseMetricStats{Name: name, Unit: GetMetricUnit(name)}
+			if v := values[name]; len(v) > 0 {
+				s := calculateStats(v)
+				ms.Avg, ms.Max, ms.HasData = s.Avg, s.Max, true
+			}
+			stats.Metrics = append(stats.Metrics, ms)
+		}
+		summary.Phases = append(summary.Phases, stats)
+	}
+	sort.Slice(summary.Phases, func(i, j int) bool {
+		return summary.Phases[i].TimeRange.Start.Before(summary.Phases[j].TimeRange.Start)
+	})
+
+	return summary
+}
+
+// chartStats summarizes each series of a chart in the unit of its axis
+func chartStats(chart ChartConfig, compareMode bool) []SeriesStats {
+	axisUnits := map[string]string{"": chart.Options.YAxisUnit}
+	for _, axis := range chart.Options.SecondaryAxes {
+		axisUnits[axis.ID] = axis.Unit
+	}
+
+	var stats []SeriesStats
+	for _, s := range chart.Series {
+		if len(s.Data) == 0 {
+			continue
+		}
+		values := make([]float64, len(s.Data))
+		for i, dp := range s.Data {
+			values[i] = dp.Value
+		}
+		label := seriesLabel(s)
+		if compareMode && s.RunName != "" {
+			label = fmt.Sprintf("%s (%s)", label, s.RunName)
+		}
+		stats = append(stats, SeriesStats{
+			Label: label,
+			Unit:  axisUnits[s.Axis],
+			Stats: calculateStats(values),
+		})
+	}
+	return stats
+}
+
+// seriesComponents are the Tempo components a pod name is shortened to
+var seriesComponents = map[string]bool{
+	"distributor": true, "ingester": true, "querier": true,
+	"compactor": true, "gateway": true, "query": true,
+}
+
+// seriesLabel returns the legend label of a series; it must match the labels
+// initChart gives the datasets in the dashboard template
+func seriesLabel(s SeriesData) string {
+	label := s.Name
+	if label == "" {
+		label = "Value"
+	}
+
+	pod, container := s.Labels["pod"], s.Labels["container"]
+	switch {
+	case pod != "" && container != "":
+		// e.g. "tempo-tempostack-ingester-0" -> "ingester-0"
+		parts := strings.Split(pod, "-")
+		short := lastParts(parts, 2)
+		for i, p := range parts {
+			if seriesComponents[p] {
+				short = strings.Join(parts[i:], "-")
+				break
+			}
+		}
+		label = short + "/" + container
+	case container != "":
+		label = container
+	case pod != "":
+		label = lastParts(strings.Split(pod, "-"), 2)
+	default:
+		for _, key := range []string{"component", "node", "cache_type", "status", "reason"} {
+			if v := s.Labels[key]; v != "" {
+				label = v
+				break
+			}
+		}
+	}
+	return label
+}
+
+// lastParts joins the last n parts with dashes
+func lastParts(parts []string, n int) string {
+	if len(parts) > n {
+		parts = parts[len(parts)-n:]
+	}
+	return strings.Join(parts, "-")
+}
+
 // calculateStats computes avg, max, min, P95, P99 from a slice of values
-func calculateStats(values []float64) ComponentStats {
+func calculateStats(values []float64) Stats {
 	if len(values) == 0 {
-		return ComponentStats{}
+		return Stats{}
 	}
 
 	// Sort for percentile calculations
@@ -590,7 +816,7 @@ func calculateStats(values []float64) ComponentStats {
 		sum += v
 	}
 
-	stats := ComponentStats{
+	stats := Stats{
 		Avg: sum / float64(len(sorted)),
 		Min: sorted[0],
 		Max: sorted[len(sorted)-1],
@@ -0,0 +1,624 @@
+package dashboard
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/metrics"
+)
+
+func writeCSV(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "small-abc-metrics.csv")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write CSV: %v", err)
+	}
+	return path
+}
+
+func TestLoadMetrics(t *testing.T) {
+	path := writeCSV(t, `query_id,metric_name,category,description,timestamp,value,labels
+1,memory,resources,Memory,2024-06-01T12:00:30Z,2,pod=a
+1,memory,resources,Memory,2024-06-01T12:00:00Z,1,pod=a
+1,memory,resources,Memory,2024-06-01T12:01:00Z,NaN,pod=a
+2,cpu,resources,CPU,2024-06-01T12:00:00Z,+Inf,
+`)
+
+	series, err := loadMetrics(path, 0)
+	if err != nil {
+		t.Fatalf("loadMetrics() error = %v", err)
+	}
+	if len(series) != 2 {
+		t.Fatalf("expected 2 series, got %d", len(series))
+	}
+
+	memory := series[0]
+	if memory.Name != "memory" || memory.Labels["pod"] != "a" {
+		t.Errorf("unexpected series %+v", memory)
+	}
+	if len(memory.DataPoints) != 2 || memory.DataPoints[0].Value != 1 || memory.DataPoints[1].Value != 2 {
+		t.Errorf("expected sorted finite data points, got %v", memory.DataPoints)
+	}
+
+	cpu := series[1]
+	if len(cpu.DataPoints) != 0 {
+		t.Errorf("expected Inf to be dropped, got %v", cpu.DataPoints)
+	}
+	if cpu.Labels == nil {
+		t.Error("expected non-nil labels for an unlabeled series")
+	}
+}
+
+func TestLoadMetrics_InvalidRow(t *testing.T) {
+	path := writeCSV(t, `query_id,metric_name,category,description,timestamp,value,labels
+1,memory,resources,Memory,not-a-time,1,
+1,memory,resources,Memory,2024-01-01T12:00:00Z,2,
+`)
+
+	// The invalid row is skipped, not the file
+	series, err := loadMetrics(path, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(series) != 1 || len(series[0].DataPoints) != 1 || series[0].DataPoints[0].Value != 2 {
+		t.Errorf("expected the valid row only, got %+v", series)
+	}
+}
+
+func TestProfileNameFromPath(t *testing.T) {
+	dir := t.TempDir()
+	header := "query_id,metric_name,category,description,timestamp,value,labels\n"
+
+	tests := []struct {
+		name     string
+		file     string
+		content  string
+		expected string
+	}{
+		{
+			name:     "run ID label",
+			file:     "1x-small-nightly-42-metrics.csv",
+			content:  header + "1,memory,resources,Memory,2024-06-01T12:00:00Z,1,\"pod=a,run_id=nightly-42\"\n",
+			expected: "1x-small",
+		},
+		{
+			name:     "no run ID label",
+			file:     "small-metrics.csv",
+			content:  header + "1,memory,resources,Memory,2024-06-01T12:00:00Z,1,pod=a\n",
+			expected: "small",
+		},
+		{
+			name:     "missing file",
+			file:     "medium-abc123-metrics.csv",
+			expected: "medium-abc123",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.file)
+			if tt.content != "" {
+				if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+					t.Fatalf("failed to write CSV: %v", err)
+				}
+			}
+			if got := ProfileNameFromPath(path); got != tt.expected {
+				t.Errorf("ProfileNameFromPath() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBuildPhaseSummary(t *testing.T) {
+	t0 := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	series := []MetricSeries{
+		{
+			Name:       "cpu_usage_total",
+			Labels:     map[string]string{"phase": "query"},
+			DataPoints: []DataPoint{{t0.Add(10 * time.Minute), 2}, {t0.Add(11 * time.Minute), 4}},
+		},
+		{
+			Name:       "cpu_usage_total",
+			Labels:     map[string]string{"phase": "ingest"},
+			DataPoints: []DataPoint{{t0, 1}, {t0.Add(time.Minute), 3}},
+		},
+		{Name: "cpu_usage_total", Labels: map[string]string{}, DataPoints: []DataPoint{{t0, 100}}},
+	}
+
+	summary := buildPhaseSummary(series)
+	if summary == nil || len(summary.Phases) != 2 {
+		t.Fatalf("expected 2 phases, got %+v", summary)
+	}
+	if summary.Phases[0].Name != "ingest" || summary.Phases[1].Name != "query" {
+		t.Errorf("expected phases in time order, got %s, %s", summary.Phases[0].Name, summary.Phases[1].Name)
+	}
+
+	for _, m := range summary.Phases[1].Metrics {
+		switch m.Name {
+		case "cpu_usage_total":
+			if !m.HasData || m.Avg != 3 || m.Max != 4 {
+				t.Errorf("unexpected query cpu stats %+v", m)
+			}
+		default:
+			if m.HasData {
+				t.Errorf("expected no data for %s", m.Name)
+			}
+		}
+	}
+}
+
+func TestBuildPhaseSummary_Unphased(t *testing.T) {
+	series := []MetricSeries{{Name: "cpu_usage_total", Labels: map[string]string{}}}
+	if summary := buildPhaseSummary(series); summary != nil {
+		t.Errorf("expected no phase summary, got %+v", summary)
+	}
+}
+
+func TestBuildEventSegments(t *testing.T) {
+	t0 := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	// Throughput of 100/s that drops to 20/s when the ingester is killed at 10m
+	// and is back at 95/s two minutes later
+	var points []DataPoint
+	for i := 0; i <= 20; i++ {
+		value := 100.0
+		switch {
+		case i == 10 || i == 11:
+			value = 20
+		case i >= 12:
+			value = 95
+		}
+		points = append(points, DataPoint{t0.Add(time.Duration(i) * time.Minute), value})
+	}
+	series := []MetricSeries{{Name: "accepted_spans_rate", Labels: map[string]string{}, DataPoints: points}}
+	events := []metrics.ClusterEvent{
+		{Time: t0.Add(10 * time.Minute), Type: "Normal", Reason: "Killing", Object: "Pod/tempo-tempostack-ingester-0"},
+		{Time: t0.Add(10*time.Minute + 20*time.Second), Type: "Warning", Reason: "OOMKilled", Object: "Pod/tempo-tempostack-ingester-1"},
+		{Time: t0.Add(3 * time.Minute), Type: "Warning", Reason: "Unhealthy", Object: "Pod/tempo-tempostack-querier-0"},
+	}
+
+	summary := buildEventSegments(series, events)
+	if summary == nil || len(summary.Incidents) != 1 {
+		t.Fatalf("expected 1 incident, got %+v", summary)
+	}
+	inc := summary.Incidents[0]
+	if len(inc.Events) != 2 {
+		t.Errorf("expected the kill and OOM to be one incident, got %d events", len(inc.Events))
+	}
+	if !inc.Recovered || inc.RecoveryTime != 2*time.Minute {
+		t.Errorf("expected recovery after 2m, got %v (recovered %v)", inc.RecoveryTime, inc.Recovered)
+	}
+	if len(inc.Segments) != 3 {
+		t.Fatalf("expected before, during and after segments, got %+v", inc.Segments)
+	}
+
+	want := map[string]float64{"before": 100, "during": (20 + 20 + 95) / 3.0, "after": 95}
+	for _, seg := range inc.Segments {
+		m := seg.Metrics[0]
+		if m.Name != "accepted_spans_rate" || !m.HasData || m.Avg != want[seg.Name] {
+			t.Errorf("unexpected %s throughput %+v, want avg %v", seg.Name, m, want[seg.Name])
+		}
+	}
+	if start := inc.Segments[0].TimeRange.Start; !start.Equal(t0.Add(5 * time.Minute)) {
+		t.Errorf("expected the before segment to start 5m before the incident, got %v", start)
+	}
+}
+
+func TestBuildEventSegments_NotRecovered(t *testing.T) {
+	t0 := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	series := []MetricSeries{{
+		Name:       "accepted_spans_rate",
+		DataPoints: []DataPoint{{t0, 100}, {t0.Add(time.Minute), 100}, {t0.Add(2 * time.Minute), 50}, {t0.Add(3 * time.Minute), 50}},
+	}}
+	events := []metrics.ClusterEvent{{Time: t0.Add(2 * time.Minute), Type: "Normal", Reason: "ScalingReplicaSet", Object: "Deployment/tempo-tempostack-distributor"}}
+
+	summary := buildEventSegments(series, events)
+	if summary == nil || len(summary.Incidents) != 1 {
+		t.Fatalf("expected 1 incident, got %+v", summary)
+	}
+	if inc := summary.Incidents[0]; inc.Recovered || inc.NoBaseline {
+		t.Errorf("expected the incident not to recover, got %+v", inc)
+	}
+}
+
+func TestBuildEventSegments_NoDisruption(t *testing.T) {
+	t0 := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	series := []MetricSeries{{Name: "accepted_spans_rate", DataPoints: []DataPoint{{t0, 100}}}}
+	events := []metrics.ClusterEvent{
+		{Time: t0, Type: "Warning", Reason: "FailedScheduling", Object: "Pod/k6-ingestion"},
+		{Time: t0.Add(time.Hour), Type: "Normal", Reason: "Killing", Object: "Pod/tempo-simplest-0"},
+	}
+	if summary := buildEventSegments(series, events); summary != nil {
+		t.Errorf("expected no segments, got %+v", summary)
+	}
+}
+
+func TestBuildCollectionGaps(t *testing.T) {
+	t0 := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	beats := func(run string, minutes ...int) MetricSeries {
+		m := MetricSeries{Name: metrics.HeartbeatMetric, Labels: map[string]string{}}
+		if run != "" {
+			m.Labels["_run"] = run
+		}
+		for _, minute := range minutes {
+			m.DataPoints = append(m.DataPoints, DataPoint{t0.Add(time.Duration(minute) * time.Minute), 1})
+		}
+		return m
+	}
+
+	// A single missing step is within 2x the step, 3 missing steps are a gap
+	gaps := buildCollectionGaps([]MetricSeries{beats("", 0, 1, 3, 4, 8, 9)})
+	if len(gaps) != 1 {
+		t.Fatalf("expected 1 gap, got %+v", gaps)
+	}
+	if !gaps[0].Start.Equal(t0.Add(4*time.Minute)) || !gaps[0].End.Equal(t0.Add(8*time.Minute)) || gaps[0].Duration != 4*time.Minute {
+		t.Errorf("unexpected gap %+v", gaps[0])
+	}
+
+	gaps = buildCollectionGaps([]MetricSeries{beats("a", 0, 1, 2), beats("b", 0, 1, 5)})
+	if len(gaps) != 1 || gaps[0].Run != "b" {
+		t.Errorf("expected a gap in run b, got %+v", gaps)
+	}
+}
+
+func TestBuildCollectionGaps_NoHeartbeat(t *testing.T) {
+	series := []MetricSeries{{Name: "cpu_usage_total", DataPoints: []DataPoint{{time.Now(), 1}, {time.Now().Add(time.Hour), 1}}}}
+	if gaps := buildCollectionGaps(series); gaps != nil {
+		t.Errorf("expected no gaps without a heartbeat, got %+v", gaps)
+	}
+}
+
+func TestChartAnchor(t *testing.T) {
+	seen := make(map[string]int)
+	tests := []struct {
+		category string
+		title    string
+		expected string
+	}{
+		{"ingestion", "Spans Received Rate", "ingestion-spans-received-rate"},
+		{"query_performance", "Query Latency (P99)", "query-performance-query-latency-p99"},
+		{"ingestion", "Spans Received Rate", "ingestion-spans-received-rate-2"},
+		{"resources", "  CPU / Memory  ", "resources-cpu-memory"},
+	}
+	for _, tt := range tests {
+		if got := chartAnchor(tt.category, tt.title, seen); got != tt.expected {
+			t.Errorf("chartAnchor(%q, %q) = %q, want %q", tt.category, tt.title, got, tt.expected)
+		}
+	}
+}
+
+func TestChartStats(t *testing.T) {
+	data := func(values ...float64) []DataPoint {
+		points := make([]DataPoint, len(values))
+		for i, v := range values {
+			points[i] = DataPoint{Value: v}
+		}
+		return points
+	}
+	chart := ChartConfig{
+		Options: ChartOptions{
+			YAxisUnit:     "bytes",
+			SecondaryAxes: []AxisOptions{{ID: "y2", Unit: "seconds"}},
+		},
+		Series: []SeriesData{
+			{Name: "memory", Labels: map[string]string{"pod": "tempo-tempostack-ingester-0", "container": "tempo"}, Data: data(1, 2, 3, 4, 5), RunName: "small"},
+			{Name: "latency", Labels: map[string]string{"component": "querier"}, Data: data(0.5), Axis: "y2", RunName: "small"},
+			{Name: "empty"},
+		},
+	}
+
+	stats := chartStats(chart, false)
+	if len(stats) != 2 {
+		t.Fatalf("expected stats for the 2 series with data, got %+v", stats)
+	}
+	if s := stats[0]; s.Label != "ingester-0/tempo" || s.Unit != "bytes" || s.Min != 1 || s.Avg != 3 || s.Max != 5 {
+		t.Errorf("unexpected stats %+v", s)
+	}
+	if s := stats[1]; s.Label != "querier" || s.Unit != "seconds" || s.P99 != 0.5 {
+		t.Errorf("unexpected stats on the secondary axis %+v", s)
+	}
+	if got := chartStats(chart, true)[0].Label; got != "ingester-0/tempo (small)" {
+		t.Errorf("expected the run name in comparison mode, got %q", got)
+	}
+}
+
+func TestSeriesLabel(t *testing.T) {
+	tests := []struct {
+		labels map[string]string
+		want   string
+	}{
+		{map[string]string{"pod": "tempo-simplest-0", "container": "tempo"}, "simplest-0/tempo"},
+		{map[string]string{"container": "tempo"}, "tempo"},
+		{map[string]string{"pod": "minio"}, "minio"},
+		{map[string]string{"status": "500"}, "500"},
+		{map[string]string{"node": "worker-1"}, "worker-1"},
+		{nil, "metric"},
+	}
+	for _, tt := range tests {
+		if got := seriesLabel(SeriesData{Name: "metric", Labels: tt.labels}); got != tt.want {
+			t.Errorf("seriesLabel(%v) = %q, want %q", tt.labels, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateComparison_MixedResolution(t *testing.T) {
+	content := `query_id,metric_name,category,description,timestamp,value,labels
+21,memory_usage_total,resources,Memory,2024-06-01T12:00:00Z,1,
+`
+	paths := []string{writeCSV(t, content), writeCSV(t, content)}
+	for i, step := range []time.Duration{time.Minute, 15 * time.Second} {
+		meta := &metrics.ExportMetadata{Source: metrics.SourcePrometheus, Resolution: metrics.Resolution{Step: step}}
+		if err := metrics.WriteExportMetadata(meta, paths[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	output := filepath.Join(t.TempDir(), "comparison.html")
+
+	err := GenerateComparison(paths, output, DashboardConfig{})
+	if !errors.Is(err, metrics.ErrResolutionMismatch) {
+		t.Fatalf("expected ErrResolutionMismatch, got %v", err)
+	}
+
+	if err := GenerateComparison(paths, output, DashboardConfig{AllowMixedResolution: true}); err != nil {
+		t.Fatalf("GenerateComparison() with AllowMixedResolution error = %v", err)
+	}
+}
+
+func TestGenerate_Attainment(t *testing.T) {
+	path := writeCSV(t, `query_id,metric_name,category,description,timestamp,value,labels
+21,memory_usage_total,resources,Memory,2024-06-01T12:00:00Z,1,
+`)
+	output := filepath.Join(t.TempDir(), "dashboard.html")
+	config := DashboardConfig{
+		Attainment: &metrics.LoadAttainment{
+			Ingestion: &metrics.RateAttainment{Target: 10, Achieved: 7.5, Observed: 7.4, AchievedPercent: 75, UnderDelivered: true},
+			Queries:   &metrics.RateAttainment{Target: 20, Achieved: 19.9, AchievedPercent: 99.5},
+		},
+	}
+	if err := Generate(path, output, config); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(data)
+	for _, want := range []string{"75.0%", "7.50 of 10.00 MB/s", "7.40 MB/s received", "99.5%", "19.9 of 20 queries/s", `<table class="chart-stats">`} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected the dashboard to contain %q", want)
+		}
+	}
+}
+
+func TestGenerate_LogErrors(t *testing.T) {
+	path := writeCSV(t, `query_id,metric_name,category,description,timestamp,value,labels
+21,memory_usage_total,resources,Memory,2024-06-01T12:00:00Z,1,
+`)
+	output := filepath.Join(t.TempDir(), "dashboard.html")
+	config := DashboardConfig{
+		LogErrors: &metrics.LogErrorSummary{
+			Total:    2,
+			Patterns: []string{"panic", "deadline-exceeded"},
+			Logs: []metrics.ComponentLogErrors{{
+				Component: "tempo-querier",
+				Pod:       "tempo-querier-0",
+				File:      "tempo-querier-tempo-querier-0.log",
+				Total:     2,
+				Found: []metrics.LogErrorMatch{
+					{Pattern: "deadline-exceeded", Count: 2, Samples: []string{`err="context deadline exceeded"`}},
+				},
+			}},
+		},
+	}
+	if err := Generate(path, output, config); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(data)
+	for _, want := range []string{`id="log-errors"`, "tempo-querier-tempo-querier-0.log", "context deadline exceeded"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected the dashboard to contain %q", want)
+		}
+	}
+}
+
+func TestGenerate_Events(t *testing.T) {
+	path := writeCSV(t, `query_id,metric_name,category,description,timestamp,value,labels
+21,memory_usage_total,resources,Memory,2024-06-01T12:00:00Z,1,
+21,memory_usage_total,resources,Memory,2024-06-01T12:01:00Z,2,
+`)
+	output := filepath.Join(t.TempDir(), "dashboard.html")
+	config := DashboardConfig{
+		Events: []metrics.ClusterEvent{{
+			Time:    time.Date(2024, 6, 1, 12, 0, 30, 0, time.UTC),
+			Type:    "Warning",
+			Reason:  "OOMKilled",
+			Object:  "Pod/tempo-simplest-0",
+			Message: "container tempo terminated with exit code 137 (restart 1)",
+			Count:   1,
+		}},
+	}
+	if err := Generate(path, output, config); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(data)
+	for _, want := range []string{`id="cluster-events"`, "12:00:30 UTC", "OOMKilled", "Pod/tempo-simplest-0", `"reason":"OOMKilled"`, `id="event-segments"`} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected the dashboard to contain %q", want)
+		}
+	}
+}
+
+func TestGenerate_Prerequisites(t *testing.T) {
+	path := writeCSV(t, `query_id,metric_name,category,description,timestamp,value,labels
+21,memory_usage_total,resources,Memory,2024-06-01T12:00:00Z,1,
+`)
+	output := filepath.Join(t.TempDir(), "dashboard.html")
+	config := DashboardConfig{
+		Prerequisites: &metrics.PrerequisitesReport{
+			CheckedAt: time.Date(2024, 6, 1, 11, 55, 0, 0, time.UTC),
+			AllMet:    true,
+			Operators: []metrics.OperatorReport{{
+				Name:      "Tempo Operator",
+				Installed: true,
+				Namespace: "openshift-tempo-operator",
+				Version:   "0.15.3",
+				Channel:   "stable",
+				CSV:       "tempo-operator.v0.15.3",
+				CRDs: []metrics.CRDReport{{
+					Name:        "tempostacks.tempo.grafana.com",
+					Found:       true,
+					Established: true,
+					Versions:    []string{"v1alpha1"},
+				}},
+			}},
+			Monitoring: metrics.MonitoringReport{UserWorkloadEnabled: true, UserWorkloadPrometheusReady: 2},
+		},
+	}
+	if err := Generate(path, output, config); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(data)
+	for _, want := range []string{`id="environment"`, `href="#environment"`, "tempo-operator.v0.15.3", "openshift-tempo-operator", "tempostacks.tempo.grafana.com (v1alpha1)", "enabled, 2 Prometheus pods ready"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected the dashboard to contain %q", want)
+		}
+	}
+}
+
+func TestGenerate_MetricsGenerator(t *testing.T) {
+	tests := []struct {
+		name        string
+		csv         string
+		wantSection bool
+	}{
+		{
+			name: "without generator",
+			csv: `query_id,metric_name,category,description,timestamp,value,labels
+1,accepted_spans_rate,ingestion,Accepted,2024-06-01T12:00:00Z,100,
+`,
+		},
+		{
+			name: "with generator",
+			csv: `query_id,metric_name,category,description,timestamp,value,labels
+1,accepted_spans_rate,ingestion,Accepted,2024-06-01T12:00:00Z,100,
+64,generator_spans_received_rate,metrics_generator,Received,2024-06-01T12:00:00Z,98,
+`,
+			wantSection: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeCSV(t, tt.csv)
+			output := filepath.Join(t.TempDir(), "dashboard.html")
+			if err := Generate(path, output, DashboardConfig{}); err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+
+			data, err := os.ReadFile(output)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.Contains(string(data), `id="category-metrics_generator"`); got != tt.wantSection {
+				t.Errorf("metrics-generator section present = %v, want %v", got, tt.wantSection)
+			}
+		})
+	}
+}
+
+func TestGenerate_CollectionGaps(t *testing.T) {
+	path := writeCSV(t, `query_id,metric_name,category,description,timestamp,value,labels
+70,collection_heartbeat,collection,Heartbeat,2024-06-01T12:00:00Z,1,
+70,collection_heartbeat,collection,Heartbeat,2024-06-01T12:01:00Z,1,
+70,collection_heartbeat,collection,Heartbeat,2024-06-01T12:10:00Z,1,
+21,memory_usage_total,resources,Memory,2024-06-01T12:00:00Z,1,
+21,memory_usage_total,resources,Memory,2024-06-01T12:10:00Z,2,
+`)
+	output := filepath.Join(t.TempDir(), "dashboard.html")
+	if err := Generate(path, output, DashboardConfig{}); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(data)
+	for _, want := range []string{`id="collection-gaps"`, "12:01:00 UTC", "12:10:00 UTC", "9m"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected the dashboard to contain %q", want)
+		}
+	}
+}
+
+func TestGenerate_SynchronizedZoom(t *testing.T) {
+	path := writeCSV(t, `query_id,metric_name,category,description,timestamp,value,labels
+21,memory_usage_total,resources,Memory,2024-06-01T12:00:00Z,1,
+21,memory_usage_total,resources,Memory,2024-06-01T12:10:00Z,2,
+`)
+	output := filepath.Join(t.TempDir(), "dashboard.html")
+	if err := Generate(path, output, DashboardConfig{}); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(data)
+	for _, want := range []string{`id="zoom-bar"`, `id="zoom-reset"`, "plugins: [gapShading, eventMarkers, zoomSelection]", "enableZoom(charts[chartId])"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected the dashboard to contain %q", want)
+		}
+	}
+}
+
+func TestGenerate_TenantIsolation(t *testing.T) {
+	path := writeCSV(t, `query_id,metric_name,category,description,timestamp,value,labels
+21,memory_usage_total,resources,Memory,2024-06-01T12:00:00Z,1,
+`)
+	output := filepath.Join(t.TempDir(), "dashboard.html")
+	config := DashboardConfig{
+		Isolation: &metrics.TenantIsolation{
+			Victim:    "tenant-1",
+			Aggressor: "tenant-2",
+			Loads: []metrics.TenantLoad{
+				{Tenant: "tenant-1", Phase: metrics.IsolationBaseline, IngestionP99: 0.05, QueryP99: 0.8, IngestionMBPerSecond: 2, QueriesPerSecond: 10},
+				{Tenant: "tenant-1", Phase: metrics.IsolationBurst, IngestionP99: 0.1, QueryP99: 1, IngestionMBPerSecond: 2, QueriesPerSecond: 10, QueryFailures: 4},
+				{Tenant: "tenant-2", Phase: metrics.IsolationBurst, IngestionP99: 0.3, IngestionMBPerSecond: 20},
+			},
+		},
+	}
+	if err := Generate(path, output, config); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(data)
+	for _, want := range []string{`id="tenant-isolation"`, `href="#tenant-isolation"`, "tenant-2 (aggressor)", "100.00 ms (&#43;100.0%)", "1.000 s (&#43;25.0%)", "20.00 MB/s"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected the dashboard to contain %q", want)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Format is the output format of a dashboard
+type Format string
+
+const (
+	// FormatHTML is the interactive HTML dashboard
+	FormatHTML Format = "html"
+	// FormatDataJSON is the resolved DashboardData as JSON, for notebooks and
+	// other tools that consume processed results
+	FormatDataJSON Format = "data-json"
+)
+
+// ParseFormat validates a dashboard output format string; empty is FormatHTML
+func ParseFormat(s string) (Format, error) {
+	switch format := Format(s); format {
+	case "", FormatHTML:
+		return FormatHTML, nil
+	case FormatDataJSON:
+		return format, nil
+	default:
+		return "", fmt.Errorf("invalid format %q (must be html or data-json)", s)
+	}
+}
+
+// dataJSON is DashboardData with the series statistics of each chart, which the
+// HTML dashboard renders in tables instead of passing them to its script
+type dataJSON struct {
+	*DashboardData
+	Categories []categoryJSON
+}
+
+type categoryJSON struct {
+	CategorySection
+	Charts []chartJSON
+}
+
+type chartJSON struct {
+	ChartConfig
+	Stats []SeriesStats
+}
+
+// WriteDataJSON writes the resolved data of a dashboard as indented JSON: its
+// configuration, summaries, and the categories with the series and statistics of
+// every chart
+func WriteDataJSON(data *DashboardData, outputPath string) error {
+	out := dataJSON{DashboardData: data, Categories: make([]categoryJSON, 0, len(data.Categories))}
+	for _, section := range data.Categories {
+		category := categoryJSON{CategorySection: section, Charts: make([]chartJSON, 0, len(section.Charts))}
+		for _, chart := range section.Charts {
+			category.Charts = append(category.Charts, chartJSON{ChartConfig: chart, Stats: chart.Stats})
+		}
+		out.Categories = append(out.Categories, category)
+	}
+
+	encoded, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode dashboard data: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := os.WriteFile(outputPath, append(encoded, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write dashboard data: %w", err)
+	}
+	return nil
+}
+
+// GenerateDataJSON writes the resolved data of the dashboard of a CSV file as JSON
+func GenerateDataJSON(csvPath, outputPath string, config DashboardConfig) error {
+	gen, err := NewGenerator(config)
+	if err != nil {
+		return err
+	}
+	data, err := gen.BuildData(csvPath)
+	if err != nil {
+		return err
+	}
+	return WriteDataJSON(data, outputPath)
+}
+
+// GenerateComparisonDataJSON writes the resolved data of a comparison dashboard as JSON
+func GenerateComparisonDataJSON(csvPaths []string, outputPath string, config DashboardConfig) error {
+	gen, err := NewGenerator(config)
+	if err != nil {
+		return err
+	}
+	data, err := gen.BuildComparisonData(csvPaths)
+	if err != nil {
+		return err
+	}
+	return WriteDataJSON(data, outputPath)
+}
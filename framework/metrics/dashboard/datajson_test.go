@@ -0,0 +1,70 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseFormat(t *testing.T) {
+	for input, want := range map[string]Format{"": FormatHTML, "html": FormatHTML, "data-json": FormatDataJSON} {
+		got, err := ParseFormat(input)
+		if err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %q, %v, want %q", input, got, err, want)
+		}
+	}
+	if _, err := ParseFormat("pdf"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
+
+func TestGenerateDataJSON(t *testing.T) {
+	path := writeCSV(t, `query_id,metric_name,category,description,timestamp,value,labels
+1,accepted_spans_rate,ingestion,Accepted,2024-06-01T12:00:00Z,100,
+1,accepted_spans_rate,ingestion,Accepted,2024-06-01T12:00:30Z,300,
+`)
+	output := filepath.Join(t.TempDir(), "data", "small-abc-dashboard-data.json")
+	if err := GenerateDataJSON(path, output, DashboardConfig{ProfileName: "small"}); err != nil {
+		t.Fatalf("GenerateDataJSON() error = %v", err)
+	}
+
+	raw, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var data struct {
+		Config     DashboardConfig
+		Summary    TestSummary
+		Categories []struct {
+			Name   string
+			Charts []struct {
+				Title  string
+				Series []SeriesData
+				Stats  []SeriesStats
+			}
+		}
+	}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		t.Fatalf("failed to decode dashboard data: %v", err)
+	}
+	if data.Config.ProfileName != "small" || data.Summary.TotalDataPoints != 2 {
+		t.Errorf("unexpected config and summary: %+v %+v", data.Config, data.Summary)
+	}
+
+	for _, category := range data.Categories {
+		if category.Name != "ingestion" {
+			continue
+		}
+		for _, chart := range category.Charts {
+			if len(chart.Series) == 0 || len(chart.Series[0].Data) != 2 {
+				continue
+			}
+			if len(chart.Stats) != 1 || chart.Stats[0].Max != 300 || chart.Stats[0].Avg != 200 {
+				t.Errorf("expected the series statistics of chart %q, got %+v", chart.Title, chart.Stats)
+			}
+			return
+		}
+	}
+	t.Errorf("expected an ingestion chart with the series, got %s", raw)
+}
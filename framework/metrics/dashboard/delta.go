@@ -0,0 +1,138 @@
+package dashboard
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// comparisonMetrics are the key metrics of comparison dashboards, summarized per run
+// and charted as changes from the baseline run
+var comparisonMetrics = []string{
+	"memory_usage_total",
+	"cpu_usage_total",
+	"accepted_spans_rate",
+	"query_latency_p99",
+}
+
+// buildDeltaSection charts the change of each key comparison metric from the baseline
+// (first) run, as (candidate-baseline)/baseline. Series are matched by their labels and
+// aligned by their position from the start of each run, as compared runs share the
+// query step. With load normalization, load-dependent metrics are compared per unit of
+// achieved load. Returns nil if no metric has data in the baseline and another run.
+func (g *Generator) buildDeltaSection(metrics []MetricSeries, loads map[string]float64) *CategorySection {
+	if !g.config.CompareMode || len(g.config.RunNames) < 2 {
+		return nil
+	}
+	baselineRun := g.config.RunNames[0]
+
+	section := &CategorySection{
+		Name:        "deltas",
+		Title:       fmt.Sprintf("Change vs %s", baselineRun),
+		Description: fmt.Sprintf("Relative change of key metrics from the baseline run %s over the test, aligned by time since the start of each run", baselineRun),
+	}
+	anchors := make(map[string]int)
+	for _, name := range comparisonMetrics {
+		normalized := len(loads) > 0 && normalizedMetrics[name]
+		title := name
+		if normalized {
+			title = fmt.Sprintf("%s per %s", name, g.config.NormalizeBy.LoadUnit())
+		}
+		chart := ChartConfig{
+			ID:          chartAnchor(section.Name, title, anchors),
+			Title:       title,
+			Description: fmt.Sprintf("(candidate - baseline) / baseline of %s", name),
+			Type:        ChartTypeLine,
+			Options: ChartOptions{
+				YAxisLabel: "Change vs baseline",
+				YAxisUnit:  "percent",
+				ShowLegend: true,
+				ShowGrid:   true,
+			},
+			MetricInfo: []MetricQueryInfo{{Name: name, Query: GetMetricQuery(name)}},
+		}
+
+		// load returns the divisor of a run's values, 1 unless normalized
+		load := func(runName string) float64 {
+			if l := loads[runName]; normalized && l > 0 {
+				return l
+			}
+			return 1
+		}
+
+		baselines := make(map[string][]DataPoint)
+		for _, m := range metrics {
+			if m.Name == name && m.Labels["_run"] == baselineRun {
+				baselines[seriesKey(m.Labels)] = m.DataPoints
+			}
+		}
+		for _, runName := range g.config.RunNames[1:] {
+			for _, m := range metrics {
+				if m.Name != name || m.Labels["_run"] != runName {
+					continue
+				}
+				baseline, ok := baselines[seriesKey(m.Labels)]
+				if !ok {
+					continue
+				}
+				data := deltaPoints(baseline, m.DataPoints, load(baselineRun), load(runName))
+				if len(data) == 0 {
+					continue
+				}
+				chart.Series = append(chart.Series, SeriesData{
+					Name:    m.Name,
+					Labels:  m.Labels,
+					Data:    data,
+					RunName: runName,
+				})
+			}
+		}
+		if len(chart.Series) == 0 {
+			continue
+		}
+		chart.Stats = chartStats(chart, true)
+		section.Charts = append(section.Charts, chart)
+	}
+
+	if len(section.Charts) == 0 {
+		return nil
+	}
+	return section
+}
+
+// seriesKey identifies a series across runs by its labels, without the run name and
+// the namespace, which differ between runs
+func seriesKey(labels map[string]string) string {
+	var parts []string
+	for k, v := range labels {
+		if k == "_run" || k == "namespace" {
+			continue
+		}
+		parts = append(parts, k+"="+v)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
+
+// deltaPoints returns the relative change of candidate from baseline at each position,
+// after dividing the values of each run by its load, at the timestamps of candidate.
+// Positions where the baseline is zero have no change and are skipped.
+func deltaPoints(baseline, candidate []DataPoint, baselineLoad, candidateLoad float64) []DataPoint {
+	n := len(candidate)
+	if len(baseline) < n {
+		n = len(baseline)
+	}
+
+	var points []DataPoint
+	for i := 0; i < n; i++ {
+		base := baseline[i].Value / baselineLoad
+		if base == 0 {
+			continue
+		}
+		points = append(points, DataPoint{
+			Timestamp: candidate[i].Timestamp,
+			Value:     (candidate[i].Value/candidateLoad - base) / base,
+		})
+	}
+	return points
+}
@@ -0,0 +1,84 @@
+package dashboard
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestDeltaPoints(t *testing.T) {
+	start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	baseline := []DataPoint{{Value: 100}, {Value: 0}, {Value: 200}, {Value: 50}}
+	candidate := []DataPoint{
+		{Timestamp: start, Value: 150},
+		{Timestamp: start.Add(time.Minute), Value: 10},
+		{Timestamp: start.Add(2 * time.Minute), Value: 100},
+	}
+
+	points := deltaPoints(baseline, candidate, 1, 1)
+	if len(points) != 2 {
+		t.Fatalf("expected 2 points (zero baseline skipped, extra baseline point ignored), got %+v", points)
+	}
+	if points[0].Value != 0.5 || !points[0].Timestamp.Equal(start) {
+		t.Errorf("expected +50%% at the candidate timestamp, got %+v", points[0])
+	}
+	if points[1].Value != -0.5 {
+		t.Errorf("expected -50%%, got %+v", points[1])
+	}
+
+	// Twice the load for twice the value is no change
+	points = deltaPoints(baseline[:1], []DataPoint{{Value: 200}}, 1, 2)
+	if len(points) != 1 || points[0].Value != 0 {
+		t.Errorf("expected no normalized change, got %+v", points)
+	}
+}
+
+func TestBuildDeltaSection(t *testing.T) {
+	g := &Generator{config: DashboardConfig{
+		CompareMode: true,
+		RunNames:    []string{"small", "medium"},
+		NormalizeBy: NormalizeByMBps,
+	}}
+	metrics := []MetricSeries{
+		{Name: "memory_usage_total", Labels: map[string]string{"_run": "small", "namespace": "tempo-perf-small"}, DataPoints: []DataPoint{{Value: 100}, {Value: 200}}},
+		{Name: "memory_usage_total", Labels: map[string]string{"_run": "medium", "namespace": "tempo-perf-medium"}, DataPoints: []DataPoint{{Value: 400}, {Value: 1000}}},
+		{Name: "accepted_spans_rate", Labels: map[string]string{"_run": "small"}, DataPoints: []DataPoint{{Value: 1000}}},
+		{Name: "accepted_spans_rate", Labels: map[string]string{"_run": "medium"}, DataPoints: []DataPoint{{Value: 4000}}},
+		// Only in the candidate run
+		{Name: "query_latency_p99", Labels: map[string]string{"_run": "medium"}, DataPoints: []DataPoint{{Value: 1}}},
+	}
+	loads := map[string]float64{"small": 1, "medium": 4}
+
+	section := g.buildDeltaSection(metrics, loads)
+	if section == nil {
+		t.Fatal("expected a delta section")
+	}
+	if len(section.Charts) != 2 {
+		t.Fatalf("expected memory and span charts, got %+v", section.Charts)
+	}
+
+	memory := section.Charts[0]
+	if memory.Title != "memory_usage_total per MB/s" || memory.ID != "deltas-memory-usage-total-per-mb-s" {
+		t.Errorf("unexpected normalized chart %q (%s)", memory.Title, memory.ID)
+	}
+	if len(memory.Series) != 1 || memory.Series[0].RunName != "medium" {
+		t.Fatalf("expected one series of the candidate run, got %+v", memory.Series)
+	}
+	got := memory.Series[0].Data
+	if len(got) != 2 || got[0].Value != 0 || math.Abs(got[1].Value-0.25) > 1e-9 {
+		t.Errorf("expected changes per MB/s of 0 and +25%%, got %+v", got)
+	}
+
+	spans := section.Charts[1]
+	if spans.Title != "accepted_spans_rate" || spans.Series[0].Data[0].Value != 3 {
+		t.Errorf("expected an unnormalized +300%% span rate change, got %q %+v", spans.Title, spans.Series)
+	}
+	if len(spans.Stats) != 1 || spans.Stats[0].Unit != "percent" {
+		t.Errorf("expected percent statistics, got %+v", spans.Stats)
+	}
+
+	g.config.CompareMode = false
+	if g.buildDeltaSection(metrics, loads) != nil {
+		t.Error("expected no delta section outside comparison mode")
+	}
+}
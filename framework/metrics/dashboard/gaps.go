@@ -0,0 +1,58 @@
+package dashboard
+
+import (
+	"sort"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/metrics"
+)
+
+// gapFactor is how many query steps may pass without a heartbeat point before the
+// stretch counts as a collection gap
+const gapFactor = 2
+
+// buildCollectionGaps finds the stretches between collection heartbeat points longer
+// than gapFactor query steps. The step is the smallest spacing of the points. In
+// comparison mode each run is checked separately. Returns nil if there are no gaps,
+// or no heartbeat was collected.
+func buildCollectionGaps(series []MetricSeries) []CollectionGap {
+	var runs []string
+	beats := make(map[string][]time.Time)
+	for _, m := range series {
+		if m.Name != metrics.HeartbeatMetric {
+			continue
+		}
+		run := m.Labels["_run"]
+		if _, ok := beats[run]; !ok {
+			runs = append(runs, run)
+		}
+		for _, dp := range m.DataPoints {
+			if dp.Value > 0 {
+				beats[run] = append(beats[run], dp.Timestamp)
+			}
+		}
+	}
+
+	var gaps []CollectionGap
+	for _, run := range runs {
+		times := beats[run]
+		sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+
+		var step time.Duration
+		for i := 1; i < len(times); i++ {
+			if d := times[i].Sub(times[i-1]); d > 0 && (step == 0 || d < step) {
+				step = d
+			}
+		}
+		if step == 0 {
+			continue
+		}
+
+		for i := 1; i < len(times); i++ {
+			if d := times[i].Sub(times[i-1]); d > gapFactor*step {
+				gaps = append(gaps, CollectionGap{Run: run, Start: times[i-1], End: times[i], Duration: d})
+			}
+		}
+	}
+	return gaps
+}
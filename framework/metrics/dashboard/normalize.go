@@ -0,0 +1,112 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// NormalizeMode selects the achieved load that comparison metrics are divided by
+type NormalizeMode string
+
+const (
+	// NormalizeNone disables load normalization
+	NormalizeNone NormalizeMode = ""
+	// NormalizeByMBps normalizes per MB/s of ingested data, taken from the k6 summary
+	NormalizeByMBps NormalizeMode = "mbps"
+	// NormalizeByKSpans normalizes per 1k spans/s accepted by Tempo.
+	// The k6 summary only reports traces, so the span rate comes from accepted_spans_rate.
+	NormalizeByKSpans NormalizeMode = "kspans"
+)
+
+// normalizedMetrics are the comparison metrics that scale with load and are normalized
+var normalizedMetrics = map[string]bool{
+	"memory_usage_total": true,
+	"cpu_usage_total":    true,
+}
+
+// ParseNormalizeMode validates a normalization mode string
+func ParseNormalizeMode(s string) (NormalizeMode, error) {
+	switch mode := NormalizeMode(strings.ToLower(s)); mode {
+	case NormalizeNone, NormalizeByMBps, NormalizeByKSpans:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid normalization mode %q (must be mbps or kspans)", s)
+	}
+}
+
+// LoadUnit returns the display unit of the achieved load
+func (m NormalizeMode) LoadUnit() string {
+	switch m {
+	case NormalizeByMBps:
+		return "MB/s"
+	case NormalizeByKSpans:
+		return "1k spans/s"
+	default:
+		return ""
+	}
+}
+
+// k6IngestionSummary holds the fields of the exported k6 metrics JSON used for normalization
+type k6IngestionSummary struct {
+	IngestionRateBPS float64 `json:"ingestion_rate_bps"`
+}
+
+// bytesPerMB converts the k6 ingestion rate to MB/s. Like the profiles' mbPerSecond,
+// a MB is 1024*1024 bytes.
+const bytesPerMB = 1024 * 1024
+
+// k6SummaryPath returns the k6 ingestion summary written next to a metrics CSV.
+// perf-runner writes <prefix>-metrics.csv and <prefix>-k6-ingestion-metrics.json
+// (combined runs export ingestion and query summaries separately).
+func k6SummaryPath(csvPath string) string {
+	prefix := strings.TrimSuffix(strings.TrimSuffix(csvPath, ".csv"), "-metrics")
+	return prefix + "-k6-ingestion-metrics.json"
+}
+
+// achievedLoad returns the load a run achieved in the unit of the normalization mode
+func achievedLoad(mode NormalizeMode, csvPath string, metrics []MetricSeries) (float64, error) {
+	switch mode {
+	case NormalizeByMBps:
+		path := k6SummaryPath(csvPath)
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			return 0, fmt.Errorf("no k6 ingestion summary found for %s (looked for %s)", csvPath, path)
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to read k6 summary: %w", err)
+		}
+
+		var summary k6IngestionSummary
+		if err := json.Unmarshal(data, &summary); err != nil {
+			return 0, fmt.Errorf("failed to parse k6 summary %s: %w", path, err)
+		}
+		if summary.IngestionRateBPS <= 0 {
+			return 0, fmt.Errorf("k6 summary %s has no ingestion rate", path)
+		}
+		return summary.IngestionRateBPS / bytesPerMB, nil
+
+	case NormalizeByKSpans:
+		var total float64
+		found := false
+		for _, m := range metrics {
+			if m.Name != "accepted_spans_rate" || len(m.DataPoints) == 0 {
+				continue
+			}
+			var sum float64
+			for _, dp := range m.DataPoints {
+				sum += dp.Value
+			}
+			total += sum / float64(len(m.DataPoints))
+			found = true
+		}
+		if !found || total <= 0 {
+			return 0, fmt.Errorf("no accepted_spans_rate data found in %s", csvPath)
+		}
+		return total / 1000, nil
+
+	default:
+		return 0, fmt.Errorf("unsupported normalization mode %q", mode)
+	}
+}
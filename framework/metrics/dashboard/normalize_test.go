@@ -0,0 +1,130 @@
+package dashboard
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseNormalizeMode(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected NormalizeMode
+		wantErr  bool
+	}{
+		{"", NormalizeNone, false},
+		{"mbps", NormalizeByMBps, false},
+		{"MBps", NormalizeByMBps, false},
+		{"kspans", NormalizeByKSpans, false},
+		{"spans", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			mode, err := ParseNormalizeMode(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseNormalizeMode(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if mode != tt.expected {
+				t.Errorf("ParseNormalizeMode(%q) = %q, want %q", tt.input, mode, tt.expected)
+			}
+		})
+	}
+}
+
+func TestK6SummaryPath(t *testing.T) {
+	got := k6SummaryPath("results/small-a1b2c3-metrics.csv")
+	if got != "results/small-a1b2c3-k6-ingestion-metrics.json" {
+		t.Errorf("unexpected k6 summary path %q", got)
+	}
+}
+
+func TestAchievedLoad_MBps(t *testing.T) {
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "small-a1b2c3-metrics.csv")
+	summary := `{"ingestion_rate_bps": 2097152}`
+	if err := os.WriteFile(filepath.Join(dir, "small-a1b2c3-k6-ingestion-metrics.json"), []byte(summary), 0644); err != nil {
+		t.Fatalf("failed to write summary: %v", err)
+	}
+
+	load, err := achievedLoad(NormalizeByMBps, csvPath, nil)
+	if err != nil {
+		t.Fatalf("achievedLoad() error = %v", err)
+	}
+	if load != 2 {
+		t.Errorf("expected 2 MB/s, got %v", load)
+	}
+}
+
+func TestAchievedLoad_MBpsMissingSummary(t *testing.T) {
+	csvPath := filepath.Join(t.TempDir(), "small-a1b2c3-metrics.csv")
+
+	_, err := achievedLoad(NormalizeByMBps, csvPath, nil)
+	if err == nil || !strings.Contains(err.Error(), "no k6 ingestion summary") {
+		t.Errorf("expected missing summary error, got %v", err)
+	}
+}
+
+func TestAchievedLoad_KSpans(t *testing.T) {
+	metrics := []MetricSeries{
+		{Name: "accepted_spans_rate", DataPoints: []DataPoint{{Value: 1000}, {Value: 3000}}},
+		{Name: "accepted_spans_rate", DataPoints: []DataPoint{{Value: 500}}},
+		{Name: "memory_usage_total", DataPoints: []DataPoint{{Value: 1e9}}},
+	}
+
+	load, err := achievedLoad(NormalizeByKSpans, "small-metrics.csv", metrics)
+	if err != nil {
+		t.Fatalf("achievedLoad() error = %v", err)
+	}
+	if load != 2.5 {
+		t.Errorf("expected 2.5k spans/s, got %v", load)
+	}
+
+	if _, err := achievedLoad(NormalizeByKSpans, "small-metrics.csv", metrics[2:]); err == nil {
+		t.Error("expected error without accepted_spans_rate data")
+	}
+}
+
+func TestBuildComparisonSummary_Normalized(t *testing.T) {
+	g := &Generator{config: DashboardConfig{
+		CompareMode: true,
+		RunNames:    []string{"small", "medium"},
+		NormalizeBy: NormalizeByMBps,
+	}}
+	metrics := []MetricSeries{
+		{Name: "memory_usage_total", Labels: map[string]string{"_run": "small"}, DataPoints: []DataPoint{{Value: 100}, {Value: 300}}},
+		{Name: "memory_usage_total", Labels: map[string]string{"_run": "medium"}, DataPoints: []DataPoint{{Value: 800}}},
+		{Name: "accepted_spans_rate", Labels: map[string]string{"_run": "small"}, DataPoints: []DataPoint{{Value: 1000}}},
+	}
+	loads := map[string]float64{"small": 1, "medium": 4}
+
+	summary := g.buildComparisonSummary(metrics, loads)
+
+	if summary.LoadUnit != "MB/s" || len(summary.Loads) != 2 {
+		t.Fatalf("unexpected loads %+v (unit %q)", summary.Loads, summary.LoadUnit)
+	}
+
+	var memory, spans *ComparisonMetric
+	for i := range summary.KeyMetrics {
+		switch summary.KeyMetrics[i].Name {
+		case "memory_usage_total":
+			memory = &summary.KeyMetrics[i]
+		case "accepted_spans_rate":
+			spans = &summary.KeyMetrics[i]
+		}
+	}
+	if memory == nil || spans == nil {
+		t.Fatalf("expected memory and span metrics, got %+v", summary.KeyMetrics)
+	}
+
+	if len(memory.Normalized) != 2 || memory.Normalized[0].Value != 200 || memory.Normalized[1].Value != 200 {
+		t.Errorf("expected 200 per MB/s for both runs, got %+v", memory.Normalized)
+	}
+	if memory.Normalized[1].Change != 0 {
+		t.Errorf("expected no normalized change, got %v", memory.Normalized[1].Change)
+	}
+	if len(spans.Normalized) != 0 {
+		t.Errorf("accepted_spans_rate should not be normalized, got %+v", spans.Normalized)
+	}
+}
@@ -0,0 +1,179 @@
+package dashboard
+
+import (
+	"sort"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/metrics"
+)
+
+const (
+	// segmentWindow bounds the "before" and "after" segments of a disruption
+	segmentWindow = 5 * time.Minute
+	// incidentGap merges disruption events closer than this into one incident,
+	// e.g. the pod kills of a rollout
+	incidentGap = time.Minute
+	// recoveryMetric is the throughput metric that decides when a disruption is over
+	recoveryMetric = "accepted_spans_rate"
+	// recoveryTolerance is how far below its level before the disruption the
+	// throughput may be and count as recovered
+	recoveryTolerance = 0.1
+)
+
+// buildEventSegments splits the key metrics around each disruption recorded in the
+// cluster events into "before", "during" and "after" segments. A disruption lasts
+// until the throughput is back within recoveryTolerance of its level before it.
+// Returns nil if no disruption happened within the metrics time range.
+func buildEventSegments(series []MetricSeries, events []metrics.ClusterEvent) *EventSegmentSummary {
+	points := make(map[string][]DataPoint)
+	var first, last time.Time
+	for _, m := range series {
+		for _, dp := range m.DataPoints {
+			if first.IsZero() || dp.Timestamp.Before(first) {
+				first = dp.Timestamp
+			}
+			if dp.Timestamp.After(last) {
+				last = dp.Timestamp
+			}
+		}
+		for _, name := range phaseSummaryMetrics {
+			if m.Name == name {
+				points[name] = append(points[name], m.DataPoints...)
+			}
+		}
+	}
+	for _, p := range points {
+		sort.Slice(p, func(i, j int) bool { return p[i].Timestamp.Before(p[j].Timestamp) })
+	}
+
+	incidents := groupIncidents(events, first, last)
+	if len(incidents) == 0 {
+		return nil
+	}
+
+	summary := &EventSegmentSummary{MetricNames: phaseSummaryMetrics}
+	boundary := first
+	for i := range incidents {
+		inc := &incidents[i]
+		start, end := inc.TimeRange.Start, inc.TimeRange.End
+		next := last.Add(time.Nanosecond)
+		if i+1 < len(incidents) {
+			next = incidents[i+1].TimeRange.Start
+		}
+
+		beforeStart := start.Add(-segmentWindow)
+		if beforeStart.Before(boundary) {
+			beforeStart = boundary
+		}
+		inBefore := func(t time.Time) bool { return !t.Before(beforeStart) && t.Before(start) }
+
+		// Without throughput before the disruption, its recovery cannot be told and
+		// it lasts as long as its events; otherwise until recovery or the next one
+		duringEnd := end
+		if baseline := segmentStats(points[recoveryMetric], inBefore); !baseline.HasData || baseline.Avg <= 0 {
+			inc.NoBaseline = true
+		} else {
+			duringEnd = next.Add(-time.Nanosecond)
+			for _, dp := range points[recoveryMetric] {
+				if dp.Timestamp.Before(end) || !dp.Timestamp.Before(next) {
+					continue
+				}
+				if dp.Value >= baseline.Avg*(1-recoveryTolerance) {
+					inc.Recovered = true
+					inc.RecoveryTime = dp.Timestamp.Sub(start)
+					duringEnd = dp.Timestamp
+					break
+				}
+			}
+		}
+		inDuring := func(t time.Time) bool { return !t.Before(start) && !t.After(duringEnd) }
+
+		afterEnd := duringEnd.Add(segmentWindow)
+		if afterEnd.After(next) {
+			afterEnd = next
+		}
+		inAfter := func(t time.Time) bool { return t.After(duringEnd) && t.Before(afterEnd) }
+
+		for _, seg := range []struct {
+			name string
+			in   func(time.Time) bool
+		}{{"before", inBefore}, {"during", inDuring}, {"after", inAfter}} {
+			if stats, ok := segmentPhaseStats(seg.name, points, seg.in); ok {
+				inc.Segments = append(inc.Segments, stats)
+			}
+		}
+		boundary = duringEnd
+	}
+	summary.Incidents = incidents
+	return summary
+}
+
+// groupIncidents merges the disruption events between first and last that are
+// less than incidentGap apart into incidents, in time order
+func groupIncidents(events []metrics.ClusterEvent, first, last time.Time) []Incident {
+	var disruptions []metrics.ClusterEvent
+	for _, e := range events {
+		if e.IsDisruption() && !e.Time.Before(first) && !e.Start().After(last) {
+			disruptions = append(disruptions, e)
+		}
+	}
+	sort.SliceStable(disruptions, func(i, j int) bool {
+		return disruptions[i].Start().Before(disruptions[j].Start())
+	})
+
+	var incidents []Incident
+	for _, e := range disruptions {
+		if n := len(incidents); n > 0 && e.Start().Sub(incidents[n-1].TimeRange.End) < incidentGap {
+			inc := &incidents[n-1]
+			inc.Events = append(inc.Events, e)
+			if e.Time.After(inc.TimeRange.End) {
+				inc.TimeRange.End = e.Time
+			}
+			continue
+		}
+		incidents = append(incidents, Incident{
+			Events:    []metrics.ClusterEvent{e},
+			TimeRange: TimeRange{Start: e.Start(), End: e.Time},
+		})
+	}
+	return incidents
+}
+
+// segmentPhaseStats returns the key metric statistics of the points for which in
+// returns true, with the time range of those points; false if there are none
+func segmentPhaseStats(name string, points map[string][]DataPoint, in func(time.Time) bool) (PhaseStats, bool) {
+	stats := PhaseStats{Name: name}
+	for _, metric := range phaseSummaryMetrics {
+		ms := segmentStats(points[metric], in)
+		ms.Name, ms.Unit = metric, GetMetricUnit(metric)
+		stats.Metrics = append(stats.Metrics, ms)
+
+		for _, dp := range points[metric] {
+			if !in(dp.Timestamp) {
+				continue
+			}
+			if stats.TimeRange.Start.IsZero() || dp.Timestamp.Before(stats.TimeRange.Start) {
+				stats.TimeRange.Start = dp.Timestamp
+			}
+			if dp.Timestamp.After(stats.TimeRange.End) {
+				stats.TimeRange.End = dp.Timestamp
+			}
+		}
+	}
+	return stats, !stats.TimeRange.Start.IsZero()
+}
+
+// segmentStats returns the average and max of the points for which in returns true
+func segmentStats(points []DataPoint, in func(time.Time) bool) PhaseMetricStats {
+	var values []float64
+	for _, dp := range points {
+		if in(dp.Timestamp) {
+			values = append(values, dp.Value)
+		}
+	}
+	if len(values) == 0 {
+		return PhaseMetricStats{}
+	}
+	s := calculateStats(values)
+	return PhaseMetricStats{Avg: s.Avg, Max: s.Max, HasData: true}
+}
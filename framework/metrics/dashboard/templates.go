@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"html/template"
+	"math"
 	"time"
 )
 
@@ -15,10 +16,11 @@ var templateFS embed.FS
 func GetTemplateFuncs() template.FuncMap {
 	return template.FuncMap{
 		"formatBytes":    formatBytes,
+		"formatChange":   formatChange,
 		"formatDuration": formatDuration,
 		"formatPercent":  formatPercent,
 		"formatTime":     formatTime,
-		"formatValue":    formatValue,
+		"formatValue":    FormatValue,
 		"toJSON":         toJSON,
 		"getRunColor":    getRunColor,
 		"sub":            sub,
@@ -65,6 +67,14 @@ func formatPercent(ratio float64) string {
 	return fmt.Sprintf("%.1f%%", ratio*100)
 }
 
+// formatChange formats a relative change in percent, "n/a" when it is unknown (NaN)
+func formatChange(percent float64) string {
+	if math.IsNaN(percent) {
+		return "n/a"
+	}
+	return fmt.Sprintf("%+.1f%%", percent)
+}
+
 // formatTime formats a time for display in UTC
 func formatTime(t time.Time) string {
 	if t.IsZero() {
@@ -73,8 +83,8 @@ func formatTime(t time.Time) string {
 	return t.UTC().Format("15:04:05 UTC")
 }
 
-// formatValue formats a value with its unit
-func formatValue(value float64, unit string) string {
+// FormatValue formats a value with its unit (see GetMetricUnit)
+func FormatValue(value float64, unit string) string {
 	switch unit {
 	case "bytes":
 		return formatBytes(value)
@@ -2,6 +2,8 @@ package dashboard
 
 import (
 	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/metrics"
 )
 
 // ChartType represents the type of chart to render
@@ -25,8 +27,26 @@ type DashboardConfig struct {
 	// Comparison mode settings
 	CompareMode bool
 	RunNames    []string // Names for each run in comparison mode
+	// NormalizeBy divides load-dependent comparison metrics by each run's achieved load
+	NormalizeBy NormalizeMode
+	// AllowMixedResolution compares runs collected with different query steps or
+	// rate windows instead of refusing them
+	AllowMixedResolution bool
+	// MaxPointsPerSeries downsamples longer series while loading, bounding memory
+	// and dashboard size for long runs. Zero keeps every point.
+	MaxPointsPerSeries int
 	// Ingester tuning configuration (if set)
 	IngesterConfig *IngesterTuningConfig
+	// LogErrors summarizes the error patterns found in the component logs (if set)
+	LogErrors *metrics.LogErrorSummary
+	// Attainment compares the requested load rates with the achieved rates (if set)
+	Attainment *metrics.LoadAttainment
+	// Events are the cluster events recorded during the run, marked on the charts
+	Events []metrics.ClusterEvent
+	// Prerequisites are the operators and monitoring state of the cluster (if set)
+	Prerequisites *metrics.PrerequisitesReport
+	// Isolation holds the per-tenant loads of a noisy-neighbor run (if set)
+	Isolation *metrics.TenantIsolation
 }
 
 // IngesterTuningConfig holds ingester tuning parameters for display
@@ -46,6 +66,12 @@ type DashboardData struct {
 	ComparisonSummary *ComparisonSummary
 	// Resource statistics (avg, max, P95, P99)
 	ResourceSummary *ResourceSummary
+	// Key metric statistics per phase (nil unless the test was phased)
+	PhaseSummary *PhaseSummary
+	// Key metric statistics around each disruption in the cluster events (nil if none)
+	EventSegments *EventSegmentSummary
+	// Stretches of the run without collected metrics (nil if none)
+	CollectionGaps []CollectionGap
 }
 
 // TestSummary provides high-level test information
@@ -67,6 +93,9 @@ type ComparisonSummary struct {
 	RunCount   int
 	RunNames   []string
 	KeyMetrics []ComparisonMetric
+	// Load normalization (empty LoadUnit if disabled)
+	LoadUnit string
+	Loads    []ComparisonValue // Achieved load per run
 }
 
 // ComparisonMetric shows a single metric across multiple runs
@@ -74,6 +103,8 @@ type ComparisonMetric struct {
 	Name   string
 	Unit   string
 	Values []ComparisonValue
+	// Normalized holds the values divided by each run's achieved load
+	Normalized []ComparisonValue
 }
 
 // ComparisonValue represents a value from one run
@@ -101,6 +132,9 @@ type ChartConfig struct {
 	Options     ChartOptions
 	// MetricInfo contains the Prometheus metric names and queries used
 	MetricInfo []MetricQueryInfo
+	// Stats summarizes each series in the table under the chart; the script
+	// does not need it
+	Stats []SeriesStats `json:"-"`
 }
 
 // MetricQueryInfo holds the metric name and PromQL query for display
@@ -115,6 +149,7 @@ type SeriesData struct {
 	Labels  map[string]string
 	Data    []DataPoint
 	RunName string // For comparison mode
+	Axis    string // Y axis ID for multi-axis charts (empty = primary)
 }
 
 // DataPoint is a timestamp-value pair
@@ -131,6 +166,17 @@ type ChartOptions struct {
 	ShowLegend  bool
 	ShowGrid    bool
 	ColorScheme string // default, red, blue, green
+	// SecondaryAxes are additional right-hand Y axes for multi-axis charts
+	SecondaryAxes []AxisOptions
+	// SharedTimeAxis spans the X axis over the whole test so stacked charts line up
+	SharedTimeAxis bool
+}
+
+// AxisOptions configures an additional Y axis
+type AxisOptions struct {
+	ID    string // Axis ID referenced by SeriesData.Axis (e.g., "y2")
+	Label string
+	Unit  string // bytes, seconds, percent, cores, count
 }
 
 // MetricSeries represents a single metric time-series from CSV
@@ -160,13 +206,82 @@ type ResourceSummary struct {
 	CPU    []ComponentStats
 }
 
+// Stats contains the statistics of the values of a series
+type Stats struct {
+	Avg float64
+	Max float64
+	Min float64
+	P95 float64
+	P99 float64
+}
+
 // ComponentStats contains statistics for a single component
 type ComponentStats struct {
 	Component string
-	Avg       float64
-	Max       float64
-	Min       float64
-	P95       float64
-	P99       float64
-	Unit      string
+	Stats
+	Unit string
+}
+
+// SeriesStats contains the statistics of a chart series, labeled as in the legend
+type SeriesStats struct {
+	Label string
+	Unit  string
+	Stats
+}
+
+// PhaseSummary contains key metric statistics for each phase of a phased test
+type PhaseSummary struct {
+	MetricNames []string
+	Phases      []PhaseStats
+}
+
+// PhaseStats contains the key metric statistics of a single phase
+type PhaseStats struct {
+	Name      string
+	TimeRange TimeRange
+	// Metrics holds one entry per PhaseSummary.MetricNames, in the same order
+	Metrics []PhaseMetricStats
+}
+
+// EventSegmentSummary contains key metric statistics before, during and after each
+// disruption of the run, such as a pod kill or a scaling event
+type EventSegmentSummary struct {
+	MetricNames []string
+	Incidents   []Incident
+}
+
+// Incident is a group of disruption events close in time, e.g. the pod kills of a rollout
+type Incident struct {
+	Events    []metrics.ClusterEvent
+	TimeRange TimeRange
+	// Segments are the "before", "during" and "after" segments that have data, with
+	// one entry per EventSegmentSummary.MetricNames in their Metrics
+	Segments []PhaseStats
+	// RecoveryTime is the time from the first event until the throughput was back
+	// within 10% of its level before the incident, if Recovered
+	RecoveryTime time.Duration
+	Recovered    bool
+	// NoBaseline is set when there was no throughput before the incident to
+	// measure its recovery against
+	NoBaseline bool
+}
+
+// PhaseMetricStats contains the statistics of a metric within a phase
+type PhaseMetricStats struct {
+	Name    string
+	Unit    string
+	Avg     float64
+	Max     float64
+	HasData bool
+}
+
+// CollectionGap is a stretch of a run without collection heartbeat points, e.g. a
+// Prometheus outage, where the charts have no data
+type CollectionGap struct {
+	// Run is the run of the gap in comparison mode
+	Run string
+	// Start and End are the heartbeat points around the gap
+	Start    time.Time
+	End      time.Time
+	Duration time.Duration
 }
@@ -0,0 +1,101 @@
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ClusterEventsSuffix is the file name suffix of recorded cluster events in a results directory
+const ClusterEventsSuffix = "-events.json"
+
+// ClusterEvent is a Kubernetes Event, or a container termination, recorded in the
+// test namespace during a run. The dashboard marks them on the charts so dips in
+// throughput can be matched with restarts and scheduling problems.
+type ClusterEvent struct {
+	// Time is the last occurrence of the event
+	Time time.Time `json:"time"`
+	// FirstTime is the first occurrence, when the event repeated
+	FirstTime time.Time `json:"first_time,omitempty"`
+	// Type is "Normal" or "Warning"
+	Type   string `json:"type"`
+	Reason string `json:"reason"`
+	// Object is the involved object, e.g. "Pod/tempo-simplest-0"
+	Object  string `json:"object"`
+	Message string `json:"message,omitempty"`
+	// Count is the number of occurrences
+	Count int32 `json:"count"`
+}
+
+// IsWarning reports whether the event is a warning
+func (e ClusterEvent) IsWarning() bool {
+	return e.Type == "Warning"
+}
+
+// disruptionReasons are the reasons of events that take capacity away from the
+// load: pod kills, preemption, eviction, scaling and container terminations
+var disruptionReasons = map[string]bool{
+	"Killing":           true,
+	"Preempting":        true,
+	"Evicted":           true,
+	"SuccessfulRescale": true,
+	"ScalingReplicaSet": true,
+	"OOMKilled":         true,
+	"Error":             true,
+	"Terminated":        true,
+}
+
+// IsDisruption reports whether the event is a failure or scaling event whose impact
+// on the load is worth measuring, as opposed to e.g. a failing probe
+func (e ClusterEvent) IsDisruption() bool {
+	return disruptionReasons[e.Reason]
+}
+
+// IsQuotaExceeded reports whether the event is a pod creation rejected by a
+// ResourceQuota of the namespace
+func (e ClusterEvent) IsQuotaExceeded() bool {
+	return e.IsWarning() && strings.Contains(e.Message, "exceeded quota")
+}
+
+// Start returns the first occurrence of the event
+func (e ClusterEvent) Start() time.Time {
+	if !e.FirstTime.IsZero() {
+		return e.FirstTime
+	}
+	return e.Time
+}
+
+// WriteClusterEvents writes recorded cluster events as JSON
+func WriteClusterEvents(events []ClusterEvent, outputPath string) error {
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	if events == nil {
+		events = []ClusterEvent{}
+	}
+	data, err := json.MarshalIndent(events, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cluster events: %w", err)
+	}
+	if err := os.WriteFile(outputPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write cluster events: %w", err)
+	}
+	return nil
+}
+
+// LoadClusterEvents reads cluster events written by WriteClusterEvents
+func LoadClusterEvents(path string) ([]ClusterEvent, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cluster events: %w", err)
+	}
+	var events []ClusterEvent
+	if err := json.Unmarshal(data, &events); err != nil {
+		return nil, fmt.Errorf("failed to parse cluster events %s: %w", path, err)
+	}
+	return events, nil
+}
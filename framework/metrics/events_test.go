@@ -0,0 +1,24 @@
+package metrics
+
+import "testing"
+
+func TestClusterEvent_IsQuotaExceeded(t *testing.T) {
+	exceeded := ClusterEvent{
+		Type:    "Warning",
+		Reason:  "FailedCreate",
+		Object:  "Job/k6-ingestion-medium",
+		Message: `pods "k6-ingestion-medium-x2" is forbidden: exceeded quota: tempo-perf-quota, requested: limits.cpu=2, used: limits.cpu=15, limited: limits.cpu=16`,
+	}
+	if !exceeded.IsQuotaExceeded() {
+		t.Errorf("expected %+v to be a quota-exceeded event", exceeded)
+	}
+
+	for _, e := range []ClusterEvent{
+		{Type: "Warning", Reason: "FailedScheduling", Message: "0/3 nodes are available: 3 Insufficient cpu."},
+		{Type: "Normal", Reason: "SuccessfulCreate", Message: "exceeded quota"},
+	} {
+		if e.IsQuotaExceeded() {
+			t.Errorf("expected %+v not to be a quota-exceeded event", e)
+		}
+	}
+}
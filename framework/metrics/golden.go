@@ -0,0 +1,188 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+
+	"sigs.k8s.io/yaml"
+)
+
+// GoldenRanges are the expected ranges of key metrics of a fixed workload. A smoke
+// run whose metrics fall outside them indicates a broken pipeline rather than a
+// performance change, so the ranges are deliberately wide.
+type GoldenRanges struct {
+	// Description documents the workload the ranges were recorded with
+	Description string `json:"description,omitempty"`
+	// Metrics are the checked metrics, by name (see GetAllQueries)
+	Metrics []GoldenRange `json:"metrics"`
+}
+
+// GoldenRange is the expected range of one metric
+type GoldenRange struct {
+	Metric string `json:"metric"`
+	// Aggregate reduces all values of the metric to one value: "avg" or "max"
+	// Default: "avg"
+	Aggregate string `json:"aggregate,omitempty"`
+	// Min and Max bound the aggregated value (inclusive); nil leaves a side open
+	Min *float64 `json:"min,omitempty"`
+	Max *float64 `json:"max,omitempty"`
+}
+
+// GoldenCheck is the result of checking a metric against its golden range
+type GoldenCheck struct {
+	Range GoldenRange
+	// Value is the aggregated value, valid when Found is set
+	Value float64
+	// Found is false when the metric has no values
+	Found  bool
+	Passed bool
+}
+
+// LoadGoldenRanges reads golden ranges from a YAML file
+func LoadGoldenRanges(path string) (*GoldenRanges, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read golden ranges: %w", err)
+	}
+
+	var golden GoldenRanges
+	if err := yaml.Unmarshal(data, &golden); err != nil {
+		return nil, fmt.Errorf("failed to parse golden ranges %s: %w", path, err)
+	}
+	if len(golden.Metrics) == 0 {
+		return nil, fmt.Errorf("golden ranges %s define no metrics", path)
+	}
+	for i, r := range golden.Metrics {
+		if r.Metric == "" {
+			return nil, fmt.Errorf("golden ranges %s: metrics[%d].metric is required", path, i)
+		}
+		if r.Aggregate != "" && r.Aggregate != "avg" && r.Aggregate != "max" {
+			return nil, fmt.Errorf("golden ranges %s: metrics[%d].aggregate must be 'avg' or 'max', got %q", path, i, r.Aggregate)
+		}
+		if r.Min == nil && r.Max == nil {
+			return nil, fmt.Errorf("golden ranges %s: metric %s needs a min or max", path, r.Metric)
+		}
+		if r.Min != nil && r.Max != nil && *r.Min > *r.Max {
+			return nil, fmt.Errorf("golden ranges %s: metric %s has min above max", path, r.Metric)
+		}
+	}
+	return &golden, nil
+}
+
+// WriteGoldenRanges writes golden ranges to a YAML file
+func WriteGoldenRanges(golden *GoldenRanges, path string) error {
+	data, err := yaml.Marshal(golden)
+	if err != nil {
+		return fmt.Errorf("failed to encode golden ranges: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create golden ranges directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write golden ranges: %w", err)
+	}
+	return nil
+}
+
+// CheckGoldenRanges aggregates the values of each golden metric across all its series
+// and checks them against the range. Metrics without values fail.
+func CheckGoldenRanges(results []MetricResult, golden *GoldenRanges) []GoldenCheck {
+	checks := make([]GoldenCheck, 0, len(golden.Metrics))
+	for _, r := range golden.Metrics {
+		check := GoldenCheck{Range: r}
+		check.Value, check.Found = aggregateMetric(results, r.Metric, r.Aggregate)
+		check.Passed = check.Found &&
+			(r.Min == nil || check.Value >= *r.Min) &&
+			(r.Max == nil || check.Value <= *r.Max)
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// RecordGoldenRanges returns golden with the bounds of each metric re-derived from
+// results: a set Min becomes value/factor and a set Max value*factor. Metrics without
+// values, or with a zero value (e.g. refused spans), keep their bounds.
+func RecordGoldenRanges(results []MetricResult, golden *GoldenRanges, factor float64) *GoldenRanges {
+	recorded := &GoldenRanges{Description: golden.Description}
+	for _, r := range golden.Metrics {
+		value, found := aggregateMetric(results, r.Metric, r.Aggregate)
+		if found && value != 0 {
+			if r.Min != nil {
+				r.Min = goldenBound(value / factor)
+			}
+			if r.Max != nil {
+				r.Max = goldenBound(value * factor)
+			}
+		}
+		recorded.Metrics = append(recorded.Metrics, r)
+	}
+	return recorded
+}
+
+// goldenBound rounds a recorded bound to 3 significant digits to keep the file readable
+func goldenBound(v float64) *float64 {
+	if v == 0 {
+		return &v
+	}
+	scale := math.Pow(10, 2-math.Floor(math.Log10(math.Abs(v))))
+	rounded := math.Round(v*scale) / scale
+	return &rounded
+}
+
+// aggregateMetric reduces the non-NaN values of all series of a metric
+func aggregateMetric(results []MetricResult, metric, aggregate string) (float64, bool) {
+	var sum, maxValue float64
+	count := 0
+	for _, res := range results {
+		if res.MetricName != metric || res.Error != nil {
+			continue
+		}
+		for _, dp := range res.DataPoints {
+			if math.IsNaN(dp.Value) || math.IsInf(dp.Value, 0) {
+				continue
+			}
+			if count == 0 || dp.Value > maxValue {
+				maxValue = dp.Value
+			}
+			sum += dp.Value
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, false
+	}
+	if aggregate == "max" {
+		return maxValue, true
+	}
+	return sum / float64(count), true
+}
+
+// String describes the check, e.g. "query_duration_p99 (max) = 1.2 in [0, 10]: ok"
+func (c GoldenCheck) String() string {
+	aggregate := c.Range.Aggregate
+	if aggregate == "" {
+		aggregate = "avg"
+	}
+	bounds := "["
+	if c.Range.Min != nil {
+		bounds += fmt.Sprintf("%g", *c.Range.Min)
+	} else {
+		bounds += "-inf"
+	}
+	if c.Range.Max != nil {
+		bounds += fmt.Sprintf(", %g]", *c.Range.Max)
+	} else {
+		bounds += ", +inf]"
+	}
+
+	if !c.Found {
+		return fmt.Sprintf("%s (%s): no data, expected %s: MISSING", c.Range.Metric, aggregate, bounds)
+	}
+	status := "ok"
+	if !c.Passed {
+		status = "OUT OF RANGE"
+	}
+	return fmt.Sprintf("%s (%s) = %.4g in %s: %s", c.Range.Metric, aggregate, c.Value, bounds, status)
+}
@@ -0,0 +1,139 @@
+package metrics
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func goldenResults() []MetricResult {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	series := func(name string, pod string, values ...float64) MetricResult {
+		r := MetricResult{MetricName: name, Labels: map[string]string{"pod": pod}}
+		for i, v := range values {
+			r.DataPoints = append(r.DataPoints, DataPoint{Timestamp: start.Add(time.Duration(i) * time.Minute), Value: v})
+		}
+		return r
+	}
+	return []MetricResult{
+		series("accepted_spans_rate", "", 100, 200, 300),
+		series("query_duration_p99", "", 0.5, math.NaN(), 2.5),
+		series("memory_usage_by_pod", "tempo-0", 100),
+		series("memory_usage_by_pod", "tempo-1", 300),
+	}
+}
+
+func floatPtr(v float64) *float64 { return &v }
+
+func TestCheckGoldenRanges(t *testing.T) {
+	golden := &GoldenRanges{Metrics: []GoldenRange{
+		{Metric: "accepted_spans_rate", Min: floatPtr(150), Max: floatPtr(250)},
+		{Metric: "query_duration_p99", Aggregate: "max", Max: floatPtr(2)},
+		{Metric: "memory_usage_by_pod", Aggregate: "max", Max: floatPtr(1000)},
+		{Metric: "refused_spans_rate", Max: floatPtr(1)},
+	}}
+
+	checks := CheckGoldenRanges(goldenResults(), golden)
+	if len(checks) != 4 {
+		t.Fatalf("expected 4 checks, got %d", len(checks))
+	}
+
+	if !checks[0].Passed || checks[0].Value != 200 {
+		t.Errorf("expected avg 200 in range, got %+v", checks[0])
+	}
+	if checks[1].Passed || checks[1].Value != 2.5 {
+		t.Errorf("expected max 2.5 out of range, got %+v", checks[1])
+	}
+	if !checks[2].Passed || checks[2].Value != 300 {
+		t.Errorf("expected max 300 across series, got %+v", checks[2])
+	}
+	if checks[3].Passed || checks[3].Found {
+		t.Errorf("expected a missing metric to fail, got %+v", checks[3])
+	}
+
+	if got := checks[1].String(); !strings.Contains(got, "OUT OF RANGE") || !strings.Contains(got, "[-inf, 2]") {
+		t.Errorf("unexpected description %q", got)
+	}
+	if got := checks[3].String(); !strings.Contains(got, "MISSING") {
+		t.Errorf("unexpected description %q", got)
+	}
+}
+
+func TestRecordGoldenRanges(t *testing.T) {
+	golden := &GoldenRanges{Description: "smoke", Metrics: []GoldenRange{
+		{Metric: "accepted_spans_rate", Min: floatPtr(10)},
+		{Metric: "query_duration_p99", Aggregate: "max", Max: floatPtr(30)},
+		{Metric: "refused_spans_rate", Aggregate: "max", Max: floatPtr(1)},
+	}}
+
+	recorded := RecordGoldenRanges(goldenResults(), golden, 3)
+	if recorded.Description != "smoke" || len(recorded.Metrics) != 3 {
+		t.Fatalf("unexpected recorded ranges %+v", recorded)
+	}
+	if m := recorded.Metrics[0]; m.Min == nil || *m.Min != 66.7 || m.Max != nil {
+		t.Errorf("expected min 66.7 and no max, got %+v", m)
+	}
+	if m := recorded.Metrics[1]; *m.Max != 7.5 {
+		t.Errorf("expected max 7.5, got %v", *m.Max)
+	}
+	if m := recorded.Metrics[2]; *m.Max != 1 {
+		t.Errorf("expected a metric without data to keep its bounds, got %v", *m.Max)
+	}
+	if *golden.Metrics[0].Min != 10 {
+		t.Error("expected the original ranges to be unchanged")
+	}
+}
+
+func TestLoadGoldenRanges(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "golden.yaml")
+
+	golden := &GoldenRanges{Description: "smoke", Metrics: []GoldenRange{
+		{Metric: "accepted_spans_rate", Min: floatPtr(10)},
+		{Metric: "query_duration_p99", Aggregate: "max", Max: floatPtr(30)},
+	}}
+	if err := WriteGoldenRanges(golden, path); err != nil {
+		t.Fatalf("unexpected write error: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "aggregate: max") {
+		t.Errorf("expected lowercase YAML keys, got:\n%s", data)
+	}
+
+	loaded, err := LoadGoldenRanges(path)
+	if err != nil {
+		t.Fatalf("unexpected load error: %v", err)
+	}
+	if len(loaded.Metrics) != 2 || *loaded.Metrics[1].Max != 30 || loaded.Metrics[0].Max != nil {
+		t.Errorf("unexpected ranges %+v", loaded.Metrics)
+	}
+
+	for name, content := range map[string]string{
+		"empty":         "metrics: []\n",
+		"no bounds":     "metrics:\n  - metric: accepted_spans_rate\n",
+		"bad aggregate": "metrics:\n  - metric: accepted_spans_rate\n    aggregate: p99\n    min: 1\n",
+		"min above max": "metrics:\n  - metric: accepted_spans_rate\n    min: 10\n    max: 1\n",
+	} {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadGoldenRanges(path); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestLoadGoldenRanges_SmokeProfile(t *testing.T) {
+	golden, err := LoadGoldenRanges("../../profiles/smoke/golden.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, r := range golden.Metrics {
+		if _, err := lookupQuery("ns", r.Metric); err != nil {
+			t.Errorf("golden metric %s is not collected: %v", r.Metric, err)
+		}
+	}
+}
@@ -3,11 +3,15 @@ package metrics
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/redhat/perf-tests-tempo/test/framework/config"
 	"github.com/redhat/perf-tests-tempo/test/framework/k6"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -23,6 +27,41 @@ type ConfigProvider interface {
 	Config() *rest.Config
 }
 
+// FrameworkConfigProvider optionally provides framework configuration (e.g. operator namespaces)
+type FrameworkConfigProvider interface {
+	FrameworkConfig() *config.Config
+}
+
+// RunIDProvider optionally provides the run ID added as a label to collected metrics
+type RunIDProvider interface {
+	RunID() string
+}
+
+// ClockProvider optionally provides the cluster-side current time. Collection windows
+// end at it, so they match the sample timestamps when the runner clock is skewed.
+type ClockProvider interface {
+	Now() time.Time
+}
+
+// ContextProvider optionally provides the context of the test, whose trace the
+// Prometheus queries are recorded in
+type ContextProvider interface {
+	Context() context.Context
+}
+
+// RunIDLabel is the label added to collected metrics to identify the run
+const RunIDLabel = "run_id"
+
+// ProfileLabel and VariantLabel are the labels of the profile and Tempo variant of
+// a run in metrics pushed via remote write
+const (
+	ProfileLabel = "profile"
+	VariantLabel = "variant"
+)
+
+// IngestionProtocolLabel is the label of the OTLP protocol traces were ingested with
+const IngestionProtocolLabel = "ingestion_protocol"
+
 // CollectMetrics collects performance metrics for the test namespace and exports to CSV
 // This should be called at the end of your test, before cleanup
 //
@@ -31,12 +70,35 @@ type ConfigProvider interface {
 //	testStart := time.Now()
 //	// ... run your test ...
 //	err := metrics.CollectMetrics(fw, testStart, "results/my-test.csv")
-func CollectMetrics(np NamespaceProvider, testStart time.Time, outputPath string) error {
-	ctx := context.Background()
+func CollectMetrics(np NamespaceProvider, testStart time.Time, outputPath string, opts ...CollectOption) error {
+	return collectMetrics(np, testStart, outputPath, false, opts)
+}
+
+// CollectMetricsIncremental collects metrics like CollectMetrics, saving the results
+// of each completed query to CheckpointDir(outputPath). When queries fail (e.g. a
+// Thanos error), it returns an error wrapping ErrCollectionIncomplete and keeps the
+// checkpoint; calling it again with the same testStart only runs the queries that
+// did not complete, over the window of the first attempt. The checkpoint is removed
+// once the metrics are exported. With WithRollups, the rollups of the intervals
+// that completed are kept instead. With WithPartialResults, the metrics of the
+// queries that completed are exported instead of returning the error.
+func CollectMetricsIncremental(np NamespaceProvider, testStart time.Time, outputPath string, opts ...CollectOption) error {
+	return collectMetrics(np, testStart, outputPath, true, opts)
+}
+
+// collectMetrics collects and exports the metrics of the test window, checkpointing
+// the completed queries when incremental is set
+func collectMetrics(np NamespaceProvider, testStart time.Time, outputPath string, incremental bool, opts []CollectOption) error {
+	ctx := traceContextFor(np)
+	var o collectOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
 	namespace := np.Namespace()
 
 	// Calculate duration
-	duration := time.Since(testStart)
+	endTime := nowFor(np)
+	duration := endTime.Sub(testStart)
 
 	fmt.Printf("\n📊 Collecting metrics for namespace: %s\n", namespace)
 	fmt.Printf("   Duration: %s\n", duration.Round(time.Second))
@@ -47,52 +109,110 @@ func CollectMetrics(np NamespaceProvider, testStart time.Time, outputPath string
 		return fmt.Errorf("failed to create output directory: %w", err)
 	}
 
-	// Get KubeConfig - try interface first, then fall back to discovery
-	var kubeConfig *rest.Config
-	if cp, ok := np.(ConfigProvider); ok {
-		kubeConfig = cp.Config()
-	} else {
-		// Fall back to standard config discovery
-		var err error
-		kubeConfig, err = rest.InClusterConfig()
-		if err != nil {
-			// Use KUBECONFIG env var if set, otherwise fall back to ~/.kube/config
-			loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
-			configOverrides := &clientcmd.ConfigOverrides{}
-			clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, configOverrides)
-			kubeConfig, err = clientConfig.ClientConfig()
-			if err != nil {
-				return fmt.Errorf("failed to get kube config: %w", err)
-			}
-		}
+	// The test is over, so stop sampling resource usage and scraping Tempo
+	sampler := resourceSamplerFor(np)
+	if sampler != nil {
+		sampler.Stop()
 	}
-
-	// Create metrics client with auto-discovery
-	config := &ClientConfig{
-		Namespace:           namespace,
-		AutoDiscover:        true,
-		MonitoringNamespace: "openshift-monitoring",
-		ServiceAccountName:  "prometheus-k8s",
-		KubeConfig:          kubeConfig,
+	scraper := metricsScraperFor(np)
+	if scraper != nil {
+		scraper.Stop()
 	}
-
-	client, err := NewClient(ctx, config)
-	if err != nil {
-		return fmt.Errorf("failed to create metrics client: %w", err)
+	storage := storageSamplerFor(np)
+	if storage != nil {
+		storage.Stop()
+	}
+	if rp, ok := np.(RollupWriterProvider); ok && rp.RollupWriter() != nil {
+		rp.RollupWriter().Stop()
 	}
 
 	// Collect all metrics from test start to now
-	endTime := time.Now()
-	results, err := client.CollectAllMetrics(ctx, testStart, endTime)
+	exportMeta := &ExportMetadata{
+		Source:     SourcePrometheus,
+		Resolution: ResolutionFromConfig(frameworkConfigFor(np)),
+	}
+	checkpointDir := ""
+	if incremental && o.rollupInterval <= 0 {
+		checkpointDir = CheckpointDir(outputPath)
+	}
+	var (
+		results, summaryResults []MetricResult
+		err                     error
+		stats                   = newQueryStatsRecorder()
+	)
+	if o.rollupInterval > 0 {
+		results, summaryResults, err = collectRollupsFromPrometheus(ctx, np, testStart, endTime, o.rollupInterval, RollupDir(outputPath), stats)
+	} else {
+		results, summaryResults, err = collectFromPrometheus(ctx, np, testStart, endTime, checkpointDir, stats)
+	}
+	if errors.Is(err, ErrCollectionIncomplete) {
+		// Like a collection without checkpoint, export what was collected, unless
+		// the caller retries the failed queries
+		if incremental && !(o.partialResults && hasCollected(results)) {
+			return err
+		}
+		fmt.Printf("⚠️  Warning: %v, exporting partial results\n", err)
+		err = nil
+	}
 	if err != nil {
-		return fmt.Errorf("failed to collect metrics: %w", err)
+		// Fall back to the Tempo metrics scraped directly and the resource usage
+		// sampled from metrics-server
+		var sources []string
+		results = nil
+		exportMeta.Resolution = Resolution{}
+		if scraper != nil && scraper.HasSamples() {
+			results = append(results, scraper.Results()...)
+			sources = append(sources, SourceScrape)
+			exportMeta.Resolution = Resolution{Step: scraper.Interval()}
+		}
+		if sampler != nil && sampler.HasSamples() {
+			results = append(results, sampler.Results()...)
+			sources = append(sources, SourceMetricsServer)
+			if exportMeta.Resolution.Step == 0 {
+				exportMeta.Resolution = Resolution{Step: sampler.Interval()}
+			}
+		}
+		if storage != nil && storage.HasSamples() {
+			results = append(results, storage.Results()...)
+			sources = append(sources, SourceStorage)
+		}
+		if len(sources) == 0 {
+			return err
+		}
+		fmt.Printf("⚠️  Warning: %v\n", err)
+		fmt.Printf("   Exporting metrics from %s instead\n", strings.Join(sources, " and "))
+		exportMeta.Source = strings.Join(sources, ",")
+	} else if scraper != nil && scraper.HasSamples() {
+		// Prometheus may be reachable without scraping the namespace
+		var filled int
+		results, filled = FillFromScrape(results, scraper.Results())
+		if filled > 0 {
+			fmt.Printf("   %d queries without data in Prometheus exported from direct scrapes of Tempo\n", filled)
+			exportMeta.Source = SourcePrometheus + "," + SourceScrape
+		}
+	}
+	if err == nil && storage != nil && storage.HasSamples() {
+		// Prometheus rarely scrapes the object storage
+		var filled int
+		results, filled = FillFromScrape(results, storage.Results())
+		if filled > 0 {
+			fmt.Println("   Storage footprint exported from samples of the trace storage")
+			exportMeta.Source += "," + SourceStorage
+		}
 	}
 
-	// Collect summary metrics (P99/max/avg over full test duration)
-	summaryResults, err := client.CollectSummaryMetrics(ctx, endTime)
-	if err != nil {
-		fmt.Printf("⚠️  Warning: failed to collect summary metrics: %v\n", err)
-		// Continue without summary metrics
+	// Split phased tests into one series per phase
+	if pp, ok := np.(PhaseProvider); ok && len(pp.Phases()) > 0 {
+		results = SplitByPhase(results, pp.Phases())
+		fmt.Printf("   Split into %d phases\n", len(pp.Phases()))
+	}
+
+	// Tag results with the run ID so metrics from different runs can be told apart
+	addRunID(np, results)
+	addRunID(np, summaryResults)
+	for key, value := range o.labels {
+		addLabel(results, key, value)
+		addLabel(summaryResults, key, value)
 	}
 
 	// Export to CSV
@@ -101,6 +221,20 @@ func CollectMetrics(np NamespaceProvider, testStart time.Time, outputPath string
 		return fmt.Errorf("failed to export metrics: %w", err)
 	}
 
+	// Record the resolution so comparisons can refuse mismatched inputs, and the
+	// queries to diagnose slow or failing ones
+	exportMeta.ExportedAt = time.Now().UTC()
+	exportMeta.Queries = stats.list()
+	for _, s := range exportMeta.Queries {
+		if s.Failed() {
+			exportMeta.FailedQueries++
+		}
+	}
+	printQueryStats(exportMeta.Queries, slowestQueries)
+	if err := WriteExportMetadata(exportMeta, outputPath); err != nil {
+		fmt.Printf("⚠️  Warning: %v\n", err)
+	}
+
 	// Export summary metrics to JSON
 	if len(summaryResults) > 0 {
 		summaryPath := outputPath[:len(outputPath)-len(filepath.Ext(outputPath))] + "-summary.json"
@@ -111,10 +245,203 @@ func CollectMetrics(np NamespaceProvider, testStart time.Time, outputPath string
 		}
 	}
 
+	if checkpointDir != "" {
+		if err := os.RemoveAll(checkpointDir); err != nil {
+			fmt.Printf("⚠️  Warning: failed to remove checkpoint: %v\n", err)
+		}
+	}
+
 	fmt.Printf("✅ Metrics collection complete: %d data series exported\n\n", len(results))
 	return nil
 }
 
+// collectFromPrometheus collects the range metrics of a time window and the summary
+// metrics of the test from Prometheus/Thanos. Failing summary metrics are only logged.
+// A non-empty checkpointDir checkpoints the range queries, see CollectMetricsIncremental;
+// failed queries then return an error wrapping ErrCollectionIncomplete along with the
+// results. The queries are recorded in stats.
+func collectFromPrometheus(ctx context.Context, np NamespaceProvider, start, end time.Time, checkpointDir string, stats *queryStatsRecorder) ([]MetricResult, []MetricResult, error) {
+	c, err := newCollectionFor(ctx, np)
+	if err != nil {
+		return nil, nil, err
+	}
+	c.stats = stats
+
+	var cp *checkpoint
+	if checkpointDir != "" {
+		var resumed bool
+		cp, resumed, err = openCheckpoint(checkpointDir, checkpointState{
+			Namespace:  np.Namespace(),
+			Start:      start,
+			End:        end,
+			Resolution: c.resolution,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		if resumed {
+			end = cp.state.End
+			fmt.Printf("♻️  Resuming metrics collection from %s\n", checkpointDir)
+		}
+	}
+
+	results, collectErr := c.collectAllMetrics(ctx, start, end, cp)
+	if collectErr != nil && !errors.Is(collectErr, ErrCollectionIncomplete) {
+		return nil, nil, fmt.Errorf("failed to collect metrics: %w", collectErr)
+	}
+
+	// Collect summary metrics (P99/max/avg over full test duration)
+	summaryResults, err := c.collectSummaryMetrics(ctx, end)
+	if err != nil {
+		fmt.Printf("⚠️  Warning: failed to collect summary metrics: %v\n", err)
+		// Continue without summary metrics
+	}
+
+	return results, summaryResults, collectErr
+}
+
+// collectRollupsFromPrometheus collects the range metrics of a time window like
+// collectFromPrometheus, interval by interval through the rollups in dir (see
+// WithRollups). Failed queries return an error wrapping ErrCollectionIncomplete
+// along with the results. The queries are recorded in stats.
+func collectRollupsFromPrometheus(ctx context.Context, np NamespaceProvider, start, end time.Time, interval time.Duration, dir string, stats *queryStatsRecorder) ([]MetricResult, []MetricResult, error) {
+	c, err := newCollectionFor(ctx, np)
+	if err != nil {
+		return nil, nil, err
+	}
+	c.stats = stats
+
+	results, collectErr := collectRollups(ctx, c, np, start, end, interval, dir, true)
+	if collectErr != nil && !errors.Is(collectErr, ErrCollectionIncomplete) {
+		return nil, nil, fmt.Errorf("failed to collect metrics: %w", collectErr)
+	}
+
+	summaryResults, err := c.collectSummaryMetrics(ctx, end)
+	if err != nil {
+		fmt.Printf("⚠️  Warning: failed to collect summary metrics: %v\n", err)
+	}
+
+	return results, summaryResults, collectErr
+}
+
+// slowestQueries is the number of slowest queries printed after a collection
+const slowestQueries = 5
+
+// hasCollected reports whether any query of a collection returned data or no data,
+// rather than failing
+func hasCollected(results []MetricResult) bool {
+	for _, r := range results {
+		if r.Error == nil || errors.Is(r.Error, ErrNoData) {
+			return true
+		}
+	}
+	return false
+}
+
+// newCollectionFor creates a collection of the metrics of the namespace of the
+// provider. The queries go to the Collector of the provider, if it has one, or to
+// the one of the mode of the framework configuration, with auto-discovery.
+func newCollectionFor(ctx context.Context, np NamespaceProvider) (*collection, error) {
+	if cp, ok := np.(CollectorProvider); ok && cp.MetricsCollector() != nil {
+		return newCollection(cp.MetricsCollector(), DefaultClientConfig(np.Namespace(), nil, frameworkConfigFor(np))), nil
+	}
+
+	kubeConfig, err := kubeConfigFor(np)
+	if err != nil {
+		return nil, err
+	}
+
+	clientConfig := DefaultClientConfig(np.Namespace(), kubeConfig, frameworkConfigFor(np))
+	collector, err := NewCollector(ctx, clientConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create metrics client: %w", err)
+	}
+	return newCollection(collector, clientConfig), nil
+}
+
+// resourceSamplerFor returns the resource sampler of the provider, or nil
+func resourceSamplerFor(np NamespaceProvider) *ResourceSampler {
+	if sp, ok := np.(ResourceSamplerProvider); ok {
+		return sp.ResourceSampler()
+	}
+	return nil
+}
+
+// metricsScraperFor returns the Tempo metrics scraper of the provider, or nil
+func metricsScraperFor(np NamespaceProvider) *MetricsScraper {
+	if sp, ok := np.(MetricsScraperProvider); ok {
+		return sp.MetricsScraper()
+	}
+	return nil
+}
+
+// storageSamplerFor returns the trace storage sampler of the provider, or nil
+func storageSamplerFor(np NamespaceProvider) *StorageSampler {
+	if sp, ok := np.(StorageSamplerProvider); ok {
+		return sp.StorageSampler()
+	}
+	return nil
+}
+
+// kubeConfigFor returns the REST config of the provider, falling back to
+// in-cluster config and then to KUBECONFIG or ~/.kube/config
+func kubeConfigFor(np NamespaceProvider) (*rest.Config, error) {
+	if cp, ok := np.(ConfigProvider); ok {
+		return cp.Config(), nil
+	}
+
+	kubeConfig, err := rest.InClusterConfig()
+	if err == nil {
+		return kubeConfig, nil
+	}
+
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	configOverrides := &clientcmd.ConfigOverrides{}
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, configOverrides)
+	kubeConfig, err = clientConfig.ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get kube config: %w", err)
+	}
+	return kubeConfig, nil
+}
+
+// nowFor returns the cluster-side time of the provider, or the runner time
+func nowFor(np NamespaceProvider) time.Time {
+	if cp, ok := np.(ClockProvider); ok {
+		return cp.Now()
+	}
+	return time.Now()
+}
+
+// traceContextFor returns a context carrying the trace of the provider's context.
+// It is not cancelled with the test, so metrics of an interrupted run are still
+// collected.
+func traceContextFor(np NamespaceProvider) context.Context {
+	if cp, ok := np.(ContextProvider); ok && cp.Context() != nil {
+		return context.WithoutCancel(cp.Context())
+	}
+	return context.Background()
+}
+
+// frameworkConfigFor returns the framework configuration of the provider, or nil
+// if it does not provide one
+func frameworkConfigFor(np NamespaceProvider) *config.Config {
+	if fp, ok := np.(FrameworkConfigProvider); ok {
+		return fp.FrameworkConfig()
+	}
+	return nil
+}
+
+// addLabel sets a label on every result
+func addLabel(results []MetricResult, key, value string) {
+	for i := range results {
+		if results[i].Labels == nil {
+			results[i].Labels = map[string]string{}
+		}
+		results[i].Labels[key] = value
+	}
+}
+
 // SummaryMetricsExport represents the JSON export of summary metrics
 type SummaryMetricsExport struct {
 	ExportedAt string               `json:"exported_at"`
@@ -177,9 +504,88 @@ func exportSummaryMetrics(results []MetricResult, outputPath string) error {
 // Example:
 //
 //	err := metrics.CollectMetricsWithDuration(fw, 30*time.Minute, "results/my-test.csv")
-func CollectMetricsWithDuration(np NamespaceProvider, duration time.Duration, outputPath string) error {
-	testStart := time.Now().Add(-duration)
-	return CollectMetrics(np, testStart, outputPath)
+//
+// For multi-hour soak tests, WithRollups keeps the metrics of each interval:
+//
+//	err := metrics.CollectMetricsWithDuration(fw, 8*time.Hour, "results/soak.csv", metrics.WithRollups(30*time.Minute))
+func CollectMetricsWithDuration(np NamespaceProvider, duration time.Duration, outputPath string, opts ...CollectOption) error {
+	testStart := nowFor(np).Add(-duration)
+	return CollectMetrics(np, testStart, outputPath, opts...)
+}
+
+// PrometheusClockOffset returns how far the clock of Prometheus is ahead of the
+// runner clock. Samples are timestamped by Prometheus, so this is the offset that
+// aligns runner timestamps with collected metrics.
+func PrometheusClockOffset(np NamespaceProvider) (time.Duration, error) {
+	ctx := traceContextFor(np)
+
+	c, err := newCollectionFor(ctx, np)
+	if err != nil {
+		return 0, err
+	}
+
+	before := time.Now()
+	serverTime, err := ServerTime(ctx, c.collector)
+	if err != nil {
+		return 0, err
+	}
+	return ClockOffset(before, time.Now(), serverTime), nil
+}
+
+// ClockOffset returns how far serverTime is ahead of the local clock, for a server
+// time read by a request sent at before and answered at after. The server is
+// assumed to have answered halfway through the request.
+func ClockOffset(before, after, serverTime time.Time) time.Duration {
+	midpoint := before.Add(after.Sub(before) / 2)
+	return serverTime.Sub(midpoint)
+}
+
+// DistributorSpansReceived returns the number of spans the Tempo distributors
+// in the namespace received between start and end
+func DistributorSpansReceived(np NamespaceProvider, start, end time.Time) (float64, error) {
+	return distributorIncrease(np, "tempo_distributor_spans_received_total", start, end)
+}
+
+// DistributorBytesReceived returns the number of bytes the Tempo distributors
+// in the namespace received between start and end
+func DistributorBytesReceived(np NamespaceProvider, start, end time.Time) (float64, error) {
+	return distributorIncrease(np, "tempo_distributor_bytes_received_total", start, end)
+}
+
+// distributorIncrease returns the increase of a distributor counter summed over the
+// distributors of the namespace between start and end
+func distributorIncrease(np NamespaceProvider, metric string, start, end time.Time) (float64, error) {
+	ctx := traceContextFor(np)
+
+	c, err := newCollectionFor(ctx, np)
+	if err != nil {
+		return 0, err
+	}
+
+	window := int(end.Sub(start).Seconds())
+	if window < 60 {
+		window = 60
+	}
+	query := fmt.Sprintf(`sum(increase(%s{namespace="%s"}[%ds]))`, metric, np.Namespace(), window)
+
+	resp, err := c.collector.Query(ctx, query, end)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query %s: %w", metric, err)
+	}
+	if len(resp.Data.Result) == 0 || len(resp.Data.Result[0].Value) < 2 {
+		return 0, fmt.Errorf("no data returned for %s", metric)
+	}
+
+	valueStr, ok := resp.Data.Result[0].Value[1].(string)
+	if !ok {
+		return 0, fmt.Errorf("unexpected value type %T", resp.Data.Result[0].Value[1])
+	}
+	increase, err := strconv.ParseFloat(valueStr, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s value %q: %w", metric, valueStr, err)
+	}
+
+	return increase, nil
 }
 
 // K6MetricsExport is the JSON structure for k6 metrics export
@@ -187,6 +593,11 @@ type K6MetricsExport struct {
 	ExportedAt string `json:"exported_at"`
 	TestType   string `json:"test_type,omitempty"`
 
+	// ScriptsChecksum and FrameworkSHA identify the k6 scripts and framework
+	// commit that produced the metrics
+	ScriptsChecksum string `json:"scripts_checksum,omitempty"`
+	FrameworkSHA    string `json:"framework_sha,omitempty"`
+
 	// Query metrics
 	QueryRequestsTotal   float64         `json:"query_requests_total,omitempty"`
 	QueryFailuresTotal   float64         `json:"query_failures_total,omitempty"`
@@ -196,8 +607,74 @@ type K6MetricsExport struct {
 	// Ingestion metrics
 	IngestionBytesTotal  float64         `json:"ingestion_bytes_total,omitempty"`
 	IngestionTracesTotal float64         `json:"ingestion_traces_total,omitempty"`
+	IngestionSpansTotal  float64         `json:"ingestion_spans_total,omitempty"`
 	IngestionRateBPS     float64         `json:"ingestion_rate_bps,omitempty"`
 	IngestionDuration    *k6.MetricStats `json:"ingestion_duration,omitempty"`
+	IngestionBytesRate   float64         `json:"ingestion_bytes_rate,omitempty"`
+	DataSentTotal        float64         `json:"data_sent_total,omitempty"`
+	DataSentRate         float64         `json:"data_sent_rate,omitempty"`
+	IterationsTotal      float64         `json:"iterations_total,omitempty"`
+	IterationsRate       float64         `json:"iterations_rate,omitempty"`
+	DroppedIterations    float64         `json:"dropped_iterations,omitempty"`
+
+	// Jaeger query API metrics
+	JaegerRequestsTotal   float64                   `json:"jaeger_requests_total,omitempty"`
+	JaegerFailuresTotal   float64                   `json:"jaeger_failures_total,omitempty"`
+	JaegerRequestDuration map[string]k6.MetricStats `json:"jaeger_request_duration_seconds,omitempty"`
+
+	// Ingestion completeness, set when the ingestion was verified
+	Completeness *CompletenessExport `json:"completeness,omitempty"`
+
+	// Achieved vs requested ingestion rate, set when the rate was checked
+	RateCheck *RateCheckExport `json:"rate_check,omitempty"`
+
+	// Achieved vs requested query rate, set when the rate was checked
+	QueryRateCheck *QueryRateCheckExport `json:"query_rate_check,omitempty"`
+
+	// Completeness of the traces returned by queries, set when they were verified
+	QueryCorrectness *QueryCorrectnessExport `json:"query_correctness,omitempty"`
+}
+
+// RateCheckExport is the JSON structure for an ingestion rate check
+type RateCheckExport struct {
+	TargetMBPerSecond   float64 `json:"target_mb_per_second"`
+	SentMBPerSecond     float64 `json:"sent_mb_per_second"`
+	WireMBPerSecond     float64 `json:"wire_mb_per_second,omitempty"`
+	ReceivedMBPerSecond float64 `json:"received_mb_per_second,omitempty"`
+	ReceivedError       string  `json:"received_error,omitempty"`
+	AchievedPercent     float64 `json:"achieved_percent"`
+	UnderDelivered      bool    `json:"under_delivered"`
+}
+
+// QueryRateCheckExport is the JSON structure for a query rate check
+type QueryRateCheckExport struct {
+	TargetQueriesPerSecond   float64 `json:"target_queries_per_second"`
+	AchievedQueriesPerSecond float64 `json:"achieved_queries_per_second"`
+	DroppedQueries           float64 `json:"dropped_queries,omitempty"`
+	AchievedPercent          float64 `json:"achieved_percent"`
+	UnderDelivered           bool    `json:"under_delivered"`
+}
+
+// QueryCorrectnessExport is the JSON structure for a query correctness report
+type QueryCorrectnessExport struct {
+	TracesSampled  int     `json:"traces_sampled"`
+	TracesChecked  int     `json:"traces_checked"`
+	TracesFound    int     `json:"traces_found"`
+	TracesComplete int     `json:"traces_complete"`
+	MissingSpans   int     `json:"missing_spans,omitempty"`
+	Score          float64 `json:"score"`
+	LookupError    string  `json:"lookup_error,omitempty"`
+}
+
+// CompletenessExport is the JSON structure for an ingestion completeness report
+type CompletenessExport struct {
+	SentSpans        float64 `json:"sent_spans"`
+	ReceivedSpans    float64 `json:"received_spans"`
+	SpanLossPercent  float64 `json:"span_loss_percent"`
+	TracesSampled    int     `json:"traces_sampled"`
+	TracesFound      int     `json:"traces_found"`
+	TraceLossPercent float64 `json:"trace_loss_percent"`
+	TraceLookupError string  `json:"trace_lookup_error,omitempty"`
 }
 
 // ExportK6Metrics exports k6 metrics to a JSON file
@@ -205,12 +682,72 @@ func ExportK6Metrics(metrics *k6.K6Metrics, outputPath string, testType string)
 	if metrics == nil {
 		return nil // Nothing to export
 	}
+	return writeK6MetricsExport(newK6MetricsExport(metrics, testType), outputPath)
+}
 
-	// Create output directory if needed
-	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
-		return fmt.Errorf("failed to create output directory: %w", err)
+// ExportK6Result exports the k6 metrics of a result to a JSON file, including its
+// ingestion completeness, rate and query correctness reports when they were taken
+func ExportK6Result(result *k6.Result, outputPath string, testType string) error {
+	if result == nil || result.Metrics == nil {
+		return nil // Nothing to export
 	}
 
+	export := newK6MetricsExport(result.Metrics, testType)
+	export.ScriptsChecksum = result.ScriptsChecksum
+	export.FrameworkSHA = result.FrameworkSHA
+	if c := result.Completeness; c != nil {
+		export.Completeness = &CompletenessExport{
+			SentSpans:        c.SentSpans,
+			ReceivedSpans:    c.ReceivedSpans,
+			SpanLossPercent:  c.SpanLossPercent,
+			TracesSampled:    c.TracesSampled,
+			TracesFound:      c.TracesFound,
+			TraceLossPercent: c.TraceLossPercent,
+		}
+		if c.TraceLookupError != nil {
+			export.Completeness.TraceLookupError = c.TraceLookupError.Error()
+		}
+	}
+	if r := result.RateCheck; r != nil {
+		export.RateCheck = &RateCheckExport{
+			TargetMBPerSecond:   r.TargetMBPerSecond,
+			SentMBPerSecond:     r.SentMBPerSecond,
+			WireMBPerSecond:     r.WireMBPerSecond,
+			ReceivedMBPerSecond: r.ReceivedMBPerSecond,
+			AchievedPercent:     r.AchievedPercent,
+			UnderDelivered:      r.UnderDelivered,
+		}
+		if r.ReceivedError != nil {
+			export.RateCheck.ReceivedError = r.ReceivedError.Error()
+		}
+	}
+	if q := result.QueryRateCheck; q != nil {
+		export.QueryRateCheck = &QueryRateCheckExport{
+			TargetQueriesPerSecond:   q.TargetQueriesPerSecond,
+			AchievedQueriesPerSecond: q.AchievedQueriesPerSecond,
+			DroppedQueries:           q.DroppedQueries,
+			AchievedPercent:          q.AchievedPercent,
+			UnderDelivered:           q.UnderDelivered,
+		}
+	}
+	if q := result.QueryCorrectness; q != nil {
+		export.QueryCorrectness = &QueryCorrectnessExport{
+			TracesSampled:  q.TracesSampled,
+			TracesChecked:  q.TracesChecked,
+			TracesFound:    q.TracesFound,
+			TracesComplete: q.TracesComplete,
+			MissingSpans:   q.MissingSpans,
+			Score:          q.Score,
+		}
+		if q.LookupError != nil {
+			export.QueryCorrectness.LookupError = q.LookupError.Error()
+		}
+	}
+	return writeK6MetricsExport(export, outputPath)
+}
+
+// newK6MetricsExport converts k6 metrics to their JSON export structure
+func newK6MetricsExport(metrics *k6.K6Metrics, testType string) K6MetricsExport {
 	export := K6MetricsExport{
 		ExportedAt:           time.Now().UTC().Format(time.RFC3339),
 		TestType:             testType,
@@ -218,7 +755,18 @@ func ExportK6Metrics(metrics *k6.K6Metrics, outputPath string, testType string)
 		QueryFailuresTotal:   metrics.QueryFailuresTotal,
 		IngestionBytesTotal:  metrics.IngestionBytesTotal,
 		IngestionTracesTotal: metrics.IngestionTracesTotal,
+		IngestionSpansTotal:  metrics.IngestionSpansTotal,
 		IngestionRateBPS:     metrics.IngestionRateBPS,
+		IngestionBytesRate:   metrics.IngestionBytesRate,
+		DataSentTotal:        metrics.DataSentTotal,
+		DataSentRate:         metrics.DataSentRate,
+		IterationsTotal:      metrics.IterationsTotal,
+		IterationsRate:       metrics.IterationsRate,
+		DroppedIterations:    metrics.DroppedIterations,
+
+		JaegerRequestsTotal:   metrics.JaegerRequestsTotal,
+		JaegerFailuresTotal:   metrics.JaegerFailuresTotal,
+		JaegerRequestDuration: metrics.JaegerRequestDuration,
 	}
 
 	// Only include non-empty stats
@@ -232,6 +780,16 @@ func ExportK6Metrics(metrics *k6.K6Metrics, outputPath string, testType string)
 		export.IngestionDuration = &metrics.IngestionDuration
 	}
 
+	return export
+}
+
+// writeK6MetricsExport writes a k6 metrics export to a JSON file
+func writeK6MetricsExport(export K6MetricsExport, outputPath string) error {
+	// Create output directory if needed
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
 	file, err := os.Create(outputPath)
 	if err != nil {
 		return fmt.Errorf("failed to create output file: %w", err)
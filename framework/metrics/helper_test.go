@@ -0,0 +1,154 @@
+package metrics
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/k6"
+)
+
+func TestExportK6Result_IncludesCompleteness(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "k6-ingestion-metrics.json")
+	result := &k6.Result{
+		Metrics: &k6.K6Metrics{IngestionSpansTotal: 1000},
+		Completeness: &k6.IngestionCompleteness{
+			SentSpans:        1000,
+			ReceivedSpans:    950,
+			SpanLossPercent:  5,
+			TraceLookupError: errors.New("search returned no traces"),
+		},
+	}
+
+	if err := ExportK6Result(result, outputPath, "ingestion"); err != nil {
+		t.Fatalf("ExportK6Result() error = %v", err)
+	}
+
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("failed to read export: %v", err)
+	}
+	var export K6MetricsExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		t.Fatalf("failed to parse export: %v", err)
+	}
+	if export.Completeness == nil {
+		t.Fatal("expected completeness in export")
+	}
+	if export.Completeness.ReceivedSpans != 950 || export.Completeness.SpanLossPercent != 5 {
+		t.Errorf("completeness = %+v, want 950 received spans and 5%% loss", export.Completeness)
+	}
+	if export.Completeness.TraceLookupError != "search returned no traces" {
+		t.Errorf("trace lookup error = %q", export.Completeness.TraceLookupError)
+	}
+}
+
+func TestExportK6Result_ScriptVersion(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "k6-query-metrics.json")
+	result := &k6.Result{
+		Metrics:         &k6.K6Metrics{QueryRequestsTotal: 10},
+		ScriptsChecksum: "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+		FrameworkSHA:    "3a70b55e1c2d4f6a8b9c0d1e2f3a4b5c6d7e8f90",
+	}
+
+	if err := ExportK6Result(result, outputPath, "query"); err != nil {
+		t.Fatalf("ExportK6Result() error = %v", err)
+	}
+
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("failed to read export: %v", err)
+	}
+	var export K6MetricsExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		t.Fatalf("failed to parse export: %v", err)
+	}
+	if export.ScriptsChecksum != result.ScriptsChecksum || export.FrameworkSHA != result.FrameworkSHA {
+		t.Errorf("expected the script version in the export, got %q and %q", export.ScriptsChecksum, export.FrameworkSHA)
+	}
+}
+
+func TestExportK6Result_WithoutCompleteness(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "k6-query-metrics.json")
+	result := &k6.Result{Metrics: &k6.K6Metrics{QueryRequestsTotal: 10}}
+
+	if err := ExportK6Result(result, outputPath, "query"); err != nil {
+		t.Fatalf("ExportK6Result() error = %v", err)
+	}
+
+	var raw map[string]any
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("failed to read export: %v", err)
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("failed to parse export: %v", err)
+	}
+	if _, ok := raw["completeness"]; ok {
+		t.Error("expected no completeness section without a report")
+	}
+}
+
+func TestExportK6Result_QueryCorrectness(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "k6-query-metrics.json")
+	result := &k6.Result{
+		Metrics: &k6.K6Metrics{QueryRequestsTotal: 10},
+		QueryCorrectness: &k6.QueryCorrectness{
+			TracesSampled:  40,
+			TracesChecked:  4,
+			TracesFound:    4,
+			TracesComplete: 3,
+			MissingSpans:   2,
+			Score:          75,
+		},
+	}
+
+	if err := ExportK6Result(result, outputPath, "query"); err != nil {
+		t.Fatalf("ExportK6Result() error = %v", err)
+	}
+
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("failed to read export: %v", err)
+	}
+	var export K6MetricsExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		t.Fatalf("failed to parse export: %v", err)
+	}
+	if q := export.QueryCorrectness; q == nil || q.Score != 75 || q.TracesChecked != 4 || q.MissingSpans != 2 {
+		t.Errorf("query correctness = %+v, want a 75%% score over 4 traces", export.QueryCorrectness)
+	}
+}
+
+func TestExportK6Result_JaegerMetrics(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "k6-jaeger-metrics.json")
+	result := &k6.Result{Metrics: &k6.K6Metrics{
+		JaegerRequestsTotal: 120,
+		JaegerFailuresTotal: 2,
+		JaegerRequestDuration: map[string]k6.MetricStats{
+			"find_traces": {Avg: 0.4, P99: 1.5},
+			"services":    {Avg: 0.01, P99: 0.05},
+		},
+	}}
+
+	if err := ExportK6Result(result, outputPath, "jaeger"); err != nil {
+		t.Fatalf("ExportK6Result() error = %v", err)
+	}
+
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("failed to read export: %v", err)
+	}
+	var export K6MetricsExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		t.Fatalf("failed to parse export: %v", err)
+	}
+	if export.JaegerRequestsTotal != 120 || export.JaegerFailuresTotal != 2 {
+		t.Errorf("requests = %v, failures = %v, want 120 and 2", export.JaegerRequestsTotal, export.JaegerFailuresTotal)
+	}
+	if got := export.JaegerRequestDuration["find_traces"].P99; got != 1.5 {
+		t.Errorf("find_traces P99 = %v, want 1.5", got)
+	}
+}
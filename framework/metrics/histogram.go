@@ -0,0 +1,177 @@
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// QuantileLabel is the label identifying the quantile of series estimated from native histograms
+const QuantileLabel = "quantile"
+
+// HistogramQuantiles are the quantiles estimated from native histogram results
+var HistogramQuantiles = []float64{0.5, 0.9, 0.99}
+
+// HistogramSample is a native histogram sample of a query result,
+// encoded by the Prometheus API as [<unix time>, <histogram>]
+type HistogramSample struct {
+	Timestamp time.Time
+	Histogram NativeHistogram
+}
+
+// NativeHistogram is a native (exponential) histogram as returned by the Prometheus API
+type NativeHistogram struct {
+	Count   float64
+	Sum     float64
+	Buckets []HistogramBucket
+}
+
+// HistogramBucket is a populated bucket of a native histogram,
+// encoded as [<boundary rule>, <lower>, <upper>, <count>]
+type HistogramBucket struct {
+	Lower float64
+	Upper float64
+	Count float64
+}
+
+// UnmarshalJSON decodes a [<unix time>, <histogram>] pair
+func (s *HistogramSample) UnmarshalJSON(data []byte) error {
+	var pair []json.RawMessage
+	if err := json.Unmarshal(data, &pair); err != nil {
+		return err
+	}
+	if len(pair) != 2 {
+		return fmt.Errorf("invalid histogram sample: expected 2 elements, got %d", len(pair))
+	}
+
+	var timestamp float64
+	if err := json.Unmarshal(pair[0], &timestamp); err != nil {
+		return fmt.Errorf("invalid histogram timestamp: %w", err)
+	}
+	if err := json.Unmarshal(pair[1], &s.Histogram); err != nil {
+		return err
+	}
+	s.Timestamp = time.Unix(int64(timestamp), 0)
+	return nil
+}
+
+// UnmarshalJSON decodes a histogram whose count and sum are strings
+func (h *NativeHistogram) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Count   string            `json:"count"`
+		Sum     string            `json:"sum"`
+		Buckets []HistogramBucket `json:"buckets"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	count, err := strconv.ParseFloat(raw.Count, 64)
+	if err != nil {
+		return fmt.Errorf("invalid histogram count %q: %w", raw.Count, err)
+	}
+	sum, err := strconv.ParseFloat(raw.Sum, 64)
+	if err != nil {
+		return fmt.Errorf("invalid histogram sum %q: %w", raw.Sum, err)
+	}
+
+	h.Count = count
+	h.Sum = sum
+	h.Buckets = raw.Buckets
+	return nil
+}
+
+// UnmarshalJSON decodes a [<boundary rule>, <lower>, <upper>, <count>] bucket.
+// The boundary rule only matters for values on a boundary and is ignored.
+func (b *HistogramBucket) UnmarshalJSON(data []byte) error {
+	var fields []json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	if len(fields) != 4 {
+		return fmt.Errorf("invalid histogram bucket: expected 4 elements, got %d", len(fields))
+	}
+
+	values := make([]float64, 3)
+	for i, field := range fields[1:] {
+		var s string
+		if err := json.Unmarshal(field, &s); err != nil {
+			return fmt.Errorf("invalid histogram bucket: %w", err)
+		}
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return fmt.Errorf("invalid histogram bucket value %q: %w", s, err)
+		}
+		values[i] = v
+	}
+
+	b.Lower, b.Upper, b.Count = values[0], values[1], values[2]
+	return nil
+}
+
+// Quantile estimates the q-quantile (0 <= q <= 1) of the histogram like PromQL's
+// histogram_quantile: the bucket holding the quantile is found from the cumulative
+// counts, and the value is interpolated exponentially within buckets with positive
+// bounds (matching the exponential bucket layout) and linearly otherwise.
+// It returns false if the histogram is empty or q is out of range.
+func (h NativeHistogram) Quantile(q float64) (float64, bool) {
+	if q < 0 || q > 1 || h.Count <= 0 || len(h.Buckets) == 0 {
+		return 0, false
+	}
+
+	rank := q * h.Count
+	var cumulative float64
+	for i, b := range h.Buckets {
+		cumulative += b.Count
+		if cumulative < rank && i < len(h.Buckets)-1 {
+			continue
+		}
+		if b.Count <= 0 {
+			return b.Upper, true
+		}
+
+		fraction := (rank - (cumulative - b.Count)) / b.Count
+		fraction = math.Max(0, math.Min(1, fraction))
+		if b.Lower > 0 && b.Upper > 0 {
+			return b.Lower * math.Pow(b.Upper/b.Lower, fraction), true
+		}
+		return b.Lower + (b.Upper-b.Lower)*fraction, true
+	}
+	return 0, false
+}
+
+// histogramResults converts the native histogram samples of a query result into
+// one series per quantile of HistogramQuantiles, labeled with QuantileLabel
+func histogramResults(query MetricQuery, labels map[string]string, samples []HistogramSample) []MetricResult {
+	if len(samples) == 0 {
+		return nil
+	}
+
+	results := make([]MetricResult, 0, len(HistogramQuantiles))
+	for _, q := range HistogramQuantiles {
+		quantileLabels := make(map[string]string, len(labels)+1)
+		for k, v := range labels {
+			quantileLabels[k] = v
+		}
+		quantileLabels[QuantileLabel] = strconv.FormatFloat(q, 'f', -1, 64)
+
+		dataPoints := make([]DataPoint, 0, len(samples))
+		for _, s := range samples {
+			if v, ok := s.Histogram.Quantile(q); ok {
+				dataPoints = append(dataPoints, DataPoint{Timestamp: s.Timestamp, Value: v})
+			}
+		}
+
+		results = append(results, MetricResult{
+			QueryID:     query.ID,
+			MetricName:  query.Name,
+			Description: query.Description,
+			Category:    query.Category,
+			Labels:      quantileLabels,
+			DataPoints:  dataPoints,
+		})
+	}
+	return results
+}
@@ -0,0 +1,130 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const histogramRangeResponse = `{
+	"status": "success",
+	"data": {
+		"resultType": "matrix",
+		"result": [{
+			"metric": {"route": "search"},
+			"histograms": [
+				[1717243200, {"count": "10", "sum": "5", "buckets": [
+					[0, "0.5", "1", "5"],
+					[0, "1", "2", "4"],
+					[0, "2", "4", "1"]
+				]}],
+				[1717243260, {"count": "0", "sum": "0"}]
+			]
+		}]
+	}
+}`
+
+func TestHistogramSample_UnmarshalJSON(t *testing.T) {
+	var resp PrometheusResponse
+	if err := json.Unmarshal([]byte(histogramRangeResponse), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+
+	samples := resp.Data.Result[0].Histograms
+	if len(samples) != 2 {
+		t.Fatalf("expected 2 histogram samples, got %d", len(samples))
+	}
+	if !samples[0].Timestamp.Equal(time.Unix(1717243200, 0)) {
+		t.Errorf("unexpected timestamp %v", samples[0].Timestamp)
+	}
+
+	h := samples[0].Histogram
+	if h.Count != 10 || h.Sum != 5 || len(h.Buckets) != 3 {
+		t.Fatalf("unexpected histogram %+v", h)
+	}
+	if b := h.Buckets[1]; b.Lower != 1 || b.Upper != 2 || b.Count != 4 {
+		t.Errorf("unexpected bucket %+v", b)
+	}
+}
+
+func TestHistogramSample_UnmarshalJSONInvalid(t *testing.T) {
+	for _, data := range []string{
+		`[1717243200]`,
+		`[1717243200, {"count": "x", "sum": "0"}]`,
+		`[1717243200, {"count": "1", "sum": "1", "buckets": [[0, "1", "2"]]}]`,
+	} {
+		var s HistogramSample
+		if err := json.Unmarshal([]byte(data), &s); err == nil {
+			t.Errorf("expected error for %s", data)
+		}
+	}
+}
+
+func TestNativeHistogram_Quantile(t *testing.T) {
+	h := NativeHistogram{
+		Count: 10,
+		Buckets: []HistogramBucket{
+			{Lower: -1, Upper: 0, Count: 2},
+			{Lower: 1, Upper: 4, Count: 4},
+			{Lower: 4, Upper: 8, Count: 4},
+		},
+	}
+
+	tests := []struct {
+		q        float64
+		expected float64
+	}{
+		{0.1, -0.5},           // linear within the bucket below zero
+		{0.4, 2},              // exponential: 1 * 4^0.5
+		{1, 8},                // upper bound of the last bucket
+		{0.8, 4 * math.Sqrt2}, // exponential: 4 * 2^0.5
+	}
+	for _, tt := range tests {
+		got, ok := h.Quantile(tt.q)
+		if !ok || math.Abs(got-tt.expected) > 1e-9 {
+			t.Errorf("Quantile(%v) = %v, %v; want %v", tt.q, got, ok, tt.expected)
+		}
+	}
+
+	if _, ok := h.Quantile(1.5); ok {
+		t.Error("expected no quantile above 1")
+	}
+	if _, ok := (NativeHistogram{}).Quantile(0.5); ok {
+		t.Error("expected no quantile of an empty histogram")
+	}
+}
+
+func TestCollectMetric_NativeHistogram(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(histogramRangeResponse))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(context.Background(), &ClientConfig{Mode: ModeKubernetes, ThanosURL: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	query := MetricQuery{ID: "99", Name: "request_duration", Category: "query_performance"}
+	results, err := newCollection(client, client.config).collectMetric(context.Background(), query, time.Unix(1717243200, 0), time.Unix(1717243260, 0), time.Minute)
+	if err != nil {
+		t.Fatalf("collectMetric() error = %v", err)
+	}
+	if len(results) != len(HistogramQuantiles) {
+		t.Fatalf("expected %d quantile series, got %d", len(HistogramQuantiles), len(results))
+	}
+
+	median := results[0]
+	if median.Labels[QuantileLabel] != "0.5" || median.Labels["route"] != "search" {
+		t.Errorf("unexpected labels %v", median.Labels)
+	}
+	// The empty histogram sample has no quantile
+	if len(median.DataPoints) != 1 || median.DataPoints[0].Value != 1 {
+		t.Errorf("unexpected median data points %+v", median.DataPoints)
+	}
+}
@@ -0,0 +1,84 @@
+package metrics
+
+import (
+	"math"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/k6"
+)
+
+// Phases of a noisy-neighbor run
+const (
+	IsolationBaseline = "baseline"
+	IsolationBurst    = "burst"
+	IsolationRecovery = "recovery"
+)
+
+// TenantIsolation is the outcome of a noisy-neighbor run: the load k6 generated for
+// each tenant in each phase and the latencies it measured. Comparing the victim in
+// the burst with its baseline shows how well Tempo isolates tenants.
+type TenantIsolation struct {
+	Victim    string       `json:"victim"`
+	Aggressor string       `json:"aggressor"`
+	Loads     []TenantLoad `json:"loads"`
+}
+
+// TenantLoad is the load of a tenant during a phase, as measured by k6
+type TenantLoad struct {
+	Tenant string `json:"tenant"`
+	Phase  string `json:"phase"`
+	// IngestionP99 and QueryP99 are the p99 latencies in seconds of the OTLP pushes
+	// and searches; zero when the tenant generated no such load
+	IngestionP99 float64 `json:"ingestion_p99_seconds,omitempty"`
+	QueryP99     float64 `json:"query_p99_seconds,omitempty"`
+	// IngestionMBPerSecond and QueriesPerSecond are the rates k6 achieved
+	IngestionMBPerSecond float64 `json:"ingestion_mb_per_second,omitempty"`
+	QueriesPerSecond     float64 `json:"queries_per_second,omitempty"`
+	QueryFailures        float64 `json:"query_failures,omitempty"`
+}
+
+// RecordLoad records the k6 results of a tenant in a phase. The ingestion or query
+// result is nil when the tenant generated no such load.
+func (t *TenantIsolation) RecordLoad(tenant, phase string, ingestion, query *k6.Result) {
+	load := TenantLoad{Tenant: tenant, Phase: phase}
+	if ingestion != nil && ingestion.Metrics != nil {
+		load.IngestionP99 = ingestion.Metrics.IngestionDuration.P99
+		load.IngestionMBPerSecond = ingestion.Metrics.IngestionBytesRate / 1024 / 1024
+	}
+	if query != nil && query.Metrics != nil {
+		load.QueryP99 = query.Metrics.QueryDurationSeconds.P99
+		load.QueriesPerSecond = query.Metrics.IterationsRate
+		load.QueryFailures = query.Metrics.QueryFailuresTotal
+	}
+	t.Loads = append(t.Loads, load)
+}
+
+// Load returns the load of a tenant in a phase, or nil if none was recorded
+func (t *TenantIsolation) Load(tenant, phase string) *TenantLoad {
+	for i := range t.Loads {
+		if t.Loads[i].Tenant == tenant && t.Loads[i].Phase == phase {
+			return &t.Loads[i]
+		}
+	}
+	return nil
+}
+
+// IngestionImpact returns the change in percent of the ingestion p99 latency of the
+// victim in a phase from its baseline, NaN when either was not measured
+func (t *TenantIsolation) IngestionImpact(phase string) float64 {
+	return t.impact(phase, func(l *TenantLoad) float64 { return l.IngestionP99 })
+}
+
+// QueryImpact returns the change in percent of the query p99 latency of the victim
+// in a phase from its baseline, NaN when either was not measured
+func (t *TenantIsolation) QueryImpact(phase string) float64 {
+	return t.impact(phase, func(l *TenantLoad) float64 { return l.QueryP99 })
+}
+
+func (t *TenantIsolation) impact(phase string, latency func(*TenantLoad) float64) float64 {
+	baseline := t.Load(t.Victim, IsolationBaseline)
+	load := t.Load(t.Victim, phase)
+	if baseline == nil || load == nil || latency(baseline) == 0 || latency(load) == 0 {
+		return math.NaN()
+	}
+	return (latency(load) - latency(baseline)) / latency(baseline) * 100
+}
@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/k6"
+)
+
+func isolationResult(ingestionP99, queryP99 float64) (*k6.Result, *k6.Result) {
+	ingestion := &k6.Result{Metrics: &k6.K6Metrics{
+		IngestionDuration:  k6.MetricStats{P99: ingestionP99},
+		IngestionBytesRate: 2 * 1024 * 1024,
+	}}
+	query := &k6.Result{Metrics: &k6.K6Metrics{
+		QueryDurationSeconds: k6.MetricStats{P99: queryP99},
+		IterationsRate:       10,
+		QueryFailuresTotal:   3,
+	}}
+	return ingestion, query
+}
+
+func TestTenantIsolation_RecordLoad(t *testing.T) {
+	ti := &TenantIsolation{Victim: "tenant-1", Aggressor: "tenant-2"}
+	ingestion, query := isolationResult(0.05, 0.8)
+	ti.RecordLoad("tenant-1", IsolationBaseline, ingestion, query)
+	ti.RecordLoad("tenant-2", IsolationBurst, ingestion, nil)
+
+	victim := ti.Load("tenant-1", IsolationBaseline)
+	if victim == nil {
+		t.Fatal("expected the baseline of the victim")
+	}
+	if victim.IngestionP99 != 0.05 || victim.IngestionMBPerSecond != 2 {
+		t.Errorf("expected ingestion p99 0.05s at 2 MB/s, got %+v", victim)
+	}
+	if victim.QueryP99 != 0.8 || victim.QueriesPerSecond != 10 || victim.QueryFailures != 3 {
+		t.Errorf("expected query p99 0.8s at 10 queries/s with 3 failures, got %+v", victim)
+	}
+
+	aggressor := ti.Load("tenant-2", IsolationBurst)
+	if aggressor == nil || aggressor.QueryP99 != 0 || aggressor.QueriesPerSecond != 0 {
+		t.Errorf("expected an aggressor without queries, got %+v", aggressor)
+	}
+	if ti.Load("tenant-2", IsolationBaseline) != nil {
+		t.Error("expected no baseline of the aggressor")
+	}
+}
+
+func TestTenantIsolation_Impact(t *testing.T) {
+	ti := &TenantIsolation{Victim: "tenant-1", Aggressor: "tenant-2"}
+	ingestion, query := isolationResult(0.05, 0.8)
+	ti.RecordLoad("tenant-1", IsolationBaseline, ingestion, query)
+	ingestion, query = isolationResult(0.1, 1.0)
+	ti.RecordLoad("tenant-1", IsolationBurst, ingestion, query)
+	ingestion, _ = isolationResult(0.05, 0)
+	ti.RecordLoad("tenant-1", IsolationRecovery, ingestion, nil)
+
+	if got := ti.IngestionImpact(IsolationBurst); math.Abs(got-100) > 1e-9 {
+		t.Errorf("IngestionImpact(burst) = %v, want +100%%", got)
+	}
+	if got := ti.QueryImpact(IsolationBurst); math.Abs(got-25) > 1e-9 {
+		t.Errorf("QueryImpact(burst) = %v, want +25%%", got)
+	}
+
+	if got := ti.IngestionImpact(IsolationRecovery); got != 0 {
+		t.Errorf("IngestionImpact(recovery) = %v, want 0%%", got)
+	}
+	if got := ti.QueryImpact(IsolationRecovery); !math.IsNaN(got) {
+		t.Errorf("QueryImpact(recovery) = %v, want NaN without queries", got)
+	}
+	if got := ti.IngestionImpact("missing"); !math.IsNaN(got) {
+		t.Errorf("expected NaN for a phase without load, got %v", got)
+	}
+}
@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// LiveQuerier queries the current value of named metrics (see GetAllQueries) while
+// a test runs, e.g. for a progress view. It reuses one connection to the metrics
+// backend across queries.
+type LiveQuerier struct {
+	namespace string
+	c         *collection
+}
+
+// NewLiveQuerier returns a LiveQuerier for the namespace of np, querying the
+// backend CollectMetrics would
+func NewLiveQuerier(ctx context.Context, np NamespaceProvider) (*LiveQuerier, error) {
+	c, err := newCollectionFor(ctx, np)
+	if err != nil {
+		return nil, err
+	}
+	return &LiveQuerier{namespace: np.Namespace(), c: c}, nil
+}
+
+// Value returns the current value of the named metric summed over its series, or
+// an error wrapping ErrNoData when it has no samples yet
+func (q *LiveQuerier) Value(ctx context.Context, metric string) (float64, error) {
+	query, err := lookupQuery(q.namespace, metric)
+	if err != nil {
+		return 0, err
+	}
+	query.Query = applyRateWindow(query.Query, q.c.resolution.RateWindow)
+
+	// A zero time evaluates at the current time of the backend
+	results, err := q.c.collectInstantMetric(ctx, query, time.Time{})
+	if err != nil {
+		return 0, fmt.Errorf("failed to query %s: %w", metric, err)
+	}
+	var total float64
+	found := false
+	for _, r := range results {
+		for _, p := range r.DataPoints {
+			if math.IsNaN(p.Value) {
+				continue
+			}
+			total += p.Value
+			found = true
+		}
+	}
+	if !found {
+		return 0, fmt.Errorf("failed to query %s: %w", metric, ErrNoData)
+	}
+	return total, nil
+}
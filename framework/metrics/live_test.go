@@ -0,0 +1,51 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// seriesCollector answers instant queries with the given values, one series each
+type seriesCollector struct {
+	staticCollector
+	values []string
+	query  string
+}
+
+func (c *seriesCollector) Query(ctx context.Context, query string, evalTime time.Time) (*PrometheusResponse, error) {
+	c.query = query
+	resp := &PrometheusResponse{Status: "success"}
+	for _, v := range c.values {
+		resp.Data.Result = append(resp.Data.Result, PrometheusResult{Metric: map[string]string{}, Value: []interface{}{float64(1717243200), v}})
+	}
+	return resp, nil
+}
+
+func TestLiveQuerier_Value(t *testing.T) {
+	collector := &seriesCollector{values: []string{"1048576", "524288", "NaN"}}
+	q, err := NewLiveQuerier(context.Background(), collectorTestProvider{collector})
+	if err != nil {
+		t.Fatalf("NewLiveQuerier() error = %v", err)
+	}
+
+	value, err := q.Value(context.Background(), "bytes_received_rate")
+	if err != nil {
+		t.Fatalf("Value() error = %v", err)
+	}
+	if value != 1572864 {
+		t.Errorf("expected the sum of the series without NaN, got %v", value)
+	}
+	if collector.query == "" {
+		t.Error("expected the query of the metric to be run")
+	}
+
+	collector.values = []string{"NaN"}
+	if _, err := q.Value(context.Background(), "bytes_received_rate"); !errors.Is(err, ErrNoData) {
+		t.Errorf("expected ErrNoData without values, got %v", err)
+	}
+	if _, err := q.Value(context.Background(), "no_such_metric"); err == nil {
+		t.Error("expected an error for an unknown metric")
+	}
+}
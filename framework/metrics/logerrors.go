@@ -0,0 +1,41 @@
+package metrics
+
+// LogErrorSummary counts the error patterns found in the component logs of a run.
+// It is reported in the dashboard and the run metadata, so failures can be
+// diagnosed without grepping the log files.
+type LogErrorSummary struct {
+	// Total is the number of matching lines across all logs
+	Total int `json:"total"`
+	// Patterns are the names of the patterns searched for, in report order
+	Patterns []string `json:"patterns"`
+	// Logs are the logs with at least one match, most matches first
+	Logs []ComponentLogErrors `json:"logs,omitempty"`
+}
+
+// ComponentLogErrors holds the matches found in the log of one container
+type ComponentLogErrors struct {
+	Component string `json:"component"`
+	Pod       string `json:"pod"`
+	Container string `json:"container,omitempty"`
+	// File is the name of the collected log file
+	File  string          `json:"file"`
+	Total int             `json:"total"`
+	Found []LogErrorMatch `json:"found"`
+}
+
+// LogErrorMatch counts the lines of a log matching a pattern, with the first lines as samples
+type LogErrorMatch struct {
+	Pattern string   `json:"pattern"`
+	Count   int      `json:"count"`
+	Samples []string `json:"samples,omitempty"`
+}
+
+// Count returns the number of lines matching the named pattern
+func (c ComponentLogErrors) Count(pattern string) int {
+	for _, m := range c.Found {
+		if m.Pattern == pattern {
+			return m.Count
+		}
+	}
+	return 0
+}
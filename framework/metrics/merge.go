@@ -0,0 +1,123 @@
+package metrics
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// RunLabel is the label identifying the run of each series in a merged export
+const RunLabel = "run"
+
+// MergeInput is a metrics export (CSV or JSON) to merge
+type MergeInput struct {
+	// Path is the metrics file
+	Path string
+	// Run names the run in the RunLabel of its series. If empty, it is derived
+	// from the file name: results/small-a1b2c3-metrics.csv is run small-a1b2c3.
+	Run string
+}
+
+// Merge combines metrics exports into one dataset written to outputPath, in the
+// format of its extension (.json, otherwise CSV), and returns it. Each series is
+// labeled with the run of its input; series with the same query, labels, and run
+// are de-duplicated into one, keeping the first data point of each timestamp, so
+// overlapping exports of the same run can be merged.
+func Merge(inputs []MergeInput, outputPath string) ([]MetricResult, error) {
+	if len(inputs) == 0 {
+		return nil, fmt.Errorf("no metrics files to merge")
+	}
+
+	merger := newResultMerger()
+	for _, input := range inputs {
+		results, err := Load(input.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s: %w", input.Path, err)
+		}
+
+		run := input.Run
+		if run == "" {
+			run = runNameFromPath(input.Path)
+		}
+
+		for i := range results {
+			labels := make(map[string]string, len(results[i].Labels)+1)
+			for k, v := range results[i].Labels {
+				labels[k] = v
+			}
+			labels[RunLabel] = run
+			results[i].Labels = labels
+		}
+		merger.add(results)
+	}
+
+	merged := merger.results()
+	if err := NewExporter(outputPath, "").Export(merged); err != nil {
+		return nil, fmt.Errorf("failed to write merged metrics: %w", err)
+	}
+	return merged, nil
+}
+
+// runNameFromPath derives a run name from a metrics file name, dropping the
+// extension and the -metrics suffix written by the runner
+func runNameFromPath(path string) string {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return strings.TrimSuffix(name, "-metrics")
+}
+
+// resultMerger combines series with the same query and labels, keeping the first
+// data point of each timestamp
+type resultMerger struct {
+	merged []MetricResult
+	index  map[string]int
+	seen   map[int]map[time.Time]bool
+}
+
+func newResultMerger() *resultMerger {
+	return &resultMerger{
+		index: make(map[string]int),
+		seen:  make(map[int]map[time.Time]bool),
+	}
+}
+
+// add merges results into the series collected so far
+func (m *resultMerger) add(results []MetricResult) {
+	for _, r := range results {
+		key := r.QueryID + "|" + formatLabels(r.Labels)
+		idx, exists := m.index[key]
+		if !exists {
+			idx = len(m.merged)
+			m.index[key] = idx
+			m.seen[idx] = make(map[time.Time]bool)
+			m.merged = append(m.merged, MetricResult{
+				QueryID:     r.QueryID,
+				MetricName:  r.MetricName,
+				Description: r.Description,
+				Category:    r.Category,
+				Labels:      r.Labels,
+				Error:       r.Error,
+			})
+		}
+
+		for _, dp := range r.DataPoints {
+			if m.seen[idx][dp.Timestamp] {
+				continue
+			}
+			m.seen[idx][dp.Timestamp] = true
+			m.merged[idx].DataPoints = append(m.merged[idx].DataPoints, dp)
+		}
+		// Data from another export replaces a failed collection
+		if m.merged[idx].Error != nil && r.Error == nil {
+			m.merged[idx].Error = nil
+		}
+	}
+}
+
+// results returns the merged series with their data points in time order
+func (m *resultMerger) results() []MetricResult {
+	for i := range m.merged {
+		sortDataPoints(m.merged[i].DataPoints)
+	}
+	return m.merged
+}
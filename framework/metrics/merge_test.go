@@ -0,0 +1,108 @@
+package metrics
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMerge(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	small := filepath.Join(dir, "small-a1b2c3-metrics.csv")
+	if err := NewCSVExporter(small).Export(sampleResults()); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+
+	// A second export of the same run overlapping the first by one sample
+	overlap := []MetricResult{{
+		QueryID:    "accepted_spans_rate",
+		MetricName: "Accepted Spans",
+		Category:   "ingestion",
+		Labels:     map[string]string{},
+		DataPoints: []DataPoint{
+			{Timestamp: start, Value: 999},
+			{Timestamp: start.Add(time.Minute), Value: 1100},
+		},
+	}}
+	smallLate := filepath.Join(dir, "small-late.json")
+	if err := NewJSONExporter(smallLate).Export(overlap); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+
+	medium := filepath.Join(dir, "medium-metrics.json")
+	if err := NewJSONExporter(medium).Export(overlap); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+
+	output := filepath.Join(dir, "merged.csv")
+	merged, err := Merge([]MergeInput{
+		{Path: small},
+		{Path: smallLate, Run: "small-a1b2c3"},
+		{Path: medium},
+	}, output)
+	if err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+
+	// 3 series of small (the overlapping one de-duplicated) and 1 of medium
+	if len(merged) != 4 {
+		t.Fatalf("expected 4 series, got %d", len(merged))
+	}
+
+	var smallSpans, mediumSpans *MetricResult
+	for i, r := range merged {
+		if r.QueryID != "accepted_spans_rate" {
+			continue
+		}
+		switch r.Labels[RunLabel] {
+		case "small-a1b2c3":
+			smallSpans = &merged[i]
+		case "medium":
+			mediumSpans = &merged[i]
+		}
+	}
+	if smallSpans == nil || mediumSpans == nil {
+		t.Fatalf("expected accepted_spans_rate series of both runs, got %+v", merged)
+	}
+	if len(smallSpans.DataPoints) != 2 || smallSpans.DataPoints[0].Value != 1000 {
+		t.Errorf("expected the first export's sample to be kept, got %+v", smallSpans.DataPoints)
+	}
+	if len(mediumSpans.DataPoints) != 2 {
+		t.Errorf("expected 2 medium samples, got %d", len(mediumSpans.DataPoints))
+	}
+
+	reloaded, err := LoadFromCSV(output)
+	if err != nil {
+		t.Fatalf("failed to load merged file: %v", err)
+	}
+	if len(reloaded) != len(merged) {
+		t.Errorf("expected %d series in the merged file, got %d", len(merged), len(reloaded))
+	}
+}
+
+func TestMerge_MissingInput(t *testing.T) {
+	dir := t.TempDir()
+	_, err := Merge([]MergeInput{{Path: filepath.Join(dir, "missing.csv")}}, filepath.Join(dir, "merged.csv"))
+	if err == nil {
+		t.Fatal("expected an error for a missing input")
+	}
+
+	if _, err := Merge(nil, filepath.Join(dir, "merged.csv")); err == nil {
+		t.Error("expected an error without inputs")
+	}
+}
+
+func TestRunNameFromPath(t *testing.T) {
+	tests := map[string]string{
+		"results/small-a1b2c3-metrics.csv": "small-a1b2c3",
+		"medium-metrics.json":              "medium",
+		"baseline.csv":                     "baseline",
+	}
+	for path, want := range tests {
+		if got := runNameFromPath(path); got != want {
+			t.Errorf("runNameFromPath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
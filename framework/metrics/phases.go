@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"time"
+)
+
+// PhaseLabel is the label added to collected metrics to identify the test phase
+const PhaseLabel = "phase"
+
+// Phase is a named time window of a phased test
+type Phase struct {
+	Name  string
+	Start time.Time
+	End   time.Time
+}
+
+// PhaseProvider optionally provides the phases of a test. Collected series are
+// split at the phase boundaries and labeled with the phase name.
+type PhaseProvider interface {
+	Phases() []Phase
+}
+
+// SplitByPhase splits each series into one series per phase, labeled with PhaseLabel.
+// A data point belongs to the phase whose window [Start, End) contains it; points
+// outside every phase are dropped. Results with errors are kept unchanged.
+func SplitByPhase(results []MetricResult, phases []Phase) []MetricResult {
+	if len(phases) == 0 {
+		return results
+	}
+
+	var split []MetricResult
+	for _, r := range results {
+		if r.Error != nil {
+			split = append(split, r)
+			continue
+		}
+
+		for _, phase := range phases {
+			var points []DataPoint
+			for _, dp := range r.DataPoints {
+				if !dp.Timestamp.Before(phase.Start) && dp.Timestamp.Before(phase.End) {
+					points = append(points, dp)
+				}
+			}
+			if len(points) == 0 {
+				continue
+			}
+
+			labels := make(map[string]string, len(r.Labels)+1)
+			for k, v := range r.Labels {
+				labels[k] = v
+			}
+			labels[PhaseLabel] = phase.Name
+
+			phaseResult := r
+			phaseResult.Labels = labels
+			phaseResult.DataPoints = points
+			split = append(split, phaseResult)
+		}
+	}
+	return split
+}
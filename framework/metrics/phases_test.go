@@ -0,0 +1,55 @@
+package metrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSplitByPhase(t *testing.T) {
+	t0 := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	at := func(m int) time.Time { return t0.Add(time.Duration(m) * time.Minute) }
+
+	results := []MetricResult{
+		{
+			MetricName: "cpu_usage_total",
+			Labels:     map[string]string{"pod": "a"},
+			DataPoints: []DataPoint{{at(0), 1}, {at(1), 2}, {at(2), 3}, {at(3), 4}, {at(9), 5}},
+		},
+		{MetricName: "broken", Error: errors.New("query failed")},
+	}
+	phases := []Phase{
+		{Name: "ingest", Start: at(0), End: at(2)},
+		{Name: "idle", Start: at(2), End: at(4)},
+		{Name: "query", Start: at(4), End: at(6)},
+	}
+
+	split := SplitByPhase(results, phases)
+	if len(split) != 3 {
+		t.Fatalf("expected 3 results, got %d: %+v", len(split), split)
+	}
+
+	ingest, idle := split[0], split[1]
+	if ingest.Labels[PhaseLabel] != "ingest" || len(ingest.DataPoints) != 2 {
+		t.Errorf("unexpected ingest series %+v", ingest)
+	}
+	if idle.Labels[PhaseLabel] != "idle" || len(idle.DataPoints) != 2 || idle.DataPoints[0].Value != 3 {
+		t.Errorf("unexpected idle series %+v", idle)
+	}
+	if ingest.Labels["pod"] != "a" || idle.Labels["pod"] != "a" {
+		t.Error("expected original labels to be kept")
+	}
+	if split[2].Error == nil {
+		t.Error("expected the failed result to be kept")
+	}
+	if _, ok := results[0].Labels[PhaseLabel]; ok {
+		t.Error("expected the input labels to be left unchanged")
+	}
+}
+
+func TestSplitByPhase_NoPhases(t *testing.T) {
+	results := []MetricResult{{MetricName: "cpu_usage_total"}}
+	if split := SplitByPhase(results, nil); len(split) != 1 || split[0].Labels != nil {
+		t.Errorf("expected results unchanged, got %+v", split)
+	}
+}
@@ -0,0 +1,103 @@
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// PrerequisitesSuffix is the file name suffix of the prerequisites report in a results directory
+const PrerequisitesSuffix = "-prerequisites.json"
+
+// PrerequisitesReport is the state of the cluster-scoped prerequisites of a run: the
+// operators, their CRDs and the monitoring stack. It is kept with the results, since
+// an operator upgrade changes what a run measures.
+type PrerequisitesReport struct {
+	CheckedAt  time.Time        `json:"checked_at"`
+	AllMet     bool             `json:"all_met"`
+	Operators  []OperatorReport `json:"operators"`
+	Monitoring MonitoringReport `json:"monitoring"`
+}
+
+// OperatorReport is the installation of an operator. The OLM fields are empty for
+// operators installed without OLM, whose Deployment is reported instead.
+type OperatorReport struct {
+	Name string `json:"name"`
+	// Installed is set when all CRDs of the operator are established
+	Installed bool `json:"installed"`
+	// Namespace is where the operator runs
+	Namespace string `json:"namespace,omitempty"`
+	Version   string `json:"version,omitempty"`
+
+	// Package, Channel and CSV are the OLM Subscription of the operator
+	Package string `json:"package,omitempty"`
+	Channel string `json:"channel,omitempty"`
+	CSV     string `json:"csv,omitempty"`
+
+	// Deployment and Image identify an operator installed without OLM
+	Deployment string `json:"deployment,omitempty"`
+	Image      string `json:"image,omitempty"`
+
+	CRDs []CRDReport `json:"crds"`
+	// Error is why the installation could not be looked up
+	Error string `json:"error,omitempty"`
+}
+
+// CRDReport is the state of a CRD of an operator
+type CRDReport struct {
+	Name        string `json:"name"`
+	Found       bool   `json:"found"`
+	Established bool   `json:"established"`
+	// Versions are the served versions of the CRD
+	Versions   []string       `json:"versions,omitempty"`
+	Conditions []CRDCondition `json:"conditions,omitempty"`
+}
+
+// CRDCondition is a status condition of a CRD, e.g. Established
+type CRDCondition struct {
+	Type               string    `json:"type"`
+	Status             string    `json:"status"`
+	Reason             string    `json:"reason,omitempty"`
+	Message            string    `json:"message,omitempty"`
+	LastTransitionTime time.Time `json:"last_transition_time,omitempty"`
+}
+
+// MonitoringReport is the state of the user workload monitoring the metrics of a
+// run are collected from
+type MonitoringReport struct {
+	UserWorkloadEnabled bool `json:"user_workload_enabled"`
+	// UserWorkloadPrometheusReady is the number of ready user workload Prometheus pods
+	UserWorkloadPrometheusReady int `json:"user_workload_prometheus_ready"`
+	// Error is why the monitoring configuration could not be read
+	Error string `json:"error,omitempty"`
+}
+
+// WritePrerequisitesReport writes a prerequisites report as indented JSON
+func WritePrerequisitesReport(report *PrerequisitesReport, outputPath string) error {
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode prerequisites report: %w", err)
+	}
+	if err := os.WriteFile(outputPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write prerequisites report: %w", err)
+	}
+	return nil
+}
+
+// LoadPrerequisitesReport reads a report written by WritePrerequisitesReport
+func LoadPrerequisitesReport(path string) (*PrerequisitesReport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read prerequisites report: %w", err)
+	}
+	var report PrerequisitesReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse prerequisites report %s: %w", path, err)
+	}
+	return &report, nil
+}
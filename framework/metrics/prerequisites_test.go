@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWritePrerequisitesReport_RoundTrip(t *testing.T) {
+	checked := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	report := &PrerequisitesReport{
+		CheckedAt: checked,
+		AllMet:    true,
+		Operators: []OperatorReport{{
+			Name:      "Tempo Operator",
+			Installed: true,
+			Namespace: "openshift-tempo-operator",
+			Version:   "0.15.3",
+			Package:   "tempo-product",
+			Channel:   "stable",
+			CSV:       "tempo-operator.v0.15.3",
+			CRDs: []CRDReport{{
+				Name:        "tempostacks.tempo.grafana.com",
+				Found:       true,
+				Established: true,
+				Versions:    []string{"v1alpha1"},
+				Conditions:  []CRDCondition{{Type: "Established", Status: "True", LastTransitionTime: checked}},
+			}},
+		}},
+		Monitoring: MonitoringReport{UserWorkloadEnabled: true, UserWorkloadPrometheusReady: 2},
+	}
+
+	path := filepath.Join(t.TempDir(), "run"+PrerequisitesSuffix)
+	if err := WritePrerequisitesReport(report, path); err != nil {
+		t.Fatalf("WritePrerequisitesReport() error = %v", err)
+	}
+	loaded, err := LoadPrerequisitesReport(path)
+	if err != nil {
+		t.Fatalf("LoadPrerequisitesReport() error = %v", err)
+	}
+
+	if !loaded.CheckedAt.Equal(checked) || !loaded.AllMet {
+		t.Errorf("expected checked_at %s and all_met, got %+v", checked, loaded)
+	}
+	if len(loaded.Operators) != 1 || loaded.Operators[0].Channel != "stable" || loaded.Operators[0].Version != "0.15.3" {
+		t.Fatalf("expected the Tempo Operator on channel stable, got %+v", loaded.Operators)
+	}
+	crds := loaded.Operators[0].CRDs
+	if len(crds) != 1 || !crds[0].Established || len(crds[0].Conditions) != 1 || crds[0].Conditions[0].Type != "Established" {
+		t.Errorf("expected an established CRD, got %+v", crds)
+	}
+	if loaded.Monitoring.UserWorkloadPrometheusReady != 2 {
+		t.Errorf("expected 2 ready Prometheus pods, got %d", loaded.Monitoring.UserWorkloadPrometheusReady)
+	}
+}
+
+func TestLoadPrerequisitesReport_MissingFile(t *testing.T) {
+	if _, err := LoadPrerequisitesReport(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("expected an error for a missing report")
+	}
+}
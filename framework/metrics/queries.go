@@ -3,6 +3,8 @@ package metrics
 import (
 	"fmt"
 	"os"
+	"regexp"
+	"strings"
 )
 
 // MetricQuery represents a single PromQL query with metadata
@@ -15,6 +17,10 @@ type MetricQuery struct {
 	Type        string // "instant" or "range"
 }
 
+// HeartbeatMetric is the metric with a point at every query step where Prometheus
+// has scrape data, so missing steps reveal collection gaps
+const HeartbeatMetric = "collection_heartbeat"
+
 // GetAllQueries returns all metric queries defined in promql-queries.md
 func GetAllQueries(namespace string) []MetricQuery {
 	queries := []MetricQuery{
@@ -421,11 +427,457 @@ func GetAllQueries(namespace string) []MetricQuery {
 			Category:    "query_performance",
 			Type:        "range",
 		},
+
+		// Autoscaling Metrics (empty unless HPAs are configured)
+		{
+			ID:          "40",
+			Name:        "hpa_current_replicas",
+			Description: "Current replica count of each autoscaled component",
+			Query:       fmt.Sprintf(`sum(kube_horizontalpodautoscaler_status_current_replicas{namespace="%s"}) by (horizontalpodautoscaler)`, namespace),
+			Category:    "autoscaling",
+			Type:        "range",
+		},
+		{
+			ID:          "41",
+			Name:        "hpa_desired_replicas",
+			Description: "Replica count the HPA of each autoscaled component wants to scale to",
+			Query:       fmt.Sprintf(`sum(kube_horizontalpodautoscaler_status_desired_replicas{namespace="%s"}) by (horizontalpodautoscaler)`, namespace),
+			Category:    "autoscaling",
+			Type:        "range",
+		},
+
+		// Backpressure Metrics (k6 via Prometheus remote write, collector self-monitoring)
+		{
+			ID:          "42",
+			Name:        "k6_active_vus",
+			Description: "Busy k6 VUs; with constant arrival rate executors each one is a request waiting for a response",
+			Query:       fmt.Sprintf(`sum(k6_vus{namespace="%s"})`, namespace),
+			Category:    "backpressure",
+			Type:        "range",
+		},
+		{
+			ID:          "43",
+			Name:        "k6_dropped_iterations_rate",
+			Description: "Rate of k6 iterations dropped because no VU was free to send them",
+			Query:       fmt.Sprintf(`sum(rate(k6_dropped_iterations_total{namespace="%s"}[1m]))`, namespace),
+			Category:    "backpressure",
+			Type:        "range",
+		},
+		{
+			ID:          "44",
+			Name:        "collector_exporter_queue_size",
+			Description: "Batches waiting in the sending queue of the OTel Collector exporters",
+			Query:       fmt.Sprintf(`sum(otelcol_exporter_queue_size{namespace="%s"}) by (exporter)`, namespace),
+			Category:    "backpressure",
+			Type:        "range",
+		},
+		{
+			ID:          "45",
+			Name:        "collector_exporter_queue_capacity",
+			Description: "Capacity of the sending queue of the OTel Collector exporters",
+			Query:       fmt.Sprintf(`max(otelcol_exporter_queue_capacity{namespace="%s"}) by (exporter)`, namespace),
+			Category:    "backpressure",
+			Type:        "range",
+		},
+		{
+			ID:          "87",
+			Name:        "collector_kafka_consumer_lag",
+			Description: "Messages written to Kafka and not yet read by the consuming OTel Collector, when ingestion goes through Kafka",
+			Query:       fmt.Sprintf(`sum(otelcol_kafka_receiver_offset_lag{namespace="%s"}) by (topic)`, namespace),
+			Category:    "backpressure",
+			Type:        "range",
+		},
+
+		// Jaeger Query Metrics (jaeger test; k6 via Prometheus remote write, tempo-query container)
+		{
+			ID:          "46",
+			Name:        "jaeger_request_duration_p99",
+			Description: "P99 latency of the Jaeger HTTP API served by tempo-query, by endpoint",
+			Query:       fmt.Sprintf(`histogram_quantile(0.99, sum(rate(k6_jaeger_request_duration_seconds{namespace="%s"}[1m])) by (endpoint))`, namespace),
+			Category:    "jaeger_query",
+			Type:        "range",
+		},
+		{
+			ID:          "47",
+			Name:        "jaeger_requests_rate",
+			Description: "Rate of requests to the Jaeger HTTP API per second, by endpoint",
+			Query:       fmt.Sprintf(`sum(rate(k6_jaeger_requests_total{namespace="%s"}[1m])) by (endpoint)`, namespace),
+			Category:    "jaeger_query",
+			Type:        "range",
+		},
+		{
+			ID:          "48",
+			Name:        "tempo_query_memory_usage",
+			Description: "Memory working set bytes used by the tempo-query (Jaeger query) containers",
+			Query:       fmt.Sprintf(`sum(container_memory_working_set_bytes{namespace="%s", container="tempo-query"}) by (pod)`, namespace),
+			Category:    "jaeger_query",
+			Type:        "range",
+		},
+		{
+			ID:          "49",
+			Name:        "tempo_query_cpu_usage",
+			Description: "CPU cores used by the tempo-query (Jaeger query) containers",
+			Query:       fmt.Sprintf(`sum(rate(container_cpu_usage_seconds_total{namespace="%s", container="tempo-query"}[5m])) by (pod)`, namespace),
+			Category:    "jaeger_query",
+			Type:        "range",
+		},
+
+		// Go Runtime Metrics (GOGC/GOMEMLIMIT experiments; kube-state-metrics and the Go
+		// collector of every Tempo container, e.g. tempo and tempo-query)
+		{
+			ID:          "50",
+			Name:        "tempo_container_restarts",
+			Description: "Restarts of the Tempo container of each pod",
+			Query:       fmt.Sprintf(`max(kube_pod_container_status_restarts_total{namespace="%s", container="tempo"}) by (pod)`, namespace),
+			Category:    "go_runtime",
+			Type:        "range",
+		},
+		{
+			ID:          "51",
+			Name:        "tempo_oom_killed",
+			Description: "1 while the last termination of the Tempo container of a pod was an OOM kill",
+			Query:       fmt.Sprintf(`max(kube_pod_container_status_last_terminated_reason{namespace="%s", container="tempo", reason="OOMKilled"}) by (pod)`, namespace),
+			Category:    "go_runtime",
+			Type:        "range",
+		},
+		{
+			ID:          "52",
+			Name:        "go_heap_inuse",
+			Description: "Go heap bytes in use by each Tempo container",
+			Query:       fmt.Sprintf(`sum(go_memstats_heap_inuse_bytes{namespace="%s", container=~"tempo.*"}) by (pod, container)`, namespace),
+			Category:    "go_runtime",
+			Type:        "range",
+		},
+		{
+			ID:          "53",
+			Name:        "go_gc_pause_rate",
+			Description: "Time spent in GC stop-the-world pauses per second by each Tempo container",
+			Query:       fmt.Sprintf(`sum(rate(go_gc_duration_seconds_sum{namespace="%s", container=~"tempo.*"}[1m])) by (pod, container)`, namespace),
+			Category:    "go_runtime",
+			Type:        "range",
+		},
+		{
+			ID:          "60",
+			Name:        "go_next_gc_bytes",
+			Description: "Heap size at which the next GC cycle of each Tempo container starts, set by GOGC and capped by GOMEMLIMIT",
+			Query:       fmt.Sprintf(`sum(go_memstats_next_gc_bytes{namespace="%s", container=~"tempo.*"}) by (pod, container)`, namespace),
+			Category:    "go_runtime",
+			Type:        "range",
+		},
+		{
+			ID:          "61",
+			Name:        "go_gc_cycles_rate",
+			Description: "GC cycles per second of each Tempo container",
+			Query:       fmt.Sprintf(`sum(rate(go_gc_duration_seconds_count{namespace="%s", container=~"tempo.*"}[1m])) by (pod, container)`, namespace),
+			Category:    "go_runtime",
+			Type:        "range",
+		},
+		{
+			ID:          "62",
+			Name:        "go_gc_pause_max",
+			Description: "Longest recent GC stop-the-world pause of each Tempo container",
+			Query:       fmt.Sprintf(`max(go_gc_duration_seconds{namespace="%s", container=~"tempo.*", quantile="1"}) by (pod, container)`, namespace),
+			Category:    "go_runtime",
+			Type:        "range",
+		},
+		{
+			ID:          "63",
+			Name:        "go_goroutines",
+			Description: "Goroutines of each Tempo container",
+			Query:       fmt.Sprintf(`sum(go_goroutines{namespace="%s", container=~"tempo.*"}) by (pod, container)`, namespace),
+			Category:    "go_runtime",
+			Type:        "range",
+		},
+
+		// OTel Collector Pipeline Metrics (collector self-monitoring)
+		{
+			ID:          "54",
+			Name:        "collector_accepted_spans_rate",
+			Description: "Rate of spans accepted by the OTel Collector receivers per second",
+			Query:       fmt.Sprintf(`sum(rate(otelcol_receiver_accepted_spans_total{namespace="%s"}[1m])) by (receiver)`, namespace),
+			Category:    "collector",
+			Type:        "range",
+		},
+		{
+			ID:          "55",
+			Name:        "collector_refused_spans_rate",
+			Description: "Rate of spans refused by the OTel Collector receivers per second",
+			Query:       fmt.Sprintf(`sum(rate(otelcol_receiver_refused_spans_total{namespace="%s"}[1m])) by (receiver)`, namespace),
+			Category:    "collector",
+			Type:        "range",
+		},
+		{
+			ID:          "56",
+			Name:        "collector_sent_spans_rate",
+			Description: "Rate of spans the OTel Collector exporters sent to Tempo per second",
+			Query:       fmt.Sprintf(`sum(rate(otelcol_exporter_sent_spans_total{namespace="%s"}[1m])) by (exporter)`, namespace),
+			Category:    "collector",
+			Type:        "range",
+		},
+		{
+			ID:          "57",
+			Name:        "collector_send_failed_spans_rate",
+			Description: "Rate of spans the OTel Collector exporters failed to send to Tempo per second",
+			Query:       fmt.Sprintf(`sum(rate(otelcol_exporter_send_failed_spans_total{namespace="%s"}[1m])) by (exporter)`, namespace),
+			Category:    "collector",
+			Type:        "range",
+		},
+		{
+			ID:          "58",
+			Name:        "collector_enqueue_failed_spans_rate",
+			Description: "Rate of spans dropped because the sending queue of the OTel Collector exporters was full",
+			Query:       fmt.Sprintf(`sum(rate(otelcol_exporter_enqueue_failed_spans_total{namespace="%s"}[1m])) by (exporter)`, namespace),
+			Category:    "collector",
+			Type:        "range",
+		},
+		{
+			ID:          "59",
+			Name:        "collector_batch_send_size_avg",
+			Description: "Average number of spans per batch sent by the OTel Collector batch processor, when the pipeline has one",
+			Query:       fmt.Sprintf(`sum(rate(otelcol_processor_batch_batch_send_size_sum{namespace="%s"}[1m])) / sum(rate(otelcol_processor_batch_batch_send_size_count{namespace="%s"}[1m]))`, namespace, namespace),
+			Category:    "collector",
+			Type:        "range",
+		},
+
+		// Metrics-Generator Metrics (tempo.metricsGenerator; span metrics and service graphs)
+		{
+			ID:          "64",
+			Name:        "generator_spans_received_rate",
+			Description: "Rate of spans received by the metrics-generator per second",
+			Query:       fmt.Sprintf(`sum(rate(tempo_metrics_generator_spans_received_total{namespace="%s"}[1m]))`, namespace),
+			Category:    "metrics_generator",
+			Type:        "range",
+		},
+		{
+			ID:          "65",
+			Name:        "generator_spans_discarded_rate",
+			Description: "Rate of spans discarded by the metrics-generator per second, by reason",
+			Query:       fmt.Sprintf(`sum(rate(tempo_metrics_generator_spans_discarded_total{namespace="%s"}[1m])) by (reason)`, namespace),
+			Category:    "metrics_generator",
+			Type:        "range",
+		},
+		{
+			ID:          "66",
+			Name:        "generator_active_series",
+			Description: "Active series of the metrics-generator registry, by tenant",
+			Query:       fmt.Sprintf(`sum(tempo_metrics_generator_registry_active_series{namespace="%s"}) by (tenant)`, namespace),
+			Category:    "metrics_generator",
+			Type:        "range",
+		},
+		{
+			ID:          "67",
+			Name:        "generator_remote_write_samples_rate",
+			Description: "Rate of generated samples sent by the metrics-generator remote write per second",
+			Query:       fmt.Sprintf(`sum(rate(prometheus_remote_storage_samples_total{namespace="%s", container=~"tempo.*"}[1m]))`, namespace),
+			Category:    "metrics_generator",
+			Type:        "range",
+		},
+		{
+			ID:          "68",
+			Name:        "generator_remote_write_failed_samples_rate",
+			Description: "Rate of generated samples the metrics-generator remote write failed to send per second",
+			Query:       fmt.Sprintf(`sum(rate(prometheus_remote_storage_samples_failed_total{namespace="%s", container=~"tempo.*"}[1m]))`, namespace),
+			Category:    "metrics_generator",
+			Type:        "range",
+		},
+		{
+			ID:          "69",
+			Name:        "generator_push_failures_rate",
+			Description: "Rate of failed pushes of spans from the distributor to the metrics-generator per second",
+			Query:       fmt.Sprintf(`sum(rate(tempo_distributor_metrics_generator_pushes_failures_total{namespace="%s"}[1m]))`, namespace),
+			Category:    "metrics_generator",
+			Type:        "range",
+		},
+		// Collection heartbeat, not charted: the dashboard flags its missing steps
+		{
+			ID:          "70",
+			Name:        HeartbeatMetric,
+			Description: "1 at every query step where Prometheus has scrape data for the namespace",
+			Query:       fmt.Sprintf(`sum(count_over_time(up{namespace="%s"}[1m])) > bool 0`, namespace),
+			Category:    "collection",
+			Type:        "range",
+		},
+		{
+			ID:          "71",
+			Name:        "accepted_spans_rate_by_pod",
+			Description: "Rate of spans accepted by the receiver of each Tempo pod, showing how load spreads across monolithic replicas and distributors",
+			Query:       fmt.Sprintf(`sum(rate(tempo_receiver_accepted_spans{namespace="%s"}[1m])) by (pod)`, namespace),
+			Category:    "ingestion",
+			Type:        "range",
+		},
+		// Node Metrics (node_exporter of the nodes running Tempo pods)
+		{
+			ID:          "72",
+			Name:        "node_disk_written_bytes_rate",
+			Description: "Bytes written per second to the disks of each node running Tempo pods",
+			Query:       tempoNodeQuery(`sum(label_replace(rate(node_disk_written_bytes_total{device!~"dm-.*"}[1m]), "node", "$1", "instance", "(.*)")) by (node)`, namespace),
+			Category:    "nodes",
+			Type:        "range",
+		},
+		{
+			ID:          "73",
+			Name:        "node_disk_read_bytes_rate",
+			Description: "Bytes read per second from the disks of each node running Tempo pods",
+			Query:       tempoNodeQuery(`sum(label_replace(rate(node_disk_read_bytes_total{device!~"dm-.*"}[1m]), "node", "$1", "instance", "(.*)")) by (node)`, namespace),
+			Category:    "nodes",
+			Type:        "range",
+		},
+		{
+			ID:          "74",
+			Name:        "node_disk_io_utilization",
+			Description: "Fraction of time the busiest disk of each node running Tempo pods spent on I/O",
+			Query:       tempoNodeQuery(`max(label_replace(rate(node_disk_io_time_seconds_total{device!~"dm-.*"}[1m]), "node", "$1", "instance", "(.*)")) by (node)`, namespace),
+			Category:    "nodes",
+			Type:        "range",
+		},
+		{
+			ID:          "75",
+			Name:        "node_network_transmit_bytes_rate",
+			Description: "Bytes transmitted per second by the physical network interfaces of each node running Tempo pods",
+			Query:       tempoNodeQuery(`sum(label_replace(rate(node_network_transmit_bytes_total{device!~"lo|veth.*|br-.*|ovs-.*|ovn-.*|genev_sys_.*|vxlan_sys_.*|tun.*"}[1m]), "node", "$1", "instance", "(.*)")) by (node)`, namespace),
+			Category:    "nodes",
+			Type:        "range",
+		},
+		{
+			ID:          "76",
+			Name:        "node_network_receive_bytes_rate",
+			Description: "Bytes received per second by the physical network interfaces of each node running Tempo pods",
+			Query:       tempoNodeQuery(`sum(label_replace(rate(node_network_receive_bytes_total{device!~"lo|veth.*|br-.*|ovs-.*|ovn-.*|genev_sys_.*|vxlan_sys_.*|tun.*"}[1m]), "node", "$1", "instance", "(.*)")) by (node)`, namespace),
+			Category:    "nodes",
+			Type:        "range",
+		},
+		// Storage footprint (MinIO bucket usage; without a Prometheus scrape of
+		// MinIO, StorageSampler provides the series)
+		{
+			ID:          "77",
+			Name:        "storage_footprint_bytes",
+			Description: "Bytes stored in the trace storage, as reported by the MinIO bucket usage scan",
+			Query:       fmt.Sprintf(`sum(minio_bucket_usage_total_bytes{namespace="%s"})`, namespace),
+			Category:    "storage",
+			Type:        "range",
+		},
+		{
+			ID:          "78",
+			Name:        "storage_footprint_objects",
+			Description: "Objects stored in the trace storage, as reported by the MinIO bucket usage scan",
+			Query:       fmt.Sprintf(`sum(minio_bucket_usage_object_total{namespace="%s"})`, namespace),
+			Category:    "storage",
+			Type:        "range",
+		},
+		// Tenant Metrics (one series per tenant, to compare the tenants of a
+		// noisy-neighbor run)
+		{
+			ID:          "79",
+			Name:        "tenant_bytes_received_rate",
+			Description: "Rate of bytes received by the distributor per second, by tenant",
+			Query:       fmt.Sprintf(`sum(rate(tempo_distributor_bytes_received_total{namespace="%s"}[1m])) by (tenant)`, namespace),
+			Category:    "tenants",
+			Type:        "range",
+		},
+		{
+			ID:          "80",
+			Name:        "tenant_spans_received_rate",
+			Description: "Rate of spans received by the distributor per second, by tenant",
+			Query:       fmt.Sprintf(`sum(rate(tempo_distributor_spans_received_total{namespace="%s"}[1m])) by (tenant)`, namespace),
+			Category:    "tenants",
+			Type:        "range",
+		},
+		{
+			ID:          "81",
+			Name:        "tenant_discarded_spans_rate",
+			Description: "Rate of spans discarded per second, by tenant and reason, e.g. rate_limited when a tenant exceeds its ingestion limits",
+			Query:       fmt.Sprintf(`sum(rate(tempo_discarded_spans_total{namespace="%s"}[1m])) by (tenant, reason)`, namespace),
+			Category:    "tenants",
+			Type:        "range",
+		},
+		{
+			ID:          "82",
+			Name:        "tenant_queries_rate",
+			Description: "Rate of queries handled by the query frontend per second, by tenant",
+			Query:       fmt.Sprintf(`sum(rate(tempo_query_frontend_queries_total{namespace="%s"}[1m])) by (tenant)`, namespace),
+			Category:    "tenants",
+			Type:        "range",
+		},
+		{
+			ID:          "83",
+			Name:        "tenant_live_traces",
+			Description: "Number of live traces held by the ingesters, by tenant",
+			Query:       fmt.Sprintf(`sum(tempo_ingester_live_traces{namespace="%s"}) by (tenant)`, namespace),
+			Category:    "tenants",
+			Type:        "range",
+		},
+		// Compaction latency and errors (compactions take minutes, hence the
+		// longer rate window of the percentiles)
+		{
+			ID:          "84",
+			Name:        "compaction_duration_p50",
+			Description: "P50 duration of a compaction cycle",
+			Query:       fmt.Sprintf(`histogram_quantile(0.50, sum(rate(tempodb_compaction_duration_seconds_bucket{namespace="%s"}[5m])) by (le))`, namespace),
+			Category:    "compactor",
+			Type:        "range",
+		},
+		{
+			ID:          "85",
+			Name:        "compaction_duration_p99",
+			Description: "P99 duration of a compaction cycle",
+			Query:       fmt.Sprintf(`histogram_quantile(0.99, sum(rate(tempodb_compaction_duration_seconds_bucket{namespace="%s"}[5m])) by (le))`, namespace),
+			Category:    "compactor",
+			Type:        "range",
+		},
+		{
+			ID:          "86",
+			Name:        "compaction_errors_rate",
+			Description: "Rate of failed compactions per second",
+			Query:       fmt.Sprintf(`sum(rate(tempodb_compaction_errors_total{namespace="%s"}[1m]))`, namespace),
+			Category:    "compactor",
+			Type:        "range",
+		},
 	}
 
 	return queries
 }
 
+// tempoNodeQuery restricts a node-level query, aggregated by node, to the nodes
+// running the Tempo pods of a namespace. node_exporter sets instance to the node
+// name, which the query relabels as node to match kube_pod_info.
+func tempoNodeQuery(query, namespace string) string {
+	return fmt.Sprintf(`%s and on (node) max(kube_pod_info{namespace="%s", pod=~"tempo-.*"}) by (node)`, query, namespace)
+}
+
+// GetOperatorQueries returns resource usage queries for operator pods in the given namespaces.
+// The namespace is mapped to a component label (e.g. tempo-operator). Returns nil if no
+// namespaces are given.
+func GetOperatorQueries(namespaces []string) []MetricQuery {
+	if len(namespaces) == 0 {
+		return nil
+	}
+
+	quoted := make([]string, len(namespaces))
+	for i, ns := range namespaces {
+		quoted[i] = regexp.QuoteMeta(ns)
+	}
+	selector := strings.Join(quoted, "|")
+
+	return []MetricQuery{
+		{
+			ID:          "38",
+			Name:        "operator_memory_usage",
+			Description: "Memory working set bytes used by the Tempo and OpenTelemetry operator pods",
+			Query:       fmt.Sprintf(`label_replace(sum(container_memory_working_set_bytes{namespace=~"%s", container!=""}) by (namespace), "component", "$1-operator", "namespace", ".*(tempo|opentelemetry)-operator.*")`, selector),
+			Category:    "operators",
+			Type:        "range",
+		},
+		{
+			ID:          "39",
+			Name:        "operator_cpu_usage",
+			Description: "CPU cores used by the Tempo and OpenTelemetry operator pods",
+			Query:       fmt.Sprintf(`label_replace(sum(rate(container_cpu_usage_seconds_total{namespace=~"%s", container!=""}[5m])) by (namespace), "component", "$1-operator", "namespace", ".*(tempo|opentelemetry)-operator.*")`, selector),
+			Category:    "operators",
+			Type:        "range",
+		},
+	}
+}
+
 // GetSummaryQueries returns instant queries for summary metrics (P99 over full test duration)
 // These are executed once at the end of the test to get aggregate values
 func GetSummaryQueries(namespace string) []MetricQuery {
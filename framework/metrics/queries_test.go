@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestGetOperatorQueries_Disabled(t *testing.T) {
+	if queries := GetOperatorQueries(nil); queries != nil {
+		t.Errorf("expected no operator queries without namespaces, got %d", len(queries))
+	}
+}
+
+func TestGetOperatorQueries(t *testing.T) {
+	queries := GetOperatorQueries([]string{"openshift-tempo-operator", "opentelemetry-operator-system"})
+
+	if len(queries) != 2 {
+		t.Fatalf("expected 2 operator queries, got %d", len(queries))
+	}
+	for _, q := range queries {
+		if q.Category != "operators" {
+			t.Errorf("expected category operators, got %q", q.Category)
+		}
+		if !strings.Contains(q.Query, `namespace=~"openshift-tempo-operator|opentelemetry-operator-system"`) {
+			t.Errorf("expected namespace selector in query, got %s", q.Query)
+		}
+	}
+}
+
+func TestQueryIDs_NumericAndUnique(t *testing.T) {
+	queries := append(GetAllQueries("tempo-perf-test"), GetOperatorQueries([]string{"openshift-tempo-operator"})...)
+
+	seen := make(map[string]string)
+	for _, q := range queries {
+		if _, err := strconv.Atoi(q.ID); err != nil {
+			t.Errorf("query %s has non-numeric ID %q", q.Name, q.ID)
+		}
+		if other, ok := seen[q.ID]; ok {
+			t.Errorf("queries %s and %s share ID %q", other, q.Name, q.ID)
+		}
+		seen[q.ID] = q.Name
+	}
+}
+
+func TestGetAllQueries_NodeQueries(t *testing.T) {
+	var nodeQueries int
+	for _, q := range GetAllQueries("tempo-perf-test") {
+		if q.Category != "nodes" {
+			continue
+		}
+		nodeQueries++
+		if !strings.Contains(q.Query, `and on (node) max(kube_pod_info{namespace="tempo-perf-test", pod=~"tempo-.*"}) by (node)`) {
+			t.Errorf("query %s is not restricted to the nodes of the Tempo pods: %s", q.Name, q.Query)
+		}
+		if !strings.Contains(q.Query, `"node", "$1", "instance", "(.*)")) by (node) and`) {
+			t.Errorf("query %s is not aggregated by node: %s", q.Name, q.Query)
+		}
+	}
+	if nodeQueries != 5 {
+		t.Errorf("expected 5 node queries, got %d", nodeQueries)
+	}
+}
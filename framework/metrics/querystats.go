@@ -0,0 +1,156 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrQueryTimeout is returned for a query that did not complete within the query
+// timeout of the collection (config.Config.MetricsQueryTimeout)
+var ErrQueryTimeout = errors.New("query timed out")
+
+// Outcomes of a query recorded in QueryStats
+const (
+	QueryStatusOK      = "ok"
+	QueryStatusNoData  = "no_data"
+	QueryStatusFailed  = "failed"
+	QueryStatusTimeout = "timeout"
+	// QueryStatusResumed is a query loaded from the checkpoint of an earlier attempt
+	QueryStatusResumed = "resumed"
+)
+
+// QueryStats is the duration and outcome of a query of a metrics collection. They are
+// recorded in the export metadata to find slow and failing queries.
+type QueryStats struct {
+	QueryID string `json:"query_id"`
+	Name    string `json:"name"`
+	// Instant is set for the instant queries of the summary metrics
+	Instant bool `json:"instant,omitempty"`
+	// Duration is the time spent on the query, summed over the intervals of a
+	// rollup collection
+	Duration time.Duration `json:"duration"`
+	// Series is the number of series returned
+	Series int    `json:"series"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Failed reports whether the query failed or timed out, so its metrics are missing
+func (s QueryStats) Failed() bool {
+	return s.Status == QueryStatusFailed || s.Status == QueryStatusTimeout
+}
+
+// queryStatusRank orders the outcomes of a query from best to worst, to keep the
+// worst outcome of a query run for several intervals
+var queryStatusRank = map[string]int{
+	QueryStatusOK:      0,
+	QueryStatusResumed: 0,
+	QueryStatusNoData:  1,
+	QueryStatusFailed:  2,
+	QueryStatusTimeout: 3,
+}
+
+// queryStatsRecorder records the QueryStats of a collection. Its methods are safe for
+// concurrent use and do nothing on a nil recorder.
+type queryStatsRecorder struct {
+	mu    sync.Mutex
+	stats map[string]*QueryStats
+}
+
+// newQueryStatsRecorder creates an empty recorder
+func newQueryStatsRecorder() *queryStatsRecorder {
+	return &queryStatsRecorder{stats: map[string]*QueryStats{}}
+}
+
+// record records a query that ran for d and returned series series or err
+func (r *queryStatsRecorder) record(query MetricQuery, instant bool, d time.Duration, series int, err error) {
+	status := QueryStatusOK
+	switch {
+	case errors.Is(err, ErrNoData):
+		status = QueryStatusNoData
+	case errors.Is(err, ErrQueryTimeout):
+		status = QueryStatusTimeout
+	case err != nil:
+		status = QueryStatusFailed
+	}
+	r.add(QueryStats{QueryID: query.ID, Name: query.Name, Instant: instant, Duration: d, Series: series, Status: status}, err)
+}
+
+// recordResumed records a query loaded from a checkpoint
+func (r *queryStatsRecorder) recordResumed(query MetricQuery, series int) {
+	r.add(QueryStats{QueryID: query.ID, Name: query.Name, Series: series, Status: QueryStatusResumed}, nil)
+}
+
+// add merges s into the stats of its query
+func (r *queryStatsRecorder) add(s QueryStats, err error) {
+	if r == nil {
+		return
+	}
+	if err != nil && s.Failed() {
+		s.Error = err.Error()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := fmt.Sprintf("%s/%t", s.QueryID, s.Instant)
+	existing, ok := r.stats[key]
+	if !ok {
+		r.stats[key] = &s
+		return
+	}
+	existing.Duration += s.Duration
+	existing.Series = max(existing.Series, s.Series)
+	if queryStatusRank[s.Status] > queryStatusRank[existing.Status] {
+		existing.Status = s.Status
+		existing.Error = s.Error
+	}
+}
+
+// list returns the recorded stats, slowest first
+func (r *queryStatsRecorder) list() []QueryStats {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stats := make([]QueryStats, 0, len(r.stats))
+	for _, s := range r.stats {
+		stats = append(stats, *s)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Duration != stats[j].Duration {
+			return stats[i].Duration > stats[j].Duration
+		}
+		return stats[i].QueryID < stats[j].QueryID
+	})
+	return stats
+}
+
+// printQueryStats prints the slowest queries and the number of failed ones
+func printQueryStats(stats []QueryStats, slowest int) {
+	if len(stats) == 0 {
+		return
+	}
+	failed, timedOut := 0, 0
+	for _, s := range stats {
+		switch s.Status {
+		case QueryStatusFailed:
+			failed++
+		case QueryStatusTimeout:
+			timedOut++
+		}
+	}
+
+	fmt.Printf("⏱️  Slowest of %d queries", len(stats))
+	if failed+timedOut > 0 {
+		fmt.Printf(" (%d failed, %d timed out)", failed, timedOut)
+	}
+	fmt.Println(":")
+	for _, s := range stats[:min(slowest, len(stats))] {
+		fmt.Printf("   %8s  %-8s %s\n", s.Duration.Round(time.Millisecond), s.Status, s.Name)
+	}
+	fmt.Println()
+}
@@ -0,0 +1,146 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// blockingCollector answers queries only when their context ends
+type blockingCollector struct{}
+
+func (blockingCollector) QueryRange(ctx context.Context, query string, start, end time.Time, step time.Duration) (*PrometheusResponse, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (blockingCollector) Query(ctx context.Context, query string, evalTime time.Time) (*PrometheusResponse, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+// failingCollector fails one query and answers the others like staticCollector
+type failingCollector struct {
+	fail string
+}
+
+func (c failingCollector) QueryRange(ctx context.Context, query string, start, end time.Time, step time.Duration) (*PrometheusResponse, error) {
+	if query == c.fail {
+		return nil, errors.New("thanos unavailable")
+	}
+	return (&staticCollector{}).QueryRange(ctx, query, start, end, step)
+}
+
+func (c failingCollector) Query(ctx context.Context, query string, evalTime time.Time) (*PrometheusResponse, error) {
+	return (&staticCollector{}).Query(ctx, query, evalTime)
+}
+
+func TestCollectMetric_QueryTimeout(t *testing.T) {
+	c := newCollection(blockingCollector{}, &ClientConfig{Namespace: "ns", QueryTimeout: 10 * time.Millisecond})
+	c.stats = newQueryStatsRecorder()
+	query := MetricQuery{ID: "1", Name: "slow_query", Query: "up"}
+
+	_, err := c.collectMetric(context.Background(), query, time.Now().Add(-time.Minute), time.Now(), time.Minute)
+	if !errors.Is(err, ErrQueryTimeout) {
+		t.Fatalf("expected ErrQueryTimeout, got %v", err)
+	}
+
+	_, err = c.collectInstantMetric(context.Background(), query, time.Time{})
+	if !errors.Is(err, ErrQueryTimeout) {
+		t.Fatalf("expected ErrQueryTimeout for instant queries, got %v", err)
+	}
+}
+
+func TestCollectMetric_CancelledIsNotTimeout(t *testing.T) {
+	c := newCollection(blockingCollector{}, &ClientConfig{Namespace: "ns", QueryTimeout: time.Minute})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := c.collectMetric(ctx, MetricQuery{ID: "1", Query: "up"}, time.Now().Add(-time.Minute), time.Now(), time.Minute)
+	if err == nil || errors.Is(err, ErrQueryTimeout) {
+		t.Errorf("expected a cancellation error, got %v", err)
+	}
+}
+
+func TestQueryStatsRecorder(t *testing.T) {
+	r := newQueryStatsRecorder()
+	fast := MetricQuery{ID: "1", Name: "fast"}
+	slow := MetricQuery{ID: "2", Name: "slow"}
+
+	r.record(fast, false, time.Second, 3, nil)
+	r.record(slow, false, 20*time.Second, 1, nil)
+	// A later rollup interval of the same query times out
+	r.record(slow, false, 30*time.Second, 0, ErrQueryTimeout)
+	r.record(fast, true, 2*time.Second, 0, ErrNoData)
+
+	stats := r.list()
+	if len(stats) != 3 {
+		t.Fatalf("expected 3 stats, got %+v", stats)
+	}
+	if stats[0].QueryID != "2" || stats[0].Duration != 50*time.Second || stats[0].Series != 1 {
+		t.Errorf("expected the slow query first with the summed duration, got %+v", stats[0])
+	}
+	if stats[0].Status != QueryStatusTimeout || !stats[0].Failed() || stats[0].Error == "" {
+		t.Errorf("expected the worst outcome to be kept, got %+v", stats[0])
+	}
+	if !stats[1].Instant || stats[1].Status != QueryStatusNoData || stats[1].Failed() {
+		t.Errorf("expected the instant query without data, got %+v", stats[1])
+	}
+	if stats[2].Status != QueryStatusOK || stats[2].Series != 3 {
+		t.Errorf("expected the fast query to be ok, got %+v", stats[2])
+	}
+
+	var nilRecorder *queryStatsRecorder
+	nilRecorder.record(fast, false, time.Second, 1, nil)
+	if nilRecorder.list() != nil {
+		t.Error("expected a nil recorder to record nothing")
+	}
+}
+
+func TestCollectMetricsIncremental_PartialResults(t *testing.T) {
+	failed := GetAllQueries("ns")[0]
+	provider := collectorTestProvider{failingCollector{fail: failed.Query}}
+	testStart := time.Now().Add(-10 * time.Minute)
+
+	outputPath := filepath.Join(t.TempDir(), "run-metrics.csv")
+	if err := CollectMetricsIncremental(provider, testStart, outputPath); !errors.Is(err, ErrCollectionIncomplete) {
+		t.Fatalf("expected ErrCollectionIncomplete without partial results, got %v", err)
+	}
+
+	if err := CollectMetricsIncremental(provider, testStart, outputPath, WithPartialResults()); err != nil {
+		t.Fatalf("expected the partial results to be exported, got %v", err)
+	}
+	meta, err := LoadExportMetadata(outputPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if meta.FailedQueries != 1 {
+		t.Errorf("expected 1 failed query, got %d", meta.FailedQueries)
+	}
+	for _, s := range meta.Queries {
+		if s.QueryID == failed.ID && !s.Instant && s.Status != QueryStatusFailed {
+			t.Errorf("expected the failing query to be recorded as failed, got %+v", s)
+		}
+	}
+	results, err := Load(outputPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) == 0 {
+		t.Error("expected the metrics of the other queries")
+	}
+}
+
+func TestHasCollected(t *testing.T) {
+	failed := MetricResult{QueryID: "1", Error: errors.New("thanos unavailable")}
+	noData := MetricResult{QueryID: "2", Error: ErrNoData}
+
+	if hasCollected([]MetricResult{failed}) {
+		t.Error("expected a collection of failed queries to have collected nothing")
+	}
+	if !hasCollected([]MetricResult{failed, noData}) {
+		t.Error("expected a query without data to count as collected")
+	}
+}
@@ -0,0 +1,309 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/retry"
+
+	"github.com/golang/snappy"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Environment variables of the remote write credentials
+const (
+	EnvRemoteWriteToken  = "TEMPO_PERF_REMOTE_WRITE_TOKEN"
+	EnvRemoteWriteTenant = "TEMPO_PERF_REMOTE_WRITE_TENANT"
+)
+
+// RemoteWriteMetricPrefix is prepended to the names of the series pushed by
+// RemoteWriteExporter, keeping them apart from the series the backend scrapes itself
+const RemoteWriteMetricPrefix = "tempo_perf_"
+
+// DefaultRemoteWriteBatchSize is the maximum number of samples sent in one remote
+// write request
+const DefaultRemoteWriteBatchSize = 5000
+
+// defaultRemoteWriteTimeout is the timeout of one remote write request
+const defaultRemoteWriteTimeout = 30 * time.Second
+
+// RemoteWriteExporter pushes metric results to a Prometheus remote write endpoint,
+// e.g. a long-term Prometheus, Thanos Receive, Cortex or Mimir, so the results of
+// past runs can be graphed with regular Grafana dashboards. Each series is named
+// RemoteWriteMetricPrefix plus its metric name and keeps its labels, plus the
+// labels set with WithLabels, such as run_id and profile.
+type RemoteWriteExporter struct {
+	url        string
+	token      string
+	tenant     string
+	labels     map[string]string
+	batchSize  int
+	httpClient *http.Client
+}
+
+// NewRemoteWriteExporter creates a remote write exporter for the endpoint URL, e.g.
+// http://mimir:8080/api/v1/push. The bearer token and tenant are read from
+// TEMPO_PERF_REMOTE_WRITE_TOKEN and TEMPO_PERF_REMOTE_WRITE_TENANT.
+func NewRemoteWriteExporter(url string) *RemoteWriteExporter {
+	return &RemoteWriteExporter{
+		url:        url,
+		token:      os.Getenv(EnvRemoteWriteToken),
+		tenant:     os.Getenv(EnvRemoteWriteTenant),
+		batchSize:  DefaultRemoteWriteBatchSize,
+		httpClient: &http.Client{Timeout: defaultRemoteWriteTimeout},
+	}
+}
+
+// WithBearerToken sets the bearer token sent with each request
+func (e *RemoteWriteExporter) WithBearerToken(token string) *RemoteWriteExporter {
+	e.token = token
+	return e
+}
+
+// WithTenant sets the tenant sent as the X-Scope-OrgID header, for multi-tenant
+// backends
+func (e *RemoteWriteExporter) WithTenant(tenant string) *RemoteWriteExporter {
+	e.tenant = tenant
+	return e
+}
+
+// WithLabels sets labels added to every series, replacing labels of the same name
+func (e *RemoteWriteExporter) WithLabels(labels map[string]string) *RemoteWriteExporter {
+	e.labels = labels
+	return e
+}
+
+// WithBatchSize sets the maximum number of samples per request
+func (e *RemoteWriteExporter) WithBatchSize(samples int) *RemoteWriteExporter {
+	if samples > 0 {
+		e.batchSize = samples
+	}
+	return e
+}
+
+// Export pushes metric results to the remote write endpoint
+func (e *RemoteWriteExporter) Export(results []MetricResult) error {
+	return e.ExportContext(context.Background(), results)
+}
+
+// ExportContext pushes metric results to the remote write endpoint. Results with
+// errors and non-finite values are skipped. Requests rejected with a server error or
+// 429 are retried, other rejections fail the export.
+func (e *RemoteWriteExporter) ExportContext(ctx context.Context, results []MetricResult) error {
+	series := buildRemoteWriteSeries(results, e.labels)
+	if len(series) == 0 {
+		fmt.Println("⚠️  No data points to push via remote write")
+		return nil
+	}
+
+	samples := 0
+	for _, batch := range batchRemoteWriteSeries(series, e.batchSize) {
+		err := retry.Do(ctx, func(ctx context.Context) error {
+			return e.send(ctx, encodeWriteRequest(batch))
+		},
+			retry.WithName("remote write"),
+			retry.WithRetryIf(func(err error) bool {
+				var rwErr *remoteWriteError
+				return !errors.As(err, &rwErr) || rwErr.recoverable()
+			}),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to push metrics via remote write: %w", err)
+		}
+		for _, s := range batch {
+			samples += len(s.samples)
+		}
+	}
+
+	fmt.Printf("📤 Pushed %d series with %d samples via remote write\n", len(series), samples)
+	return nil
+}
+
+// send posts an encoded WriteRequest
+func (e *RemoteWriteExporter) send(ctx context.Context, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(snappy.Encode(nil, payload)))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	if e.token != "" {
+		req.Header.Set("Authorization", "Bearer "+e.token)
+	}
+	if e.tenant != "" {
+		req.Header.Set(TenantHeader, e.tenant)
+	}
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return &remoteWriteError{statusCode: resp.StatusCode, status: resp.Status, body: strings.TrimSpace(string(body))}
+	}
+	return nil
+}
+
+// remoteWriteError is a request rejected by the remote write endpoint
+type remoteWriteError struct {
+	statusCode int
+	status     string
+	body       string
+}
+
+func (e *remoteWriteError) Error() string {
+	return fmt.Sprintf("%s: %s", e.status, e.body)
+}
+
+// recoverable reports whether the request may succeed when sent again
+func (e *remoteWriteError) recoverable() bool {
+	return e.statusCode/100 == 5 || e.statusCode == http.StatusTooManyRequests
+}
+
+// remoteWriteLabel is a label of a remote write series
+type remoteWriteLabel struct {
+	name, value string
+}
+
+// remoteWriteSample is a sample of a remote write series, at a Unix time in milliseconds
+type remoteWriteSample struct {
+	value     float64
+	timestamp int64
+}
+
+// remoteWriteSeries is a series of a remote write request
+type remoteWriteSeries struct {
+	labels  []remoteWriteLabel
+	samples []remoteWriteSample
+}
+
+// buildRemoteWriteSeries converts metric results into remote write series with
+// sorted labels and samples in time order
+func buildRemoteWriteSeries(results []MetricResult, extraLabels map[string]string) []remoteWriteSeries {
+	var series []remoteWriteSeries
+	for _, result := range results {
+		if result.Error != nil {
+			continue
+		}
+
+		samples := make([]remoteWriteSample, 0, len(result.DataPoints))
+		for _, dp := range result.DataPoints {
+			if math.IsNaN(dp.Value) || math.IsInf(dp.Value, 0) {
+				continue
+			}
+			samples = append(samples, remoteWriteSample{value: dp.Value, timestamp: dp.Timestamp.UnixMilli()})
+		}
+		if len(samples) == 0 {
+			continue
+		}
+		sort.SliceStable(samples, func(i, j int) bool { return samples[i].timestamp < samples[j].timestamp })
+
+		labelValues := map[string]string{}
+		for name, value := range result.Labels {
+			labelValues[sanitizeMetricName(name)] = value
+		}
+		for name, value := range extraLabels {
+			labelValues[sanitizeMetricName(name)] = value
+		}
+		labelValues["__name__"] = RemoteWriteMetricPrefix + sanitizeMetricName(result.MetricName)
+
+		labels := make([]remoteWriteLabel, 0, len(labelValues))
+		for name, value := range labelValues {
+			if value != "" {
+				labels = append(labels, remoteWriteLabel{name: name, value: value})
+			}
+		}
+		sort.Slice(labels, func(i, j int) bool { return labels[i].name < labels[j].name })
+
+		series = append(series, remoteWriteSeries{labels: labels, samples: samples})
+	}
+	return series
+}
+
+// batchRemoteWriteSeries splits series into batches of at most size samples,
+// splitting series with more samples across batches
+func batchRemoteWriteSeries(series []remoteWriteSeries, size int) [][]remoteWriteSeries {
+	var batches [][]remoteWriteSeries
+	var batch []remoteWriteSeries
+	count := 0
+	for _, s := range series {
+		for len(s.samples) > 0 {
+			n := min(len(s.samples), size-count)
+			batch = append(batch, remoteWriteSeries{labels: s.labels, samples: s.samples[:n]})
+			s.samples = s.samples[n:]
+			count += n
+			if count == size {
+				batches = append(batches, batch)
+				batch, count = nil, 0
+			}
+		}
+	}
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+	return batches
+}
+
+// sanitizeMetricName replaces the characters not allowed in Prometheus metric and
+// label names with underscores
+func sanitizeMetricName(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_':
+			b.WriteRune(r)
+		case r >= '0' && r <= '9' && i > 0:
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+// encodeWriteRequest encodes series as a prometheus.WriteRequest protobuf message:
+//
+//	WriteRequest { repeated TimeSeries timeseries = 1; }
+//	TimeSeries   { repeated Label labels = 1; repeated Sample samples = 2; }
+//	Label        { string name = 1; string value = 2; }
+//	Sample       { double value = 1; int64 timestamp = 2; }
+func encodeWriteRequest(series []remoteWriteSeries) []byte {
+	var req []byte
+	for _, s := range series {
+		var ts []byte
+		for _, l := range s.labels {
+			var label []byte
+			label = protowire.AppendTag(label, 1, protowire.BytesType)
+			label = protowire.AppendString(label, l.name)
+			label = protowire.AppendTag(label, 2, protowire.BytesType)
+			label = protowire.AppendString(label, l.value)
+			ts = protowire.AppendTag(ts, 1, protowire.BytesType)
+			ts = protowire.AppendBytes(ts, label)
+		}
+		for _, smp := range s.samples {
+			var sample []byte
+			sample = protowire.AppendTag(sample, 1, protowire.Fixed64Type)
+			sample = protowire.AppendFixed64(sample, math.Float64bits(smp.value))
+			sample = protowire.AppendTag(sample, 2, protowire.VarintType)
+			sample = protowire.AppendVarint(sample, uint64(smp.timestamp))
+			ts = protowire.AppendTag(ts, 2, protowire.BytesType)
+			ts = protowire.AppendBytes(ts, sample)
+		}
+		req = protowire.AppendTag(req, 1, protowire.BytesType)
+		req = protowire.AppendBytes(req, ts)
+	}
+	return req
+}
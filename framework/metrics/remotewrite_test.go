@@ -0,0 +1,225 @@
+package metrics
+
+import (
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/snappy"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// remoteWriteServer records the series of the remote write requests it receives
+type remoteWriteServer struct {
+	mu       sync.Mutex
+	requests int
+	series   []remoteWriteSeries
+	headers  http.Header
+}
+
+func (s *remoteWriteServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	compressed, _ := io.ReadAll(r.Body)
+	payload, err := snappy.Decode(nil, compressed)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	series, err := decodeWriteRequest(payload)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+	s.series = append(s.series, series...)
+	s.headers = r.Header.Clone()
+}
+
+// decodeWriteRequest decodes the messages written by encodeWriteRequest
+func decodeWriteRequest(b []byte) ([]remoteWriteSeries, error) {
+	var series []remoteWriteSeries
+	err := decodeFields(b, func(_ protowire.Number, ts []byte) error {
+		var s remoteWriteSeries
+		err := decodeFields(ts, func(num protowire.Number, field []byte) error {
+			if num == 1 {
+				var l remoteWriteLabel
+				err := decodeFields(field, func(num protowire.Number, v []byte) error {
+					if num == 1 {
+						l.name = string(v)
+					} else {
+						l.value = string(v)
+					}
+					return nil
+				})
+				s.labels = append(s.labels, l)
+				return err
+			}
+			var smp remoteWriteSample
+			for len(field) > 0 {
+				num, typ, n := protowire.ConsumeTag(field)
+				field = field[n:]
+				switch {
+				case num == 1 && typ == protowire.Fixed64Type:
+					v, n := protowire.ConsumeFixed64(field)
+					smp.value = math.Float64frombits(v)
+					field = field[n:]
+				case num == 2 && typ == protowire.VarintType:
+					v, n := protowire.ConsumeVarint(field)
+					smp.timestamp = int64(v)
+					field = field[n:]
+				default:
+					return errors.New("unexpected sample field")
+				}
+			}
+			s.samples = append(s.samples, smp)
+			return nil
+		})
+		series = append(series, s)
+		return err
+	})
+	return series, err
+}
+
+// decodeFields calls fn with each length-delimited field of a message
+func decodeFields(b []byte, fn func(protowire.Number, []byte) error) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 || typ != protowire.BytesType {
+			return errors.New("unexpected field")
+		}
+		b = b[n:]
+		v, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return errors.New("truncated field")
+		}
+		b = b[n:]
+		if err := fn(num, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestRemoteWriteExporter_Export(t *testing.T) {
+	received := &remoteWriteServer{}
+	server := httptest.NewServer(received)
+	defer server.Close()
+
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	results := []MetricResult{
+		{
+			MetricName: "bytes_received_rate",
+			Labels:     map[string]string{"pod": "tempo-0", RunIDLabel: "old"},
+			DataPoints: []DataPoint{
+				{Timestamp: start.Add(time.Minute), Value: 2},
+				{Timestamp: start, Value: 1},
+				{Timestamp: start.Add(2 * time.Minute), Value: math.NaN()},
+			},
+		},
+		{MetricName: "failed_query", Error: errors.New("no data")},
+	}
+
+	err := NewRemoteWriteExporter(server.URL).
+		WithBearerToken("secret").
+		WithTenant("perf").
+		WithLabels(map[string]string{RunIDLabel: "abc123", ProfileLabel: "small"}).
+		Export(results)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := received.headers.Get("Authorization"); got != "Bearer secret" {
+		t.Errorf("expected bearer token, got %q", got)
+	}
+	if got := received.headers.Get(TenantHeader); got != "perf" {
+		t.Errorf("expected tenant header, got %q", got)
+	}
+	if got := received.headers.Get("Content-Encoding"); got != "snappy" {
+		t.Errorf("expected snappy encoding, got %q", got)
+	}
+	if len(received.series) != 1 {
+		t.Fatalf("expected 1 series, got %d", len(received.series))
+	}
+
+	var labels []string
+	for _, l := range received.series[0].labels {
+		labels = append(labels, l.name+"="+l.value)
+	}
+	expected := "__name__=tempo_perf_bytes_received_rate,pod=tempo-0,profile=small,run_id=abc123"
+	if got := strings.Join(labels, ","); got != expected {
+		t.Errorf("expected labels %s, got %s", expected, got)
+	}
+
+	samples := received.series[0].samples
+	if len(samples) != 2 {
+		t.Fatalf("expected 2 samples without the NaN one, got %d", len(samples))
+	}
+	if samples[0].value != 1 || samples[0].timestamp != start.UnixMilli() || samples[1].value != 2 {
+		t.Errorf("expected samples in time order, got %+v", samples)
+	}
+}
+
+func TestRemoteWriteExporter_Batches(t *testing.T) {
+	received := &remoteWriteServer{}
+	server := httptest.NewServer(received)
+	defer server.Close()
+
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	results := []MetricResult{
+		{MetricName: "a", DataPoints: []DataPoint{{Timestamp: start, Value: 1}, {Timestamp: start.Add(time.Minute), Value: 2}, {Timestamp: start.Add(2 * time.Minute), Value: 3}}},
+		{MetricName: "b", DataPoints: []DataPoint{{Timestamp: start, Value: 4}}},
+	}
+
+	if err := NewRemoteWriteExporter(server.URL).WithBatchSize(2).Export(results); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if received.requests != 2 {
+		t.Errorf("expected 2 requests of 2 samples, got %d", received.requests)
+	}
+	total := 0
+	for _, s := range received.series {
+		total += len(s.samples)
+	}
+	if total != 4 {
+		t.Errorf("expected 4 samples, got %d", total)
+	}
+}
+
+func TestRemoteWriteExporter_RejectedRequest(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		http.Error(w, "out of order sample", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	results := []MetricResult{{MetricName: "a", DataPoints: []DataPoint{{Timestamp: time.Now(), Value: 1}}}}
+	err := NewRemoteWriteExporter(server.URL).Export(results)
+	if err == nil || !strings.Contains(err.Error(), "out of order sample") {
+		t.Fatalf("expected the rejection in the error, got %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("expected a client error not to be retried, got %d requests", requests)
+	}
+}
+
+func TestSanitizeMetricName(t *testing.T) {
+	tests := map[string]string{
+		"bytes_received_rate": "bytes_received_rate",
+		"p99-latency.ms":      "p99_latency_ms",
+		"9lives":              "_lives",
+	}
+	for in, expected := range tests {
+		if got := sanitizeMetricName(in); got != expected {
+			t.Errorf("sanitizeMetricName(%q) = %q, expected %q", in, got, expected)
+		}
+	}
+}
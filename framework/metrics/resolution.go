@@ -0,0 +1,142 @@
+package metrics
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/config"
+)
+
+// Export sources recorded in ExportMetadata
+const (
+	SourcePrometheus    = "prometheus"
+	SourceMetricsServer = "metrics-server"
+	SourceScrape        = "scrape"
+	SourceStorage       = "storage-sampler"
+)
+
+// ErrResolutionMismatch is returned when metrics files collected with different
+// resolutions are compared
+var ErrResolutionMismatch = errors.New("metrics collected with different resolutions")
+
+// Resolution is the evaluation resolution of the range queries. Comparisons are only
+// fair between runs collected with the same resolution.
+type Resolution struct {
+	// Step is the range query step
+	Step time.Duration `json:"step"`
+	// RateWindow replaces the range of every range selector in the queries
+	// (e.g. [1m]). Zero keeps the ranges of the queries.
+	RateWindow time.Duration `json:"rate_window,omitempty"`
+}
+
+// String describes the resolution, e.g. "step 1m0s, rate window 2m0s"
+func (r Resolution) String() string {
+	if r.RateWindow == 0 {
+		return fmt.Sprintf("step %s, query rate windows", r.Step)
+	}
+	return fmt.Sprintf("step %s, rate window %s", r.Step, r.RateWindow)
+}
+
+// ResolutionFromConfig returns the resolution set in the framework configuration.
+// A nil cfg reads it from the environment.
+func ResolutionFromConfig(cfg *config.Config) Resolution {
+	if cfg == nil {
+		cfg = config.FromEnv()
+	}
+	r := Resolution{Step: cfg.MetricsQueryStep, RateWindow: cfg.MetricsRateWindow}
+	if r.Step <= 0 {
+		r.Step = config.DefaultMetricsQueryStep
+	}
+	return r
+}
+
+// rangeSelectorPattern matches the range of a range selector, e.g. [1m] or [1h30m],
+// but not subqueries ([5m:1m])
+var rangeSelectorPattern = regexp.MustCompile(`\[[0-9]+[a-z]+(?:[0-9]+[a-z]+)*\]`)
+
+// applyRateWindow replaces the range of every range selector of a query with window.
+// A zero window returns the query unchanged.
+func applyRateWindow(query string, window time.Duration) string {
+	if window <= 0 {
+		return query
+	}
+	return rangeSelectorPattern.ReplaceAllString(query, fmt.Sprintf("[%ds]", int(window.Seconds())))
+}
+
+// ExportMetadata describes how an exported metrics file was collected. It is written
+// next to the file, see ExportMetadataPath.
+type ExportMetadata struct {
+	ExportedAt time.Time  `json:"exported_at"`
+	Source     string     `json:"source"`
+	Resolution Resolution `json:"resolution"`
+	// FailedQueries is the number of Prometheus queries that failed or timed out,
+	// whose metrics are missing from a partial export
+	FailedQueries int `json:"failed_queries,omitempty"`
+	// Queries are the duration and outcome of each Prometheus query, slowest first
+	Queries []QueryStats `json:"queries,omitempty"`
+}
+
+// ExportMetadataPath returns the metadata file of a metrics file:
+// results/run-metrics.csv has results/run-metrics-export.json
+func ExportMetadataPath(metricsPath string) string {
+	return metricsPath[:len(metricsPath)-len(filepath.Ext(metricsPath))] + "-export.json"
+}
+
+// WriteExportMetadata writes the metadata of a metrics file next to it
+func WriteExportMetadata(meta *ExportMetadata, metricsPath string) error {
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode export metadata: %w", err)
+	}
+	if err := os.WriteFile(ExportMetadataPath(metricsPath), data, 0644); err != nil {
+		return fmt.Errorf("failed to write export metadata: %w", err)
+	}
+	return nil
+}
+
+// LoadExportMetadata reads the metadata of a metrics file. The error wraps
+// os.ErrNotExist for files exported before metadata was recorded.
+func LoadExportMetadata(metricsPath string) (*ExportMetadata, error) {
+	data, err := os.ReadFile(ExportMetadataPath(metricsPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read export metadata: %w", err)
+	}
+
+	var meta ExportMetadata
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("failed to parse export metadata of %s: %w", metricsPath, err)
+	}
+	return &meta, nil
+}
+
+// CheckResolutions returns the common resolution of metrics files, or an error
+// wrapping ErrResolutionMismatch if they were collected with different resolutions.
+// Files without export metadata are skipped.
+func CheckResolutions(metricsPaths []string) (Resolution, error) {
+	var common Resolution
+	var commonPath string
+	for _, path := range metricsPaths {
+		meta, err := LoadExportMetadata(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return Resolution{}, err
+		}
+
+		if commonPath == "" {
+			common, commonPath = meta.Resolution, path
+			continue
+		}
+		if meta.Resolution != common {
+			return Resolution{}, fmt.Errorf("%w: %s (%s) and %s (%s)",
+				ErrResolutionMismatch, commonPath, common, path, meta.Resolution)
+		}
+	}
+	return common, nil
+}
@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/config"
+)
+
+func TestApplyRateWindow(t *testing.T) {
+	tests := []struct {
+		query    string
+		window   time.Duration
+		expected string
+	}{
+		{`sum(rate(x[1m]))`, 0, `sum(rate(x[1m]))`},
+		{`sum(rate(x{a="b"}[1m])) / sum(rate(y[5m]))`, 2 * time.Minute, `sum(rate(x{a="b"}[120s])) / sum(rate(y[120s]))`},
+		{`max_over_time(x[1h30m])`, 30 * time.Second, `max_over_time(x[30s])`},
+		{`max_over_time(rate(x[1m])[5m:1m])`, 2 * time.Minute, `max_over_time(rate(x[120s])[5m:1m])`},
+	}
+	for _, tt := range tests {
+		if got := applyRateWindow(tt.query, tt.window); got != tt.expected {
+			t.Errorf("applyRateWindow(%q, %s) = %q, want %q", tt.query, tt.window, got, tt.expected)
+		}
+	}
+}
+
+func TestResolutionFromConfig(t *testing.T) {
+	r := ResolutionFromConfig(config.Default().WithMetricsResolution(0, time.Minute))
+	if r.Step != config.DefaultMetricsQueryStep || r.RateWindow != time.Minute {
+		t.Errorf("unexpected resolution %s", r)
+	}
+}
+
+func TestCheckResolutions(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, r Resolution) string {
+		path := filepath.Join(dir, name+"-metrics.csv")
+		if err := WriteExportMetadata(&ExportMetadata{Source: SourcePrometheus, Resolution: r}, path); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	a := write("a", Resolution{Step: time.Minute})
+	b := write("b", Resolution{Step: time.Minute})
+	c := write("c", Resolution{Step: time.Minute, RateWindow: 2 * time.Minute})
+	legacy := filepath.Join(dir, "legacy-metrics.csv")
+
+	r, err := CheckResolutions([]string{legacy, a, b})
+	if err != nil {
+		t.Fatalf("CheckResolutions() error = %v", err)
+	}
+	if r.Step != time.Minute {
+		t.Errorf("unexpected common resolution %s", r)
+	}
+
+	if _, err := CheckResolutions([]string{a, legacy, c}); !errors.Is(err, ErrResolutionMismatch) {
+		t.Errorf("expected ErrResolutionMismatch, got %v", err)
+	}
+}
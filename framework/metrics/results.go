@@ -0,0 +1,456 @@
+package metrics
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// csvTimestampFormat is the timestamp layout written by CSVExporter
+const csvTimestampFormat = "2006-01-02T15:04:05Z"
+
+// Load reads metric results previously written by an Exporter.
+// The format is detected from the file extension (.json, otherwise CSV).
+func Load(path string) ([]MetricResult, error) {
+	if strings.ToLower(filepath.Ext(path)) == ".json" {
+		return LoadFromJSON(path)
+	}
+	return LoadFromCSV(path)
+}
+
+// LoadFromCSV reads metric results from a CSV file written by CSVExporter.
+// Rows are grouped into one MetricResult per query ID and label set, with
+// data points sorted by timestamp. Malformed rows are skipped and reported.
+func LoadFromCSV(path string) ([]MetricResult, error) {
+	results, stats, err := LoadFromCSVWithOptions(path, CSVLoadOptions{})
+	if err != nil {
+		return nil, err
+	}
+	stats.PrintSkipped(path)
+	return results, nil
+}
+
+// CSVLoadOptions controls how LoadFromCSVWithOptions reads a metrics file
+type CSVLoadOptions struct {
+	// MaxPointsPerSeries downsamples series with more data points by averaging
+	// them into that many buckets of equal duration, bounding memory for long runs.
+	// Zero keeps every point.
+	MaxPointsPerSeries int
+}
+
+// CSVLoadStats describes a metrics file read by LoadFromCSVWithOptions
+type CSVLoadStats struct {
+	// Rows is the number of data rows read
+	Rows int
+	// Series is the number of series the rows were grouped into
+	Series int
+	// Points is the number of data points kept after downsampling
+	Points int
+	// DownsampledSeries is the number of series that were downsampled
+	DownsampledSeries int
+	// SkippedRows is the number of malformed rows skipped, e.g. the truncated last
+	// row of a file whose run was interrupted
+	SkippedRows int
+	// FirstSkipped describes the first skipped row
+	FirstSkipped string
+}
+
+// PrintSkipped reports the rows of the file at path that were skipped, if any
+func (s *CSVLoadStats) PrintSkipped(path string) {
+	if s.SkippedRows > 0 {
+		fmt.Printf("⚠️  Skipped %d malformed row(s) in %s (first: %s)\n", s.SkippedRows, filepath.Base(path), s.FirstSkipped)
+	}
+}
+
+// LoadFromCSVWithOptions reads metric results like LoadFromCSV, streaming the rows
+// so that memory grows with the data points kept rather than the file size.
+// With MaxPointsPerSeries, a first pass finds the time range of each series, and
+// longer series are averaged into buckets of equal duration over their range, so
+// the whole run keeps the same resolution.
+func LoadFromCSVWithOptions(path string, opts CSVLoadOptions) ([]MetricResult, *CSVLoadStats, error) {
+	maxPoints := opts.MaxPointsPerSeries
+
+	// First pass: the number of rows and time range of each series
+	spans := make(map[string]*seriesSpan)
+	if maxPoints > 0 {
+		err := scanCSV(path, func(row csvRow) {
+			key := row.key()
+			span, ok := spans[key]
+			if !ok {
+				spans[key] = &seriesSpan{count: 1, first: row.timestamp, last: row.timestamp}
+				return
+			}
+			span.count++
+			if row.timestamp.Before(span.first) {
+				span.first = row.timestamp
+			}
+			if row.timestamp.After(span.last) {
+				span.last = row.timestamp
+			}
+		}, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var results []MetricResult
+	index := make(map[string]int)
+	buckets := make(map[int]*timeBuckets)
+	stats := &CSVLoadStats{}
+
+	err := scanCSV(path, func(row csvRow) {
+		stats.Rows++
+
+		key := row.key()
+		idx, exists := index[key]
+		if !exists {
+			idx = len(results)
+			index[key] = idx
+			results = append(results, MetricResult{
+				QueryID:     row.record[0],
+				MetricName:  row.record[1],
+				Category:    row.record[2],
+				Description: row.record[3],
+				Labels:      parseLabelString(row.record[6]),
+				DataPoints:  []DataPoint{},
+			})
+			if span := spans[key]; span != nil && span.count > maxPoints {
+				buckets[idx] = newTimeBuckets(span.first, span.last, maxPoints)
+			}
+		}
+
+		dp := DataPoint{Timestamp: row.timestamp, Value: row.value}
+		if b := buckets[idx]; b != nil {
+			b.add(dp)
+			return
+		}
+		results[idx].DataPoints = append(results[idx].DataPoints, dp)
+	}, func(err error) {
+		if stats.SkippedRows == 0 {
+			stats.FirstSkipped = err.Error()
+		}
+		stats.SkippedRows++
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i := range results {
+		if b := buckets[i]; b != nil {
+			results[i].DataPoints = b.points()
+		}
+		sortDataPoints(results[i].DataPoints)
+		stats.Points += len(results[i].DataPoints)
+	}
+	stats.Series = len(results)
+	stats.DownsampledSeries = len(buckets)
+
+	return results, stats, nil
+}
+
+// csvRow is a data row of a metrics file written by CSVExporter
+type csvRow struct {
+	// record holds query_id, metric_name, category, description, timestamp, value, labels
+	record    []string
+	timestamp time.Time
+	value     float64
+}
+
+// key identifies the series of the row
+func (r csvRow) key() string {
+	return r.record[0] + "|" + r.record[6]
+}
+
+// scanCSV calls fn with each data row of a metrics file. The record of a row is
+// only valid during the call. Malformed rows are skipped rather than failing the
+// whole file, calling skip (optional) with the reason.
+func scanCSV(path string, fn func(row csvRow), skip func(err error)) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open metrics file: %w", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(bufio.NewReader(file))
+	reader.ReuseRecord = true
+	// The number of columns is checked per row
+	reader.FieldsPerRecord = -1
+
+	if skip == nil {
+		skip = func(error) {}
+	}
+	for header := true; ; header = false {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			skip(err)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read CSV: %w", err)
+		}
+		if header {
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+		if len(record) < 7 {
+			skip(fmt.Errorf("line %d: expected 7 columns, got %d", line, len(record)))
+			continue
+		}
+
+		// query_id, metric_name, category, description, timestamp, value, labels
+		ts, err := time.Parse(csvTimestampFormat, record[4])
+		if err != nil {
+			skip(fmt.Errorf("line %d: invalid timestamp %q", line, record[4]))
+			continue
+		}
+		val, err := strconv.ParseFloat(record[5], 64)
+		if err != nil {
+			skip(fmt.Errorf("line %d: invalid value %q", line, record[5]))
+			continue
+		}
+		fn(csvRow{record: record, timestamp: ts, value: val})
+	}
+}
+
+// seriesSpan is the number of rows and the time range of a series
+type seriesSpan struct {
+	count       int
+	first, last time.Time
+}
+
+// timeBuckets averages the points of a series into buckets of equal duration
+// between the first and last timestamp of the series, in any order of the points
+type timeBuckets struct {
+	start   time.Time
+	width   time.Duration
+	buckets []timeBucket
+}
+
+// timeBucket accumulates the points of a bucket
+type timeBucket struct {
+	first  time.Time
+	sum    float64
+	finite int
+	points int
+}
+
+// newTimeBuckets creates n buckets covering first to last
+func newTimeBuckets(first, last time.Time, n int) *timeBuckets {
+	return &timeBuckets{
+		start:   first,
+		width:   last.Sub(first) / time.Duration(n),
+		buckets: make([]timeBucket, n),
+	}
+}
+
+// add adds a point to its bucket. NaN and Inf values are left out of the averages.
+func (b *timeBuckets) add(dp DataPoint) {
+	i := 0
+	if b.width > 0 {
+		i = min(int(dp.Timestamp.Sub(b.start)/b.width), len(b.buckets)-1)
+	}
+	bucket := &b.buckets[i]
+	if bucket.points == 0 || dp.Timestamp.Before(bucket.first) {
+		bucket.first = dp.Timestamp
+	}
+	bucket.points++
+	if !math.IsNaN(dp.Value) && !math.IsInf(dp.Value, 0) {
+		bucket.sum += dp.Value
+		bucket.finite++
+	}
+}
+
+// points returns the average of each bucket with points, at the timestamp of its
+// first point, or NaN for buckets without finite values
+func (b *timeBuckets) points() []DataPoint {
+	var out []DataPoint
+	for _, bucket := range b.buckets {
+		if bucket.points == 0 {
+			continue
+		}
+		value := math.NaN()
+		if bucket.finite > 0 {
+			value = bucket.sum / float64(bucket.finite)
+		}
+		out = append(out, DataPoint{Timestamp: bucket.first, Value: value})
+	}
+	return out
+}
+
+// LoadFromJSON reads metric results from a JSON report written by JSONExporter.
+// Metrics that failed during collection are returned with Error set.
+func LoadFromJSON(path string) ([]MetricResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open metrics file: %w", err)
+	}
+
+	var report JSONExportReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON: %w", err)
+	}
+
+	results := make([]MetricResult, 0, len(report.Metrics))
+	for _, m := range report.Metrics {
+		result := MetricResult{
+			QueryID:     m.QueryID,
+			MetricName:  m.MetricName,
+			Description: m.Description,
+			Category:    m.Category,
+			Labels:      m.Labels,
+			DataPoints:  make([]DataPoint, 0, len(m.DataPoints)),
+		}
+		if result.Labels == nil {
+			result.Labels = map[string]string{}
+		}
+		if m.Error != "" {
+			result.Error = errors.New(m.Error)
+		}
+
+		for _, dp := range m.DataPoints {
+			ts, err := time.Parse(time.RFC3339, dp.Timestamp)
+			if err != nil {
+				return nil, fmt.Errorf("metric %s: invalid timestamp %q: %w", m.QueryID, dp.Timestamp, err)
+			}
+			result.DataPoints = append(result.DataPoints, DataPoint{Timestamp: ts, Value: dp.Value})
+		}
+		sortDataPoints(result.DataPoints)
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// Values returns the finite data point values of the result.
+// NaN and Inf samples are skipped.
+func (r MetricResult) Values() []float64 {
+	values := make([]float64, 0, len(r.DataPoints))
+	for _, dp := range r.DataPoints {
+		if math.IsNaN(dp.Value) || math.IsInf(dp.Value, 0) {
+			continue
+		}
+		values = append(values, dp.Value)
+	}
+	return values
+}
+
+// Mean returns the arithmetic mean of the result's values, or NaN if there are none
+func (r MetricResult) Mean() float64 {
+	values := r.Values()
+	if len(values) == 0 {
+		return math.NaN()
+	}
+
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// Max returns the largest of the result's values, or NaN if there are none
+func (r MetricResult) Max() float64 {
+	values := r.Values()
+	if len(values) == 0 {
+		return math.NaN()
+	}
+
+	largest := values[0]
+	for _, v := range values[1:] {
+		if v > largest {
+			largest = v
+		}
+	}
+	return largest
+}
+
+// Percentile returns the p-th percentile (0 <= p <= 1) of the result's values
+// using linear interpolation between closest ranks, or NaN if there are none.
+// For example, Percentile(0.99) returns the p99 value.
+func (r MetricResult) Percentile(p float64) float64 {
+	values := r.Values()
+	if len(values) == 0 || p < 0 || p > 1 {
+		return math.NaN()
+	}
+
+	sort.Float64s(values)
+
+	rank := p * float64(len(values)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	if lower == upper {
+		return values[lower]
+	}
+	return values[lower] + (values[upper]-values[lower])*(rank-float64(lower))
+}
+
+// FilterByCategory returns the results belonging to the given category
+func FilterByCategory(results []MetricResult, category string) []MetricResult {
+	return filterResults(results, func(r MetricResult) bool { return r.Category == category })
+}
+
+// FilterByQueryID returns the results for the given query ID
+func FilterByQueryID(results []MetricResult, queryID string) []MetricResult {
+	return filterResults(results, func(r MetricResult) bool { return r.QueryID == queryID })
+}
+
+// FilterByLabels returns the results whose labels contain all given key/value pairs
+func FilterByLabels(results []MetricResult, labels map[string]string) []MetricResult {
+	return filterResults(results, func(r MetricResult) bool {
+		for k, v := range labels {
+			if r.Labels[k] != v {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+func filterResults(results []MetricResult, match func(MetricResult) bool) []MetricResult {
+	var filtered []MetricResult
+	for _, r := range results {
+		if match(r) {
+			filtered = append(filtered, r)
+		}
+	}
+	return filtered
+}
+
+// parseLabelString parses labels formatted by formatLabels
+func parseLabelString(labelStr string) map[string]string {
+	labels := make(map[string]string)
+	if labelStr == "" {
+		return labels
+	}
+
+	for _, part := range strings.Split(labelStr, ",") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) == 2 {
+			labels[kv[0]] = kv[1]
+		}
+	}
+
+	return labels
+}
+
+// sortDataPoints sorts data points by timestamp
+func sortDataPoints(points []DataPoint) {
+	sort.Slice(points, func(i, j int) bool {
+		return points[i].Timestamp.Before(points[j].Timestamp)
+	})
+}
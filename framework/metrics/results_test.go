@@ -0,0 +1,270 @@
+package metrics
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func sampleResults() []MetricResult {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	return []MetricResult{
+		{
+			QueryID:     "cpu_usage_by_component",
+			MetricName:  "CPU Usage",
+			Category:    "resources",
+			Description: "CPU usage per component",
+			Labels:      map[string]string{"component": "ingester"},
+			DataPoints: []DataPoint{
+				{Timestamp: start, Value: 1},
+				{Timestamp: start.Add(time.Minute), Value: 2},
+				{Timestamp: start.Add(2 * time.Minute), Value: 3},
+				{Timestamp: start.Add(3 * time.Minute), Value: 4},
+			},
+		},
+		{
+			QueryID:     "cpu_usage_by_component",
+			MetricName:  "CPU Usage",
+			Category:    "resources",
+			Description: "CPU usage per component",
+			Labels:      map[string]string{"component": "compactor"},
+			DataPoints: []DataPoint{
+				{Timestamp: start, Value: 0.5},
+			},
+		},
+		{
+			QueryID:     "accepted_spans_rate",
+			MetricName:  "Accepted Spans",
+			Category:    "ingestion",
+			Description: "Accepted spans per second",
+			Labels:      map[string]string{},
+			DataPoints: []DataPoint{
+				{Timestamp: start, Value: 1000},
+			},
+		},
+	}
+}
+
+func TestLoadFromCSV_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.csv")
+	if err := NewCSVExporter(path).Export(sampleResults()); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+
+	results, err := LoadFromCSV(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	ingester := FilterByLabels(results, map[string]string{"component": "ingester"})
+	if len(ingester) != 1 {
+		t.Fatalf("expected 1 ingester result, got %d", len(ingester))
+	}
+	if len(ingester[0].DataPoints) != 4 {
+		t.Errorf("expected 4 data points, got %d", len(ingester[0].DataPoints))
+	}
+	if ingester[0].Category != "resources" {
+		t.Errorf("expected category resources, got %q", ingester[0].Category)
+	}
+}
+
+func TestLoadFromJSON_RoundTrip(t *testing.T) {
+	input := sampleResults()
+	input = append(input, MetricResult{
+		QueryID:  "failed_query",
+		Category: "ingestion",
+		Error:    errors.New("query failed"),
+	})
+
+	path := filepath.Join(t.TempDir(), "metrics.json")
+	if err := NewJSONExporter(path).Export(input); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+
+	results, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(results) != 4 {
+		t.Fatalf("expected 4 results, got %d", len(results))
+	}
+	failed := FilterByQueryID(results, "failed_query")
+	if len(failed) != 1 || failed[0].Error == nil {
+		t.Fatal("expected failed query to be loaded with its error")
+	}
+	if failed[0].Error.Error() != "query failed" {
+		t.Errorf("expected error message to be preserved, got %q", failed[0].Error)
+	}
+	if !results[0].DataPoints[0].Timestamp.Equal(input[0].DataPoints[0].Timestamp) {
+		t.Errorf("expected timestamp to be preserved, got %v", results[0].DataPoints[0].Timestamp)
+	}
+}
+
+func TestLoadFromCSV_MissingFile(t *testing.T) {
+	if _, err := LoadFromCSV(filepath.Join(t.TempDir(), "missing.csv")); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
+
+func TestLoadFromCSVWithOptions_SkipsMalformedRows(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.csv")
+	content := `query_id,metric_name,category,description,timestamp,value,labels
+22,cpu_usage_total,resources,CPU,2024-01-01T12:00:00Z,1.5,
+22,cpu_usage_total,resources,CPU,yesterday,2,
+22,cpu_usage_total,resources,CPU,2024-01-01T12:01:00Z,n/a,
+22,cpu_usage_total,resources
+22,cpu_usage_total,resources,CPU,2024-01-01T12:02:00Z,3,
+22,cpu_usage_total,resources,"CPU,2024-01-01T12:03:00Z,4,`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	results, stats, err := LoadFromCSVWithOptions(path, CSVLoadOptions{MaxPointsPerSeries: 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats.Rows != 2 || stats.SkippedRows != 4 {
+		t.Errorf("expected 2 rows read and 4 skipped, got %+v", stats)
+	}
+	if !strings.Contains(stats.FirstSkipped, "line 3") {
+		t.Errorf("expected the first skipped row to be line 3, got %q", stats.FirstSkipped)
+	}
+	if len(results) != 1 || len(results[0].DataPoints) != 2 || results[0].DataPoints[1].Value != 3 {
+		t.Errorf("expected the 2 valid points, got %+v", results)
+	}
+}
+
+func TestMetricResult_Statistics(t *testing.T) {
+	r := sampleResults()[0]
+
+	if got := r.Mean(); got != 2.5 {
+		t.Errorf("expected mean 2.5, got %v", got)
+	}
+	if got := r.Max(); got != 4 {
+		t.Errorf("expected max 4, got %v", got)
+	}
+	if got := r.Percentile(0.5); got != 2.5 {
+		t.Errorf("expected p50 2.5, got %v", got)
+	}
+	if got := r.Percentile(1); got != 4 {
+		t.Errorf("expected p100 4, got %v", got)
+	}
+	if got := r.Percentile(0.99); math.Abs(got-3.97) > 1e-9 {
+		t.Errorf("expected p99 3.97, got %v", got)
+	}
+}
+
+func TestMetricResult_StatisticsEmpty(t *testing.T) {
+	r := MetricResult{DataPoints: []DataPoint{{Value: math.NaN()}}}
+
+	if !math.IsNaN(r.Mean()) {
+		t.Error("expected NaN mean for result without finite values")
+	}
+	if !math.IsNaN(r.Percentile(0.99)) {
+		t.Error("expected NaN percentile for result without finite values")
+	}
+}
+
+func TestFilterByCategory(t *testing.T) {
+	results := FilterByCategory(sampleResults(), "resources")
+	if len(results) != 2 {
+		t.Errorf("expected 2 resources results, got %d", len(results))
+	}
+	if len(FilterByCategory(sampleResults(), "unknown")) != 0 {
+		t.Error("expected no results for unknown category")
+	}
+}
+
+func TestLoadFromCSVWithOptions_Downsample(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	series := MetricResult{
+		QueryID:    "22",
+		MetricName: "cpu_usage_total",
+		Category:   "resources",
+		Labels:     map[string]string{},
+	}
+	for i := 0; i < 100; i++ {
+		series.DataPoints = append(series.DataPoints, DataPoint{Timestamp: start.Add(time.Duration(i) * time.Minute), Value: float64(i)})
+	}
+
+	path := filepath.Join(t.TempDir(), "metrics.csv")
+	if err := NewCSVExporter(path).Export(append([]MetricResult{series}, sampleResults()...)); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+
+	results, stats, err := LoadFromCSVWithOptions(path, CSVLoadOptions{MaxPointsPerSeries: 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats.Rows != 106 || stats.Series != 4 || stats.DownsampledSeries != 1 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+
+	cpu := FilterByCategory(results, "resources")
+	var points []DataPoint
+	for _, r := range cpu {
+		if r.QueryID == "22" {
+			points = r.DataPoints
+		}
+	}
+	if len(points) != 10 {
+		t.Fatalf("expected 10 points, got %d", len(points))
+	}
+	if !points[0].Timestamp.Equal(start) {
+		t.Errorf("expected the first point at %v, got %v", start, points[0].Timestamp)
+	}
+
+	// Buckets cover equal durations of the run: 99 minutes in 10 buckets, starting
+	// at most one sample apart from that
+	width := 99 * time.Minute / 10
+	for i := 1; i < len(points); i++ {
+		step := points[i].Timestamp.Sub(points[i-1].Timestamp)
+		if step < width-time.Minute || step > width+time.Minute {
+			t.Errorf("expected buckets %v apart, got %v between points %d and %d", width, step, i-1, i)
+		}
+		if points[i].Value <= points[i-1].Value {
+			t.Fatalf("expected increasing downsampled points, got %+v", points)
+		}
+	}
+	if mid := points[5].Value; mid < 45 || mid > 55 {
+		t.Errorf("expected the middle bucket to average the middle of the run, got %v", mid)
+	}
+
+	// Series within the limit are kept as is
+	if stats.Points != len(points)+6 {
+		t.Errorf("expected %d points in total, got %d", len(points)+6, stats.Points)
+	}
+}
+
+func TestTimeBuckets_SkipsNaN(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	points := []DataPoint{
+		{Timestamp: start.Add(time.Minute), Value: math.NaN()},
+		{Timestamp: start, Value: 2},
+		{Timestamp: start.Add(2 * time.Minute), Value: math.NaN()},
+		{Timestamp: start.Add(3 * time.Minute), Value: math.Inf(1)},
+	}
+
+	buckets := newTimeBuckets(start, start.Add(3*time.Minute), 2)
+	for _, dp := range points {
+		buckets.add(dp)
+	}
+	got := buckets.points()
+	if len(got) != 2 {
+		t.Fatalf("expected 2 points, got %d", len(got))
+	}
+	if got[0].Value != 2 || !got[0].Timestamp.Equal(start) {
+		t.Errorf("expected the first bucket to average to 2 at %v, got %+v", start, got[0])
+	}
+	if !math.IsNaN(got[1].Value) {
+		t.Errorf("expected NaN for a bucket without finite values, got %v", got[1].Value)
+	}
+}
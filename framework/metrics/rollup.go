@@ -0,0 +1,299 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// rollupDelay is how long after the end of an interval its rollup is collected, so
+// the last samples of the interval are scraped and available in Thanos
+const rollupDelay = time.Minute
+
+// CollectOption configures a metrics collection
+type CollectOption func(*collectOptions)
+
+// collectOptions configures a metrics collection
+type collectOptions struct {
+	rollupInterval time.Duration
+	labels         map[string]string
+	partialResults bool
+}
+
+// WithRollups collects the test window in intervals aligned to multiples of interval
+// (e.g. 12:00-12:30, 12:30-13:00) and merges them into the final export. Each
+// complete interval is written to RollupDir(outputPath) and reused when it already
+// exists there, e.g. written during the run by a RollupWriter, so long soak tests
+// only query the end of the run and keep partial results when they are aborted.
+func WithRollups(interval time.Duration) CollectOption {
+	return func(o *collectOptions) {
+		o.rollupInterval = interval
+	}
+}
+
+// WithLabels adds labels to every collected series, e.g. the settings runs are
+// compared on, like IngestionProtocolLabel
+func WithLabels(labels map[string]string) CollectOption {
+	return func(o *collectOptions) {
+		if o.labels == nil {
+			o.labels = map[string]string{}
+		}
+		for k, v := range labels {
+			o.labels[k] = v
+		}
+	}
+}
+
+// WithPartialResults exports the metrics collected when some queries failed or timed
+// out instead of returning ErrCollectionIncomplete from CollectMetricsIncremental.
+// The failed queries are recorded in the export metadata. A collection in which
+// every query failed still returns the error.
+func WithPartialResults() CollectOption {
+	return func(o *collectOptions) {
+		o.partialResults = true
+	}
+}
+
+// RollupDir returns the directory of the rollups of a metrics file:
+// results/run-metrics.csv has results/run-metrics-rollups
+func RollupDir(metricsPath string) string {
+	return metricsPath[:len(metricsPath)-len(filepath.Ext(metricsPath))] + "-rollups"
+}
+
+// rollupWindow is an interval of a rollup collection
+type rollupWindow struct {
+	Start time.Time
+	End   time.Time
+}
+
+// complete reports whether the window ends at an interval boundary, rather than at
+// the end of a collection
+func (w rollupWindow) complete(interval time.Duration) bool {
+	return w.End.Truncate(interval).Equal(w.End)
+}
+
+// rollupWindows splits start-end at the multiples of interval
+func rollupWindows(start, end time.Time, interval time.Duration) []rollupWindow {
+	var windows []rollupWindow
+	for from := start; from.Before(end); {
+		to := from.Truncate(interval).Add(interval)
+		if to.After(end) {
+			to = end
+		}
+		windows = append(windows, rollupWindow{Start: from, End: to})
+		from = to
+	}
+	return windows
+}
+
+// rollupPath returns the rollup file of the window starting at start
+func rollupPath(dir string, start time.Time) string {
+	return filepath.Join(dir, "rollup-"+start.UTC().Format("20060102T150405Z")+".csv")
+}
+
+// collectRollups collects start-end window by window. Complete windows are loaded
+// from their rollup file in dir, or collected and written to it, checkpointing their
+// queries. The last, partial window is only collected when partial is set, and is
+// not written. The results of all windows are merged; an error wrapping
+// ErrCollectionIncomplete is returned along with them when queries failed.
+func collectRollups(ctx context.Context, c *collection, np NamespaceProvider, start, end time.Time, interval time.Duration, dir string, partial bool) ([]MetricResult, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create rollup directory: %w", err)
+	}
+
+	merger := newResultMerger()
+	incomplete := 0
+	for _, w := range rollupWindows(start, end, interval) {
+		complete := w.complete(interval)
+		if !complete && !partial {
+			continue
+		}
+
+		path := rollupPath(dir, w.Start)
+		if complete {
+			if _, err := os.Stat(path); err == nil {
+				results, err := Load(path)
+				if err == nil {
+					merger.add(results)
+					continue
+				}
+				fmt.Printf("⚠️  Warning: %v, collecting %s again\n", err, path)
+			}
+		}
+
+		fmt.Printf("📦 Collecting metrics of %s - %s\n", w.Start.UTC().Format(time.RFC3339), w.End.UTC().Format(time.RFC3339))
+		results, err := collectRollup(ctx, c, np, w, path, complete)
+		if errors.Is(err, ErrCollectionIncomplete) {
+			incomplete++
+		} else if err != nil {
+			return nil, err
+		}
+		merger.add(results)
+	}
+
+	if incomplete > 0 {
+		return merger.results(), fmt.Errorf("%w: %d intervals have failed queries", ErrCollectionIncomplete, incomplete)
+	}
+	return merger.results(), nil
+}
+
+// collectRollup collects the metrics of a window, tagged with the run ID. A complete
+// window is checkpointed and written to path once all its queries completed.
+func collectRollup(ctx context.Context, c *collection, np NamespaceProvider, w rollupWindow, path string, complete bool) ([]MetricResult, error) {
+	if !complete {
+		results, err := c.collectAllMetrics(ctx, w.Start, w.End, nil)
+		if err != nil {
+			return nil, err
+		}
+		addRunID(np, results)
+		if failed := countFailed(results); failed > 0 {
+			return results, fmt.Errorf("%w: %d queries failed", ErrCollectionIncomplete, failed)
+		}
+		return results, nil
+	}
+
+	cp, _, err := openCheckpoint(CheckpointDir(path), checkpointState{
+		Namespace:  np.Namespace(),
+		Start:      w.Start,
+		End:        w.End,
+		Resolution: c.resolution,
+	})
+	if err != nil {
+		return nil, err
+	}
+	results, err := c.collectAllMetrics(ctx, w.Start, w.End, cp)
+	addRunID(np, results)
+	if err != nil {
+		return results, err
+	}
+
+	// Write and rename, so a rollup read while the test runs is never partial
+	if err := NewCSVExporter(path + ".tmp").Export(results); err != nil {
+		return nil, fmt.Errorf("failed to write rollup: %w", err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return nil, fmt.Errorf("failed to write rollup: %w", err)
+	}
+	if err := os.RemoveAll(cp.dir); err != nil {
+		fmt.Printf("⚠️  Warning: failed to remove checkpoint: %v\n", err)
+	}
+	fmt.Printf("📦 Rollup written to %s\n", path)
+	return results, nil
+}
+
+// countFailed returns the number of results of queries that failed for another
+// reason than ErrNoData
+func countFailed(results []MetricResult) int {
+	failed := 0
+	for _, r := range results {
+		if r.Error != nil && !errors.Is(r.Error, ErrNoData) {
+			failed++
+		}
+	}
+	return failed
+}
+
+// addRunID tags results with the run ID of the provider, if it has one
+func addRunID(np NamespaceProvider, results []MetricResult) {
+	if rp, ok := np.(RunIDProvider); ok && rp.RunID() != "" {
+		addLabel(results, RunIDLabel, rp.RunID())
+	}
+}
+
+// RollupWriterProvider optionally provides a RollupWriter, which is stopped when
+// the metrics are collected
+type RollupWriterProvider interface {
+	RollupWriter() *RollupWriter
+}
+
+// RollupWriter writes the rollups of a running test: shortly after each interval
+// boundary, the intervals completed since the test started that have no rollup yet
+// are collected into RollupDir of the metrics file. Collecting the metrics with
+// WithRollups and the same interval then reuses them.
+type RollupWriter struct {
+	np        NamespaceProvider
+	testStart time.Time
+	dir       string
+	interval  time.Duration
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewRollupWriter creates a writer of the rollups of outputPath for a test started
+// at testStart
+func NewRollupWriter(np NamespaceProvider, testStart time.Time, outputPath string, interval time.Duration) *RollupWriter {
+	return &RollupWriter{
+		np:        np,
+		testStart: testStart,
+		dir:       RollupDir(outputPath),
+		interval:  interval,
+	}
+}
+
+// Start writes rollups in the background until Stop is called or ctx is done
+func (w *RollupWriter) Start(ctx context.Context) error {
+	if w.interval <= 0 {
+		return fmt.Errorf("invalid rollup interval: %s", w.interval)
+	}
+	if err := os.MkdirAll(w.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create rollup directory: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	w.mu.Lock()
+	w.cancel = cancel
+	w.done = make(chan struct{})
+	w.mu.Unlock()
+	go func() {
+		defer close(w.done)
+		for {
+			now := nowFor(w.np)
+			next := now.Truncate(w.interval).Add(w.interval + rollupDelay)
+			timer := time.NewTimer(next.Sub(now))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+			if err := w.Write(ctx); err != nil && ctx.Err() == nil {
+				fmt.Printf("⚠️  Warning: failed to write metrics rollups: %v\n", err)
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops writing rollups, interrupting a rollup being collected
+func (w *RollupWriter) Stop() {
+	w.mu.Lock()
+	cancel, done := w.cancel, w.done
+	w.cancel = nil
+	w.mu.Unlock()
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+}
+
+// Write collects the completed intervals without a rollup
+func (w *RollupWriter) Write(ctx context.Context) error {
+	c, err := newCollectionFor(ctx, w.np)
+	if err != nil {
+		return err
+	}
+	_, err = collectRollups(ctx, c, w.np, w.testStart, nowFor(w.np).Add(-rollupDelay), w.interval, w.dir, false)
+	return err
+}
+
+// Dir returns the directory the rollups are written to
+func (w *RollupWriter) Dir() string {
+	return w.dir
+}
@@ -0,0 +1,147 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRollupDir(t *testing.T) {
+	if got := RollupDir("results/small-metrics.csv"); got != "results/small-metrics-rollups" {
+		t.Errorf("unexpected rollup dir %q", got)
+	}
+}
+
+func TestRollupWindows(t *testing.T) {
+	start := time.Date(2024, 6, 1, 12, 10, 0, 0, time.UTC)
+	end := time.Date(2024, 6, 1, 13, 15, 0, 0, time.UTC)
+	windows := rollupWindows(start, end, 30*time.Minute)
+
+	want := []rollupWindow{
+		{Start: start, End: time.Date(2024, 6, 1, 12, 30, 0, 0, time.UTC)},
+		{Start: time.Date(2024, 6, 1, 12, 30, 0, 0, time.UTC), End: time.Date(2024, 6, 1, 13, 0, 0, 0, time.UTC)},
+		{Start: time.Date(2024, 6, 1, 13, 0, 0, 0, time.UTC), End: end},
+	}
+	if len(windows) != len(want) {
+		t.Fatalf("expected %d windows, got %+v", len(want), windows)
+	}
+	for i := range want {
+		if !windows[i].Start.Equal(want[i].Start) || !windows[i].End.Equal(want[i].End) {
+			t.Errorf("window %d: expected %+v, got %+v", i, want[i], windows[i])
+		}
+	}
+	if !windows[0].complete(30*time.Minute) || windows[2].complete(30*time.Minute) {
+		t.Errorf("expected only the last window to be partial")
+	}
+
+	if windows := rollupWindows(end, end, 30*time.Minute); len(windows) != 0 {
+		t.Errorf("expected no windows for an empty range, got %+v", windows)
+	}
+}
+
+type rollupTestProvider struct{}
+
+func (rollupTestProvider) Namespace() string { return "ns" }
+func (rollupTestProvider) RunID() string     { return "run1" }
+
+func TestCollectRollups_ReusesCompletedIntervals(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests = map[string]int{}
+		failing  = true
+	)
+	second := time.Date(2024, 6, 1, 13, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		start := r.URL.Query().Get("start")
+		requests[start]++
+		w.Header().Set("Content-Type", "application/json")
+		if failing && strings.Contains(r.URL.Query().Get("query"), "tempo_request_duration_seconds") && start == fmt.Sprint(second.Unix()) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"status":"success","data":{"resultType":"matrix","result":[{"metric":{},"values":[[%s,"1"]]}]}}`, start)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(context.Background(), &ClientConfig{Mode: ModeKubernetes, Namespace: "ns", ThanosURL: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	c := newCollection(client, client.config)
+
+	dir := filepath.Join(t.TempDir(), "rollups")
+	start := time.Date(2024, 6, 1, 12, 10, 0, 0, time.UTC)
+	boundary := time.Date(2024, 6, 1, 12, 30, 0, 0, time.UTC)
+	end := time.Date(2024, 6, 1, 13, 15, 0, 0, time.UTC)
+	np := rollupTestProvider{}
+
+	// Complete intervals are written, the partial one is skipped
+	_, err = collectRollups(context.Background(), c, np, start, second.Add(time.Minute), 30*time.Minute, dir, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(rollupPath(dir, start)); err != nil {
+		t.Errorf("expected the first interval to be written: %v", err)
+	}
+	if _, err := os.Stat(rollupPath(dir, boundary)); err != nil {
+		t.Errorf("expected the second interval to be written: %v", err)
+	}
+	if _, err := os.Stat(rollupPath(dir, second)); err == nil {
+		t.Errorf("expected the partial interval not to be written")
+	}
+
+	// The final collection fails on the last interval
+	if _, err := collectRollups(context.Background(), c, np, start, end, 30*time.Minute, dir, true); !errors.Is(err, ErrCollectionIncomplete) {
+		t.Fatalf("expected ErrCollectionIncomplete, got %v", err)
+	}
+
+	mu.Lock()
+	failing = false
+	requests = map[string]int{}
+	mu.Unlock()
+
+	results, err := collectRollups(context.Background(), c, np, start, end, 30*time.Minute, dir, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requests[fmt.Sprint(start.Unix())] != 0 || requests[fmt.Sprint(boundary.Unix())] != 0 {
+		t.Errorf("expected the written intervals to be reused, got requests %v", requests)
+	}
+	if requests[fmt.Sprint(second.Unix())] == 0 {
+		t.Errorf("expected the partial interval to be collected")
+	}
+
+	total := len(GetAllQueries("ns"))
+	if len(results) != total {
+		t.Fatalf("expected the intervals to be merged into %d series, got %d", total, len(results))
+	}
+	for _, r := range results {
+		if len(r.DataPoints) != 3 {
+			t.Errorf("%s: expected a data point of each interval, got %d", r.QueryID, len(r.DataPoints))
+		}
+		if r.Labels[RunIDLabel] != "run1" {
+			t.Errorf("%s: expected the run ID label, got %v", r.QueryID, r.Labels)
+		}
+	}
+}
+
+func TestWithLabels(t *testing.T) {
+	var o collectOptions
+	WithLabels(map[string]string{IngestionProtocolLabel: "http"})(&o)
+	WithLabels(map[string]string{"cluster": "perf"})(&o)
+
+	if len(o.labels) != 2 || o.labels[IngestionProtocolLabel] != "http" || o.labels["cluster"] != "perf" {
+		t.Errorf("expected the labels of both options, got %v", o.labels)
+	}
+}
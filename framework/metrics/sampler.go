@@ -0,0 +1,248 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/gvr"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+)
+
+// DefaultSamplingInterval is the interval at which ResourceSampler polls metrics-server
+const DefaultSamplingInterval = 15 * time.Second
+
+// ResourceSamplerProvider optionally provides a ResourceSampler whose samples are
+// exported when Prometheus is unavailable
+type ResourceSamplerProvider interface {
+	ResourceSampler() *ResourceSampler
+}
+
+// ResourceSampler polls the metrics.k8s.io API for the CPU and memory usage of the
+// Tempo containers of a namespace. It is a fallback for clusters without a
+// Prometheus/Thanos monitoring stack; its results use the same query IDs and metric
+// names as the Prometheus resource queries so the dashboard charts them unchanged.
+type ResourceSampler struct {
+	client    dynamic.Interface
+	namespace string
+	interval  time.Duration
+
+	mu      sync.Mutex
+	samples []containerSample
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// containerSample is the resource usage of a container at a poll
+type containerSample struct {
+	Time      time.Time
+	Pod       string
+	Container string
+	CPU       float64 // cores
+	Memory    float64 // bytes
+}
+
+// NewResourceSampler creates a sampler for the Tempo pods of a namespace.
+// A non-positive interval uses DefaultSamplingInterval.
+func NewResourceSampler(client dynamic.Interface, namespace string, interval time.Duration) *ResourceSampler {
+	if interval <= 0 {
+		interval = DefaultSamplingInterval
+	}
+	return &ResourceSampler{
+		client:    client,
+		namespace: namespace,
+		interval:  interval,
+	}
+}
+
+// Start takes a first sample and then polls in the background until Stop is called
+// or ctx is done. It returns an error if metrics-server cannot be queried.
+func (s *ResourceSampler) Start(ctx context.Context) error {
+	if err := s.Sample(ctx); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	s.cancel = cancel
+	s.done = make(chan struct{})
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.Sample(ctx); err != nil && ctx.Err() == nil {
+					fmt.Printf("⚠️  Warning: failed to sample resource usage: %v\n", err)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops background polling and waits for it to finish
+func (s *ResourceSampler) Stop() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	<-s.done
+}
+
+// Sample polls the resource usage of the Tempo containers once
+func (s *ResourceSampler) Sample(ctx context.Context) error {
+	list, err := s.client.Resource(gvr.PodMetrics).Namespace(s.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list pod metrics: %w", err)
+	}
+
+	now := time.Now()
+	var samples []containerSample
+	for _, item := range list.Items {
+		containers, _, _ := unstructured.NestedSlice(item.Object, "containers")
+		for _, c := range containers {
+			container, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _, _ := unstructured.NestedString(container, "name")
+			if !strings.HasPrefix(name, "tempo") {
+				continue
+			}
+			cpu, _, _ := unstructured.NestedString(container, "usage", "cpu")
+			memory, _, _ := unstructured.NestedString(container, "usage", "memory")
+			samples = append(samples, containerSample{
+				Time:      now,
+				Pod:       item.GetName(),
+				Container: name,
+				CPU:       quantityValue(cpu),
+				Memory:    quantityValue(memory),
+			})
+		}
+	}
+
+	s.mu.Lock()
+	s.samples = append(s.samples, samples...)
+	s.mu.Unlock()
+	return nil
+}
+
+// Interval returns the polling interval
+func (s *ResourceSampler) Interval() time.Duration {
+	return s.interval
+}
+
+// HasSamples reports whether any usage was sampled
+func (s *ResourceSampler) HasSamples() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.samples) > 0
+}
+
+// Results returns the sampled usage as the resource metrics of the Prometheus
+// queries: totals, per pod and container, and per component
+func (s *ResourceSampler) Results() []MetricResult {
+	s.mu.Lock()
+	samples := make([]containerSample, len(s.samples))
+	copy(samples, s.samples)
+	s.mu.Unlock()
+
+	memory := func(c containerSample) float64 { return c.Memory }
+	cpu := func(c containerSample) float64 { return c.CPU }
+	total := func(containerSample) map[string]string { return map[string]string{} }
+	byPodContainer := func(c containerSample) map[string]string {
+		return map[string]string{"pod": c.Pod, "container": c.Container}
+	}
+	byComponent := func(c containerSample) map[string]string {
+		if component := podComponent(c.Pod); component != "" {
+			return map[string]string{"component": component}
+		}
+		return map[string]string{}
+	}
+
+	var results []MetricResult
+	results = append(results, sumSamples(samples, "21", "memory_usage_total", "Total memory usage of Tempo containers (metrics-server)", memory, total)...)
+	results = append(results, sumSamples(samples, "22", "cpu_usage_total", "Total CPU usage of Tempo containers (metrics-server)", cpu, total)...)
+	results = append(results, sumSamples(samples, "23", "memory_usage_by_pod_container", "Memory usage by pod and container (metrics-server)", memory, byPodContainer)...)
+	results = append(results, sumSamples(samples, "24", "cpu_usage_by_pod_container", "CPU usage by pod and container (metrics-server)", cpu, byPodContainer)...)
+	results = append(results, sumSamples(samples, "25", "memory_usage_by_component", "Memory usage grouped by Tempo component (metrics-server)", memory, byComponent)...)
+	results = append(results, sumSamples(samples, "26", "cpu_usage_by_component", "CPU usage grouped by Tempo component (metrics-server)", cpu, byComponent)...)
+	return results
+}
+
+// sumSamples sums a value of the samples of each poll into one "resources" series
+// per label set
+func sumSamples(samples []containerSample, id, name, description string, value func(containerSample) float64, labels func(containerSample) map[string]string) []MetricResult {
+	series := make(map[string]*MetricResult)
+	sums := make(map[string]map[time.Time]float64)
+	for _, c := range samples {
+		l := labels(c)
+		key := formatLabels(l)
+		if _, ok := series[key]; !ok {
+			series[key] = &MetricResult{
+				QueryID:     id,
+				MetricName:  name,
+				Description: description,
+				Category:    "resources",
+				Labels:      l,
+			}
+			sums[key] = make(map[time.Time]float64)
+		}
+		sums[key][c.Time] += value(c)
+	}
+
+	var results []MetricResult
+	for _, key := range sortedKeys(series) {
+		r := series[key]
+		for t, v := range sums[key] {
+			r.DataPoints = append(r.DataPoints, DataPoint{Timestamp: t, Value: v})
+		}
+		sort.Slice(r.DataPoints, func(i, j int) bool {
+			return r.DataPoints[i].Timestamp.Before(r.DataPoints[j].Timestamp)
+		})
+		results = append(results, *r)
+	}
+	return results
+}
+
+// tempoComponents are the components matched in pod names, as in the component queries
+var tempoComponents = []string{"distributor", "ingester", "querier", "compactor", "gateway", "query-frontend"}
+
+// podComponent returns the Tempo component of a pod, or an empty string
+func podComponent(pod string) string {
+	for _, component := range tempoComponents {
+		if strings.Contains(pod, "-"+component+"-") {
+			return component
+		}
+	}
+	return ""
+}
+
+// quantityValue parses a resource quantity, returning 0 if it is invalid
+func quantityValue(s string) float64 {
+	q, err := resource.ParseQuantity(s)
+	if err != nil {
+		return 0
+	}
+	return q.AsApproximateFloat64()
+}
+
+// sortedKeys returns the keys of a map in order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
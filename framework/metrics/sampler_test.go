@@ -0,0 +1,124 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/gvr"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func podMetrics(name string, containers ...map[string]interface{}) *unstructured.Unstructured {
+	items := make([]interface{}, len(containers))
+	for i, c := range containers {
+		items[i] = c
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "metrics.k8s.io/v1beta1",
+		"kind":       "PodMetrics",
+		"metadata":   map[string]interface{}{"name": name, "namespace": "perf"},
+		"containers": items,
+	}}
+}
+
+func containerUsage(name, cpu, memory string) map[string]interface{} {
+	return map[string]interface{}{
+		"name":  name,
+		"usage": map[string]interface{}{"cpu": cpu, "memory": memory},
+	}
+}
+
+func newFakeSampler(t *testing.T, objects ...*unstructured.Unstructured) *ResourceSampler {
+	t.Helper()
+	listKinds := map[schema.GroupVersionResource]string{gvr.PodMetrics: "PodMetricsList"}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds)
+	// PodMetrics is served as "pods", which the tracker cannot guess from the kind
+	for _, obj := range objects {
+		if err := client.Tracker().Create(gvr.PodMetrics, obj, "perf"); err != nil {
+			t.Fatalf("failed to add pod metrics: %v", err)
+		}
+	}
+	return NewResourceSampler(client, "perf", 0)
+}
+
+func TestResourceSampler_Results(t *testing.T) {
+	s := newFakeSampler(t,
+		podMetrics("tempo-tempostack-ingester-0",
+			containerUsage("tempo", "500m", "1Gi"),
+			containerUsage("oauth-proxy", "100m", "64Mi")),
+		podMetrics("tempo-tempostack-distributor-7f9c-abcde",
+			containerUsage("tempo", "250m", "512Mi")),
+	)
+
+	if err := s.Sample(context.Background()); err != nil {
+		t.Fatalf("Sample() error = %v", err)
+	}
+	if !s.HasSamples() {
+		t.Fatal("expected samples")
+	}
+
+	byName := make(map[string][]MetricResult)
+	for _, r := range s.Results() {
+		byName[r.MetricName] = append(byName[r.MetricName], r)
+	}
+
+	memory := byName["memory_usage_total"]
+	if len(memory) != 1 || memory[0].QueryID != "21" || memory[0].Category != "resources" {
+		t.Fatalf("unexpected memory_usage_total %+v", memory)
+	}
+	if got, want := memory[0].DataPoints[0].Value, float64(1536<<20); got != want {
+		t.Errorf("memory_usage_total = %v, want %v (non-tempo containers excluded)", got, want)
+	}
+	if got := byName["cpu_usage_total"][0].DataPoints[0].Value; got != 0.75 {
+		t.Errorf("cpu_usage_total = %v, want 0.75", got)
+	}
+
+	components := map[string]float64{}
+	for _, r := range byName["cpu_usage_by_component"] {
+		components[r.Labels["component"]] = r.DataPoints[0].Value
+	}
+	if components["ingester"] != 0.5 || components["distributor"] != 0.25 {
+		t.Errorf("unexpected cpu_usage_by_component %v", components)
+	}
+	if len(byName["memory_usage_by_pod_container"]) != 2 {
+		t.Errorf("expected 2 pod/container series, got %d", len(byName["memory_usage_by_pod_container"]))
+	}
+}
+
+func TestResourceSampler_StartStop(t *testing.T) {
+	s := newFakeSampler(t, podMetrics("tempo-simplest-0", containerUsage("tempo", "1", "1Gi")))
+
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	s.Stop()
+	s.Stop() // idempotent
+
+	results := s.Results()
+	if len(results) == 0 {
+		t.Fatal("expected the first sample to be taken on start")
+	}
+	for _, r := range results {
+		if r.MetricName == "memory_usage_by_component" && len(r.Labels) != 0 {
+			t.Errorf("expected no component for monolithic pods, got %v", r.Labels)
+		}
+	}
+}
+
+func TestPodComponent(t *testing.T) {
+	tests := map[string]string{
+		"tempo-tempostack-query-frontend-5d8f-x": "query-frontend",
+		"tempo-tempostack-querier-5d8f-x":        "querier",
+		"tempo-tempostack-gateway-5d8f-x":        "gateway",
+		"tempo-simplest-0":                       "",
+	}
+	for pod, want := range tests {
+		if got := podComponent(pod); got != want {
+			t.Errorf("podComponent(%q) = %q, want %q", pod, got, want)
+		}
+	}
+}
@@ -0,0 +1,562 @@
+package metrics
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// DefaultScrapeInterval is the interval at which MetricsScraper scrapes the Tempo pods,
+// the interval of the fallback PodMonitor
+const DefaultScrapeInterval = 30 * time.Second
+
+// tempoMetricsPort is the HTTP port of Tempo, used when its container does not name
+// an "http" port
+const tempoMetricsPort = "3200"
+
+// tempoPodSelector selects the pods of the Tempo deployments of a namespace
+const tempoPodSelector = "app.kubernetes.io/managed-by=tempo-operator"
+
+// MetricsScraperProvider optionally provides a MetricsScraper whose results are
+// exported for the metrics Prometheus has no data for
+type MetricsScraperProvider interface {
+	MetricsScraper() *MetricsScraper
+}
+
+// scrapeKind is how the samples of a metric are aggregated at each scrape
+type scrapeKind int
+
+const (
+	// scrapeSum sums the values, like sum(metric)
+	scrapeSum scrapeKind = iota
+	// scrapeRate sums the per-second increase since the previous scrape, like
+	// sum(rate(metric[1m]))
+	scrapeRate
+	// scrapeQuantile estimates a quantile from the increase of the buckets of a
+	// classic histogram, like histogram_quantile(q, sum(rate(metric_bucket[1m])) by (le))
+	scrapeQuantile
+)
+
+// scrapeQuery computes the series of a Prometheus query from scraped samples
+type scrapeQuery struct {
+	ID     string
+	Metric string
+	Kind   scrapeKind
+	// By are the labels the samples are grouped by. pod and container are the
+	// scraped pod and container.
+	By       []string
+	Quantile float64
+}
+
+// scrapeQueries are the Tempo-internal queries that can be computed from the
+// /metrics endpoints of the Tempo pods. Queries matching labels by regex, and
+// the resource, kube-state-metrics, k6 and collector queries, are left out.
+var scrapeQueries = []scrapeQuery{
+	{ID: "1", Metric: "tempo_receiver_accepted_spans", Kind: scrapeRate},
+	{ID: "2", Metric: "tempo_receiver_refused_spans", Kind: scrapeRate},
+	{ID: "3", Metric: "tempo_distributor_bytes_received_total", Kind: scrapeRate, By: []string{"status"}},
+	{ID: "4", Metric: "tempo_distributor_push_duration_seconds", Kind: scrapeQuantile, Quantile: 0.99},
+	{ID: "5", Metric: "tempo_ingester_failed_flushes_total", Kind: scrapeRate},
+	{ID: "6", Metric: "tempo_discarded_spans_total", Kind: scrapeRate, By: []string{"reason"}},
+	{ID: "7", Metric: "tempo_ingester_live_traces", Kind: scrapeSum, By: []string{"pod"}},
+	{ID: "8", Metric: "tempo_ingester_blocks_flushed_total", Kind: scrapeRate, By: []string{"pod"}},
+	{ID: "9", Metric: "tempo_ingester_flush_queue_length", Kind: scrapeSum, By: []string{"pod"}},
+	{ID: "10", Metric: "tempo_ingester_traces_created_total", Kind: scrapeSum},
+	{ID: "11", Metric: "tempo_distributor_spans_received_total", Kind: scrapeSum},
+	{ID: "12", Metric: "tempodb_compaction_blocks_total", Kind: scrapeRate},
+	{ID: "13", Metric: "tempodb_compaction_bytes_written_total", Kind: scrapeRate},
+	{ID: "14", Metric: "tempodb_compaction_outstanding_blocks", Kind: scrapeSum},
+	{ID: "15", Metric: "tempodb_retention_deleted_total", Kind: scrapeSum},
+	{ID: "16", Metric: "tempodb_retention_marked_for_deletion_total", Kind: scrapeSum},
+	{ID: "17", Metric: "tempo_query_frontend_bytes_inspected_total", Kind: scrapeRate},
+	{ID: "18", Metric: "tempodb_backend_request_duration_seconds", Kind: scrapeQuantile, Quantile: 0.99},
+	{ID: "19", Metric: "tempodb_blocklist_poll_duration_seconds", Kind: scrapeQuantile, Quantile: 0.99},
+	{ID: "20", Metric: "tempodb_blocklist_length", Kind: scrapeSum, By: []string{"tenant"}},
+	{ID: "31", Metric: "tempo_query_frontend_queue_duration_seconds", Kind: scrapeQuantile, Quantile: 0.99},
+	{ID: "32", Metric: "tempo_query_frontend_retries_count", Kind: scrapeRate},
+	{ID: "33", Metric: "tempo_query_frontend_queue_length", Kind: scrapeSum, By: []string{"pod"}},
+	{ID: "34", Metric: "tempo_query_frontend_queries_total", Kind: scrapeRate, By: []string{"pod"}},
+	{ID: "35", Metric: "tempo_query_frontend_queries_total", Kind: scrapeRate},
+	{ID: "52", Metric: "go_memstats_heap_inuse_bytes", Kind: scrapeSum, By: []string{"pod", "container"}},
+	{ID: "53", Metric: "go_gc_duration_seconds_sum", Kind: scrapeRate, By: []string{"pod", "container"}},
+	{ID: "60", Metric: "go_memstats_next_gc_bytes", Kind: scrapeSum, By: []string{"pod", "container"}},
+	{ID: "61", Metric: "go_gc_duration_seconds_count", Kind: scrapeRate, By: []string{"pod", "container"}},
+	{ID: "63", Metric: "go_goroutines", Kind: scrapeSum, By: []string{"pod", "container"}},
+	{ID: "64", Metric: "tempo_metrics_generator_spans_received_total", Kind: scrapeRate},
+	{ID: "65", Metric: "tempo_metrics_generator_spans_discarded_total", Kind: scrapeRate, By: []string{"reason"}},
+	{ID: "66", Metric: "tempo_metrics_generator_registry_active_series", Kind: scrapeSum, By: []string{"tenant"}},
+	{ID: "67", Metric: "prometheus_remote_storage_samples_total", Kind: scrapeRate},
+	{ID: "68", Metric: "prometheus_remote_storage_samples_failed_total", Kind: scrapeRate},
+	{ID: "69", Metric: "tempo_distributor_metrics_generator_pushes_failures_total", Kind: scrapeRate},
+	{ID: "71", Metric: "tempo_receiver_accepted_spans", Kind: scrapeRate, By: []string{"pod"}},
+	{ID: "79", Metric: "tempo_distributor_bytes_received_total", Kind: scrapeRate, By: []string{"tenant"}},
+	{ID: "80", Metric: "tempo_distributor_spans_received_total", Kind: scrapeRate, By: []string{"tenant"}},
+	{ID: "81", Metric: "tempo_discarded_spans_total", Kind: scrapeRate, By: []string{"tenant", "reason"}},
+	{ID: "82", Metric: "tempo_query_frontend_queries_total", Kind: scrapeRate, By: []string{"tenant"}},
+	{ID: "83", Metric: "tempo_ingester_live_traces", Kind: scrapeSum, By: []string{"tenant"}},
+	{ID: "84", Metric: "tempodb_compaction_duration_seconds", Kind: scrapeQuantile, Quantile: 0.50},
+	{ID: "85", Metric: "tempodb_compaction_duration_seconds", Kind: scrapeQuantile, Quantile: 0.99},
+	{ID: "86", Metric: "tempodb_compaction_errors_total", Kind: scrapeRate},
+}
+
+// scrapedSample is a sample of a scraped metric, labeled with its pod and container
+type scrapedSample struct {
+	Name   string
+	Labels map[string]string
+	Value  float64
+}
+
+// MetricsScraper scrapes the /metrics endpoint of each Tempo pod of a namespace at
+// intervals, through the pod proxy of the API server. It is a fallback for clusters
+// where Prometheus does not scrape the namespace; its results use the query IDs and
+// metric names of the Prometheus queries so the dashboard charts them unchanged.
+type MetricsScraper struct {
+	client    kubernetes.Interface
+	namespace string
+	interval  time.Duration
+	// metrics are the names of the samples kept from a scrape
+	metrics map[string]bool
+
+	mu       sync.Mutex
+	series   map[string]*MetricResult
+	previous map[string]scrapedSample
+	lastTime time.Time
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// NewMetricsScraper creates a scraper for the Tempo pods of a namespace.
+// A non-positive interval uses DefaultScrapeInterval.
+func NewMetricsScraper(client kubernetes.Interface, namespace string, interval time.Duration) *MetricsScraper {
+	if interval <= 0 {
+		interval = DefaultScrapeInterval
+	}
+	metrics := make(map[string]bool)
+	for _, q := range scrapeQueries {
+		if q.Kind == scrapeQuantile {
+			metrics[q.Metric+"_bucket"] = true
+		} else {
+			metrics[q.Metric] = true
+		}
+	}
+	return &MetricsScraper{
+		client:    client,
+		namespace: namespace,
+		interval:  interval,
+		metrics:   metrics,
+		series:    make(map[string]*MetricResult),
+	}
+}
+
+// Start scrapes a first time and then scrapes in the background until Stop is
+// called or ctx is done. It returns an error if no Tempo pod can be scraped.
+func (s *MetricsScraper) Start(ctx context.Context) error {
+	if err := s.Scrape(ctx); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	s.cancel = cancel
+	s.done = make(chan struct{})
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.Scrape(ctx); err != nil && ctx.Err() == nil {
+					fmt.Printf("⚠️  Warning: failed to scrape Tempo metrics: %v\n", err)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops background scraping and waits for it to finish
+func (s *MetricsScraper) Stop() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	<-s.done
+}
+
+// Interval returns the scrape interval
+func (s *MetricsScraper) Interval() time.Duration {
+	return s.interval
+}
+
+// Scrape scrapes the running Tempo pods once and adds a point to the series of each
+// query. Pods that fail to be scraped are skipped; an error is returned when none
+// could be scraped.
+func (s *MetricsScraper) Scrape(ctx context.Context) error {
+	pods, err := s.client.CoreV1().Pods(s.namespace).List(ctx, metav1.ListOptions{LabelSelector: tempoPodSelector})
+	if err != nil {
+		return fmt.Errorf("failed to list Tempo pods: %w", err)
+	}
+
+	now := time.Now()
+	current := make(map[string]scrapedSample)
+	var (
+		scraped int
+		errs    []string
+	)
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		for _, target := range metricsTargets(&pod) {
+			data, err := s.client.CoreV1().Pods(s.namespace).ProxyGet("http", pod.Name, target.port, "metrics", nil).DoRaw(ctx)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%s/%s: %v", pod.Name, target.container, err))
+				continue
+			}
+			samples, err := parseMetrics(bytes.NewReader(data), s.metrics)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%s/%s: %v", pod.Name, target.container, err))
+				continue
+			}
+			scraped++
+			for _, sample := range samples {
+				sample.Labels["pod"] = pod.Name
+				sample.Labels["container"] = target.container
+				current[sample.Name+formatLabels(sample.Labels)] = sample
+			}
+		}
+	}
+	if scraped == 0 {
+		if len(errs) > 0 {
+			return fmt.Errorf("failed to scrape Tempo pods: %s", strings.Join(errs, "; "))
+		}
+		return fmt.Errorf("no running Tempo pods found")
+	}
+
+	s.mu.Lock()
+	s.evaluate(now, current)
+	s.previous, s.lastTime = current, now
+	s.mu.Unlock()
+	return nil
+}
+
+// metricsTarget is a container of a pod serving /metrics
+type metricsTarget struct {
+	container string
+	port      string
+}
+
+// metricsTargets returns the Tempo containers of a pod with an "http" port. The
+// container named tempo, whose port may be unnamed, uses tempoMetricsPort.
+func metricsTargets(pod *corev1.Pod) []metricsTarget {
+	var targets []metricsTarget
+	for _, c := range pod.Spec.Containers {
+		if !strings.HasPrefix(c.Name, "tempo") {
+			continue
+		}
+		port := ""
+		for _, p := range c.Ports {
+			if p.Name == "http" {
+				port = strconv.Itoa(int(p.ContainerPort))
+			}
+		}
+		if port == "" && c.Name == "tempo" {
+			port = tempoMetricsPort
+		}
+		if port != "" {
+			targets = append(targets, metricsTarget{container: c.Name, port: port})
+		}
+	}
+	return targets
+}
+
+// evaluate adds the points of a scrape at now to the series of each query. Rates and
+// quantiles are computed from the increase since the previous scrape, so the first
+// scrape only has the sums.
+func (s *MetricsScraper) evaluate(now time.Time, current map[string]scrapedSample) {
+	byName := make(map[string][]string)
+	for key, sample := range current {
+		byName[sample.Name] = append(byName[sample.Name], key)
+	}
+	elapsed := now.Sub(s.lastTime).Seconds()
+
+	for _, q := range scrapeQueries {
+		groups := make(map[string]map[string]string)
+		values := make(map[string]float64)
+		// Bucket increases of each group by upper bound, for quantiles
+		buckets := make(map[string]map[float64]float64)
+
+		name := q.Metric
+		if q.Kind == scrapeQuantile {
+			name += "_bucket"
+		}
+		for _, key := range byName[name] {
+			sample := current[key]
+			value := sample.Value
+			if q.Kind != scrapeSum {
+				prev, ok := s.previous[key]
+				if !ok || elapsed <= 0 {
+					continue
+				}
+				value = counterIncrease(prev.Value, sample.Value) / elapsed
+			}
+
+			labels := make(map[string]string, len(q.By))
+			for _, l := range q.By {
+				if v, ok := sample.Labels[l]; ok {
+					labels[l] = v
+				}
+			}
+			group := formatLabels(labels)
+			groups[group] = labels
+
+			if q.Kind == scrapeQuantile {
+				le, err := strconv.ParseFloat(sample.Labels["le"], 64)
+				if err != nil {
+					continue
+				}
+				if buckets[group] == nil {
+					buckets[group] = make(map[float64]float64)
+				}
+				buckets[group][le] += value
+				continue
+			}
+			values[group] += value
+		}
+
+		for group, labels := range groups {
+			value := values[group]
+			if q.Kind == scrapeQuantile {
+				v, ok := bucketQuantile(q.Quantile, buckets[group])
+				if !ok {
+					continue
+				}
+				value = v
+			}
+			key := q.ID + group
+			r, ok := s.series[key]
+			if !ok {
+				r = &MetricResult{QueryID: q.ID, Labels: labels}
+				s.series[key] = r
+			}
+			r.DataPoints = append(r.DataPoints, DataPoint{Timestamp: now, Value: value})
+		}
+	}
+}
+
+// counterIncrease returns the increase of a counter between two samples. A lower
+// value means the counter was reset, e.g. by a restart, and counts from zero.
+func counterIncrease(prev, value float64) float64 {
+	if value < prev {
+		return value
+	}
+	return value - prev
+}
+
+// bucketQuantile estimates the q-quantile of the cumulative counts of classic
+// histogram buckets by upper bound, like PromQL's histogram_quantile: linear
+// interpolation within the bucket holding the quantile, and the upper bound of the
+// last finite bucket for the +Inf bucket. It returns false if there are no
+// observations.
+func bucketQuantile(q float64, buckets map[float64]float64) (float64, bool) {
+	bounds := make([]float64, 0, len(buckets))
+	for le := range buckets {
+		bounds = append(bounds, le)
+	}
+	sort.Float64s(bounds)
+	if len(bounds) < 2 || !math.IsInf(bounds[len(bounds)-1], 1) || q < 0 || q > 1 {
+		return 0, false
+	}
+	total := buckets[bounds[len(bounds)-1]]
+	if total <= 0 {
+		return 0, false
+	}
+
+	rank := q * total
+	i := sort.Search(len(bounds), func(i int) bool { return buckets[bounds[i]] >= rank })
+	if i == len(bounds)-1 {
+		return bounds[len(bounds)-2], true
+	}
+	if i == 0 && bounds[0] <= 0 {
+		return bounds[0], true
+	}
+
+	lower, count := 0.0, buckets[bounds[i]]
+	if i > 0 {
+		lower = bounds[i-1]
+		rank -= buckets[bounds[i-1]]
+		count -= buckets[bounds[i-1]]
+	}
+	if count <= 0 {
+		return bounds[i], true
+	}
+	return lower + (bounds[i]-lower)*(rank/count), true
+}
+
+// HasSamples reports whether any series was computed
+func (s *MetricsScraper) HasSamples() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.series) > 0
+}
+
+// Results returns the scraped series as the results of the Prometheus queries with
+// the same IDs
+func (s *MetricsScraper) Results() []MetricResult {
+	queries := make(map[string]MetricQuery)
+	for _, q := range GetAllQueries(s.namespace) {
+		queries[q.ID] = q
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var results []MetricResult
+	for _, key := range sortedKeys(s.series) {
+		r := *s.series[key]
+		q := queries[r.QueryID]
+		r.MetricName = q.Name
+		r.Description = q.Description + " (scraped)"
+		r.Category = q.Category
+		r.DataPoints = append([]DataPoint(nil), r.DataPoints...)
+		results = append(results, r)
+	}
+	return results
+}
+
+// FillFromScrape replaces the results of the queries that returned no data, e.g.
+// because Prometheus does not scrape the namespace, with the scraped series of the
+// same queries. It returns the results and the number of queries filled.
+func FillFromScrape(results, scraped []MetricResult) ([]MetricResult, int) {
+	hasData := make(map[string]bool)
+	for _, r := range results {
+		if r.Error == nil && len(r.DataPoints) > 0 {
+			hasData[r.QueryID] = true
+		}
+	}
+	fill := make(map[string]bool)
+	for _, r := range scraped {
+		if !hasData[r.QueryID] {
+			fill[r.QueryID] = true
+		}
+	}
+	if len(fill) == 0 {
+		return results, 0
+	}
+
+	filled := make([]MetricResult, 0, len(results))
+	for _, r := range results {
+		if !fill[r.QueryID] {
+			filled = append(filled, r)
+		}
+	}
+	for _, r := range scraped {
+		if fill[r.QueryID] {
+			filled = append(filled, r)
+		}
+	}
+	return filled, len(fill)
+}
+
+// parseMetrics parses the samples of the metrics in names from the Prometheus text
+// exposition format. Timestamps are ignored.
+func parseMetrics(r io.Reader, names map[string]bool) ([]scrapedSample, error) {
+	var samples []scrapedSample
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		end := strings.IndexAny(line, "{ \t")
+		if end < 0 {
+			return nil, fmt.Errorf("invalid sample %q", line)
+		}
+		name := line[:end]
+		if !names[name] {
+			continue
+		}
+
+		rest := line[end:]
+		labels := make(map[string]string)
+		if rest[0] == '{' {
+			var err error
+			labels, rest, err = parseLabels(rest[1:])
+			if err != nil {
+				return nil, fmt.Errorf("invalid sample %q: %w", line, err)
+			}
+		}
+
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("invalid sample %q: no value", line)
+		}
+		value, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sample %q: %w", line, err)
+		}
+		samples = append(samples, scrapedSample{Name: name, Labels: labels, Value: value})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read metrics: %w", err)
+	}
+	return samples, nil
+}
+
+// parseLabels parses the label pairs after the opening brace of a sample, returning
+// the labels and the rest of the line after the closing brace
+func parseLabels(s string) (map[string]string, string, error) {
+	labels := make(map[string]string)
+	for {
+		s = strings.TrimLeft(s, " \t,")
+		if s == "" {
+			return nil, "", fmt.Errorf("unterminated labels")
+		}
+		if s[0] == '}' {
+			return labels, s[1:], nil
+		}
+
+		eq := strings.IndexByte(s, '=')
+		if eq < 0 || len(s) < eq+2 || s[eq+1] != '"' {
+			return nil, "", fmt.Errorf("invalid label")
+		}
+		name := strings.TrimSpace(s[:eq])
+
+		var value strings.Builder
+		i := eq + 2
+		for ; i < len(s) && s[i] != '"'; i++ {
+			if s[i] == '\\' && i+1 < len(s) {
+				i++
+				switch s[i] {
+				case 'n':
+					value.WriteByte('\n')
+				default:
+					value.WriteByte(s[i])
+				}
+				continue
+			}
+			value.WriteByte(s[i])
+		}
+		if i >= len(s) {
+			return nil, "", fmt.Errorf("unterminated label value")
+		}
+		labels[name] = value.String()
+		s = s[i+1:]
+	}
+}
@@ -0,0 +1,237 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// fakeProxyResponse is the response of a fake pod proxy request
+type fakeProxyResponse struct {
+	body string
+}
+
+func (r fakeProxyResponse) DoRaw(context.Context) ([]byte, error) { return []byte(r.body), nil }
+func (r fakeProxyResponse) Stream(context.Context) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader(r.body)), nil
+}
+
+func TestParseMetrics(t *testing.T) {
+	input := `# HELP tempo_distributor_bytes_received_total The total number of proto bytes received
+# TYPE tempo_distributor_bytes_received_total counter
+tempo_distributor_bytes_received_total{status="success",tenant="single-tenant"} 1.5e+06
+tempo_distributor_bytes_received_total{status="error\"quoted\\",tenant="single-tenant"} 3 1700000000000
+tempo_distributor_push_duration_seconds_bucket{le="+Inf"} 42
+go_goroutines 120
+unused_metric{a="b"} 1
+`
+	names := map[string]bool{
+		"tempo_distributor_bytes_received_total":         true,
+		"tempo_distributor_push_duration_seconds_bucket": true,
+		"go_goroutines": true,
+	}
+	samples, err := parseMetrics(strings.NewReader(input), names)
+	if err != nil {
+		t.Fatalf("parseMetrics() error = %v", err)
+	}
+	if len(samples) != 4 {
+		t.Fatalf("got %d samples, want 4: %+v", len(samples), samples)
+	}
+	if samples[0].Value != 1.5e6 || samples[0].Labels["status"] != "success" {
+		t.Errorf("samples[0] = %+v", samples[0])
+	}
+	if samples[1].Labels["status"] != `error"quoted\` || samples[1].Value != 3 {
+		t.Errorf("samples[1] = %+v, want the escaped label and the value before the timestamp", samples[1])
+	}
+	if samples[2].Labels["le"] != "+Inf" {
+		t.Errorf("samples[2] = %+v", samples[2])
+	}
+	if samples[3].Name != "go_goroutines" || len(samples[3].Labels) != 0 || samples[3].Value != 120 {
+		t.Errorf("samples[3] = %+v", samples[3])
+	}
+
+	if _, err := parseMetrics(strings.NewReader(`go_goroutines{pod="x 1`), names); err == nil {
+		t.Error("expected an error for unterminated labels")
+	}
+}
+
+func TestBucketQuantile(t *testing.T) {
+	buckets := map[float64]float64{0.1: 50, 0.5: 90, 1: 100, math.Inf(1): 100}
+	tests := []struct {
+		q    float64
+		want float64
+	}{
+		{0.5, 0.1},
+		{0.7, 0.3},
+		{0.95, 0.75},
+	}
+	for _, tt := range tests {
+		got, ok := bucketQuantile(tt.q, buckets)
+		if !ok || math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("bucketQuantile(%v) = %v, %v, want %v", tt.q, got, ok, tt.want)
+		}
+	}
+
+	// Observations above the last finite bucket return its upper bound
+	if got, ok := bucketQuantile(0.99, map[float64]float64{1: 10, math.Inf(1): 100}); !ok || got != 1 {
+		t.Errorf("bucketQuantile() in +Inf = %v, %v, want 1", got, ok)
+	}
+	if _, ok := bucketQuantile(0.99, map[float64]float64{1: 0, math.Inf(1): 0}); ok {
+		t.Error("expected no quantile without observations")
+	}
+}
+
+func TestMetricsScraper_Evaluate(t *testing.T) {
+	s := NewMetricsScraper(nil, "perf", 0)
+	start := time.Unix(1700000000, 0)
+	sample := func(name, pod string, value float64, labels ...string) (string, scrapedSample) {
+		l := map[string]string{"pod": pod, "container": "tempo"}
+		for i := 0; i+1 < len(labels); i += 2 {
+			l[labels[i]] = labels[i+1]
+		}
+		return name + formatLabels(l), scrapedSample{Name: name, Labels: l, Value: value}
+	}
+	scrape := func(at time.Time, samples ...func() (string, scrapedSample)) {
+		current := make(map[string]scrapedSample)
+		for _, f := range samples {
+			key, s := f()
+			current[key] = s
+		}
+		s.evaluate(at, current)
+		s.previous, s.lastTime = current, at
+	}
+	at := func(name, pod string, value float64, labels ...string) func() (string, scrapedSample) {
+		return func() (string, scrapedSample) { return sample(name, pod, value, labels...) }
+	}
+
+	scrape(start,
+		at("tempo_receiver_accepted_spans", "tempo-distributor-a", 1000),
+		at("tempo_receiver_accepted_spans", "tempo-distributor-b", 2000),
+		at("tempo_ingester_live_traces", "tempo-ingester-0", 7),
+		at("tempo_distributor_push_duration_seconds_bucket", "tempo-distributor-a", 0, "le", "0.1"),
+		at("tempo_distributor_push_duration_seconds_bucket", "tempo-distributor-a", 0, "le", "+Inf"),
+	)
+	scrape(start.Add(10*time.Second),
+		at("tempo_receiver_accepted_spans", "tempo-distributor-a", 1500),
+		// Restarted: counts from zero
+		at("tempo_receiver_accepted_spans", "tempo-distributor-b", 300),
+		at("tempo_ingester_live_traces", "tempo-ingester-0", 9),
+		at("tempo_distributor_push_duration_seconds_bucket", "tempo-distributor-a", 100, "le", "0.1"),
+		at("tempo_distributor_push_duration_seconds_bucket", "tempo-distributor-a", 100, "le", "+Inf"),
+	)
+
+	results := s.Results()
+	byID := make(map[string][]MetricResult)
+	for _, r := range results {
+		byID[r.QueryID] = append(byID[r.QueryID], r)
+	}
+
+	accepted := byID["1"]
+	if len(accepted) != 1 || len(accepted[0].DataPoints) != 1 || accepted[0].DataPoints[0].Value != 80 {
+		t.Errorf("accepted_spans_rate = %+v, want one point of (500+300)/10s", accepted)
+	}
+	if accepted[0].MetricName != "accepted_spans_rate" || accepted[0].Category == "" {
+		t.Errorf("accepted_spans_rate has name %q and category %q", accepted[0].MetricName, accepted[0].Category)
+	}
+	byPod := byID["71"]
+	if len(byPod) != 2 || byPod[0].Labels["pod"] != "tempo-distributor-a" || byPod[0].DataPoints[0].Value != 50 {
+		t.Errorf("accepted_spans_rate_by_pod = %+v", byPod)
+	}
+
+	live := byID["7"]
+	if len(live) != 1 || len(live[0].DataPoints) != 2 || live[0].DataPoints[1].Value != 9 {
+		t.Errorf("ingester_live_traces = %+v, want a point per scrape", live)
+	}
+
+	p99 := byID["4"]
+	if len(p99) != 1 || len(p99[0].DataPoints) != 1 || math.Abs(p99[0].DataPoints[0].Value-0.099) > 1e-9 {
+		t.Errorf("distributor_push_duration_p99 = %+v, want 0.099", p99)
+	}
+}
+
+func TestMetricsScraper_Scrape(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "tempo-tempo-0", Namespace: "perf",
+			Labels: map[string]string{"app.kubernetes.io/managed-by": "tempo-operator"},
+		},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{
+			{Name: "tempo", Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 3200}}},
+			{Name: "oauth-proxy", Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 8080}}},
+		}},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	client := fake.NewSimpleClientset(pod)
+	var proxied []string
+	client.PrependProxyReactor("pods", func(action k8stesting.Action) (bool, rest.ResponseWrapper, error) {
+		get := action.(k8stesting.ProxyGetAction)
+		proxied = append(proxied, get.GetName()+":"+get.GetPort()+"/"+get.GetPath())
+		return true, fakeProxyResponse{body: "go_goroutines 120\ntempo_ingester_live_traces 7\n"}, nil
+	})
+
+	s := NewMetricsScraper(client, "perf", 0)
+	if err := s.Scrape(context.Background()); err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+	if len(proxied) != 1 || proxied[0] != "tempo-tempo-0:3200/metrics" {
+		t.Errorf("proxied %v, want only the tempo container", proxied)
+	}
+
+	results := s.Results()
+	if len(results) != 3 {
+		t.Fatalf("got %d series, want the goroutines and the live traces by pod and by tenant: %+v", len(results), results)
+	}
+	for _, r := range results {
+		if r.QueryID == "63" && (r.Labels["pod"] != "tempo-tempo-0" || r.Labels["container"] != "tempo" || r.DataPoints[0].Value != 120) {
+			t.Errorf("go_goroutines = %+v", r)
+		}
+		if r.QueryID == "83" && r.DataPoints[0].Value != 7 {
+			t.Errorf("tenant_live_traces = %+v", r)
+		}
+	}
+}
+
+func TestMetricsScraper_NoPods(t *testing.T) {
+	s := NewMetricsScraper(fake.NewSimpleClientset(), "perf", 0)
+	if err := s.Start(context.Background()); err == nil {
+		t.Error("expected an error without Tempo pods")
+	}
+}
+
+func TestFillFromScrape(t *testing.T) {
+	results := []MetricResult{
+		{QueryID: "1", MetricName: "accepted_spans_rate", DataPoints: []DataPoint{{Value: 1}}},
+		{QueryID: "7", MetricName: "ingester_live_traces", Error: ErrNoData},
+		{QueryID: "21", MetricName: "memory_usage_total", DataPoints: []DataPoint{{Value: 1}}},
+	}
+	scraped := []MetricResult{
+		{QueryID: "1", MetricName: "accepted_spans_rate", DataPoints: []DataPoint{{Value: 2}}},
+		{QueryID: "7", MetricName: "ingester_live_traces", Labels: map[string]string{"pod": "a"}, DataPoints: []DataPoint{{Value: 3}}},
+		{QueryID: "7", MetricName: "ingester_live_traces", Labels: map[string]string{"pod": "b"}, DataPoints: []DataPoint{{Value: 4}}},
+	}
+
+	got, filled := FillFromScrape(results, scraped)
+	if filled != 1 {
+		t.Errorf("filled = %d, want 1", filled)
+	}
+	if len(got) != 4 {
+		t.Fatalf("got %d results, want 4: %+v", len(got), got)
+	}
+	if got[0].DataPoints[0].Value != 1 {
+		t.Errorf("the Prometheus result of query 1 was replaced: %+v", got[0])
+	}
+	for _, r := range got {
+		if r.QueryID == "7" && r.Error != nil {
+			t.Errorf("the empty result of query 7 was kept: %+v", r)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// DefaultStorageSamplingInterval is the interval at which StorageSampler measures
+// the trace storage
+const DefaultStorageSamplingInterval = time.Minute
+
+// Query IDs and metric names of the storage footprint series
+const (
+	storageBytesQueryID   = "77"
+	storageObjectsQueryID = "78"
+
+	StorageBytesMetric   = "storage_footprint_bytes"
+	StorageObjectsMetric = "storage_footprint_objects"
+)
+
+// MinIO metrics endpoints reporting bucket usage, tried in order: bucket metrics
+// moved out of the cluster endpoint in recent releases
+var minioMetricsPaths = []string{"minio/v2/metrics/bucket", "minio/v2/metrics/cluster"}
+
+// minioPodSelector and minioPort select the S3 API of the MinIO servers deployed
+// by the framework
+const (
+	minioPodSelector = "app.kubernetes.io/name=minio"
+	minioPort        = "9000"
+)
+
+// StorageSamplerProvider optionally provides a StorageSampler whose samples are
+// exported for the storage footprint queries
+type StorageSamplerProvider interface {
+	StorageSampler() *StorageSampler
+}
+
+// StorageUsage is the size of the trace storage at a point in time
+type StorageUsage struct {
+	Bytes   float64
+	Objects float64
+}
+
+// StorageSource measures the trace storage, e.g. the bucket of Tempo
+type StorageSource interface {
+	StorageUsage(ctx context.Context) (*StorageUsage, error)
+}
+
+// StorageSampler measures the bytes and objects in the trace storage at intervals,
+// so the storage footprint of a run can be related to the bytes it ingested. Its
+// results use the IDs and names of the storage footprint queries, which only have
+// data in Prometheus when it scrapes the object storage.
+type StorageSampler struct {
+	source   StorageSource
+	interval time.Duration
+
+	mu      sync.Mutex
+	samples []storageSample
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// storageSample is the usage of the trace storage at a measurement
+type storageSample struct {
+	Time  time.Time
+	Usage StorageUsage
+}
+
+// NewStorageSampler creates a sampler of a trace storage.
+// A non-positive interval uses DefaultStorageSamplingInterval.
+func NewStorageSampler(source StorageSource, interval time.Duration) *StorageSampler {
+	if interval <= 0 {
+		interval = DefaultStorageSamplingInterval
+	}
+	return &StorageSampler{source: source, interval: interval}
+}
+
+// Start takes a first sample and then samples in the background until Stop is
+// called or ctx is done. It returns an error if the storage cannot be measured.
+func (s *StorageSampler) Start(ctx context.Context) error {
+	if err := s.Sample(ctx); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	s.cancel = cancel
+	s.done = make(chan struct{})
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.Sample(ctx); err != nil && ctx.Err() == nil {
+					fmt.Printf("⚠️  Warning: failed to measure the trace storage: %v\n", err)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops background sampling and waits for it to finish
+func (s *StorageSampler) Stop() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	<-s.done
+}
+
+// Interval returns the sampling interval
+func (s *StorageSampler) Interval() time.Duration {
+	return s.interval
+}
+
+// Sample measures the trace storage once
+func (s *StorageSampler) Sample(ctx context.Context) error {
+	usage, err := s.source.StorageUsage(ctx)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.samples = append(s.samples, storageSample{Time: time.Now(), Usage: *usage})
+	s.mu.Unlock()
+	return nil
+}
+
+// HasSamples reports whether any sample was taken
+func (s *StorageSampler) HasSamples() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.samples) > 0
+}
+
+// Results returns the samples as the series of the storage footprint queries
+func (s *StorageSampler) Results() []MetricResult {
+	s.mu.Lock()
+	samples := append([]storageSample(nil), s.samples...)
+	s.mu.Unlock()
+	if len(samples) == 0 {
+		return nil
+	}
+
+	series := func(id, name, description string, value func(StorageUsage) float64) MetricResult {
+		r := MetricResult{
+			QueryID:     id,
+			MetricName:  name,
+			Description: description,
+			Category:    "storage",
+			Labels:      map[string]string{},
+		}
+		for _, sample := range samples {
+			r.DataPoints = append(r.DataPoints, DataPoint{Timestamp: sample.Time, Value: value(sample.Usage)})
+		}
+		return r
+	}
+	return []MetricResult{
+		series(storageBytesQueryID, StorageBytesMetric, "Bytes stored in the trace storage (sampled)",
+			func(u StorageUsage) float64 { return u.Bytes }),
+		series(storageObjectsQueryID, StorageObjectsMetric, "Objects stored in the trace storage (sampled)",
+			func(u StorageUsage) float64 { return u.Objects }),
+	}
+}
+
+// MinIOStorageSource measures the buckets of the MinIO deployed in a namespace from
+// the bucket usage metrics of a MinIO server, read through the pod proxy of the API
+// server. MinIO computes the usage in a background scan, so it lags writes by up
+// to a scan cycle.
+type MinIOStorageSource struct {
+	client    kubernetes.Interface
+	namespace string
+}
+
+// NewMinIOStorageSource creates a source for the MinIO of a namespace, whose
+// metrics endpoints must not require authentication
+func NewMinIOStorageSource(client kubernetes.Interface, namespace string) *MinIOStorageSource {
+	return &MinIOStorageSource{client: client, namespace: namespace}
+}
+
+// StorageUsage returns the total usage of the buckets. Every MinIO server reports
+// the usage of the whole cluster, so the first server that answers is used.
+func (m *MinIOStorageSource) StorageUsage(ctx context.Context) (*StorageUsage, error) {
+	pods, err := m.client.CoreV1().Pods(m.namespace).List(ctx, metav1.ListOptions{LabelSelector: minioPodSelector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list MinIO pods: %w", err)
+	}
+
+	var errs []string
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		for _, path := range minioMetricsPaths {
+			data, err := m.client.CoreV1().Pods(m.namespace).ProxyGet("http", pod.Name, minioPort, path, nil).DoRaw(ctx)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%s/%s: %v", pod.Name, path, err))
+				continue
+			}
+			usage, err := parseMinIOUsage(data)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%s/%s: %v", pod.Name, path, err))
+				continue
+			}
+			return usage, nil
+		}
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("failed to read MinIO bucket usage: %s", strings.Join(errs, "; "))
+	}
+	return nil, fmt.Errorf("no running MinIO pods found")
+}
+
+// parseMinIOUsage sums the bucket usage metrics of a MinIO metrics page. Before its
+// first scan MinIO reports no usage, which is zero.
+func parseMinIOUsage(data []byte) (*StorageUsage, error) {
+	samples, err := parseMetrics(bytes.NewReader(data), map[string]bool{
+		"minio_bucket_usage_total_bytes":  true,
+		"minio_bucket_usage_object_total": true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	usage := &StorageUsage{}
+	for _, sample := range samples {
+		if sample.Name == "minio_bucket_usage_total_bytes" {
+			usage.Bytes += sample.Value
+		} else {
+			usage.Objects += sample.Value
+		}
+	}
+	return usage, nil
+}
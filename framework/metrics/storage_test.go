@@ -0,0 +1,107 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// fakeStorageSource returns its usages in order, then fails
+type fakeStorageSource struct {
+	usages []StorageUsage
+}
+
+func (f *fakeStorageSource) StorageUsage(ctx context.Context) (*StorageUsage, error) {
+	if len(f.usages) == 0 {
+		return nil, errors.New("storage unavailable")
+	}
+	usage := f.usages[0]
+	f.usages = f.usages[1:]
+	return &usage, nil
+}
+
+func TestParseMinIOUsage(t *testing.T) {
+	page := `# HELP minio_bucket_usage_total_bytes Total bucket size in bytes
+# TYPE minio_bucket_usage_total_bytes gauge
+minio_bucket_usage_total_bytes{bucket="tempo",server="minio-0:9000"} 1.5e+06
+minio_bucket_usage_total_bytes{bucket="other",server="minio-0:9000"} 500000
+minio_bucket_usage_object_total{bucket="tempo",server="minio-0:9000"} 42
+minio_bucket_usage_version_total{bucket="tempo",server="minio-0:9000"} 43
+`
+	usage, err := parseMinIOUsage([]byte(page))
+	if err != nil {
+		t.Fatalf("parseMinIOUsage() error = %v", err)
+	}
+	if want := (StorageUsage{Bytes: 2e6, Objects: 42}); *usage != want {
+		t.Errorf("parseMinIOUsage() = %+v, want %+v", *usage, want)
+	}
+}
+
+func TestParseMinIOUsage_BeforeScan(t *testing.T) {
+	usage, err := parseMinIOUsage([]byte("minio_cluster_nodes_online_total 1\n"))
+	if err != nil {
+		t.Fatalf("parseMinIOUsage() error = %v", err)
+	}
+	if *usage != (StorageUsage{}) {
+		t.Errorf("parseMinIOUsage() = %+v, want zero usage", *usage)
+	}
+}
+
+func TestStorageSampler_Results(t *testing.T) {
+	source := &fakeStorageSource{usages: []StorageUsage{{Bytes: 0, Objects: 0}, {Bytes: 4096, Objects: 3}}}
+	s := NewStorageSampler(source, 0)
+	if s.Interval() != DefaultStorageSamplingInterval {
+		t.Errorf("Interval() = %s, want %s", s.Interval(), DefaultStorageSamplingInterval)
+	}
+	if s.HasSamples() || s.Results() != nil {
+		t.Error("expected no results before sampling")
+	}
+	for i := 0; i < 2; i++ {
+		if err := s.Sample(context.Background()); err != nil {
+			t.Fatalf("Sample() error = %v", err)
+		}
+	}
+	if err := s.Sample(context.Background()); err == nil {
+		t.Error("expected the error of the source")
+	}
+
+	results := s.Results()
+	if len(results) != 2 {
+		t.Fatalf("expected 2 series, got %d", len(results))
+	}
+	want := map[string][]float64{
+		StorageBytesMetric:   {0, 4096},
+		StorageObjectsMetric: {0, 3},
+	}
+	for _, r := range results {
+		if r.Category != "storage" {
+			t.Errorf("%s: category = %q, want storage", r.MetricName, r.Category)
+		}
+		values := want[r.MetricName]
+		if len(r.DataPoints) != len(values) {
+			t.Fatalf("%s: expected %d points, got %d", r.MetricName, len(values), len(r.DataPoints))
+		}
+		for i, dp := range r.DataPoints {
+			if dp.Value != values[i] {
+				t.Errorf("%s[%d] = %v, want %v", r.MetricName, i, dp.Value, values[i])
+			}
+		}
+	}
+}
+
+func TestStorageSampler_QueryIDs(t *testing.T) {
+	s := NewStorageSampler(&fakeStorageSource{usages: []StorageUsage{{Bytes: 1}}}, 0)
+	if err := s.Sample(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	queries := make(map[string]MetricQuery)
+	for _, q := range GetAllQueries("perf") {
+		queries[q.ID] = q
+	}
+	for _, r := range s.Results() {
+		q, ok := queries[r.QueryID]
+		if !ok || q.Name != r.MetricName || q.Category != r.Category {
+			t.Errorf("series %s (query %s) does not match its Prometheus query %+v", r.MetricName, r.QueryID, q)
+		}
+	}
+}
@@ -4,16 +4,17 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
-	"time"
 
 	"github.com/redhat/perf-tests-tempo/test/framework/wait"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes"
 )
@@ -67,185 +68,514 @@ func buildNodeAntiAffinity(nodeSelector map[string]string) *corev1.NodeAffinity
 
 // Config holds MinIO configuration options
 type Config struct {
-	// StorageSize is the PVC size for MinIO (e.g., "10Gi")
+	// StorageSize is the PVC size of each MinIO server (e.g., "10Gi")
 	// Default: "2Gi"
 	StorageSize string
+
+	// Replicas is the number of MinIO servers: 1 runs a single server as a
+	// Deployment, 4 to 16 run distributed MinIO as a StatefulSet, erasure coding
+	// the objects over one PVC per server.
+	// Default: 1
+	Replicas int
+
+	// StorageClass is the StorageClass of the MinIO PVCs.
+	// If empty, the default StorageClass of the cluster is used.
+	StorageClass string
+
+	// Resources are the resource requirements of the MinIO containers (optional)
+	Resources *corev1.ResourceRequirements
 }
 
 // DefaultStorageSize is the default PVC size for MinIO
 const DefaultStorageSize = "2Gi"
 
-// Setup deploys MinIO with PVC and waits for it to be ready
+// Replica bounds of distributed MinIO, which needs at least 4 drives for erasure
+// coding and supports erasure sets of up to 16 drives
+const (
+	MinDistributedReplicas = 4
+	MaxDistributedReplicas = 16
+)
+
+// Names of the MinIO objects
+const (
+	name            = "minio"
+	headlessService = "minio-hl"
+	bucketJob       = "minio-make-bucket"
+	bucket          = "tempo"
+	image           = "quay.io/minio/minio:latest"
+	mcImage         = "quay.io/minio/mc:latest"
+	accessKey       = "tempo"
+	secretKey       = "supersecret"
+)
+
+// ValidateReplicas checks that replicas is a supported number of MinIO servers
+func ValidateReplicas(replicas int) error {
+	if replicas == 0 || replicas == 1 {
+		return nil
+	}
+	if replicas < MinDistributedReplicas || replicas > MaxDistributedReplicas {
+		return fmt.Errorf("MinIO replicas must be 1 or between %d and %d for distributed MinIO, got %d",
+			MinDistributedReplicas, MaxDistributedReplicas, replicas)
+	}
+	return nil
+}
+
+// Setup deploys MinIO with PVC and waits for it to be ready.
+// With more than one replica, it deploys distributed MinIO and creates the bucket.
 // Note: EnsureNamespace should be called before this function
 func Setup(c Clients, config *Config) error {
 	namespace := c.Namespace()
 	client := c.Client()
 	ctx := c.Context()
 
-	// Determine storage size
-	storageSize := DefaultStorageSize
-	if config != nil && config.StorageSize != "" {
-		storageSize = config.StorageSize
+	cfg, err := resolveConfig(config)
+	if err != nil {
+		return err
 	}
 
-	fmt.Printf("📦 Setting up MinIO with %s storage\n", storageSize)
+	if cfg.Replicas == 1 {
+		fmt.Printf("📦 Setting up MinIO with %s storage\n", cfg.StorageSize)
+	} else {
+		fmt.Printf("📦 Setting up distributed MinIO with %d servers of %s storage\n", cfg.Replicas, cfg.StorageSize)
+	}
 
-	// Create PVC
-	pvc := &corev1.PersistentVolumeClaim{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      "minio",
-			Namespace: namespace,
-			Labels: map[string]string{
-				"app.kubernetes.io/name": "minio",
-			},
-		},
-		Spec: corev1.PersistentVolumeClaimSpec{
-			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
-			Resources: corev1.VolumeResourceRequirements{
-				Requests: corev1.ResourceList{
-					corev1.ResourceStorage: resource.MustParse(storageSize),
-				},
-			},
-		},
+	// Create Secret
+	_, err = client.CoreV1().Secrets(namespace).Create(ctx, buildSecret(namespace), metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create MinIO secret: %w", err)
 	}
 
-	_, err := client.CoreV1().PersistentVolumeClaims(namespace).Create(ctx, pvc, metav1.CreateOptions{})
+	if cfg.Replicas == 1 {
+		err = setupSingle(c, cfg)
+	} else {
+		err = setupDistributed(c, cfg)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Create Service
+	_, err = client.CoreV1().Services(namespace).Create(ctx, buildService(namespace), metav1.CreateOptions{})
 	if err != nil && !apierrors.IsAlreadyExists(err) {
-		return fmt.Errorf("failed to create MinIO PVC: %w", err)
+		return fmt.Errorf("failed to create MinIO service: %w", err)
 	}
 
-	// Create Secret
-	secret := &corev1.Secret{
+	// Wait for MinIO to be ready
+	selector, err := labels.Parse("app.kubernetes.io/name=minio")
+	if err != nil {
+		return fmt.Errorf("failed to parse selector: %w", err)
+	}
+
+	// The timeout is the PodReadyTimeout of the framework configuration
+	if err := wait.ForPodsReady(c, selector, 0, cfg.Replicas); err != nil {
+		return err
+	}
+
+	// A single server creates the bucket as a directory of its volume; distributed
+	// MinIO erasure codes its volumes, so the bucket is created through the S3 API
+	if cfg.Replicas > 1 {
+		return createBucket(c)
+	}
+	return nil
+}
+
+// Manifests returns the objects Setup creates in namespace, in the order they are
+// created, without touching the cluster
+func Manifests(namespace string, tempoNodeSelector map[string]string, config *Config) ([]runtime.Object, error) {
+	cfg, err := resolveConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := []runtime.Object{buildSecret(namespace)}
+	if cfg.Replicas == 1 {
+		objects = append(objects,
+			buildPVC(namespace, cfg),
+			buildDeployment(namespace, tempoNodeSelector, cfg))
+	} else {
+		objects = append(objects,
+			buildHeadlessService(namespace),
+			buildStatefulSet(namespace, tempoNodeSelector, cfg))
+	}
+	objects = append(objects, buildService(namespace))
+	if cfg.Replicas > 1 {
+		objects = append(objects, buildBucketJob(namespace, tempoNodeSelector))
+	}
+	return objects, nil
+}
+
+// resolveConfig applies the defaults to config and validates it
+func resolveConfig(config *Config) (*Config, error) {
+	cfg := &Config{StorageSize: DefaultStorageSize, Replicas: 1}
+	if config != nil {
+		if config.StorageSize != "" {
+			cfg.StorageSize = config.StorageSize
+		}
+		if config.Replicas > 0 {
+			cfg.Replicas = config.Replicas
+		}
+		cfg.StorageClass = config.StorageClass
+		cfg.Resources = config.Resources
+	}
+	if err := ValidateReplicas(cfg.Replicas); err != nil {
+		return nil, err
+	}
+	if _, err := resource.ParseQuantity(cfg.StorageSize); err != nil {
+		return nil, fmt.Errorf("invalid MinIO storage size %q: %w", cfg.StorageSize, err)
+	}
+	return cfg, nil
+}
+
+// buildSecret returns the Secret with the MinIO endpoint and credentials Tempo uses
+func buildSecret(namespace string) *corev1.Secret {
+	return &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "minio",
+			Name:      name,
 			Namespace: namespace,
 		},
 		StringData: map[string]string{
 			"endpoint":          fmt.Sprintf("http://minio.%s.svc.cluster.local:9000", namespace),
-			"bucket":            "tempo",
-			"access_key_id":     "tempo",
-			"access_key_secret": "supersecret",
+			"bucket":            bucket,
+			"access_key_id":     accessKey,
+			"access_key_secret": secretKey,
 		},
 		Type: corev1.SecretTypeOpaque,
 	}
+}
+
+// buildService returns the Service of the MinIO S3 API
+func buildService(namespace string) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: corev1.ServiceSpec{
+			Ports:    servicePorts(),
+			Selector: podLabels(),
+			Type:     corev1.ServiceTypeClusterIP,
+		},
+	}
+}
+
+// setupSingle creates the PVC and the Deployment of a single MinIO server
+func setupSingle(c Clients, cfg *Config) error {
+	namespace := c.Namespace()
+	client := c.Client()
+	ctx := c.Context()
 
-	_, err = client.CoreV1().Secrets(namespace).Create(ctx, secret, metav1.CreateOptions{})
+	// Create PVC
+	_, err := client.CoreV1().PersistentVolumeClaims(namespace).Create(ctx, buildPVC(namespace, cfg), metav1.CreateOptions{})
 	if err != nil && !apierrors.IsAlreadyExists(err) {
-		return fmt.Errorf("failed to create MinIO secret: %w", err)
+		return fmt.Errorf("failed to create MinIO PVC: %w", err)
 	}
 
 	// Create Deployment
-	deployment := &appsv1.Deployment{
+	deployment := buildDeployment(namespace, c.GetTempoNodeSelector(), cfg)
+	_, err = client.AppsV1().Deployments(namespace).Create(ctx, deployment, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create MinIO deployment: %w", err)
+	}
+	return nil
+}
+
+// buildPVC returns the PVC of a single MinIO server
+func buildPVC(namespace string, cfg *Config) *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "minio",
+			Name:      name,
+			Namespace: namespace,
+			Labels:    podLabels(),
+		},
+		Spec: pvcSpec(cfg),
+	}
+}
+
+// buildDeployment returns the Deployment of a single MinIO server
+func buildDeployment(namespace string, tempoNodeSelector map[string]string, cfg *Config) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
 			Namespace: namespace,
 		},
 		Spec: appsv1.DeploymentSpec{
 			Selector: &metav1.LabelSelector{
-				MatchLabels: map[string]string{
-					"app.kubernetes.io/name": "minio",
-				},
+				MatchLabels: podLabels(),
 			},
 			Strategy: appsv1.DeploymentStrategy{
 				Type: appsv1.RecreateDeploymentStrategyType,
 			},
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: map[string]string{
-						"app.kubernetes.io/name": "minio",
-					},
+					Labels: podLabels(),
 				},
 				Spec: corev1.PodSpec{
 					Containers: []corev1.Container{
-						{
-							Name:  "minio",
-							Image: "quay.io/minio/minio:latest",
-							Command: []string{
-								"/bin/sh",
-								"-c",
-								"mkdir -p /storage/tempo && minio server /storage",
-							},
-							Env: []corev1.EnvVar{
-								{
-									Name:  "MINIO_ACCESS_KEY",
-									Value: "tempo",
-								},
-								{
-									Name:  "MINIO_SECRET_KEY",
-									Value: "supersecret",
-								},
-							},
-							Ports: []corev1.ContainerPort{
-								{
-									ContainerPort: 9000,
-								},
-							},
-							VolumeMounts: []corev1.VolumeMount{
-								{
-									Name:      "storage",
-									MountPath: "/storage",
-								},
-							},
-						},
+						container(cfg, []string{
+							"/bin/sh",
+							"-c",
+							"mkdir -p /storage/" + bucket + " && minio server /storage",
+						}),
 					},
 					Volumes: []corev1.Volume{
 						{
 							Name: "storage",
 							VolumeSource: corev1.VolumeSource{
 								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
-									ClaimName: "minio",
+									ClaimName: name,
 								},
 							},
 						},
 					},
+					Affinity: affinity(tempoNodeSelector, false),
 				},
 			},
 		},
 	}
+}
 
-	// Apply anti-affinity to avoid Tempo nodes if node selector is set
-	if nodeSelector := c.GetTempoNodeSelector(); len(nodeSelector) > 0 {
-		deployment.Spec.Template.Spec.Affinity = &corev1.Affinity{
-			NodeAffinity: buildNodeAntiAffinity(nodeSelector),
-		}
+// setupDistributed creates the headless Service and the StatefulSet of distributed
+// MinIO, with one PVC per server
+func setupDistributed(c Clients, cfg *Config) error {
+	namespace := c.Namespace()
+	client := c.Client()
+	ctx := c.Context()
+
+	_, err := client.CoreV1().Services(namespace).Create(ctx, buildHeadlessService(namespace), metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create MinIO headless service: %w", err)
 	}
 
-	_, err = client.AppsV1().Deployments(namespace).Create(ctx, deployment, metav1.CreateOptions{})
+	statefulSet := buildStatefulSet(namespace, c.GetTempoNodeSelector(), cfg)
+	_, err = client.AppsV1().StatefulSets(namespace).Create(ctx, statefulSet, metav1.CreateOptions{})
 	if err != nil && !apierrors.IsAlreadyExists(err) {
-		return fmt.Errorf("failed to create MinIO deployment: %w", err)
+		return fmt.Errorf("failed to create MinIO statefulset: %w", err)
 	}
+	return nil
+}
 
-	// Create Service
-	service := &corev1.Service{
+// buildHeadlessService returns the headless Service that gives each server the DNS
+// name its peers connect to; the names must resolve before the servers are ready,
+// as they only become ready once they have formed the cluster
+func buildHeadlessService(namespace string) *corev1.Service {
+	return &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "minio",
+			Name:      headlessService,
 			Namespace: namespace,
 		},
 		Spec: corev1.ServiceSpec{
-			Ports: []corev1.ServicePort{
-				{
-					Port:       9000,
-					Protocol:   corev1.ProtocolTCP,
-					TargetPort: intstr.FromInt32(9000),
+			ClusterIP:                corev1.ClusterIPNone,
+			PublishNotReadyAddresses: true,
+			Ports:                    servicePorts(),
+			Selector:                 podLabels(),
+		},
+	}
+}
+
+// buildStatefulSet returns the StatefulSet of distributed MinIO
+func buildStatefulSet(namespace string, tempoNodeSelector map[string]string, cfg *Config) *appsv1.StatefulSet {
+	servers := fmt.Sprintf("http://%s-{0...%d}.%s.%s.svc.cluster.local/storage",
+		name, cfg.Replicas-1, headlessService, namespace)
+	minioContainer := container(cfg, []string{"minio", "server", servers})
+	minioContainer.ReadinessProbe = &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path: "/minio/health/ready",
+				Port: intstr.FromInt32(9000),
+			},
+		},
+		PeriodSeconds: 5,
+	}
+
+	replicas := int32(cfg.Replicas)
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:    &replicas,
+			ServiceName: headlessService,
+			// All servers must start together to form the cluster
+			PodManagementPolicy: appsv1.ParallelPodManagement,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: podLabels(),
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: podLabels(),
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{minioContainer},
+					Affinity:   affinity(tempoNodeSelector, true),
 				},
 			},
-			Selector: map[string]string{
-				"app.kubernetes.io/name": "minio",
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "storage",
+						Labels: podLabels(),
+					},
+					Spec: pvcSpec(cfg),
+				},
 			},
-			Type: corev1.ServiceTypeClusterIP,
 		},
 	}
+}
+
+// createBucket creates the Tempo bucket with a Job running the MinIO client and
+// waits for it to complete
+func createBucket(c Clients) error {
+	namespace := c.Namespace()
+	client := c.Client()
+	ctx := c.Context()
 
-	_, err = client.CoreV1().Services(namespace).Create(ctx, service, metav1.CreateOptions{})
+	job := buildBucketJob(namespace, c.GetTempoNodeSelector())
+	_, err := client.BatchV1().Jobs(namespace).Create(ctx, job, metav1.CreateOptions{})
 	if err != nil && !apierrors.IsAlreadyExists(err) {
-		return fmt.Errorf("failed to create MinIO service: %w", err)
+		return fmt.Errorf("failed to create MinIO bucket job: %w", err)
 	}
 
-	// Wait for MinIO to be ready
-	selector, err := labels.Parse("app.kubernetes.io/name=minio")
-	if err != nil {
-		return fmt.Errorf("failed to parse selector: %w", err)
+	if err := wait.ForJobComplete(c, bucketJob, 0); err != nil {
+		return fmt.Errorf("failed to create MinIO bucket: %w", err)
+	}
+	return nil
+}
+
+// buildBucketJob returns the Job creating the Tempo bucket with the MinIO client
+func buildBucketJob(namespace string, tempoNodeSelector map[string]string) *batchv1.Job {
+	backoffLimit := int32(6)
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      bucketJob,
+			Namespace: namespace,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						"app.kubernetes.io/name": bucketJob,
+					},
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						{
+							Name:    "mc",
+							Image:   mcImage,
+							Command: []string{"mc", "mb", "--ignore-existing", "minio/" + bucket},
+							Env: []corev1.EnvVar{
+								{
+									Name:  "MC_HOST_minio",
+									Value: fmt.Sprintf("http://%s:%s@%s:9000", accessKey, secretKey, name),
+								},
+							},
+						},
+					},
+					Affinity: affinity(tempoNodeSelector, false),
+				},
+			},
+		},
 	}
+}
+
+// podLabels returns the labels of the MinIO pods
+func podLabels() map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/name": name,
+	}
+}
 
-	return wait.ForPodsReady(c, selector, 120*time.Second, 1)
+// servicePorts returns the S3 API port of the MinIO Services
+func servicePorts() []corev1.ServicePort {
+	return []corev1.ServicePort{
+		{
+			Name:       "api",
+			Port:       9000,
+			Protocol:   corev1.ProtocolTCP,
+			TargetPort: intstr.FromInt32(9000),
+		},
+	}
+}
+
+// pvcSpec returns the spec of the PVC of a MinIO server
+func pvcSpec(cfg *Config) corev1.PersistentVolumeClaimSpec {
+	spec := corev1.PersistentVolumeClaimSpec{
+		AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+		Resources: corev1.VolumeResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceStorage: resource.MustParse(cfg.StorageSize),
+			},
+		},
+	}
+	if cfg.StorageClass != "" {
+		spec.StorageClassName = &cfg.StorageClass
+	}
+	return spec
+}
+
+// container returns the MinIO server container running command
+func container(cfg *Config, command []string) corev1.Container {
+	c := corev1.Container{
+		Name:    name,
+		Image:   image,
+		Command: command,
+		Env: []corev1.EnvVar{
+			{
+				Name:  "MINIO_ACCESS_KEY",
+				Value: accessKey,
+			},
+			{
+				Name:  "MINIO_SECRET_KEY",
+				Value: secretKey,
+			},
+			// The storage footprint is read from the metrics endpoints without credentials
+			{
+				Name:  "MINIO_PROMETHEUS_AUTH_TYPE",
+				Value: "public",
+			},
+		},
+		Ports: []corev1.ContainerPort{
+			{
+				ContainerPort: 9000,
+			},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      "storage",
+				MountPath: "/storage",
+			},
+		},
+	}
+	if cfg.Resources != nil {
+		c.Resources = *cfg.Resources
+	}
+	return c
+}
+
+// affinity returns the anti-affinity of MinIO pods to Tempo nodes if the Tempo
+// node selector is set and, with spread, prefers one MinIO server per node
+func affinity(tempoNodeSelector map[string]string, spread bool) *corev1.Affinity {
+	a := &corev1.Affinity{}
+	if len(tempoNodeSelector) > 0 {
+		a.NodeAffinity = buildNodeAntiAffinity(tempoNodeSelector)
+	}
+	if spread {
+		a.PodAntiAffinity = &corev1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+				{
+					Weight: 100,
+					PodAffinityTerm: corev1.PodAffinityTerm{
+						LabelSelector: &metav1.LabelSelector{MatchLabels: podLabels()},
+						TopologyKey:   "kubernetes.io/hostname",
+					},
+				},
+			},
+		}
+	}
+	if a.NodeAffinity == nil && a.PodAntiAffinity == nil {
+		return nil
+	}
+	return a
 }
@@ -26,8 +26,13 @@ type MonitoringConfig struct {
 }
 
 // EnableUserWorkloadMonitoring enables user workload monitoring in OpenShift
-// by creating or updating the cluster-monitoring-config ConfigMap
+// by creating or updating the cluster-monitoring-config ConfigMap.
+// The cluster-wide ConfigMap is only edited on clusters that pass CheckClusterSafety.
 func (f *Framework) EnableUserWorkloadMonitoring() error {
+	if err := f.CheckClusterSafety(); err != nil {
+		return fmt.Errorf("refusing to edit %s: %w", clusterMonitoringConfigMap, err)
+	}
+
 	ctx := f.ctx
 	client := f.client.CoreV1().ConfigMaps(monitoringNamespace)
 
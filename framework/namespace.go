@@ -4,20 +4,27 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/redhat/perf-tests-tempo/test/framework/tracing"
+
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-// EnsureNamespace creates the namespace if it doesn't exist
-func (f *Framework) EnsureNamespace() error {
+// EnsureNamespace creates the namespace if it doesn't exist, with the namespace
+// quota if one was set with WithNamespaceQuota
+func (f *Framework) EnsureNamespace() (err error) {
+	_, span := f.startOperation("framework.EnsureNamespace")
+	defer func() { tracing.End(span, err) }()
+
 	ns := &corev1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: f.namespace,
+			Name:   f.namespace,
+			Labels: f.GetManagedLabels(),
 		},
 	}
 
-	_, err := f.client.CoreV1().Namespaces().Create(f.ctx, ns, metav1.CreateOptions{})
-	if err != nil {
+	if _, err := f.client.CoreV1().Namespaces().Create(f.ctx, ns, metav1.CreateOptions{}); err != nil {
 		// Check if namespace already exists
 		_, getErr := f.client.CoreV1().Namespaces().Get(f.ctx, f.namespace, metav1.GetOptions{})
 		if getErr != nil {
@@ -26,11 +33,32 @@ func (f *Framework) EnsureNamespace() error {
 		// Namespace exists, that's fine
 	}
 
+	if err := f.applyNamespaceQuota(); err != nil {
+		return fmt.Errorf("failed to apply namespace quota: %w", err)
+	}
+
 	// Wait a moment for namespace to be ready
 	time.Sleep(f.config.NamespacePollInterval)
 	return nil
 }
 
+// CheckNamespaceAvailable returns ErrNamespaceInUse if the namespace already exists.
+// Run it before deploying to avoid reusing leftover resources from another run.
+func (f *Framework) CheckNamespaceAvailable() error {
+	ns, err := f.client.CoreV1().Namespaces().Get(f.ctx, f.namespace, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check namespace %s: %w", f.namespace, err)
+	}
+
+	if owner := ns.Labels[LabelRunID]; owner != "" {
+		return fmt.Errorf("%w: namespace %s belongs to run %s", ErrNamespaceInUse, f.namespace, owner)
+	}
+	return fmt.Errorf("%w: namespace %s already exists", ErrNamespaceInUse, f.namespace)
+}
+
 // DeleteNamespace deletes the namespace
 func (f *Framework) DeleteNamespace() error {
 	err := f.client.CoreV1().Namespaces().Delete(f.ctx, f.namespace, metav1.DeleteOptions{})
@@ -38,7 +66,11 @@ func (f *Framework) DeleteNamespace() error {
 		return fmt.Errorf("failed to delete namespace: %w", err)
 	}
 
-	// Wait for namespace deletion with timeout
+	return f.waitForNamespaceDeletion()
+}
+
+// waitForNamespaceDeletion waits until the namespace no longer exists
+func (f *Framework) waitForNamespaceDeletion() error {
 	timeout := f.config.NamespaceTimeout
 	pollInterval := f.config.NamespacePollInterval
 	deadline := time.Now().Add(timeout)
@@ -0,0 +1,319 @@
+// Package notify posts run lifecycle events to Slack or generic webhooks.
+//
+// Long performance runs can take hours, so the runner uses a Notifier to
+// report run start, profile completion, SLO violations, and failures
+// asynchronously. A Notifier without a webhook URL is a no-op, which lets
+// callers invoke it unconditionally.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Environment variable names for notification configuration
+const (
+	EnvWebhookURL       = "TEMPO_PERF_NOTIFY_WEBHOOK_URL"
+	EnvFormat           = "TEMPO_PERF_NOTIFY_FORMAT"
+	EnvDashboardBaseURL = "TEMPO_PERF_NOTIFY_DASHBOARD_BASE_URL"
+)
+
+// DefaultTimeout is the default timeout for webhook requests
+const DefaultTimeout = 10 * time.Second
+
+// Format determines the payload shape sent to the webhook
+type Format string
+
+const (
+	// FormatSlack sends Slack incoming-webhook compatible payloads
+	FormatSlack Format = "slack"
+	// FormatGeneric sends the raw Event as JSON
+	FormatGeneric Format = "generic"
+)
+
+// EventType identifies a run lifecycle event
+type EventType string
+
+const (
+	// EventRunStarted is sent once when the runner starts executing profiles
+	EventRunStarted EventType = "run_started"
+	// EventProfileCompleted is sent when a profile finishes successfully
+	EventProfileCompleted EventType = "profile_completed"
+	// EventSLOViolation is sent when a profile breaches its load test thresholds
+	EventSLOViolation EventType = "slo_violation"
+	// EventFailure is sent when a profile fails
+	EventFailure EventType = "failure"
+)
+
+// Config holds webhook notification settings
+type Config struct {
+	// WebhookURL is the endpoint events are posted to. Empty disables notifications.
+	WebhookURL string
+
+	// Format is the payload format (slack or generic). Defaults to slack.
+	Format Format
+
+	// DashboardBaseURL is prepended to dashboard file names to build links.
+	// If empty, the local dashboard path is reported instead.
+	DashboardBaseURL string
+
+	// Timeout is the HTTP request timeout. Defaults to DefaultTimeout.
+	Timeout time.Duration
+}
+
+// FromEnv returns a Config populated from environment variables. It returns an
+// error for an unknown format, so a typo fails at startup rather than at the
+// first notification.
+func FromEnv() (*Config, error) {
+	format := Format(strings.ToLower(os.Getenv(EnvFormat)))
+	if format != "" && format != FormatSlack && format != FormatGeneric {
+		return nil, fmt.Errorf("%s must be '%s' or '%s', got %q", EnvFormat, FormatSlack, FormatGeneric, os.Getenv(EnvFormat))
+	}
+	return &Config{
+		WebhookURL:       os.Getenv(EnvWebhookURL),
+		Format:           format,
+		DashboardBaseURL: os.Getenv(EnvDashboardBaseURL),
+	}, nil
+}
+
+// Merge returns a copy of c with non-empty fields from other applied on top
+func (c *Config) Merge(other *Config) *Config {
+	cp := Config{}
+	if c != nil {
+		cp = *c
+	}
+	if other == nil {
+		return &cp
+	}
+	if other.WebhookURL != "" {
+		cp.WebhookURL = other.WebhookURL
+	}
+	if other.Format != "" {
+		cp.Format = other.Format
+	}
+	if other.DashboardBaseURL != "" {
+		cp.DashboardBaseURL = other.DashboardBaseURL
+	}
+	if other.Timeout > 0 {
+		cp.Timeout = other.Timeout
+	}
+	return &cp
+}
+
+// Event describes a single lifecycle event
+type Event struct {
+	Type         EventType         `json:"type"`
+	Profile      string            `json:"profile,omitempty"`
+	Message      string            `json:"message"`
+	Duration     string            `json:"duration,omitempty"`
+	DashboardURL string            `json:"dashboardUrl,omitempty"`
+	Fields       map[string]string `json:"fields,omitempty"`
+	// Report is the Markdown summary of the profile run, when one was generated
+	Report    string    `json:"report,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Notifier posts events to a webhook
+type Notifier struct {
+	config     Config
+	httpClient *http.Client
+	report     string
+}
+
+// New creates a Notifier from the given configuration. A nil config yields a disabled notifier.
+func New(cfg *Config) *Notifier {
+	n := &Notifier{}
+	if cfg != nil {
+		n.config = *cfg
+	}
+	if n.config.Format == "" {
+		n.config.Format = FormatSlack
+	}
+	if n.config.Timeout <= 0 {
+		n.config.Timeout = DefaultTimeout
+	}
+	n.httpClient = &http.Client{Timeout: n.config.Timeout}
+	return n
+}
+
+// Enabled returns true if a webhook URL is configured
+func (n *Notifier) Enabled() bool {
+	return n != nil && n.config.WebhookURL != ""
+}
+
+// WithReport returns a copy of the notifier that attaches a Markdown run report
+// (see package report) to the events it sends
+func (n *Notifier) WithReport(markdown string) *Notifier {
+	cp := *n
+	cp.report = markdown
+	return &cp
+}
+
+// RunStarted reports that the runner is starting the given profiles
+func (n *Notifier) RunStarted(ctx context.Context, profiles []string, testType string) error {
+	return n.Send(ctx, Event{
+		Type:    EventRunStarted,
+		Message: fmt.Sprintf("Starting %s run for %d profile(s): %s", testType, len(profiles), strings.Join(profiles, ", ")),
+		Fields: map[string]string{
+			"testType": testType,
+		},
+	})
+}
+
+// ProfileCompleted reports that a profile finished successfully
+func (n *Notifier) ProfileCompleted(ctx context.Context, profile string, duration time.Duration, dashboardPath string) error {
+	return n.Send(ctx, Event{
+		Type:         EventProfileCompleted,
+		Profile:      profile,
+		Message:      fmt.Sprintf("Profile %s completed successfully", profile),
+		Duration:     duration.Round(time.Second).String(),
+		DashboardURL: n.dashboardURL(dashboardPath),
+	})
+}
+
+// SLOViolation reports that a profile breached its load test thresholds
+func (n *Notifier) SLOViolation(ctx context.Context, profile, detail string, dashboardPath string) error {
+	return n.Send(ctx, Event{
+		Type:         EventSLOViolation,
+		Profile:      profile,
+		Message:      fmt.Sprintf("Profile %s violated SLO: %s", profile, detail),
+		DashboardURL: n.dashboardURL(dashboardPath),
+	})
+}
+
+// Failure reports that a profile failed
+func (n *Notifier) Failure(ctx context.Context, profile string, duration time.Duration, err error, dashboardPath string) error {
+	msg := fmt.Sprintf("Profile %s failed", profile)
+	if err != nil {
+		msg = fmt.Sprintf("%s: %v", msg, err)
+	}
+	return n.Send(ctx, Event{
+		Type:         EventFailure,
+		Profile:      profile,
+		Message:      msg,
+		Duration:     duration.Round(time.Second).String(),
+		DashboardURL: n.dashboardURL(dashboardPath),
+	})
+}
+
+// Send posts an event to the configured webhook. It is a no-op when the notifier is disabled.
+func (n *Notifier) Send(ctx context.Context, event Event) error {
+	if !n.Enabled() {
+		return nil
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+	if event.Report == "" {
+		event.Report = n.report
+	}
+
+	body, err := n.payload(event)
+	if err != nil {
+		return fmt.Errorf("failed to build notification payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.config.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create notification request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("webhook returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	return nil
+}
+
+// payload renders the event in the configured format
+func (n *Notifier) payload(event Event) ([]byte, error) {
+	switch n.config.Format {
+	case FormatGeneric:
+		return json.Marshal(event)
+	case FormatSlack:
+		return json.Marshal(map[string]string{"text": slackText(event)})
+	default:
+		return nil, fmt.Errorf("unsupported notification format %q", n.config.Format)
+	}
+}
+
+// dashboardURL builds a link for a dashboard path, using DashboardBaseURL if set
+func (n *Notifier) dashboardURL(path string) string {
+	if path == "" {
+		return ""
+	}
+	if n.config.DashboardBaseURL == "" {
+		return path
+	}
+	base := strings.TrimSuffix(n.config.DashboardBaseURL, "/")
+	name := path[strings.LastIndex(path, "/")+1:]
+	return base + "/" + name
+}
+
+// slackText formats an event as Slack mrkdwn text
+func slackText(event Event) string {
+	var sb strings.Builder
+	sb.WriteString(eventIcon(event.Type))
+	sb.WriteString(" *")
+	sb.WriteString(event.Message)
+	sb.WriteString("*")
+
+	if event.Duration != "" {
+		fmt.Fprintf(&sb, "\nDuration: %s", event.Duration)
+	}
+	if event.DashboardURL != "" {
+		if strings.HasPrefix(event.DashboardURL, "http://") || strings.HasPrefix(event.DashboardURL, "https://") {
+			fmt.Fprintf(&sb, "\nDashboard: <%s|open>", event.DashboardURL)
+		} else {
+			fmt.Fprintf(&sb, "\nDashboard: `%s`", event.DashboardURL)
+		}
+	}
+
+	keys := make([]string, 0, len(event.Fields))
+	for k := range event.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&sb, "\n%s: %s", k, event.Fields[k])
+	}
+
+	// Slack mrkdwn has no tables, so the report is sent as preformatted text
+	if event.Report != "" {
+		fmt.Fprintf(&sb, "\n```\n%s\n```", strings.TrimSpace(event.Report))
+	}
+
+	return sb.String()
+}
+
+// eventIcon returns the Slack emoji used for an event type
+func eventIcon(t EventType) string {
+	switch t {
+	case EventRunStarted:
+		return ":rocket:"
+	case EventProfileCompleted:
+		return ":white_check_mark:"
+	case EventSLOViolation:
+		return ":warning:"
+	case EventFailure:
+		return ":x:"
+	default:
+		return ":information_source:"
+	}
+}
@@ -0,0 +1,170 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNotifier_DisabledIsNoop(t *testing.T) {
+	n := New(nil)
+	if n.Enabled() {
+		t.Error("expected notifier without webhook URL to be disabled")
+	}
+	if err := n.RunStarted(context.Background(), []string{"small"}, "combined"); err != nil {
+		t.Errorf("expected no error from disabled notifier, got %v", err)
+	}
+}
+
+func TestNotifier_SlackPayload(t *testing.T) {
+	var body map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(data, &body); err != nil {
+			t.Errorf("invalid JSON payload: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	n := New(&Config{
+		WebhookURL:       server.URL,
+		DashboardBaseURL: "https://reports.example.com/run-1/",
+	})
+
+	err := n.ProfileCompleted(context.Background(), "medium", 90*time.Second, "results/medium-dashboard.html")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	text := body["text"]
+	if !strings.Contains(text, "Profile medium completed successfully") {
+		t.Errorf("expected completion message, got %q", text)
+	}
+	if !strings.Contains(text, "https://reports.example.com/run-1/medium-dashboard.html") {
+		t.Errorf("expected dashboard link, got %q", text)
+	}
+	if !strings.Contains(text, "1m30s") {
+		t.Errorf("expected duration, got %q", text)
+	}
+}
+
+func TestNotifier_GenericPayload(t *testing.T) {
+	var event Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(data, &event); err != nil {
+			t.Errorf("invalid JSON payload: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	n := New(&Config{WebhookURL: server.URL, Format: FormatGeneric})
+
+	err := n.Failure(context.Background(), "large", time.Minute, errors.New("k6 test failed"), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if event.Type != EventFailure {
+		t.Errorf("expected type %q, got %q", EventFailure, event.Type)
+	}
+	if event.Profile != "large" {
+		t.Errorf("expected profile large, got %q", event.Profile)
+	}
+	if !strings.Contains(event.Message, "k6 test failed") {
+		t.Errorf("expected error in message, got %q", event.Message)
+	}
+	if event.Timestamp.IsZero() {
+		t.Error("expected timestamp to be set")
+	}
+}
+
+func TestNotifier_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid_token", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	n := New(&Config{WebhookURL: server.URL})
+
+	err := n.SLOViolation(context.Background(), "small", "k6 thresholds breached", "")
+	if err == nil {
+		t.Fatal("expected error for non-2xx status")
+	}
+	if !strings.Contains(err.Error(), "403") {
+		t.Errorf("expected status code in error, got %v", err)
+	}
+}
+
+func TestConfig_Merge(t *testing.T) {
+	base := &Config{WebhookURL: "https://hooks.example.com/a", Format: FormatSlack}
+	merged := base.Merge(&Config{Format: FormatGeneric})
+
+	if merged.WebhookURL != base.WebhookURL {
+		t.Errorf("expected webhook URL to be preserved, got %q", merged.WebhookURL)
+	}
+	if merged.Format != FormatGeneric {
+		t.Errorf("expected format override, got %q", merged.Format)
+	}
+	if base.Format != FormatSlack {
+		t.Error("expected original config to be unchanged")
+	}
+}
+
+func TestFromEnv_Format(t *testing.T) {
+	t.Setenv(EnvFormat, "Generic")
+	config, err := FromEnv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Format != FormatGeneric {
+		t.Errorf("expected format %q, got %q", FormatGeneric, config.Format)
+	}
+
+	t.Setenv(EnvFormat, "teams")
+	if _, err := FromEnv(); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
+
+func TestNotifier_WithReport(t *testing.T) {
+	var payloads [][]byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		payloads = append(payloads, data)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	report := "## ✅ Tempo performance run: small\n"
+	for _, format := range []Format{FormatGeneric, FormatSlack} {
+		n := New(&Config{WebhookURL: server.URL, Format: format}).WithReport(report)
+		if err := n.ProfileCompleted(context.Background(), "small", time.Minute, ""); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	var event Event
+	if err := json.Unmarshal(payloads[0], &event); err != nil {
+		t.Fatalf("invalid JSON payload: %v", err)
+	}
+	if event.Report != report {
+		t.Errorf("expected the report in the generic payload, got %q", event.Report)
+	}
+
+	var body map[string]string
+	if err := json.Unmarshal(payloads[1], &body); err != nil {
+		t.Fatalf("invalid JSON payload: %v", err)
+	}
+	if !strings.Contains(body["text"], "```\n## ✅ Tempo performance run: small\n```") {
+		t.Errorf("expected the report as preformatted text, got %q", body["text"])
+	}
+}
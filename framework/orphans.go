@@ -0,0 +1,269 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/gvr"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultOrphanMaxAge is the default age after which managed resources are considered stale
+const DefaultOrphanMaxAge = 24 * time.Hour
+
+// OrphanCleanupOptions configures orphan cleanup
+type OrphanCleanupOptions struct {
+	// MaxAge is the age after which managed resources are considered stale.
+	// Namespaces annotated with an expiry time (see Preserve) are stale once they expire instead.
+	MaxAge time.Duration
+
+	// DryRun only reports stale resources without deleting them
+	DryRun bool
+
+	// ExpiredOnly limits the cleanup to namespaces whose expiry annotation has passed,
+	// and the cluster-scoped resources of those namespaces; MaxAge is ignored
+	ExpiredOnly bool
+}
+
+// OrphanCleanupResult lists the stale resources that were found, and deleted unless DryRun was set
+type OrphanCleanupResult struct {
+	Namespaces          []string
+	ClusterRoles        []string
+	ClusterRoleBindings []string
+	PersistentVolumes   []string
+}
+
+// Total returns the number of stale resources
+func (r *OrphanCleanupResult) Total() int {
+	return len(r.Namespaces) + len(r.ClusterRoles) + len(r.ClusterRoleBindings) + len(r.PersistentVolumes)
+}
+
+// CleanupOrphans scans the cluster for resources labeled as managed by the framework
+// that are older than MaxAge (e.g. left behind by aborted runs) and deletes them.
+// Stale namespaces are cleaned up like Cleanup does, removing finalizers from CRs
+// that block namespace deletion. Cluster-scoped resources whose namespace no longer
+// exists are deleted as well. Deletion is refused on clusters that fail CheckClusterSafety.
+func CleanupOrphans(ctx context.Context, opts OrphanCleanupOptions, frameworkOpts ...Option) (*OrphanCleanupResult, error) {
+	f, err := newFramework(ctx, "", frameworkOpts...)
+	if err != nil {
+		return nil, err
+	}
+	if !opts.DryRun {
+		if err := f.CheckClusterSafety(); err != nil {
+			return nil, err
+		}
+	}
+	return f.cleanupOrphans(opts, time.Now())
+}
+
+// cleanupOrphans implements CleanupOrphans for a cluster-level framework instance
+func (f *Framework) cleanupOrphans(opts OrphanCleanupOptions, now time.Time) (*OrphanCleanupResult, error) {
+	if opts.MaxAge <= 0 {
+		opts.MaxAge = DefaultOrphanMaxAge
+	}
+
+	managedSelector := fmt.Sprintf("%s=%s", LabelManagedBy, LabelManagedByValue)
+	result := &OrphanCleanupResult{}
+	var errs []error
+
+	// 1. Stale namespaces (cascades to their CRs and labeled cluster resources)
+	namespaces, err := f.client.CoreV1().Namespaces().List(f.ctx, metav1.ListOptions{LabelSelector: managedSelector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list managed namespaces: %w", err)
+	}
+
+	staleNamespaces := make(map[string]bool)
+	for i := range namespaces.Items {
+		ns := &namespaces.Items[i]
+		stale := isStale(ns, opts.MaxAge, now)
+		if opts.ExpiredOnly {
+			stale = isExpired(ns, now)
+		}
+		if !stale {
+			continue
+		}
+		if f.ctx.Err() != nil {
+			return result, fmt.Errorf("context cancelled during orphan cleanup: %w", f.ctx.Err())
+		}
+
+		staleNamespaces[ns.Name] = true
+		result.Namespaces = append(result.Namespaces, ns.Name)
+		if opts.DryRun {
+			continue
+		}
+
+		f.logger.Info("deleting stale namespace", "namespace", ns.Name, "created", ns.CreationTimestamp.Time)
+		if err := f.forNamespace(ns.Name).forceCleanup(ns.DeletionTimestamp != nil); err != nil {
+			errs = append(errs, fmt.Errorf("failed to clean up namespace %s: %w", ns.Name, err))
+		}
+	}
+
+	// orphaned reports whether a cluster-scoped resource belongs to a stale or deleted namespace
+	orphaned := func(obj metav1.Object) bool {
+		instance := obj.GetLabels()[LabelInstance]
+		if staleNamespaces[instance] {
+			return true
+		}
+		if opts.ExpiredOnly || !isStale(obj, opts.MaxAge, now) {
+			return false
+		}
+		if instance == "" {
+			return true
+		}
+		_, err := f.client.CoreV1().Namespaces().Get(f.ctx, instance, metav1.GetOptions{})
+		return apierrors.IsNotFound(err)
+	}
+
+	// 2. ClusterRoles
+	clusterRoles, err := f.client.RbacV1().ClusterRoles().List(f.ctx, metav1.ListOptions{LabelSelector: managedSelector})
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to list ClusterRoles: %w", err))
+	} else {
+		for i := range clusterRoles.Items {
+			cr := &clusterRoles.Items[i]
+			if !orphaned(cr) {
+				continue
+			}
+			result.ClusterRoles = append(result.ClusterRoles, cr.Name)
+			if opts.DryRun {
+				continue
+			}
+			f.logger.Info("deleting stale ClusterRole", "name", cr.Name)
+			if err := f.client.RbacV1().ClusterRoles().Delete(f.ctx, cr.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+				errs = append(errs, fmt.Errorf("failed to delete ClusterRole %s: %w", cr.Name, err))
+			}
+		}
+	}
+
+	// 3. ClusterRoleBindings
+	clusterRoleBindings, err := f.client.RbacV1().ClusterRoleBindings().List(f.ctx, metav1.ListOptions{LabelSelector: managedSelector})
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to list ClusterRoleBindings: %w", err))
+	} else {
+		for i := range clusterRoleBindings.Items {
+			crb := &clusterRoleBindings.Items[i]
+			if !orphaned(crb) {
+				continue
+			}
+			result.ClusterRoleBindings = append(result.ClusterRoleBindings, crb.Name)
+			if opts.DryRun {
+				continue
+			}
+			f.logger.Info("deleting stale ClusterRoleBinding", "name", crb.Name)
+			if err := f.client.RbacV1().ClusterRoleBindings().Delete(f.ctx, crb.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+				errs = append(errs, fmt.Errorf("failed to delete ClusterRoleBinding %s: %w", crb.Name, err))
+			}
+		}
+	}
+
+	// 4. PersistentVolumes (only Released or Available ones are deleted)
+	pvs, err := f.client.CoreV1().PersistentVolumes().List(f.ctx, metav1.ListOptions{LabelSelector: managedSelector})
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to list PersistentVolumes: %w", err))
+	} else {
+		for i := range pvs.Items {
+			pv := &pvs.Items[i]
+			if !orphaned(pv) {
+				continue
+			}
+			if opts.DryRun {
+				result.PersistentVolumes = append(result.PersistentVolumes, pv.Name)
+				continue
+			}
+			deleted, err := f.deleteOrphanedPV(pv)
+			if err != nil {
+				errs = append(errs, err)
+			} else if deleted {
+				result.PersistentVolumes = append(result.PersistentVolumes, pv.Name)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return result, errors.Join(errs...)
+	}
+
+	return result, nil
+}
+
+// forceCleanup runs Cleanup and, if it fails or the namespace gets stuck terminating,
+// removes finalizers from remaining CRs whose operator no longer processes them and
+// deletes the namespace
+func (f *Framework) forceCleanup(terminating bool) error {
+	if !terminating {
+		err := f.Cleanup()
+		if err == nil {
+			return nil
+		}
+		f.logger.Warn("cleanup did not complete, removing finalizers from remaining CRs", "namespace", f.namespace, "error", err)
+	}
+
+	if err := f.removeManagedCRFinalizers(); err != nil {
+		f.logger.Warn("failed to remove finalizers from some CRs", "namespace", f.namespace, "error", err)
+	}
+
+	// Cleanup may have failed before deleting the namespace
+	if err := f.DeleteNamespace(); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	if err := f.cleanupClusterResourcesByLabel(); err != nil {
+		return fmt.Errorf("failed to cleanup cluster-scoped resources: %w", err)
+	}
+	if err := f.cleanupOrphanedPVs(); err != nil {
+		f.logger.Warn("failed to cleanup orphaned PVs", "error", err)
+	}
+
+	return nil
+}
+
+// removeManagedCRFinalizers removes finalizers from all managed CR kinds left in the namespace
+func (f *Framework) removeManagedCRFinalizers() error {
+	var remaining []TrackedResource
+	for _, resourceGVR := range gvr.AllManagedCRs() {
+		list, err := f.dynamicClient.Resource(resourceGVR).Namespace(f.namespace).List(f.ctx, metav1.ListOptions{})
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				f.logger.Debug("failed to list CRs", "resource", resourceGVR.Resource, "error", err)
+			}
+			continue
+		}
+		for _, item := range list.Items {
+			remaining = append(remaining, TrackedResource{GVR: resourceGVR, Namespace: f.namespace, Name: item.GetName()})
+		}
+	}
+
+	return f.removeFinalizersFromCRs(remaining)
+}
+
+// isStale reports whether a managed resource should be garbage collected.
+// An expiry annotation takes precedence over the creation time.
+func isStale(obj metav1.Object, maxAge time.Duration, now time.Time) bool {
+	if expiresAt, ok := expiryTime(obj); ok {
+		return now.After(expiresAt)
+	}
+	return now.Sub(obj.GetCreationTimestamp().Time) > maxAge
+}
+
+// isExpired reports whether a resource has an expiry annotation that has passed
+func isExpired(obj metav1.Object, now time.Time) bool {
+	expiresAt, ok := expiryTime(obj)
+	return ok && now.After(expiresAt)
+}
+
+// expiryTime returns the time of the expiry annotation set by Preserve
+func expiryTime(obj metav1.Object) (time.Time, bool) {
+	value, ok := obj.GetAnnotations()[AnnotationExpiresAt]
+	if !ok {
+		return time.Time{}, false
+	}
+	expiresAt, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return expiresAt, true
+}
@@ -0,0 +1,207 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/config"
+	"github.com/redhat/perf-tests-tempo/test/framework/gvr"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var orphanTestNow = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func managedMeta(name, instance string, age time.Duration, annotations map[string]string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:              name,
+		CreationTimestamp: metav1.NewTime(orphanTestNow.Add(-age)),
+		Labels: map[string]string{
+			LabelManagedBy: LabelManagedByValue,
+			LabelInstance:  instance,
+		},
+		Annotations: annotations,
+	}
+}
+
+func newOrphanTestFramework(objects ...runtime.Object) *Framework {
+	return &Framework{
+		client: fake.NewSimpleClientset(objects...),
+		ctx:    context.Background(),
+		logger: slog.Default(),
+		config: config.Default(),
+	}
+}
+
+func TestIsStale(t *testing.T) {
+	tests := []struct {
+		name     string
+		meta     metav1.ObjectMeta
+		expected bool
+	}{
+		{"old", managedMeta("a", "a", 48*time.Hour, nil), true},
+		{"recent", managedMeta("b", "b", time.Hour, nil), false},
+		{"expired annotation", managedMeta("c", "c", time.Hour, map[string]string{
+			AnnotationExpiresAt: orphanTestNow.Add(-time.Minute).Format(time.RFC3339),
+		}), true},
+		{"preserved", managedMeta("d", "d", 48*time.Hour, map[string]string{
+			AnnotationExpiresAt: orphanTestNow.Add(time.Hour).Format(time.RFC3339),
+		}), false},
+		{"invalid annotation", managedMeta("e", "e", 48*time.Hour, map[string]string{
+			AnnotationExpiresAt: "tomorrow",
+		}), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ns := &corev1.Namespace{ObjectMeta: tt.meta}
+			if got := isStale(ns, 24*time.Hour, orphanTestNow); got != tt.expected {
+				t.Errorf("expected stale=%v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestCleanupOrphans_DryRun(t *testing.T) {
+	f := newOrphanTestFramework(
+		&corev1.Namespace{ObjectMeta: managedMeta("tempo-perf-old", "tempo-perf-old", 48*time.Hour, nil)},
+		&corev1.Namespace{ObjectMeta: managedMeta("tempo-perf-new", "tempo-perf-new", time.Hour, nil)},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:              "unmanaged",
+			CreationTimestamp: metav1.NewTime(orphanTestNow.Add(-48 * time.Hour)),
+		}},
+		// Belongs to a stale namespace
+		&rbacv1.ClusterRole{ObjectMeta: managedMeta("old-role", "tempo-perf-old", time.Hour, nil)},
+		// Namespace is gone
+		&rbacv1.ClusterRoleBinding{ObjectMeta: managedMeta("gone-binding", "tempo-perf-gone", 48*time.Hour, nil)},
+		// Namespace still in use
+		&rbacv1.ClusterRoleBinding{ObjectMeta: managedMeta("new-binding", "tempo-perf-new", 48*time.Hour, nil)},
+	)
+
+	result, err := f.cleanupOrphans(OrphanCleanupOptions{MaxAge: 24 * time.Hour, DryRun: true}, orphanTestNow)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sort.Strings(result.ClusterRoleBindings)
+	expected := &OrphanCleanupResult{
+		Namespaces:          []string{"tempo-perf-old"},
+		ClusterRoles:        []string{"old-role"},
+		ClusterRoleBindings: []string{"gone-binding"},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected %+v, got %+v", expected, result)
+	}
+
+	// Dry run must not delete anything
+	if _, err := f.client.CoreV1().Namespaces().Get(f.ctx, "tempo-perf-old", metav1.GetOptions{}); err != nil {
+		t.Errorf("expected namespace to survive dry run, got %v", err)
+	}
+}
+
+func TestCleanupOrphans_ExpiredOnly(t *testing.T) {
+	expired := map[string]string{AnnotationExpiresAt: orphanTestNow.Add(-time.Minute).Format(time.RFC3339)}
+	valid := map[string]string{AnnotationExpiresAt: orphanTestNow.Add(time.Hour).Format(time.RFC3339)}
+	f := newOrphanTestFramework(
+		&corev1.Namespace{ObjectMeta: managedMeta("tempo-perf-expired", "tempo-perf-expired", time.Hour, expired)},
+		&corev1.Namespace{ObjectMeta: managedMeta("tempo-perf-preserved", "tempo-perf-preserved", 48*time.Hour, valid)},
+		// Old, but not preserved: left to cleanup-orphans with --max-age
+		&corev1.Namespace{ObjectMeta: managedMeta("tempo-perf-old", "tempo-perf-old", 48*time.Hour, nil)},
+		&rbacv1.ClusterRole{ObjectMeta: managedMeta("expired-role", "tempo-perf-expired", time.Hour, nil)},
+		&rbacv1.ClusterRole{ObjectMeta: managedMeta("gone-role", "tempo-perf-gone", 48*time.Hour, nil)},
+	)
+
+	result, err := f.cleanupOrphans(OrphanCleanupOptions{ExpiredOnly: true, DryRun: true}, orphanTestNow)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := &OrphanCleanupResult{
+		Namespaces:   []string{"tempo-perf-expired"},
+		ClusterRoles: []string{"expired-role"},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected %+v, got %+v", expected, result)
+	}
+}
+
+func TestCleanupOrphans_DeletesClusterResourcesOfDeletedNamespace(t *testing.T) {
+	f := newOrphanTestFramework(
+		&rbacv1.ClusterRole{ObjectMeta: managedMeta("gone-role", "tempo-perf-gone", 48*time.Hour, nil)},
+		&rbacv1.ClusterRole{ObjectMeta: managedMeta("recent-role", "tempo-perf-gone", time.Hour, nil)},
+	)
+
+	result, err := f.cleanupOrphans(OrphanCleanupOptions{MaxAge: 24 * time.Hour}, orphanTestNow)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Total() != 1 {
+		t.Errorf("expected 1 stale resource, got %+v", result)
+	}
+
+	if _, err := f.client.RbacV1().ClusterRoles().Get(f.ctx, "gone-role", metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected stale ClusterRole to be deleted, got %v", err)
+	}
+	if _, err := f.client.RbacV1().ClusterRoles().Get(f.ctx, "recent-role", metav1.GetOptions{}); err != nil {
+		t.Errorf("expected recent ClusterRole to be kept, got %v", err)
+	}
+}
+
+// newManagedCRDynamicClient returns a fake dynamic client that can list all managed CR kinds
+func newManagedCRDynamicClient() *dynamicfake.FakeDynamicClient {
+	listKinds := make(map[schema.GroupVersionResource]string)
+	for _, resourceGVR := range gvr.AllManagedCRs() {
+		listKinds[resourceGVR] = resourceGVR.Resource + "List"
+	}
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds)
+}
+
+func TestForceCleanup_DeletesNamespaceWhenCleanupFails(t *testing.T) {
+	f := newOrphanTestFramework(
+		&corev1.Namespace{ObjectMeta: managedMeta("tempo-perf-old", "tempo-perf-old", 48*time.Hour, nil)},
+		&rbacv1.ClusterRole{ObjectMeta: managedMeta("old-role", "tempo-perf-old", 48*time.Hour, nil)},
+	)
+	dynamicClient := newManagedCRDynamicClient()
+	// Listing CRs fails, so Cleanup returns before deleting the namespace
+	dynamicClient.PrependReactor("list", "*", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "tempostacks"}, "", errors.New("forbidden"))
+	})
+	f.dynamicClient = dynamicClient
+
+	if err := f.forNamespace("tempo-perf-old").forceCleanup(false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := f.client.CoreV1().Namespaces().Get(f.ctx, "tempo-perf-old", metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected namespace to be deleted, got %v", err)
+	}
+	if _, err := f.client.RbacV1().ClusterRoles().Get(f.ctx, "old-role", metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected ClusterRole to be deleted, got %v", err)
+	}
+}
+
+func TestForceCleanup_NamespaceAlreadyGone(t *testing.T) {
+	f := newOrphanTestFramework()
+	dynamicClient := newManagedCRDynamicClient()
+	f.dynamicClient = dynamicClient
+	dynamicClient.PrependReactor("list", "*", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewNotFound(schema.GroupResource{Resource: "tempostacks"}, "")
+	})
+
+	if err := f.forNamespace("tempo-perf-gone").forceCleanup(true); err != nil {
+		t.Errorf("expected a missing namespace to count as deleted, got %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package otel
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/wait"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// StaticTokenSecretName is the long-lived ServiceAccount token of the collector
+const StaticTokenSecretName = "otel-collector-token"
+
+// IngestionClientTLSSecretName holds the client certificate for mTLS ingestion
+// (must match tempo package)
+const IngestionClientTLSSecretName = "tempo-ingestion-client-tls"
+
+// ingestionTLSMountPath is where the client certificate is mounted in the collector
+const ingestionTLSMountPath = "/etc/tempo-ingestion-tls"
+
+// createStaticToken creates a long-lived token Secret for the collector ServiceAccount
+// and waits for the token controller to populate it. Unlike the projected token it
+// is never rotated, so the gateway sees the same token for the whole run.
+func createStaticToken(fw FrameworkOperations, timeout time.Duration) error {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      StaticTokenSecretName,
+			Namespace: fw.Namespace(),
+			Labels:    fw.GetManagedLabels(),
+			Annotations: map[string]string{
+				corev1.ServiceAccountNameKey: "otel-collector-sa",
+			},
+		},
+		Type: corev1.SecretTypeServiceAccountToken,
+	}
+	secrets := fw.Client().CoreV1().Secrets(fw.Namespace())
+	_, err := secrets.Create(fw.Context(), secret, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create token Secret: %w", err)
+	}
+
+	err = wait.ForCondition(fw.Context(), wait.PollInterval(fw), timeout, func() (bool, error) {
+		s, err := secrets.Get(fw.Context(), StaticTokenSecretName, metav1.GetOptions{})
+		if err != nil {
+			return false, nil
+		}
+		return len(s.Data[corev1.ServiceAccountTokenKey]) > 0, nil
+	})
+	if errors.Is(err, wait.ErrTimeout) {
+		return fmt.Errorf("token Secret %s not populated after %v", StaticTokenSecretName, timeout)
+	}
+	return err
+}
@@ -2,6 +2,7 @@ package otel
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"time"
@@ -14,6 +15,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
@@ -35,6 +37,14 @@ type FrameworkOperations interface {
 	// GetTempoNodeSelector returns the node selector used for Tempo pods.
 	// Used to create anti-affinity for the OTel Collector.
 	GetTempoNodeSelector() map[string]string
+	// GetIngestionAuth returns how the collector authenticates to Tempo
+	GetIngestionAuth() string
+	// GetCollectorImage returns the pinned collector image, or empty for the
+	// default of the operator
+	GetCollectorImage() string
+	// GetIngestionProtocol returns the OTLP protocol the collector exports to
+	// Tempo with, or empty for gRPC
+	GetIngestionProtocol() string
 }
 
 // Tempo CR names (must match tempo package)
@@ -43,21 +53,89 @@ const (
 	StackCRName      = "tempostack"
 )
 
+// CollectorName is the name of the OpenTelemetryCollector CR SetupCollector creates
+const CollectorName = "otel-collector"
+
+// DefaultTenant is the tenant the collector of SetupCollector sends traces as
+// (must match tempo package)
+const DefaultTenant = "tenant-1"
+
+// Ingestion authentication modes (must match tempo package)
+const (
+	IngestionAuthSAToken     = "sa-token"
+	IngestionAuthStaticToken = "static-token"
+	IngestionAuthMTLS        = "mtls"
+	IngestionAuthNone        = "none"
+)
+
+// OTLP protocols the collector exports traces to Tempo with (must match k6 package)
+const (
+	ProtocolGRPC = "grpc"
+	ProtocolHTTP = "http"
+)
+
 // SetupCollector deploys OpenTelemetry Collector with RBAC
 // tempoVariant should be "monolithic" or "stack" to determine the gateway endpoint
 func SetupCollector(fw FrameworkOperations, tempoVariant string) error {
-	// Deploy RBAC first
-	if err := setupRBAC(fw); err != nil {
-		return fmt.Errorf("failed to setup OTel Collector RBAC: %w", err)
+	if err := setupCredentials(fw); err != nil {
+		return err
 	}
 
 	// Deploy Collector CR
-	if err := setupCollectorCR(fw, tempoVariant); err != nil {
+	collectorObj := buildCollectorCR(fw.Namespace(), CollectorName, DefaultTenant, tempoVariant, fw.GetTempoNodeSelector(), fw.GetIngestionAuth(), fw.GetIngestionProtocol(), fw.GetCollectorImage())
+	if err := setupCollectorCR(fw, collectorObj); err != nil {
 		return fmt.Errorf("failed to setup OTel Collector CR: %w", err)
 	}
 
 	// Wait for collector to be ready
-	return waitForCollectorReady(fw, 300*time.Second)
+	return waitForCollectorReady(fw, CollectorName, 300*time.Second)
+}
+
+// setupCredentials sets up the RBAC and, for the static-token ingestion auth, the
+// token the collector sending to Tempo authenticates with
+func setupCredentials(fw FrameworkOperations) error {
+	// Deploy RBAC first
+	if err := setupRBAC(fw); err != nil {
+		return fmt.Errorf("failed to setup OTel Collector RBAC: %w", err)
+	}
+
+	// The static token is read from a Secret the collector references
+	if fw.GetIngestionAuth() == IngestionAuthStaticToken {
+		if err := createStaticToken(fw, 60*time.Second); err != nil {
+			return fmt.Errorf("failed to setup OTel Collector token: %w", err)
+		}
+	}
+	return nil
+}
+
+// SetupTenantCollector deploys another collector sending traces as tenant, to load
+// Tempo with several tenants at once. It shares the ServiceAccount and token of the
+// collector of SetupCollector, which must be set up first.
+func SetupTenantCollector(fw FrameworkOperations, tempoVariant, tenant string) error {
+	if err := setupWriteRBAC(fw, tenant); err != nil {
+		return fmt.Errorf("failed to setup OTel Collector RBAC for tenant %s: %w", tenant, err)
+	}
+	name := TenantCollectorName(tenant)
+	collectorObj := buildCollectorCR(fw.Namespace(), name, tenant, tempoVariant, fw.GetTempoNodeSelector(), fw.GetIngestionAuth(), fw.GetIngestionProtocol(), fw.GetCollectorImage())
+	if err := setupCollectorCR(fw, collectorObj); err != nil {
+		return fmt.Errorf("failed to setup OTel Collector CR for tenant %s: %w", tenant, err)
+	}
+	return waitForCollectorReady(fw, name, 300*time.Second)
+}
+
+// TenantCollectorName returns the name of the collector of a tenant created by
+// SetupTenantCollector
+func TenantCollectorName(tenant string) string {
+	return CollectorName + "-" + tenant
+}
+
+// CollectorEndpoint returns the OTLP endpoint of a collector for protocol:
+// the gRPC receiver, or the base URL of the HTTP receiver
+func CollectorEndpoint(namespace, name, protocol string) string {
+	if protocol == ProtocolHTTP {
+		return fmt.Sprintf("http://%s-collector.%s.svc.cluster.local:4318", name, namespace)
+	}
+	return fmt.Sprintf("%s-collector.%s.svc.cluster.local:4317", name, namespace)
 }
 
 // setupRBAC sets up RBAC resources for OTel Collector
@@ -125,11 +203,42 @@ func setupRBAC(fw FrameworkOperations) error {
 		return fmt.Errorf("failed to create RoleBinding: %w", err)
 	}
 
+	return setupWriteRBAC(fw, DefaultTenant)
+}
+
+// setupWriteRBAC allows the collector ServiceAccount to write the traces of a tenant
+func setupWriteRBAC(fw FrameworkOperations, tenant string) error {
+	client := fw.Client()
+	ctx := fw.Context()
+
+	clusterRole, clusterRoleBinding := buildWriteRBAC(fw.Namespace(), tenant, fw.GetManagedLabels())
+	_, err := client.RbacV1().ClusterRoles().Create(ctx, clusterRole, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create ClusterRole: %w", err)
+	}
+	// Track ClusterRole
+	fw.TrackClusterResource(gvr.ClusterRole, clusterRole.Name)
+
+	_, err = client.RbacV1().ClusterRoleBindings().Create(ctx, clusterRoleBinding, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create ClusterRoleBinding: %w", err)
+	}
+	// Track ClusterRoleBinding
+	fw.TrackClusterResource(gvr.ClusterRoleBinding, clusterRoleBinding.Name)
+
+	return nil
+}
+
+// buildWriteRBAC returns the ClusterRole allowing to write the traces of a tenant
+// and its binding to the collector ServiceAccount of namespace
+func buildWriteRBAC(namespace, tenant string, managedLabels map[string]string) (*rbacv1.ClusterRole, *rbacv1.ClusterRoleBinding) {
 	// Generate unique names for cluster-scoped resources to avoid conflicts
 	clusterRoleName := fmt.Sprintf("allow-write-traces-%s", namespace)
-	clusterRoleBindingName := fmt.Sprintf("allow-write-traces-%s", namespace)
+	if tenant != DefaultTenant {
+		clusterRoleName = fmt.Sprintf("allow-write-traces-%s-%s", tenant, namespace)
+	}
+	clusterRoleBindingName := clusterRoleName
 
-	// Create ClusterRole
 	clusterRole := &rbacv1.ClusterRole{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:   clusterRoleName,
@@ -138,20 +247,13 @@ func setupRBAC(fw FrameworkOperations) error {
 		Rules: []rbacv1.PolicyRule{
 			{
 				APIGroups:     []string{"tempo.grafana.com"},
-				Resources:     []string{"tenant-1"},
+				Resources:     []string{tenant},
 				ResourceNames: []string{"traces"},
 				Verbs:         []string{"create"},
 			},
 		},
 	}
-	_, err = client.RbacV1().ClusterRoles().Create(ctx, clusterRole, metav1.CreateOptions{})
-	if err != nil && !apierrors.IsAlreadyExists(err) {
-		return fmt.Errorf("failed to create ClusterRole: %w", err)
-	}
-	// Track ClusterRole
-	fw.TrackClusterResource(gvr.ClusterRole, clusterRoleName)
 
-	// Create ClusterRoleBinding
 	clusterRoleBinding := &rbacv1.ClusterRoleBinding{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:   clusterRoleBindingName,
@@ -170,22 +272,17 @@ func setupRBAC(fw FrameworkOperations) error {
 			},
 		},
 	}
-	_, err = client.RbacV1().ClusterRoleBindings().Create(ctx, clusterRoleBinding, metav1.CreateOptions{})
-	if err != nil && !apierrors.IsAlreadyExists(err) {
-		return fmt.Errorf("failed to create ClusterRoleBinding: %w", err)
-	}
-	// Track ClusterRoleBinding
-	fw.TrackClusterResource(gvr.ClusterRoleBinding, clusterRoleBindingName)
-
-	return nil
+	return clusterRole, clusterRoleBinding
 }
 
-// setupCollectorCR sets up the OpenTelemetryCollector CR
-func setupCollectorCR(fw FrameworkOperations, tempoVariant string) error {
+// setupCollectorCR creates an OpenTelemetryCollector CR built by buildCollectorCR,
+// replacing an existing collector of the same name
+func setupCollectorCR(fw FrameworkOperations, collectorObj *unstructured.Unstructured) error {
 	namespace := fw.Namespace()
+	name := collectorObj.GetName()
 
 	// Delete existing collector if present to ensure clean configuration
-	err := fw.DynamicClient().Resource(CollectorGVR).Namespace(namespace).Delete(fw.Context(), "otel-collector", metav1.DeleteOptions{})
+	err := fw.DynamicClient().Resource(CollectorGVR).Namespace(namespace).Delete(fw.Context(), name, metav1.DeleteOptions{})
 	if err != nil && !apierrors.IsNotFound(err) {
 		return fmt.Errorf("failed to delete existing OpenTelemetryCollector: %w", err)
 	}
@@ -195,18 +292,7 @@ func setupCollectorCR(fw FrameworkOperations, tempoVariant string) error {
 		time.Sleep(5 * time.Second)
 	}
 
-	// Build OpenTelemetryCollector CR programmatically
-	collectorObj := buildCollectorCR(namespace, tempoVariant, fw.GetTempoNodeSelector())
-
-	// Add managed labels
-	labels := collectorObj.GetLabels()
-	if labels == nil {
-		labels = make(map[string]string)
-	}
-	for k, v := range fw.GetManagedLabels() {
-		labels[k] = v
-	}
-	collectorObj.SetLabels(labels)
+	addLabels(collectorObj, fw.GetManagedLabels())
 
 	// Create the collector CR
 	_, err = fw.DynamicClient().Resource(CollectorGVR).Namespace(namespace).Create(fw.Context(), collectorObj, metav1.CreateOptions{})
@@ -215,46 +301,83 @@ func setupCollectorCR(fw FrameworkOperations, tempoVariant string) error {
 	}
 
 	// Track the created resource for cleanup
-	fw.TrackCR(CollectorGVR, namespace, "otel-collector")
+	fw.TrackCR(CollectorGVR, namespace, name)
 
 	return nil
 }
 
-// waitForCollectorReady waits for OpenTelemetry Collector to be ready
-func waitForCollectorReady(fw FrameworkOperations, timeout time.Duration) error {
+// BuildCollector returns the OpenTelemetryCollector CR SetupCollector creates,
+// labeled with managedLabels
+func BuildCollector(namespace, tempoVariant string, tempoNodeSelector map[string]string, ingestionAuth, ingestionProtocol, image string, managedLabels map[string]string) *unstructured.Unstructured {
+	collectorObj := buildCollectorCR(namespace, CollectorName, DefaultTenant, tempoVariant, tempoNodeSelector, ingestionAuth, ingestionProtocol, image)
+	addLabels(collectorObj, managedLabels)
+	return collectorObj
+}
+
+// BuildTenantCollector returns the objects SetupTenantCollector creates for tenant,
+// in order, labeled with managedLabels: the ClusterRole and ClusterRoleBinding
+// allowing to write the traces of the tenant, and the OpenTelemetryCollector CR
+func BuildTenantCollector(namespace, tempoVariant, tenant string, tempoNodeSelector map[string]string, ingestionAuth, ingestionProtocol, image string, managedLabels map[string]string) []runtime.Object {
+	clusterRole, clusterRoleBinding := buildWriteRBAC(namespace, tenant, managedLabels)
+	collectorObj := buildCollectorCR(namespace, TenantCollectorName(tenant), tenant, tempoVariant, tempoNodeSelector, ingestionAuth, ingestionProtocol, image)
+	addLabels(collectorObj, managedLabels)
+	return []runtime.Object{clusterRole, clusterRoleBinding, collectorObj}
+}
+
+// addLabels adds the managed labels to a collector CR
+func addLabels(collectorObj *unstructured.Unstructured, managedLabels map[string]string) {
+	labels := collectorObj.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	for k, v := range managedLabels {
+		labels[k] = v
+	}
+	collectorObj.SetLabels(labels)
+}
+
+// waitForCollectorReady waits for the OpenTelemetry Collector of a CR to be ready
+func waitForCollectorReady(fw FrameworkOperations, name string, timeout time.Duration) error {
 	namespace := fw.Namespace()
 	client := fw.Client()
 	ctx := fw.Context()
-	deadline := time.Now().Add(timeout)
 
-	for time.Now().Before(deadline) {
+	selector := "app.kubernetes.io/name=opentelemetry-collector"
+	if name != CollectorName {
+		// The label above matches the pods of all collectors
+		selector = fmt.Sprintf("app.kubernetes.io/instance=%s.%s", namespace, name)
+	}
+
+	err := wait.ForCondition(ctx, wait.PollInterval(fw), timeout, func() (bool, error) {
 		// Check for deployment
-		for _, deploymentName := range []string{"otel-collector-collector", "otel-collector"} {
+		for _, deploymentName := range []string{name + "-collector", name} {
 			deployment, err := client.AppsV1().Deployments(namespace).Get(ctx, deploymentName, metav1.GetOptions{})
 			if err == nil {
 				if deployment.Status.ReadyReplicas == deployment.Status.Replicas &&
 					deployment.Status.ReadyReplicas > 0 {
-					return nil
+					return true, nil
 				}
 			}
 		}
 
 		// Check for pods directly
 		pods, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
-			LabelSelector: "app.kubernetes.io/name=opentelemetry-collector",
+			LabelSelector: selector,
 		})
 		if err == nil {
 			for _, pod := range pods.Items {
 				if wait.IsPodReady(&pod) {
-					return nil
+					return true, nil
 				}
 			}
 		}
 
-		time.Sleep(5 * time.Second)
+		return false, nil
+	})
+	if errors.Is(err, wait.ErrTimeout) {
+		return fmt.Errorf("otel collector %s not ready after %v", name, timeout)
 	}
-
-	return fmt.Errorf("otel collector not ready after %v", timeout)
+	return err
 }
 
 // buildNodeAntiAffinity creates a NodeAffinity structure for unstructured objects
@@ -295,8 +418,10 @@ func buildNodeAntiAffinityUnstructured(nodeSelector map[string]string) map[strin
 	}
 }
 
-// buildCollectorCR builds an OpenTelemetryCollector CR programmatically
-func buildCollectorCR(namespace string, tempoVariant string, tempoNodeSelector map[string]string) *unstructured.Unstructured {
+// buildCollectorCR builds an OpenTelemetryCollector CR programmatically, sending
+// traces as tenant through the gateway with the OTLP exporter of ingestionProtocol.
+// A non-empty image pins the collector image.
+func buildCollectorCR(namespace, name, tenant, tempoVariant string, tempoNodeSelector map[string]string, ingestionAuth, ingestionProtocol, image string) *unstructured.Unstructured {
 	// Determine Tempo gateway host based on variant
 	var crName string
 	switch tempoVariant {
@@ -307,61 +432,132 @@ func buildCollectorCR(namespace string, tempoVariant string, tempoNodeSelector m
 	default:
 		crName = MonolithicCRName
 	}
-	tempoGatewayHost := fmt.Sprintf("tempo-%s-gateway.%s.svc.cluster.local", crName, namespace)
+
+	// Both exporters are configured, the pipeline uses the one of the protocol
+	exporter := "otlp"
+	if ingestionProtocol == ProtocolHTTP {
+		exporter = "otlphttp"
+	}
+
+	config := map[string]interface{}{
+		"receivers": map[string]interface{}{
+			"otlp": map[string]interface{}{
+				"protocols": map[string]interface{}{
+					"grpc": map[string]interface{}{},
+					"http": map[string]interface{}{},
+				},
+			},
+		},
+		"service": map[string]interface{}{
+			"pipelines": map[string]interface{}{
+				"traces": map[string]interface{}{
+					"receivers": []interface{}{"otlp"},
+					"exporters": []interface{}{exporter},
+				},
+			},
+		},
+	}
 
 	spec := map[string]interface{}{
 		"mode":           "deployment",
 		"serviceAccount": "otel-collector-sa",
-		"config": map[string]interface{}{
-			"extensions": map[string]interface{}{
-				"bearertokenauth": map[string]interface{}{
-					"filename": "/var/run/secrets/kubernetes.io/serviceaccount/token",
-				},
+		// Create a ServiceMonitor for the collector's own metrics (otelcol_*), see
+		// SetupCollectorMonitoring
+		"observability": map[string]interface{}{
+			"metrics": map[string]interface{}{
+				"enableMetrics": true,
 			},
-			"receivers": map[string]interface{}{
-				"otlp": map[string]interface{}{
-					"protocols": map[string]interface{}{
-						"grpc": map[string]interface{}{},
-						"http": map[string]interface{}{},
-					},
+		},
+		"config": config,
+	}
+	if image != "" {
+		spec["image"] = image
+	}
+
+	switch ingestionAuth {
+	case IngestionAuthMTLS, IngestionAuthNone:
+		// Tempo runs without the gateway and multitenancy; send to its receivers directly
+		tempoHost := fmt.Sprintf("tempo-%s.%s.svc.cluster.local", crName, namespace)
+		grpcTLS := map[string]interface{}{"insecure": true}
+		if ingestionAuth == IngestionAuthMTLS {
+			grpcTLS = map[string]interface{}{
+				"ca_file":   ingestionTLSMountPath + "/ca.crt",
+				"cert_file": ingestionTLSMountPath + "/tls.crt",
+				"key_file":  ingestionTLSMountPath + "/tls.key",
+			}
+			spec["volumes"] = []interface{}{
+				map[string]interface{}{
+					"name":   "ingestion-tls",
+					"secret": map[string]interface{}{"secretName": IngestionClientTLSSecretName},
 				},
+			}
+			spec["volumeMounts"] = []interface{}{
+				map[string]interface{}{
+					"name":      "ingestion-tls",
+					"mountPath": ingestionTLSMountPath,
+					"readOnly":  true,
+				},
+			}
+		}
+		config["exporters"] = map[string]interface{}{
+			"otlp": map[string]interface{}{
+				"endpoint": fmt.Sprintf("%s:4317", tempoHost),
+				"tls":      grpcTLS,
 			},
-			"exporters": map[string]interface{}{
-				"otlp": map[string]interface{}{
-					"endpoint": fmt.Sprintf("%s:8090", tempoGatewayHost),
-					"tls": map[string]interface{}{
-						"ca_file": "/var/run/secrets/kubernetes.io/serviceaccount/service-ca.crt",
-					},
-					"auth": map[string]interface{}{
-						"authenticator": "bearertokenauth",
-					},
-					"headers": map[string]interface{}{
-						"X-Scope-OrgID": "tenant-1",
+			// OTLP/HTTP stays plaintext, see tempo.buildMonolithicIngestionSpec
+			"otlphttp": map[string]interface{}{
+				"endpoint": fmt.Sprintf("http://%s:4318", tempoHost),
+			},
+		}
+	default:
+		tempoGatewayHost := fmt.Sprintf("tempo-%s-gateway.%s.svc.cluster.local", crName, namespace)
+		bearerToken := map[string]interface{}{
+			"filename": "/var/run/secrets/kubernetes.io/serviceaccount/token",
+		}
+		if ingestionAuth == IngestionAuthStaticToken {
+			bearerToken = map[string]interface{}{"token": "${env:TEMPO_TOKEN}"}
+			spec["env"] = []interface{}{
+				map[string]interface{}{
+					"name": "TEMPO_TOKEN",
+					"valueFrom": map[string]interface{}{
+						"secretKeyRef": map[string]interface{}{
+							"name": StaticTokenSecretName,
+							"key":  "token",
+						},
 					},
 				},
-				"otlphttp": map[string]interface{}{
-					"endpoint": fmt.Sprintf("https://%s:8080/api/traces/v1/tenant-1", tempoGatewayHost),
-					"tls": map[string]interface{}{
-						"ca_file": "/var/run/secrets/kubernetes.io/serviceaccount/service-ca.crt",
-					},
-					"auth": map[string]interface{}{
-						"authenticator": "bearertokenauth",
-					},
-					"headers": map[string]interface{}{
-						"X-Scope-OrgID": "tenant-1",
-					},
+			}
+		}
+		config["extensions"] = map[string]interface{}{
+			"bearertokenauth": bearerToken,
+		}
+		config["service"].(map[string]interface{})["extensions"] = []interface{}{"bearertokenauth"}
+		config["exporters"] = map[string]interface{}{
+			"otlp": map[string]interface{}{
+				"endpoint": fmt.Sprintf("%s:8090", tempoGatewayHost),
+				"tls": map[string]interface{}{
+					"ca_file": "/var/run/secrets/kubernetes.io/serviceaccount/service-ca.crt",
+				},
+				"auth": map[string]interface{}{
+					"authenticator": "bearertokenauth",
+				},
+				"headers": map[string]interface{}{
+					"X-Scope-OrgID": tenant,
 				},
 			},
-			"service": map[string]interface{}{
-				"extensions": []interface{}{"bearertokenauth"},
-				"pipelines": map[string]interface{}{
-					"traces": map[string]interface{}{
-						"receivers": []interface{}{"otlp"},
-						"exporters": []interface{}{"otlp"},
-					},
+			"otlphttp": map[string]interface{}{
+				"endpoint": fmt.Sprintf("https://%s:8080/api/traces/v1/%s", tempoGatewayHost, tenant),
+				"tls": map[string]interface{}{
+					"ca_file": "/var/run/secrets/kubernetes.io/serviceaccount/service-ca.crt",
+				},
+				"auth": map[string]interface{}{
+					"authenticator": "bearertokenauth",
+				},
+				"headers": map[string]interface{}{
+					"X-Scope-OrgID": tenant,
 				},
 			},
-		},
+		}
 	}
 
 	// Add anti-affinity to avoid Tempo nodes if node selector is set
@@ -374,7 +570,7 @@ func buildCollectorCR(namespace string, tempoVariant string, tempoNodeSelector m
 			"apiVersion": "opentelemetry.io/v1beta1",
 			"kind":       "OpenTelemetryCollector",
 			"metadata": map[string]interface{}{
-				"name":      "otel-collector",
+				"name":      name,
 				"namespace": namespace,
 			},
 			"spec": spec,
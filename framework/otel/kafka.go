@@ -0,0 +1,155 @@
+package otel
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// KafkaCollectorName is the name of the collector SetupKafkaCollectors creates to
+// consume the traces from Kafka and forward them to Tempo
+const KafkaCollectorName = "otel-collector-kafka"
+
+// DefaultKafkaTopic is the topic traces are written to when KafkaConfig sets none,
+// the default of the Kafka exporter
+const DefaultKafkaTopic = "otlp_spans"
+
+// kafkaProtocolVersion is the Kafka protocol version of the exporter and receiver,
+// which older collectors require to be set
+const kafkaProtocolVersion = "2.0.0"
+
+// KafkaConfig is the Kafka traces are buffered in between the collector k6 sends
+// to and the collector forwarding them to Tempo
+type KafkaConfig struct {
+	// Brokers are the bootstrap addresses of the Kafka cluster (host:port)
+	Brokers []string
+
+	// Topic is the topic the traces are written to and read from.
+	// Default: DefaultKafkaTopic
+	Topic string
+
+	// GroupID is the consumer group of the collector reading the topic.
+	// Default: KafkaCollectorName
+	GroupID string
+
+	// MaxMessageBytes is the largest message the brokers accept (optional). Without
+	// it the exporter keeps the 1MB default of Kafka.
+	MaxMessageBytes int
+}
+
+// topic returns the topic of the traces
+func (k *KafkaConfig) topic() string {
+	if k.Topic == "" {
+		return DefaultKafkaTopic
+	}
+	return k.Topic
+}
+
+// groupID returns the consumer group of the collector reading the topic
+func (k *KafkaConfig) groupID() string {
+	if k.GroupID == "" {
+		return KafkaCollectorName
+	}
+	return k.GroupID
+}
+
+// SetupKafkaCollectors deploys the collectors of the Kafka ingestion path in place
+// of SetupCollector: a collector consuming the topic of kafka and forwarding the
+// traces to Tempo like the collector of SetupCollector does, and the collector k6
+// sends to, which writes the traces to the topic. The consumer is set up first, so
+// it reads the topic from its first message.
+func SetupKafkaCollectors(fw FrameworkOperations, tempoVariant string, kafka *KafkaConfig) error {
+	if err := setupCredentials(fw); err != nil {
+		return err
+	}
+
+	consumer := buildKafkaConsumerCR(fw.Namespace(), tempoVariant, fw.GetTempoNodeSelector(), fw.GetIngestionAuth(), fw.GetIngestionProtocol(), fw.GetCollectorImage(), kafka)
+	if err := setupCollectorCR(fw, consumer); err != nil {
+		return fmt.Errorf("failed to setup Kafka consumer OTel Collector CR: %w", err)
+	}
+	if err := waitForCollectorReady(fw, KafkaCollectorName, 300*time.Second); err != nil {
+		return err
+	}
+
+	producer := buildKafkaProducerCR(fw.Namespace(), tempoVariant, fw.GetTempoNodeSelector(), fw.GetCollectorImage(), kafka)
+	if err := setupCollectorCR(fw, producer); err != nil {
+		return fmt.Errorf("failed to setup OTel Collector CR: %w", err)
+	}
+	return waitForCollectorReady(fw, CollectorName, 300*time.Second)
+}
+
+// BuildKafkaCollectors returns the OpenTelemetryCollector CRs SetupKafkaCollectors
+// creates, in order, labeled with managedLabels
+func BuildKafkaCollectors(namespace, tempoVariant string, tempoNodeSelector map[string]string, ingestionAuth, ingestionProtocol, image string, kafka *KafkaConfig, managedLabels map[string]string) []*unstructured.Unstructured {
+	collectors := []*unstructured.Unstructured{
+		buildKafkaConsumerCR(namespace, tempoVariant, tempoNodeSelector, ingestionAuth, ingestionProtocol, image, kafka),
+		buildKafkaProducerCR(namespace, tempoVariant, tempoNodeSelector, image, kafka),
+	}
+	for _, collectorObj := range collectors {
+		addLabels(collectorObj, managedLabels)
+	}
+	return collectors
+}
+
+// buildKafkaConsumerCR builds the collector reading the traces from the Kafka topic
+// and sending them to Tempo as the default tenant, like buildCollectorCR
+func buildKafkaConsumerCR(namespace, tempoVariant string, tempoNodeSelector map[string]string, ingestionAuth, ingestionProtocol, image string, kafka *KafkaConfig) *unstructured.Unstructured {
+	collectorObj := buildCollectorCR(namespace, KafkaCollectorName, DefaultTenant, tempoVariant, tempoNodeSelector, ingestionAuth, ingestionProtocol, image)
+	config := collectorConfig(collectorObj)
+	config["receivers"] = map[string]interface{}{
+		"kafka": map[string]interface{}{
+			"brokers":          brokerList(kafka),
+			"topic":            kafka.topic(),
+			"encoding":         "otlp_proto",
+			"protocol_version": kafkaProtocolVersion,
+			"group_id":         kafka.groupID(),
+			// Traces written before the consumer group first commits are not skipped
+			"initial_offset": "earliest",
+		},
+	}
+	tracesPipeline(config)["receivers"] = []interface{}{"kafka"}
+	return collectorObj
+}
+
+// buildKafkaProducerCR builds the collector k6 sends to, which writes the traces
+// to the Kafka topic instead of sending them to Tempo
+func buildKafkaProducerCR(namespace, tempoVariant string, tempoNodeSelector map[string]string, image string, kafka *KafkaConfig) *unstructured.Unstructured {
+	// Without authentication to Tempo the collector needs no token or certificate
+	collectorObj := buildCollectorCR(namespace, CollectorName, DefaultTenant, tempoVariant, tempoNodeSelector, IngestionAuthNone, "", image)
+	exporter := map[string]interface{}{
+		"brokers":          brokerList(kafka),
+		"topic":            kafka.topic(),
+		"encoding":         "otlp_proto",
+		"protocol_version": kafkaProtocolVersion,
+	}
+	if kafka.MaxMessageBytes > 0 {
+		exporter["producer"] = map[string]interface{}{
+			"max_message_bytes": int64(kafka.MaxMessageBytes),
+		}
+	}
+	config := collectorConfig(collectorObj)
+	config["exporters"] = map[string]interface{}{"kafka": exporter}
+	tracesPipeline(config)["exporters"] = []interface{}{"kafka"}
+	return collectorObj
+}
+
+// collectorConfig returns the collector configuration of a CR built by buildCollectorCR
+func collectorConfig(collectorObj *unstructured.Unstructured) map[string]interface{} {
+	return collectorObj.Object["spec"].(map[string]interface{})["config"].(map[string]interface{})
+}
+
+// tracesPipeline returns the traces pipeline of a collector configuration
+func tracesPipeline(config map[string]interface{}) map[string]interface{} {
+	pipelines := config["service"].(map[string]interface{})["pipelines"].(map[string]interface{})
+	return pipelines["traces"].(map[string]interface{})
+}
+
+// brokerList returns the brokers of kafka as a list of the collector configuration
+func brokerList(kafka *KafkaConfig) []interface{} {
+	brokers := make([]interface{}, 0, len(kafka.Brokers))
+	for _, b := range kafka.Brokers {
+		brokers = append(brokers, b)
+	}
+	return brokers
+}
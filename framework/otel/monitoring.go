@@ -0,0 +1,128 @@
+package otel
+
+import (
+	"fmt"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/gvr"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// collectorPodMonitorName is the PodMonitor created when the operator did not create
+// a ServiceMonitor for the collector
+const collectorPodMonitorName = "otel-collector-pods"
+
+// collectorMetricsPort is the container port of the collector's own telemetry,
+// added by the operator
+const collectorMetricsPort = "metrics"
+
+// SetupCollectorMonitoring makes sure Prometheus scrapes the collector's own metrics
+// (otelcol_*). The operator creates a ServiceMonitor for them when the CR enables
+// metrics; without one a PodMonitor is created, so the collector is never scraped twice.
+func SetupCollectorMonitoring(fw FrameworkOperations) error {
+	fmt.Println("\n📊 Setting up OTel Collector metrics monitoring...")
+
+	list, err := fw.DynamicClient().Resource(gvr.ServiceMonitor).Namespace(fw.Namespace()).List(fw.Context(), metav1.ListOptions{
+		LabelSelector: "app.kubernetes.io/managed-by=opentelemetry-operator",
+	})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to list collector ServiceMonitors: %w", err)
+	}
+	if err == nil && len(list.Items) > 0 {
+		names := make([]string, 0, len(list.Items))
+		for _, item := range list.Items {
+			names = append(names, item.GetName())
+		}
+		fmt.Printf("✅ Found %d ServiceMonitor(s) for the OTel Collector: %v\n", len(names), names)
+		return nil
+	}
+
+	fmt.Println("📦 Creating PodMonitor for OTel Collector metrics...")
+	return ensureCollectorPodMonitor(fw)
+}
+
+// ensureCollectorPodMonitor creates a PodMonitor scraping the collector pods
+func ensureCollectorPodMonitor(fw FrameworkOperations) error {
+	namespace := fw.Namespace()
+	ctx := fw.Context()
+
+	_, err := fw.DynamicClient().Resource(gvr.PodMonitor).Namespace(namespace).Get(ctx, collectorPodMonitorName, metav1.GetOptions{})
+	if err == nil {
+		fmt.Printf("✅ PodMonitor %s already exists\n", collectorPodMonitorName)
+		return nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to check collector PodMonitor: %w", err)
+	}
+
+	podMonitor := BuildPodMonitor(namespace, fw.GetManagedLabels())
+	_, err = fw.DynamicClient().Resource(gvr.PodMonitor).Namespace(namespace).Create(ctx, podMonitor, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create collector PodMonitor: %w", err)
+	}
+	fw.TrackCR(gvr.PodMonitor, namespace, collectorPodMonitorName)
+
+	fmt.Printf("✅ Created PodMonitor %s for OTel Collector metrics\n", collectorPodMonitorName)
+	return nil
+}
+
+// BuildPodMonitor returns the PodMonitor SetupCollectorMonitoring creates when the
+// operator did not create a ServiceMonitor, labeled with managedLabels
+func BuildPodMonitor(namespace string, managedLabels map[string]string) *unstructured.Unstructured {
+	podMonitor := buildCollectorPodMonitor(namespace)
+	labels := podMonitor.GetLabels()
+	for k, v := range managedLabels {
+		labels[k] = v
+	}
+	podMonitor.SetLabels(labels)
+	return podMonitor
+}
+
+// buildCollectorPodMonitor builds a PodMonitor for the metrics port of the collector
+// pods, labelling the samples with their namespace and pod
+func buildCollectorPodMonitor(namespace string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "monitoring.coreos.com/v1",
+			"kind":       "PodMonitor",
+			"metadata": map[string]interface{}{
+				"name":      collectorPodMonitorName,
+				"namespace": namespace,
+				"labels": map[string]interface{}{
+					"app.kubernetes.io/name":       "otel-collector",
+					"app.kubernetes.io/managed-by": "perf-tests",
+				},
+			},
+			"spec": map[string]interface{}{
+				"selector": map[string]interface{}{
+					"matchLabels": map[string]interface{}{
+						"app.kubernetes.io/component":  "opentelemetry-collector",
+						"app.kubernetes.io/managed-by": "opentelemetry-operator",
+					},
+				},
+				"namespaceSelector": map[string]interface{}{
+					"matchNames": []interface{}{namespace},
+				},
+				"podMetricsEndpoints": []interface{}{
+					map[string]interface{}{
+						"port":     collectorMetricsPort,
+						"path":     "/metrics",
+						"interval": "30s",
+						"relabelings": []interface{}{
+							map[string]interface{}{
+								"sourceLabels": []interface{}{"__meta_kubernetes_namespace"},
+								"targetLabel":  "namespace",
+							},
+							map[string]interface{}{
+								"sourceLabels": []interface{}{"__meta_kubernetes_pod_name"},
+								"targetLabel":  "pod",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,24 @@
+package framework
+
+import (
+	"slices"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/metrics"
+)
+
+// RecordPhase records the time window of a test phase. Metrics collected
+// afterwards are split at the recorded phase boundaries and labeled with the phase.
+func (f *Framework) RecordPhase(name string, start, end time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.phases = append(f.phases, metrics.Phase{Name: name, Start: start, End: end})
+	f.logger.Info("Recorded test phase", "phase", name, "duration", end.Sub(start).Round(time.Second))
+}
+
+// Phases returns the recorded test phases in order
+func (f *Framework) Phases() []metrics.Phase {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.phases)
+}
@@ -3,10 +3,18 @@ package framework
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/redhat/perf-tests-tempo/test/framework/config"
+	"github.com/redhat/perf-tests-tempo/test/framework/gvr"
+	"github.com/redhat/perf-tests-tempo/test/framework/metrics"
+
+	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 // PrerequisiteStatus represents the status of a single prerequisite
@@ -35,6 +43,31 @@ var (
 	}
 )
 
+// operatorPrerequisite describes how to find the installation of a required operator
+type operatorPrerequisite struct {
+	name string
+	crds []string
+	// packages are the OLM package names of the operator, product first
+	packages []string
+	// deployment is the name prefix of the operator Deployment, for installs without OLM
+	deployment string
+}
+
+var operatorPrerequisites = []operatorPrerequisite{
+	{
+		name:       "Tempo Operator",
+		crds:       tempoCRDs,
+		packages:   []string{"tempo-product", "tempo-operator"},
+		deployment: "tempo-operator",
+	},
+	{
+		name:       "OpenTelemetry Operator",
+		crds:       openTelemetryCRDs,
+		packages:   []string{"opentelemetry-product", "opentelemetry-operator"},
+		deployment: "opentelemetry-operator",
+	},
+}
+
 // CheckPrerequisites verifies that required operators are installed in the cluster
 func (f *Framework) CheckPrerequisites() (*PrerequisitesResult, error) {
 	apiextClient, err := apiextensionsclient.NewForConfig(f.restConfig)
@@ -108,6 +141,167 @@ func isCRDEstablished(crd *apiextensionsv1.CustomResourceDefinition) bool {
 	return false
 }
 
+// PrerequisitesReport returns the state of the cluster-scoped prerequisites of a run:
+// the version, channel and namespace of each operator, the conditions of their CRDs
+// and the user workload monitoring state. Lookups that fail are recorded in the
+// report rather than returned, so a report is available on partially readable clusters.
+func (f *Framework) PrerequisitesReport() (*metrics.PrerequisitesReport, error) {
+	apiextClient, err := apiextensionsclient.NewForConfig(f.restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create apiextensions client: %w", err)
+	}
+
+	report := &metrics.PrerequisitesReport{
+		CheckedAt: time.Now().UTC(),
+		AllMet:    true,
+	}
+
+	// Clusters without OLM have no Subscription resource, their operators are
+	// found by their Deployment instead
+	subscriptions, err := f.dynamicClient.Resource(gvr.Subscription).Namespace("").List(f.ctx, metav1.ListOptions{})
+	if err != nil {
+		f.logger.Debug("OLM subscriptions are not available", "error", err)
+		subscriptions = nil
+	}
+
+	for _, op := range operatorPrerequisites {
+		operator := metrics.OperatorReport{Name: op.name, Installed: true}
+		for _, name := range op.crds {
+			crd := crdReport(f.ctx, apiextClient, name)
+			if !crd.Established {
+				operator.Installed = false
+			}
+			operator.CRDs = append(operator.CRDs, crd)
+		}
+		if !operator.Installed {
+			report.AllMet = false
+		}
+		if err := f.lookupOperator(&operator, op, subscriptions); err != nil {
+			operator.Error = err.Error()
+		}
+		report.Operators = append(report.Operators, operator)
+	}
+
+	report.Monitoring = f.monitoringReport()
+	return report, nil
+}
+
+// crdReport returns the served versions and conditions of a CRD
+func crdReport(ctx context.Context, client apiextensionsclient.Interface, name string) metrics.CRDReport {
+	report := metrics.CRDReport{Name: name}
+	crd, err := client.ApiextensionsV1().CustomResourceDefinitions().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return report
+	}
+
+	report.Found = true
+	report.Established = isCRDEstablished(crd)
+	for _, v := range crd.Spec.Versions {
+		if v.Served {
+			report.Versions = append(report.Versions, v.Name)
+		}
+	}
+	for _, c := range crd.Status.Conditions {
+		report.Conditions = append(report.Conditions, metrics.CRDCondition{
+			Type:               string(c.Type),
+			Status:             string(c.Status),
+			Reason:             c.Reason,
+			Message:            c.Message,
+			LastTransitionTime: c.LastTransitionTime.Time,
+		})
+	}
+	return report
+}
+
+// lookupOperator fills in the installation of an operator, from its OLM Subscription
+// and ClusterServiceVersion, or else from its Deployment in the operator namespaces
+func (f *Framework) lookupOperator(report *metrics.OperatorReport, op operatorPrerequisite, subscriptions *unstructured.UnstructuredList) error {
+	if sub := findSubscription(subscriptions, op.packages); sub != nil {
+		report.Namespace = sub.GetNamespace()
+		report.Package, _, _ = unstructured.NestedString(sub.Object, "spec", "name")
+		report.Channel, _, _ = unstructured.NestedString(sub.Object, "spec", "channel")
+		report.CSV, _, _ = unstructured.NestedString(sub.Object, "status", "installedCSV")
+		if report.CSV == "" {
+			return nil
+		}
+		csv, err := f.dynamicClient.Resource(gvr.ClusterServiceVersion).Namespace(report.Namespace).Get(f.ctx, report.CSV, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get cluster service version %s: %w", report.CSV, err)
+		}
+		report.Version, _, _ = unstructured.NestedString(csv.Object, "spec", "version")
+		return nil
+	}
+
+	namespaces := f.config.OperatorNamespaces
+	if len(namespaces) == 0 {
+		namespaces = config.DefaultOperatorNamespaces
+	}
+	for _, ns := range namespaces {
+		deployments, err := f.client.AppsV1().Deployments(ns).List(f.ctx, metav1.ListOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to list deployments in %s: %w", ns, err)
+		}
+		for _, d := range deployments.Items {
+			if !strings.HasPrefix(d.Name, op.deployment) {
+				continue
+			}
+			report.Namespace = ns
+			report.Deployment = d.Name
+			report.Version = d.Labels["app.kubernetes.io/version"]
+			for _, c := range d.Spec.Template.Spec.Containers {
+				if c.Name == "manager" || report.Image == "" {
+					report.Image = c.Image
+				}
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
+// findSubscription returns the subscription to the first of packages found
+func findSubscription(subscriptions *unstructured.UnstructuredList, packages []string) *unstructured.Unstructured {
+	if subscriptions == nil {
+		return nil
+	}
+	for _, pkg := range packages {
+		for i := range subscriptions.Items {
+			name, _, _ := unstructured.NestedString(subscriptions.Items[i].Object, "spec", "name")
+			if name == pkg {
+				return &subscriptions.Items[i]
+			}
+		}
+	}
+	return nil
+}
+
+// monitoringReport returns the state of user workload monitoring
+func (f *Framework) monitoringReport() metrics.MonitoringReport {
+	var report metrics.MonitoringReport
+	enabled, err := f.IsUserWorkloadMonitoringEnabled()
+	if err != nil {
+		report.Error = err.Error()
+		return report
+	}
+	report.UserWorkloadEnabled = enabled
+
+	pods, err := f.client.CoreV1().Pods(userWorkloadMonitoringNS).List(f.ctx, metav1.ListOptions{
+		LabelSelector: "app.kubernetes.io/name=prometheus",
+	})
+	if err != nil {
+		report.Error = fmt.Sprintf("failed to list user workload Prometheus pods: %v", err)
+		return report
+	}
+	for _, pod := range pods.Items {
+		for _, cond := range pod.Status.Conditions {
+			if cond.Type == corev1.PodReady && cond.Status == corev1.ConditionTrue {
+				report.UserWorkloadPrometheusReady++
+			}
+		}
+	}
+	return report
+}
+
 // String returns a human-readable summary of the prerequisites result
 func (r *PrerequisitesResult) String() string {
 	tempoStatus := "✓"
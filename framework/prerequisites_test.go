@@ -0,0 +1,150 @@
+package framework
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/config"
+	"github.com/redhat/perf-tests-tempo/test/framework/metrics"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newPrerequisitesTestFramework(objects []runtime.Object, dynamicObjects ...runtime.Object) *Framework {
+	return &Framework{
+		client:        fake.NewSimpleClientset(objects...),
+		dynamicClient: dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), dynamicObjects...),
+		ctx:           context.Background(),
+		logger:        slog.Default(),
+		config:        config.Default(),
+	}
+}
+
+func TestLookupOperator_Subscription(t *testing.T) {
+	csv := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "operators.coreos.com/v1alpha1",
+		"kind":       "ClusterServiceVersion",
+		"metadata":   map[string]interface{}{"name": "tempo-operator.v0.15.3", "namespace": "openshift-tempo-operator"},
+		"spec":       map[string]interface{}{"version": "0.15.3"},
+	}}
+	subscriptions := &unstructured.UnstructuredList{Items: []unstructured.Unstructured{
+		{Object: map[string]interface{}{
+			"metadata": map[string]interface{}{"name": "otel", "namespace": "openshift-opentelemetry-operator"},
+			"spec":     map[string]interface{}{"name": "opentelemetry-product", "channel": "stable"},
+		}},
+		{Object: map[string]interface{}{
+			"metadata": map[string]interface{}{"name": "tempo", "namespace": "openshift-tempo-operator"},
+			"spec":     map[string]interface{}{"name": "tempo-product", "channel": "stable"},
+			"status":   map[string]interface{}{"installedCSV": "tempo-operator.v0.15.3"},
+		}},
+	}}
+	f := newPrerequisitesTestFramework(nil, csv)
+
+	var report metrics.OperatorReport
+	if err := f.lookupOperator(&report, operatorPrerequisites[0], subscriptions); err != nil {
+		t.Fatalf("lookupOperator() error = %v", err)
+	}
+	want := metrics.OperatorReport{
+		Namespace: "openshift-tempo-operator",
+		Version:   "0.15.3",
+		Package:   "tempo-product",
+		Channel:   "stable",
+		CSV:       "tempo-operator.v0.15.3",
+	}
+	if report.Namespace != want.Namespace || report.Version != want.Version || report.Package != want.Package ||
+		report.Channel != want.Channel || report.CSV != want.CSV || report.Deployment != "" {
+		t.Errorf("lookupOperator() = %+v, want %+v", report, want)
+	}
+}
+
+func TestLookupOperator_Deployment(t *testing.T) {
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "opentelemetry-operator-controller-manager",
+			Namespace: "opentelemetry-operator-system",
+			Labels:    map[string]string{"app.kubernetes.io/version": "0.120.0"},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "kube-rbac-proxy", Image: "quay.io/brancz/kube-rbac-proxy:v0.13.1"},
+						{Name: "manager", Image: "ghcr.io/open-telemetry/opentelemetry-operator:0.120.0"},
+					},
+				},
+			},
+		},
+	}
+	f := newPrerequisitesTestFramework([]runtime.Object{deployment})
+
+	var report metrics.OperatorReport
+	if err := f.lookupOperator(&report, operatorPrerequisites[1], nil); err != nil {
+		t.Fatalf("lookupOperator() error = %v", err)
+	}
+	if report.Namespace != "opentelemetry-operator-system" {
+		t.Errorf("expected namespace opentelemetry-operator-system, got %q", report.Namespace)
+	}
+	if report.Deployment != deployment.Name {
+		t.Errorf("expected deployment %s, got %q", deployment.Name, report.Deployment)
+	}
+	if report.Version != "0.120.0" {
+		t.Errorf("expected version 0.120.0, got %q", report.Version)
+	}
+	if report.Image != "ghcr.io/open-telemetry/opentelemetry-operator:0.120.0" {
+		t.Errorf("expected the manager image, got %q", report.Image)
+	}
+}
+
+func TestLookupOperator_NotFound(t *testing.T) {
+	f := newPrerequisitesTestFramework(nil)
+
+	var report metrics.OperatorReport
+	if err := f.lookupOperator(&report, operatorPrerequisites[0], &unstructured.UnstructuredList{}); err != nil {
+		t.Fatalf("lookupOperator() error = %v", err)
+	}
+	if report.Namespace != "" || report.Version != "" {
+		t.Errorf("expected no installation, got %+v", report)
+	}
+}
+
+func TestMonitoringReport(t *testing.T) {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: clusterMonitoringConfigMap, Namespace: monitoringNamespace},
+		Data:       map[string]string{"config.yaml": "enableUserWorkload: true\n"},
+	}
+	pod := func(name string, ready corev1.ConditionStatus) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: userWorkloadMonitoringNS,
+				Labels:    map[string]string{"app.kubernetes.io/name": "prometheus"},
+			},
+			Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}},
+			},
+		}
+	}
+	f := newPrerequisitesTestFramework([]runtime.Object{
+		cm,
+		pod("prometheus-user-workload-0", corev1.ConditionTrue),
+		pod("prometheus-user-workload-1", corev1.ConditionFalse),
+	})
+
+	report := f.monitoringReport()
+	if !report.UserWorkloadEnabled {
+		t.Error("expected user workload monitoring to be enabled")
+	}
+	if report.UserWorkloadPrometheusReady != 1 {
+		t.Errorf("expected 1 ready Prometheus pod, got %d", report.UserWorkloadPrometheusReady)
+	}
+	if report.Error != "" {
+		t.Errorf("expected no error, got %q", report.Error)
+	}
+}
@@ -1,34 +1,171 @@
 package profile
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
+	"net/url"
 	"os"
 	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"sigs.k8s.io/yaml"
 )
 
-// Load reads a profile from a YAML file
+// Load reads a profile from a YAML file. A profile that extends another one is
+// deep-merged over it, see Profile.Extends.
 func Load(path string) (*Profile, error) {
-	data, err := os.ReadFile(path)
+	raw, err := loadRaw(path, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read profile file %s: %w", path, err)
+		return nil, err
+	}
+
+	profile, err := decode(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid profile %s: %w", path, err)
+	}
+	return profile, nil
+}
+
+// Parse decodes and validates a profile from YAML. Fields the profile does not
+// define, e.g. misspelled ones, are errors rather than silently ignored. Profiles
+// that extend another one must be read with Load, which finds the base profile.
+func Parse(data []byte) (*Profile, error) {
+	raw, err := parseRaw(data)
+	if err != nil {
+		return nil, err
+	}
+	if base, ok := raw["extends"]; ok {
+		return nil, fmt.Errorf("profile extends %v: profiles that extend another one must be read from a file with Load", base)
+	}
+	return decode(raw)
+}
+
+// parseRaw decodes YAML into generic maps and checks it for unknown fields
+func parseRaw(data []byte) (map[string]interface{}, error) {
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	if raw == nil {
+		return map[string]interface{}{}, nil
+	}
+	m, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("failed to parse YAML: a profile must be a mapping")
+	}
+	if err := checkUnknownFields(m, reflect.TypeOf(Profile{}), ""); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// decode converts a profile decoded into generic maps to a Profile and validates it
+func decode(raw map[string]interface{}) (*Profile, error) {
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode profile: %w", err)
 	}
 
 	var profile Profile
 	if err := yaml.Unmarshal(data, &profile); err != nil {
-		return nil, fmt.Errorf("failed to parse profile %s: %w", path, err)
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
 	}
 
 	if err := Validate(&profile); err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// loadRaw reads the profile at path into generic maps, merged over the profiles it
+// extends. chain holds the paths of the profiles extending it, to detect cycles.
+func loadRaw(path string, chain []string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profile file %s: %w", path, err)
+	}
+	raw, err := parseRaw(data)
+	if err != nil {
 		return nil, fmt.Errorf("invalid profile %s: %w", path, err)
 	}
 
-	return &profile, nil
+	value, ok := raw["extends"]
+	if !ok {
+		return raw, nil
+	}
+	base, ok := value.(string)
+	if !ok || strings.TrimSpace(base) == "" {
+		return nil, fmt.Errorf("invalid profile %s: extends must be a profile name", path)
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve profile path %s: %w", path, err)
+	}
+	chain = append(chain, abs)
+	basePath := profilePath(filepath.Dir(path), base)
+	if baseAbs, err := filepath.Abs(basePath); err == nil && slices.Contains(chain, baseAbs) {
+		names := make([]string, 0, len(chain)+1)
+		for _, p := range append(chain, baseAbs) {
+			names = append(names, strings.TrimSuffix(filepath.Base(p), filepath.Ext(p)))
+		}
+		return nil, fmt.Errorf("profile inheritance cycle: %s", strings.Join(names, " -> "))
+	}
+
+	baseRaw, err := loadRaw(basePath, chain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load profile %q extended by %s: %w", base, path, err)
+	}
+	// The name identifies a single profile, so it is never inherited
+	delete(baseRaw, "name")
+	return mergeRaw(baseRaw, raw), nil
+}
+
+// mergeRaw deep-merges override into base: nested mappings are merged field by
+// field, while lists and other values replace those of base. A null value removes
+// the field of base.
+func mergeRaw(base, override map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(base)+len(override))
+	for key, value := range base {
+		merged[key] = value
+	}
+	for key, value := range override {
+		if value == nil {
+			delete(merged, key)
+			continue
+		}
+		baseMap, baseIsMap := merged[key].(map[string]interface{})
+		overrideMap, overrideIsMap := value.(map[string]interface{})
+		if baseIsMap && overrideIsMap {
+			merged[key] = mergeRaw(baseMap, overrideMap)
+			continue
+		}
+		merged[key] = value
+	}
+	return merged
+}
+
+// profilePath returns the file of the named profile in dir, .yaml or else .yml
+func profilePath(dir, name string) string {
+	path := filepath.Join(dir, name+".yaml")
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		path = filepath.Join(dir, name+".yml")
+	}
+	return path
 }
 
-// LoadAll reads all YAML profiles from a directory
+// LoadAll reads all YAML profiles from a directory. All profiles are checked, and
+// the errors of every invalid one are returned together.
 func LoadAll(dir string) ([]*Profile, error) {
 	entries, err := os.ReadDir(dir)
 	if err != nil {
@@ -36,6 +173,7 @@ func LoadAll(dir string) ([]*Profile, error) {
 	}
 
 	var profiles []*Profile
+	var errs []error
 	for _, entry := range entries {
 		if entry.IsDir() {
 			continue
@@ -47,36 +185,40 @@ func LoadAll(dir string) ([]*Profile, error) {
 
 		profile, err := Load(filepath.Join(dir, name))
 		if err != nil {
-			return nil, err
+			errs = append(errs, err)
+			continue
 		}
 		profiles = append(profiles, profile)
 	}
 
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
 	return profiles, nil
 }
 
-// LoadByNames loads specific profiles by name from a directory
+// LoadByNames loads specific profiles by name from a directory. All profiles are
+// checked, and the errors of every invalid one are returned together.
 func LoadByNames(dir string, names []string) ([]*Profile, error) {
 	var profiles []*Profile
+	var errs []error
 	for _, name := range names {
 		name = strings.TrimSpace(name)
 		if name == "" {
 			continue
 		}
 
-		// Try with .yaml extension first, then .yml
-		path := filepath.Join(dir, name+".yaml")
-		if _, err := os.Stat(path); os.IsNotExist(err) {
-			path = filepath.Join(dir, name+".yml")
-		}
-
-		profile, err := Load(path)
+		profile, err := Load(profilePath(dir, name))
 		if err != nil {
-			return nil, fmt.Errorf("failed to load profile %q: %w", name, err)
+			errs = append(errs, fmt.Errorf("failed to load profile %q: %w", name, err))
+			continue
 		}
 		profiles = append(profiles, profile)
 	}
 
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
 	return profiles, nil
 }
 
@@ -103,8 +245,62 @@ func Validate(p *Profile) error {
 		}
 	}
 
+	if err := validateAutoscaling(&p.Tempo); err != nil {
+		return err
+	}
+	if err := validateEnv(p.Tempo.Env); err != nil {
+		return err
+	}
+	if err := validateStorage(p); err != nil {
+		return err
+	}
+	if err := validateIngestionAuth(&p.Tempo); err != nil {
+		return err
+	}
+	if err := validateMetricsGenerator(&p.Tempo); err != nil {
+		return err
+	}
+	if err := validateReplicas(p); err != nil {
+		return err
+	}
+	if err := validateBlock(p.Tempo.Block); err != nil {
+		return err
+	}
+	if err := validateExtraConfig(&p.Tempo); err != nil {
+		return err
+	}
+	if o := p.Tempo.Overrides; o != nil && o.Ingester != nil {
+		for _, f := range [][2]string{
+			{"flushCheckPeriod", o.Ingester.FlushCheckPeriod},
+			{"traceIdlePeriod", o.Ingester.TraceIdlePeriod},
+			{"maxBlockDuration", o.Ingester.MaxBlockDuration},
+		} {
+			if err := validateDuration("tempo.overrides.ingester."+f[0], f[1]); err != nil {
+				return err
+			}
+		}
+	}
+	if err := validateImages(p.Images); err != nil {
+		return err
+	}
+	if err := validateQuota(p.Quota); err != nil {
+		return err
+	}
+	if s := p.SLO; s != nil && s.MaxCompactionBacklogGrowth != nil && *s.MaxCompactionBacklogGrowth < 0 {
+		return fmt.Errorf("slo.maxCompactionBacklogGrowth must not be negative, got %d", *s.MaxCompactionBacklogGrowth)
+	}
+	if err := validateDuration("timeout", p.Timeout); err != nil {
+		return err
+	}
+
 	// Validate K6 config
 	// Duration is optional - defaults to 5m if not set (can be overridden via DURATION env var)
+	if err := validateDuration("k6.duration", p.K6.Duration); err != nil {
+		return err
+	}
+	if err := validateK6Pod(p.K6.Pod); err != nil {
+		return err
+	}
 	if p.K6.VUs.Min <= 0 {
 		return fmt.Errorf("k6.vus.min must be positive")
 	}
@@ -120,10 +316,526 @@ func Validate(p *Profile) error {
 	if p.K6.Ingestion.TraceProfile == "" {
 		return fmt.Errorf("k6.ingestion.traceProfile is required")
 	}
+	if p.K6.Ingestion.Protocol != "" && !slices.Contains(IngestionProtocols, p.K6.Ingestion.Protocol) {
+		return fmt.Errorf("k6.ingestion.protocol must be one of %s, got %q", strings.Join(IngestionProtocols, ", "), p.K6.Ingestion.Protocol)
+	}
 	if p.K6.Query.QueriesPerSecond <= 0 {
 		return fmt.Errorf("k6.query.queriesPerSecond must be positive")
 	}
 
+	if err := validateReplay(p.K6.Replay); err != nil {
+		return err
+	}
+	if err := validatePhases(p.Phases); err != nil {
+		return err
+	}
+	for i, ph := range p.Phases {
+		if ph.Type == "replay" && p.K6.Replay == nil {
+			return fmt.Errorf("phases[%d] is a replay phase but k6.replay is not set", i)
+		}
+	}
+	if err := validateTenantIsolation(p); err != nil {
+		return err
+	}
+	if err := validateKafka(p); err != nil {
+		return err
+	}
+
+	// Validate notification config
+	if p.Notifications != nil && p.Notifications.Format != "" &&
+		p.Notifications.Format != "slack" && p.Notifications.Format != "generic" {
+		return fmt.Errorf("notifications.format must be 'slack' or 'generic', got %q", p.Notifications.Format)
+	}
+
+	return nil
+}
+
+// AutoscalableComponents are the TempoStack components that can be autoscaled.
+// Ingesters are excluded: scaling them down drops in-memory traces from the ring.
+var AutoscalableComponents = []string{"distributor", "querier", "query-frontend", "compactor"}
+
+// validateAutoscaling checks the autoscaling settings of a Tempo config
+func validateAutoscaling(t *TempoConfig) error {
+	a := t.Autoscaling
+	if a == nil {
+		return nil
+	}
+	if t.Variant != "stack" {
+		return fmt.Errorf("tempo.autoscaling is only supported with the stack variant")
+	}
+	for _, c := range a.Components {
+		if !slices.Contains(AutoscalableComponents, c) {
+			return fmt.Errorf("tempo.autoscaling.components: unsupported component %q (must be one of %s)", c, strings.Join(AutoscalableComponents, ", "))
+		}
+	}
+	if a.MinReplicas < 0 {
+		return fmt.Errorf("tempo.autoscaling.minReplicas cannot be negative")
+	}
+	if a.MaxReplicas <= 0 {
+		return fmt.Errorf("tempo.autoscaling.maxReplicas must be positive")
+	}
+	if a.MinReplicas > a.MaxReplicas {
+		return fmt.Errorf("tempo.autoscaling.minReplicas cannot be greater than tempo.autoscaling.maxReplicas")
+	}
+	if a.TargetCPUUtilization != nil && *a.TargetCPUUtilization <= 0 {
+		return fmt.Errorf("tempo.autoscaling.targetCPUUtilization must be positive")
+	}
+	if a.TargetMemoryUtilization != nil && *a.TargetMemoryUtilization <= 0 {
+		return fmt.Errorf("tempo.autoscaling.targetMemoryUtilization must be positive")
+	}
+	return nil
+}
+
+// validateStorage checks the storage backend of a profile
+func validateStorage(p *Profile) error {
+	s := p.Storage
+	if s == nil {
+		return nil
+	}
+	switch s.Backend {
+	case "", "minio":
+		if s.Size != "" || s.StorageClass != "" {
+			return fmt.Errorf("storage.size and storage.storageClass require storage.backend 'pv'")
+		}
+		if s.MinioReplicas < 0 || s.MinioReplicas == 2 || s.MinioReplicas == 3 || s.MinioReplicas > 16 {
+			return fmt.Errorf("storage.minioReplicas must be 1, or between 4 and 16 for distributed MinIO, got %d", s.MinioReplicas)
+		}
+		if r := s.MinioResources; r != nil && (r.Memory == "") != (r.CPU == "") {
+			return fmt.Errorf("storage.minioResources requires both memory and cpu")
+		}
+	case "pv":
+		if p.Tempo.Variant != "monolithic" {
+			return fmt.Errorf("storage.backend 'pv' is only supported with the monolithic variant")
+		}
+		if s.HasMinioSettings() {
+			return fmt.Errorf("storage.minioSize, minioReplicas, minioStorageClass and minioResources cannot be set with storage.backend 'pv'")
+		}
+	default:
+		return fmt.Errorf("storage.backend must be 'minio' or 'pv', got %q", s.Backend)
+	}
+	return nil
+}
+
+// validateReplicas checks the number of TempoMonolithic replicas of a profile
+func validateReplicas(p *Profile) error {
+	replicas := p.Tempo.Replicas
+	if replicas < 0 {
+		return fmt.Errorf("tempo.replicas cannot be negative")
+	}
+	if replicas <= 1 {
+		return nil
+	}
+	if p.Tempo.Variant != "monolithic" {
+		return fmt.Errorf("tempo.replicas is only supported with the monolithic variant")
+	}
+	if p.Storage.UsesPV() {
+		return fmt.Errorf("tempo.replicas greater than 1 requires storage.backend 'minio', as each replica would get its own volume")
+	}
+	return nil
+}
+
+// validateQuota checks the namespace quota of a profile
+func validateQuota(q *QuotaConfig) error {
+	if q == nil {
+		return nil
+	}
+	if q.CPU == "" || q.Memory == "" {
+		return fmt.Errorf("quota requires both cpu and memory")
+	}
+	quantities := [][2]string{{"quota.cpu", q.CPU}, {"quota.memory", q.Memory}}
+	if d := q.DefaultContainer; d != nil {
+		if d.CPU == "" || d.Memory == "" {
+			return fmt.Errorf("quota.defaultContainer requires both memory and cpu")
+		}
+		quantities = append(quantities, [2]string{"quota.defaultContainer.cpu", d.CPU}, [2]string{"quota.defaultContainer.memory", d.Memory})
+	}
+	for _, f := range quantities {
+		v, err := resource.ParseQuantity(f[1])
+		if err != nil || v.Sign() <= 0 {
+			return fmt.Errorf("%s must be a positive quantity such as \"16\" or \"64Gi\", got %q", f[0], f[1])
+		}
+	}
+	if q.Pods < 0 {
+		return fmt.Errorf("quota.pods cannot be negative")
+	}
+	return nil
+}
+
+// validateK6Pod checks the resources and placement overrides of the k6 pods
+func validateK6Pod(pod *K6PodConfig) error {
+	if pod == nil {
+		return nil
+	}
+	if r := pod.Resources; r != nil {
+		if r.CPU == "" || r.Memory == "" {
+			return fmt.Errorf("k6.pod.resources requires both memory and cpu")
+		}
+		for _, f := range [][2]string{{"k6.pod.resources.cpu", r.CPU}, {"k6.pod.resources.memory", r.Memory}} {
+			v, err := resource.ParseQuantity(f[1])
+			if err != nil || v.Sign() <= 0 {
+				return fmt.Errorf("%s must be a positive quantity such as \"4\" or \"8Gi\", got %q", f[0], f[1])
+			}
+		}
+	}
+	for i, t := range pod.Tolerations {
+		if !slices.Contains(TolerationOperators, t.Operator) {
+			return fmt.Errorf("k6.pod.tolerations[%d].operator must be one of %s, got %q", i, strings.Join(TolerationOperators, ", "), t.Operator)
+		}
+		if t.Operator == "Exists" && t.Value != "" {
+			return fmt.Errorf("k6.pod.tolerations[%d].value must be empty with operator Exists", i)
+		}
+		if t.Key == "" && t.Operator != "Exists" {
+			return fmt.Errorf("k6.pod.tolerations[%d].key is required unless operator is Exists", i)
+		}
+		if !slices.Contains(TaintEffects, t.Effect) {
+			return fmt.Errorf("k6.pod.tolerations[%d].effect must be one of %s, got %q", i, strings.Join(TaintEffects[1:], ", "), t.Effect)
+		}
+	}
+	_, err := pod.KubernetesAffinity()
+	return err
+}
+
+// KubernetesAffinity decodes the affinity of the k6 pods, or returns nil if none is
+// set. Unknown fields are an error.
+func (c *K6PodConfig) KubernetesAffinity() (*corev1.Affinity, error) {
+	if len(c.Affinity) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(c.Affinity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode k6.pod.affinity: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var affinity corev1.Affinity
+	if err := dec.Decode(&affinity); err != nil {
+		return nil, fmt.Errorf("invalid k6.pod.affinity: %w", err)
+	}
+	return &affinity, nil
+}
+
+// validateDuration checks that an optional duration field is a positive Go duration
+func validateDuration(field, value string) error {
+	if value == "" {
+		return nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("%s must be a duration such as \"5m\" or \"1h30m\", got %q", field, value)
+	}
+	if d <= 0 {
+		return fmt.Errorf("%s must be positive, got %q", field, value)
+	}
+	return nil
+}
+
+// TolerationOperators are the operators of k6 pod tolerations; empty is Equal
+var TolerationOperators = []string{"", "Equal", "Exists"}
+
+// TaintEffects are the effects of k6 pod tolerations; empty matches all effects
+var TaintEffects = []string{"", "NoSchedule", "PreferNoSchedule", "NoExecute"}
+
+// MetricsGeneratorProcessors are the metrics-generator processors that can be enabled
+var MetricsGeneratorProcessors = []string{"span-metrics", "service-graphs"}
+
+// validateMetricsGenerator checks the metrics-generator settings of a Tempo config
+func validateMetricsGenerator(t *TempoConfig) error {
+	g := t.MetricsGenerator
+	if g == nil {
+		return nil
+	}
+	if t.Variant != "monolithic" {
+		return fmt.Errorf("tempo.metricsGenerator is only supported with the monolithic variant")
+	}
+	for _, processor := range g.Processors {
+		if !slices.Contains(MetricsGeneratorProcessors, processor) {
+			return fmt.Errorf("tempo.metricsGenerator.processors: unsupported processor %q (must be one of %s)", processor, strings.Join(MetricsGeneratorProcessors, ", "))
+		}
+	}
+	if g.RemoteWriteURL != "" {
+		if u, err := url.Parse(g.RemoteWriteURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("tempo.metricsGenerator.remoteWriteURL must be an http(s) URL, got %q", g.RemoteWriteURL)
+		}
+	}
+	return nil
+}
+
+// validateIngestionAuth checks the ingestion authentication of a Tempo config
+func validateIngestionAuth(t *TempoConfig) error {
+	switch t.IngestionAuth {
+	case "", "sa-token", "static-token":
+		return nil
+	case "mtls", "none":
+		// TempoStack encrypts its internal traffic with certificates only the
+		// gateway holds, so it cannot be queried without the gateway
+		if t.Variant != "monolithic" {
+			return fmt.Errorf("tempo.ingestionAuth %q is only supported with the monolithic variant", t.IngestionAuth)
+		}
+		return nil
+	default:
+		return fmt.Errorf("tempo.ingestionAuth must be 'sa-token', 'static-token', 'mtls' or 'none', got %q", t.IngestionAuth)
+	}
+}
+
+// IngestionProtocols are the OTLP protocols traces can be ingested with
+var IngestionProtocols = []string{"grpc", "http"}
+
+// BlockVersions are the Tempo block formats a profile can select
+var BlockVersions = []string{"v2", "vParquet2", "vParquet3", "vParquet4"}
+
+// BlockEncodings are the compression encodings of v2 blocks
+var BlockEncodings = []string{"none", "gzip", "lz4-64k", "lz4-256k", "lz4-1M", "lz4", "snappy", "zstd", "s2"}
+
+// validateBlock checks the block format of a Tempo config
+func validateBlock(b *BlockConfig) error {
+	if b == nil {
+		return nil
+	}
+	if b.Version != "" && !slices.Contains(BlockVersions, b.Version) {
+		return fmt.Errorf("tempo.block.version: unsupported version %q (must be one of %s)", b.Version, strings.Join(BlockVersions, ", "))
+	}
+	if b.Encoding != "" {
+		if !slices.Contains(BlockEncodings, b.Encoding) {
+			return fmt.Errorf("tempo.block.encoding: unsupported encoding %q (must be one of %s)", b.Encoding, strings.Join(BlockEncodings, ", "))
+		}
+		if b.Version != "v2" {
+			return fmt.Errorf("tempo.block.encoding only applies to v2 blocks, set tempo.block.version to v2")
+		}
+	}
+	if b.BloomFilterFalsePositive != nil && (*b.BloomFilterFalsePositive <= 0 || *b.BloomFilterFalsePositive >= 1) {
+		return fmt.Errorf("tempo.block.bloomFilterFalsePositive must be between 0 and 1, got %v", *b.BloomFilterFalsePositive)
+	}
+	if b.BloomFilterShardSizeBytes != nil && *b.BloomFilterShardSizeBytes <= 0 {
+		return fmt.Errorf("tempo.block.bloomFilterShardSizeBytes must be positive, got %d", *b.BloomFilterShardSizeBytes)
+	}
+	return nil
+}
+
+// validateExtraConfig checks that the raw Tempo extraConfig does not set a key that
+// another field of the profile sets, where one would silently win
+func validateExtraConfig(t *TempoConfig) error {
+	if len(t.ExtraConfig) == 0 {
+		return nil
+	}
+
+	type keyField struct {
+		set   bool
+		field string
+		key   string
+	}
+	fields := []keyField{
+		{t.MetricsGenerator != nil, "metricsGenerator", "metrics_generator.storage"},
+		{t.MetricsGenerator != nil, "metricsGenerator.processors", "overrides.defaults.metrics_generator.processors"},
+	}
+	if b := t.Block; b != nil {
+		fields = append(fields,
+			keyField{b.Version != "", "block.version", "storage.trace.block.version"},
+			keyField{b.Encoding != "", "block.encoding", "storage.trace.block.v2_encoding"},
+			keyField{b.BloomFilterFalsePositive != nil, "block.bloomFilterFalsePositive", "storage.trace.block.bloom_filter_false_positive"},
+			keyField{b.BloomFilterShardSizeBytes != nil, "block.bloomFilterShardSizeBytes", "storage.trace.block.bloom_filter_shard_size_bytes"},
+		)
+	}
+	if o := t.Overrides; o != nil {
+		fields = append(fields, keyField{o.MaxTracesPerUser != nil, "overrides.maxTracesPerUser", "overrides.defaults.ingestion.max_traces_per_user"})
+		if ing := o.Ingester; ing != nil {
+			fields = append(fields,
+				keyField{ing.FlushCheckPeriod != "", "overrides.ingester.flushCheckPeriod", "ingester.flush_check_period"},
+				keyField{ing.TraceIdlePeriod != "", "overrides.ingester.traceIdlePeriod", "ingester.trace_idle_period"},
+				keyField{ing.MaxBlockDuration != "", "overrides.ingester.maxBlockDuration", "ingester.max_block_duration"},
+				keyField{ing.ConcurrentFlushes != nil, "overrides.ingester.concurrentFlushes", "ingester.concurrent_flushes"},
+			)
+		}
+	}
+
+	for _, f := range fields {
+		if f.set && hasNestedKey(t.ExtraConfig, strings.Split(f.key, ".")) {
+			return fmt.Errorf("tempo.extraConfig sets %s, which tempo.%s already sets", f.key, f.field)
+		}
+	}
+	return nil
+}
+
+// hasNestedKey reports whether m sets the value at keys, or replaces one of its
+// parents with a value that is not a map
+func hasNestedKey(m map[string]interface{}, keys []string) bool {
+	for i, key := range keys {
+		value, ok := m[key]
+		if !ok {
+			return false
+		}
+		next, isMap := value.(map[string]interface{})
+		if i == len(keys)-1 || !isMap {
+			return true
+		}
+		m = next
+	}
+	return false
+}
+
+// envNamePattern matches valid environment variable names
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// goMemLimitPattern matches the GOMEMLIMIT syntax: "off" or bytes with an optional
+// B, KiB, MiB, GiB or TiB suffix
+var goMemLimitPattern = regexp.MustCompile(`^(off|[0-9]+(B|KiB|MiB|GiB|TiB)?)$`)
+
+// validateEnv checks the Tempo container environment variables. The Go runtime
+// ignores malformed GOGC and GOMEMLIMIT values, so they are checked here rather
+// than silently benchmarking the defaults.
+func validateEnv(env map[string]string) error {
+	for name, value := range env {
+		if !envNamePattern.MatchString(name) {
+			return fmt.Errorf("tempo.env: invalid variable name %q", name)
+		}
+		switch name {
+		case "GOGC":
+			if _, err := strconv.Atoi(value); err != nil && value != "off" {
+				return fmt.Errorf("tempo.env.GOGC must be a percentage or 'off', got %q", value)
+			}
+		case "GOMEMLIMIT":
+			if !goMemLimitPattern.MatchString(value) {
+				return fmt.Errorf("tempo.env.GOMEMLIMIT must be 'off' or bytes with an optional B, KiB, MiB, GiB or TiB suffix, got %q", value)
+			}
+		}
+	}
+	return nil
+}
+
+// validateImages checks that the pinned images are plain image references
+func validateImages(images *ImagesConfig) error {
+	if images == nil {
+		return nil
+	}
+	for field, image := range map[string]string{"tempo": images.Tempo, "collector": images.Collector, "k6": images.K6} {
+		if strings.ContainsAny(image, " \t\n") {
+			return fmt.Errorf("images.%s must be an image reference, got %q", field, image)
+		}
+		if _, digest, ok := strings.Cut(image, "@"); ok && !strings.HasPrefix(digest, "sha256:") {
+			return fmt.Errorf("images.%s must pin a sha256 digest, got %q", field, image)
+		}
+	}
+	return nil
+}
+
+// validateReplay checks the trace capture of the replay test
+func validateReplay(r *ReplayConfig) error {
+	if r == nil {
+		return nil
+	}
+	if (r.File == "") == (r.PVC == "") {
+		return fmt.Errorf("k6.replay needs exactly one of file or pvc")
+	}
+	if r.Path != "" && r.PVC == "" {
+		return fmt.Errorf("k6.replay.path is only used with k6.replay.pvc")
+	}
+	if r.Speedup < 0 {
+		return fmt.Errorf("k6.replay.speedup cannot be negative")
+	}
+	return nil
+}
+
+// PhaseTypes are the supported phase types; "idle" generates no load
+var PhaseTypes = []string{"ingestion", "query", "combined", "jaeger", "replay", "idle"}
+
+// phaseNamePattern keeps phase names usable as metric label values and in file names
+var phaseNamePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// validatePhases checks the phases of a profile
+func validatePhases(phases []PhaseConfig) error {
+	seen := make(map[string]bool)
+	for i, ph := range phases {
+		if !phaseNamePattern.MatchString(ph.Name) {
+			return fmt.Errorf("phases[%d].name must consist of lowercase alphanumerics and '-', got %q", i, ph.Name)
+		}
+		if seen[ph.Name] {
+			return fmt.Errorf("phases[%d].name %q is not unique", i, ph.Name)
+		}
+		seen[ph.Name] = true
+
+		if !slices.Contains(PhaseTypes, ph.Type) {
+			return fmt.Errorf("phases[%d].type must be one of %s, got %q", i, strings.Join(PhaseTypes, ", "), ph.Type)
+		}
+		d, err := time.ParseDuration(ph.Duration)
+		if err != nil {
+			return fmt.Errorf("phases[%d].duration is invalid: %w", i, err)
+		}
+		if d <= 0 {
+			return fmt.Errorf("phases[%d].duration must be positive", i)
+		}
+	}
+	return nil
+}
+
+// validateTenantIsolation checks the noisy-neighbor scenario of a profile
+func validateTenantIsolation(p *Profile) error {
+	ti := p.TenantIsolation
+	if ti == nil {
+		return nil
+	}
+	if len(p.Phases) > 0 {
+		return fmt.Errorf("tenantIsolation cannot be combined with phases")
+	}
+	// Without the gateway Tempo runs without multitenancy
+	if !p.Tempo.UsesGateway() {
+		return fmt.Errorf("tenantIsolation requires multitenancy, which tempo.ingestionAuth %q disables", p.Tempo.IngestionAuth)
+	}
+	for _, f := range [][2]string{
+		{"baseline", ti.Baseline},
+		{"burst", ti.Burst},
+	} {
+		if f[1] == "" {
+			return fmt.Errorf("tenantIsolation.%s is required", f[0])
+		}
+		if err := validateDuration("tenantIsolation."+f[0], f[1]); err != nil {
+			return err
+		}
+	}
+	if err := validateDuration("tenantIsolation.recovery", ti.Recovery); err != nil {
+		return err
+	}
+	if ti.Aggressor.MBPerSecond <= 0 {
+		return fmt.Errorf("tenantIsolation.aggressor.mbPerSecond must be positive")
+	}
+	if ti.Aggressor.QueriesPerSecond < 0 {
+		return fmt.Errorf("tenantIsolation.aggressor.queriesPerSecond cannot be negative")
+	}
+	if ti.Aggressor.VUs < 0 {
+		return fmt.Errorf("tenantIsolation.aggressor.vus cannot be negative")
+	}
+	return nil
+}
+
+// kafkaTopicPattern matches the names Kafka allows for topics
+var kafkaTopicPattern = regexp.MustCompile(`^[a-zA-Z0-9._-]{1,249}$`)
+
+// validateKafka checks the Kafka ingestion path of a profile
+func validateKafka(p *Profile) error {
+	k := p.Kafka
+	if k == nil {
+		return nil
+	}
+	// The collector of the aggressor tenant sends to Tempo directly
+	if p.TenantIsolation != nil {
+		return fmt.Errorf("kafka cannot be combined with tenantIsolation")
+	}
+	for i, broker := range k.Brokers {
+		host, port, err := net.SplitHostPort(broker)
+		if err != nil || host == "" || port == "" {
+			return fmt.Errorf("kafka.brokers[%d] must be host:port, got %q", i, broker)
+		}
+	}
+	if len(k.Brokers) > 0 && (k.Partitions != 0 || k.Resources != nil) {
+		return fmt.Errorf("kafka.partitions and kafka.resources only apply to the deployed broker and cannot be set with kafka.brokers")
+	}
+	if k.Topic != "" && !kafkaTopicPattern.MatchString(k.Topic) {
+		return fmt.Errorf("kafka.topic must consist of up to 249 alphanumerics, '.', '_' and '-', got %q", k.Topic)
+	}
+	if k.Partitions < 0 {
+		return fmt.Errorf("kafka.partitions cannot be negative")
+	}
+	if r := k.Resources; r != nil && (r.Memory == "" || r.CPU == "") {
+		return fmt.Errorf("kafka.resources requires both memory and cpu")
+	}
 	return nil
 }
 
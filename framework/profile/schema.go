@@ -0,0 +1,231 @@
+package profile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// SchemaID is the $id of the profile JSON Schema, relative to the profiles directory
+const SchemaID = "profile.schema.json"
+
+// durationPattern matches Go durations such as "5m" or "1h30m"
+const durationPattern = `^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+
+// schemaHints refine the schema of the fields at a path beyond their Go type with
+// the required fields, allowed values and formats that Validate checks. Elements
+// of a list are at the path of the list followed by "[]".
+var schemaHints = map[string]map[string]interface{}{
+	"": {"required": []string{"name", "tempo", "k6"}},
+
+	"tempo":                                     {"required": []string{"variant"}},
+	"tempo.variant":                             {"enum": []string{"monolithic", "stack"}},
+	"tempo.ingestionAuth":                       {"enum": []string{"sa-token", "static-token", "mtls", "none"}},
+	"tempo.autoscaling":                         {"required": []string{"maxReplicas"}},
+	"tempo.autoscaling.components[]":            {"enum": AutoscalableComponents},
+	"tempo.autoscaling.minReplicas":             {"minimum": 0},
+	"tempo.autoscaling.maxReplicas":             {"minimum": 1},
+	"tempo.autoscaling.targetCPUUtilization":    {"minimum": 1},
+	"tempo.autoscaling.targetMemoryUtilization": {"minimum": 1},
+	"tempo.overrides.ingester.flushCheckPeriod": {"pattern": durationPattern},
+	"tempo.overrides.ingester.traceIdlePeriod":  {"pattern": durationPattern},
+	"tempo.overrides.ingester.maxBlockDuration": {"pattern": durationPattern},
+	"tempo.metricsGenerator.processors[]":       {"enum": MetricsGeneratorProcessors},
+	"tempo.metricsGenerator.remoteWriteURL":     {"pattern": "^https?://"},
+	"tempo.block.version":                       {"enum": BlockVersions},
+	"tempo.block.encoding":                      {"enum": BlockEncodings},
+	"tempo.block.bloomFilterFalsePositive":      {"exclusiveMinimum": 0, "exclusiveMaximum": 1},
+	"tempo.block.bloomFilterShardSizeBytes":     {"minimum": 1},
+
+	"k6":                            {"required": []string{"vus", "ingestion", "query"}},
+	"k6.duration":                   {"pattern": durationPattern},
+	"k6.vus":                        {"required": []string{"min", "max"}},
+	"k6.vus.min":                    {"minimum": 1},
+	"k6.vus.max":                    {"minimum": 1},
+	"k6.ingestion":                  {"required": []string{"mbPerSecond", "traceProfile"}},
+	"k6.ingestion.mbPerSecond":      {"exclusiveMinimum": 0},
+	"k6.ingestion.protocol":         {"enum": IngestionProtocols},
+	"k6.query":                      {"required": []string{"queriesPerSecond"}},
+	"k6.query.queriesPerSecond":     {"minimum": 1},
+	"k6.replay.speedup":             {"minimum": 0},
+	"k6.pod.resources":              {"required": []string{"cpu", "memory"}},
+	"k6.pod.tolerations[].operator": {"enum": TolerationOperators[1:]},
+	"k6.pod.tolerations[].effect":   {"enum": TaintEffects[1:]},
+
+	"storage.backend":       {"enum": []string{"minio", "pv"}},
+	"storage.minioReplicas": {"enum": []int{1, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}},
+
+	"kafka.brokers[]":  {"pattern": `^.+:[0-9]+$`},
+	"kafka.topic":      {"pattern": kafkaTopicPattern.String()},
+	"kafka.partitions": {"minimum": 0},
+	"kafka.resources":  {"required": []string{"cpu", "memory"}},
+
+	"notifications.format": {"enum": []string{"slack", "generic"}},
+
+	"quota":                  {"required": []string{"cpu", "memory"}},
+	"quota.pods":             {"minimum": 0},
+	"quota.defaultContainer": {"required": []string{"cpu", "memory"}},
+
+	"slo.maxCompactionBacklogGrowth": {"minimum": 0},
+
+	"phases[]":          {"required": []string{"name", "type", "duration"}},
+	"phases[].name":     {"pattern": phaseNamePattern.String()},
+	"phases[].type":     {"enum": PhaseTypes},
+	"phases[].duration": {"pattern": durationPattern},
+}
+
+// Schema returns the JSON Schema of profile YAML files, for editors to validate
+// and complete profiles. Validate remains the authority on what a profile may set.
+// Profiles that extend another one only set some fields, so they are checked
+// against a variant of the schema without required fields.
+func Schema() map[string]interface{} {
+	extending := typeSchema(reflect.TypeOf(Profile{}), "", true)
+	extending["required"] = []string{"name", "extends"}
+	return map[string]interface{}{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id":     SchemaID,
+		"title":   "Tempo performance test profile",
+		"$defs": map[string]interface{}{
+			"profile":          typeSchema(reflect.TypeOf(Profile{}), "", false),
+			"extendingProfile": extending,
+		},
+		"if":   map[string]interface{}{"required": []string{"extends"}},
+		"then": map[string]interface{}{"$ref": "#/$defs/extendingProfile"},
+		"else": map[string]interface{}{"$ref": "#/$defs/profile"},
+	}
+}
+
+// SchemaJSON returns the indented JSON of Schema
+func SchemaJSON() ([]byte, error) {
+	data, err := json.MarshalIndent(Schema(), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode profile schema: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// typeSchema returns the schema of a Go type of the profile at path. A partial
+// schema leaves out the required fields.
+func typeSchema(t reflect.Type, path string, partial bool) map[string]interface{} {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	schema := make(map[string]interface{})
+	switch t.Kind() {
+	case reflect.Struct:
+		properties := make(map[string]interface{})
+		for _, f := range yamlFields(t) {
+			properties[f.name] = typeSchema(f.typ, joinPath(path, f.name), partial)
+		}
+		schema["type"] = "object"
+		schema["properties"] = properties
+		schema["additionalProperties"] = false
+	case reflect.Slice:
+		schema["type"] = "array"
+		schema["items"] = typeSchema(t.Elem(), path+"[]", partial)
+	case reflect.Map:
+		schema["type"] = "object"
+		if t.Elem().Kind() != reflect.Interface {
+			schema["additionalProperties"] = typeSchema(t.Elem(), "", partial)
+		}
+	case reflect.String:
+		schema["type"] = "string"
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		schema["type"] = "integer"
+	case reflect.Float32, reflect.Float64:
+		schema["type"] = "number"
+	case reflect.Bool:
+		schema["type"] = "boolean"
+	}
+
+	for key, value := range schemaHints[path] {
+		if partial && key == "required" {
+			continue
+		}
+		schema[key] = value
+	}
+	return schema
+}
+
+// yamlField is a field of a profile struct as it appears in YAML
+type yamlField struct {
+	name string
+	typ  reflect.Type
+}
+
+// yamlFields returns the fields of a struct type under their YAML names
+func yamlFields(t reflect.Type) []yamlField {
+	var fields []yamlField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if !f.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields = append(fields, yamlField{name: name, typ: f.Type})
+	}
+	return fields
+}
+
+// checkUnknownFields returns an error for every key of value, the YAML decoded into
+// generic maps, that is not a field of t, naming its path and the valid fields
+func checkUnknownFields(value interface{}, t reflect.Type, path string) error {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	var errs []error
+	switch t.Kind() {
+	case reflect.Struct:
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		fields := make(map[string]reflect.Type)
+		for _, f := range yamlFields(t) {
+			fields[f.name] = f.typ
+		}
+		keys := make([]string, 0, len(m))
+		for key := range m {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			typ, ok := fields[key]
+			if !ok {
+				valid := make([]string, 0, len(fields))
+				for name := range fields {
+					valid = append(valid, name)
+				}
+				sort.Strings(valid)
+				errs = append(errs, fmt.Errorf("unknown field %s (valid fields: %s)", joinPath(path, key), strings.Join(valid, ", ")))
+				continue
+			}
+			errs = append(errs, checkUnknownFields(m[key], typ, joinPath(path, key)))
+		}
+	case reflect.Slice:
+		items, ok := value.([]interface{})
+		if !ok {
+			return nil
+		}
+		for i, item := range items {
+			errs = append(errs, checkUnknownFields(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i)))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// joinPath appends a field name to a dotted field path
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
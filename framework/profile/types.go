@@ -8,6 +8,11 @@ type Profile struct {
 	// Description provides human-readable details about the profile
 	Description string `yaml:"description"`
 
+	// Extends names a profile in the same directory this one is based on (optional).
+	// The fields set here are deep-merged over it: mappings are merged, lists and
+	// other values replaced, and null removes a field. The name is not inherited.
+	Extends string `yaml:"extends,omitempty"`
+
 	// Tempo contains Tempo deployment configuration
 	Tempo TempoConfig `yaml:"tempo"`
 
@@ -16,13 +21,197 @@ type Profile struct {
 
 	// Storage contains storage configuration (optional)
 	Storage *StorageConfig `yaml:"storage,omitempty"`
+
+	// Kafka routes the ingestion through Kafka (optional): the OTel Collector k6
+	// sends to writes the traces to a Kafka topic, and a second collector consumes
+	// them and forwards them to Tempo, to benchmark a Kafka-buffered ingestion path
+	Kafka *KafkaConfig `yaml:"kafka,omitempty"`
+
+	// Notifications contains webhook notification settings (optional).
+	// Values here override the TEMPO_PERF_NOTIFY_* environment variables.
+	Notifications *NotificationConfig `yaml:"notifications,omitempty"`
+
+	// Phases is an ordered list of test phases run against the same deployment (optional).
+	// When set, the phases replace the single test selected with --test-type.
+	Phases []PhaseConfig `yaml:"phases,omitempty"`
+
+	// Images pins the images of the Tempo, OTel Collector and k6 containers (optional).
+	// Without it, the images chosen by the operators and the default k6 image are used.
+	Images *ImagesConfig `yaml:"images,omitempty"`
+
+	// Quota caps the resources of the test namespace with a ResourceQuota and
+	// LimitRange (optional), so a runaway pod cannot consume the whole cluster
+	Quota *QuotaConfig `yaml:"quota,omitempty"`
+
+	// Timeout bounds the whole run of the profile, from setup to report, as a Go
+	// duration (optional). It overrides --profile-timeout.
+	Timeout string `yaml:"timeout,omitempty"`
+
+	// TenantIsolation runs the noisy-neighbor scenario instead of the test selected
+	// with --test-type (optional): the k6 load of the profile runs as one tenant
+	// while a second tenant bursts, to measure how much the burst slows it down
+	TenantIsolation *TenantIsolationConfig `yaml:"tenantIsolation,omitempty"`
+
+	// SLO adds thresholds on Tempo metrics to the k6 thresholds (optional). A run
+	// that crosses one is reported as an SLO violation.
+	SLO *SLOConfig `yaml:"slo,omitempty"`
+}
+
+// SLOConfig defines thresholds checked against the metrics collected over the load
+type SLOConfig struct {
+	// MaxCompactionBacklogGrowth is how many more outstanding blocks the compactor
+	// may have at the end of the load than at its start (optional). A backlog that
+	// keeps growing means compaction falls behind ingestion.
+	MaxCompactionBacklogGrowth *int `yaml:"maxCompactionBacklogGrowth,omitempty"`
+}
+
+// TenantIsolationConfig defines the noisy-neighbor scenario. The victim tenant runs
+// the combined k6 load of the profile through all phases: baseline, burst, and
+// recovery when set. During the burst an aggressor tenant adds its own load.
+type TenantIsolationConfig struct {
+	// Baseline is how long the victim runs alone before the burst (e.g., "5m")
+	Baseline string `yaml:"baseline"`
+
+	// Burst is how long the aggressor runs next to the victim (e.g., "10m")
+	Burst string `yaml:"burst"`
+
+	// Recovery is how long the victim runs alone after the burst (optional)
+	Recovery string `yaml:"recovery,omitempty"`
+
+	// Aggressor is the load of the aggressor tenant during the burst
+	Aggressor AggressorConfig `yaml:"aggressor"`
+}
+
+// AggressorConfig defines the load of the aggressor tenant. Its trace profile is
+// the one of the profile.
+type AggressorConfig struct {
+	// MBPerSecond is the ingestion rate of the aggressor, usually well above what
+	// the deployment sustains
+	MBPerSecond float64 `yaml:"mbPerSecond"`
+
+	// QueriesPerSecond adds a query load to the aggressor (optional).
+	// Default: 0, ingestion only
+	QueriesPerSecond int `yaml:"queriesPerSecond,omitempty"`
+
+	// VUs is the maximum number of VUs of the aggressor (optional).
+	// Default: k6.vus.max of the profile
+	VUs int `yaml:"vus,omitempty"`
+}
+
+// QuotaConfig defines the resource budget of the test namespace
+type QuotaConfig struct {
+	// CPU is the total CPU limit of all containers in the namespace (e.g., "16")
+	CPU string `yaml:"cpu"`
+
+	// Memory is the total memory limit of all containers in the namespace (e.g., "64Gi")
+	Memory string `yaml:"memory"`
+
+	// Pods is the maximum number of pods in the namespace (optional)
+	Pods int `yaml:"pods,omitempty"`
+
+	// DefaultContainer is the limit of containers that set none (optional)
+	// Default: cpu "500m", memory "512Mi"
+	DefaultContainer *ResourceSpec `yaml:"defaultContainer,omitempty"`
+}
+
+// ImagesConfig pins container images, by tag or digest (e.g. "quay.io/org/tempo@sha256:...").
+// The run fails when a pinned Tempo or collector image is not the one running.
+type ImagesConfig struct {
+	// Tempo is the image of the Tempo containers. For TempoStack it is set in the
+	// CR; for TempoMonolithic, which has no image setting, the CR is switched to
+	// unmanaged and the image is patched into its workloads.
+	Tempo string `yaml:"tempo,omitempty"`
+
+	// Collector is the image of the OTel Collector
+	Collector string `yaml:"collector,omitempty"`
+
+	// K6 is the image of the k6 load generator pods
+	K6 string `yaml:"k6,omitempty"`
+}
+
+// PhaseConfig defines one phase of a phased test
+type PhaseConfig struct {
+	// Name identifies the phase in metric labels and output file names (e.g., "warmup")
+	Name string `yaml:"name"`
+
+	// Type is the load generated during the phase: "ingestion", "query", "combined", "jaeger", "replay", or "idle"
+	Type string `yaml:"type"`
+
+	// Duration of the phase (e.g., "10m")
+	Duration string `yaml:"duration"`
+}
+
+// NotificationConfig defines webhook notification settings for a profile
+type NotificationConfig struct {
+	// WebhookURL is the Slack or generic webhook endpoint
+	WebhookURL string `yaml:"webhookURL,omitempty"`
+
+	// Format is the payload format: "slack" (default) or "generic"
+	Format string `yaml:"format,omitempty"`
+
+	// DashboardBaseURL is the base URL where generated dashboards are published
+	DashboardBaseURL string `yaml:"dashboardBaseURL,omitempty"`
 }
 
 // StorageConfig defines storage settings for the test
 type StorageConfig struct {
+	// Backend is the trace storage: "minio" (S3 object storage in MinIO) or "pv"
+	// (a persistent volume of TempoMonolithic, without MinIO)
+	// Default: "minio"
+	Backend string `yaml:"backend,omitempty"`
+
 	// MinioSize is the PVC size for MinIO (e.g., "10Gi")
 	// Default: "2Gi"
 	MinioSize string `yaml:"minioSize,omitempty"`
+
+	// MinioReplicas is the number of MinIO servers: 1, or 4 to 16 for distributed
+	// MinIO with one PVC of MinioSize per server
+	// Default: 1
+	MinioReplicas int `yaml:"minioReplicas,omitempty"`
+
+	// MinioStorageClass is the StorageClass of the MinIO PVCs.
+	// If not set, the default StorageClass of the cluster is used.
+	MinioStorageClass string `yaml:"minioStorageClass,omitempty"`
+
+	// MinioResources defines CPU and memory for each MinIO server (optional)
+	MinioResources *ResourceSpec `yaml:"minioResources,omitempty"`
+
+	// Size is the size of the "pv" backend volume (e.g., "20Gi")
+	// Default: "10Gi"
+	Size string `yaml:"size,omitempty"`
+
+	// StorageClass is the StorageClass of the "pv" backend volume.
+	// If not set, the default StorageClass of the cluster is used.
+	StorageClass string `yaml:"storageClass,omitempty"`
+}
+
+// KafkaConfig defines the Kafka of the ingestion path
+type KafkaConfig struct {
+	// Brokers are the bootstrap addresses (host:port) of an existing Kafka cluster
+	// (optional). Without them a single Kafka broker is deployed in the test namespace.
+	Brokers []string `yaml:"brokers,omitempty"`
+
+	// Topic is the topic of the traces
+	// Default: "otlp_spans", or "otlp_spans-<run ID>" with brokers
+	Topic string `yaml:"topic,omitempty"`
+
+	// Partitions is the number of partitions of the topic on the deployed broker,
+	// which bounds how many partitions the consuming collector reads in parallel
+	// Default: 1
+	Partitions int `yaml:"partitions,omitempty"`
+
+	// Resources defines CPU and memory for the deployed broker (optional)
+	Resources *ResourceSpec `yaml:"resources,omitempty"`
+}
+
+// UsesPV reports whether traces are stored on a persistent volume instead of MinIO
+func (s *StorageConfig) UsesPV() bool {
+	return s != nil && s.Backend == "pv"
+}
+
+// HasMinioSettings reports whether any MinIO setting is set
+func (s *StorageConfig) HasMinioSettings() bool {
+	return s != nil && (s.MinioSize != "" || s.MinioReplicas != 0 || s.MinioStorageClass != "" || s.MinioResources != nil)
 }
 
 // TempoConfig defines Tempo deployment settings
@@ -41,6 +230,118 @@ type TempoConfig struct {
 
 	// Overrides defines Tempo overrides configuration (optional)
 	Overrides *TempoOverrides `yaml:"overrides,omitempty"`
+
+	// Autoscaling creates HorizontalPodAutoscalers for TempoStack components (optional).
+	// Only applies to TempoStack (not monolithic).
+	Autoscaling *AutoscalingConfig `yaml:"autoscaling,omitempty"`
+
+	// Env sets environment variables of the Tempo containers (optional), e.g.
+	// GOGC and GOMEMLIMIT to benchmark Go runtime tuning. Setting it switches the
+	// Tempo CR to unmanaged, since the operator has no API for them.
+	// Example: {"GOGC": "200", "GOMEMLIMIT": "3GiB"}
+	Env map[string]string `yaml:"env,omitempty"`
+
+	// IngestionAuth is how the OTel Collector authenticates when sending traces to Tempo:
+	// - "sa-token": the projected ServiceAccount token, through the gateway (default)
+	// - "static-token": a long-lived ServiceAccount token Secret, through the gateway
+	// - "mtls": a client certificate, directly to Tempo without the gateway
+	// - "none": plaintext without authentication, directly to Tempo without the gateway
+	// "mtls" and "none" deploy Tempo without multitenancy and are only supported
+	// with the monolithic variant.
+	IngestionAuth string `yaml:"ingestionAuth,omitempty"`
+
+	// ExtraConfig is raw Tempo configuration merged into the extraConfig of the
+	// Tempo CR (optional), to try settings the profile has no field for. Keys set
+	// by other fields, like ingester.max_block_duration, are rejected.
+	// Example: {"querier": {"max_concurrent_queries": 40}}
+	ExtraConfig map[string]interface{} `yaml:"extraConfig,omitempty"`
+
+	// MetricsGenerator enables the Tempo metrics-generator (optional), to measure
+	// the overhead of span metrics and service graphs against plain ingestion.
+	// Only applies to monolithic (the operator deploys no generator for TempoStack).
+	MetricsGenerator *MetricsGeneratorConfig `yaml:"metricsGenerator,omitempty"`
+
+	// Block sets the format of the trace blocks Tempo writes (optional), to compare
+	// storage formats with the same load. Unset fields keep the Tempo defaults.
+	// Example: {"version": "v2", "encoding": "zstd"}
+	Block *BlockConfig `yaml:"block,omitempty"`
+
+	// Replicas is the number of TempoMonolithic pods (optional), to measure an HA
+	// monolithic deployment. Only applies to monolithic, requires the minio storage
+	// backend and a Tempo operator whose TempoMonolithic supports replicas.
+	// Default: 1
+	Replicas int `yaml:"replicas,omitempty"`
+}
+
+// MetricsGeneratorConfig defines the Tempo metrics-generator, enabled for all tenants
+type MetricsGeneratorConfig struct {
+	// Processors to enable: span-metrics, service-graphs
+	// Default: ["span-metrics", "service-graphs"]
+	Processors []string `yaml:"processors,omitempty"`
+
+	// RemoteWriteURL is the Prometheus remote write endpoint the generated series
+	// are sent to (optional). Without it, the series are generated but dropped.
+	RemoteWriteURL string `yaml:"remoteWriteURL,omitempty"`
+}
+
+// BlockConfig defines the storage.trace.block settings of Tempo
+type BlockConfig struct {
+	// Version is the block format: v2, vParquet2, vParquet3, vParquet4
+	// Default: the default of the Tempo version
+	Version string `yaml:"version,omitempty"`
+
+	// Encoding is the compression of v2 blocks: none, gzip, lz4-64k, lz4-256k,
+	// lz4-1M, lz4, snappy, zstd, s2. Requires version v2, parquet blocks have
+	// their own compression.
+	Encoding string `yaml:"encoding,omitempty"`
+
+	// BloomFilterFalsePositive is the false positive rate of the bloom filters
+	// of a block, between 0 and 1 (Tempo default: 0.01)
+	BloomFilterFalsePositive *float64 `yaml:"bloomFilterFalsePositive,omitempty"`
+
+	// BloomFilterShardSizeBytes is the size of a bloom filter shard in bytes
+	// (Tempo default: 100KiB)
+	BloomFilterShardSizeBytes *int `yaml:"bloomFilterShardSizeBytes,omitempty"`
+}
+
+// EnabledProcessors returns the processors the metrics-generator runs, or nil
+// when it is not enabled
+func (g *MetricsGeneratorConfig) EnabledProcessors() []string {
+	if g == nil {
+		return nil
+	}
+	if len(g.Processors) == 0 {
+		return MetricsGeneratorProcessors
+	}
+	return g.Processors
+}
+
+// UsesGateway reports whether Tempo is deployed with the multitenancy gateway, which
+// serves ingestion and queries
+func (t *TempoConfig) UsesGateway() bool {
+	return t.IngestionAuth != "mtls" && t.IngestionAuth != "none"
+}
+
+// AutoscalingConfig defines HorizontalPodAutoscaler settings for TempoStack components
+type AutoscalingConfig struct {
+	// Components to autoscale: distributor, querier, query-frontend, compactor
+	// Default: ["distributor", "querier"]
+	Components []string `yaml:"components,omitempty"`
+
+	// MinReplicas is the lower replica bound of each HPA
+	// Default: 1
+	MinReplicas int `yaml:"minReplicas,omitempty"`
+
+	// MaxReplicas is the upper replica bound of each HPA (required)
+	MaxReplicas int `yaml:"maxReplicas"`
+
+	// TargetCPUUtilization is the average CPU utilization target in percent of requests
+	// Default: 80
+	TargetCPUUtilization *int `yaml:"targetCPUUtilization,omitempty"`
+
+	// TargetMemoryUtilization is the average memory utilization target in percent of requests.
+	// If not set, only CPU is used.
+	TargetMemoryUtilization *int `yaml:"targetMemoryUtilization,omitempty"`
 }
 
 // TempoOverrides defines Tempo limits and overrides
@@ -103,6 +404,47 @@ type K6Config struct {
 
 	// Query contains query test settings
 	Query QueryConfig `yaml:"query"`
+
+	// Replay contains the trace capture of the replay test (optional)
+	Replay *ReplayConfig `yaml:"replay,omitempty"`
+
+	// Pod overrides the resources and placement of the k6 Job pods (optional)
+	Pod *K6PodConfig `yaml:"pod,omitempty"`
+}
+
+// K6PodConfig overrides the resources and placement of the k6 Job pods, so
+// high-rate profiles can request bigger runner pods and pin them to dedicated
+// load generation nodes
+type K6PodConfig struct {
+	// Resources are the requests and limits of the k6 container (optional).
+	// Default: requests cpu "500m" and memory "512Mi", limits cpu "2" and memory "2Gi"
+	Resources *ResourceSpec `yaml:"resources,omitempty"`
+
+	// NodeSelector pins the k6 pods to nodes with these labels (optional)
+	NodeSelector map[string]string `yaml:"nodeSelector,omitempty"`
+
+	// Tolerations let the k6 pods run on tainted nodes (optional)
+	Tolerations []TolerationConfig `yaml:"tolerations,omitempty"`
+
+	// Affinity is a Kubernetes pod affinity (nodeAffinity, podAffinity,
+	// podAntiAffinity) replacing the default anti-affinity to the Tempo nodes (optional)
+	Affinity map[string]interface{} `yaml:"affinity,omitempty"`
+}
+
+// TolerationConfig is a Kubernetes toleration
+type TolerationConfig struct {
+	Key string `yaml:"key,omitempty"`
+
+	// Operator is "Equal" (default) or "Exists"
+	Operator string `yaml:"operator,omitempty"`
+
+	Value string `yaml:"value,omitempty"`
+
+	// Effect is "NoSchedule", "PreferNoSchedule" or "NoExecute"; empty matches all effects
+	Effect string `yaml:"effect,omitempty"`
+
+	// TolerationSeconds is how long a NoExecute taint is tolerated (optional)
+	TolerationSeconds *int64 `yaml:"tolerationSeconds,omitempty"`
 }
 
 // VUsConfig defines virtual user range
@@ -121,10 +463,58 @@ type IngestionConfig struct {
 
 	// TraceProfile determines trace complexity (small, medium, large, xlarge)
 	TraceProfile string `yaml:"traceProfile"`
+
+	// Protocol is the OTLP protocol traces are sent with, from k6 to the OTel
+	// Collector and from the collector to Tempo: "grpc" (default) or "http"
+	Protocol string `yaml:"protocol,omitempty"`
+}
+
+// IngestionProtocol returns the OTLP protocol traces are ingested with, "grpc"
+// when the profile does not set one
+func (i IngestionConfig) IngestionProtocol() string {
+	if i.Protocol == "" {
+		return "grpc"
+	}
+	return i.Protocol
+}
+
+// ReplayConfig defines the OTLP JSON trace capture replayed by the replay test.
+// Exactly one of File and PVC must be set.
+type ReplayConfig struct {
+	// File is a local capture uploaded in a ConfigMap (up to about 1MiB)
+	File string `yaml:"file,omitempty"`
+
+	// PVC is a PersistentVolumeClaim holding larger captures
+	PVC string `yaml:"pvc,omitempty"`
+
+	// Path is the capture file within the PVC (default "traces.json")
+	Path string `yaml:"path,omitempty"`
+
+	// Speedup replays the capture faster (>1) or slower (<1) than recorded (default 1)
+	Speedup float64 `yaml:"speedup,omitempty"`
 }
 
 // QueryConfig defines query test parameters
 type QueryConfig struct {
 	// QueriesPerSecond is the target query rate
 	QueriesPerSecond int `yaml:"queriesPerSecond"`
+
+	// Corpus selects the TraceQL searches of the query and combined tests (optional):
+	// - "generate": sampled from the attributes Tempo indexed once ingestion has started
+	// - a path to a query corpus file, e.g. one generated by an earlier run
+	// If not set, the built-in queries of the k6 scripts are used.
+	Corpus string `yaml:"corpus,omitempty"`
+}
+
+// QueryCorpusGenerate is the query corpus setting that generates the corpus from
+// the ingested data
+const QueryCorpusGenerate = "generate"
+
+// CorpusFile returns the query corpus file of the profile, or "" when the corpus
+// is generated or the built-in queries are used
+func (q QueryConfig) CorpusFile() string {
+	if q.Corpus == QueryCorpusGenerate {
+		return ""
+	}
+	return q.Corpus
 }
@@ -0,0 +1,126 @@
+package framework
+
+import (
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/k6"
+	"github.com/redhat/perf-tests-tempo/test/framework/metrics"
+	"github.com/redhat/perf-tests-tempo/test/framework/metrics/dashboard"
+)
+
+// MetricsProvider collects the metrics of a test window into a file.
+// The default implementation queries Prometheus/Thanos; set another one with
+// WithMetricsProvider to use a different backend (e.g. Datadog).
+type MetricsProvider interface {
+	CollectMetrics(f *Framework, testStart time.Time, outputPath string) error
+}
+
+// LoadRunner generates the test load against the deployed Tempo.
+// The default implementation runs k6 as Kubernetes Jobs; set another one with
+// WithLoadRunner to use a different load tool.
+type LoadRunner interface {
+	RunTest(f *Framework, testType k6.TestType, config *k6.Config) (*k6.Result, error)
+	RunParallelTests(f *Framework, config *k6.Config) (*k6.ParallelResult, error)
+}
+
+// ReportGenerator renders reports from collected metrics files.
+// The default implementation generates HTML dashboards; set another one with
+// WithReportGenerator to publish results elsewhere.
+type ReportGenerator interface {
+	Generate(metricsPath, outputPath string, config dashboard.DashboardConfig) error
+	GenerateComparison(metricsPaths []string, outputPath string, config dashboard.DashboardConfig) error
+}
+
+// PrometheusMetricsProvider is the default MetricsProvider, querying Prometheus/Thanos
+type PrometheusMetricsProvider struct{}
+
+// CollectMetrics collects the metrics of the framework namespace and exports them to CSV or JSON
+func (PrometheusMetricsProvider) CollectMetrics(f *Framework, testStart time.Time, outputPath string) error {
+	return metrics.CollectMetrics(f, testStart, outputPath)
+}
+
+// K6LoadRunner is the default LoadRunner, running k6 tests as Kubernetes Jobs
+type K6LoadRunner struct{}
+
+// RunTest deploys and runs a k6 test as a Kubernetes Job
+func (K6LoadRunner) RunTest(f *Framework, testType k6.TestType, config *k6.Config) (*k6.Result, error) {
+	return k6.RunTest(f, testType, config)
+}
+
+// RunParallelTests runs ingestion and query tests as separate parallel Kubernetes Jobs
+func (K6LoadRunner) RunParallelTests(f *Framework, config *k6.Config) (*k6.ParallelResult, error) {
+	return k6.RunParallelTests(f, config)
+}
+
+// HTMLReportGenerator is the default ReportGenerator, generating HTML dashboards
+type HTMLReportGenerator struct{}
+
+// Generate generates an HTML dashboard from a metrics file
+func (HTMLReportGenerator) Generate(metricsPath, outputPath string, config dashboard.DashboardConfig) error {
+	return dashboard.Generate(metricsPath, outputPath, config)
+}
+
+// GenerateComparison generates an HTML dashboard comparing several metrics files
+func (HTMLReportGenerator) GenerateComparison(metricsPaths []string, outputPath string, config dashboard.DashboardConfig) error {
+	return dashboard.GenerateComparison(metricsPaths, outputPath, config)
+}
+
+// WithMetricsProvider replaces the Prometheus metrics collection
+func WithMetricsProvider(p MetricsProvider) Option {
+	return func(f *Framework) {
+		f.metricsProvider = p
+	}
+}
+
+// WithMetricsCollector queries the metrics of the default PrometheusMetricsProvider
+// through c, e.g. a metrics.RemoteCollector for a Mimir endpoint, instead of the
+// backend selected by TEMPO_PERF_PROMETHEUS_MODE
+func WithMetricsCollector(c metrics.Collector) Option {
+	return func(f *Framework) {
+		f.metricsCollector = c
+	}
+}
+
+// WithLoadRunner replaces the k6 load generation
+func WithLoadRunner(r LoadRunner) Option {
+	return func(f *Framework) {
+		f.loadRunner = r
+	}
+}
+
+// WithReportGenerator replaces the HTML dashboard generation
+func WithReportGenerator(g ReportGenerator) Option {
+	return func(f *Framework) {
+		f.reportGenerator = g
+	}
+}
+
+// MetricsProvider returns the metrics provider, PrometheusMetricsProvider unless replaced
+func (f *Framework) MetricsProvider() MetricsProvider {
+	if f.metricsProvider == nil {
+		return PrometheusMetricsProvider{}
+	}
+	return f.metricsProvider
+}
+
+// MetricsCollector returns the Collector set with WithMetricsCollector, or nil for
+// the backend of the framework configuration
+func (f *Framework) MetricsCollector() metrics.Collector {
+	return f.metricsCollector
+}
+
+// LoadRunner returns the load runner, K6LoadRunner unless replaced
+func (f *Framework) LoadRunner() LoadRunner {
+	if f.loadRunner == nil {
+		return K6LoadRunner{}
+	}
+	return f.loadRunner
+}
+
+// ReportGenerator returns the report generator, HTMLReportGenerator unless replaced
+func (f *Framework) ReportGenerator() ReportGenerator {
+	if f.reportGenerator == nil {
+		return HTMLReportGenerator{}
+	}
+	return f.reportGenerator
+}
@@ -0,0 +1,88 @@
+package framework
+
+import (
+	"testing"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/k6"
+	"github.com/redhat/perf-tests-tempo/test/framework/metrics/dashboard"
+)
+
+type recordingProvider struct {
+	calls []string
+}
+
+func (r *recordingProvider) CollectMetrics(f *Framework, testStart time.Time, outputPath string) error {
+	r.calls = append(r.calls, "collect:"+outputPath)
+	return nil
+}
+
+func (r *recordingProvider) RunTest(f *Framework, testType k6.TestType, config *k6.Config) (*k6.Result, error) {
+	r.calls = append(r.calls, "run:"+string(testType))
+	return &k6.Result{Success: true}, nil
+}
+
+func (r *recordingProvider) RunParallelTests(f *Framework, config *k6.Config) (*k6.ParallelResult, error) {
+	r.calls = append(r.calls, "parallel")
+	return &k6.ParallelResult{}, nil
+}
+
+func (r *recordingProvider) Generate(metricsPath, outputPath string, config dashboard.DashboardConfig) error {
+	r.calls = append(r.calls, "report:"+config.ProfileName)
+	return nil
+}
+
+func (r *recordingProvider) GenerateComparison(metricsPaths []string, outputPath string, config dashboard.DashboardConfig) error {
+	r.calls = append(r.calls, "compare")
+	return nil
+}
+
+func TestProviders_Defaults(t *testing.T) {
+	f := newOrphanTestFramework()
+
+	if _, ok := f.MetricsProvider().(PrometheusMetricsProvider); !ok {
+		t.Errorf("expected PrometheusMetricsProvider, got %T", f.MetricsProvider())
+	}
+	if _, ok := f.LoadRunner().(K6LoadRunner); !ok {
+		t.Errorf("expected K6LoadRunner, got %T", f.LoadRunner())
+	}
+	if _, ok := f.ReportGenerator().(HTMLReportGenerator); !ok {
+		t.Errorf("expected HTMLReportGenerator, got %T", f.ReportGenerator())
+	}
+}
+
+func TestProviders_Replaced(t *testing.T) {
+	r := &recordingProvider{}
+	f := newOrphanTestFramework()
+	for _, opt := range []Option{WithMetricsProvider(r), WithLoadRunner(r), WithReportGenerator(r)} {
+		opt(f)
+	}
+	// Namespace-scoped copies keep the replacements
+	f = f.forNamespace("tempo-perf-test")
+
+	if _, err := f.RunK6IngestionTest(k6.SizeSmall); err != nil {
+		t.Fatalf("RunK6IngestionTest() error = %v", err)
+	}
+	if _, err := f.RunK6ParallelTests(&k6.Config{}); err != nil {
+		t.Fatalf("RunK6ParallelTests() error = %v", err)
+	}
+	if err := f.CollectMetricsWithDuration(time.Minute, "metrics.csv"); err != nil {
+		t.Fatalf("CollectMetricsWithDuration() error = %v", err)
+	}
+	if err := f.GenerateDashboard("metrics.csv", "dashboard.html", "small"); err != nil {
+		t.Fatalf("GenerateDashboard() error = %v", err)
+	}
+	if err := f.GenerateComparisonDashboard([]string{"a.csv", "b.csv"}, "comparison.html", dashboard.DashboardConfig{}); err != nil {
+		t.Fatalf("GenerateComparisonDashboard() error = %v", err)
+	}
+
+	want := []string{"run:ingestion", "parallel", "collect:metrics.csv", "report:small", "compare"}
+	if len(r.calls) != len(want) {
+		t.Fatalf("calls = %v, want %v", r.calls, want)
+	}
+	for i := range want {
+		if r.calls[i] != want[i] {
+			t.Errorf("call %d = %q, want %q", i, r.calls[i], want[i])
+		}
+	}
+}
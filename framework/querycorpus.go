@@ -0,0 +1,210 @@
+package framework
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/k6"
+)
+
+const (
+	// maxCorpusTags bounds the attributes sampled per scope, as each one costs a
+	// tag values request through the API server service proxy
+	maxCorpusTags = 20
+
+	// maxCorpusTagValues is the number of values of an attribute queried by the corpus
+	maxCorpusTagValues = 5
+
+	// corpusQueryLimit is the trace limit of the attribute searches of the corpus
+	corpusQueryLimit = 20
+)
+
+// corpusScopes are the attribute scopes sampled for the corpus
+var corpusScopes = []string{"resource", "span"}
+
+// corpusIntrinsicQueries are searches on intrinsics, which always have data
+var corpusIntrinsicQueries = []k6.CorpusQuery{
+	{Query: "{ status = error }", Limit: 50},
+	{Query: "{ duration > 100ms }", Limit: 30},
+	{Query: "{ duration > 1s }", Limit: 10},
+}
+
+// corpusAttribute is an attribute found by the search tags API, with a sample of its values
+type corpusAttribute struct {
+	Scope  string
+	Name   string
+	Values []tagValue
+}
+
+// tagValue is a value returned by the search tag values API
+type tagValue struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// searchTagsResponse is the response of /api/v2/search/tags
+type searchTagsResponse struct {
+	Scopes []struct {
+		Name string   `json:"name"`
+		Tags []string `json:"tags"`
+	} `json:"scopes"`
+}
+
+// searchTagValuesResponse is the response of /api/v2/search/tag/{tag}/values
+type searchTagValuesResponse struct {
+	TagValues []tagValue `json:"tagValues"`
+}
+
+// GenerateQueryCorpus samples the attribute keys and values Tempo indexed since start,
+// through the search tags API of the query frontend, and writes a TraceQL query
+// corpus for the query test to outputPath (see k6.Config.QueryCorpusFile). Unlike the
+// built-in queries, its searches match the ingested data. Call it once ingestion
+// has started; it fails if Tempo has no attribute values yet.
+func (f *Framework) GenerateQueryCorpus(variant string, start time.Time, outputPath string) (*k6.QueryCorpus, error) {
+	service, err := queryFrontendService(variant)
+	if err != nil {
+		return nil, err
+	}
+	end := f.Now()
+	window := map[string]string{
+		"start": strconv.FormatInt(start.Unix(), 10),
+		"end":   strconv.FormatInt(end.Unix(), 10),
+	}
+
+	var attributes []corpusAttribute
+	for _, scope := range corpusScopes {
+		tags, err := f.searchTags(service, scope, window)
+		if err != nil {
+			return nil, err
+		}
+		for _, tag := range tags {
+			values, err := f.searchTagValues(service, scope+"."+tag, window)
+			if err != nil {
+				return nil, err
+			}
+			if len(values) > 0 {
+				attributes = append(attributes, corpusAttribute{Scope: scope, Name: tag, Values: values})
+			}
+		}
+	}
+
+	if len(attributes) == 0 {
+		return nil, fmt.Errorf("no attribute values found between %s and %s", start.Format(time.RFC3339), end.Format(time.RFC3339))
+	}
+
+	corpus := &k6.QueryCorpus{GeneratedAt: end.UTC(), Queries: buildQueryCorpus(attributes)}
+	if err := k6.WriteQueryCorpus(corpus, outputPath); err != nil {
+		return nil, err
+	}
+	fmt.Printf("🔎 Generated %d queries from %d attributes to %s\n", len(corpus.Queries), len(attributes), outputPath)
+	return corpus, nil
+}
+
+// searchTags returns up to maxCorpusTags attribute names of a scope, sorted
+func (f *Framework) searchTags(service, scope string, window map[string]string) ([]string, error) {
+	params := map[string]string{"scope": scope}
+	for k, v := range window {
+		params[k] = v
+	}
+	body, err := f.queryFrontendGet(service, "api/v2/search/tags", params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s tags: %w", scope, err)
+	}
+	var response searchTagsResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse %s tags: %w", scope, err)
+	}
+
+	var tags []string
+	for _, s := range response.Scopes {
+		if s.Name != scope {
+			continue
+		}
+		for _, tag := range s.Tags {
+			// The name is a segment of the tag values path
+			if !strings.Contains(tag, "/") {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	sort.Strings(tags)
+	if len(tags) > maxCorpusTags {
+		tags = tags[:maxCorpusTags]
+	}
+	return tags, nil
+}
+
+// searchTagValues returns up to maxCorpusTagValues values of an attribute that
+// TraceQL can compare with, sorted
+func (f *Framework) searchTagValues(service, attribute string, window map[string]string) ([]tagValue, error) {
+	body, err := f.queryFrontendGet(service, "api/v2/search/tag/"+attribute+"/values", window)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get values of %s: %w", attribute, err)
+	}
+	var response searchTagValuesResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse values of %s: %w", attribute, err)
+	}
+
+	var values []tagValue
+	for _, v := range response.TagValues {
+		if _, ok := traceQLValue(v); ok {
+			values = append(values, v)
+		}
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i].Value < values[j].Value })
+	if len(values) > maxCorpusTagValues {
+		values = values[:maxCorpusTagValues]
+	}
+	return values, nil
+}
+
+// buildQueryCorpus returns the intrinsic searches, an equality search for every
+// sampled attribute value, and for each service an error and a slow span search
+func buildQueryCorpus(attributes []corpusAttribute) []k6.CorpusQuery {
+	queries := append([]k6.CorpusQuery(nil), corpusIntrinsicQueries...)
+	for _, a := range attributes {
+		for _, v := range a.Values {
+			value, _ := traceQLValue(v)
+			condition := fmt.Sprintf("%s = %s", traceQLAttribute(a.Scope, a.Name), value)
+			queries = append(queries, k6.CorpusQuery{Query: "{ " + condition + " }", Limit: corpusQueryLimit})
+			if a.Scope == "resource" && a.Name == "service.name" {
+				queries = append(queries,
+					k6.CorpusQuery{Query: "{ " + condition + " && status = error }", Limit: corpusQueryLimit},
+					k6.CorpusQuery{Query: "{ " + condition + " && duration > 200ms }", Limit: corpusQueryLimit},
+				)
+			}
+		}
+	}
+	return queries
+}
+
+// plainAttributeName matches the attribute names TraceQL accepts without quotes
+var plainAttributeName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+// traceQLAttribute returns the TraceQL reference of a scoped attribute, quoting
+// names with other characters
+func traceQLAttribute(scope, name string) string {
+	if plainAttributeName.MatchString(name) {
+		return scope + "." + name
+	}
+	return scope + "." + strconv.Quote(name)
+}
+
+// traceQLValue returns the TraceQL literal of a tag value, or false for types the
+// corpus does not compare
+func traceQLValue(v tagValue) (string, bool) {
+	switch v.Type {
+	case "string":
+		return strconv.Quote(v.Value), true
+	case "int", "float", "bool":
+		return v.Value, v.Value != ""
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,81 @@
+package framework
+
+import (
+	"testing"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/k6"
+)
+
+func TestBuildQueryCorpus(t *testing.T) {
+	attributes := []corpusAttribute{
+		{Scope: "resource", Name: "service.name", Values: []tagValue{{Type: "string", Value: "frontend"}}},
+		{Scope: "span", Name: "http.status_code", Values: []tagValue{{Type: "int", Value: "500"}}},
+	}
+
+	queries := buildQueryCorpus(attributes)
+
+	want := append([]k6.CorpusQuery(nil), corpusIntrinsicQueries...)
+	want = append(want,
+		k6.CorpusQuery{Query: `{ resource.service.name = "frontend" }`, Limit: corpusQueryLimit},
+		k6.CorpusQuery{Query: `{ resource.service.name = "frontend" && status = error }`, Limit: corpusQueryLimit},
+		k6.CorpusQuery{Query: `{ resource.service.name = "frontend" && duration > 200ms }`, Limit: corpusQueryLimit},
+		k6.CorpusQuery{Query: `{ span.http.status_code = 500 }`, Limit: corpusQueryLimit},
+	)
+	if len(queries) != len(want) {
+		t.Fatalf("expected %d queries, got %d: %v", len(want), len(queries), queries)
+	}
+	for i := range want {
+		if queries[i] != want[i] {
+			t.Errorf("query %d: expected %+v, got %+v", i, want[i], queries[i])
+		}
+	}
+}
+
+func TestBuildQueryCorpus_KeepsIntrinsics(t *testing.T) {
+	queries := buildQueryCorpus(nil)
+	queries[0].Query = "changed"
+	if corpusIntrinsicQueries[0].Query == "changed" {
+		t.Error("buildQueryCorpus() modified the intrinsic queries")
+	}
+}
+
+func TestTraceQLAttribute(t *testing.T) {
+	tests := []struct {
+		scope, name, want string
+	}{
+		{"resource", "service.name", "resource.service.name"},
+		{"span", "http_method", "span.http_method"},
+		{"span", "http-method", `span."http-method"`},
+		{"span", "my attr", `span."my attr"`},
+		{"span", "1st", `span."1st"`},
+	}
+
+	for _, tt := range tests {
+		if got := traceQLAttribute(tt.scope, tt.name); got != tt.want {
+			t.Errorf("traceQLAttribute(%q, %q) = %s, want %s", tt.scope, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTraceQLValue(t *testing.T) {
+	tests := []struct {
+		value  tagValue
+		want   string
+		wantOK bool
+	}{
+		{tagValue{Type: "string", Value: "checkout"}, `"checkout"`, true},
+		{tagValue{Type: "string", Value: `say "hi"`}, `"say \"hi\""`, true},
+		{tagValue{Type: "int", Value: "200"}, "200", true},
+		{tagValue{Type: "float", Value: "0.5"}, "0.5", true},
+		{tagValue{Type: "bool", Value: "true"}, "true", true},
+		{tagValue{Type: "int", Value: ""}, "", false},
+		{tagValue{Type: "keyword", Value: "client"}, "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := traceQLValue(tt.value)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("traceQLValue(%+v) = (%s, %v), want (%s, %v)", tt.value, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
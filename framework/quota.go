@@ -0,0 +1,131 @@
+package framework
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NamespaceQuotaName is the name of the ResourceQuota and LimitRange of the test namespace
+const NamespaceQuotaName = "tempo-perf-quota"
+
+// DefaultContainerLimits are the limits of containers that set none in a namespace
+// with a quota. Kubernetes also uses them as the requests of these containers.
+var DefaultContainerLimits = corev1.ResourceList{
+	corev1.ResourceCPU:    resource.MustParse("500m"),
+	corev1.ResourceMemory: resource.MustParse("512Mi"),
+}
+
+// NamespaceQuota caps what the pods of the test namespace may consume, so a
+// runaway pod (e.g. a misconfigured k6 Job) cannot take over the cluster
+type NamespaceQuota struct {
+	// CPU and Memory are the total limits of all containers in the namespace
+	CPU    resource.Quantity
+	Memory resource.Quantity
+
+	// Pods is the maximum number of pods; 0 does not limit them
+	Pods int
+
+	// DefaultLimits are the limits of containers that set none, which the quota
+	// would otherwise reject. Empty uses DefaultContainerLimits.
+	DefaultLimits corev1.ResourceList
+}
+
+// String returns a short description of the quota, e.g. "cpu 16, memory 64Gi, 40 pods"
+func (q *NamespaceQuota) String() string {
+	s := fmt.Sprintf("cpu %s, memory %s", q.CPU.String(), q.Memory.String())
+	if q.Pods > 0 {
+		s += fmt.Sprintf(", %d pods", q.Pods)
+	}
+	return s
+}
+
+// WithNamespaceQuota creates a ResourceQuota and LimitRange in the test namespace
+// when EnsureNamespace runs. Pods rejected by the quota show up as
+// FailedCreate warning events.
+func WithNamespaceQuota(q *NamespaceQuota) Option {
+	return func(f *Framework) {
+		f.namespaceQuota = q
+	}
+}
+
+// applyNamespaceQuota creates or updates the ResourceQuota and LimitRange of the
+// namespace quota, if one is set
+func (f *Framework) applyNamespaceQuota() error {
+	q := f.namespaceQuota
+	if q == nil || f.namespaceQuotaApplied {
+		return nil
+	}
+
+	hard := corev1.ResourceList{
+		corev1.ResourceLimitsCPU:    q.CPU,
+		corev1.ResourceLimitsMemory: q.Memory,
+	}
+	if q.Pods > 0 {
+		hard[corev1.ResourcePods] = *resource.NewQuantity(int64(q.Pods), resource.DecimalSI)
+	}
+	quota := &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      NamespaceQuotaName,
+			Namespace: f.namespace,
+			Labels:    f.GetManagedLabels(),
+		},
+		Spec: corev1.ResourceQuotaSpec{Hard: hard},
+	}
+
+	defaults := q.DefaultLimits
+	if len(defaults) == 0 {
+		defaults = DefaultContainerLimits
+	}
+	limitRange := &corev1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      NamespaceQuotaName,
+			Namespace: f.namespace,
+			Labels:    f.GetManagedLabels(),
+		},
+		Spec: corev1.LimitRangeSpec{
+			Limits: []corev1.LimitRangeItem{{
+				Type:    corev1.LimitTypeContainer,
+				Default: defaults,
+			}},
+		},
+	}
+
+	// The LimitRange goes first, so no pod is created without limits under the quota
+	limitRanges := f.client.CoreV1().LimitRanges(f.namespace)
+	if _, err := limitRanges.Create(f.ctx, limitRange, metav1.CreateOptions{}); err != nil {
+		if !apierrors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create LimitRange: %w", err)
+		}
+		existing, err := limitRanges.Get(f.ctx, NamespaceQuotaName, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get LimitRange: %w", err)
+		}
+		existing.Spec = limitRange.Spec
+		if _, err := limitRanges.Update(f.ctx, existing, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to update LimitRange: %w", err)
+		}
+	}
+
+	quotas := f.client.CoreV1().ResourceQuotas(f.namespace)
+	if _, err := quotas.Create(f.ctx, quota, metav1.CreateOptions{}); err != nil {
+		if !apierrors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create ResourceQuota: %w", err)
+		}
+		existing, err := quotas.Get(f.ctx, NamespaceQuotaName, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get ResourceQuota: %w", err)
+		}
+		existing.Spec = quota.Spec
+		if _, err := quotas.Update(f.ctx, existing, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to update ResourceQuota: %w", err)
+		}
+	}
+
+	f.namespaceQuotaApplied = true
+	f.logger.Info("Applied namespace quota", "namespace", f.namespace, "quota", q.String())
+	return nil
+}
@@ -0,0 +1,97 @@
+package framework
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/config"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestEnsureNamespace_Quota(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	ctx := context.Background()
+	cfg := config.Default()
+	cfg.NamespacePollInterval = 0
+	f := &Framework{
+		client:    client,
+		namespace: "perf",
+		ctx:       ctx,
+		logger:    slog.Default(),
+		config:    cfg,
+	}
+	WithNamespaceQuota(&NamespaceQuota{
+		CPU:    resource.MustParse("16"),
+		Memory: resource.MustParse("64Gi"),
+		Pods:   40,
+	})(f)
+
+	// Setup ensures the namespace once per component
+	for i := 0; i < 2; i++ {
+		if err := f.EnsureNamespace(); err != nil {
+			t.Fatalf("EnsureNamespace() error = %v", err)
+		}
+	}
+
+	quota, err := client.CoreV1().ResourceQuotas("perf").Get(ctx, NamespaceQuotaName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected the ResourceQuota to be created: %v", err)
+	}
+	hard := quota.Spec.Hard
+	if cpu := hard[corev1.ResourceLimitsCPU]; cpu.String() != "16" {
+		t.Errorf("expected a CPU limit of 16, got %s", cpu.String())
+	}
+	if memory := hard[corev1.ResourceLimitsMemory]; memory.String() != "64Gi" {
+		t.Errorf("expected a memory limit of 64Gi, got %s", memory.String())
+	}
+	if pods := hard[corev1.ResourcePods]; pods.Value() != 40 {
+		t.Errorf("expected at most 40 pods, got %s", pods.String())
+	}
+	if quota.Labels[LabelManagedBy] != LabelManagedByValue {
+		t.Errorf("expected the managed labels, got %v", quota.Labels)
+	}
+
+	limitRange, err := client.CoreV1().LimitRanges("perf").Get(ctx, NamespaceQuotaName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected the LimitRange to be created: %v", err)
+	}
+	if len(limitRange.Spec.Limits) != 1 || limitRange.Spec.Limits[0].Default.Cpu().String() != "500m" {
+		t.Errorf("expected the default container limits, got %+v", limitRange.Spec.Limits)
+	}
+}
+
+func TestEnsureNamespace_QuotaUpdated(t *testing.T) {
+	client := fake.NewSimpleClientset(&corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: NamespaceQuotaName, Namespace: "perf"},
+		Spec:       corev1.ResourceQuotaSpec{Hard: corev1.ResourceList{corev1.ResourceLimitsCPU: resource.MustParse("2")}},
+	})
+	cfg := config.Default()
+	cfg.NamespacePollInterval = 0
+	f := &Framework{
+		client:         client,
+		namespace:      "perf",
+		ctx:            context.Background(),
+		logger:         slog.Default(),
+		config:         cfg,
+		namespaceQuota: &NamespaceQuota{CPU: resource.MustParse("8"), Memory: resource.MustParse("32Gi")},
+	}
+	if err := f.EnsureNamespace(); err != nil {
+		t.Fatalf("EnsureNamespace() error = %v", err)
+	}
+
+	quota, err := client.CoreV1().ResourceQuotas("perf").Get(context.Background(), NamespaceQuotaName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get ResourceQuota: %v", err)
+	}
+	if cpu := quota.Spec.Hard[corev1.ResourceLimitsCPU]; cpu.String() != "8" {
+		t.Errorf("expected the existing quota to be updated, got %s", cpu.String())
+	}
+	if _, ok := quota.Spec.Hard[corev1.ResourcePods]; ok {
+		t.Errorf("expected no pod limit, got %v", quota.Spec.Hard)
+	}
+}
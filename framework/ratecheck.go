@@ -0,0 +1,109 @@
+package framework
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/k6"
+	"github.com/redhat/perf-tests-tempo/test/framework/metrics"
+)
+
+// DefaultMinAchievedRatePercent is the percentage of the requested ingestion and
+// query rates the generator must reach for a run to count as delivering its load
+const DefaultMinAchievedRatePercent = 90
+
+// bytesPerMB matches the MB of the profiles' mbPerSecond
+const bytesPerMB = 1024 * 1024
+
+// CheckIngestionRate compares the ingestion rate requested by targetMBPerSecond with
+// the rate k6 achieved (tempo_ingestion_bytes_total, or data_sent without it) and
+// with the bytes the distributors received since testStart over the same load time.
+// A run below minPercent of the target under-delivered: the generator, not Tempo,
+// limited the load. The report is also stored in result.RateCheck.
+//
+// The distributor rate is informational; a failed query is recorded in
+// ReceivedError instead of failing the check. Call it after
+// VerifyIngestionCompleteness, which waits for the last samples to be scraped.
+func (f *Framework) CheckIngestionRate(result *k6.Result, targetMBPerSecond, minPercent float64, testStart time.Time) (*k6.IngestionRateCheck, error) {
+	if result == nil || result.Metrics == nil {
+		return nil, fmt.Errorf("no k6 metrics available to check the ingestion rate")
+	}
+	if targetMBPerSecond <= 0 {
+		return nil, fmt.Errorf("no target ingestion rate to compare with")
+	}
+
+	check := newIngestionRateCheck(result.Metrics, targetMBPerSecond, minPercent)
+	if check.SentMBPerSecond <= 0 {
+		return nil, fmt.Errorf("k6 did not report an ingestion rate")
+	}
+
+	received, err := metrics.DistributorBytesReceived(f, testStart, f.Now())
+	if err != nil {
+		check.ReceivedError = err
+	} else if seconds := loadSeconds(result.Metrics); seconds > 0 {
+		check.ReceivedMBPerSecond = received / seconds / bytesPerMB
+	}
+
+	result.RateCheck = check
+	return check, nil
+}
+
+// newIngestionRateCheck compares the rate k6 achieved with the target
+func newIngestionRateCheck(m *k6.K6Metrics, targetMBPerSecond, minPercent float64) *k6.IngestionRateCheck {
+	sent := m.IngestionBytesRate
+	if sent <= 0 {
+		sent = m.DataSentRate
+	}
+
+	check := &k6.IngestionRateCheck{
+		TargetMBPerSecond: targetMBPerSecond,
+		SentMBPerSecond:   sent / bytesPerMB,
+		WireMBPerSecond:   m.DataSentRate / bytesPerMB,
+	}
+	check.AchievedPercent = check.SentMBPerSecond / targetMBPerSecond * 100
+	check.UnderDelivered = check.AchievedPercent < minPercent
+	return check
+}
+
+// loadSeconds returns how long k6 generated load, derived from a counter's total and
+// average rate; the distributor bytes are spread over the same time
+func loadSeconds(m *k6.K6Metrics) float64 {
+	switch {
+	case m.IngestionBytesRate > 0:
+		return m.IngestionBytesTotal / m.IngestionBytesRate
+	case m.DataSentRate > 0:
+		return m.DataSentTotal / m.DataSentRate
+	}
+	return 0
+}
+
+// CheckQueryRate compares the query rate requested by targetQueriesPerSecond with the
+// rate k6 achieved (k6 iterations, one query each). A run below minPercent of the
+// target under-delivered its queries. The report is also stored in result.QueryRateCheck.
+func (f *Framework) CheckQueryRate(result *k6.Result, targetQueriesPerSecond, minPercent float64) (*k6.QueryRateCheck, error) {
+	if result == nil || result.Metrics == nil {
+		return nil, fmt.Errorf("no k6 metrics available to check the query rate")
+	}
+	if targetQueriesPerSecond <= 0 {
+		return nil, fmt.Errorf("no target query rate to compare with")
+	}
+	if result.Metrics.IterationsRate <= 0 {
+		return nil, fmt.Errorf("k6 did not report a query rate")
+	}
+
+	check := newQueryRateCheck(result.Metrics, targetQueriesPerSecond, minPercent)
+	result.QueryRateCheck = check
+	return check, nil
+}
+
+// newQueryRateCheck compares the query rate k6 achieved with the target
+func newQueryRateCheck(m *k6.K6Metrics, targetQueriesPerSecond, minPercent float64) *k6.QueryRateCheck {
+	check := &k6.QueryRateCheck{
+		TargetQueriesPerSecond:   targetQueriesPerSecond,
+		AchievedQueriesPerSecond: m.IterationsRate,
+		DroppedQueries:           m.DroppedIterations,
+	}
+	check.AchievedPercent = check.AchievedQueriesPerSecond / targetQueriesPerSecond * 100
+	check.UnderDelivered = check.AchievedPercent < minPercent
+	return check
+}
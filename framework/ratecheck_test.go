@@ -0,0 +1,66 @@
+package framework
+
+import (
+	"testing"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/k6"
+)
+
+func TestNewIngestionRateCheck(t *testing.T) {
+	tests := []struct {
+		name           string
+		metrics        k6.K6Metrics
+		wantSent       float64
+		underDelivered bool
+	}{
+		{"reached target", k6.K6Metrics{IngestionBytesRate: 10 * bytesPerMB, DataSentRate: 11 * bytesPerMB}, 10, false},
+		{"within tolerance", k6.K6Metrics{IngestionBytesRate: 9.5 * bytesPerMB}, 9.5, false},
+		{"generator limited", k6.K6Metrics{IngestionBytesRate: 6 * bytesPerMB}, 6, true},
+		{"data_sent fallback", k6.K6Metrics{DataSentRate: 5 * bytesPerMB}, 5, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := newIngestionRateCheck(&tt.metrics, 10, DefaultMinAchievedRatePercent)
+			if check.SentMBPerSecond != tt.wantSent {
+				t.Errorf("expected sent %v MB/s, got %v", tt.wantSent, check.SentMBPerSecond)
+			}
+			if check.AchievedPercent != tt.wantSent*10 {
+				t.Errorf("expected %v%% achieved, got %v", tt.wantSent*10, check.AchievedPercent)
+			}
+			if check.UnderDelivered != tt.underDelivered {
+				t.Errorf("expected under-delivered %v, got %v", tt.underDelivered, check.UnderDelivered)
+			}
+		})
+	}
+}
+
+func TestNewQueryRateCheck(t *testing.T) {
+	check := newQueryRateCheck(&k6.K6Metrics{IterationsRate: 19, DroppedIterations: 60}, 20, DefaultMinAchievedRatePercent)
+	if check.AchievedPercent != 95 {
+		t.Errorf("expected 95%% achieved, got %v", check.AchievedPercent)
+	}
+	if check.DroppedQueries != 60 {
+		t.Errorf("expected 60 dropped queries, got %v", check.DroppedQueries)
+	}
+	if check.UnderDelivered {
+		t.Error("expected 95% of the target not to be under-delivered")
+	}
+
+	check = newQueryRateCheck(&k6.K6Metrics{IterationsRate: 12}, 20, DefaultMinAchievedRatePercent)
+	if !check.UnderDelivered {
+		t.Errorf("expected %v%% of the target to be under-delivered", check.AchievedPercent)
+	}
+}
+
+func TestLoadSeconds(t *testing.T) {
+	if got := loadSeconds(&k6.K6Metrics{IngestionBytesTotal: 6000, IngestionBytesRate: 20}); got != 300 {
+		t.Errorf("expected 300s from ingestion bytes, got %v", got)
+	}
+	if got := loadSeconds(&k6.K6Metrics{DataSentTotal: 1200, DataSentRate: 10}); got != 120 {
+		t.Errorf("expected 120s from data_sent, got %v", got)
+	}
+	if got := loadSeconds(&k6.K6Metrics{}); got != 0 {
+		t.Errorf("expected 0 without rates, got %v", got)
+	}
+}
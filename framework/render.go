@@ -0,0 +1,261 @@
+package framework
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/k6"
+	"github.com/redhat/perf-tests-tempo/test/framework/kafka"
+	"github.com/redhat/perf-tests-tempo/test/framework/minio"
+	"github.com/redhat/perf-tests-tempo/test/framework/otel"
+	"github.com/redhat/perf-tests-tempo/test/framework/tempo"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
+)
+
+// ManifestConfig describes the deployment and loads of a run for RenderManifests
+type ManifestConfig struct {
+	// Variant is the Tempo variant: "monolithic" or "stack"
+	Variant string
+
+	// Resources is the Tempo configuration passed to SetupTempo (optional)
+	Resources *ResourceConfig
+
+	// MinIO is the configuration passed to SetupMinIOWithConfig (optional)
+	MinIO *MinIOConfig
+
+	// SkipMinIO leaves MinIO out, e.g. when traces are stored on a persistent volume
+	SkipMinIO bool
+
+	// TenantCollectors are the tenants given their own collector by
+	// SetupTenantCollector, in order. The loads of these tenants without a
+	// TempoEndpoint send to the collector of their tenant.
+	TenantCollectors []string
+
+	// Loads are the k6 loads of the run, in order
+	Loads []ManifestLoad
+}
+
+// ManifestLoad is a k6 load of a run
+type ManifestLoad struct {
+	// Name names the manifest file of the load, e.g. a phase name.
+	// Default: the test type
+	Name string
+
+	// TestType is the test type passed to RunK6Test
+	TestType k6.TestType
+
+	// Parallel renders the ingestion and query Jobs of RunK6ParallelTests instead
+	Parallel bool
+
+	Config *k6.Config
+}
+
+// manifestFile is a multi-document YAML file written by RenderManifests
+type manifestFile struct {
+	name    string
+	comment string
+	objects []runtime.Object
+}
+
+// RenderManifests writes the manifests a run would apply in the namespace of f to
+// outputDir, without touching the cluster: MinIO, the Tempo CR, Kafka, the OTel
+// Collector CRs, the collectors of other tenants with their RBAC, the fallback
+// PodMonitors and the k6 Jobs, one numbered YAML file each in the order they are
+// applied. It returns the paths of the written files.
+//
+// Objects created from cluster state, like the ingestion certificates, and the
+// patches Setup applies to the operator-managed workloads (env, image, extra
+// files) are not rendered.
+func (f *Framework) RenderManifests(config *ManifestConfig, outputDir string) ([]string, error) {
+	if config == nil {
+		return nil, fmt.Errorf("manifest config is required")
+	}
+	namespace := f.namespace
+	managedLabels := f.GetManagedLabels()
+
+	// SetupTempo stores these for the OTel Collector and k6 setups
+	tempoNodeSelector := f.GetTempoNodeSelector()
+	ingestionAuth := f.GetIngestionAuth()
+	collectorImage := f.GetCollectorImage()
+	ingestionProtocol := f.GetIngestionProtocol()
+	kafkaConfig := f.GetKafka()
+	if r := config.Resources; r != nil {
+		if len(r.NodeSelector) > 0 {
+			tempoNodeSelector = r.NodeSelector
+		}
+		ingestionAuth = r.IngestionAuth
+		collectorImage = r.CollectorImage
+		ingestionProtocol = r.IngestionProtocol
+		kafkaConfig = r.Kafka
+	}
+
+	var files []manifestFile
+	if !config.SkipMinIO {
+		// MinIO is set up before SetupTempo stores the node selector
+		objects, err := minio.Manifests(namespace, f.GetTempoNodeSelector(), toMinIOConfig(config.MinIO))
+		if err != nil {
+			return nil, fmt.Errorf("failed to render MinIO: %w", err)
+		}
+		files = append(files, manifestFile{name: "minio", objects: objects})
+	}
+
+	tempoCR, err := tempo.BuildCR(namespace, config.Variant, toTempoConfig(config.Resources), managedLabels)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render Tempo: %w", err)
+	}
+	files = append(files, manifestFile{name: "tempo", objects: []runtime.Object{tempoCR}})
+
+	if kafkaConfig == nil {
+		files = append(files, manifestFile{name: "otel-collector", objects: []runtime.Object{
+			otel.BuildCollector(namespace, config.Variant, tempoNodeSelector, ingestionAuth, ingestionProtocol, collectorImage, managedLabels),
+		}})
+	} else {
+		if len(kafkaConfig.Brokers) == 0 {
+			objects, err := kafka.Manifests(namespace, tempoNodeSelector, toKafkaConfig(kafkaConfig))
+			if err != nil {
+				return nil, fmt.Errorf("failed to render Kafka: %w", err)
+			}
+			files = append(files, manifestFile{name: "kafka", objects: objects})
+		}
+		file := manifestFile{name: "otel-collector"}
+		for _, collector := range otel.BuildKafkaCollectors(namespace, config.Variant, tempoNodeSelector, ingestionAuth, ingestionProtocol, collectorImage, toOTelKafkaConfig(namespace, f.runID, kafkaConfig), managedLabels) {
+			file.objects = append(file.objects, collector)
+		}
+		files = append(files, file)
+	}
+	for _, tenant := range config.TenantCollectors {
+		files = append(files, manifestFile{
+			name:    "otel-collector-" + tenant,
+			objects: otel.BuildTenantCollector(namespace, config.Variant, tenant, tempoNodeSelector, ingestionAuth, ingestionProtocol, collectorImage, managedLabels),
+		})
+	}
+
+	files = append(files,
+		manifestFile{
+			name:    "podmonitors",
+			comment: "Created only when the operators did not create ServiceMonitors",
+			objects: []runtime.Object{
+				tempo.BuildPodMonitor(namespace, config.Variant, managedLabels),
+				otel.BuildPodMonitor(namespace, managedLabels),
+			},
+		},
+	)
+
+	for _, load := range config.Loads {
+		file := manifestFile{name: "k6-" + load.Name}
+		if load.Name == "" {
+			file.name = "k6-" + string(load.TestType)
+		}
+		loadConfig := load.Config
+		if loadConfig != nil && loadConfig.TempoEndpoint == "" && slices.Contains(config.TenantCollectors, loadConfig.TempoTenant) {
+			tenantConfig := *loadConfig
+			tenantConfig.TempoEndpoint = otel.CollectorEndpoint(namespace, otel.TenantCollectorName(loadConfig.TempoTenant), ingestionProtocol)
+			loadConfig = &tenantConfig
+		}
+		if load.Parallel {
+			jobs, err := k6.BuildParallelJobs(namespace, tempoNodeSelector, loadConfig)
+			if err != nil {
+				return nil, fmt.Errorf("failed to render k6 Jobs: %w", err)
+			}
+			for _, job := range jobs {
+				file.objects = append(file.objects, job)
+			}
+		} else {
+			job, err := k6.BuildJob(namespace, tempoNodeSelector, load.TestType, loadConfig)
+			if err != nil {
+				return nil, fmt.Errorf("failed to render k6 Job: %w", err)
+			}
+			file.objects = append(file.objects, job)
+		}
+		files = append(files, file)
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create manifests directory: %w", err)
+	}
+	var paths []string
+	for i, file := range files {
+		data, err := manifestYAML(file.comment, file.objects)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render %s: %w", file.name, err)
+		}
+		path := filepath.Join(outputDir, fmt.Sprintf("%02d-%s.yaml", i+1, file.name))
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// manifestYAML marshals objects to a multi-document YAML file, headed by comment
+func manifestYAML(comment string, objects []runtime.Object) ([]byte, error) {
+	var buf bytes.Buffer
+	if comment != "" {
+		fmt.Fprintf(&buf, "# %s\n", comment)
+	}
+	for i, obj := range objects {
+		content, err := manifestContent(obj)
+		if err != nil {
+			return nil, err
+		}
+		data, err := yaml.Marshal(content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal manifest: %w", err)
+		}
+		if i > 0 {
+			buf.WriteString("---\n")
+		}
+		buf.Write(data)
+	}
+	return buf.Bytes(), nil
+}
+
+// manifestContent returns the fields of an object as sent to the API server, with its
+// apiVersion and kind and without status or unset creation timestamps
+func manifestContent(obj runtime.Object) (map[string]interface{}, error) {
+	var content map[string]interface{}
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		content = u.DeepCopy().Object
+	} else {
+		// Typed objects leave their apiVersion and kind to the client
+		gvks, _, err := scheme.Scheme.ObjectKinds(obj)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find the kind of %T: %w", obj, err)
+		}
+		content, err = runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert %T: %w", obj, err)
+		}
+		content["apiVersion"] = gvks[0].GroupVersion().String()
+		content["kind"] = gvks[0].Kind
+	}
+	delete(content, "status")
+	pruneCreationTimestamps(content)
+	return content, nil
+}
+
+// pruneCreationTimestamps removes the null creationTimestamp fields of the object
+// metadata, including the metadata of pod and PVC templates
+func pruneCreationTimestamps(value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if ts, ok := v["creationTimestamp"]; ok && ts == nil {
+			delete(v, "creationTimestamp")
+		}
+		for _, child := range v {
+			pruneCreationTimestamps(child)
+		}
+	case []interface{}:
+		for _, child := range v {
+			pruneCreationTimestamps(child)
+		}
+	}
+}
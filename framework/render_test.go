@@ -0,0 +1,205 @@
+package framework
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+)
+
+func TestRenderManifests(t *testing.T) {
+	fw, err := New(context.Background(), "tempo-perf-small-abc123", WithRunID("abc123"), WithRESTConfig(&rest.Config{}))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	dir := t.TempDir()
+
+	paths, err := fw.RenderManifests(&ManifestConfig{
+		Variant:   "monolithic",
+		Resources: &ResourceConfig{NodeSelector: map[string]string{"node-role.kubernetes.io/infra": ""}},
+		MinIO:     &MinIOConfig{Replicas: 4},
+	}, dir)
+	if err != nil {
+		t.Fatalf("RenderManifests() error = %v", err)
+	}
+
+	var names []string
+	for _, p := range paths {
+		names = append(names, filepath.Base(p))
+	}
+	if got := strings.Join(names, ","); got != "01-minio.yaml,02-tempo.yaml,03-otel-collector.yaml,04-podmonitors.yaml" {
+		t.Fatalf("unexpected files %s", got)
+	}
+
+	minio := readManifest(t, paths[0])
+	for _, want := range []string{"kind: Secret", "kind: StatefulSet", "kind: Job", "name: minio-make-bucket"} {
+		if !strings.Contains(minio, want) {
+			t.Errorf("MinIO manifests do not contain %q", want)
+		}
+	}
+	tempo := readManifest(t, paths[1])
+	for _, want := range []string{"kind: TempoMonolithic", "tempo-perf-test.io/run-id: abc123", "namespace: tempo-perf-small-abc123"} {
+		if !strings.Contains(tempo, want) {
+			t.Errorf("Tempo manifest does not contain %q", want)
+		}
+	}
+	// The collector avoids the Tempo nodes
+	if collector := readManifest(t, paths[2]); !strings.Contains(collector, "node-role.kubernetes.io/infra") {
+		t.Error("OTel Collector manifest has no anti-affinity to the Tempo nodes")
+	}
+	if podMonitors := readManifest(t, paths[3]); strings.Count(podMonitors, "kind: PodMonitor") != 2 {
+		t.Errorf("expected 2 PodMonitors, got:\n%s", podMonitors)
+	}
+}
+
+func TestRenderManifests_InvalidVariant(t *testing.T) {
+	fw, err := New(context.Background(), "tempo-perf-small-abc123", WithRESTConfig(&rest.Config{}))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if _, err := fw.RenderManifests(&ManifestConfig{Variant: "distributed", SkipMinIO: true}, t.TempDir()); err == nil {
+		t.Error("expected an error for an invalid variant")
+	}
+}
+
+func TestManifestYAML(t *testing.T) {
+	job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "k6-ingestion-small", Namespace: "perf"}}
+
+	data, err := manifestYAML("comment", []runtime.Object{job, job})
+	if err != nil {
+		t.Fatalf("manifestYAML() error = %v", err)
+	}
+	got := string(data)
+	if !strings.HasPrefix(got, "# comment\napiVersion: batch/v1\nkind: Job\n") {
+		t.Errorf("expected the comment, apiVersion and kind first, got:\n%s", got)
+	}
+	if strings.Count(got, "---\n") != 1 {
+		t.Errorf("expected 2 documents, got:\n%s", got)
+	}
+	for _, unwanted := range []string{"creationTimestamp", "status"} {
+		if strings.Contains(got, unwanted) {
+			t.Errorf("manifest contains %s:\n%s", unwanted, got)
+		}
+	}
+}
+
+// readManifest returns the content of a rendered manifest file
+func readManifest(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestRenderManifests_TenantCollectors(t *testing.T) {
+	fw, err := New(context.Background(), "tempo-perf-noisy-abc123", WithRunID("abc123"), WithRESTConfig(&rest.Config{}))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	paths, err := fw.RenderManifests(&ManifestConfig{
+		Variant:          "stack",
+		Resources:        &ResourceConfig{Tenants: []string{"tenant-1", "tenant-2"}},
+		SkipMinIO:        true,
+		TenantCollectors: []string{"tenant-2"},
+	}, t.TempDir())
+	if err != nil {
+		t.Fatalf("RenderManifests() error = %v", err)
+	}
+	var names []string
+	for _, p := range paths {
+		names = append(names, filepath.Base(p))
+	}
+	if got := strings.Join(names, ","); got != "01-tempo.yaml,02-otel-collector.yaml,03-otel-collector-tenant-2.yaml,04-podmonitors.yaml" {
+		t.Fatalf("unexpected files %s", got)
+	}
+
+	collector := readManifest(t, paths[2])
+	for _, want := range []string{"kind: ClusterRole\n", "kind: ClusterRoleBinding", "name: allow-write-traces-tenant-2-tempo-perf-noisy-abc123", "name: otel-collector-tenant-2", "X-Scope-OrgID: tenant-2"} {
+		if !strings.Contains(collector, want) {
+			t.Errorf("tenant collector manifests do not contain %q", want)
+		}
+	}
+}
+
+func TestRenderManifests_Kafka(t *testing.T) {
+	fw, err := New(context.Background(), "tempo-perf-kafka-abc123", WithRunID("abc123"), WithRESTConfig(&rest.Config{}))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	paths, err := fw.RenderManifests(&ManifestConfig{
+		Variant:   "monolithic",
+		Resources: &ResourceConfig{Kafka: &KafkaConfig{Topic: "tempo-spans", Partitions: 3}},
+		SkipMinIO: true,
+	}, t.TempDir())
+	if err != nil {
+		t.Fatalf("RenderManifests() error = %v", err)
+	}
+	var names []string
+	for _, p := range paths {
+		names = append(names, filepath.Base(p))
+	}
+	if got := strings.Join(names, ","); got != "01-tempo.yaml,02-kafka.yaml,03-otel-collector.yaml,04-podmonitors.yaml" {
+		t.Fatalf("unexpected files %s", got)
+	}
+
+	kafka := readManifest(t, paths[1])
+	for _, want := range []string{"kind: Deployment", "value: \"3\"", "kafka.tempo-perf-kafka-abc123.svc.cluster.local:9092", "enableServiceLinks: false"} {
+		if !strings.Contains(kafka, want) {
+			t.Errorf("Kafka manifests do not contain %q", want)
+		}
+	}
+	// The consumer is created before the collector k6 sends to
+	collectors := strings.Split(readManifest(t, paths[2]), "---\n")
+	if len(collectors) != 2 {
+		t.Fatalf("expected 2 collectors, got %d", len(collectors))
+	}
+	for _, want := range []string{"name: otel-collector-kafka", "group_id: otel-collector-kafka", "topic: tempo-spans", "bearertokenauth"} {
+		if !strings.Contains(collectors[0], want) {
+			t.Errorf("consumer collector does not contain %q", want)
+		}
+	}
+	for _, want := range []string{"name: otel-collector\n", "max_message_bytes: 10485760", "- kafka"} {
+		if !strings.Contains(collectors[1], want) {
+			t.Errorf("producer collector does not contain %q", want)
+		}
+	}
+	if strings.Contains(collectors[1], "bearertokenauth") {
+		t.Error("expected the producer collector not to authenticate to Tempo")
+	}
+
+	// Existing brokers are used as they are
+	paths, err = fw.RenderManifests(&ManifestConfig{
+		Variant:   "monolithic",
+		Resources: &ResourceConfig{Kafka: &KafkaConfig{Brokers: []string{"kafka-bootstrap.kafka.svc:9092"}}},
+		SkipMinIO: true,
+	}, t.TempDir())
+	if err != nil {
+		t.Fatalf("RenderManifests() error = %v", err)
+	}
+	if len(paths) != 3 {
+		t.Fatalf("expected no Kafka manifests with existing brokers, got %v", paths)
+	}
+	collectors = strings.Split(readManifest(t, paths[1]), "---\n")
+	if len(collectors) != 2 {
+		t.Fatalf("expected 2 collectors, got %d", len(collectors))
+	}
+	for _, c := range collectors {
+		if !strings.Contains(c, "kafka-bootstrap.kafka.svc:9092") || !strings.Contains(c, "topic: otlp_spans-abc123") || strings.Contains(c, "max_message_bytes") {
+			t.Errorf("expected the collector to use the existing brokers with the topic of the run and the default message size, got:\n%s", c)
+		}
+	}
+	// Runs sharing the brokers do not share the consumer group
+	if !strings.Contains(collectors[0], "group_id: otel-collector-kafka-abc123") {
+		t.Errorf("expected the consumer group of the run, got:\n%s", collectors[0])
+	}
+}
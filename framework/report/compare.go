@@ -0,0 +1,106 @@
+package report
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/metrics"
+)
+
+// ComparedRun is a run compared by GenerateComparisonMarkdown
+type ComparedRun struct {
+	// Name identifies the run in the report, e.g. its deployment variant
+	Name    string
+	Run     *Run
+	Results []metrics.MetricResult
+}
+
+// GenerateComparisonMarkdown returns the Markdown comparison of runs of the same
+// workload, e.g. a profile deployed as each Tempo variant: the outcome of each
+// run and the average of each key metric with its change from the first run.
+func GenerateComparisonMarkdown(title string, runs []ComparedRun) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "## Tempo performance comparison: %s\n\n", inline(title))
+	if len(runs) > 0 {
+		fmt.Fprintf(&b, "Changes are relative to **%s**.\n\n", inline(runs[0].Name))
+	}
+
+	b.WriteString("### Runs\n\n")
+	writeTable(&b, []string{"Run", "Profile", "Result", "Duration"}, comparedRunRows(runs))
+
+	b.WriteString("\n### Key Metrics\n\n")
+	if rows := comparedMetricRows(runs); len(rows) > 0 {
+		header := []string{"Metric"}
+		for _, r := range runs {
+			header = append(header, r.Name)
+		}
+		writeTable(&b, header, rows)
+	} else {
+		b.WriteString("No metrics were collected.\n")
+	}
+	return b.String()
+}
+
+// comparedRunRows returns the outcome of each compared run
+func comparedRunRows(runs []ComparedRun) [][]string {
+	rows := make([][]string, 0, len(runs))
+	for _, r := range runs {
+		result := statusIcon(r.Run) + " passed"
+		switch {
+		case r.Run.SLOViolated:
+			result = statusIcon(r.Run) + " SLO violated"
+		case r.Run.Error != nil:
+			result = statusIcon(r.Run) + " failed"
+		}
+		rows = append(rows, []string{r.Name, code(r.Run.Profile), result, r.Run.Duration.Round(time.Second).String()})
+	}
+	return rows
+}
+
+// comparedMetricRows returns the average of each key metric per run, with the
+// change from the first run. Metrics without data in any run are skipped.
+func comparedMetricRows(runs []ComparedRun) [][]string {
+	var rows [][]string
+	for _, m := range KeyMetrics {
+		row := []string{m.Title}
+		var reference float64
+		hasReference, hasData := false, false
+		for i, r := range runs {
+			avg, _, ok := aggregate(r.Results, m.Name)
+			if !ok {
+				row = append(row, "➖")
+				continue
+			}
+			hasData = true
+			cell := formatValue(avg, m.Unit)
+			if i == 0 {
+				reference, hasReference = avg, true
+			} else if hasReference {
+				cell += " (" + formatChange(avg, reference) + ")"
+			}
+			row = append(row, cell)
+		}
+		if hasData {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// formatChange returns the relative change of value from reference
+func formatChange(value, reference float64) string {
+	if reference == 0 {
+		if value == 0 {
+			return "±0%"
+		}
+		return "n/a"
+	}
+	change := (value - reference) / math.Abs(reference) * 100
+	if math.Abs(change) < 0.05 {
+		return "±0%"
+	}
+	return fmt.Sprintf("%+.1f%%", change)
+}
@@ -0,0 +1,544 @@
+// Package report renders a concise Markdown summary of a profile run: its
+// configuration, the averages of the key metrics, whether it met its SLOs and
+// links to its artifacts. The summary is short enough to paste into a pull
+// request or send through the notification webhook. GenerateSummary returns the
+// same content as JSON for automation. GenerateComparisonMarkdown puts the key
+// metrics of runs of the same workload side by side.
+package report
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/metrics"
+	"github.com/redhat/perf-tests-tempo/test/framework/metrics/dashboard"
+	"github.com/redhat/perf-tests-tempo/test/framework/retry"
+)
+
+// Run is the outcome of a profile run summarized by GenerateMarkdown
+type Run struct {
+	Profile  string
+	RunID    string
+	Variant  string
+	TestType string
+	// Duration is the wall time of the profile run
+	Duration time.Duration
+
+	// Error is why the run failed; nil if it succeeded
+	Error error
+	// SLOViolated is set when the run failed because k6 thresholds were crossed
+	SLOViolated bool
+	// TimedOutStage is the stage during which the run exceeded its timeout; empty
+	// if it did not time out
+	TimedOutStage string
+
+	// Phases are the recorded test phases of the run
+	Phases []metrics.Phase
+
+	// Metadata is the recorded run metadata, nil when no metrics were collected
+	Metadata *metrics.RunMetadata
+
+	// Quota describes the namespace quota of the run; empty if it had none
+	Quota string
+	// QuotaExceeded are the events of pods the namespace quota rejected
+	QuotaExceeded []metrics.ClusterEvent
+
+	// Alerts are the intervals during which alerts were firing; nil if the alerts
+	// were not collected
+	Alerts []metrics.FiringAlert
+
+	// Retries are the retried operations of the run, e.g. cluster API calls
+	Retries []retry.OperationStats
+
+	// Artifacts are the files written for the run
+	Artifacts []Artifact
+	// ArtifactBaseURL is where the artifacts are published. Links are built from
+	// it and the artifact file names; if empty, they are relative to the report.
+	ArtifactBaseURL string
+}
+
+// Artifact is a file written for a run
+type Artifact struct {
+	Name string
+	Path string
+}
+
+// KeyMetric is a metric averaged in the report
+type KeyMetric struct {
+	// Name is the metric name (see metrics.GetAllQueries)
+	Name  string
+	Title string
+	// Unit is "bytes", "seconds", or a label appended to the value, e.g. "spans/s"
+	Unit string
+}
+
+// KeyMetrics are the metrics averaged in the report, in report order
+var KeyMetrics = []KeyMetric{
+	{Name: "accepted_spans_rate", Title: "Accepted spans", Unit: "spans/s"},
+	{Name: "refused_spans_rate", Title: "Refused spans", Unit: "spans/s"},
+	{Name: "distributor_push_duration_p99", Title: "Push latency P99", Unit: "seconds"},
+	{Name: "queries_per_second", Title: "Queries", Unit: "queries/s"},
+	{Name: "query_duration_p99", Title: "Query latency P99", Unit: "seconds"},
+	{Name: "compactor_outstanding_blocks", Title: "Compaction backlog", Unit: "blocks"},
+	{Name: "compaction_duration_p99", Title: "Compaction duration P99", Unit: "seconds"},
+	{Name: "cpu_usage_total", Title: "Tempo CPU", Unit: "cores"},
+	{Name: "memory_usage_total", Title: "Tempo memory", Unit: "bytes"},
+}
+
+// GenerateMarkdown returns the Markdown summary of a run from its metric results
+func GenerateMarkdown(run *Run, results []metrics.MetricResult) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "## %s Tempo performance run: %s\n\n", statusIcon(run), run.Profile)
+	switch {
+	case run.Error == nil:
+		b.WriteString("**Result:** passed\n")
+	case run.SLOViolated:
+		fmt.Fprintf(&b, "**Result:** SLO violated: %s\n", inline(run.Error.Error()))
+	default:
+		fmt.Fprintf(&b, "**Result:** failed: %s\n", inline(run.Error.Error()))
+	}
+
+	b.WriteString("\n### Configuration\n\n")
+	writeTable(&b, []string{"Setting", "Value"}, configRows(run))
+
+	b.WriteString("\n### Key Metrics\n\n")
+	if rows := metricRows(results); len(rows) > 0 {
+		writeTable(&b, []string{"Metric", "Avg", "Max"}, rows)
+	} else {
+		b.WriteString("No metrics were collected.\n")
+	}
+
+	if rows := storageRows(results); len(rows) > 0 {
+		b.WriteString("\n### Storage Footprint\n\n")
+		writeTable(&b, []string{"Measure", "Value"}, rows)
+	}
+
+	if run.Metadata != nil && run.Metadata.Isolation != nil {
+		b.WriteString("\n### Tenant Isolation\n\n")
+		writeTable(&b, []string{"Phase", "Tenant", "Ingestion", "Ingestion p99", "Queries", "Query p99", "Query failures"},
+			isolationRows(run.Metadata.Isolation))
+	}
+
+	b.WriteString("\n### SLOs\n\n")
+	writeTable(&b, []string{"Check", "Result", "Detail"}, sloRows(run))
+
+	if len(run.Alerts) > 0 {
+		b.WriteString("\n### Firing Alerts\n\n")
+		writeTable(&b, []string{"Alert", "Severity", "Start", "Duration"}, alertRows(run.Alerts))
+	}
+
+	if len(run.Retries) > 0 {
+		b.WriteString("\n### Retries\n\n")
+		writeTable(&b, []string{"Operation", "Calls", "Retries", "Failed", "Waited"}, retryRows(run.Retries))
+	}
+
+	if len(run.Artifacts) > 0 {
+		b.WriteString("\n### Artifacts\n\n")
+		for _, a := range run.Artifacts {
+			fmt.Fprintf(&b, "- [%s](%s)\n", a.Name, artifactLink(run.ArtifactBaseURL, a.Path))
+		}
+	}
+	return b.String()
+}
+
+// statusIcon returns the emoji of the run outcome
+func statusIcon(run *Run) string {
+	switch {
+	case run.Error == nil:
+		return "✅"
+	case run.SLOViolated:
+		return "⚠️"
+	default:
+		return "❌"
+	}
+}
+
+// configRows returns the configuration table of a run, skipping settings that
+// are not known, e.g. of a run that failed during setup
+func configRows(run *Run) [][]string {
+	var rows [][]string
+	add := func(name, value string) {
+		if value != "" {
+			rows = append(rows, []string{name, value})
+		}
+	}
+	duration := func(d time.Duration) string {
+		if d <= 0 {
+			return ""
+		}
+		return d.Round(time.Second).String()
+	}
+
+	add("Profile", code(run.Profile))
+	add("Run ID", code(run.RunID))
+	add("Variant", run.Variant)
+	add("Test type", run.TestType)
+	add("Duration", duration(run.Duration))
+	add("Namespace quota", run.Quota)
+
+	if meta := run.Metadata; meta != nil {
+		add("Load duration", duration(meta.TestDuration))
+		add("Tempo version", meta.TempoVersion)
+		add("Ingestion auth", meta.IngestionAuth)
+		add("Ingestion protocol", meta.IngestionProtocol)
+		add("Ingestion path", meta.IngestionPath)
+		add("Metrics-generator", strings.Join(meta.MetricsGenerator, ", "))
+		add("Block format", meta.Block.String())
+		if meta.Replicas > 1 {
+			add("Replicas", fmt.Sprint(meta.Replicas))
+		}
+		for _, key := range sortedKeys(meta.TempoEnv) {
+			add("Env "+key, code(meta.TempoEnv[key]))
+		}
+		for _, key := range sortedKeys(meta.Images) {
+			add("Image "+key, code(meta.Images[key]))
+		}
+		add("Framework commit", code(meta.FrameworkSHA))
+	}
+	return rows
+}
+
+// metricRows returns the average and max of each key metric with data
+func metricRows(results []metrics.MetricResult) [][]string {
+	var rows [][]string
+	for _, m := range KeyMetrics {
+		avg, max, ok := aggregate(results, m.Name)
+		if !ok {
+			continue
+		}
+		rows = append(rows, []string{m.Title, formatValue(avg, m.Unit), formatValue(max, m.Unit)})
+	}
+	return rows
+}
+
+// aggregate returns the average and max of the values of all series of a metric
+func aggregate(results []metrics.MetricResult, name string) (avg, max float64, ok bool) {
+	var sum float64
+	count := 0
+	for _, r := range results {
+		if r.MetricName != name || r.Error != nil {
+			continue
+		}
+		for _, dp := range r.DataPoints {
+			if math.IsNaN(dp.Value) || math.IsInf(dp.Value, 0) {
+				continue
+			}
+			if count == 0 || dp.Value > max {
+				max = dp.Value
+			}
+			sum += dp.Value
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, 0, false
+	}
+	return sum / float64(count), max, true
+}
+
+// bytesPerMB matches the MB of the ingestion rates
+const bytesPerMB = 1024 * 1024
+
+// storageRows returns the storage footprint of a run: the bytes and objects stored
+// at its end and, when the storage grew, the growth relative to the bytes the
+// distributors received over the run. Nothing is returned without storage samples.
+func storageRows(results []metrics.MetricResult) [][]string {
+	first, last, ok := seriesEnds(results, metrics.StorageBytesMetric)
+	if !ok {
+		return nil
+	}
+	rows := [][]string{{"Stored bytes", dashboard.FormatValue(last, "bytes")}}
+	if _, objects, ok := seriesEnds(results, metrics.StorageObjectsMetric); ok {
+		rows = append(rows, []string{"Stored objects", fmt.Sprintf("%.0f", objects)})
+	}
+
+	growth := last - first
+	ingested := integrate(results, "bytes_received_rate")
+	if ingested <= 0 {
+		return rows
+	}
+	rows = append(rows, []string{"Ingested bytes", dashboard.FormatValue(ingested, "bytes")})
+	if growth <= 0 {
+		// Blocks are flushed after they are cut, and MinIO reports usage after a scan
+		return append(rows, []string{"Compression ratio", "no growth of the storage was measured during the run"})
+	}
+	return append(rows,
+		[]string{"Bytes stored per MB ingested", dashboard.FormatValue(growth/(ingested/bytesPerMB), "bytes")},
+		[]string{"Compression ratio", fmt.Sprintf("%.1fx", ingested/growth)},
+	)
+}
+
+// seriesEnds returns the earliest and latest values of the series of a metric,
+// summed over its series at those times
+func seriesEnds(results []metrics.MetricResult, name string) (first, last float64, ok bool) {
+	var start, end time.Time
+	for _, r := range results {
+		if r.MetricName != name || r.Error != nil || len(r.DataPoints) == 0 {
+			continue
+		}
+		points := r.DataPoints
+		switch t := points[0].Timestamp; {
+		case !ok || t.Before(start):
+			start, first = t, points[0].Value
+		case t.Equal(start):
+			first += points[0].Value
+		}
+		switch t := points[len(points)-1].Timestamp; {
+		case !ok || t.After(end):
+			end, last = t, points[len(points)-1].Value
+		case t.Equal(end):
+			last += points[len(points)-1].Value
+		}
+		ok = true
+	}
+	return first, last, ok
+}
+
+// integrate returns the total of a per-second rate metric over its series, e.g.
+// the bytes received from bytes_received_rate
+func integrate(results []metrics.MetricResult, name string) float64 {
+	var total float64
+	for _, r := range results {
+		if r.MetricName != name || r.Error != nil {
+			continue
+		}
+		for i := 1; i < len(r.DataPoints); i++ {
+			v := r.DataPoints[i].Value
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				continue
+			}
+			total += v * r.DataPoints[i].Timestamp.Sub(r.DataPoints[i-1].Timestamp).Seconds()
+		}
+	}
+	return total
+}
+
+// formatValue formats a key metric value with its unit
+func formatValue(v float64, unit string) string {
+	switch unit {
+	case "bytes", "seconds":
+		return dashboard.FormatValue(v, unit)
+	default:
+		return dashboard.FormatValue(v, "count") + " " + unit
+	}
+}
+
+// sloRows returns the table of the pass/fail checks of a run
+func sloRows(run *Run) [][]string {
+	checks := SLOChecks(run)
+	rows := make([][]string, 0, len(checks))
+	for _, c := range checks {
+		rows = append(rows, []string{c.Name, checkIcons[c.Result] + " " + c.Result, c.Detail})
+	}
+	return rows
+}
+
+// SLOChecks returns the pass/fail checks of a run
+func SLOChecks(run *Run) []SLOCheck {
+	var checks []SLOCheck
+	meta := run.Metadata
+	var backlog *metrics.CompactionBacklog
+	if meta != nil {
+		backlog = meta.CompactionBacklog
+	}
+
+	switch {
+	// The compaction backlog is only checked once the k6 thresholds passed
+	case run.Error == nil || backlog.Violated():
+		checks = append(checks, SLOCheck{Name: "k6 thresholds", Result: CheckPass})
+	case run.SLOViolated:
+		checks = append(checks, SLOCheck{Name: "k6 thresholds", Result: CheckFail, Detail: run.Error.Error()})
+	default:
+		checks = append(checks, SLOCheck{Name: "k6 thresholds", Result: CheckUnknown, Detail: "the run failed for another reason"})
+	}
+
+	if run.Quota != "" || len(run.QuotaExceeded) > 0 {
+		detail := "no pods were rejected"
+		if n := len(run.QuotaExceeded); n > 0 {
+			e := run.QuotaExceeded[0]
+			detail = fmt.Sprintf("%d quota-exceeded events, first: %s: %s", n, e.Object, e.Message)
+		}
+		checks = append(checks, SLOCheck{Name: "Namespace quota", Result: passFail(len(run.QuotaExceeded) == 0), Detail: detail})
+	}
+
+	if run.Alerts != nil {
+		detail := "no alerts fired"
+		if n := len(run.Alerts); n > 0 {
+			detail = fmt.Sprintf("%d firing intervals of %s", n, strings.Join(alertNames(run.Alerts), ", "))
+		}
+		checks = append(checks, SLOCheck{Name: "Alerts", Result: passFail(len(run.Alerts) == 0), Detail: detail})
+	}
+
+	if meta == nil {
+		return checks
+	}
+	if a := meta.Attainment; a != nil {
+		if a.Ingestion != nil {
+			checks = append(checks, SLOCheck{Name: "Ingestion rate", Result: passFail(!a.Ingestion.UnderDelivered),
+				Detail: fmt.Sprintf("%.2f of %.2f MB/s (%.1f%%)", a.Ingestion.Achieved, a.Ingestion.Target, a.Ingestion.AchievedPercent)})
+		}
+		if a.Queries != nil {
+			checks = append(checks, SLOCheck{Name: "Query rate", Result: passFail(!a.Queries.UnderDelivered),
+				Detail: fmt.Sprintf("%.1f of %.0f queries/s (%.1f%%)", a.Queries.Achieved, a.Queries.Target, a.Queries.AchievedPercent)})
+		}
+	}
+	if meta.LogErrors != nil {
+		detail := "no error patterns in the component logs"
+		if meta.LogErrors.Total > 0 {
+			detail = fmt.Sprintf("%d matching lines in %d logs", meta.LogErrors.Total, len(meta.LogErrors.Logs))
+		}
+		checks = append(checks, SLOCheck{Name: "Log errors", Result: passFail(meta.LogErrors.Total == 0), Detail: detail})
+	}
+	if backlog != nil && backlog.MaxGrowth != nil {
+		check := SLOCheck{Name: "Compaction backlog", Result: CheckUnknown, Detail: "the backlog was not collected"}
+		if backlog.Checked() {
+			check.Result = passFail(!backlog.Violated())
+			check.Detail = fmt.Sprintf("grew by %.0f blocks (%.0f to %.0f, peak %.0f), allowed %d",
+				backlog.Growth(), backlog.Start, backlog.End, backlog.Peak, *backlog.MaxGrowth)
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// isolationRows returns the load of each tenant per phase of a noisy-neighbor run,
+// with the change of the p99 latencies of the victim from its baseline
+func isolationRows(isolation *metrics.TenantIsolation) [][]string {
+	rows := make([][]string, 0, len(isolation.Loads))
+	for _, l := range isolation.Loads {
+		tenant := l.Tenant
+		if tenant == isolation.Aggressor {
+			tenant += " (aggressor)"
+		}
+		ingestionP99, queryP99 := "-", "-"
+		if l.IngestionP99 > 0 {
+			ingestionP99 = dashboard.FormatValue(l.IngestionP99, "seconds")
+		}
+		if l.QueryP99 > 0 {
+			queryP99 = dashboard.FormatValue(l.QueryP99, "seconds")
+		}
+		if l.Tenant == isolation.Victim && l.Phase != metrics.IsolationBaseline {
+			ingestionP99 += impactSuffix(isolation.IngestionImpact(l.Phase))
+			queryP99 += impactSuffix(isolation.QueryImpact(l.Phase))
+		}
+		queries := "-"
+		if l.QueriesPerSecond > 0 {
+			queries = fmt.Sprintf("%.1f/s", l.QueriesPerSecond)
+		}
+		rows = append(rows, []string{l.Phase, tenant, fmt.Sprintf("%.2f MB/s", l.IngestionMBPerSecond),
+			ingestionP99, queries, queryP99, fmt.Sprintf("%.0f", l.QueryFailures)})
+	}
+	return rows
+}
+
+// impactSuffix formats the change of a latency from the baseline, empty when unknown
+func impactSuffix(percent float64) string {
+	if math.IsNaN(percent) {
+		return ""
+	}
+	return fmt.Sprintf(" (%+.1f%%)", percent)
+}
+
+// alertRows returns the timeline of the firing alerts, in UTC
+func alertRows(alerts []metrics.FiringAlert) [][]string {
+	rows := make([][]string, 0, len(alerts))
+	for _, a := range alerts {
+		duration := a.Duration().Round(time.Second).String()
+		if a.Active {
+			duration += " (still firing)"
+		}
+		rows = append(rows, []string{a.Name, a.Severity, a.Start.UTC().Format(time.TimeOnly), duration})
+	}
+	return rows
+}
+
+// retryRows returns the attempts of each retried operation; cancelled calls count
+// as failed
+func retryRows(ops []retry.OperationStats) [][]string {
+	rows := make([][]string, 0, len(ops))
+	for _, op := range ops {
+		rows = append(rows, []string{op.Operation, fmt.Sprint(op.Calls), fmt.Sprint(op.Retries()),
+			fmt.Sprint(op.Failures + op.Cancelled), op.Delay.Round(time.Second).String()})
+	}
+	return rows
+}
+
+// alertNames returns the distinct names of the alerts, in order of first firing
+func alertNames(alerts []metrics.FiringAlert) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, a := range alerts {
+		if !seen[a.Name] {
+			seen[a.Name] = true
+			names = append(names, a.Name)
+		}
+	}
+	return names
+}
+
+// passFail returns the result of a check
+func passFail(pass bool) string {
+	if pass {
+		return CheckPass
+	}
+	return CheckFail
+}
+
+// checkIcons are the emoji of the check results in the Markdown tables
+var checkIcons = map[string]string{
+	CheckPass:    "✅",
+	CheckFail:    "❌",
+	CheckUnknown: "➖",
+}
+
+// artifactLink returns the link of an artifact, relative to the report unless
+// the artifacts are published at baseURL
+func artifactLink(baseURL, path string) string {
+	name := filepath.Base(path)
+	if baseURL == "" {
+		return name
+	}
+	return strings.TrimSuffix(baseURL, "/") + "/" + name
+}
+
+// writeTable writes a Markdown table
+func writeTable(b *strings.Builder, header []string, rows [][]string) {
+	fmt.Fprintf(b, "| %s |\n", strings.Join(header, " | "))
+	fmt.Fprintf(b, "|%s\n", strings.Repeat("---|", len(header)))
+	for _, row := range rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = inline(cell)
+		}
+		fmt.Fprintf(b, "| %s |\n", strings.Join(cells, " | "))
+	}
+}
+
+// inline makes text safe for a single line or table cell
+func inline(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// code formats a value as inline code; empty values stay empty
+func code(s string) string {
+	if s == "" {
+		return ""
+	}
+	return "`" + s + "`"
+}
+
+// sortedKeys returns the keys of a map in order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
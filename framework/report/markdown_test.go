@@ -0,0 +1,329 @@
+package report
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/metrics"
+	"github.com/redhat/perf-tests-tempo/test/framework/retry"
+)
+
+func TestGenerateMarkdown_Passed(t *testing.T) {
+	run := &Run{
+		Profile:  "small",
+		RunID:    "a1b2c3",
+		Variant:  "monolithic",
+		TestType: "combined",
+		Duration: 12*time.Minute + 300*time.Millisecond,
+		Metadata: &metrics.RunMetadata{
+			TempoVersion:      "2.7.1",
+			TestDuration:      5 * time.Minute,
+			IngestionProtocol: "http",
+			Block:             &metrics.BlockFormat{Version: "v2", Encoding: "zstd"},
+			Images:            map[string]string{"tempo": "quay.io/tempo:2.7.1"},
+			Attainment: &metrics.LoadAttainment{
+				Ingestion: &metrics.RateAttainment{Target: 1, Achieved: 0.99, AchievedPercent: 99},
+				Queries:   &metrics.RateAttainment{Target: 20, Achieved: 12, AchievedPercent: 60, UnderDelivered: true},
+			},
+		},
+		Artifacts:       []Artifact{{Name: "dashboard.html", Path: "results/small-a1b2c3-dashboard.html"}},
+		ArtifactBaseURL: "https://example.com/runs/",
+	}
+	results := []metrics.MetricResult{
+		{MetricName: "accepted_spans_rate", DataPoints: []metrics.DataPoint{{Value: 1000}, {Value: 3000}, {Value: math.NaN()}}},
+		{MetricName: "memory_usage_total", DataPoints: []metrics.DataPoint{{Value: 1024 * 1024 * 1024}}},
+		{MetricName: "query_duration_p99", Error: errors.New("query failed"), DataPoints: []metrics.DataPoint{{Value: 5}}},
+	}
+
+	md := GenerateMarkdown(run, results)
+	for _, want := range []string{
+		"## ✅ Tempo performance run: small",
+		"| Run ID | `a1b2c3` |",
+		"| Duration | 12m0s |",
+		"| Tempo version | 2.7.1 |",
+		"| Ingestion protocol | http |",
+		"| Block format | v2, zstd |",
+		"| Image tempo | `quay.io/tempo:2.7.1` |",
+		"| Accepted spans | 2.00K spans/s | 3.00K spans/s |",
+		"| Tempo memory | 1.00 GB | 1.00 GB |",
+		"| k6 thresholds | ✅ pass |",
+		"| Ingestion rate | ✅ pass | 0.99 of 1.00 MB/s (99.0%) |",
+		"| Query rate | ❌ fail | 12.0 of 20 queries/s (60.0%) |",
+		"- [dashboard.html](https://example.com/runs/small-a1b2c3-dashboard.html)",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("expected the report to contain %q, got:\n%s", want, md)
+		}
+	}
+	if strings.Contains(md, "Query latency") {
+		t.Errorf("expected failed queries to be skipped, got:\n%s", md)
+	}
+}
+
+func TestGenerateMarkdown_SLOViolated(t *testing.T) {
+	run := &Run{
+		Profile:     "large",
+		Error:       errors.New("k6 thresholds | crossed\nhttp_req_failed"),
+		SLOViolated: true,
+	}
+
+	md := GenerateMarkdown(run, nil)
+	for _, want := range []string{
+		"## ⚠️ Tempo performance run: large",
+		"**Result:** SLO violated: k6 thresholds \\| crossed http_req_failed",
+		"| k6 thresholds | ❌ fail | k6 thresholds \\| crossed http_req_failed |",
+		"No metrics were collected.",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("expected the report to contain %q, got:\n%s", want, md)
+		}
+	}
+	if strings.Contains(md, "| Variant |") || strings.Contains(md, "### Artifacts") {
+		t.Errorf("expected unknown settings and artifacts to be skipped, got:\n%s", md)
+	}
+}
+
+func TestGenerateMarkdown_CompactionBacklog(t *testing.T) {
+	maxGrowth := 10
+	run := &Run{
+		Profile:     "large",
+		Error:       errors.New("compaction backlog grew by 18 blocks, more than the allowed 10"),
+		SLOViolated: true,
+		Metadata: &metrics.RunMetadata{CompactionBacklog: &metrics.CompactionBacklog{
+			Samples: 20, Start: 4, End: 22, Peak: 30, MaxGrowth: &maxGrowth,
+		}},
+	}
+
+	md := GenerateMarkdown(run, nil)
+	for _, want := range []string{
+		"| k6 thresholds | ✅ pass |",
+		"| Compaction backlog | ❌ fail | grew by 18 blocks (4 to 22, peak 30), allowed 10 |",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("expected the report to contain %q, got:\n%s", want, md)
+		}
+	}
+
+	run.Metadata.CompactionBacklog = &metrics.CompactionBacklog{MaxGrowth: &maxGrowth}
+	if md := GenerateMarkdown(run, nil); !strings.Contains(md, "| Compaction backlog | ➖ unknown | the backlog was not collected |") {
+		t.Errorf("expected an unknown check without samples, got:\n%s", md)
+	}
+}
+
+func TestGenerateMarkdown_QuotaExceeded(t *testing.T) {
+	run := &Run{
+		Profile: "small",
+		Error:   errors.New("k6 test did not succeed"),
+		Quota:   "cpu 16, memory 64Gi",
+		QuotaExceeded: []metrics.ClusterEvent{
+			{Type: "Warning", Reason: "FailedCreate", Object: "Job/k6-ingestion-small", Message: "exceeded quota: tempo-perf-quota"},
+			{Type: "Warning", Reason: "FailedCreate", Object: "Job/k6-query-small", Message: "exceeded quota: tempo-perf-quota"},
+		},
+	}
+
+	md := GenerateMarkdown(run, nil)
+	for _, want := range []string{
+		"| Namespace quota | cpu 16, memory 64Gi |",
+		"| Namespace quota | ❌ fail | 2 quota-exceeded events, first: Job/k6-ingestion-small: exceeded quota: tempo-perf-quota |",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("expected the report to contain %q, got:\n%s", want, md)
+		}
+	}
+
+	run.QuotaExceeded = nil
+	if md := GenerateMarkdown(run, nil); !strings.Contains(md, "| Namespace quota | ✅ pass | no pods were rejected |") {
+		t.Errorf("expected the quota check to pass, got:\n%s", md)
+	}
+}
+
+func TestGenerateMarkdown_Alerts(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	run := &Run{
+		Profile: "small",
+		Alerts: []metrics.FiringAlert{
+			{Name: "TempoPerfHighRefusedSpans", Severity: "warning", Start: start, End: start.Add(90 * time.Second)},
+			{Name: "TempoPerfQueryQueueSaturated", Severity: "warning", Start: start.Add(time.Minute), End: start.Add(2 * time.Minute), Active: true},
+			{Name: "TempoPerfHighRefusedSpans", Severity: "warning", Start: start.Add(5 * time.Minute), End: start.Add(6 * time.Minute)},
+		},
+	}
+
+	md := GenerateMarkdown(run, nil)
+	for _, want := range []string{
+		"| Alerts | ❌ fail | 3 firing intervals of TempoPerfHighRefusedSpans, TempoPerfQueryQueueSaturated |",
+		"### Firing Alerts",
+		"| TempoPerfHighRefusedSpans | warning | 12:00:00 | 1m30s |",
+		"| TempoPerfQueryQueueSaturated | warning | 12:01:00 | 1m0s (still firing) |",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("expected the report to contain %q, got:\n%s", want, md)
+		}
+	}
+
+	run.Alerts = []metrics.FiringAlert{}
+	md = GenerateMarkdown(run, nil)
+	if !strings.Contains(md, "| Alerts | ✅ pass | no alerts fired |") || strings.Contains(md, "### Firing Alerts") {
+		t.Errorf("expected the alerts check to pass without a timeline, got:\n%s", md)
+	}
+
+	run.Alerts = nil
+	if md := GenerateMarkdown(run, nil); strings.Contains(md, "| Alerts |") {
+		t.Errorf("expected no alerts check when alerts were not collected, got:\n%s", md)
+	}
+}
+
+func TestGenerateMarkdown_Failed(t *testing.T) {
+	run := &Run{
+		Profile:   "small",
+		Error:     errors.New("failed to setup Tempo"),
+		Artifacts: []Artifact{{Name: "events.json", Path: "/tmp/results/small-x-events.json"}},
+	}
+
+	md := GenerateMarkdown(run, nil)
+	for _, want := range []string{"## ❌", "| k6 thresholds | ➖ unknown |", "- [events.json](small-x-events.json)"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("expected the report to contain %q, got:\n%s", want, md)
+		}
+	}
+}
+
+func TestGenerateMarkdown_StorageFootprint(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	points := func(values ...float64) []metrics.DataPoint {
+		dps := make([]metrics.DataPoint, len(values))
+		for i, v := range values {
+			dps[i] = metrics.DataPoint{Timestamp: start.Add(time.Duration(i) * time.Minute), Value: v}
+		}
+		return dps
+	}
+	const mb = 1024 * 1024
+	results := []metrics.MetricResult{
+		// 1 MB/s for three minutes, split by status
+		{MetricName: "bytes_received_rate", Labels: map[string]string{"status": "ok"}, DataPoints: points(0, 0.5*mb, 0.5*mb, 0.5*mb)},
+		{MetricName: "bytes_received_rate", Labels: map[string]string{"status": "other"}, DataPoints: points(0, 0.5*mb, 0.5*mb, 0.5*mb)},
+		{MetricName: metrics.StorageBytesMetric, DataPoints: points(mb, 10*mb, 46*mb)},
+		{MetricName: metrics.StorageObjectsMetric, DataPoints: points(0, 12, 30)},
+	}
+
+	md := GenerateMarkdown(&Run{Profile: "small"}, results)
+	for _, want := range []string{
+		"### Storage Footprint",
+		"| Stored bytes | 46.00 MB |",
+		"| Stored objects | 30 |",
+		"| Ingested bytes | 180.00 MB |",
+		"| Bytes stored per MB ingested | 256.00 KB |",
+		"| Compression ratio | 4.0x |",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("expected the report to contain %q, got:\n%s", want, md)
+		}
+	}
+
+	// Before blocks are flushed the storage does not grow
+	results[2].DataPoints = points(mb, mb)
+	md = GenerateMarkdown(&Run{Profile: "small"}, results)
+	if !strings.Contains(md, "| Compression ratio | no growth of the storage was measured during the run |") {
+		t.Errorf("expected no compression ratio without growth, got:\n%s", md)
+	}
+
+	if md := GenerateMarkdown(&Run{Profile: "small"}, results[:2]); strings.Contains(md, "Storage Footprint") {
+		t.Errorf("expected no storage footprint without samples, got:\n%s", md)
+	}
+}
+
+func TestGenerateComparisonMarkdown(t *testing.T) {
+	runs := []ComparedRun{
+		{
+			Name: "monolithic",
+			Run:  &Run{Profile: "small-monolithic", Duration: 10 * time.Minute},
+			Results: []metrics.MetricResult{
+				{MetricName: "accepted_spans_rate", DataPoints: []metrics.DataPoint{{Value: 2000}}},
+				{MetricName: "cpu_usage_total", DataPoints: []metrics.DataPoint{{Value: 2}}},
+			},
+		},
+		{
+			Name: "stack",
+			Run:  &Run{Profile: "small-stack", Duration: 12 * time.Minute, Error: errors.New("k6 test did not succeed"), SLOViolated: true},
+			Results: []metrics.MetricResult{
+				{MetricName: "accepted_spans_rate", DataPoints: []metrics.DataPoint{{Value: 2500}}},
+				{MetricName: "cpu_usage_total", DataPoints: []metrics.DataPoint{{Value: 2}}},
+				{MetricName: "query_duration_p99", DataPoints: []metrics.DataPoint{{Value: 0.5}}},
+			},
+		},
+	}
+
+	md := GenerateComparisonMarkdown("small", runs)
+	for _, want := range []string{
+		"## Tempo performance comparison: small",
+		"Changes are relative to **monolithic**.",
+		"| monolithic | `small-monolithic` | ✅ passed | 10m0s |",
+		"| stack | `small-stack` | ⚠️ SLO violated | 12m0s |",
+		"| Metric | monolithic | stack |",
+		"| Accepted spans | 2.00K spans/s | 2.50K spans/s (+25.0%) |",
+		"| Tempo CPU | 2.00 cores | 2.00 cores (±0%) |",
+		"| Query latency P99 | ➖ | 500.00 ms |",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("expected the comparison to contain %q, got:\n%s", want, md)
+		}
+	}
+	if strings.Contains(md, "Refused spans") {
+		t.Errorf("expected metrics without data to be skipped, got:\n%s", md)
+	}
+}
+
+func TestGenerateMarkdown_Retries(t *testing.T) {
+	run := &Run{
+		Profile: "small",
+		Retries: []retry.OperationStats{
+			{Operation: "metrics collection", Calls: 1, Attempts: 3, Successes: 1, Delay: 20 * time.Second},
+			{Operation: "list pods", Calls: 4, Attempts: 9, Successes: 2, Failures: 1, Cancelled: 1, Delay: 1500 * time.Millisecond},
+		},
+	}
+	md := GenerateMarkdown(run, nil)
+	for _, want := range []string{
+		"### Retries",
+		"| Operation | Calls | Retries | Failed | Waited |",
+		"| metrics collection | 1 | 2 | 0 | 20s |",
+		"| list pods | 4 | 5 | 2 | 2s |",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("expected the report to contain %q, got:\n%s", want, md)
+		}
+	}
+	if strings.Contains(GenerateMarkdown(&Run{Profile: "small"}, nil), "### Retries") {
+		t.Error("expected no retries section without retried operations")
+	}
+}
+
+func TestGenerateMarkdown_TenantIsolation(t *testing.T) {
+	run := &Run{
+		Profile: "noisy-neighbor",
+		Metadata: &metrics.RunMetadata{Isolation: &metrics.TenantIsolation{
+			Victim:    "tenant-1",
+			Aggressor: "tenant-2",
+			Loads: []metrics.TenantLoad{
+				{Tenant: "tenant-1", Phase: metrics.IsolationBaseline, IngestionP99: 0.05, QueryP99: 0.8, IngestionMBPerSecond: 2, QueriesPerSecond: 10},
+				{Tenant: "tenant-1", Phase: metrics.IsolationBurst, IngestionP99: 0.1, QueryP99: 1, IngestionMBPerSecond: 2, QueriesPerSecond: 10, QueryFailures: 4},
+				{Tenant: "tenant-2", Phase: metrics.IsolationBurst, IngestionP99: 0.3, IngestionMBPerSecond: 20},
+			},
+		}},
+	}
+	md := GenerateMarkdown(run, nil)
+	for _, want := range []string{
+		"### Tenant Isolation",
+		"| baseline | tenant-1 | 2.00 MB/s | 50.00 ms | 10.0/s | 800.00 ms | 0 |",
+		"| burst | tenant-1 | 2.00 MB/s | 100.00 ms (+100.0%) | 10.0/s | 1.000 s (+25.0%) | 4 |",
+		"| burst | tenant-2 (aggressor) | 20.00 MB/s | 300.00 ms | - | - | 0 |",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("expected the report to contain %q, got:\n%s", want, md)
+		}
+	}
+	if strings.Contains(GenerateMarkdown(&Run{Profile: "small", Metadata: &metrics.RunMetadata{}}, nil), "### Tenant Isolation") {
+		t.Error("expected no tenant isolation section without a noisy-neighbor run")
+	}
+}
@@ -0,0 +1,166 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/metrics"
+	"github.com/redhat/perf-tests-tempo/test/framework/retry"
+)
+
+// Statuses of a run in its summary
+const (
+	StatusPassed      = "passed"
+	StatusSLOViolated = "slo_violated"
+	StatusTimedOut    = "timed_out"
+	StatusFailed      = "failed"
+)
+
+// Results of an SLO check
+const (
+	CheckPass    = "pass"
+	CheckFail    = "fail"
+	CheckUnknown = "unknown"
+)
+
+// SLOCheck is a pass/fail check of a run, e.g. the k6 thresholds or the alerts
+type SLOCheck struct {
+	Name string `json:"name"`
+	// Result is CheckPass, CheckFail or CheckUnknown
+	Result string `json:"result"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Summary is the machine-readable summary of a run, with the same content as its
+// Markdown report, so automation does not have to parse the runner output
+type Summary struct {
+	Profile      string `json:"profile"`
+	RunID        string `json:"run_id"`
+	Variant      string `json:"variant,omitempty"`
+	TestType     string `json:"test_type,omitempty"`
+	TempoVersion string `json:"tempo_version,omitempty"`
+
+	// Status is StatusPassed, StatusSLOViolated, StatusTimedOut or StatusFailed
+	Status string `json:"status"`
+	// Error is why the run failed
+	Error string `json:"error,omitempty"`
+	// TimedOutStage is the stage during which a timed out run exceeded its timeout
+	TimedOutStage string `json:"timed_out_stage,omitempty"`
+
+	// StartedAt is when the load started; nil for runs that failed before it
+	StartedAt *time.Time `json:"started_at,omitempty"`
+	// DurationSeconds is the wall time of the run, TestDurationSeconds the time
+	// spent generating load
+	DurationSeconds     float64 `json:"duration_seconds"`
+	TestDurationSeconds float64 `json:"test_duration_seconds,omitempty"`
+	// Phases are the recorded test phases, in order
+	Phases []SummaryPhase `json:"phases,omitempty"`
+
+	// GeneratorLimited is set when k6 did not reach the requested ingestion rate
+	GeneratorLimited bool                    `json:"generator_limited,omitempty"`
+	Attainment       *metrics.LoadAttainment `json:"attainment,omitempty"`
+	SLOs             []SLOCheck              `json:"slos"`
+	KeyMetrics       []SummaryMetric         `json:"key_metrics,omitempty"`
+	Retries          []retry.OperationStats  `json:"retries,omitempty"`
+	Artifacts        []SummaryArtifact       `json:"artifacts,omitempty"`
+}
+
+// SummaryPhase is a test phase of a run
+type SummaryPhase struct {
+	Name            string    `json:"name"`
+	Start           time.Time `json:"start"`
+	End             time.Time `json:"end"`
+	DurationSeconds float64   `json:"duration_seconds"`
+}
+
+// SummaryMetric is the average and max of a key metric, in the unit of KeyMetric
+type SummaryMetric struct {
+	Name string  `json:"name"`
+	Unit string  `json:"unit"`
+	Avg  float64 `json:"avg"`
+	Max  float64 `json:"max"`
+}
+
+// SummaryArtifact is a file written for a run. URL is set when the artifacts are
+// published.
+type SummaryArtifact struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	URL  string `json:"url,omitempty"`
+}
+
+// GenerateSummary returns the summary of a run from its metric results
+func GenerateSummary(run *Run, results []metrics.MetricResult) *Summary {
+	s := &Summary{
+		Profile:         run.Profile,
+		RunID:           run.RunID,
+		Variant:         run.Variant,
+		TestType:        run.TestType,
+		Status:          runStatus(run),
+		TimedOutStage:   run.TimedOutStage,
+		DurationSeconds: run.Duration.Seconds(),
+		SLOs:            SLOChecks(run),
+		Retries:         run.Retries,
+	}
+	if run.Error != nil {
+		s.Error = run.Error.Error()
+	}
+	if meta := run.Metadata; meta != nil {
+		s.TempoVersion = meta.TempoVersion
+		if !meta.StartedAt.IsZero() {
+			startedAt := meta.StartedAt
+			s.StartedAt = &startedAt
+		}
+		s.TestDurationSeconds = meta.TestDuration.Seconds()
+		s.GeneratorLimited = meta.GeneratorLimited
+		s.Attainment = meta.Attainment
+	}
+	for _, p := range run.Phases {
+		s.Phases = append(s.Phases, SummaryPhase{Name: p.Name, Start: p.Start, End: p.End, DurationSeconds: p.End.Sub(p.Start).Seconds()})
+	}
+	for _, m := range KeyMetrics {
+		if avg, max, ok := aggregate(results, m.Name); ok {
+			s.KeyMetrics = append(s.KeyMetrics, SummaryMetric{Name: m.Name, Unit: m.Unit, Avg: avg, Max: max})
+		}
+	}
+	for _, a := range run.Artifacts {
+		artifact := SummaryArtifact{Name: a.Name, Path: a.Path}
+		if run.ArtifactBaseURL != "" {
+			artifact.URL = artifactLink(run.ArtifactBaseURL, a.Path)
+		}
+		s.Artifacts = append(s.Artifacts, artifact)
+	}
+	return s
+}
+
+// runStatus returns the status of a run in its summary
+func runStatus(run *Run) string {
+	switch {
+	case run.Error == nil:
+		return StatusPassed
+	case run.TimedOutStage != "":
+		return StatusTimedOut
+	case run.SLOViolated:
+		return StatusSLOViolated
+	default:
+		return StatusFailed
+	}
+}
+
+// WriteSummary writes the summary of a run as indented JSON
+func WriteSummary(summary *Summary, outputPath string) error {
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode run summary: %w", err)
+	}
+	if err := os.WriteFile(outputPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write run summary: %w", err)
+	}
+	return nil
+}
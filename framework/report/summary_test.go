@@ -0,0 +1,114 @@
+package report
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/metrics"
+)
+
+func TestGenerateSummary_Passed(t *testing.T) {
+	started := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	run := &Run{
+		Profile:  "phased",
+		RunID:    "a1b2c3",
+		Variant:  "stack",
+		TestType: "phased",
+		Duration: 20 * time.Minute,
+		Phases: []metrics.Phase{
+			{Name: "ingest", Start: started, End: started.Add(5 * time.Minute)},
+			{Name: "query", Start: started.Add(6 * time.Minute), End: started.Add(8 * time.Minute)},
+		},
+		Metadata: &metrics.RunMetadata{
+			TempoVersion: "2.7.1",
+			StartedAt:    started,
+			TestDuration: 8 * time.Minute,
+			Attainment: &metrics.LoadAttainment{
+				Ingestion: &metrics.RateAttainment{Target: 1, Achieved: 0.99, AchievedPercent: 99},
+			},
+		},
+		Artifacts:       []Artifact{{Name: "dashboard.html", Path: "results/phased-a1b2c3-dashboard.html"}},
+		ArtifactBaseURL: "https://example.com/runs",
+	}
+	results := []metrics.MetricResult{
+		{MetricName: "accepted_spans_rate", DataPoints: []metrics.DataPoint{{Value: 1000}, {Value: 3000}}},
+	}
+
+	s := GenerateSummary(run, results)
+	if s.Status != StatusPassed || s.Error != "" || s.TempoVersion != "2.7.1" {
+		t.Errorf("expected a passed run of Tempo 2.7.1, got %+v", s)
+	}
+	if s.DurationSeconds != 1200 || s.TestDurationSeconds != 480 {
+		t.Errorf("expected durations of 1200s and 480s, got %v and %v", s.DurationSeconds, s.TestDurationSeconds)
+	}
+	if s.StartedAt == nil || !s.StartedAt.Equal(started) {
+		t.Errorf("expected the load to start at %s, got %v", started, s.StartedAt)
+	}
+	if len(s.Phases) != 2 || s.Phases[0].Name != "ingest" || s.Phases[1].DurationSeconds != 120 {
+		t.Errorf("expected the ingest and 120s query phases, got %+v", s.Phases)
+	}
+	if s.Attainment == nil || s.Attainment.Ingestion.AchievedPercent != 99 {
+		t.Errorf("expected the ingestion attainment, got %+v", s.Attainment)
+	}
+	if len(s.SLOs) != 2 || s.SLOs[0].Name != "k6 thresholds" || s.SLOs[0].Result != CheckPass || s.SLOs[1].Name != "Ingestion rate" {
+		t.Errorf("expected passed k6 thresholds and ingestion rate checks, got %+v", s.SLOs)
+	}
+	if len(s.KeyMetrics) != 1 || s.KeyMetrics[0].Avg != 2000 || s.KeyMetrics[0].Max != 3000 || s.KeyMetrics[0].Unit != "spans/s" {
+		t.Errorf("expected the accepted spans averaged, got %+v", s.KeyMetrics)
+	}
+	if len(s.Artifacts) != 1 || s.Artifacts[0].URL != "https://example.com/runs/phased-a1b2c3-dashboard.html" {
+		t.Errorf("expected the dashboard published under the base URL, got %+v", s.Artifacts)
+	}
+}
+
+func TestGenerateSummary_Status(t *testing.T) {
+	tests := []struct {
+		name string
+		run  *Run
+		want string
+	}{
+		{"slo violated", &Run{Error: errors.New("k6 test did not succeed"), SLOViolated: true}, StatusSLOViolated},
+		{"timed out", &Run{Error: errors.New("profile timed out"), TimedOutStage: "load"}, StatusTimedOut},
+		{"failed", &Run{Error: errors.New("failed to setup Tempo")}, StatusFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := GenerateSummary(tt.run, nil)
+			if s.Status != tt.want {
+				t.Errorf("expected status %s, got %s", tt.want, s.Status)
+			}
+			if s.Error != tt.run.Error.Error() {
+				t.Errorf("expected error %q, got %q", tt.run.Error, s.Error)
+			}
+			if s.StartedAt != nil || s.Phases != nil {
+				t.Errorf("expected no start time or phases without metadata, got %+v", s)
+			}
+		})
+	}
+}
+
+func TestWriteSummary(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results", "small-a1b2c3-summary.json")
+	if err := WriteSummary(GenerateSummary(&Run{Profile: "small", RunID: "a1b2c3"}, nil), path); err != nil {
+		t.Fatalf("WriteSummary() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("expected valid JSON, got %v", err)
+	}
+	if decoded["status"] != StatusPassed || decoded["run_id"] != "a1b2c3" {
+		t.Errorf("expected a passed run a1b2c3, got %v", decoded)
+	}
+	if _, ok := decoded["started_at"]; ok {
+		t.Error("expected no start time for a run without metadata")
+	}
+}
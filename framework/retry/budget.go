@@ -0,0 +1,102 @@
+package retry
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Default retry budget values
+const (
+	DefaultBudgetRate  = 10.0
+	DefaultBudgetBurst = 10
+)
+
+// Budget is a token bucket of retries shared by concurrent operations. Each retry
+// takes a token and tokens are refilled at a fixed rate up to a burst; first attempts
+// are free. When the budget is exhausted, retries wait for a token, so operations
+// retrying against an overloaded API server collectively slow down to the refill
+// rate instead of stampeding it after every backoff.
+type Budget struct {
+	rate  float64 // tokens per second
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewBudget creates a budget admitting retriesPerSecond retries on average and up to
+// burst retries at once. Non-positive values use DefaultBudgetRate and DefaultBudgetBurst.
+func NewBudget(retriesPerSecond float64, burst int) *Budget {
+	if retriesPerSecond <= 0 {
+		retriesPerSecond = DefaultBudgetRate
+	}
+	if burst <= 0 {
+		burst = DefaultBudgetBurst
+	}
+	return &Budget{
+		rate:   retriesPerSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait takes a token, blocking until one is available or ctx is done.
+// A token reserved while waiting is returned if ctx is done first.
+func (b *Budget) Wait(ctx context.Context) error {
+	delay := b.reserve(time.Now())
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		b.release()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Available returns the number of retries the budget admits without waiting
+func (b *Budget) Available() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill(time.Now())
+	if b.tokens < 0 {
+		return 0
+	}
+	return b.tokens
+}
+
+// reserve takes a token and returns how long the caller must wait until it is refilled.
+// Tokens may go negative, queueing waiters in reservation order.
+func (b *Budget) reserve(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill(now)
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// release returns a reserved token that was not used
+func (b *Budget) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens = min(b.burst, b.tokens+1)
+}
+
+// refill adds the tokens accrued since the last refill; callers hold mu
+func (b *Budget) refill(now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = min(b.burst, b.tokens+elapsed.Seconds()*b.rate)
+		b.last = now
+	}
+}
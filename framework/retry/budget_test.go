@@ -0,0 +1,89 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBudget_Burst(t *testing.T) {
+	b := NewBudget(1, 3)
+
+	for i := 0; i < 3; i++ {
+		if delay := b.reserve(b.last); delay != 0 {
+			t.Fatalf("retry %d within the burst waited %v", i+1, delay)
+		}
+	}
+	if delay := b.reserve(b.last); delay != time.Second {
+		t.Errorf("expected the 4th retry to wait 1s, got %v", delay)
+	}
+	if delay := b.reserve(b.last); delay != 2*time.Second {
+		t.Errorf("expected the 5th retry to queue behind the 4th, got %v", delay)
+	}
+}
+
+func TestBudget_Refill(t *testing.T) {
+	b := NewBudget(10, 1)
+	start := b.last
+
+	b.reserve(start)
+	if delay := b.reserve(start.Add(100 * time.Millisecond)); delay != 0 {
+		t.Errorf("expected a refilled token after 100ms, got a wait of %v", delay)
+	}
+
+	// Idle time refills no more than the burst
+	b.reserve(start.Add(time.Hour))
+	if delay := b.reserve(start.Add(time.Hour)); delay <= 0 {
+		t.Error("expected the burst to cap the refill")
+	}
+}
+
+func TestBudget_WaitCanceled(t *testing.T) {
+	b := NewBudget(0.001, 1)
+	if err := b.Wait(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := b.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+	// The canceled reservation is returned, so the next retry does not queue behind it
+	if b.tokens < -0.1 {
+		t.Errorf("expected the canceled token to be returned, tokens = %v", b.tokens)
+	}
+}
+
+func TestDoWithBudget_SharedAcrossOperations(t *testing.T) {
+	const operations = 5
+	b := NewBudget(50, 1)
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < operations; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			attempts := 0
+			err := DoWithBudget(context.Background(), b, func(ctx context.Context) error {
+				attempts++
+				if attempts == 1 {
+					return errors.New("server overloaded")
+				}
+				return nil
+			}, WithInitialDelay(time.Millisecond), WithJitter(0))
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// One retry is admitted at once, the other four at 50 per second
+	if elapsed := time.Since(start); elapsed < 70*time.Millisecond {
+		t.Errorf("expected retries to be spread over at least 80ms, took %v", elapsed)
+	}
+}
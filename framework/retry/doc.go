@@ -52,6 +52,38 @@
 //	    }),
 //	)
 //
+// # Retry Budgets
+//
+// Share a Budget between concurrent operations to limit their combined retry
+// rate, so they back off together when the API server is overloaded instead of
+// retrying in lockstep. First attempts never wait for the budget:
+//
+//	budget := retry.NewBudget(5, 10) // 5 retries/sec, bursts of 10
+//	for _, item := range items {
+//	    go func() {
+//	        err := retry.DoWithBudget(ctx, budget, func(ctx context.Context) error {
+//	            return process(ctx, item)
+//	        }, retry.WithMaxAttempts(5))
+//	    }()
+//	}
+//
+// WithBudget adds a budget to Do or DoWithData.
+//
+// # Retry Metrics
+//
+// Report the attempts, the time waited between them and the outcome of named
+// operations to a Recorder. Stats aggregates them per operation name, e.g. to see
+// how flaky the cluster API was during a run:
+//
+//	stats := retry.NewStats()
+//	err := retry.Do(ctx, fn,
+//	    retry.WithName("create job"),
+//	    retry.WithMetrics(stats),
+//	)
+//	for _, op := range stats.Snapshot() {
+//	    fmt.Printf("%s: %d retries\n", op.Operation, op.Retries())
+//	}
+//
 // # Returning Values
 //
 // Use DoWithData to retry and return a value:
@@ -0,0 +1,140 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Outcome is how a retried operation finished
+type Outcome string
+
+// Outcomes of a retried operation
+const (
+	OutcomeSuccess   Outcome = "success"
+	OutcomeFailure   Outcome = "failure"
+	OutcomeCancelled Outcome = "cancelled"
+)
+
+// UnnamedOperation is the operation name recorded for operations without WithName
+const UnnamedOperation = "unnamed"
+
+// Record describes one retried operation, reported to a Recorder when it finishes
+type Record struct {
+	Operation string
+	// Attempts is the number of calls of the function, including the first
+	Attempts int
+	// Delay is the time spent waiting between attempts, for the backoff and the budget
+	Delay   time.Duration
+	Outcome Outcome
+}
+
+// Recorder receives the record of every operation retried with WithMetrics.
+// It is called from the goroutine of the operation, so it must be safe for
+// concurrent use when operations run concurrently.
+type Recorder interface {
+	RecordRetry(r Record)
+}
+
+// WithMetrics reports the attempts, delay and outcome of the operation to a recorder
+func WithMetrics(r Recorder) Option {
+	return func(c *Config) {
+		c.Recorder = r
+	}
+}
+
+// WithName names the operation in the records of WithMetrics and on retry span events
+func WithName(name string) Option {
+	return func(c *Config) {
+		c.Name = name
+	}
+}
+
+// outcomeOf returns the outcome of an operation that returned err
+func outcomeOf(ctx context.Context, err error) Outcome {
+	switch {
+	case err == nil:
+		return OutcomeSuccess
+	case ctx.Err() != nil && errors.Is(err, ctx.Err()):
+		return OutcomeCancelled
+	default:
+		return OutcomeFailure
+	}
+}
+
+// OperationStats aggregates the records of an operation
+type OperationStats struct {
+	Operation string `json:"operation"`
+	// Calls is the number of times the operation ran, Attempts the calls of its
+	// function over all of them
+	Calls    int `json:"calls"`
+	Attempts int `json:"attempts"`
+
+	Successes int `json:"successes"`
+	Failures  int `json:"failures"`
+	Cancelled int `json:"cancelled,omitempty"`
+
+	// Delay is the total time spent waiting between attempts
+	Delay time.Duration `json:"delay"`
+}
+
+// Retries returns the number of attempts beyond the first of each call
+func (s OperationStats) Retries() int {
+	return s.Attempts - s.Calls
+}
+
+// Stats is a Recorder aggregating the records of each operation, e.g. of all the
+// operations of a run. It is safe for concurrent use.
+type Stats struct {
+	mu  sync.Mutex
+	ops map[string]*OperationStats
+}
+
+// NewStats creates empty stats
+func NewStats() *Stats {
+	return &Stats{ops: make(map[string]*OperationStats)}
+}
+
+// RecordRetry adds the record of an operation
+func (s *Stats) RecordRetry(r Record) {
+	name := r.Operation
+	if name == "" {
+		name = UnnamedOperation
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	op, ok := s.ops[name]
+	if !ok {
+		op = &OperationStats{Operation: name}
+		s.ops[name] = op
+	}
+	op.Calls++
+	op.Attempts += r.Attempts
+	op.Delay += r.Delay
+	switch r.Outcome {
+	case OutcomeSuccess:
+		op.Successes++
+	case OutcomeCancelled:
+		op.Cancelled++
+	default:
+		op.Failures++
+	}
+}
+
+// Snapshot returns the stats of each operation recorded so far, by operation name
+func (s *Stats) Snapshot() []OperationStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.ops) == 0 {
+		return nil
+	}
+	stats := make([]OperationStats, 0, len(s.ops))
+	for _, op := range s.ops {
+		stats = append(stats, *op)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Operation < stats[j].Operation })
+	return stats
+}
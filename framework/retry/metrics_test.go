@@ -0,0 +1,76 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// recorded collects the records of operations
+type recorded []Record
+
+func (r *recorded) RecordRetry(rec Record) {
+	*r = append(*r, rec)
+}
+
+func TestWithMetrics_Outcomes(t *testing.T) {
+	var records recorded
+	calls := 0
+	err := Do(context.Background(), func(ctx context.Context) error {
+		calls++
+		if calls < 3 {
+			return errors.New("transient error")
+		}
+		return nil
+	}, WithName("list pods"), WithMetrics(&records), WithMaxAttempts(5), WithInitialDelay(time.Millisecond), WithJitter(0))
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+
+	_ = Do(context.Background(), func(ctx context.Context) error {
+		return Permanent(errors.New("not found"))
+	}, WithName("get pod"), WithMetrics(&records))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_ = Do(ctx, func(ctx context.Context) error { return nil }, WithMetrics(&records))
+
+	if len(records) != 3 {
+		t.Fatalf("expected 3 records, got %d", len(records))
+	}
+	if r := records[0]; r.Operation != "list pods" || r.Attempts != 3 || r.Outcome != OutcomeSuccess || r.Delay < 2*time.Millisecond {
+		t.Errorf("unexpected record of a retried success: %+v", r)
+	}
+	if r := records[1]; r.Operation != "get pod" || r.Attempts != 1 || r.Outcome != OutcomeFailure || r.Delay != 0 {
+		t.Errorf("unexpected record of a permanent failure: %+v", r)
+	}
+	if r := records[2]; r.Attempts != 0 || r.Outcome != OutcomeCancelled {
+		t.Errorf("unexpected record of a cancelled operation: %+v", r)
+	}
+}
+
+func TestStats_Snapshot(t *testing.T) {
+	stats := NewStats()
+	if stats.Snapshot() != nil {
+		t.Error("expected no stats before any record")
+	}
+	stats.RecordRetry(Record{Operation: "list pods", Attempts: 3, Delay: 3 * time.Second, Outcome: OutcomeSuccess})
+	stats.RecordRetry(Record{Operation: "list pods", Attempts: 5, Delay: 10 * time.Second, Outcome: OutcomeFailure})
+	stats.RecordRetry(Record{Attempts: 1, Outcome: OutcomeCancelled})
+
+	snapshot := stats.Snapshot()
+	if len(snapshot) != 2 {
+		t.Fatalf("expected 2 operations, got %d", len(snapshot))
+	}
+	want := OperationStats{Operation: "list pods", Calls: 2, Attempts: 8, Successes: 1, Failures: 1, Delay: 13 * time.Second}
+	if snapshot[0] != want {
+		t.Errorf("snapshot[0] = %+v, want %+v", snapshot[0], want)
+	}
+	if snapshot[0].Retries() != 6 {
+		t.Errorf("Retries() = %d, want 6", snapshot[0].Retries())
+	}
+	if op := snapshot[1]; op.Operation != UnnamedOperation || op.Cancelled != 1 {
+		t.Errorf("unexpected stats of an unnamed operation: %+v", op)
+	}
+}
@@ -5,6 +5,9 @@ import (
 	"errors"
 	"math/rand"
 	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Default retry configuration values
@@ -39,6 +42,16 @@ type Config struct {
 
 	// OnRetry is called before each retry with the attempt number and error
 	OnRetry func(attempt int, err error, delay time.Duration)
+
+	// Budget is shared with other operations to limit their combined retry rate.
+	// If nil, retries are only limited by the backoff delay.
+	Budget *Budget
+
+	// Name identifies the operation in records and span events (optional)
+	Name string
+
+	// Recorder receives the record of the operation when it finishes (optional)
+	Recorder Recorder
 }
 
 // DefaultConfig returns a Config with default values
@@ -104,6 +117,13 @@ func WithOnRetry(fn func(attempt int, err error, delay time.Duration)) Option {
 	}
 }
 
+// WithBudget shares a retry budget with other operations
+func WithBudget(b *Budget) Option {
+	return func(c *Config) {
+		c.Budget = b
+	}
+}
+
 // RetryableError wraps an error to indicate it should be retried
 type RetryableError struct {
 	Err error
@@ -169,6 +189,17 @@ func Do(ctx context.Context, fn func(ctx context.Context) error, opts ...Option)
 		cfg.MaxAttempts = 1
 	}
 
+	record := Record{Operation: cfg.Name}
+	err := do(ctx, cfg, fn, &record)
+	if cfg.Recorder != nil {
+		record.Outcome = outcomeOf(ctx, err)
+		cfg.Recorder.RecordRetry(record)
+	}
+	return err
+}
+
+// do runs the attempts of Do and records their count and delay
+func do(ctx context.Context, cfg *Config, fn func(ctx context.Context) error, record *Record) error {
 	var lastErr error
 	delay := cfg.InitialDelay
 
@@ -180,6 +211,7 @@ func Do(ctx context.Context, fn func(ctx context.Context) error, opts ...Option)
 		}
 
 		lastErr = fn(ctx)
+		record.Attempts = attempt
 		if lastErr == nil {
 			return nil
 		}
@@ -210,16 +242,25 @@ func Do(ctx context.Context, fn func(ctx context.Context) error, opts ...Option)
 			actualDelay = time.Duration(float64(delay) + (rand.Float64()*2-1)*jitterRange)
 		}
 
+		// Record the retry on the span of the operation, if it is traced
+		attrs := []attribute.KeyValue{
+			attribute.Int("retry.attempt", attempt),
+			attribute.String("retry.error", lastErr.Error()),
+			attribute.String("retry.delay", actualDelay.String()),
+		}
+		if cfg.Name != "" {
+			attrs = append(attrs, attribute.String("retry.operation", cfg.Name))
+		}
+		trace.SpanFromContext(ctx).AddEvent("retry", trace.WithAttributes(attrs...))
+
 		// Call retry callback if configured
 		if cfg.OnRetry != nil {
 			cfg.OnRetry(attempt, lastErr, actualDelay)
 		}
 
-		// Wait before next attempt
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case <-time.After(actualDelay):
+		// Wait before next attempt, then for the shared budget to admit the retry
+		if err := wait(ctx, cfg.Budget, actualDelay, record); err != nil {
+			return err
 		}
 
 		// Calculate next delay with exponential backoff
@@ -232,6 +273,28 @@ func Do(ctx context.Context, fn func(ctx context.Context) error, opts ...Option)
 	return lastErr
 }
 
+// wait waits for the backoff delay and the budget, adding the time waited to the record
+func wait(ctx context.Context, budget *Budget, delay time.Duration, record *Record) error {
+	start := time.Now()
+	defer func() { record.Delay += time.Since(start) }()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(delay):
+	}
+	if budget != nil {
+		return budget.Wait(ctx)
+	}
+	return nil
+}
+
+// DoWithBudget executes the function with retries that take tokens from a budget
+// shared with other operations
+func DoWithBudget(ctx context.Context, b *Budget, fn func(ctx context.Context) error, opts ...Option) error {
+	return Do(ctx, fn, append([]Option{WithBudget(b)}, opts...)...)
+}
+
 // DoWithData executes the function with retries and returns a result
 func DoWithData[T any](ctx context.Context, fn func(ctx context.Context) (T, error), opts ...Option) (T, error) {
 	var result T
@@ -0,0 +1,56 @@
+package framework
+
+import (
+	"crypto/rand"
+	"fmt"
+	"regexp"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// runIDAlphabet contains the characters used in generated run IDs.
+// Only lowercase alphanumerics are used so IDs are valid in namespace names and label values.
+const runIDAlphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+// DefaultRunIDLength is the length of generated run IDs
+const DefaultRunIDLength = 6
+
+// MaxRunIDLength bounds run IDs so that namespace names stay within the 63 character limit
+const MaxRunIDLength = 16
+
+var runIDPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+// NewRunID returns a random run ID suitable for namespace names and label values
+func NewRunID() string {
+	buf := make([]byte, DefaultRunIDLength)
+	_, _ = rand.Read(buf) // never returns an error since Go 1.24
+	for i, b := range buf {
+		buf[i] = runIDAlphabet[int(b)%len(runIDAlphabet)]
+	}
+	return string(buf)
+}
+
+// ValidateRunID checks that a run ID can be used in namespace names and label values
+func ValidateRunID(runID string) error {
+	if runID == "" {
+		return fmt.Errorf("run ID must not be empty")
+	}
+	if len(runID) > MaxRunIDLength {
+		return fmt.Errorf("run ID %q is longer than %d characters", runID, MaxRunIDLength)
+	}
+	if !runIDPattern.MatchString(runID) {
+		return fmt.Errorf("run ID %q must consist of lowercase alphanumerics and '-', and start and end with an alphanumeric", runID)
+	}
+	return nil
+}
+
+// RunNamespace returns the namespace name for a profile within a run.
+// It returns an error if the name exceeds the 63 character limit of namespace names.
+func RunNamespace(profileName, runID string) (string, error) {
+	namespace := fmt.Sprintf("tempo-perf-%s-%s", profileName, runID)
+	if len(namespace) > validation.DNS1123LabelMaxLength {
+		return "", fmt.Errorf("namespace %q for profile %q and run ID %q is longer than %d characters",
+			namespace, profileName, runID, validation.DNS1123LabelMaxLength)
+	}
+	return namespace, nil
+}
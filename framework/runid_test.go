@@ -0,0 +1,98 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestNewRunID(t *testing.T) {
+	id := NewRunID()
+	if len(id) != DefaultRunIDLength {
+		t.Errorf("expected run ID of length %d, got %q", DefaultRunIDLength, id)
+	}
+	if err := ValidateRunID(id); err != nil {
+		t.Errorf("generated run ID should be valid: %v", err)
+	}
+	if NewRunID() == id {
+		t.Error("expected consecutive run IDs to differ")
+	}
+}
+
+func TestValidateRunID(t *testing.T) {
+	valid := []string{"abc123", "nightly-42", "a"}
+	for _, id := range valid {
+		if err := ValidateRunID(id); err != nil {
+			t.Errorf("expected %q to be valid, got %v", id, err)
+		}
+	}
+
+	invalid := []string{"", "Nightly", "run_1", "-abc", "abc-", strings.Repeat("a", MaxRunIDLength+1)}
+	for _, id := range invalid {
+		if err := ValidateRunID(id); err == nil {
+			t.Errorf("expected %q to be invalid", id)
+		}
+	}
+}
+
+func TestRunNamespace(t *testing.T) {
+	got, err := RunNamespace("small", "abc123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "tempo-perf-small-abc123" {
+		t.Errorf("unexpected namespace %q", got)
+	}
+}
+
+func TestRunNamespace_TooLong(t *testing.T) {
+	// tempo-perf- (11) + profile + - (1) + run ID (16) must not exceed 63 characters
+	if _, err := RunNamespace(strings.Repeat("p", 35), strings.Repeat("r", MaxRunIDLength)); err != nil {
+		t.Errorf("expected a 63 character namespace to be valid, got %v", err)
+	}
+	if _, err := RunNamespace(strings.Repeat("p", 36), strings.Repeat("r", MaxRunIDLength)); err == nil {
+		t.Error("expected error for a 64 character namespace")
+	}
+}
+
+func TestGetManagedLabels_RunID(t *testing.T) {
+	f := &Framework{namespace: "tempo-perf-small-abc123", runID: "abc123"}
+
+	labels := f.GetManagedLabels()
+	if labels[LabelRunID] != "abc123" {
+		t.Errorf("expected run ID label, got %v", labels)
+	}
+
+	f.runID = ""
+	if _, ok := f.GetManagedLabels()[LabelRunID]; ok {
+		t.Error("expected no run ID label when run ID is unset")
+	}
+}
+
+func TestCheckNamespaceAvailable(t *testing.T) {
+	client := fake.NewSimpleClientset(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "tempo-perf-small-abc123",
+			Labels: map[string]string{LabelRunID: "abc123"},
+		},
+	})
+
+	taken := &Framework{client: client, ctx: context.Background(), namespace: "tempo-perf-small-abc123"}
+	err := taken.CheckNamespaceAvailable()
+	if !errors.Is(err, ErrNamespaceInUse) {
+		t.Fatalf("expected ErrNamespaceInUse, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "abc123") {
+		t.Errorf("expected owning run ID in error, got %v", err)
+	}
+
+	free := &Framework{client: client, ctx: context.Background(), namespace: "tempo-perf-small-def456"}
+	if err := free.CheckNamespaceAvailable(); err != nil {
+		t.Errorf("expected namespace to be available, got %v", err)
+	}
+}
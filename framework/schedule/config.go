@@ -0,0 +1,119 @@
+package schedule
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/k6"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Config is a schedule file
+type Config struct {
+	// Timezone is the IANA time zone the cron expressions are evaluated in.
+	// Default: the local time zone
+	Timezone string `json:"timezone,omitempty"`
+
+	// Schedules are the scheduled runs
+	Schedules []Entry `json:"schedules"`
+
+	location *time.Location
+}
+
+// Entry runs a set of profiles on a cron schedule
+type Entry struct {
+	// Name identifies the entry in the status
+	Name string `json:"name"`
+
+	// Cron is a five-field cron expression or a macro like @daily (see ParseCron)
+	Cron string `json:"cron"`
+
+	// Profiles are the names of the profiles to run, in order
+	Profiles []string `json:"profiles"`
+
+	// TestType is the test type of the profiles without phases. Default: combined
+	TestType string `json:"testType,omitempty"`
+
+	cron *Cron
+}
+
+// Load reads and validates a schedule file
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schedule: %w", err)
+	}
+
+	var config Config
+	if err := yaml.UnmarshalStrict(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse schedule %s: %w", path, err)
+	}
+	if err := config.validate(); err != nil {
+		return nil, fmt.Errorf("schedule %s: %w", path, err)
+	}
+	return &config, nil
+}
+
+// validate checks the entries and parses their cron expressions
+func (c *Config) validate() error {
+	c.location = time.Local
+	if c.Timezone != "" {
+		loc, err := time.LoadLocation(c.Timezone)
+		if err != nil {
+			return fmt.Errorf("invalid timezone %q: %w", c.Timezone, err)
+		}
+		c.location = loc
+	}
+	if len(c.Schedules) == 0 {
+		return fmt.Errorf("no schedules defined")
+	}
+
+	seen := make(map[string]bool)
+	for i := range c.Schedules {
+		e := &c.Schedules[i]
+		if e.Name == "" {
+			return fmt.Errorf("schedules[%d].name is required", i)
+		}
+		if seen[e.Name] {
+			return fmt.Errorf("duplicate schedule name %q", e.Name)
+		}
+		seen[e.Name] = true
+		if len(e.Profiles) == 0 {
+			return fmt.Errorf("schedule %s: profiles are required", e.Name)
+		}
+
+		cron, err := ParseCron(e.Cron)
+		if err != nil {
+			return fmt.Errorf("schedule %s: %w", e.Name, err)
+		}
+		if cron.Next(time.Now().In(c.location)).IsZero() {
+			return fmt.Errorf("schedule %s: cron expression %q never fires", e.Name, e.Cron)
+		}
+		e.cron = cron
+
+		if e.TestType == "" {
+			e.TestType = string(k6.TestCombined)
+		}
+		switch k6.TestType(e.TestType) {
+		case k6.TestIngestion, k6.TestQuery, k6.TestCombined, k6.TestJaeger, k6.TestReplay:
+		default:
+			return fmt.Errorf("schedule %s: invalid test type %q (must be ingestion, query, combined, jaeger, or replay)", e.Name, e.TestType)
+		}
+	}
+	return nil
+}
+
+// Location returns the time zone of the schedule
+func (c *Config) Location() *time.Location {
+	if c.location == nil {
+		return time.Local
+	}
+	return c.location
+}
+
+// Next returns the first time after t the entry fires, in the time zone of the schedule
+func (c *Config) Next(e *Entry, t time.Time) time.Time {
+	return e.cron.Next(t.In(c.Location()))
+}
@@ -0,0 +1,73 @@
+package schedule
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeSchedule writes a schedule file and returns its path
+func writeSchedule(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "schedule.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	path := writeSchedule(t, `timezone: UTC
+schedules:
+  - name: nightly
+    cron: "0 2 * * *"
+    profiles: [small, medium]
+  - name: weekly-soak
+    cron: "@weekly"
+    profiles: [soak]
+    testType: ingestion
+`)
+	config, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(config.Schedules) != 2 {
+		t.Fatalf("got %d schedules, want 2", len(config.Schedules))
+	}
+	nightly := &config.Schedules[0]
+	if nightly.TestType != "combined" {
+		t.Errorf("default test type = %q, want combined", nightly.TestType)
+	}
+	from := time.Date(2026, 3, 4, 10, 0, 0, 0, time.FixedZone("UTC+2", 2*60*60))
+	if got, want := config.Next(nightly, from), time.Date(2026, 3, 5, 2, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("Next() = %v, want %v in the schedule time zone", got, want)
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"empty", "schedules: []\n", "no schedules"},
+		{"unknown field", "schedules:\n  - name: a\n    cron: '@daily'\n    profiles: [small]\n    profile: small\n", "unknown field"},
+		{"no name", "schedules:\n  - cron: '@daily'\n    profiles: [small]\n", "name is required"},
+		{"duplicate", "schedules:\n  - name: a\n    cron: '@daily'\n    profiles: [small]\n  - name: a\n    cron: '@hourly'\n    profiles: [small]\n", "duplicate"},
+		{"no profiles", "schedules:\n  - name: a\n    cron: '@daily'\n", "profiles are required"},
+		{"bad cron", "schedules:\n  - name: a\n    cron: '0 2 * *'\n    profiles: [small]\n", "5 fields"},
+		{"never", "schedules:\n  - name: a\n    cron: '0 0 31 2 *'\n    profiles: [small]\n", "never fires"},
+		{"test type", "schedules:\n  - name: a\n    cron: '@daily'\n    profiles: [small]\n    testType: soak\n", "invalid test type"},
+		{"timezone", "timezone: Mars/Olympus\nschedules:\n  - name: a\n    cron: '@daily'\n    profiles: [small]\n", "invalid timezone"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(writeSchedule(t, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Load() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"@yearly",
+	} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("ParseCron(%q) expected an error", expr)
+		}
+	}
+}
+
+func TestCron_Next(t *testing.T) {
+	// Wednesday
+	from := time.Date(2026, 3, 4, 10, 30, 15, 0, time.UTC)
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, 3, 4, 10, 31, 0, 0, time.UTC)},
+		{"0 2 * * *", time.Date(2026, 3, 5, 2, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, 3, 4, 11, 0, 0, 0, time.UTC)},
+		{"*/20 * * * *", time.Date(2026, 3, 4, 10, 40, 0, 0, time.UTC)},
+		{"15,45 9-17 * * *", time.Date(2026, 3, 4, 10, 45, 0, 0, time.UTC)},
+		{"10/30 * * * *", time.Date(2026, 3, 4, 10, 40, 0, 0, time.UTC)},
+		// Saturday
+		{"0 3 * * 6", time.Date(2026, 3, 7, 3, 0, 0, 0, time.UTC)},
+		// Sunday as 7
+		{"0 3 * * 7", time.Date(2026, 3, 8, 3, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)},
+		// Both days restricted: the 10th or a Monday, whichever comes first
+		{"0 0 10 * 1", time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		c, err := ParseCron(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseCron(%q) error = %v", tt.expr, err)
+		}
+		if got := c.Next(from); !got.Equal(tt.want) {
+			t.Errorf("ParseCron(%q).Next() = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestCron_NextNever(t *testing.T) {
+	c, err := ParseCron("0 0 30 2 *")
+	if err != nil {
+		t.Fatalf("ParseCron() error = %v", err)
+	}
+	if got := c.Next(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)); !got.IsZero() {
+		t.Errorf("Next() = %v, want the zero time for February 30", got)
+	}
+}
+
+func TestCron_NextLocation(t *testing.T) {
+	loc := time.FixedZone("UTC+2", 2*60*60)
+	c, err := ParseCron("0 2 * * *")
+	if err != nil {
+		t.Fatalf("ParseCron() error = %v", err)
+	}
+	got := c.Next(time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC).In(loc))
+	if want := time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("Next() = %v, want %v (02:00 in UTC+2)", got, want)
+	}
+}
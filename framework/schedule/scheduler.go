@@ -0,0 +1,54 @@
+package schedule
+
+import (
+	"context"
+	"time"
+)
+
+// Run fires the entries of config until ctx is done, calling run for each firing
+// entry. Runs never overlap: an entry that fires while another runs starts once the
+// run ends, and firings missed during a run are coalesced into that one run.
+func Run(ctx context.Context, config *Config, tracker *Tracker, run func(ctx context.Context, e *Entry)) {
+	now := time.Now()
+	next := make(map[string]time.Time, len(config.Schedules))
+	for i := range config.Schedules {
+		e := &config.Schedules[i]
+		next[e.Name] = config.Next(e, now)
+		tracker.SetNext(e.Name, next[e.Name])
+	}
+
+	for {
+		e := earliest(config, next)
+		if wait := time.Until(next[e.Name]); wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		run(ctx, e)
+		if ctx.Err() != nil {
+			return
+		}
+		next[e.Name] = config.Next(e, time.Now())
+		tracker.SetNext(e.Name, next[e.Name])
+	}
+}
+
+// earliest returns the entry that fires first, the first in the file on ties
+func earliest(config *Config, next map[string]time.Time) *Entry {
+	var first *Entry
+	for i := range config.Schedules {
+		e := &config.Schedules[i]
+		if first == nil || next[e.Name].Before(next[first.Name]) {
+			first = e
+		}
+	}
+	return first
+}
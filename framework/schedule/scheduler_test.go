@@ -0,0 +1,19 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEarliest(t *testing.T) {
+	config := testConfig(t)
+	at := time.Date(2026, 3, 5, 2, 0, 0, 0, time.UTC)
+	next := map[string]time.Time{"nightly": at, "hourly": at}
+	if e := earliest(config, next); e.Name != "nightly" {
+		t.Errorf("earliest() = %s, want the first entry on ties", e.Name)
+	}
+	next["hourly"] = at.Add(-time.Hour)
+	if e := earliest(config, next); e.Name != "hourly" {
+		t.Errorf("earliest() = %s, want hourly", e.Name)
+	}
+}
@@ -0,0 +1,263 @@
+package schedule
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/metrics"
+)
+
+// StatusFile is the file in the results directory the Tracker persists the latest
+// run of each entry to
+const StatusFile = "schedule-status.json"
+
+// defaultRunsLimit is the number of runs /runs returns without a limit parameter
+const defaultRunsLimit = 50
+
+// Status is the state of the scheduler
+type Status struct {
+	StartedAt time.Time     `json:"started_at"`
+	Schedules []EntryStatus `json:"schedules"`
+}
+
+// EntryStatus is the state of a schedule entry
+type EntryStatus struct {
+	Name     string     `json:"name"`
+	Cron     string     `json:"cron"`
+	Profiles []string   `json:"profiles"`
+	NextRun  *time.Time `json:"next_run,omitempty"`
+	// Running is the run in progress, with the profiles finished so far
+	Running *RunStatus `json:"running,omitempty"`
+	// LastRun is the latest finished run
+	LastRun *RunStatus `json:"last_run,omitempty"`
+}
+
+// RunStatus is the outcome of a scheduled run
+type RunStatus struct {
+	RunID      string          `json:"run_id"`
+	StartedAt  time.Time       `json:"started_at"`
+	FinishedAt *time.Time      `json:"finished_at,omitempty"`
+	Success    bool            `json:"success"`
+	Error      string          `json:"error,omitempty"`
+	Profiles   []ProfileStatus `json:"profiles,omitempty"`
+}
+
+// ProfileStatus is the outcome of a profile of a scheduled run
+type ProfileStatus struct {
+	Name        string        `json:"name"`
+	Success     bool          `json:"success"`
+	SLOViolated bool          `json:"slo_violated,omitempty"`
+	Error       string        `json:"error,omitempty"`
+	Duration    time.Duration `json:"duration"`
+	// MetricsPath and ReportPath are the metrics CSV and Markdown summary of the run
+	MetricsPath string `json:"metrics_path,omitempty"`
+	ReportPath  string `json:"report_path,omitempty"`
+	// TimedOutPhase is the stage of the run that exceeded the profile timeout
+	TimedOutPhase string `json:"timed_out_phase,omitempty"`
+}
+
+// Tracker records the next, running and latest runs of the entries of a schedule.
+// The latest runs are persisted, so a restarted scheduler reports them.
+type Tracker struct {
+	mu      sync.Mutex
+	path    string
+	started time.Time
+	entries []*EntryStatus
+}
+
+// NewTracker returns a Tracker of the entries of config that persists to path,
+// loading the latest runs persisted there by an earlier scheduler
+func NewTracker(config *Config, path string) (*Tracker, error) {
+	t := &Tracker{path: path, started: time.Now()}
+	for _, e := range config.Schedules {
+		t.entries = append(t.entries, &EntryStatus{Name: e.Name, Cron: e.Cron, Profiles: e.Profiles})
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return t, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schedule status: %w", err)
+	}
+	var lastRuns map[string]*RunStatus
+	if err := json.Unmarshal(data, &lastRuns); err != nil {
+		return nil, fmt.Errorf("failed to parse schedule status %s: %w", path, err)
+	}
+	// Entries removed from the schedule are dropped
+	for _, e := range t.entries {
+		e.LastRun = lastRuns[e.Name]
+	}
+	return t, nil
+}
+
+// entry returns the status of the entry name, or nil
+func (t *Tracker) entry(name string) *EntryStatus {
+	for _, e := range t.entries {
+		if e.Name == name {
+			return e
+		}
+	}
+	return nil
+}
+
+// SetNext records the next time entry name runs
+func (t *Tracker) SetNext(name string, next time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if e := t.entry(name); e != nil {
+		e.NextRun = &next
+	}
+}
+
+// Start records that entry name started the run runID
+func (t *Tracker) Start(name, runID string, at time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if e := t.entry(name); e != nil {
+		e.NextRun = nil
+		e.Running = &RunStatus{RunID: runID, StartedAt: at}
+	}
+}
+
+// ProfileDone records a finished profile of the running run of entry name
+func (t *Tracker) ProfileDone(name string, profile ProfileStatus) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if e := t.entry(name); e != nil && e.Running != nil {
+		e.Running.Profiles = append(e.Running.Profiles, profile)
+	}
+}
+
+// Finish records the end of the running run of entry name, failed if runErr is set
+// or any profile failed, and persists the latest runs
+func (t *Tracker) Finish(name string, at time.Time, runErr error) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	e := t.entry(name)
+	if e == nil || e.Running == nil {
+		return nil
+	}
+	run := e.Running
+	run.FinishedAt = &at
+	run.Success = runErr == nil && len(run.Profiles) > 0
+	if runErr != nil {
+		run.Error = runErr.Error()
+	}
+	for _, p := range run.Profiles {
+		if !p.Success {
+			run.Success = false
+		}
+	}
+	e.Running, e.LastRun = nil, run
+	return t.save()
+}
+
+// save writes the latest runs to the status file, replacing it atomically
+func (t *Tracker) save() error {
+	lastRuns := make(map[string]*RunStatus)
+	for _, e := range t.entries {
+		if e.LastRun != nil {
+			lastRuns[e.Name] = e.LastRun
+		}
+	}
+	data, err := json.MarshalIndent(lastRuns, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode schedule status: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(t.path), 0755); err != nil {
+		return fmt.Errorf("failed to create schedule status directory: %w", err)
+	}
+	tmp := t.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write schedule status: %w", err)
+	}
+	if err := os.Rename(tmp, t.path); err != nil {
+		return fmt.Errorf("failed to write schedule status: %w", err)
+	}
+	return nil
+}
+
+// Status returns a copy of the state of the scheduler
+func (t *Tracker) Status() Status {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	status := Status{StartedAt: t.started}
+	for _, e := range t.entries {
+		entry := *e
+		if e.Running != nil {
+			running := *e.Running
+			running.Profiles = append([]ProfileStatus(nil), e.Running.Profiles...)
+			entry.Running = &running
+		}
+		status.Schedules = append(status.Schedules, entry)
+	}
+	return status
+}
+
+// Handler serves the status of the scheduler:
+//
+//	GET /status   the next, running and latest run of each entry
+//	GET /runs     the run metadata in resultsDir, newest first (?profile=, ?limit=)
+//	GET /healthz  ok while the scheduler is up
+func (t *Tracker) Handler(resultsDir string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, t.Status())
+	})
+	mux.HandleFunc("/runs", func(w http.ResponseWriter, r *http.Request) {
+		limit := defaultRunsLimit
+		if s := r.URL.Query().Get("limit"); s != "" {
+			n, err := strconv.Atoi(s)
+			if err != nil || n <= 0 {
+				http.Error(w, "invalid limit", http.StatusBadRequest)
+				return
+			}
+			limit = n
+		}
+		runs, err := latestRuns(resultsDir, r.URL.Query().Get("profile"), limit)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, runs)
+	})
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "ok")
+	})
+	return mux
+}
+
+// latestRuns returns up to limit runs in dir, of profile if set, newest first
+func latestRuns(dir, profile string, limit int) ([]*metrics.RunMetadata, error) {
+	all, err := metrics.ListRuns(dir)
+	if err != nil {
+		return nil, err
+	}
+	runs := make([]*metrics.RunMetadata, 0, len(all))
+	for _, r := range all {
+		if profile == "" || r.Profile == profile {
+			runs = append(runs, r)
+		}
+	}
+	sort.Slice(runs, func(i, j int) bool { return runs[i].StartedAt.After(runs[j].StartedAt) })
+	if len(runs) > limit {
+		runs = runs[:limit]
+	}
+	return runs, nil
+}
+
+// writeJSON writes v as an indented JSON response
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
@@ -0,0 +1,119 @@
+package schedule
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/metrics"
+)
+
+func testConfig(t *testing.T) *Config {
+	t.Helper()
+	config := &Config{Timezone: "UTC", Schedules: []Entry{
+		{Name: "nightly", Cron: "0 2 * * *", Profiles: []string{"small", "medium"}},
+		{Name: "hourly", Cron: "@hourly", Profiles: []string{"small"}},
+	}}
+	if err := config.validate(); err != nil {
+		t.Fatalf("validate() error = %v", err)
+	}
+	return config
+}
+
+func TestTracker_Persist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), StatusFile)
+	config := testConfig(t)
+	tracker, err := NewTracker(config, path)
+	if err != nil {
+		t.Fatalf("NewTracker() error = %v", err)
+	}
+
+	start := time.Date(2026, 3, 5, 2, 0, 0, 0, time.UTC)
+	tracker.SetNext("nightly", start)
+	tracker.Start("nightly", "abc123", start)
+	tracker.ProfileDone("nightly", ProfileStatus{Name: "small", Success: true, Duration: time.Hour})
+
+	status := tracker.Status()
+	if status.Schedules[0].Running == nil || len(status.Schedules[0].Running.Profiles) != 1 || status.Schedules[0].NextRun != nil {
+		t.Errorf("running status = %+v", status.Schedules[0])
+	}
+
+	tracker.ProfileDone("nightly", ProfileStatus{Name: "medium", Error: "deploy failed"})
+	if err := tracker.Finish("nightly", start.Add(2*time.Hour), nil); err != nil {
+		t.Fatalf("Finish() error = %v", err)
+	}
+
+	// A restarted scheduler reports the latest run
+	restarted, err := NewTracker(config, path)
+	if err != nil {
+		t.Fatalf("NewTracker() error = %v", err)
+	}
+	last := restarted.Status().Schedules[0].LastRun
+	if last == nil || last.RunID != "abc123" || last.Success || len(last.Profiles) != 2 {
+		t.Fatalf("persisted last run = %+v, want the failed run abc123", last)
+	}
+	if restarted.Status().Schedules[1].LastRun != nil {
+		t.Error("expected no last run of the hourly entry")
+	}
+}
+
+func TestTracker_FinishError(t *testing.T) {
+	tracker, err := NewTracker(testConfig(t), filepath.Join(t.TempDir(), StatusFile))
+	if err != nil {
+		t.Fatalf("NewTracker() error = %v", err)
+	}
+	tracker.Start("hourly", "run1", time.Now())
+	if err := tracker.Finish("hourly", time.Now(), errors.New("failed to load profiles")); err != nil {
+		t.Fatalf("Finish() error = %v", err)
+	}
+	last := tracker.Status().Schedules[1].LastRun
+	if last == nil || last.Success || last.Error != "failed to load profiles" {
+		t.Errorf("last run = %+v, want the failure", last)
+	}
+}
+
+func TestTracker_Handler(t *testing.T) {
+	dir := t.TempDir()
+	for i, profile := range []string{"small", "medium", "small"} {
+		meta := &metrics.RunMetadata{
+			RunID:     "run" + string(rune('a'+i)),
+			Profile:   profile,
+			StartedAt: time.Date(2026, 3, 1+i, 2, 0, 0, 0, time.UTC),
+		}
+		if err := metrics.WriteRunMetadata(meta, filepath.Join(dir, profile+"-"+meta.RunID+metrics.RunMetadataSuffix)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tracker, err := NewTracker(testConfig(t), filepath.Join(dir, StatusFile))
+	if err != nil {
+		t.Fatalf("NewTracker() error = %v", err)
+	}
+	handler := tracker.Handler(dir)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/status", nil))
+	var status Status
+	if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil || len(status.Schedules) != 2 {
+		t.Errorf("/status = %s (%v)", rec.Body.String(), err)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/runs?profile=small&limit=1", nil))
+	var runs []metrics.RunMetadata
+	if err := json.Unmarshal(rec.Body.Bytes(), &runs); err != nil {
+		t.Fatalf("/runs = %s (%v)", rec.Body.String(), err)
+	}
+	if len(runs) != 1 || runs[0].RunID != "runc" {
+		t.Errorf("/runs = %+v, want the newest small run", runs)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/runs?limit=x", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("/runs?limit=x status = %d, want 400", rec.Code)
+	}
+}
@@ -0,0 +1,68 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/metrics"
+	"github.com/redhat/perf-tests-tempo/test/framework/upload"
+)
+
+// StartStorageSampler starts measuring the bytes and objects in the trace storage of
+// Tempo: the buckets of the MinIO deployed by SetupMinIO, or the bucket of an "s3"
+// storage. CollectMetrics exports the samples as the storage footprint series. A
+// nil storage is the default MinIO. A non-positive interval uses
+// metrics.DefaultStorageSamplingInterval.
+func (f *Framework) StartStorageSampler(interval time.Duration, storage *StorageConfig) error {
+	source, err := f.storageSource(storage)
+	if err != nil {
+		return err
+	}
+	sampler := metrics.NewStorageSampler(source, interval)
+	if err := sampler.Start(f.ctx); err != nil {
+		return fmt.Errorf("failed to measure the trace storage: %w", err)
+	}
+	f.storageSampler = sampler
+	return nil
+}
+
+// StorageSampler returns the sampler started with StartStorageSampler, or nil
+func (f *Framework) StorageSampler() *metrics.StorageSampler {
+	return f.storageSampler
+}
+
+// storageSource returns the source measuring a trace storage
+func (f *Framework) storageSource(storage *StorageConfig) (metrics.StorageSource, error) {
+	if storage == nil || storage.Type == "" || storage.Type == "minio" {
+		return metrics.NewMinIOStorageSource(f.client, f.namespace), nil
+	}
+	if storage.Type != "s3" {
+		return nil, fmt.Errorf("%s storage is not object storage", storage.Type)
+	}
+	uploader, err := upload.New(&upload.Target{Scheme: "s3", Bucket: storage.Bucket}, &upload.Config{
+		Endpoint:        storage.Endpoint,
+		Region:          storage.Region,
+		AccessKeyID:     storage.AccessKeyID,
+		SecretAccessKey: storage.SecretAccessKey,
+		Insecure:        storage.Insecure,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid S3 storage: %w", err)
+	}
+	return &s3StorageSource{uploader: uploader}, nil
+}
+
+// s3StorageSource measures an S3 bucket by listing its objects
+type s3StorageSource struct {
+	uploader *upload.Uploader
+}
+
+// StorageUsage returns the size and count of the objects in the bucket
+func (s *s3StorageSource) StorageUsage(ctx context.Context) (*metrics.StorageUsage, error) {
+	usage, err := s.uploader.Usage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &metrics.StorageUsage{Bytes: float64(usage.Bytes), Objects: float64(usage.Objects)}, nil
+}
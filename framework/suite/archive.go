@@ -0,0 +1,79 @@
+package suite
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// archiveDir writes the regular files of dir to a gzipped tarball at archivePath,
+// with paths relative to the parent of dir so the archive unpacks into one directory
+func archiveDir(dir, archivePath string) (err error) {
+	out, err := os.Create(archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+	defer func() {
+		if closeErr := out.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("failed to write archive: %w", closeErr)
+		}
+	}()
+
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+	base := filepath.Dir(dir)
+
+	walkErr := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		return addToArchive(tw, base, path)
+	})
+	if walkErr != nil {
+		return fmt.Errorf("failed to archive %s: %w", dir, walkErr)
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	return nil
+}
+
+// addToArchive adds one file to the archive, named relative to base
+func addToArchive(tw *tar.Writer, base, path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	name, err := filepath.Rel(base, path)
+	if err != nil {
+		return err
+	}
+
+	header, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	header.Name = filepath.ToSlash(name)
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(tw, f)
+	return err
+}
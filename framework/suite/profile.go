@@ -0,0 +1,408 @@
+package suite
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/redhat/perf-tests-tempo/test/framework"
+	"github.com/redhat/perf-tests-tempo/test/framework/k6"
+	"github.com/redhat/perf-tests-tempo/test/framework/metrics"
+	"github.com/redhat/perf-tests-tempo/test/framework/profile"
+)
+
+// ResourceConfig converts the Tempo settings of a profile to the deployment
+// configuration of SetupTempo. It returns nil when the profile sets nothing, so
+// the operator defaults apply.
+func ResourceConfig(p *profile.Profile, nodeSelector map[string]string) *framework.ResourceConfig {
+	config := &framework.ResourceConfig{}
+	hasConfig := false
+
+	// Add resources if specified
+	if p.Tempo.HasResources() {
+		config.Resources = resourceRequirements(p.Tempo.Resources)
+		hasConfig = true
+	}
+
+	// Add replication factor if specified (only applies to TempoStack)
+	if p.Tempo.ReplicationFactor != nil {
+		config.ReplicationFactor = p.Tempo.ReplicationFactor
+		hasConfig = true
+	}
+
+	// Get max traces per user from env var (takes precedence) or profile
+	maxTracesPerUser := MaxTracesPerUser(p)
+	ingester := ingesterConfig(p)
+
+	if maxTracesPerUser != nil || ingester != nil {
+		config.Overrides = &framework.TempoOverrides{
+			MaxTracesPerUser: maxTracesPerUser,
+			Ingester:         ingester,
+		}
+		hasConfig = true
+	}
+
+	// Add autoscaling if specified (only applies to TempoStack)
+	if a := p.Tempo.Autoscaling; a != nil {
+		config.Autoscaling = &framework.AutoscalingConfig{
+			Components:              a.Components,
+			MinReplicas:             a.MinReplicas,
+			MaxReplicas:             a.MaxReplicas,
+			TargetCPUUtilization:    a.TargetCPUUtilization,
+			TargetMemoryUtilization: a.TargetMemoryUtilization,
+		}
+		hasConfig = true
+	}
+
+	// Store traces on a persistent volume instead of MinIO (only applies to monolithic)
+	if p.Storage.UsesPV() {
+		config.Storage = &framework.StorageConfig{
+			Type:             "pv",
+			Size:             p.Storage.Size,
+			StorageClassName: p.Storage.StorageClass,
+		}
+		hasConfig = true
+	}
+
+	// Add Tempo container environment variables if specified
+	if len(p.Tempo.Env) > 0 {
+		config.Env = p.Tempo.Env
+		hasConfig = true
+	}
+
+	// Add raw Tempo configuration if specified
+	if len(p.Tempo.ExtraConfig) > 0 {
+		config.ExtraConfig = p.Tempo.ExtraConfig
+		hasConfig = true
+	}
+
+	// Enable the metrics-generator if specified (only applies to monolithic)
+	if g := p.Tempo.MetricsGenerator; g != nil {
+		config.MetricsGenerator = &framework.MetricsGeneratorConfig{
+			Processors:     g.Processors,
+			RemoteWriteURL: g.RemoteWriteURL,
+		}
+		hasConfig = true
+	}
+
+	// Set the block format if specified
+	if b := p.Tempo.Block; b != nil {
+		config.Block = &framework.BlockConfig{
+			Version:                   b.Version,
+			Encoding:                  b.Encoding,
+			BloomFilterFalsePositive:  b.BloomFilterFalsePositive,
+			BloomFilterShardSizeBytes: b.BloomFilterShardSizeBytes,
+		}
+		hasConfig = true
+	}
+
+	// Run several TempoMonolithic replicas if specified (only applies to monolithic)
+	if p.Tempo.Replicas > 1 {
+		config.Replicas = p.Tempo.Replicas
+		hasConfig = true
+	}
+
+	// Add ingestion authentication if specified
+	if p.Tempo.IngestionAuth != "" {
+		config.IngestionAuth = p.Tempo.IngestionAuth
+		hasConfig = true
+	}
+
+	// Export to Tempo with the ingestion protocol if specified
+	if p.K6.Ingestion.Protocol != "" {
+		config.IngestionProtocol = p.K6.Ingestion.Protocol
+		hasConfig = true
+	}
+
+	// Route the ingestion through Kafka if specified
+	if k := p.Kafka; k != nil {
+		config.Kafka = &framework.KafkaConfig{
+			Brokers:    k.Brokers,
+			Topic:      k.Topic,
+			Partitions: k.Partitions,
+		}
+		if r := k.Resources; r != nil && r.Memory != "" && r.CPU != "" {
+			config.Kafka.Resources = resourceRequirements(r)
+		}
+		hasConfig = true
+	}
+
+	// Add the aggressor tenant of the noisy-neighbor scenario
+	if p.TenantIsolation != nil {
+		config.Tenants = []string{k6.DefaultTenant, AggressorTenant}
+		hasConfig = true
+	}
+
+	// Pin the Tempo and collector images if specified
+	if p.Images != nil && (p.Images.Tempo != "" || p.Images.Collector != "") {
+		config.Image = p.Images.Tempo
+		config.CollectorImage = p.Images.Collector
+		hasConfig = true
+	}
+
+	// Add node selector if specified
+	if len(nodeSelector) > 0 {
+		config.NodeSelector = nodeSelector
+		hasConfig = true
+	}
+
+	if !hasConfig {
+		return nil // Use operator defaults
+	}
+	return config
+}
+
+// MaxTracesPerUser returns the max traces per user setting from the
+// MAX_TRACES_PER_USER env var or the profile
+func MaxTracesPerUser(p *profile.Profile) *int {
+	// Environment variable takes precedence
+	if envVal := os.Getenv("MAX_TRACES_PER_USER"); envVal != "" {
+		var val int
+		if _, err := fmt.Sscanf(envVal, "%d", &val); err == nil {
+			return &val
+		}
+	}
+
+	// Fall back to profile setting
+	if p.Tempo.Overrides != nil && p.Tempo.Overrides.MaxTracesPerUser != nil {
+		return p.Tempo.Overrides.MaxTracesPerUser
+	}
+
+	return nil
+}
+
+// Timeout returns the deadline of a run of the profile: its timeout, or fallback
+// if it sets none
+func Timeout(p *profile.Profile, fallback time.Duration) time.Duration {
+	if p.Timeout == "" {
+		return fallback
+	}
+	timeout, _ := time.ParseDuration(p.Timeout) // validated by the profile loader
+	return timeout
+}
+
+// ingesterConfig returns the ingester tuning config from the profile
+func ingesterConfig(p *profile.Profile) *framework.IngesterConfig {
+	if p.Tempo.Overrides == nil || p.Tempo.Overrides.Ingester == nil {
+		return nil
+	}
+
+	ing := p.Tempo.Overrides.Ingester
+	// Only return config if at least one field is set
+	if ing.FlushCheckPeriod == "" && ing.TraceIdlePeriod == "" &&
+		ing.MaxBlockDuration == "" && ing.ConcurrentFlushes == nil {
+		return nil
+	}
+
+	return &framework.IngesterConfig{
+		FlushCheckPeriod:  ing.FlushCheckPeriod,
+		TraceIdlePeriod:   ing.TraceIdlePeriod,
+		MaxBlockDuration:  ing.MaxBlockDuration,
+		ConcurrentFlushes: ing.ConcurrentFlushes,
+	}
+}
+
+// MinIOConfig returns the MinIO configuration of a profile, nil for the defaults
+func MinIOConfig(p *profile.Profile) *framework.MinIOConfig {
+	if !p.Storage.HasMinioSettings() {
+		return nil
+	}
+	config := &framework.MinIOConfig{
+		StorageSize:  p.Storage.MinioSize,
+		Replicas:     p.Storage.MinioReplicas,
+		StorageClass: p.Storage.MinioStorageClass,
+	}
+	if r := p.Storage.MinioResources; r != nil && r.Memory != "" && r.CPU != "" {
+		config.Resources = resourceRequirements(r)
+	}
+	return config
+}
+
+// NamespaceQuota returns the namespace quota of a profile, nil if it sets none
+func NamespaceQuota(p *profile.Profile) *framework.NamespaceQuota {
+	q := p.Quota
+	if q == nil {
+		return nil
+	}
+	quota := &framework.NamespaceQuota{
+		CPU:    resource.MustParse(q.CPU),
+		Memory: resource.MustParse(q.Memory),
+		Pods:   q.Pods,
+	}
+	if d := q.DefaultContainer; d != nil {
+		quota.DefaultLimits = resourceRequirements(d).Limits
+	}
+	return quota
+}
+
+// resourceRequirements returns the limits and requests of a resource spec
+func resourceRequirements(spec *profile.ResourceSpec) *corev1.ResourceRequirements {
+	resources := corev1.ResourceList{
+		corev1.ResourceMemory: resource.MustParse(spec.Memory),
+		corev1.ResourceCPU:    resource.MustParse(spec.CPU),
+	}
+	return &corev1.ResourceRequirements{
+		Limits:   resources,
+		Requests: resources.DeepCopy(),
+	}
+}
+
+// K6Config returns the k6 configuration of a profile. The load duration comes from
+// the DURATION env var, default 5m.
+func K6Config(p *profile.Profile) *k6.Config {
+	// Get duration from DURATION env var, default to 5m
+	duration := os.Getenv("DURATION")
+	if duration == "" {
+		duration = "5m"
+	}
+
+	config := &k6.Config{
+		TempoVariant:     k6.TempoVariant(p.Tempo.Variant),
+		MBPerSecond:      p.K6.Ingestion.MBPerSecond,
+		QueriesPerSecond: p.K6.Query.QueriesPerSecond,
+		Duration:         duration,
+		VUsMin:           p.K6.VUs.Min,
+		VUsMax:           p.K6.VUs.Max,
+		TraceProfile:     p.K6.Ingestion.TraceProfile,
+		Protocol:         k6.Protocol(p.K6.Ingestion.Protocol),
+		NoGateway:        !p.Tempo.UsesGateway(),
+	}
+	if r := p.K6.Replay; r != nil {
+		config.ReplayFile = r.File
+		config.ReplayPVC = r.PVC
+		config.ReplayPath = r.Path
+		config.ReplaySpeedup = r.Speedup
+	}
+	config.QueryCorpusFile = p.K6.Query.CorpusFile()
+	if p.Images != nil {
+		config.Image = p.Images.K6
+	}
+	if p.K6.Pod != nil {
+		config.Pod = k6PodConfig(p.K6.Pod)
+	}
+	return config
+}
+
+// AggressorTenant is the tenant of the aggressor load of the noisy-neighbor
+// scenario; the victim is k6.DefaultTenant
+const AggressorTenant = "tenant-2"
+
+// AggressorK6Config returns the k6 configuration of the aggressor of the
+// noisy-neighbor scenario from the configuration of the victim, victim. The
+// aggressor sends its traces to the collector of its tenant at endpoint.
+func AggressorK6Config(p *profile.Profile, victim *k6.Config, endpoint string) *k6.Config {
+	aggressor := p.TenantIsolation.Aggressor
+	config := *victim
+	config.TempoTenant = AggressorTenant
+	config.TempoEndpoint = endpoint
+	// The query endpoint holds the tenant; leave it to the defaults of the tenant
+	config.TempoQueryEndpoint = ""
+	config.MBPerSecond = aggressor.MBPerSecond
+	config.QueriesPerSecond = aggressor.QueriesPerSecond
+	config.Duration = p.TenantIsolation.Burst
+	if aggressor.VUs > 0 {
+		config.VUsMax = aggressor.VUs
+		config.VUsMin = min(config.VUsMin, aggressor.VUs)
+	}
+	// A query corpus is sampled from the traces of the victim
+	config.QueryCorpusFile = ""
+	config.BeforeQuery = nil
+	return &config
+}
+
+// k6PodConfig converts the k6 pod overrides of a profile
+func k6PodConfig(pod *profile.K6PodConfig) *k6.PodConfig {
+	config := &k6.PodConfig{NodeSelector: pod.NodeSelector}
+	if pod.Resources != nil {
+		config.Resources = resourceRequirements(pod.Resources)
+	}
+	for _, t := range pod.Tolerations {
+		config.Tolerations = append(config.Tolerations, corev1.Toleration{
+			Key:               t.Key,
+			Operator:          corev1.TolerationOperator(t.Operator),
+			Value:             t.Value,
+			Effect:            corev1.TaintEffect(t.Effect),
+			TolerationSeconds: t.TolerationSeconds,
+		})
+	}
+	config.Affinity, _ = pod.KubernetesAffinity() // validated by the profile loader
+	return config
+}
+
+// ManifestConfig returns the deployment and k6 loads of a run of a profile for
+// RenderManifests: the phases of the profile, the noisy-neighbor phases, or
+// testType. Combined loads run as parallel ingestion and query Jobs, like in the
+// runner.
+func ManifestConfig(p *profile.Profile, testType k6.TestType, nodeSelector map[string]string) *framework.ManifestConfig {
+	config := &framework.ManifestConfig{
+		Variant:   p.Tempo.Variant,
+		Resources: ResourceConfig(p, nodeSelector),
+		MinIO:     MinIOConfig(p),
+		SkipMinIO: p.Storage.UsesPV(),
+	}
+
+	k6Config := K6Config(p)
+	k6Config.PrometheusRWURL = k6.GetPrometheusRemoteWriteURL()
+	if p.TenantIsolation != nil {
+		config.TenantCollectors = []string{AggressorTenant}
+		config.Loads = isolationLoads(p, k6Config)
+		return config
+	}
+	if len(p.Phases) == 0 {
+		config.Loads = []framework.ManifestLoad{manifestLoad("", testType, k6Config)}
+		return config
+	}
+	for _, phase := range p.Phases {
+		if phase.Type == "idle" {
+			continue
+		}
+		phaseConfig := *k6Config
+		phaseConfig.Duration = phase.Duration
+		config.Loads = append(config.Loads, manifestLoad(phase.Name, k6.TestType(phase.Type), &phaseConfig))
+	}
+	return config
+}
+
+// isolationLoads returns the k6 loads of the noisy-neighbor scenario: the combined
+// load of the victim in each phase, and the load of the aggressor alongside it in
+// the burst. The aggressor sends to the collector of its tenant.
+func isolationLoads(p *profile.Profile, victim *k6.Config) []framework.ManifestLoad {
+	ti := p.TenantIsolation
+	phases := [][2]string{
+		{metrics.IsolationBaseline, ti.Baseline},
+		{metrics.IsolationBurst, ti.Burst},
+	}
+	if ti.Recovery != "" {
+		phases = append(phases, [2]string{metrics.IsolationRecovery, ti.Recovery})
+	}
+
+	var loads []framework.ManifestLoad
+	for _, phase := range phases {
+		phaseConfig := *victim
+		phaseConfig.Duration = phase[1]
+		loads = append(loads, manifestLoad(phase[0], k6.TestCombined, &phaseConfig))
+		if phase[0] != metrics.IsolationBurst {
+			continue
+		}
+		aggressor := AggressorK6Config(p, &phaseConfig, "")
+		testType := k6.TestIngestion
+		if aggressor.QueriesPerSecond > 0 {
+			testType = k6.TestCombined
+		}
+		loads = append(loads, manifestLoad(phase[0]+"-aggressor", testType, aggressor))
+	}
+	return loads
+}
+
+// manifestLoad returns a k6 load of a run
+func manifestLoad(name string, testType k6.TestType, config *k6.Config) framework.ManifestLoad {
+	return framework.ManifestLoad{
+		Name:     name,
+		TestType: testType,
+		Parallel: testType == k6.TestCombined,
+		Config:   config,
+	}
+}
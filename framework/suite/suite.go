@@ -0,0 +1,308 @@
+// Package suite runs a perf test against the deployment of a profile, so test files
+// only contain the load and assertions. Run checks the prerequisites, deploys MinIO,
+// Tempo and the OTel Collector, collects events, metrics, firing alerts, logs and an
+// artifact archive after the test body, and cleans up:
+//
+//	var _ = Describe("medium profile", func() {
+//	    It("handles the combined load", func() {
+//	        _, err := suite.Run("medium", &suite.Options{CollectMetrics: true}, func(fw *framework.Framework, p *profile.Profile) {
+//	            result, err := fw.RunK6ParallelTests(suite.K6Config(p))
+//	            Expect(err).NotTo(HaveOccurred())
+//	            Expect(result.Success()).To(BeTrue())
+//	        })
+//	        Expect(err).NotTo(HaveOccurred())
+//	    })
+//	})
+//
+// It does not depend on Ginkgo: the body fails the test the usual way (a Ginkgo
+// assertion panics, t.FailNow exits the goroutine) and Run still collects the
+// artifacts and cleans up on the way out.
+package suite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework"
+	"github.com/redhat/perf-tests-tempo/test/framework/metrics"
+	"github.com/redhat/perf-tests-tempo/test/framework/profile"
+)
+
+// DefaultProfilesDir is the directory profiles are loaded from
+const DefaultProfilesDir = "profiles"
+
+// DefaultOutputDir is the directory the artifacts of runs are written to
+const DefaultOutputDir = "results"
+
+// Options configures Run
+type Options struct {
+	// Context bounds the run. Default: context.Background()
+	Context context.Context
+	// ProfilesDir is the directory of the profile files. Default: DefaultProfilesDir
+	ProfilesDir string
+	// RunID names the namespace and artifacts of the run. Default: a new run ID
+	RunID string
+	// OutputDir receives the artifacts in {OutputDir}/{profile}-{run-id}.
+	// Default: DefaultOutputDir
+	OutputDir string
+	// NodeSelector places the Tempo pods; other components avoid those nodes
+	NodeSelector map[string]string
+
+	// CollectMetrics collects the Prometheus metrics of the test body
+	CollectMetrics bool
+	// CollectLogs collects and analyzes the component logs after the test body
+	CollectLogs bool
+	// Archive packs the artifacts into {OutputDir}/{profile}-{run-id}.tar.gz
+	Archive bool
+	// SkipCleanup keeps the namespace and its resources after the run
+	SkipCleanup bool
+	// CleanupTTL keeps the namespace of a failed run for debugging instead of
+	// deleting it; it expires after CleanupTTL (see framework.WithTTL)
+	CleanupTTL time.Duration
+
+	// FrameworkOptions are passed to framework.New
+	FrameworkOptions []framework.Option
+	Hooks            Hooks
+}
+
+// Hooks run custom steps at fixed points of Run. A hook returning an error fails
+// the run before the test body.
+type Hooks struct {
+	// BeforeSetup runs once the prerequisites are met, before anything is deployed
+	BeforeSetup func(fw *framework.Framework) error
+	// AfterSetup runs once Tempo and the OTel Collector are ready
+	AfterSetup func(fw *framework.Framework) error
+	// BeforeCleanup runs after the artifacts were collected, also when the body failed
+	BeforeCleanup func(fw *framework.Framework)
+}
+
+// Result describes a run
+type Result struct {
+	Profile   *profile.Profile
+	Namespace string
+	RunID     string
+
+	// TestStart and TestDuration are the time window of the test body
+	TestStart    time.Time
+	TestDuration time.Duration
+
+	// ArtifactsDir holds the metrics and logs of the run
+	ArtifactsDir string
+	// MetricsPath is the metrics CSV, set when metrics were collected
+	MetricsPath string
+	// EventsPath holds the cluster events of the run, set when they were recorded
+	EventsPath string
+	// AlertsPath holds the alerts that fired during the run, set when metrics
+	// were collected
+	AlertsPath string
+	// ArchivePath is the artifact archive, set when it was written
+	ArchivePath string
+}
+
+// Run deploys the named profile in a new namespace, runs body against it and
+// cleans up. Setup and artifact failures are returned; the body reports its own
+// failures. The returned Result is non-nil once the framework was created.
+func Run(profileName string, opts *Options, body func(fw *framework.Framework, p *profile.Profile)) (result *Result, err error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	profiles, err := profile.LoadByNames(valueOr(opts.ProfilesDir, DefaultProfilesDir), []string{profileName})
+	if err != nil {
+		return nil, err
+	}
+	if len(profiles) == 0 {
+		return nil, fmt.Errorf("no profile name given")
+	}
+	p := profiles[0]
+
+	runID := opts.RunID
+	if runID == "" {
+		runID = framework.NewRunID()
+	}
+	namespace, err := framework.RunNamespace(p.Name, runID)
+	if err != nil {
+		return nil, err
+	}
+
+	fwOpts := append([]framework.Option{framework.WithRunID(runID)}, opts.FrameworkOptions...)
+	fw, err := framework.New(ctx, namespace, fwOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create framework: %w", err)
+	}
+	result = &Result{
+		Profile:      p,
+		Namespace:    namespace,
+		RunID:        runID,
+		ArtifactsDir: filepath.Join(valueOr(opts.OutputDir, DefaultOutputDir), fmt.Sprintf("%s-%s", p.Name, runID)),
+	}
+
+	if err := fw.CheckClusterSafety(); err != nil {
+		return result, err
+	}
+	// Checked before cleanup is deferred, so a colliding run never deletes
+	// resources it does not own
+	if err := fw.CheckNamespaceAvailable(); err != nil {
+		return result, err
+	}
+	if len(opts.NodeSelector) > 0 {
+		fw.SetTempoNodeSelector(opts.NodeSelector)
+	}
+
+	if !opts.SkipCleanup {
+		defer func() {
+			if opts.Hooks.BeforeCleanup != nil {
+				opts.Hooks.BeforeCleanup(fw)
+			}
+			var cleanupOpts []framework.CleanupOption
+			if err != nil && opts.CleanupTTL > 0 {
+				cleanupOpts = append(cleanupOpts, framework.WithTTL(opts.CleanupTTL))
+			}
+			if cleanupErr := fw.Cleanup(cleanupOpts...); cleanupErr != nil && err == nil {
+				err = fmt.Errorf("failed to clean up: %w", cleanupErr)
+			}
+		}()
+	}
+
+	if err := setup(fw, p, opts); err != nil {
+		return result, err
+	}
+
+	// Deferred so the artifacts are collected before cleanup even when the body
+	// fails by panicking or exiting its goroutine
+	result.TestStart = fw.Now()
+	defer func() {
+		result.TestDuration = fw.Now().Sub(result.TestStart)
+		if artifactsErr := collectArtifacts(fw, opts, result); artifactsErr != nil && err == nil {
+			err = artifactsErr
+		}
+	}()
+
+	body(fw, p)
+	return result, nil
+}
+
+// setup checks the prerequisites and deploys the components of a profile
+func setup(fw *framework.Framework, p *profile.Profile, opts *Options) error {
+	prereqs, err := fw.CheckPrerequisites()
+	if err != nil {
+		return fmt.Errorf("failed to check prerequisites: %w", err)
+	}
+	if !prereqs.AllMet {
+		return fmt.Errorf("prerequisites not met: %s", prereqs)
+	}
+
+	if err := fw.EnableUserWorkloadMonitoring(); err != nil {
+		fw.Logger().Warn("Failed to enable user workload monitoring, Tempo metrics may not be available", "error", err)
+	}
+	if _, err := fw.WatchEvents(); err != nil {
+		fw.Logger().Warn("Failed to watch cluster events, they will not be recorded", "error", err)
+	}
+
+	if opts.Hooks.BeforeSetup != nil {
+		if err := opts.Hooks.BeforeSetup(fw); err != nil {
+			return fmt.Errorf("before setup hook failed: %w", err)
+		}
+	}
+
+	if !p.Storage.UsesPV() {
+		if err := fw.SetupMinIOWithConfig(MinIOConfig(p)); err != nil {
+			return fmt.Errorf("failed to setup MinIO: %w", err)
+		}
+	}
+	if err := fw.SetupTempo(p.Tempo.Variant, ResourceConfig(p, opts.NodeSelector)); err != nil {
+		return fmt.Errorf("failed to setup Tempo: %w", err)
+	}
+	if err := fw.SetupOTelCollector(p.Tempo.Variant); err != nil {
+		return fmt.Errorf("failed to setup OTel Collector: %w", err)
+	}
+	if err := fw.SetupTempoMonitoring(p.Tempo.Variant); err != nil {
+		fw.Logger().Warn("Failed to setup Tempo monitoring", "error", err)
+	}
+	if err := fw.SetupOTelCollectorMonitoring(); err != nil {
+		fw.Logger().Warn("Failed to setup OTel Collector monitoring", "error", err)
+	}
+	if err := fw.SetupAlertRules(); err != nil {
+		fw.Logger().Warn("Failed to setup alert rules, firing alerts will not be recorded", "error", err)
+	}
+
+	if opts.Hooks.AfterSetup != nil {
+		if err := opts.Hooks.AfterSetup(fw); err != nil {
+			return fmt.Errorf("after setup hook failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// collectArtifacts writes the cluster events, metrics and logs of a run to its
+// artifacts directory and archives it, as enabled by the options
+func collectArtifacts(fw *framework.Framework, opts *Options, result *Result) error {
+	if !opts.CollectMetrics && !opts.CollectLogs && !opts.Archive {
+		return nil
+	}
+	if err := os.MkdirAll(result.ArtifactsDir, 0755); err != nil {
+		return fmt.Errorf("failed to create artifacts directory: %w", err)
+	}
+
+	var errs []error
+	if w := fw.EventWatcher(); w != nil {
+		w.Stop()
+		eventsPath := filepath.Join(result.ArtifactsDir, "events.json")
+		if err := w.WriteJSON(eventsPath); err != nil {
+			errs = append(errs, err)
+		} else {
+			result.EventsPath = eventsPath
+		}
+	}
+	if opts.CollectMetrics {
+		metricsPath := filepath.Join(result.ArtifactsDir, "metrics.csv")
+		if err := fw.CollectMetrics(result.TestStart, metricsPath); err != nil {
+			errs = append(errs, fmt.Errorf("failed to collect metrics: %w", err))
+		} else {
+			result.MetricsPath = metricsPath
+		}
+		if alerts, err := fw.CollectAlerts(result.TestStart); err != nil {
+			errs = append(errs, fmt.Errorf("failed to collect alerts: %w", err))
+		} else {
+			alertsPath := filepath.Join(result.ArtifactsDir, "alerts.json")
+			if err := metrics.WriteAlerts(alerts, alertsPath); err != nil {
+				errs = append(errs, err)
+			} else {
+				result.AlertsPath = alertsPath
+			}
+		}
+	}
+	if opts.CollectLogs {
+		logs, err := fw.CollectLogs(&framework.LogCollectionConfig{OutputDir: filepath.Join(result.ArtifactsDir, "logs")})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to collect logs: %w", err))
+		} else {
+			fw.AnalyzeLogs(logs)
+		}
+	}
+	if opts.Archive {
+		archivePath := result.ArtifactsDir + ".tar.gz"
+		if err := archiveDir(result.ArtifactsDir, archivePath); err != nil {
+			errs = append(errs, err)
+		} else {
+			result.ArchivePath = archivePath
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// valueOr returns value, or def when value is empty
+func valueOr(value, def string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
@@ -0,0 +1,620 @@
+package suite
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/k6"
+	"github.com/redhat/perf-tests-tempo/test/framework/profile"
+)
+
+func TestArchiveDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "small-run1")
+	if err := os.MkdirAll(filepath.Join(dir, "logs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"metrics.csv":          "query_id,metric_name\n",
+		"logs/tempo-pod-0.log": "level=info\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	archivePath := dir + ".tar.gz"
+	if err := archiveDir(dir, archivePath); err != nil {
+		t.Fatalf("archiveDir() error = %v", err)
+	}
+
+	f, err := os.Open(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+
+	var names []string
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := files[header.Name[len("small-run1/"):]]
+		if string(data) != want {
+			t.Errorf("%s: expected %q, got %q", header.Name, want, data)
+		}
+		names = append(names, header.Name)
+	}
+
+	slices.Sort(names)
+	want := []string{"small-run1/logs/tempo-pod-0.log", "small-run1/metrics.csv"}
+	if !slices.Equal(names, want) {
+		t.Errorf("expected archive entries %v, got %v", want, names)
+	}
+}
+
+func TestResourceConfig_Defaults(t *testing.T) {
+	p := &profile.Profile{Name: "defaults"}
+	if config := ResourceConfig(p, nil); config != nil {
+		t.Errorf("expected nil for a profile without Tempo settings, got %+v", config)
+	}
+}
+
+func TestResourceConfig_PVStorage(t *testing.T) {
+	p := &profile.Profile{
+		Name:    "pv",
+		Storage: &profile.StorageConfig{Backend: "pv", Size: "20Gi", StorageClass: "gp3"},
+	}
+	config := ResourceConfig(p, map[string]string{"node-role": "tempo"})
+	if config == nil || config.Storage == nil {
+		t.Fatal("expected pv storage to be configured")
+	}
+	if config.Storage.Type != "pv" || config.Storage.Size != "20Gi" || config.Storage.StorageClassName != "gp3" {
+		t.Errorf("unexpected storage config %+v", *config.Storage)
+	}
+	if config.NodeSelector["node-role"] != "tempo" {
+		t.Errorf("expected the node selector to be set, got %v", config.NodeSelector)
+	}
+}
+
+func TestResourceConfig_ExtraConfig(t *testing.T) {
+	extraConfig := map[string]interface{}{"querier": map[string]interface{}{"max_concurrent_queries": 40}}
+	p := &profile.Profile{
+		Name:  "extra",
+		Tempo: profile.TempoConfig{Variant: "stack", ExtraConfig: extraConfig},
+	}
+	config := ResourceConfig(p, nil)
+	if config == nil || !reflect.DeepEqual(config.ExtraConfig, extraConfig) {
+		t.Errorf("expected the extraConfig to be passed through, got %+v", config)
+	}
+}
+
+func TestResourceConfig_MetricsGenerator(t *testing.T) {
+	p := &profile.Profile{
+		Name: "generator",
+		Tempo: profile.TempoConfig{
+			Variant:          "monolithic",
+			MetricsGenerator: &profile.MetricsGeneratorConfig{Processors: []string{"span-metrics"}},
+		},
+	}
+	config := ResourceConfig(p, nil)
+	if config == nil || config.MetricsGenerator == nil {
+		t.Fatal("expected the metrics-generator to be configured")
+	}
+	if !reflect.DeepEqual(config.MetricsGenerator.Processors, []string{"span-metrics"}) {
+		t.Errorf("unexpected processors %v", config.MetricsGenerator.Processors)
+	}
+}
+
+func TestResourceConfig_Block(t *testing.T) {
+	falsePositive := 0.05
+	p := &profile.Profile{
+		Name: "v2-zstd",
+		Tempo: profile.TempoConfig{
+			Variant: "stack",
+			Block:   &profile.BlockConfig{Version: "v2", Encoding: "zstd", BloomFilterFalsePositive: &falsePositive},
+		},
+	}
+	config := ResourceConfig(p, nil)
+	if config == nil || config.Block == nil {
+		t.Fatal("expected the block format to be configured")
+	}
+	if config.Block.Version != "v2" || config.Block.Encoding != "zstd" || *config.Block.BloomFilterFalsePositive != 0.05 {
+		t.Errorf("unexpected block format %+v", config.Block)
+	}
+}
+
+func TestValidate_Block(t *testing.T) {
+	valid := func() *profile.Profile {
+		return &profile.Profile{
+			Name:  "block",
+			Tempo: profile.TempoConfig{Variant: "monolithic", Block: &profile.BlockConfig{Version: "v2", Encoding: "snappy"}},
+			K6: profile.K6Config{
+				VUs:       profile.VUsConfig{Min: 1, Max: 2},
+				Ingestion: profile.IngestionConfig{MBPerSecond: 1, TraceProfile: "small"},
+				Query:     profile.QueryConfig{QueriesPerSecond: 1},
+			},
+		}
+	}
+	if err := profile.Validate(valid()); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	shardSize := -1
+	tests := []struct {
+		name   string
+		modify func(p *profile.Profile)
+	}{
+		{"unknown version", func(p *profile.Profile) { p.Tempo.Block.Version = "v3" }},
+		{"encoding of parquet blocks", func(p *profile.Profile) { p.Tempo.Block.Version = "vParquet4" }},
+		{"negative shard size", func(p *profile.Profile) { p.Tempo.Block.BloomFilterShardSizeBytes = &shardSize }},
+		{"extraConfig conflict", func(p *profile.Profile) {
+			p.Tempo.ExtraConfig = map[string]interface{}{"storage": map[string]interface{}{"trace": map[string]interface{}{
+				"block": map[string]interface{}{"v2_encoding": "gzip"},
+			}}}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := valid()
+			tt.modify(p)
+			if err := profile.Validate(p); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestValidate_SLO(t *testing.T) {
+	maxGrowth := -1
+	p := &profile.Profile{
+		Name:  "slo",
+		Tempo: profile.TempoConfig{Variant: "stack"},
+		K6: profile.K6Config{
+			VUs:       profile.VUsConfig{Min: 1, Max: 2},
+			Ingestion: profile.IngestionConfig{MBPerSecond: 1, TraceProfile: "small"},
+			Query:     profile.QueryConfig{QueriesPerSecond: 1},
+		},
+		SLO: &profile.SLOConfig{MaxCompactionBacklogGrowth: &maxGrowth},
+	}
+	if err := profile.Validate(p); err == nil {
+		t.Error("expected an error for a negative backlog growth")
+	}
+	maxGrowth = 0
+	if err := profile.Validate(p); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestIngestionAuth_NoGateway(t *testing.T) {
+	p := &profile.Profile{
+		Name:  "mtls",
+		Tempo: profile.TempoConfig{Variant: "monolithic", IngestionAuth: "mtls"},
+	}
+	config := ResourceConfig(p, nil)
+	if config == nil || config.IngestionAuth != "mtls" {
+		t.Fatalf("expected the ingestion auth to be passed on, got %+v", config)
+	}
+	if !K6Config(p).NoGateway {
+		t.Errorf("expected k6 to query Tempo without the gateway")
+	}
+
+	p.Tempo.IngestionAuth = "static-token"
+	if K6Config(p).NoGateway {
+		t.Errorf("expected k6 to query through the gateway with static tokens")
+	}
+}
+
+func TestIngestionProtocol(t *testing.T) {
+	p := &profile.Profile{
+		Name:  "http",
+		Tempo: profile.TempoConfig{Variant: "stack"},
+		K6: profile.K6Config{
+			VUs:       profile.VUsConfig{Min: 1, Max: 2},
+			Ingestion: profile.IngestionConfig{MBPerSecond: 1, TraceProfile: "small", Protocol: "http"},
+			Query:     profile.QueryConfig{QueriesPerSecond: 1},
+		},
+	}
+	if err := profile.Validate(p); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	config := ResourceConfig(p, nil)
+	if config == nil || config.IngestionProtocol != "http" {
+		t.Fatalf("expected the collector to export over http, got %+v", config)
+	}
+	if protocol := K6Config(p).Protocol; protocol != k6.ProtocolHTTP {
+		t.Errorf("expected k6 to send over http, got %q", protocol)
+	}
+
+	p.K6.Ingestion.Protocol = "thrift"
+	if err := profile.Validate(p); err == nil {
+		t.Error("expected an error for an unknown protocol")
+	}
+	p.K6.Ingestion.Protocol = ""
+	if ResourceConfig(p, nil) != nil || p.K6.Ingestion.IngestionProtocol() != "grpc" {
+		t.Errorf("expected the gRPC default without a protocol")
+	}
+}
+
+func TestPinnedImages(t *testing.T) {
+	p := &profile.Profile{
+		Name:   "pinned",
+		Tempo:  profile.TempoConfig{Variant: "stack"},
+		Images: &profile.ImagesConfig{Tempo: "quay.io/org/tempo:2.6.0", Collector: "quay.io/org/otelcol:0.110.0", K6: "quay.io/org/xk6-tempo@sha256:abc"},
+	}
+	config := ResourceConfig(p, nil)
+	if config == nil || config.Image != p.Images.Tempo || config.CollectorImage != p.Images.Collector {
+		t.Errorf("expected the Tempo and collector images to be pinned, got %+v", config)
+	}
+	if image := K6Config(p).Image; image != p.Images.K6 {
+		t.Errorf("expected the k6 image to be pinned, got %q", image)
+	}
+}
+
+func TestMinIOConfig(t *testing.T) {
+	p := &profile.Profile{Name: "defaults"}
+	if config := MinIOConfig(p); config != nil {
+		t.Errorf("expected nil for a profile without MinIO settings, got %+v", config)
+	}
+
+	p.Storage = &profile.StorageConfig{
+		MinioSize:         "50Gi",
+		MinioReplicas:     4,
+		MinioStorageClass: "gp3",
+		MinioResources:    &profile.ResourceSpec{Memory: "4Gi", CPU: "2"},
+	}
+	config := MinIOConfig(p)
+	if config == nil || config.StorageSize != "50Gi" || config.Replicas != 4 || config.StorageClass != "gp3" {
+		t.Fatalf("unexpected MinIO config %+v", config)
+	}
+	if config.Resources == nil || config.Resources.Limits.Memory().String() != "4Gi" || config.Resources.Requests.Cpu().String() != "2" {
+		t.Errorf("expected the MinIO resources to be set, got %+v", config.Resources)
+	}
+}
+
+func TestNamespaceQuota(t *testing.T) {
+	p := &profile.Profile{Name: "defaults"}
+	if quota := NamespaceQuota(p); quota != nil {
+		t.Errorf("expected nil for a profile without a quota, got %+v", quota)
+	}
+
+	p.Quota = &profile.QuotaConfig{CPU: "16", Memory: "64Gi", Pods: 40}
+	quota := NamespaceQuota(p)
+	if quota == nil || quota.String() != "cpu 16, memory 64Gi, 40 pods" {
+		t.Fatalf("unexpected quota %+v", quota)
+	}
+	if quota.DefaultLimits != nil {
+		t.Errorf("expected the default container limits, got %v", quota.DefaultLimits)
+	}
+
+	p.Quota.DefaultContainer = &profile.ResourceSpec{Memory: "1Gi", CPU: "1"}
+	if limits := NamespaceQuota(p).DefaultLimits; limits.Memory().String() != "1Gi" || limits.Cpu().String() != "1" {
+		t.Errorf("expected the default container limits of the profile, got %v", limits)
+	}
+}
+
+func TestManifestConfig(t *testing.T) {
+	p := &profile.Profile{Name: "small", Tempo: profile.TempoConfig{Variant: "stack"}}
+	config := ManifestConfig(p, k6.TestQuery, nil)
+	if config.Variant != "stack" || config.SkipMinIO {
+		t.Errorf("unexpected deployment %+v", config)
+	}
+	if len(config.Loads) != 1 || config.Loads[0].TestType != k6.TestQuery || config.Loads[0].Parallel {
+		t.Fatalf("expected a single query load, got %+v", config.Loads)
+	}
+	if config.Loads[0].Config.PrometheusRWURL == "" {
+		t.Error("expected k6 to remote write its metrics")
+	}
+}
+
+func TestManifestConfig_Phases(t *testing.T) {
+	p := &profile.Profile{
+		Name:    "phased",
+		Tempo:   profile.TempoConfig{Variant: "monolithic"},
+		Storage: &profile.StorageConfig{Backend: "pv"},
+		Phases: []profile.PhaseConfig{
+			{Name: "warmup", Type: "ingestion", Duration: "5m"},
+			{Name: "pause", Type: "idle", Duration: "1m"},
+			{Name: "mixed", Type: "combined", Duration: "10m"},
+		},
+	}
+	config := ManifestConfig(p, k6.TestIngestion, nil)
+	if !config.SkipMinIO {
+		t.Error("expected MinIO to be skipped for PV storage")
+	}
+	if len(config.Loads) != 2 {
+		t.Fatalf("expected the 2 non-idle phases, got %+v", config.Loads)
+	}
+	warmup, mixed := config.Loads[0], config.Loads[1]
+	if warmup.Name != "warmup" || warmup.Parallel || warmup.Config.Duration != "5m" {
+		t.Errorf("unexpected warmup load %+v", warmup)
+	}
+	if mixed.Name != "mixed" || !mixed.Parallel || mixed.Config.Duration != "10m" {
+		t.Errorf("unexpected mixed load %+v", mixed)
+	}
+}
+
+func TestManifestConfig_TenantIsolation(t *testing.T) {
+	p := &profile.Profile{
+		Name:  "noisy",
+		Tempo: profile.TempoConfig{Variant: "stack"},
+		TenantIsolation: &profile.TenantIsolationConfig{
+			Baseline:  "5m",
+			Burst:     "10m",
+			Aggressor: profile.AggressorConfig{MBPerSecond: 20},
+		},
+	}
+	config := ManifestConfig(p, k6.TestIngestion, nil)
+	if !slices.Equal(config.TenantCollectors, []string{AggressorTenant}) {
+		t.Errorf("expected the collector of the aggressor, got %v", config.TenantCollectors)
+	}
+
+	var names []string
+	for _, load := range config.Loads {
+		names = append(names, load.Name)
+	}
+	if !slices.Equal(names, []string{"baseline", "burst", "burst-aggressor"}) {
+		t.Fatalf("expected the victim phases and the aggressor, got %v", names)
+	}
+	if burst := config.Loads[1]; !burst.Parallel || burst.Config.Duration != "10m" || burst.Config.TempoTenant == AggressorTenant {
+		t.Errorf("unexpected victim burst load %+v", burst)
+	}
+	// Without queries the aggressor only ingests, through the collector of its tenant
+	aggressor := config.Loads[2]
+	if aggressor.Parallel || aggressor.TestType != k6.TestIngestion || aggressor.Config.TempoTenant != AggressorTenant || aggressor.Config.TempoEndpoint != "" {
+		t.Errorf("unexpected aggressor load %+v", aggressor)
+	}
+}
+
+func TestK6Config_Pod(t *testing.T) {
+	p := &profile.Profile{Name: "defaults", Tempo: profile.TempoConfig{Variant: "stack"}}
+	if pod := K6Config(p).Pod; pod != nil {
+		t.Errorf("expected no k6 pod overrides, got %+v", pod)
+	}
+
+	p.K6.Pod = &profile.K6PodConfig{
+		Resources:    &profile.ResourceSpec{Memory: "8Gi", CPU: "4"},
+		NodeSelector: map[string]string{"node-role.kubernetes.io/loadgen": ""},
+		Tolerations:  []profile.TolerationConfig{{Key: "loadgen", Operator: "Exists", Effect: "NoSchedule"}},
+		Affinity: map[string]interface{}{
+			"podAntiAffinity": map[string]interface{}{
+				"requiredDuringSchedulingIgnoredDuringExecution": []interface{}{
+					map[string]interface{}{
+						"topologyKey":   "kubernetes.io/hostname",
+						"labelSelector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "k6-perf-test"}},
+					},
+				},
+			},
+		},
+	}
+	pod := K6Config(p).Pod
+	if pod == nil || pod.Resources == nil || pod.Resources.Limits.Memory().String() != "8Gi" || pod.Resources.Requests.Cpu().String() != "4" {
+		t.Fatalf("expected the k6 resources of the profile, got %+v", pod)
+	}
+	if _, ok := pod.NodeSelector["node-role.kubernetes.io/loadgen"]; !ok {
+		t.Errorf("expected the k6 node selector, got %v", pod.NodeSelector)
+	}
+	if len(pod.Tolerations) != 1 || pod.Tolerations[0].Operator != "Exists" || pod.Tolerations[0].Effect != "NoSchedule" {
+		t.Errorf("unexpected tolerations %+v", pod.Tolerations)
+	}
+	if pod.Affinity == nil || pod.Affinity.PodAntiAffinity == nil || len(pod.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution) != 1 {
+		t.Errorf("expected the pod anti-affinity of the profile, got %+v", pod.Affinity)
+	}
+}
+
+func TestValidate_K6Pod(t *testing.T) {
+	valid := func() *profile.Profile {
+		return &profile.Profile{
+			Name:  "k6-pod",
+			Tempo: profile.TempoConfig{Variant: "stack"},
+			K6: profile.K6Config{
+				VUs:       profile.VUsConfig{Min: 1, Max: 2},
+				Ingestion: profile.IngestionConfig{MBPerSecond: 1, TraceProfile: "small"},
+				Query:     profile.QueryConfig{QueriesPerSecond: 1},
+				Pod:       &profile.K6PodConfig{},
+			},
+		}
+	}
+	if err := profile.Validate(valid()); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		pod  profile.K6PodConfig
+	}{
+		{"partial resources", profile.K6PodConfig{Resources: &profile.ResourceSpec{CPU: "4"}}},
+		{"bad quantity", profile.K6PodConfig{Resources: &profile.ResourceSpec{CPU: "4", Memory: "lots"}}},
+		{"bad operator", profile.K6PodConfig{Tolerations: []profile.TolerationConfig{{Key: "a", Operator: "In"}}}},
+		{"exists with value", profile.K6PodConfig{Tolerations: []profile.TolerationConfig{{Key: "a", Operator: "Exists", Value: "b"}}}},
+		{"bad effect", profile.K6PodConfig{Tolerations: []profile.TolerationConfig{{Key: "a", Effect: "NoRun"}}}},
+		{"unknown affinity field", profile.K6PodConfig{Affinity: map[string]interface{}{"nodeAffinty": map[string]interface{}{}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := valid()
+			p.K6.Pod = &tt.pod
+			if err := profile.Validate(p); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	p := &profile.Profile{}
+	if got := Timeout(p, time.Hour); got != time.Hour {
+		t.Errorf("Timeout() = %s, want the fallback 1h", got)
+	}
+	p.Timeout = "90m"
+	if got := Timeout(p, time.Hour); got != 90*time.Minute {
+		t.Errorf("Timeout() = %s, want the profile timeout 1h30m", got)
+	}
+}
+
+func tenantIsolationProfile() *profile.Profile {
+	return &profile.Profile{
+		Name:  "noisy-neighbor",
+		Tempo: profile.TempoConfig{Variant: "stack"},
+		K6: profile.K6Config{
+			VUs:       profile.VUsConfig{Min: 10, Max: 50},
+			Ingestion: profile.IngestionConfig{MBPerSecond: 1, TraceProfile: "small"},
+			Query:     profile.QueryConfig{QueriesPerSecond: 5},
+		},
+		TenantIsolation: &profile.TenantIsolationConfig{
+			Baseline:  "5m",
+			Burst:     "10m",
+			Aggressor: profile.AggressorConfig{MBPerSecond: 20, VUs: 5},
+		},
+	}
+}
+
+func TestValidate_TenantIsolation(t *testing.T) {
+	if err := profile.Validate(tenantIsolationProfile()); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(p *profile.Profile)
+	}{
+		{"missing burst", func(p *profile.Profile) { p.TenantIsolation.Burst = "" }},
+		{"bad recovery", func(p *profile.Profile) { p.TenantIsolation.Recovery = "soon" }},
+		{"no aggressor rate", func(p *profile.Profile) { p.TenantIsolation.Aggressor.MBPerSecond = 0 }},
+		{"with phases", func(p *profile.Profile) {
+			p.Phases = []profile.PhaseConfig{{Name: "ingest", Type: "ingestion", Duration: "5m"}}
+		}},
+		{"without gateway", func(p *profile.Profile) {
+			p.Tempo.Variant = "monolithic"
+			p.Tempo.IngestionAuth = "none"
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tenantIsolationProfile()
+			tt.modify(p)
+			if err := profile.Validate(p); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestResourceConfig_TenantIsolation(t *testing.T) {
+	config := ResourceConfig(tenantIsolationProfile(), nil)
+	if config == nil || !slices.Equal(config.Tenants, []string{k6.DefaultTenant, AggressorTenant}) {
+		t.Fatalf("expected the victim and aggressor tenants, got %+v", config)
+	}
+}
+
+func TestAggressorK6Config(t *testing.T) {
+	p := tenantIsolationProfile()
+	victim := K6Config(p)
+	victim.QueryCorpusFile = "corpus.json"
+	victim.PrometheusRWURL = "http://prometheus/api/v1/write"
+
+	config := AggressorK6Config(p, victim, "otel-collector-tenant-2-collector:4317")
+
+	if config.TempoTenant != AggressorTenant || config.TempoEndpoint != "otel-collector-tenant-2-collector:4317" {
+		t.Errorf("expected the aggressor tenant and collector, got %q and %q", config.TempoTenant, config.TempoEndpoint)
+	}
+	if config.MBPerSecond != 20 || config.QueriesPerSecond != 0 || config.Duration != "10m" {
+		t.Errorf("expected 20 MB/s without queries for 10m, got %+v", config)
+	}
+	if config.VUsMin != 5 || config.VUsMax != 5 {
+		t.Errorf("expected 5 VUs, got %d-%d", config.VUsMin, config.VUsMax)
+	}
+	if config.QueryCorpusFile != "" || config.PrometheusRWURL != victim.PrometheusRWURL {
+		t.Errorf("expected no corpus and the remote write URL of the victim, got %+v", config)
+	}
+	if victim.TempoTenant != "" || victim.MBPerSecond != 1 {
+		t.Errorf("expected the victim config to be unchanged, got %+v", victim)
+	}
+}
+
+// kafkaProfile returns a valid profile ingesting through Kafka
+func kafkaProfile() *profile.Profile {
+	return &profile.Profile{
+		Name:  "kafka",
+		Tempo: profile.TempoConfig{Variant: "monolithic"},
+		K6: profile.K6Config{
+			VUs:       profile.VUsConfig{Min: 1, Max: 2},
+			Ingestion: profile.IngestionConfig{MBPerSecond: 1, TraceProfile: "small"},
+			Query:     profile.QueryConfig{QueriesPerSecond: 1},
+		},
+		Kafka: &profile.KafkaConfig{
+			Partitions: 3,
+			Resources:  &profile.ResourceSpec{Memory: "2Gi", CPU: "1"},
+		},
+	}
+}
+
+func TestValidate_Kafka(t *testing.T) {
+	if err := profile.Validate(kafkaProfile()); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	external := kafkaProfile()
+	external.Kafka = &profile.KafkaConfig{Brokers: []string{"kafka-bootstrap.kafka.svc:9092"}, Topic: "tempo.spans"}
+	if err := profile.Validate(external); err != nil {
+		t.Fatalf("Validate() error = %v for existing brokers", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(p *profile.Profile)
+	}{
+		{"broker without port", func(p *profile.Profile) {
+			p.Kafka = &profile.KafkaConfig{Brokers: []string{"kafka-bootstrap"}}
+		}},
+		{"partitions with brokers", func(p *profile.Profile) { p.Kafka.Brokers = []string{"kafka:9092"} }},
+		{"bad topic", func(p *profile.Profile) { p.Kafka.Topic = "otlp spans" }},
+		{"negative partitions", func(p *profile.Profile) { p.Kafka.Partitions = -1 }},
+		{"resources without cpu", func(p *profile.Profile) { p.Kafka.Resources.CPU = "" }},
+		{"with tenant isolation", func(p *profile.Profile) {
+			p.Tempo.Variant = "stack"
+			p.TenantIsolation = tenantIsolationProfile().TenantIsolation
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := kafkaProfile()
+			tt.modify(p)
+			if err := profile.Validate(p); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestResourceConfig_Kafka(t *testing.T) {
+	config := ResourceConfig(kafkaProfile(), nil)
+	if config == nil || config.Kafka == nil {
+		t.Fatalf("expected a Kafka config, got %+v", config)
+	}
+	if config.Kafka.Partitions != 3 || len(config.Kafka.Brokers) != 0 {
+		t.Errorf("expected a deployed broker with 3 partitions, got %+v", config.Kafka)
+	}
+	if r := config.Kafka.Resources; r == nil || r.Limits.Memory().String() != "2Gi" {
+		t.Errorf("expected the broker resources, got %+v", r)
+	}
+}
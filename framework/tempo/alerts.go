@@ -0,0 +1,137 @@
+package tempo
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/gvr"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// AlertRulesName is the name of the PrometheusRule of the performance alerts
+const AlertRulesName = "tempo-perf-alerts"
+
+// AlertRule is a performance alert evaluated by Prometheus during a run
+type AlertRule struct {
+	Name string
+	// Expr is the PromQL expression; %[1]s is replaced by the test namespace
+	Expr string
+	// For is how long Expr must hold before the alert fires
+	For      time.Duration
+	Severity string
+	Summary  string
+}
+
+// DefaultAlertRules are the performance alerts deployed by SetupAlertRules. The
+// durations are short, so alerts fire within the load of a test.
+var DefaultAlertRules = []AlertRule{
+	{
+		Name: "TempoPerfHighRefusedSpans",
+		Expr: `sum(rate(tempo_receiver_refused_spans{namespace="%[1]s"}[1m]))` +
+			` / (sum(rate(tempo_receiver_accepted_spans{namespace="%[1]s"}[1m])) + sum(rate(tempo_receiver_refused_spans{namespace="%[1]s"}[1m]))) > 0.01`,
+		For:      time.Minute,
+		Severity: "warning",
+		Summary:  "More than 1% of the received spans are refused",
+	},
+	{
+		Name:     "TempoPerfCompactorFallingBehind",
+		Expr:     `sum(tempodb_compaction_outstanding_blocks{namespace="%[1]s"}) > 100`,
+		For:      5 * time.Minute,
+		Severity: "warning",
+		Summary:  "More than 100 blocks are waiting for compaction",
+	},
+	{
+		Name:     "TempoPerfQueryQueueSaturated",
+		Expr:     `histogram_quantile(0.99, sum(rate(tempo_query_frontend_queue_duration_seconds_bucket{namespace="%[1]s"}[1m])) by (le)) > 1`,
+		For:      2 * time.Minute,
+		Severity: "warning",
+		Summary:  "Queries wait more than 1s in the query-frontend queue (p99)",
+	},
+}
+
+// SetupAlertRules creates a PrometheusRule with the alert rules for the test
+// namespace; nil rules uses DefaultAlertRules. The firing alerts are collected
+// with metrics.CollectAlerts.
+func SetupAlertRules(fw FrameworkOperations, rules []AlertRule) error {
+	namespace := fw.Namespace()
+	ctx := fw.Context()
+	if rules == nil {
+		rules = DefaultAlertRules
+	}
+
+	fmt.Println("\n🚨 Setting up performance alert rules...")
+
+	rule := buildAlertRules(namespace, rules)
+	labels := rule.GetLabels()
+	for k, v := range fw.GetManagedLabels() {
+		labels[k] = v
+	}
+	rule.SetLabels(labels)
+
+	client := fw.DynamicClient().Resource(gvr.PrometheusRule).Namespace(namespace)
+	if _, err := client.Create(ctx, rule, metav1.CreateOptions{}); err != nil {
+		if !apierrors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create PrometheusRule: %w", err)
+		}
+		existing, err := client.Get(ctx, AlertRulesName, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get PrometheusRule: %w", err)
+		}
+		existing.Object["spec"] = rule.Object["spec"]
+		if _, err := client.Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to update PrometheusRule: %w", err)
+		}
+	}
+	fw.TrackCR(gvr.PrometheusRule, namespace, AlertRulesName)
+
+	fmt.Printf("✅ Created PrometheusRule %s with %d alerts\n", AlertRulesName, len(rules))
+	return nil
+}
+
+// buildAlertRules builds the PrometheusRule of the alert rules for a namespace
+func buildAlertRules(namespace string, rules []AlertRule) *unstructured.Unstructured {
+	alerts := make([]interface{}, 0, len(rules))
+	for _, r := range rules {
+		alerts = append(alerts, map[string]interface{}{
+			"alert": r.Name,
+			"expr":  fmt.Sprintf(r.Expr, namespace),
+			"for":   fmt.Sprintf("%ds", int(r.For.Seconds())),
+			"labels": map[string]interface{}{
+				"severity": r.Severity,
+			},
+			"annotations": map[string]interface{}{
+				"summary": r.Summary,
+			},
+		})
+	}
+
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "monitoring.coreos.com/v1",
+			"kind":       "PrometheusRule",
+			"metadata": map[string]interface{}{
+				"name":      AlertRulesName,
+				"namespace": namespace,
+				"labels": map[string]interface{}{
+					"app.kubernetes.io/name":       "tempo-perf-alerts",
+					"app.kubernetes.io/managed-by": "perf-tests",
+					// Evaluated by the user workload Prometheus, which scrapes the
+					// Tempo metrics, instead of Thanos Ruler
+					"openshift.io/prometheus-rule-evaluation-scope": "leaf-prometheus",
+				},
+			},
+			"spec": map[string]interface{}{
+				"groups": []interface{}{
+					map[string]interface{}{
+						"name":     "tempo-perf",
+						"interval": "30s",
+						"rules":    alerts,
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,31 @@
+package tempo
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestBuildAlertRules(t *testing.T) {
+	rule := buildAlertRules("perf", DefaultAlertRules)
+
+	groups, _, _ := unstructured.NestedSlice(rule.Object, "spec", "groups")
+	if len(groups) != 1 {
+		t.Fatalf("expected 1 rule group, got %d", len(groups))
+	}
+	rules := groups[0].(map[string]interface{})["rules"].([]interface{})
+	if len(rules) != len(DefaultAlertRules) {
+		t.Fatalf("expected %d rules, got %d", len(DefaultAlertRules), len(rules))
+	}
+	for _, r := range rules {
+		alert := r.(map[string]interface{})
+		expr := alert["expr"].(string)
+		if strings.Contains(expr, "%") || !strings.Contains(expr, `namespace="perf"`) {
+			t.Errorf("expected %s to select the namespace, got %s", alert["alert"], expr)
+		}
+	}
+	if got := rules[0].(map[string]interface{})["for"]; got != "60s" {
+		t.Errorf("expected for 60s, got %v", got)
+	}
+}
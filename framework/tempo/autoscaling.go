@@ -0,0 +1,106 @@
+package tempo
+
+import (
+	"fmt"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/gvr"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultAutoscaledComponents are the TempoStack components autoscaled when none are configured
+var DefaultAutoscaledComponents = []string{"distributor", "querier"}
+
+// DefaultTargetCPUUtilization is the CPU utilization target used when none is configured
+const DefaultTargetCPUUtilization = 80
+
+// SetupAutoscaling creates a HorizontalPodAutoscaler for each configured component
+// of a TempoStack.
+//
+// The operator reconciles the replica count of every deployment from the CR, which
+// would undo each scaling decision, so the TempoStack is switched to unmanaged first.
+// Utilization targets are relative to the container requests; set resources in the
+// profile so the operator assigns requests to every component.
+func SetupAutoscaling(fw FrameworkOperations, crName string, cfg *AutoscalingConfig) error {
+	if cfg == nil {
+		return nil
+	}
+
+	if err := waitForWorkloads(fw, gvr.TempoStack, crName); err != nil {
+		return err
+	}
+	if err := setUnmanaged(fw, gvr.TempoStack, crName); err != nil {
+		return err
+	}
+
+	components := cfg.Components
+	if len(components) == 0 {
+		components = DefaultAutoscaledComponents
+	}
+
+	for _, component := range components {
+		hpa := buildHPA(fw.Namespace(), crName, component, cfg, fw.GetManagedLabels())
+		_, err := fw.Client().AutoscalingV2().HorizontalPodAutoscalers(fw.Namespace()).Create(fw.Context(), hpa, metav1.CreateOptions{})
+		if err != nil && !apierrors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create HPA for %s: %w", component, err)
+		}
+		fw.Logger().Info("Created HPA", "component", component,
+			"minReplicas", *hpa.Spec.MinReplicas, "maxReplicas", hpa.Spec.MaxReplicas)
+	}
+
+	return nil
+}
+
+// buildHPA builds the HorizontalPodAutoscaler of a TempoStack component deployment
+func buildHPA(namespace, crName, component string, cfg *AutoscalingConfig, labels map[string]string) *autoscalingv2.HorizontalPodAutoscaler {
+	name := fmt.Sprintf("tempo-%s-%s", crName, component)
+
+	minReplicas := int32(cfg.MinReplicas)
+	if minReplicas <= 0 {
+		minReplicas = 1
+	}
+	targetCPU := int32(DefaultTargetCPUUtilization)
+	if cfg.TargetCPUUtilization != nil {
+		targetCPU = int32(*cfg.TargetCPUUtilization)
+	}
+
+	metrics := []autoscalingv2.MetricSpec{resourceMetric(corev1.ResourceCPU, targetCPU)}
+	if cfg.TargetMemoryUtilization != nil {
+		metrics = append(metrics, resourceMetric(corev1.ResourceMemory, int32(*cfg.TargetMemoryUtilization)))
+	}
+
+	return &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       name,
+			},
+			MinReplicas: &minReplicas,
+			MaxReplicas: int32(cfg.MaxReplicas),
+			Metrics:     metrics,
+		},
+	}
+}
+
+// resourceMetric returns an average utilization target for a container resource
+func resourceMetric(name corev1.ResourceName, utilization int32) autoscalingv2.MetricSpec {
+	return autoscalingv2.MetricSpec{
+		Type: autoscalingv2.ResourceMetricSourceType,
+		Resource: &autoscalingv2.ResourceMetricSource{
+			Name: name,
+			Target: autoscalingv2.MetricTarget{
+				Type:               autoscalingv2.UtilizationMetricType,
+				AverageUtilization: &utilization,
+			},
+		},
+	}
+}
@@ -0,0 +1,56 @@
+package tempo
+
+import (
+	"testing"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestBuildHPA_Defaults(t *testing.T) {
+	hpa := buildHPA("tempo-perf-test", "tempostack", "distributor", &AutoscalingConfig{MaxReplicas: 4}, map[string]string{"managed-by": "test"})
+
+	if hpa.Name != "tempo-tempostack-distributor" || hpa.Spec.ScaleTargetRef.Name != "tempo-tempostack-distributor" {
+		t.Errorf("unexpected HPA name %q targeting %q", hpa.Name, hpa.Spec.ScaleTargetRef.Name)
+	}
+	if hpa.Spec.ScaleTargetRef.Kind != "Deployment" {
+		t.Errorf("expected Deployment target, got %q", hpa.Spec.ScaleTargetRef.Kind)
+	}
+	if *hpa.Spec.MinReplicas != 1 || hpa.Spec.MaxReplicas != 4 {
+		t.Errorf("replicas = %d-%d, want 1-4", *hpa.Spec.MinReplicas, hpa.Spec.MaxReplicas)
+	}
+	if len(hpa.Spec.Metrics) != 1 {
+		t.Fatalf("expected only a CPU metric, got %d metrics", len(hpa.Spec.Metrics))
+	}
+	cpu := hpa.Spec.Metrics[0].Resource
+	if cpu.Name != corev1.ResourceCPU || *cpu.Target.AverageUtilization != DefaultTargetCPUUtilization {
+		t.Errorf("unexpected CPU metric %+v", cpu)
+	}
+	if hpa.Labels["managed-by"] != "test" {
+		t.Errorf("expected managed labels, got %v", hpa.Labels)
+	}
+}
+
+func TestBuildHPA_CustomTargets(t *testing.T) {
+	cpu, memory := 60, 75
+	cfg := &AutoscalingConfig{MinReplicas: 2, MaxReplicas: 8, TargetCPUUtilization: &cpu, TargetMemoryUtilization: &memory}
+
+	hpa := buildHPA("tempo-perf-test", "tempostack", "querier", cfg, nil)
+
+	if *hpa.Spec.MinReplicas != 2 || hpa.Spec.MaxReplicas != 8 {
+		t.Errorf("replicas = %d-%d, want 2-8", *hpa.Spec.MinReplicas, hpa.Spec.MaxReplicas)
+	}
+	want := map[corev1.ResourceName]int32{corev1.ResourceCPU: 60, corev1.ResourceMemory: 75}
+	if len(hpa.Spec.Metrics) != len(want) {
+		t.Fatalf("expected %d metrics, got %d", len(want), len(hpa.Spec.Metrics))
+	}
+	for _, m := range hpa.Spec.Metrics {
+		if m.Type != autoscalingv2.ResourceMetricSourceType || m.Resource.Target.Type != autoscalingv2.UtilizationMetricType {
+			t.Errorf("expected resource utilization metric, got %+v", m)
+			continue
+		}
+		if *m.Resource.Target.AverageUtilization != want[m.Resource.Name] {
+			t.Errorf("%s target = %d, want %d", m.Resource.Name, *m.Resource.Target.AverageUtilization, want[m.Resource.Name])
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package tempo
+
+import (
+	"fmt"
+	"slices"
+)
+
+// BlockVersions are the Tempo block formats that can be written
+var BlockVersions = []string{"v2", "vParquet2", "vParquet3", "vParquet4"}
+
+// BlockEncodings are the compression encodings of v2 blocks
+var BlockEncodings = []string{"none", "gzip", "lz4-64k", "lz4-256k", "lz4-1M", "lz4", "snappy", "zstd", "s2"}
+
+// validateBlock checks the block format settings
+func validateBlock(cfg *BlockConfig) error {
+	if cfg == nil {
+		return nil
+	}
+	if cfg.Version != "" && !slices.Contains(BlockVersions, cfg.Version) {
+		return fmt.Errorf("invalid block version %q (must be one of %v)", cfg.Version, BlockVersions)
+	}
+	if cfg.Encoding != "" {
+		if !slices.Contains(BlockEncodings, cfg.Encoding) {
+			return fmt.Errorf("invalid block encoding %q (must be one of %v)", cfg.Encoding, BlockEncodings)
+		}
+		// Parquet blocks have their own compression, the encoding would silently
+		// not apply to them
+		if cfg.Version != "v2" {
+			return fmt.Errorf("block encoding %q only applies to v2 blocks (set the block version to v2)", cfg.Encoding)
+		}
+	}
+	if cfg.BloomFilterFalsePositive != nil && (*cfg.BloomFilterFalsePositive <= 0 || *cfg.BloomFilterFalsePositive >= 1) {
+		return fmt.Errorf("block bloom filter false positive rate must be between 0 and 1, got %v", *cfg.BloomFilterFalsePositive)
+	}
+	if cfg.BloomFilterShardSizeBytes != nil && *cfg.BloomFilterShardSizeBytes <= 0 {
+		return fmt.Errorf("block bloom filter shard size must be positive, got %d", *cfg.BloomFilterShardSizeBytes)
+	}
+	return nil
+}
+
+// buildBlockExtraConfig returns the extraConfig of the storage.trace.block settings
+// of cfg, or nil when none is set
+func buildBlockExtraConfig(cfg *BlockConfig) map[string]interface{} {
+	if cfg == nil {
+		return nil
+	}
+	block := map[string]interface{}{}
+	if cfg.Version != "" {
+		block["version"] = cfg.Version
+	}
+	if cfg.Encoding != "" {
+		block["v2_encoding"] = cfg.Encoding
+	}
+	if cfg.BloomFilterFalsePositive != nil {
+		block["bloom_filter_false_positive"] = *cfg.BloomFilterFalsePositive
+	}
+	if cfg.BloomFilterShardSizeBytes != nil {
+		block["bloom_filter_shard_size_bytes"] = *cfg.BloomFilterShardSizeBytes
+	}
+	if len(block) == 0 {
+		return nil
+	}
+	return map[string]interface{}{
+		"storage": map[string]interface{}{
+			"trace": map[string]interface{}{
+				"block": block,
+			},
+		},
+	}
+}
@@ -0,0 +1,89 @@
+package tempo
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestValidateBlock(t *testing.T) {
+	falsePositive := 0.05
+	badFalsePositive := 1.5
+	shardSize := 0
+	tests := []struct {
+		name    string
+		cfg     *BlockConfig
+		wantErr bool
+	}{
+		{name: "unset", cfg: nil},
+		{name: "vParquet4", cfg: &BlockConfig{Version: "vParquet4", BloomFilterFalsePositive: &falsePositive}},
+		{name: "v2 with zstd", cfg: &BlockConfig{Version: "v2", Encoding: "zstd"}},
+		{name: "unknown version", cfg: &BlockConfig{Version: "vParquet9"}, wantErr: true},
+		{name: "unknown encoding", cfg: &BlockConfig{Version: "v2", Encoding: "brotli"}, wantErr: true},
+		{name: "encoding of parquet blocks", cfg: &BlockConfig{Version: "vParquet3", Encoding: "snappy"}, wantErr: true},
+		{name: "encoding without version", cfg: &BlockConfig{Encoding: "snappy"}, wantErr: true},
+		{name: "false positive rate above 1", cfg: &BlockConfig{BloomFilterFalsePositive: &badFalsePositive}, wantErr: true},
+		{name: "zero shard size", cfg: &BlockConfig{BloomFilterShardSizeBytes: &shardSize}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateBlock(tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateBlock() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBuildTempoMonolithicCR_Block(t *testing.T) {
+	falsePositive := 0.05
+	shardSize := 256 * 1024
+	cr := buildTempoMonolithicCR("perf", &ResourceConfig{
+		Block: &BlockConfig{
+			Version:                   "v2",
+			Encoding:                  "zstd",
+			BloomFilterFalsePositive:  &falsePositive,
+			BloomFilterShardSizeBytes: &shardSize,
+		},
+		ExtraConfig: map[string]interface{}{"storage": map[string]interface{}{"trace": map[string]interface{}{
+			"pool": map[string]interface{}{"max_workers": 200},
+		}}},
+	})
+
+	var extraConfig map[string]interface{}
+	if err := json.Unmarshal(cr.Spec.ExtraConfig.Tempo.Raw, &extraConfig); err != nil {
+		t.Fatalf("invalid extraConfig: %v", err)
+	}
+	expected := map[string]interface{}{
+		"block": map[string]interface{}{
+			"version":                       "v2",
+			"v2_encoding":                   "zstd",
+			"bloom_filter_false_positive":   0.05,
+			"bloom_filter_shard_size_bytes": float64(shardSize),
+		},
+		"pool": map[string]interface{}{"max_workers": float64(200)},
+	}
+	trace := extraConfig["storage"].(map[string]interface{})["trace"]
+	if !reflect.DeepEqual(trace, expected) {
+		t.Errorf("got storage.trace %v, want %v", trace, expected)
+	}
+}
+
+func TestBuildStackExtraConfig_Block(t *testing.T) {
+	var extraConfig map[string]interface{}
+	if err := json.Unmarshal(buildStackExtraConfig(&ResourceConfig{Block: &BlockConfig{Version: "vParquet3"}}), &extraConfig); err != nil {
+		t.Fatalf("invalid extraConfig: %v", err)
+	}
+	expected := map[string]interface{}{"version": "vParquet3"}
+	block := extraConfig["storage"].(map[string]interface{})["trace"].(map[string]interface{})["block"]
+	if !reflect.DeepEqual(block, expected) {
+		t.Errorf("got storage.trace.block %v, want %v", block, expected)
+	}
+}
+
+func TestBuildBlockExtraConfig_Empty(t *testing.T) {
+	if config := buildBlockExtraConfig(&BlockConfig{}); config != nil {
+		t.Errorf("expected no extraConfig without block settings, got %v", config)
+	}
+}
@@ -0,0 +1,59 @@
+package tempo
+
+import (
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// SetTempoEnv sets environment variables of the Tempo container in all Tempo
+// workloads of a CR, e.g. GOGC and GOMEMLIMIT to benchmark Go runtime tuning.
+// Like extra config files, the operator has no API for them, so the CR is switched
+// to unmanaged and stops being reconciled for the rest of the run.
+func SetTempoEnv(fw FrameworkOperations, crGVR schema.GroupVersionResource, crName string, env map[string]string) error {
+	if len(env) == 0 {
+		return nil
+	}
+
+	if err := patchTempoWorkloads(fw, crGVR, crName, "set environment of", "Set Tempo environment",
+		func(spec *corev1.PodSpec) bool {
+			return setContainerEnv(spec, env)
+		}); err != nil {
+		return err
+	}
+
+	fw.Logger().Info("Tempo environment configured", "env", env)
+	return nil
+}
+
+// setContainerEnv sets env in the Tempo container, replacing variables of the same
+// name set by the operator. Returns false if the pod has no Tempo container.
+func setContainerEnv(spec *corev1.PodSpec, env map[string]string) bool {
+	containerIdx := tempoContainerIndex(spec)
+	if containerIdx < 0 {
+		return false
+	}
+
+	names := make([]string, 0, len(env))
+	for name := range env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	container := &spec.Containers[containerIdx]
+	for _, name := range names {
+		replaced := false
+		for i := range container.Env {
+			if container.Env[i].Name == name {
+				container.Env[i] = corev1.EnvVar{Name: name, Value: env[name]}
+				replaced = true
+			}
+		}
+		if !replaced {
+			container.Env = append(container.Env, corev1.EnvVar{Name: name, Value: env[name]})
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,64 @@
+package tempo
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestSetContainerEnv(t *testing.T) {
+	env := map[string]string{"GOMEMLIMIT": "3GiB", "GOGC": "200"}
+
+	tests := []struct {
+		name    string
+		spec    corev1.PodSpec
+		wantOK  bool
+		wantEnv []corev1.EnvVar
+	}{
+		{
+			name:   "no tempo container",
+			spec:   corev1.PodSpec{Containers: []corev1.Container{{Name: "tempo-gateway"}}},
+			wantOK: false,
+		},
+		{
+			name:   "appends in name order",
+			spec:   corev1.PodSpec{Containers: []corev1.Container{{Name: "sidecar"}, {Name: "tempo"}}},
+			wantOK: true,
+			wantEnv: []corev1.EnvVar{
+				{Name: "GOGC", Value: "200"},
+				{Name: "GOMEMLIMIT", Value: "3GiB"},
+			},
+		},
+		{
+			name: "replaces operator values",
+			spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "tempo", Env: []corev1.EnvVar{
+				{Name: "GOMEMLIMIT", Value: "1GiB"},
+				{Name: "TZ", Value: "UTC"},
+			}}}},
+			wantOK: true,
+			wantEnv: []corev1.EnvVar{
+				{Name: "GOMEMLIMIT", Value: "3GiB"},
+				{Name: "TZ", Value: "UTC"},
+				{Name: "GOGC", Value: "200"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := tt.spec
+			if ok := setContainerEnv(&spec, env); ok != tt.wantOK {
+				t.Fatalf("setContainerEnv() = %v, want %v", ok, tt.wantOK)
+			}
+			if !tt.wantOK {
+				return
+			}
+
+			got := spec.Containers[tempoContainerIndex(&spec)].Env
+			if !reflect.DeepEqual(got, tt.wantEnv) {
+				t.Errorf("got env %v, want %v", got, tt.wantEnv)
+			}
+		})
+	}
+}
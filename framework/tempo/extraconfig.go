@@ -0,0 +1,111 @@
+package tempo
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// managedExtraConfigKeys returns the dotted Tempo config keys that are set from
+// the explicit settings of resources. Defaults, like the ingester's
+// max_block_duration of 10m, are not included and may be overridden.
+func managedExtraConfigKeys(resources *ResourceConfig) map[string]string {
+	keys := make(map[string]string)
+	if resources.Overrides != nil {
+		if resources.Overrides.MaxTracesPerUser != nil {
+			keys["overrides.defaults.ingestion.max_traces_per_user"] = "MaxTracesPerUser"
+		}
+		if ing := resources.Overrides.Ingester; ing != nil {
+			if ing.MaxBlockDuration != "" {
+				keys["ingester.max_block_duration"] = "Ingester.MaxBlockDuration"
+			}
+			if ing.TraceIdlePeriod != "" {
+				keys["ingester.trace_idle_period"] = "Ingester.TraceIdlePeriod"
+			}
+			if ing.FlushCheckPeriod != "" {
+				keys["ingester.flush_check_period"] = "Ingester.FlushCheckPeriod"
+			}
+			if ing.ConcurrentFlushes != nil {
+				keys["ingester.concurrent_flushes"] = "Ingester.ConcurrentFlushes"
+			}
+		}
+	}
+	if resources.MetricsGenerator != nil {
+		keys["metrics_generator.storage"] = "MetricsGenerator"
+		keys["overrides.defaults.metrics_generator.processors"] = "MetricsGenerator.Processors"
+	}
+	if block := resources.Block; block != nil {
+		if block.Version != "" {
+			keys["storage.trace.block.version"] = "Block.Version"
+		}
+		if block.Encoding != "" {
+			keys["storage.trace.block.v2_encoding"] = "Block.Encoding"
+		}
+		if block.BloomFilterFalsePositive != nil {
+			keys["storage.trace.block.bloom_filter_false_positive"] = "Block.BloomFilterFalsePositive"
+		}
+		if block.BloomFilterShardSizeBytes != nil {
+			keys["storage.trace.block.bloom_filter_shard_size_bytes"] = "Block.BloomFilterShardSizeBytes"
+		}
+	}
+	for _, f := range resources.ExtraFiles {
+		for key := range f.References {
+			keys[key] = fmt.Sprintf("the reference of extra config file %q", f.Name)
+		}
+	}
+	return keys
+}
+
+// validateExtraConfig checks that the raw extraConfig does not set a key that is
+// also set from another setting, where one would silently win
+func validateExtraConfig(resources *ResourceConfig) error {
+	if len(resources.ExtraConfig) == 0 {
+		return nil
+	}
+
+	managed := managedExtraConfigKeys(resources)
+	keys := make([]string, 0, len(managed))
+	for key := range managed {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if conflict, ok := findNestedKey(resources.ExtraConfig, strings.Split(key, ".")); ok {
+			return fmt.Errorf("extraConfig key %q conflicts with %s, which sets %q", conflict, managed[key], key)
+		}
+	}
+	return nil
+}
+
+// findNestedKey reports whether m sets the value at keys, either directly or by
+// setting one of its parents to a value that is not a map. It returns the dotted
+// key that is set.
+func findNestedKey(m map[string]interface{}, keys []string) (string, bool) {
+	for i, key := range keys {
+		value, ok := m[key]
+		if !ok {
+			return "", false
+		}
+		next, isMap := value.(map[string]interface{})
+		if i == len(keys)-1 || !isMap {
+			return strings.Join(keys[:i+1], "."), true
+		}
+		m = next
+	}
+	return "", false
+}
+
+// mergeExtraConfig merges src into dst: maps are merged recursively and other values
+// of src replace those of dst
+func mergeExtraConfig(dst, src map[string]interface{}) {
+	for key, value := range src {
+		srcMap, srcIsMap := value.(map[string]interface{})
+		dstMap, dstIsMap := dst[key].(map[string]interface{})
+		if srcIsMap && dstIsMap {
+			mergeExtraConfig(dstMap, srcMap)
+			continue
+		}
+		dst[key] = value
+	}
+}
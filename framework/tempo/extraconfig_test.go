@@ -0,0 +1,140 @@
+package tempo
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidateExtraConfig(t *testing.T) {
+	maxTraces := 0
+	tests := []struct {
+		name      string
+		resources *ResourceConfig
+		wantErr   string
+	}{
+		{
+			name: "overrides the default max_block_duration",
+			resources: &ResourceConfig{ExtraConfig: map[string]interface{}{
+				"ingester": map[string]interface{}{"max_block_duration": "5m"},
+			}},
+		},
+		{
+			name: "other ingester key",
+			resources: &ResourceConfig{
+				Overrides:   &TempoOverrides{Ingester: &IngesterConfig{MaxBlockDuration: "30m"}},
+				ExtraConfig: map[string]interface{}{"ingester": map[string]interface{}{"lifecycler": map[string]interface{}{}}},
+			},
+		},
+		{
+			name: "ingester field",
+			resources: &ResourceConfig{
+				Overrides:   &TempoOverrides{Ingester: &IngesterConfig{MaxBlockDuration: "30m"}},
+				ExtraConfig: map[string]interface{}{"ingester": map[string]interface{}{"max_block_duration": "5m"}},
+			},
+			wantErr: `"ingester.max_block_duration" conflicts with Ingester.MaxBlockDuration`,
+		},
+		{
+			name: "replaces a parent",
+			resources: &ResourceConfig{
+				Overrides:   &TempoOverrides{MaxTracesPerUser: &maxTraces},
+				ExtraConfig: map[string]interface{}{"overrides": map[string]interface{}{"defaults": "none"}},
+			},
+			wantErr: `"overrides.defaults" conflicts with MaxTracesPerUser`,
+		},
+		{
+			name: "extra file reference",
+			resources: &ResourceConfig{
+				ExtraFiles: []ExtraConfigFile{{
+					Name:       "per-tenant",
+					Files:      map[string]string{"overrides.yaml": ""},
+					References: map[string]string{"overrides.per_tenant_override_config": "overrides.yaml"},
+				}},
+				ExtraConfig: map[string]interface{}{"overrides": map[string]interface{}{"per_tenant_override_config": "/tmp/o.yaml"}},
+			},
+			wantErr: `extra config file "per-tenant"`,
+		},
+		{
+			name: "metrics-generator processors",
+			resources: &ResourceConfig{
+				MetricsGenerator: &MetricsGeneratorConfig{},
+				ExtraConfig: map[string]interface{}{"overrides": map[string]interface{}{"defaults": map[string]interface{}{
+					"metrics_generator": map[string]interface{}{"processors": []interface{}{"local-blocks"}},
+				}}},
+			},
+			wantErr: `conflicts with MetricsGenerator.Processors`,
+		},
+		{
+			name: "other block key",
+			resources: &ResourceConfig{
+				Block: &BlockConfig{Version: "vParquet3"},
+				ExtraConfig: map[string]interface{}{"storage": map[string]interface{}{"trace": map[string]interface{}{
+					"block": map[string]interface{}{"parquet_row_group_size_bytes": 50000000},
+				}}},
+			},
+		},
+		{
+			name: "block version",
+			resources: &ResourceConfig{
+				Block: &BlockConfig{Version: "vParquet3"},
+				ExtraConfig: map[string]interface{}{"storage": map[string]interface{}{"trace": map[string]interface{}{
+					"block": map[string]interface{}{"version": "vParquet4"},
+				}}},
+			},
+			wantErr: `conflicts with Block.Version`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateExtraConfig(tt.resources)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestMergeExtraConfig(t *testing.T) {
+	dst := map[string]interface{}{
+		"ingester": map[string]interface{}{"max_block_duration": "10m"},
+		"server":   "replaced",
+	}
+	mergeExtraConfig(dst, map[string]interface{}{
+		"ingester": map[string]interface{}{"max_block_duration": "5m", "trace_idle_period": "2s"},
+		"server":   map[string]interface{}{"log_level": "debug"},
+	})
+
+	expected := map[string]interface{}{
+		"ingester": map[string]interface{}{"max_block_duration": "5m", "trace_idle_period": "2s"},
+		"server":   map[string]interface{}{"log_level": "debug"},
+	}
+	if !reflect.DeepEqual(dst, expected) {
+		t.Errorf("got %v, want %v", dst, expected)
+	}
+}
+
+func TestBuildTempoMonolithicCR_ExtraConfig(t *testing.T) {
+	cr := buildTempoMonolithicCR("perf", &ResourceConfig{ExtraConfig: map[string]interface{}{
+		"querier": map[string]interface{}{"max_concurrent_queries": 40},
+	}})
+
+	var extraConfig map[string]interface{}
+	if err := json.Unmarshal(cr.Spec.ExtraConfig.Tempo.Raw, &extraConfig); err != nil {
+		t.Fatalf("invalid extraConfig: %v", err)
+	}
+	expected := map[string]interface{}{
+		"ingester": map[string]interface{}{"max_block_duration": "10m"},
+		"querier":  map[string]interface{}{"max_concurrent_queries": float64(40)},
+	}
+	if !reflect.DeepEqual(extraConfig, expected) {
+		t.Errorf("got extraConfig %v, want %v", extraConfig, expected)
+	}
+}
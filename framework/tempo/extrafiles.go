@@ -0,0 +1,339 @@
+package tempo
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/gvr"
+	"github.com/redhat/perf-tests-tempo/test/framework/wait"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// DefaultExtraConfigMountDir is the directory under which extra config files are mounted
+const DefaultExtraConfigMountDir = "/conf/extra"
+
+// tempoContainerName is the name of the Tempo container in operator-managed workloads
+const tempoContainerName = "tempo"
+
+// ExtraConfigFile is a set of files stored in a ConfigMap or Secret, mounted into
+// the Tempo containers and referenced from the Tempo extraConfig.
+//
+// The Tempo Operator has no API for extra volumes, so once the workloads exist the
+// Tempo CR is switched to unmanaged and the workloads are patched to mount the files.
+// This disables operator reconciliation for the whole CR: later changes to the CR
+// are not rolled out and deleted workloads are not recreated until it is managed again.
+type ExtraConfigFile struct {
+	// Name is the name of the ConfigMap or Secret
+	Name string
+
+	// Secret stores the files in a Secret instead of a ConfigMap
+	Secret bool
+
+	// Files maps file names to their content
+	Files map[string]string
+
+	// MountPath is the directory the files are mounted at.
+	// Default: /conf/extra/<Name>
+	MountPath string
+
+	// References maps dotted Tempo config keys to a file name in Files.
+	// The key is set to the mounted file path in extraConfig.
+	// Example: {"overrides.per_tenant_override_config": "overrides.yaml"}
+	References map[string]string
+}
+
+// GetMountPath returns the directory the files are mounted at
+func (e *ExtraConfigFile) GetMountPath() string {
+	if e.MountPath != "" {
+		return e.MountPath
+	}
+	return path.Join(DefaultExtraConfigMountDir, e.Name)
+}
+
+// validateExtraConfigFiles checks names and references of extra config files
+func validateExtraConfigFiles(files []ExtraConfigFile) error {
+	seen := make(map[string]bool)
+	for _, f := range files {
+		if f.Name == "" {
+			return fmt.Errorf("extra config file name is required")
+		}
+		if seen[f.Name] {
+			return fmt.Errorf("duplicate extra config file name %q", f.Name)
+		}
+		seen[f.Name] = true
+
+		if len(f.Files) == 0 {
+			return fmt.Errorf("extra config file %q has no files", f.Name)
+		}
+		for key, file := range f.References {
+			if _, ok := f.Files[file]; !ok {
+				return fmt.Errorf("extra config file %q: reference %q points to unknown file %q", f.Name, key, file)
+			}
+		}
+	}
+	return nil
+}
+
+// CreateExtraConfigFiles creates a ConfigMap or Secret for each extra config file.
+// The created resources are labeled and tracked for cleanup.
+func CreateExtraConfigFiles(fw FrameworkOperations, files []ExtraConfigFile) error {
+	if err := validateExtraConfigFiles(files); err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		meta := metav1.ObjectMeta{
+			Name:      f.Name,
+			Namespace: fw.Namespace(),
+			Labels:    fw.GetManagedLabels(),
+		}
+
+		var err error
+		var resourceGVR schema.GroupVersionResource
+		if f.Secret {
+			resourceGVR = gvr.Secret
+			secret := &corev1.Secret{
+				ObjectMeta: meta,
+				StringData: f.Files,
+				Type:       corev1.SecretTypeOpaque,
+			}
+			_, err = fw.Client().CoreV1().Secrets(fw.Namespace()).Create(fw.Context(), secret, metav1.CreateOptions{})
+		} else {
+			resourceGVR = gvr.ConfigMap
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: meta,
+				Data:       f.Files,
+			}
+			_, err = fw.Client().CoreV1().ConfigMaps(fw.Namespace()).Create(fw.Context(), configMap, metav1.CreateOptions{})
+		}
+		if err != nil && !apierrors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create %s %s: %w", resourceGVR.Resource, f.Name, err)
+		}
+
+		fw.TrackCR(resourceGVR, fw.Namespace(), f.Name)
+		fw.Logger().Info("Created extra config files", "kind", resourceGVR.Resource, "name", f.Name, "files", len(f.Files))
+	}
+
+	return nil
+}
+
+// applyExtraFileReferences sets the referenced Tempo config keys to the mounted file paths
+func applyExtraFileReferences(extraConfig map[string]interface{}, files []ExtraConfigFile) {
+	for _, f := range files {
+		for key, file := range f.References {
+			setNestedValue(extraConfig, strings.Split(key, "."), path.Join(f.GetMountPath(), file))
+		}
+	}
+}
+
+// setNestedValue sets a value in a nested map, creating intermediate maps as needed
+func setNestedValue(m map[string]interface{}, keys []string, value interface{}) {
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := m[key].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			m[key] = next
+		}
+		m = next
+	}
+	m[keys[len(keys)-1]] = value
+}
+
+// tempoWorkloads returns the names of the Deployments and StatefulSets running a
+// Tempo container that the operator creates for a CR
+func tempoWorkloads(crGVR schema.GroupVersionResource, crName string) (deployments, statefulSets []string) {
+	if crGVR == gvr.TempoStack {
+		for _, component := range []string{"distributor", "querier", "query-frontend", "compactor"} {
+			deployments = append(deployments, fmt.Sprintf("tempo-%s-%s", crName, component))
+		}
+		return deployments, []string{fmt.Sprintf("tempo-%s-ingester", crName)}
+	}
+	return nil, []string{"tempo-" + crName}
+}
+
+// MountExtraConfigFiles mounts extra config files into all Tempo workloads of a CR.
+// The CR is switched to unmanaged first so the operator does not revert the patch,
+// which stops the operator from reconciling the CR for the rest of the run.
+func MountExtraConfigFiles(fw FrameworkOperations, crGVR schema.GroupVersionResource, crName string, files []ExtraConfigFile) error {
+	if len(files) == 0 {
+		return nil
+	}
+
+	return patchTempoWorkloads(fw, crGVR, crName, "mount extra config files into", "Mounted extra config files",
+		func(spec *corev1.PodSpec) bool {
+			return addExtraFileVolumes(spec, files)
+		})
+}
+
+// patchTempoWorkloads applies patch to the pod template of every Tempo workload of a
+// CR and updates the workloads for which it returns true. The CR is switched to
+// unmanaged first so the operator does not revert the change. action completes the
+// error message ("failed to <action> deployment x") and done is logged per workload.
+func patchTempoWorkloads(fw FrameworkOperations, crGVR schema.GroupVersionResource, crName, action, done string, patch func(*corev1.PodSpec) bool) error {
+	selector := fmt.Sprintf("app.kubernetes.io/instance=%s", crName)
+
+	// Wait for the operator to create every workload, otherwise the ones created
+	// after the CR is unmanaged would not be patched
+	if err := waitForWorkloads(fw, crGVR, crName); err != nil {
+		return err
+	}
+
+	if err := setUnmanaged(fw, crGVR, crName); err != nil {
+		return err
+	}
+
+	// Re-list after the operator stopped reconciling to avoid update conflicts
+	deployList, err := fw.Client().AppsV1().Deployments(fw.Namespace()).List(fw.Context(), metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return fmt.Errorf("failed to list Tempo deployments: %w", err)
+	}
+	stsList, err := fw.Client().AppsV1().StatefulSets(fw.Namespace()).List(fw.Context(), metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return fmt.Errorf("failed to list Tempo statefulsets: %w", err)
+	}
+	deployments := deployList.Items
+	statefulSets := stsList.Items
+
+	for i := range deployments {
+		d := &deployments[i]
+		if !patch(&d.Spec.Template.Spec) {
+			continue
+		}
+		if _, err := fw.Client().AppsV1().Deployments(fw.Namespace()).Update(fw.Context(), d, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to %s deployment %s: %w", action, d.Name, err)
+		}
+		fw.Logger().Info(done, "deployment", d.Name)
+	}
+
+	for i := range statefulSets {
+		s := &statefulSets[i]
+		if !patch(&s.Spec.Template.Spec) {
+			continue
+		}
+		if _, err := fw.Client().AppsV1().StatefulSets(fw.Namespace()).Update(fw.Context(), s, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to %s statefulset %s: %w", action, s.Name, err)
+		}
+		fw.Logger().Info(done, "statefulset", s.Name)
+	}
+
+	return nil
+}
+
+// waitForWorkloads waits until the operator created every workload of a Tempo CR
+func waitForWorkloads(fw FrameworkOperations, crGVR schema.GroupVersionResource, crName string) error {
+	expectedDeployments, expectedStatefulSets := tempoWorkloads(crGVR, crName)
+
+	ctx := fw.Context()
+	err := wait.ForCondition(ctx, wait.PollInterval(fw), 300*time.Second, func() (bool, error) {
+		for _, name := range expectedDeployments {
+			if _, err := fw.Client().AppsV1().Deployments(fw.Namespace()).Get(ctx, name, metav1.GetOptions{}); err != nil {
+				return false, nil
+			}
+		}
+		for _, name := range expectedStatefulSets {
+			if _, err := fw.Client().AppsV1().StatefulSets(fw.Namespace()).Get(ctx, name, metav1.GetOptions{}); err != nil {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		return fmt.Errorf("timeout waiting for Tempo workloads of %s: %w", crName, err)
+	}
+
+	return nil
+}
+
+// setUnmanaged switches a Tempo CR to the Unmanaged management state.
+// The operator then ignores the CR entirely until it is switched back to Managed.
+func setUnmanaged(fw FrameworkOperations, crGVR schema.GroupVersionResource, crName string) error {
+	field := "management"
+	if crGVR == gvr.TempoStack {
+		field = "managementState"
+	}
+
+	patch := []byte(fmt.Sprintf(`{"spec":{%q:"Unmanaged"}}`, field))
+	_, err := fw.DynamicClient().Resource(crGVR).Namespace(fw.Namespace()).Patch(
+		fw.Context(), crName, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to set %s %s to unmanaged: %w", crGVR.Resource, crName, err)
+	}
+
+	return nil
+}
+
+// tempoContainerIndex returns the index of the Tempo container of a pod, or -1
+func tempoContainerIndex(spec *corev1.PodSpec) int {
+	for i, c := range spec.Containers {
+		if c.Name == tempoContainerName {
+			return i
+		}
+	}
+	return -1
+}
+
+// addExtraFileVolumes adds volumes and Tempo container mounts for extra config files.
+// Returns false if the pod has no Tempo container.
+func addExtraFileVolumes(spec *corev1.PodSpec, files []ExtraConfigFile) bool {
+	containerIdx := tempoContainerIndex(spec)
+	if containerIdx < 0 {
+		return false
+	}
+
+	for _, f := range files {
+		volumeName := "extra-" + f.Name
+
+		volume := corev1.Volume{Name: volumeName}
+		if f.Secret {
+			volume.VolumeSource = corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{SecretName: f.Name},
+			}
+		} else {
+			volume.VolumeSource = corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: f.Name},
+				},
+			}
+		}
+
+		if !hasVolume(spec.Volumes, volumeName) {
+			spec.Volumes = append(spec.Volumes, volume)
+		}
+
+		container := &spec.Containers[containerIdx]
+		if !hasVolumeMount(container.VolumeMounts, volumeName) {
+			container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+				Name:      volumeName,
+				MountPath: f.GetMountPath(),
+				ReadOnly:  true,
+			})
+		}
+	}
+
+	return true
+}
+
+func hasVolume(volumes []corev1.Volume, name string) bool {
+	for _, v := range volumes {
+		if v.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func hasVolumeMount(mounts []corev1.VolumeMount, name string) bool {
+	for _, m := range mounts {
+		if m.Name == name {
+			return true
+		}
+	}
+	return false
+}
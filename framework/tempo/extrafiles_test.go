@@ -0,0 +1,237 @@
+package tempo
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestValidateExtraConfigFiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   []ExtraConfigFile
+		wantErr string
+	}{
+		{
+			name:  "valid",
+			files: []ExtraConfigFile{{Name: "overrides", Files: map[string]string{"o.yaml": "x"}, References: map[string]string{"overrides.per_tenant_override_config": "o.yaml"}}},
+		},
+		{
+			name:    "missing name",
+			files:   []ExtraConfigFile{{Files: map[string]string{"o.yaml": "x"}}},
+			wantErr: "name is required",
+		},
+		{
+			name: "duplicate name",
+			files: []ExtraConfigFile{
+				{Name: "overrides", Files: map[string]string{"o.yaml": "x"}},
+				{Name: "overrides", Files: map[string]string{"p.yaml": "y"}},
+			},
+			wantErr: "duplicate",
+		},
+		{
+			name:    "no files",
+			files:   []ExtraConfigFile{{Name: "overrides"}},
+			wantErr: "has no files",
+		},
+		{
+			name:    "unknown reference",
+			files:   []ExtraConfigFile{{Name: "overrides", Files: map[string]string{"o.yaml": "x"}, References: map[string]string{"a.b": "missing.yaml"}}},
+			wantErr: "unknown file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateExtraConfigFiles(tt.files)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestSetNestedValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		initial  map[string]interface{}
+		keys     []string
+		expected map[string]interface{}
+	}{
+		{
+			name:     "top level",
+			initial:  map[string]interface{}{},
+			keys:     []string{"a"},
+			expected: map[string]interface{}{"a": "v"},
+		},
+		{
+			name:     "creates intermediate maps",
+			initial:  map[string]interface{}{},
+			keys:     []string{"a", "b", "c"},
+			expected: map[string]interface{}{"a": map[string]interface{}{"b": map[string]interface{}{"c": "v"}}},
+		},
+		{
+			name:     "keeps sibling keys",
+			initial:  map[string]interface{}{"a": map[string]interface{}{"x": 1}},
+			keys:     []string{"a", "b"},
+			expected: map[string]interface{}{"a": map[string]interface{}{"x": 1, "b": "v"}},
+		},
+		{
+			name:     "replaces non-map intermediate",
+			initial:  map[string]interface{}{"a": "scalar"},
+			keys:     []string{"a", "b"},
+			expected: map[string]interface{}{"a": map[string]interface{}{"b": "v"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setNestedValue(tt.initial, tt.keys, "v")
+			if !reflect.DeepEqual(tt.initial, tt.expected) {
+				t.Errorf("got %v, want %v", tt.initial, tt.expected)
+			}
+		})
+	}
+}
+
+func TestApplyExtraFileReferences(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    []ExtraConfigFile
+		expected map[string]interface{}
+	}{
+		{
+			name:     "no references",
+			files:    []ExtraConfigFile{{Name: "overrides", Files: map[string]string{"o.yaml": "x"}}},
+			expected: map[string]interface{}{},
+		},
+		{
+			name: "default mount path",
+			files: []ExtraConfigFile{{
+				Name:       "overrides",
+				Files:      map[string]string{"o.yaml": "x"},
+				References: map[string]string{"overrides.per_tenant_override_config": "o.yaml"},
+			}},
+			expected: map[string]interface{}{
+				"overrides": map[string]interface{}{"per_tenant_override_config": "/conf/extra/overrides/o.yaml"},
+			},
+		},
+		{
+			name: "custom mount path",
+			files: []ExtraConfigFile{{
+				Name:       "ca",
+				MountPath:  "/etc/tls",
+				Files:      map[string]string{"ca.crt": "x"},
+				References: map[string]string{"server.tls_ca_path": "ca.crt"},
+			}},
+			expected: map[string]interface{}{
+				"server": map[string]interface{}{"tls_ca_path": "/etc/tls/ca.crt"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			extraConfig := map[string]interface{}{}
+			applyExtraFileReferences(extraConfig, tt.files)
+			if !reflect.DeepEqual(extraConfig, tt.expected) {
+				t.Errorf("got %v, want %v", extraConfig, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAddExtraFileVolumes(t *testing.T) {
+	files := []ExtraConfigFile{
+		{Name: "overrides", Files: map[string]string{"o.yaml": "x"}},
+		{Name: "creds", Secret: true, MountPath: "/etc/creds", Files: map[string]string{"key": "x"}},
+	}
+
+	tests := []struct {
+		name       string
+		spec       corev1.PodSpec
+		wantOK     bool
+		wantMounts int
+	}{
+		{
+			name:   "no tempo container",
+			spec:   corev1.PodSpec{Containers: []corev1.Container{{Name: "tempo-gateway"}}},
+			wantOK: false,
+		},
+		{
+			name:       "tempo container",
+			spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "sidecar"}, {Name: "tempo"}}},
+			wantOK:     true,
+			wantMounts: 2,
+		},
+		{
+			name: "already mounted",
+			spec: corev1.PodSpec{
+				Volumes:    []corev1.Volume{{Name: "extra-overrides"}},
+				Containers: []corev1.Container{{Name: "tempo", VolumeMounts: []corev1.VolumeMount{{Name: "extra-overrides", MountPath: "/conf/extra/overrides"}}}},
+			},
+			wantOK:     true,
+			wantMounts: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := tt.spec
+			if ok := addExtraFileVolumes(&spec, files); ok != tt.wantOK {
+				t.Fatalf("addExtraFileVolumes() = %v, want %v", ok, tt.wantOK)
+			}
+			if !tt.wantOK {
+				if len(spec.Volumes) != 0 {
+					t.Errorf("expected no volumes, got %v", spec.Volumes)
+				}
+				return
+			}
+
+			if len(spec.Volumes) != 2 {
+				t.Fatalf("expected 2 volumes, got %d", len(spec.Volumes))
+			}
+			if spec.Volumes[1].Secret == nil || spec.Volumes[1].Secret.SecretName != "creds" {
+				t.Errorf("expected secret volume for creds, got %+v", spec.Volumes[1])
+			}
+
+			var mounts []corev1.VolumeMount
+			for _, c := range spec.Containers {
+				if c.Name == "tempo" {
+					mounts = c.VolumeMounts
+				} else if len(c.VolumeMounts) > 0 {
+					t.Errorf("container %s should not get mounts", c.Name)
+				}
+			}
+			if len(mounts) != tt.wantMounts {
+				t.Fatalf("expected %d mounts, got %v", tt.wantMounts, mounts)
+			}
+			if mounts[1].MountPath != "/etc/creds" || !mounts[1].ReadOnly {
+				t.Errorf("unexpected creds mount %+v", mounts[1])
+			}
+		})
+	}
+}
+
+func TestTempoWorkloads(t *testing.T) {
+	deployments, statefulSets := tempoWorkloads(TempoStackGVR, "tempostack")
+	if len(deployments) != 4 || deployments[2] != "tempo-tempostack-query-frontend" {
+		t.Errorf("unexpected TempoStack deployments %v", deployments)
+	}
+	if !reflect.DeepEqual(statefulSets, []string{"tempo-tempostack-ingester"}) {
+		t.Errorf("unexpected TempoStack statefulsets %v", statefulSets)
+	}
+
+	deployments, statefulSets = tempoWorkloads(TempoMonolithicGVR, "simplest")
+	if len(deployments) != 0 || !reflect.DeepEqual(statefulSets, []string{"tempo-simplest"}) {
+		t.Errorf("unexpected TempoMonolithic workloads %v %v", deployments, statefulSets)
+	}
+}
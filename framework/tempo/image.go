@@ -0,0 +1,36 @@
+package tempo
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// SetTempoImage sets the image of the Tempo container in all Tempo workloads of a
+// CR. It is used for TempoMonolithic, which has no image override; like SetTempoEnv
+// it switches the CR to unmanaged for the rest of the run.
+func SetTempoImage(fw FrameworkOperations, crGVR schema.GroupVersionResource, crName, image string) error {
+	if image == "" {
+		return nil
+	}
+
+	if err := patchTempoWorkloads(fw, crGVR, crName, "set image of", "Set Tempo image",
+		func(spec *corev1.PodSpec) bool {
+			return setContainerImage(spec, image)
+		}); err != nil {
+		return err
+	}
+
+	fw.Logger().Info("Tempo image pinned", "image", image)
+	return nil
+}
+
+// setContainerImage sets the image of the Tempo container. Returns false if the pod
+// has no Tempo container or already runs image.
+func setContainerImage(spec *corev1.PodSpec, image string) bool {
+	containerIdx := tempoContainerIndex(spec)
+	if containerIdx < 0 || spec.Containers[containerIdx].Image == image {
+		return false
+	}
+	spec.Containers[containerIdx].Image = image
+	return true
+}
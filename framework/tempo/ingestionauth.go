@@ -0,0 +1,257 @@
+package tempo
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"time"
+
+	tempoapi "github.com/grafana/tempo-operator/api/tempo/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Ingestion authentication modes of the OTel Collector (must match otel package)
+const (
+	// IngestionAuthSAToken sends the projected ServiceAccount token to the gateway
+	IngestionAuthSAToken = "sa-token"
+	// IngestionAuthStaticToken sends a long-lived ServiceAccount token to the gateway
+	IngestionAuthStaticToken = "static-token"
+	// IngestionAuthMTLS sends to Tempo directly with a client certificate
+	IngestionAuthMTLS = "mtls"
+	// IngestionAuthNone sends to Tempo directly in plaintext
+	IngestionAuthNone = "none"
+)
+
+// Names of the ingestion mTLS resources (the client secret must match otel package)
+const (
+	// IngestionCAConfigMapName holds the CA that signed both ingestion certificates
+	IngestionCAConfigMapName = "tempo-ingestion-ca"
+	// IngestionServerTLSSecretName holds the serving certificate of the Tempo receiver
+	IngestionServerTLSSecretName = "tempo-ingestion-tls"
+	// IngestionClientTLSSecretName holds the client certificate of the OTel Collector
+	// and the CA (ca.crt) to verify Tempo with
+	IngestionClientTLSSecretName = "tempo-ingestion-client-tls"
+)
+
+// ingestionCertValidity covers the longest soak runs
+const ingestionCertValidity = 30 * 24 * time.Hour
+
+// UsesGateway reports whether Tempo is deployed with the multitenancy gateway for
+// an ingestion authentication mode
+func UsesGateway(auth string) bool {
+	return auth != IngestionAuthMTLS && auth != IngestionAuthNone
+}
+
+// validateIngestionAuth checks the ingestion authentication against the Tempo variant
+func validateIngestionAuth(variant, auth string) error {
+	switch auth {
+	case "", IngestionAuthSAToken, IngestionAuthStaticToken:
+		return nil
+	case IngestionAuthMTLS, IngestionAuthNone:
+		// The gateway of a TempoStack holds the client certificates of its
+		// internally encrypted components; without it queries are refused
+		if variant != "monolithic" {
+			return fmt.Errorf("ingestion auth %q is only supported with the monolithic variant", auth)
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid ingestion auth %q (must be 'sa-token', 'static-token', 'mtls' or 'none')", auth)
+	}
+}
+
+// buildMonolithicIngestionSpec returns the OTLP receivers of a TempoMonolithic without
+// the gateway. With mTLS the gRPC receiver requires client certificates signed by
+// the ingestion CA; OTLP/HTTP stays plaintext, as the collector only exports gRPC.
+func buildMonolithicIngestionSpec(auth string) *tempoapi.MonolithicIngestionSpec {
+	grpc := &tempoapi.MonolithicIngestionOTLPProtocolsGRPCSpec{Enabled: true}
+	if auth == IngestionAuthMTLS {
+		grpc.TLS = &tempoapi.TLSSpec{
+			Enabled: true,
+			CA:      IngestionCAConfigMapName,
+			Cert:    IngestionServerTLSSecretName,
+		}
+	}
+	return &tempoapi.MonolithicIngestionSpec{
+		OTLP: &tempoapi.MonolithicIngestionOTLPSpec{
+			GRPC: grpc,
+			HTTP: &tempoapi.MonolithicIngestionOTLPProtocolsHTTPSpec{Enabled: true},
+		},
+	}
+}
+
+// CreateIngestionTLS generates a CA with a serving certificate for the TempoMonolithic
+// service and a client certificate for the OTel Collector, and stores them in the
+// resources referenced by the CR and the collector
+func CreateIngestionTLS(fw FrameworkOperations, crName string) error {
+	service := fmt.Sprintf("tempo-%s", crName)
+	certs, err := generateIngestionCerts([]string{
+		service,
+		fmt.Sprintf("%s.%s.svc", service, fw.Namespace()),
+		fmt.Sprintf("%s.%s.svc.cluster.local", service, fw.Namespace()),
+	}, ingestionCertValidity)
+	if err != nil {
+		return err
+	}
+
+	caConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      IngestionCAConfigMapName,
+			Namespace: fw.Namespace(),
+			Labels:    fw.GetManagedLabels(),
+		},
+		// The operator reads the CA from the service-ca.crt key
+		Data: map[string]string{"service-ca.crt": string(certs.CA)},
+	}
+	configMaps := fw.Client().CoreV1().ConfigMaps(fw.Namespace())
+	if _, err := configMaps.Create(fw.Context(), caConfigMap, metav1.CreateOptions{}); err != nil {
+		if !apierrors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create ingestion CA ConfigMap: %w", err)
+		}
+		if _, err := configMaps.Update(fw.Context(), caConfigMap, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to update ingestion CA ConfigMap: %w", err)
+		}
+	}
+
+	secrets := []*corev1.Secret{
+		tlsSecret(fw, IngestionServerTLSSecretName, certs.ServerCert, certs.ServerKey, nil),
+		tlsSecret(fw, IngestionClientTLSSecretName, certs.ClientCert, certs.ClientKey, certs.CA),
+	}
+	for _, secret := range secrets {
+		client := fw.Client().CoreV1().Secrets(fw.Namespace())
+		if _, err := client.Create(fw.Context(), secret, metav1.CreateOptions{}); err != nil {
+			if !apierrors.IsAlreadyExists(err) {
+				return fmt.Errorf("failed to create secret %s: %w", secret.Name, err)
+			}
+			if _, err := client.Update(fw.Context(), secret, metav1.UpdateOptions{}); err != nil {
+				return fmt.Errorf("failed to update secret %s: %w", secret.Name, err)
+			}
+		}
+	}
+
+	fw.Logger().Info("Created ingestion mTLS certificates", "ca", IngestionCAConfigMapName,
+		"server", IngestionServerTLSSecretName, "client", IngestionClientTLSSecretName)
+	return nil
+}
+
+// tlsSecret builds a kubernetes.io/tls Secret, with the CA under ca.crt when set
+func tlsSecret(fw FrameworkOperations, name string, cert, key, ca []byte) *corev1.Secret {
+	data := map[string][]byte{
+		corev1.TLSCertKey:       cert,
+		corev1.TLSPrivateKeyKey: key,
+	}
+	if ca != nil {
+		data["ca.crt"] = ca
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: fw.Namespace(),
+			Labels:    fw.GetManagedLabels(),
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: data,
+	}
+}
+
+// ingestionCerts are the PEM encoded certificates and keys of ingestion mTLS
+type ingestionCerts struct {
+	CA         []byte
+	ServerCert []byte
+	ServerKey  []byte
+	ClientCert []byte
+	ClientKey  []byte
+}
+
+// generateIngestionCerts creates a self-signed CA and signs a serving certificate for
+// hosts and a client certificate with it
+func generateIngestionCerts(hosts []string, validFor time.Duration) (*ingestionCerts, error) {
+	// Backdated to tolerate clock skew between the runner and the cluster
+	notBefore := time.Now().Add(-time.Hour)
+	notAfter := time.Now().Add(validFor)
+
+	caTemplate := &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "tempo-perf-ingestion-ca"},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caPEM, _, caCert, caKey, err := signCert(caTemplate, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ingestion CA: %w", err)
+	}
+
+	serverTemplate := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: hosts[0]},
+		DNSNames:    hosts,
+		NotBefore:   notBefore,
+		NotAfter:    notAfter,
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	serverPEM, serverKeyPEM, _, _, err := signCert(serverTemplate, caCert, caKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ingestion serving certificate: %w", err)
+	}
+
+	clientTemplate := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "otel-collector"},
+		NotBefore:   notBefore,
+		NotAfter:    notAfter,
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	clientPEM, clientKeyPEM, _, _, err := signCert(clientTemplate, caCert, caKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ingestion client certificate: %w", err)
+	}
+
+	return &ingestionCerts{
+		CA:         caPEM,
+		ServerCert: serverPEM,
+		ServerKey:  serverKeyPEM,
+		ClientCert: clientPEM,
+		ClientKey:  clientKeyPEM,
+	}, nil
+}
+
+// signCert creates a key for template and signs it with parentKey, or self-signs it
+// when parent is nil
+func signCert(template, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (certPEM, keyPEM []byte, cert *x509.Certificate, key *ecdsa.PrivateKey, err error) {
+	key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	template.SerialNumber, err = rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	cert, err = x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return certPEM, keyPEM, cert, key, nil
+}
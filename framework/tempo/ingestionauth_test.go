@@ -0,0 +1,85 @@
+package tempo
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"testing"
+	"time"
+)
+
+func TestValidateIngestionAuth(t *testing.T) {
+	tests := []struct {
+		name    string
+		variant string
+		auth    string
+		wantErr bool
+	}{
+		{name: "default", variant: "stack"},
+		{name: "static token stack", variant: "stack", auth: IngestionAuthStaticToken},
+		{name: "mtls monolithic", variant: "monolithic", auth: IngestionAuthMTLS},
+		{name: "none monolithic", variant: "monolithic", auth: IngestionAuthNone},
+		{name: "mtls stack", variant: "stack", auth: IngestionAuthMTLS, wantErr: true},
+		{name: "unknown", variant: "monolithic", auth: "oidc", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateIngestionAuth(tt.variant, tt.auth)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateIngestionAuth() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBuildTempoMonolithicCR_IngestionAuth(t *testing.T) {
+	cr := buildTempoMonolithicCR("ns", &ResourceConfig{IngestionAuth: IngestionAuthStaticToken})
+	if cr.Spec.Multitenancy == nil || cr.Spec.Ingestion != nil {
+		t.Errorf("expected the gateway for static tokens, got multitenancy %+v, ingestion %+v", cr.Spec.Multitenancy, cr.Spec.Ingestion)
+	}
+
+	cr = buildTempoMonolithicCR("ns", &ResourceConfig{IngestionAuth: IngestionAuthNone})
+	if cr.Spec.Multitenancy != nil {
+		t.Errorf("expected no multitenancy without auth")
+	}
+	if grpc := cr.Spec.Ingestion.OTLP.GRPC; !grpc.Enabled || grpc.TLS != nil {
+		t.Errorf("expected plaintext OTLP/gRPC, got %+v", grpc)
+	}
+
+	cr = buildTempoMonolithicCR("ns", &ResourceConfig{IngestionAuth: IngestionAuthMTLS})
+	tlsSpec := cr.Spec.Ingestion.OTLP.GRPC.TLS
+	if tlsSpec == nil || !tlsSpec.Enabled || tlsSpec.CA != IngestionCAConfigMapName || tlsSpec.Cert != IngestionServerTLSSecretName {
+		t.Errorf("expected OTLP/gRPC TLS with the ingestion CA and certificate, got %+v", tlsSpec)
+	}
+}
+
+func TestGenerateIngestionCerts(t *testing.T) {
+	host := "tempo-simplest.ns.svc.cluster.local"
+	certs, err := generateIngestionCerts([]string{"tempo-simplest", host}, time.Hour)
+	if err != nil {
+		t.Fatalf("generateIngestionCerts() error = %v", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(certs.CA) {
+		t.Fatalf("failed to parse CA")
+	}
+
+	server, err := tls.X509KeyPair(certs.ServerCert, certs.ServerKey)
+	if err != nil {
+		t.Fatalf("invalid serving key pair: %v", err)
+	}
+	serverCert, _ := x509.ParseCertificate(server.Certificate[0])
+	if _, err := serverCert.Verify(x509.VerifyOptions{DNSName: host, Roots: pool}); err != nil {
+		t.Errorf("serving certificate does not verify for %s: %v", host, err)
+	}
+
+	client, err := tls.X509KeyPair(certs.ClientCert, certs.ClientKey)
+	if err != nil {
+		t.Fatalf("invalid client key pair: %v", err)
+	}
+	clientCert, _ := x509.ParseCertificate(client.Certificate[0])
+	opts := x509.VerifyOptions{Roots: pool, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}}
+	if _, err := clientCert.Verify(opts); err != nil {
+		t.Errorf("client certificate does not verify: %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package tempo
+
+import "fmt"
+
+// MetricsGeneratorProcessors are the metrics-generator processors that can be enabled
+var MetricsGeneratorProcessors = []string{"span-metrics", "service-graphs"}
+
+// metricsGeneratorWALPath is where the metrics-generator keeps the WAL of its
+// series, on the volume TempoMonolithic mounts at /var/tempo
+const metricsGeneratorWALPath = "/var/tempo/generator/wal"
+
+// validateMetricsGenerator checks the metrics-generator settings for a variant
+func validateMetricsGenerator(variant string, cfg *MetricsGeneratorConfig) error {
+	if cfg == nil {
+		return nil
+	}
+	// The operator deploys no metrics-generator component for TempoStack, while
+	// the single process of TempoMonolithic runs it once it is configured
+	if variant != "monolithic" {
+		return fmt.Errorf("the metrics-generator is only supported with the monolithic variant")
+	}
+	for _, processor := range cfg.Processors {
+		if !isMetricsGeneratorProcessor(processor) {
+			return fmt.Errorf("invalid metrics-generator processor %q (must be one of %v)", processor, MetricsGeneratorProcessors)
+		}
+	}
+	return nil
+}
+
+// isMetricsGeneratorProcessor reports whether name is a supported processor
+func isMetricsGeneratorProcessor(name string) bool {
+	for _, processor := range MetricsGeneratorProcessors {
+		if processor == name {
+			return true
+		}
+	}
+	return false
+}
+
+// buildMetricsGeneratorExtraConfig returns the extraConfig that runs the
+// metrics-generator with the configured processors for all tenants
+func buildMetricsGeneratorExtraConfig(cfg *MetricsGeneratorConfig) map[string]interface{} {
+	processors := cfg.Processors
+	if len(processors) == 0 {
+		processors = MetricsGeneratorProcessors
+	}
+
+	storage := map[string]interface{}{
+		"path": metricsGeneratorWALPath,
+	}
+	if cfg.RemoteWriteURL != "" {
+		storage["remote_write"] = []interface{}{
+			map[string]interface{}{"url": cfg.RemoteWriteURL, "send_exemplars": true},
+		}
+	}
+
+	processorList := make([]interface{}, len(processors))
+	for i, processor := range processors {
+		processorList[i] = processor
+	}
+
+	return map[string]interface{}{
+		"metrics_generator": map[string]interface{}{
+			"storage": storage,
+		},
+		"overrides": map[string]interface{}{
+			"defaults": map[string]interface{}{
+				"metrics_generator": map[string]interface{}{
+					"processors": processorList,
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,79 @@
+package tempo
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestValidateMetricsGenerator(t *testing.T) {
+	tests := []struct {
+		name    string
+		variant string
+		cfg     *MetricsGeneratorConfig
+		wantErr bool
+	}{
+		{name: "disabled stack", variant: "stack"},
+		{name: "default processors", variant: "monolithic", cfg: &MetricsGeneratorConfig{}},
+		{name: "span-metrics", variant: "monolithic", cfg: &MetricsGeneratorConfig{Processors: []string{"span-metrics"}}},
+		{name: "stack", variant: "stack", cfg: &MetricsGeneratorConfig{}, wantErr: true},
+		{name: "unknown processor", variant: "monolithic", cfg: &MetricsGeneratorConfig{Processors: []string{"local-blocks"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateMetricsGenerator(tt.variant, tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateMetricsGenerator() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBuildTempoMonolithicCR_MetricsGenerator(t *testing.T) {
+	maxTraces := 1000
+	cr := buildTempoMonolithicCR("perf", &ResourceConfig{
+		Overrides: &TempoOverrides{MaxTracesPerUser: &maxTraces},
+		MetricsGenerator: &MetricsGeneratorConfig{
+			Processors:     []string{"span-metrics"},
+			RemoteWriteURL: "http://prometheus:9090/api/v1/write",
+		},
+	})
+
+	var extraConfig map[string]interface{}
+	if err := json.Unmarshal(cr.Spec.ExtraConfig.Tempo.Raw, &extraConfig); err != nil {
+		t.Fatalf("invalid extraConfig: %v", err)
+	}
+	expected := map[string]interface{}{
+		"ingester": map[string]interface{}{"max_block_duration": "10m"},
+		"metrics_generator": map[string]interface{}{
+			"storage": map[string]interface{}{
+				"path": "/var/tempo/generator/wal",
+				"remote_write": []interface{}{
+					map[string]interface{}{"url": "http://prometheus:9090/api/v1/write", "send_exemplars": true},
+				},
+			},
+		},
+		"overrides": map[string]interface{}{
+			"defaults": map[string]interface{}{
+				"ingestion":         map[string]interface{}{"max_traces_per_user": float64(1000)},
+				"metrics_generator": map[string]interface{}{"processors": []interface{}{"span-metrics"}},
+			},
+		},
+	}
+	if !reflect.DeepEqual(extraConfig, expected) {
+		t.Errorf("got extraConfig %v, want %v", extraConfig, expected)
+	}
+}
+
+func TestBuildMetricsGeneratorExtraConfig_Defaults(t *testing.T) {
+	config := buildMetricsGeneratorExtraConfig(&MetricsGeneratorConfig{})
+
+	storage := config["metrics_generator"].(map[string]interface{})["storage"].(map[string]interface{})
+	if _, ok := storage["remote_write"]; ok {
+		t.Errorf("expected no remote_write without a URL, got %v", storage["remote_write"])
+	}
+	processors := config["overrides"].(map[string]interface{})["defaults"].(map[string]interface{})["metrics_generator"].(map[string]interface{})["processors"]
+	if !reflect.DeepEqual(processors, []interface{}{"span-metrics", "service-graphs"}) {
+		t.Errorf("got processors %v, want span-metrics and service-graphs", processors)
+	}
+}
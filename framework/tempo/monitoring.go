@@ -65,6 +65,38 @@ func EnsurePodMonitor(fw FrameworkOperations, variant string) error {
 	namespace := fw.Namespace()
 	ctx := fw.Context()
 
+	podMonitor := BuildPodMonitor(namespace, variant, fw.GetManagedLabels())
+	podMonitorName := podMonitor.GetName()
+
+	// Check if PodMonitor already exists
+	_, err := fw.DynamicClient().Resource(gvr.PodMonitor).Namespace(namespace).Get(ctx, podMonitorName, metav1.GetOptions{})
+	if err == nil {
+		fmt.Printf("✅ PodMonitor %s already exists\n", podMonitorName)
+		return nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to check PodMonitor: %w", err)
+	}
+
+	_, err = fw.DynamicClient().Resource(gvr.PodMonitor).Namespace(namespace).Create(ctx, podMonitor, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create PodMonitor: %w", err)
+	}
+
+	// Track for cleanup
+	fw.TrackCR(gvr.PodMonitor, namespace, podMonitorName)
+
+	fmt.Printf("✅ Created PodMonitor %s as fallback for Tempo metrics\n", podMonitorName)
+
+	// Give Prometheus time to discover the new PodMonitor
+	time.Sleep(5 * time.Second)
+
+	return nil
+}
+
+// BuildPodMonitor returns the PodMonitor EnsurePodMonitor creates for the Tempo pods
+// of variant
+func BuildPodMonitor(namespace, variant string, managedLabels map[string]string) *unstructured.Unstructured {
 	var podMonitorName string
 	var matchLabels map[string]interface{}
 
@@ -82,17 +114,6 @@ func EnsurePodMonitor(fw FrameworkOperations, variant string) error {
 		}
 	}
 
-	// Check if PodMonitor already exists
-	_, err := fw.DynamicClient().Resource(gvr.PodMonitor).Namespace(namespace).Get(ctx, podMonitorName, metav1.GetOptions{})
-	if err == nil {
-		fmt.Printf("✅ PodMonitor %s already exists\n", podMonitorName)
-		return nil
-	}
-	if !apierrors.IsNotFound(err) {
-		return fmt.Errorf("failed to check PodMonitor: %w", err)
-	}
-
-	// Create PodMonitor
 	podMonitor := &unstructured.Unstructured{
 		Object: map[string]interface{}{
 			"apiVersion": "monitoring.coreos.com/v1",
@@ -162,25 +183,11 @@ func EnsurePodMonitor(fw FrameworkOperations, variant string) error {
 
 	// Add managed labels
 	labels := podMonitor.GetLabels()
-	for k, v := range fw.GetManagedLabels() {
+	for k, v := range managedLabels {
 		labels[k] = v
 	}
 	podMonitor.SetLabels(labels)
-
-	_, err = fw.DynamicClient().Resource(gvr.PodMonitor).Namespace(namespace).Create(ctx, podMonitor, metav1.CreateOptions{})
-	if err != nil {
-		return fmt.Errorf("failed to create PodMonitor: %w", err)
-	}
-
-	// Track for cleanup
-	fw.TrackCR(gvr.PodMonitor, namespace, podMonitorName)
-
-	fmt.Printf("✅ Created PodMonitor %s as fallback for Tempo metrics\n", podMonitorName)
-
-	// Give Prometheus time to discover the new PodMonitor
-	time.Sleep(5 * time.Second)
-
-	return nil
+	return podMonitor
 }
 
 // SetupTempoMonitoring verifies ServiceMonitors and creates PodMonitor fallback if needed
@@ -21,23 +21,30 @@ import (
 // SetupMonolithic deploys Tempo Monolithic with optional resource configuration
 func SetupMonolithic(fw FrameworkOperations, resources *ResourceConfig) error {
 	// Build TempoMonolithic CR using typed API
-	tempoCR := buildTempoMonolithicCR(fw.Namespace(), resources)
-
-	// Convert to unstructured for dynamic client
-	unstructuredObj, err := toUnstructured(tempoCR)
+	unstructuredObj, err := buildMonolithicObject(fw.Namespace(), resources, fw.GetManagedLabels())
 	if err != nil {
-		return fmt.Errorf("failed to convert TempoMonolithic to unstructured: %w", err)
+		return err
+	}
+	name := unstructuredObj.GetName()
+
+	// Create the storage PVC first when the CR cannot set its StorageClass
+	if resources != nil {
+		if err := CreatePVStorageClaim(fw, name, resources.Storage); err != nil {
+			return err
+		}
 	}
 
-	// Add managed labels
-	labels := unstructuredObj.GetLabels()
-	if labels == nil {
-		labels = make(map[string]string)
+	// Create the ingestion certificates before the CR references them
+	if resources != nil && resources.IngestionAuth == IngestionAuthMTLS {
+		if err := CreateIngestionTLS(fw, name); err != nil {
+			return fmt.Errorf("failed to setup ingestion mTLS: %w", err)
+		}
 	}
-	for k, v := range fw.GetManagedLabels() {
-		labels[k] = v
+
+	replicas := 1
+	if resources != nil && resources.Replicas > 1 {
+		replicas = resources.Replicas
 	}
-	unstructuredObj.SetLabels(labels)
 
 	_, err = fw.DynamicClient().Resource(TempoMonolithicGVR).Namespace(fw.Namespace()).Create(fw.Context(), unstructuredObj, metav1.CreateOptions{})
 	if err != nil && !apierrors.IsAlreadyExists(err) {
@@ -45,12 +52,57 @@ func SetupMonolithic(fw FrameworkOperations, resources *ResourceConfig) error {
 	}
 
 	// Track the created resource (even if it already exists, for cleanup)
-	fw.TrackCR(TempoMonolithicGVR, fw.Namespace(), tempoCR.Name)
+	fw.TrackCR(TempoMonolithicGVR, fw.Namespace(), name)
+
+	if replicas > 1 {
+		if err := verifyMonolithicReplicas(fw, name, replicas); err != nil {
+			return err
+		}
+	}
 
-	// Wait for Tempo to be ready
+	// Mount extra config files (Tempo cannot start until referenced files exist)
+	if resources != nil && len(resources.ExtraFiles) > 0 {
+		if err := MountExtraConfigFiles(fw, TempoMonolithicGVR, name, resources.ExtraFiles); err != nil {
+			return fmt.Errorf("failed to mount extra config files: %w", err)
+		}
+	}
+
+	// Set Tempo container environment variables (e.g. Go runtime tuning)
+	if resources != nil && len(resources.Env) > 0 {
+		if err := SetTempoEnv(fw, TempoMonolithicGVR, name, resources.Env); err != nil {
+			return fmt.Errorf("failed to set Tempo environment: %w", err)
+		}
+	}
+
+	// Pin the Tempo image (TempoMonolithic has no image override)
+	if resources != nil && resources.Image != "" {
+		if err := SetTempoImage(fw, TempoMonolithicGVR, name, resources.Image); err != nil {
+			return fmt.Errorf("failed to set Tempo image: %w", err)
+		}
+	}
+
+	// Wait for Tempo to be ready (every replica when there are several)
+	if replicas > 1 {
+		return waitForMonolithicReplicas(fw, name, replicas, 300*time.Second)
+	}
 	return wait.ForTempoPodsReady(fw, 300*time.Second)
 }
 
+// buildMonolithicObject builds the TempoMonolithic CR for the dynamic client, with
+// managed labels and replicas
+func buildMonolithicObject(namespace string, resources *ResourceConfig, managedLabels map[string]string) (*unstructured.Unstructured, error) {
+	unstructuredObj, err := withLabels(buildTempoMonolithicCR(namespace, resources), managedLabels)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert TempoMonolithic to unstructured: %w", err)
+	}
+	if resources != nil && resources.Replicas > 1 {
+		if err := setMonolithicReplicas(unstructuredObj, resources.Replicas); err != nil {
+			return nil, err
+		}
+	}
+	return unstructuredObj, nil
+}
+
 // toUnstructured converts a typed object to unstructured
 func toUnstructured(obj interface{}) (*unstructured.Unstructured, error) {
 	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
@@ -60,6 +112,15 @@ func toUnstructured(obj interface{}) (*unstructured.Unstructured, error) {
 	return &unstructured.Unstructured{Object: content}, nil
 }
 
+// getTempoResources returns the resources of the preset profile, or the custom
+// resources when no profile is set
+func getTempoResources(resources *ResourceConfig) *corev1.ResourceRequirements {
+	if resources.Profile != "" {
+		return getProfileResources(resources.Profile)
+	}
+	return resources.Resources
+}
+
 // getProfileResources returns resource requirements for a preset profile
 func getProfileResources(profile string) *corev1.ResourceRequirements {
 	switch profile {
@@ -103,10 +164,10 @@ func getProfileResources(profile string) *corev1.ResourceRequirements {
 
 // buildTempoMonolithicCR builds a TempoMonolithic CR using typed API
 func buildTempoMonolithicCR(namespace string, resources *ResourceConfig) *tempoapi.TempoMonolithic {
-	// Determine storage secret name
-	secretName := GetStorageSecretName(nil)
-	if resources != nil && resources.Storage != nil {
-		secretName = GetStorageSecretName(resources.Storage)
+	// Determine trace storage (S3 secret or persistent volume)
+	var storage *StorageConfig
+	if resources != nil {
+		storage = resources.Storage
 	}
 
 	// Build extra config as JSON
@@ -129,6 +190,22 @@ func buildTempoMonolithicCR(namespace string, resources *ResourceConfig) *tempoa
 		}
 	}
 
+	// Enable the metrics-generator if configured
+	if resources != nil && resources.MetricsGenerator != nil {
+		mergeExtraConfig(extraConfig, buildMetricsGeneratorExtraConfig(resources.MetricsGenerator))
+	}
+
+	// Set the block format if configured
+	if resources != nil {
+		mergeExtraConfig(extraConfig, buildBlockExtraConfig(resources.Block))
+	}
+
+	// Point extraConfig keys at mounted extra files
+	if resources != nil {
+		applyExtraFileReferences(extraConfig, resources.ExtraFiles)
+		mergeExtraConfig(extraConfig, resources.ExtraConfig)
+	}
+
 	extraConfigJSON, _ := json.Marshal(extraConfig)
 
 	tempoCR := &tempoapi.TempoMonolithic{
@@ -141,26 +218,12 @@ func buildTempoMonolithicCR(namespace string, resources *ResourceConfig) *tempoa
 			Namespace: namespace,
 		},
 		Spec: tempoapi.TempoMonolithicSpec{
-			Storage: &tempoapi.MonolithicStorageSpec{
-				Traces: tempoapi.MonolithicTracesStorageSpec{
-					Backend: tempoapi.MonolithicTracesStorageBackendS3,
-					S3: &tempoapi.MonolithicTracesStorageS3Spec{
-						MonolithicTracesObjectStorageSpec: tempoapi.MonolithicTracesObjectStorageSpec{
-							Secret: secretName,
-						},
-					},
-				},
-			},
+			Storage: buildMonolithicStorageSpec(storage),
 			Multitenancy: &tempoapi.MonolithicMultitenancySpec{
 				Enabled: true,
 				TenantsSpec: tempoapi.TenantsSpec{
-					Mode: tempoapi.ModeOpenShift,
-					Authentication: []tempoapi.AuthenticationSpec{
-						{
-							TenantName: "tenant-1",
-							TenantID:   "tenant-1",
-						},
-					},
+					Mode:           tempoapi.ModeOpenShift,
+					Authentication: buildTenantsAuthentication(resources),
 				},
 			},
 			JaegerUI: &tempoapi.MonolithicJaegerUISpec{
@@ -184,15 +247,16 @@ func buildTempoMonolithicCR(namespace string, resources *ResourceConfig) *tempoa
 		},
 	}
 
+	// Without the gateway the collector sends to Tempo directly, and queries skip
+	// the gateway too
+	if resources != nil && !UsesGateway(resources.IngestionAuth) {
+		tempoCR.Spec.Multitenancy = nil
+		tempoCR.Spec.Ingestion = buildMonolithicIngestionSpec(resources.IngestionAuth)
+	}
+
 	// Apply resource configuration if provided
 	if resources != nil {
-		var resourceReqs *corev1.ResourceRequirements
-		if resources.Profile != "" {
-			resourceReqs = getProfileResources(resources.Profile)
-		} else if resources.Resources != nil {
-			resourceReqs = resources.Resources
-		}
-		if resourceReqs != nil {
+		if resourceReqs := getTempoResources(resources); resourceReqs != nil {
 			tempoCR.Spec.Resources = resourceReqs
 		}
 
@@ -0,0 +1,75 @@
+package tempo
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/wait"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// validateReplicas checks the number of TempoMonolithic replicas for a variant
+func validateReplicas(variant string, replicas int, storage *StorageConfig) error {
+	if replicas < 0 {
+		return fmt.Errorf("invalid replicas %d (cannot be negative)", replicas)
+	}
+	if replicas <= 1 {
+		return nil
+	}
+	if variant != "monolithic" {
+		return fmt.Errorf("replicas is only supported with the monolithic variant")
+	}
+	// Each pod of the StatefulSet would get a volume of its own, so the replicas
+	// would not share their traces
+	if isPVStorage(storage) {
+		return fmt.Errorf("replicas greater than 1 require object storage, not pv storage")
+	}
+	return nil
+}
+
+// setMonolithicReplicas sets spec.replicas of a TempoMonolithic CR. The typed API
+// of the operator version in go.mod has no replicas field, so it is set unstructured.
+func setMonolithicReplicas(obj *unstructured.Unstructured, replicas int) error {
+	if err := unstructured.SetNestedField(obj.Object, int64(replicas), "spec", "replicas"); err != nil {
+		return fmt.Errorf("failed to set TempoMonolithic replicas: %w", err)
+	}
+	return nil
+}
+
+// verifyMonolithicReplicas checks that the API server kept spec.replicas of the
+// TempoMonolithic CR; the CRD of older operators does not have it and prunes it
+func verifyMonolithicReplicas(fw FrameworkOperations, name string, replicas int) error {
+	cr, err := fw.DynamicClient().Resource(TempoMonolithicGVR).Namespace(fw.Namespace()).Get(fw.Context(), name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get TempoMonolithic: %w", err)
+	}
+	got, found, err := unstructured.NestedInt64(cr.Object, "spec", "replicas")
+	if err != nil || !found {
+		return fmt.Errorf("TempoMonolithic %s has no spec.replicas: the installed Tempo operator does not support monolithic replicas", name)
+	}
+	if got != int64(replicas) {
+		return fmt.Errorf("TempoMonolithic %s has %d replicas, expected %d", name, got, replicas)
+	}
+	return nil
+}
+
+// monolithicPodSelector selects the Tempo pods of a TempoMonolithic
+func monolithicPodSelector(name string) labels.Selector {
+	return labels.SelectorFromSet(labels.Set{
+		"app.kubernetes.io/instance":  name,
+		"app.kubernetes.io/component": "tempo",
+	})
+}
+
+// waitForMonolithicReplicas waits until all the replicas of a TempoMonolithic are ready
+func waitForMonolithicReplicas(fw FrameworkOperations, name string, replicas int, timeout time.Duration) error {
+	fmt.Printf("⏳ Waiting for %d TempoMonolithic replicas to be ready...\n", replicas)
+	if err := wait.ForPodsReady(fw, monolithicPodSelector(name), timeout, replicas); err != nil {
+		return fmt.Errorf("TempoMonolithic replicas not ready: %w", err)
+	}
+	fmt.Printf("✅ %d TempoMonolithic replicas are ready\n", replicas)
+	return nil
+}
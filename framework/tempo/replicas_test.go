@@ -0,0 +1,59 @@
+package tempo
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestValidateReplicas(t *testing.T) {
+	tests := []struct {
+		name     string
+		variant  string
+		replicas int
+		storage  *StorageConfig
+		wantErr  bool
+	}{
+		{name: "default stack", variant: "stack"},
+		{name: "single monolithic", variant: "monolithic", replicas: 1},
+		{name: "ha monolithic", variant: "monolithic", replicas: 3},
+		{name: "ha monolithic s3", variant: "monolithic", replicas: 2, storage: &StorageConfig{Type: "s3"}},
+		{name: "negative", variant: "monolithic", replicas: -1, wantErr: true},
+		{name: "stack", variant: "stack", replicas: 2, wantErr: true},
+		{name: "pv storage", variant: "monolithic", replicas: 2, storage: &StorageConfig{Type: "pv"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateReplicas(tt.variant, tt.replicas, tt.storage)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateReplicas() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSetMonolithicReplicas(t *testing.T) {
+	obj, err := toUnstructured(buildTempoMonolithicCR("perf", nil))
+	if err != nil {
+		t.Fatalf("toUnstructured() error = %v", err)
+	}
+	if err := setMonolithicReplicas(obj, 3); err != nil {
+		t.Fatalf("setMonolithicReplicas() error = %v", err)
+	}
+
+	replicas, found, err := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+	if err != nil || !found || replicas != 3 {
+		t.Errorf("spec.replicas = %d (found %v, err %v), want 3", replicas, found, err)
+	}
+	if _, found, _ := unstructured.NestedMap(obj.Object, "spec", "storage"); !found {
+		t.Error("setMonolithicReplicas() dropped the rest of the spec")
+	}
+}
+
+func TestMonolithicPodSelector(t *testing.T) {
+	got := monolithicPodSelector("simplest").String()
+	want := "app.kubernetes.io/component=tempo,app.kubernetes.io/instance=simplest"
+	if got != want {
+		t.Errorf("monolithicPodSelector() = %q, want %q", got, want)
+	}
+}
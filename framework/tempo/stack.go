@@ -3,13 +3,15 @@ package tempo
 import (
 	"encoding/json"
 	"fmt"
-	"time"
+	"slices"
 
 	"github.com/redhat/perf-tests-tempo/test/framework/wait"
 
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 
 	tempoapi "github.com/grafana/tempo-operator/api/tempo/v1alpha1"
@@ -18,23 +20,11 @@ import (
 // SetupStack deploys Tempo Stack
 func SetupStack(fw FrameworkOperations, resources *ResourceConfig) error {
 	// Build TempoStack CR using typed API
-	stackCR := buildTempoStackCR(fw.Namespace(), resources)
-
-	// Convert to unstructured for dynamic client
-	unstructuredObj, err := toUnstructured(stackCR)
+	unstructuredObj, err := buildStackObject(fw.Namespace(), resources, fw.GetManagedLabels())
 	if err != nil {
-		return fmt.Errorf("failed to convert TempoStack to unstructured: %w", err)
-	}
-
-	// Add managed labels
-	labels := unstructuredObj.GetLabels()
-	if labels == nil {
-		labels = make(map[string]string)
+		return err
 	}
-	for k, v := range fw.GetManagedLabels() {
-		labels[k] = v
-	}
-	unstructuredObj.SetLabels(labels)
+	name := unstructuredObj.GetName()
 
 	_, err = fw.DynamicClient().Resource(TempoStackGVR).Namespace(fw.Namespace()).Create(fw.Context(), unstructuredObj, metav1.CreateOptions{})
 	if err != nil && !apierrors.IsAlreadyExists(err) {
@@ -42,29 +32,102 @@ func SetupStack(fw FrameworkOperations, resources *ResourceConfig) error {
 	}
 
 	// Track the created resource (even if it already exists, for cleanup)
-	fw.TrackCR(TempoStackGVR, fw.Namespace(), stackCR.Name)
+	fw.TrackCR(TempoStackGVR, fw.Namespace(), name)
+
+	// Mount extra config files (Tempo cannot start until referenced files exist)
+	if resources != nil && len(resources.ExtraFiles) > 0 {
+		if err := MountExtraConfigFiles(fw, TempoStackGVR, name, resources.ExtraFiles); err != nil {
+			return fmt.Errorf("failed to mount extra config files: %w", err)
+		}
+	}
+
+	// Set Tempo container environment variables (e.g. Go runtime tuning)
+	if resources != nil && len(resources.Env) > 0 {
+		if err := SetTempoEnv(fw, TempoStackGVR, name, resources.Env); err != nil {
+			return fmt.Errorf("failed to set Tempo environment: %w", err)
+		}
+	}
+
+	// Create HPAs (the operator would otherwise reset the scaled replica counts)
+	if resources != nil && resources.Autoscaling != nil {
+		if err := SetupAutoscaling(fw, name, resources.Autoscaling); err != nil {
+			return fmt.Errorf("failed to setup autoscaling: %w", err)
+		}
+	}
 
-	// Wait for Tempo to be ready
-	return wait.ForTempoPodsReady(fw, 300*time.Second)
+	// Wait for the operator to report the stack ready and all components to roll out,
+	// within the configured CRReadyTimeout
+	report, err := wait.ForTempoStackReady(fw, name, 0)
+	if err != nil {
+		return err
+	}
+	fw.Logger().Info("TempoStack ready", "name", report.Name, "components", len(report.Components))
+	return nil
 }
 
-// buildTempoStackCR builds a TempoStack CR using typed API
-func buildTempoStackCR(namespace string, resources *ResourceConfig) *tempoapi.TempoStack {
-	storageSize := resource.MustParse("10Gi")
+// buildStackObject builds the TempoStack CR for the dynamic client, with managed labels
+func buildStackObject(namespace string, resources *ResourceConfig, managedLabels map[string]string) (*unstructured.Unstructured, error) {
+	unstructuredObj, err := withLabels(buildTempoStackCR(namespace, resources), managedLabels)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert TempoStack to unstructured: %w", err)
+	}
+	return unstructuredObj, nil
+}
+
+// StackComponents are the TempoStack components, by the name used in ComponentResources
+var StackComponents = []string{"distributor", "ingester", "querier", "query-frontend", "compactor", "gateway"}
 
-	// Determine storage secret name
-	secretName := GetStorageSecretName(nil)
-	if resources != nil && resources.Storage != nil {
-		secretName = GetStorageSecretName(resources.Storage)
+// validateComponentResources checks the per-component resources of a variant
+func validateComponentResources(variant string, components map[string]*corev1.ResourceRequirements) error {
+	if len(components) == 0 {
+		return nil
+	}
+	if variant != "stack" {
+		return fmt.Errorf("component resources are only supported with the stack variant")
+	}
+	for name := range components {
+		if !slices.Contains(StackComponents, name) {
+			return fmt.Errorf("invalid component %q in component resources (must be one of %v)", name, StackComponents)
+		}
 	}
+	return nil
+}
+
+// stackComponentSpecs returns the component specs of a TempoStack template by component name
+func stackComponentSpecs(template *tempoapi.TempoTemplateSpec) map[string]*tempoapi.TempoComponentSpec {
+	return map[string]*tempoapi.TempoComponentSpec{
+		"distributor":    &template.Distributor.TempoComponentSpec,
+		"ingester":       &template.Ingester,
+		"querier":        &template.Querier,
+		"query-frontend": &template.QueryFrontend.TempoComponentSpec,
+		"compactor":      &template.Compactor,
+		"gateway":        &template.Gateway.TempoComponentSpec,
+	}
+}
 
-	// Build extra config for ingester tuning
+// buildStackExtraConfig builds the extraConfig of a TempoStack: ingester tuning,
+// references to mounted extra files and raw ExtraConfig
+func buildStackExtraConfig(resources *ResourceConfig) []byte {
 	extraConfig := map[string]interface{}{}
 	ingesterConfig := buildIngesterExtraConfig(resources)
 	if len(ingesterConfig) > 0 {
 		extraConfig["ingester"] = ingesterConfig
 	}
+	if resources != nil {
+		mergeExtraConfig(extraConfig, buildBlockExtraConfig(resources.Block))
+		applyExtraFileReferences(extraConfig, resources.ExtraFiles)
+		mergeExtraConfig(extraConfig, resources.ExtraConfig)
+	}
 	extraConfigJSON, _ := json.Marshal(extraConfig)
+	return extraConfigJSON
+}
+
+// buildTempoStackCR builds a TempoStack CR using typed API
+func buildTempoStackCR(namespace string, resources *ResourceConfig) *tempoapi.TempoStack {
+	var storage *StorageConfig
+	if resources != nil {
+		storage = resources.Storage
+	}
 
 	stackCR := &tempoapi.TempoStack{
 		TypeMeta: metav1.TypeMeta{
@@ -86,21 +149,11 @@ func buildTempoStackCR(namespace string, resources *ResourceConfig) *tempoapi.Te
 					Enabled: true,
 				},
 			},
-			Storage: tempoapi.ObjectStorageSpec{
-				Secret: tempoapi.ObjectStorageSecretSpec{
-					Type: tempoapi.ObjectStorageSecretS3,
-					Name: secretName,
-				},
-			},
-			StorageSize: storageSize,
+			Storage:     buildStackStorageSpec(storage),
+			StorageSize: resource.MustParse("10Gi"),
 			Tenants: &tempoapi.TenantsSpec{
-				Mode: tempoapi.ModeOpenShift,
-				Authentication: []tempoapi.AuthenticationSpec{
-					{
-						TenantName: "tenant-1",
-						TenantID:   "tenant-1",
-					},
-				},
+				Mode:           tempoapi.ModeOpenShift,
+				Authentication: buildTenantsAuthentication(resources),
 			},
 			Observability: tempoapi.ObservabilitySpec{
 				Metrics: tempoapi.MetricsConfigSpec{
@@ -110,14 +163,17 @@ func buildTempoStackCR(namespace string, resources *ResourceConfig) *tempoapi.Te
 			},
 			ExtraConfig: &tempoapi.ExtraConfigSpec{
 				Tempo: apiextensionsv1.JSON{
-					Raw: extraConfigJSON,
+					Raw: buildStackExtraConfig(resources),
 				},
 			},
 		},
 	}
+	if resources == nil {
+		return stackCR
+	}
 
 	// Add limits if configured
-	if resources != nil && resources.Overrides != nil && resources.Overrides.MaxTracesPerUser != nil {
+	if resources.Overrides != nil && resources.Overrides.MaxTracesPerUser != nil {
 		stackCR.Spec.LimitSpec = tempoapi.LimitSpec{
 			Global: tempoapi.RateLimitSpec{
 				Ingestion: tempoapi.IngestionLimitSpec{
@@ -128,45 +184,34 @@ func buildTempoStackCR(namespace string, resources *ResourceConfig) *tempoapi.Te
 	}
 
 	// Set replication factor if configured
-	if resources != nil && resources.ReplicationFactor != nil {
+	if resources.ReplicationFactor != nil {
 		stackCR.Spec.ReplicationFactor = *resources.ReplicationFactor
 
 		// Ingester replicas must be >= replicationFactor (Tempo Operator requirement)
 		replicas := int32(*resources.ReplicationFactor)
-		stackCR.Spec.Template.Ingester = tempoapi.TempoComponentSpec{
-			Replicas: &replicas,
-		}
+		stackCR.Spec.Template.Ingester.Replicas = &replicas
 	}
 
-	// Apply node selector to all components if provided
-	if resources != nil && len(resources.NodeSelector) > 0 {
-		nodeSelector := resources.NodeSelector
-
-		// Apply to distributor
-		stackCR.Spec.Template.Distributor.NodeSelector = nodeSelector
+	// The operator splits the total limits between the components and sets
+	// requests from them
+	if reqs := getTempoResources(resources); reqs != nil && len(reqs.Limits) > 0 {
+		stackCR.Spec.Resources.Total = &corev1.ResourceRequirements{Limits: reqs.Limits}
+	}
 
-		// Apply to ingester (preserve replicas if already set)
-		if stackCR.Spec.Template.Ingester.Replicas != nil {
-			replicas := stackCR.Spec.Template.Ingester.Replicas
-			stackCR.Spec.Template.Ingester = tempoapi.TempoComponentSpec{
-				Replicas:     replicas,
-				NodeSelector: nodeSelector,
-			}
-		} else {
-			stackCR.Spec.Template.Ingester.NodeSelector = nodeSelector
+	// Apply node selector and per-component resources, which take precedence over
+	// the share of the total of a component
+	for name, component := range stackComponentSpecs(&stackCR.Spec.Template) {
+		if len(resources.NodeSelector) > 0 {
+			component.NodeSelector = resources.NodeSelector
 		}
+		if reqs := resources.ComponentResources[name]; reqs != nil {
+			component.Resources = reqs
+		}
+	}
 
-		// Apply to querier
-		stackCR.Spec.Template.Querier.NodeSelector = nodeSelector
-
-		// Apply to compactor
-		stackCR.Spec.Template.Compactor.NodeSelector = nodeSelector
-
-		// Apply to query frontend
-		stackCR.Spec.Template.QueryFrontend.TempoComponentSpec.NodeSelector = nodeSelector
-
-		// Apply to gateway
-		stackCR.Spec.Template.Gateway.TempoComponentSpec.NodeSelector = nodeSelector
+	// Pin the Tempo image instead of the default of the operator
+	if resources.Image != "" {
+		stackCR.Spec.Images.Tempo = resources.Image
 	}
 
 	return stackCR
@@ -0,0 +1,142 @@
+package tempo
+
+import (
+	"encoding/json"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestBuildTempoStackCR_Defaults(t *testing.T) {
+	cr := buildTempoStackCR("perf", nil)
+
+	if cr.Name != "tempostack" || cr.Namespace != "perf" {
+		t.Errorf("name = %s/%s, want perf/tempostack", cr.Namespace, cr.Name)
+	}
+	if cr.Spec.Storage.Secret.Name != "minio" {
+		t.Errorf("storage secret = %q, want minio", cr.Spec.Storage.Secret.Name)
+	}
+	if cr.Spec.Resources.Total != nil {
+		t.Errorf("total resources = %+v, want none", cr.Spec.Resources.Total)
+	}
+	if !cr.Spec.Template.Gateway.Enabled || !cr.Spec.Template.QueryFrontend.JaegerQuery.Enabled {
+		t.Error("expected the gateway and the Jaeger query to be enabled")
+	}
+
+	var extraConfig map[string]interface{}
+	if err := json.Unmarshal(cr.Spec.ExtraConfig.Tempo.Raw, &extraConfig); err != nil {
+		t.Fatalf("extraConfig is not JSON: %v", err)
+	}
+	if _, ok := extraConfig["ingester"]; !ok {
+		t.Errorf("extraConfig = %v, want the default ingester tuning", extraConfig)
+	}
+}
+
+func TestBuildTempoStackCR_ReplicationFactor(t *testing.T) {
+	rf := 3
+	nodeSelector := map[string]string{"node-role.kubernetes.io/infra": ""}
+	cr := buildTempoStackCR("perf", &ResourceConfig{ReplicationFactor: &rf, NodeSelector: nodeSelector})
+
+	if cr.Spec.ReplicationFactor != 3 {
+		t.Errorf("replicationFactor = %d, want 3", cr.Spec.ReplicationFactor)
+	}
+	ingester := cr.Spec.Template.Ingester
+	if ingester.Replicas == nil || *ingester.Replicas != 3 {
+		t.Errorf("ingester replicas = %v, want 3", ingester.Replicas)
+	}
+	for name, component := range stackComponentSpecs(&cr.Spec.Template) {
+		if _, ok := component.NodeSelector["node-role.kubernetes.io/infra"]; !ok {
+			t.Errorf("%s nodeSelector = %v, want %v", name, component.NodeSelector, nodeSelector)
+		}
+	}
+}
+
+func TestBuildTempoStackCR_Resources(t *testing.T) {
+	ingester := &corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("6Gi")},
+		Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("6Gi")},
+	}
+	cr := buildTempoStackCR("perf", &ResourceConfig{
+		Profile:            "medium",
+		ComponentResources: map[string]*corev1.ResourceRequirements{"ingester": ingester},
+	})
+
+	total := cr.Spec.Resources.Total
+	if total == nil {
+		t.Fatal("expected the total resources of the profile")
+	}
+	if got := total.Limits[corev1.ResourceMemory]; got.String() != "8Gi" {
+		t.Errorf("total memory limit = %s, want 8Gi", got.String())
+	}
+	if len(total.Requests) != 0 {
+		t.Errorf("total requests = %v, want none (the operator sets them)", total.Requests)
+	}
+	if cr.Spec.Template.Ingester.Resources != ingester {
+		t.Errorf("ingester resources = %+v, want %+v", cr.Spec.Template.Ingester.Resources, ingester)
+	}
+	if cr.Spec.Template.Querier.Resources != nil {
+		t.Errorf("querier resources = %+v, want its share of the total", cr.Spec.Template.Querier.Resources)
+	}
+
+	custom := buildTempoStackCR("perf", &ResourceConfig{Resources: &corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+	}})
+	if got := custom.Spec.Resources.Total.Limits[corev1.ResourceCPU]; got.String() != "4" {
+		t.Errorf("total cpu limit = %s, want 4", got.String())
+	}
+}
+
+func TestBuildTempoStackCR_StorageAndLimits(t *testing.T) {
+	maxTraces := 0
+	cr := buildTempoStackCR("perf", &ResourceConfig{
+		Storage:     &StorageConfig{Type: "s3", SecretName: "my-bucket"},
+		Overrides:   &TempoOverrides{MaxTracesPerUser: &maxTraces},
+		ExtraConfig: map[string]interface{}{"querier": map[string]interface{}{"max_concurrent_queries": 40}},
+		Image:       "quay.io/org/tempo:2.8.0",
+	})
+
+	if cr.Spec.Storage.Secret.Name != "my-bucket" {
+		t.Errorf("storage secret = %q, want my-bucket", cr.Spec.Storage.Secret.Name)
+	}
+	if got := cr.Spec.LimitSpec.Global.Ingestion.MaxTracesPerUser; got == nil || *got != 0 {
+		t.Errorf("maxTracesPerUser = %v, want 0", got)
+	}
+	if cr.Spec.Images.Tempo != "quay.io/org/tempo:2.8.0" {
+		t.Errorf("tempo image = %q", cr.Spec.Images.Tempo)
+	}
+
+	var extraConfig map[string]map[string]interface{}
+	if err := json.Unmarshal(cr.Spec.ExtraConfig.Tempo.Raw, &extraConfig); err != nil {
+		t.Fatalf("extraConfig is not JSON: %v", err)
+	}
+	if extraConfig["querier"]["max_concurrent_queries"] != float64(40) {
+		t.Errorf("extraConfig = %v, want the merged querier setting", extraConfig)
+	}
+	if len(extraConfig["ingester"]) == 0 {
+		t.Errorf("extraConfig = %v, want the ingester tuning kept", extraConfig)
+	}
+}
+
+func TestValidateComponentResources(t *testing.T) {
+	reqs := &corev1.ResourceRequirements{}
+	tests := []struct {
+		name       string
+		variant    string
+		components map[string]*corev1.ResourceRequirements
+		wantErr    bool
+	}{
+		{name: "none", variant: "monolithic"},
+		{name: "stack", variant: "stack", components: map[string]*corev1.ResourceRequirements{"ingester": reqs, "query-frontend": reqs}},
+		{name: "monolithic", variant: "monolithic", components: map[string]*corev1.ResourceRequirements{"ingester": reqs}, wantErr: true},
+		{name: "unknown component", variant: "stack", components: map[string]*corev1.ResourceRequirements{"generator": reqs}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateComponentResources(tt.variant, tt.components)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateComponentResources() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package tempo
+
+import (
+	"fmt"
+
+	tempoapi "github.com/grafana/tempo-operator/api/tempo/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// monolithicStorageVolume is the volumeClaimTemplate name of the TempoMonolithic StatefulSet
+const monolithicStorageVolume = "tempo-storage"
+
+// DefaultPVStorageSize is the size of the "pv" storage volume, as defaulted by the operator
+const DefaultPVStorageSize = "10Gi"
+
+// isPVStorage reports whether traces are stored on a persistent volume
+func isPVStorage(storage *StorageConfig) bool {
+	return storage != nil && storage.Type == "pv"
+}
+
+// validateStorage checks the storage configuration against the Tempo variant
+func validateStorage(variant string, storage *StorageConfig) error {
+	if storage == nil {
+		return nil
+	}
+	switch storage.Type {
+	case "", "minio", "s3":
+		return nil
+	case "pv":
+		if variant != "monolithic" {
+			return fmt.Errorf("pv storage is only supported with the monolithic variant")
+		}
+		if storage.Size != "" {
+			if _, err := resource.ParseQuantity(storage.Size); err != nil {
+				return fmt.Errorf("invalid pv storage size %q: %w", storage.Size, err)
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid storage type %q (must be 'minio', 's3' or 'pv')", storage.Type)
+	}
+}
+
+// pvStorageSize returns the size of the "pv" storage volume
+func pvStorageSize(storage *StorageConfig) resource.Quantity {
+	if storage.Size != "" {
+		return resource.MustParse(storage.Size)
+	}
+	return resource.MustParse(DefaultPVStorageSize)
+}
+
+// buildMonolithicStorageSpec returns the trace storage of a TempoMonolithic: a
+// persistent volume for the "pv" type, the S3 secret otherwise
+func buildMonolithicStorageSpec(storage *StorageConfig) *tempoapi.MonolithicStorageSpec {
+	if isPVStorage(storage) {
+		size := pvStorageSize(storage)
+		return &tempoapi.MonolithicStorageSpec{
+			Traces: tempoapi.MonolithicTracesStorageSpec{
+				Backend: tempoapi.MonolithicTracesStorageBackendPV,
+				Size:    &size,
+			},
+		}
+	}
+
+	return &tempoapi.MonolithicStorageSpec{
+		Traces: tempoapi.MonolithicTracesStorageSpec{
+			Backend: tempoapi.MonolithicTracesStorageBackendS3,
+			S3: &tempoapi.MonolithicTracesStorageS3Spec{
+				MonolithicTracesObjectStorageSpec: tempoapi.MonolithicTracesObjectStorageSpec{
+					Secret: GetStorageSecretName(storage),
+				},
+			},
+		},
+	}
+}
+
+// buildStackStorageSpec builds the object storage of a TempoStack, which supports no
+// persistent volume storage, from the storage secret
+func buildStackStorageSpec(storage *StorageConfig) tempoapi.ObjectStorageSpec {
+	return tempoapi.ObjectStorageSpec{
+		Secret: tempoapi.ObjectStorageSecretSpec{
+			Type: tempoapi.ObjectStorageSecretS3,
+			Name: GetStorageSecretName(storage),
+		},
+	}
+}
+
+// pvStorageClaimName returns the name of the PVC the TempoMonolithic StatefulSet
+// creates for its storage volume
+func pvStorageClaimName(crName string) string {
+	return fmt.Sprintf("%s-tempo-%s-0", monolithicStorageVolume, crName)
+}
+
+// CreatePVStorageClaim creates the storage PVC of a TempoMonolithic with the configured
+// StorageClass. The operator has no StorageClass setting for monolithic storage, but
+// a StatefulSet uses an existing PVC with the name of its volumeClaimTemplate, so the
+// PVC is created before the CR. Without a StorageClass the operator creates the PVC.
+func CreatePVStorageClaim(fw FrameworkOperations, crName string, storage *StorageConfig) error {
+	if !isPVStorage(storage) || storage.StorageClassName == "" {
+		return nil
+	}
+
+	storageClass := storage.StorageClassName
+	size := pvStorageSize(storage)
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pvStorageClaimName(crName),
+			Namespace: fw.Namespace(),
+			Labels:    fw.GetManagedLabels(),
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			StorageClassName: &storageClass,
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: size,
+				},
+			},
+		},
+	}
+
+	_, err := fw.Client().CoreV1().PersistentVolumeClaims(fw.Namespace()).Create(fw.Context(), pvc, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create Tempo storage PVC: %w", err)
+	}
+
+	fw.Logger().Info("Created Tempo storage PVC", "name", pvc.Name, "storageClass", storageClass, "size", size.String())
+	return nil
+}
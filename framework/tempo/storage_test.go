@@ -0,0 +1,58 @@
+package tempo
+
+import (
+	"testing"
+
+	tempoapi "github.com/grafana/tempo-operator/api/tempo/v1alpha1"
+)
+
+func TestValidateStorage(t *testing.T) {
+	tests := []struct {
+		name    string
+		variant string
+		storage *StorageConfig
+		wantErr bool
+	}{
+		{name: "default", variant: "stack"},
+		{name: "s3", variant: "stack", storage: &StorageConfig{Type: "s3"}},
+		{name: "pv monolithic", variant: "monolithic", storage: &StorageConfig{Type: "pv", Size: "20Gi"}},
+		{name: "pv stack", variant: "stack", storage: &StorageConfig{Type: "pv"}, wantErr: true},
+		{name: "invalid size", variant: "monolithic", storage: &StorageConfig{Type: "pv", Size: "lots"}, wantErr: true},
+		{name: "unknown type", variant: "monolithic", storage: &StorageConfig{Type: "gcs"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateStorage(tt.variant, tt.storage)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateStorage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBuildMonolithicStorageSpec(t *testing.T) {
+	spec := buildMonolithicStorageSpec(nil)
+	if spec.Traces.Backend != tempoapi.MonolithicTracesStorageBackendS3 || spec.Traces.S3.Secret != "minio" {
+		t.Errorf("expected the MinIO S3 secret by default, got %+v", spec.Traces)
+	}
+
+	spec = buildMonolithicStorageSpec(&StorageConfig{Type: "pv"})
+	if spec.Traces.Backend != tempoapi.MonolithicTracesStorageBackendPV || spec.Traces.S3 != nil {
+		t.Fatalf("expected pv storage without S3, got %+v", spec.Traces)
+	}
+	if spec.Traces.Size.String() != DefaultPVStorageSize {
+		t.Errorf("expected size %s, got %s", DefaultPVStorageSize, spec.Traces.Size)
+	}
+
+	spec = buildMonolithicStorageSpec(&StorageConfig{Type: "pv", Size: "50Gi"})
+	if spec.Traces.Size.String() != "50Gi" {
+		t.Errorf("expected size 50Gi, got %s", spec.Traces.Size)
+	}
+}
+
+func TestPVStorageClaimName(t *testing.T) {
+	// StatefulSet PVCs are named <volumeClaimTemplate>-<statefulset>-<ordinal>
+	if got := pvStorageClaimName("simplest"); got != "tempo-storage-tempo-simplest-0" {
+		t.Errorf("unexpected claim name %q", got)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
@@ -44,6 +45,50 @@ type ResourceConfig = struct {
 	// Storage configures S3-compatible storage for Tempo.
 	// If nil, uses default MinIO setup (requires calling SetupMinIO first).
 	Storage *StorageConfig
+
+	// ExtraFiles are ConfigMap/Secret backed files mounted into Tempo and
+	// referenced from extraConfig (e.g. a per-tenant overrides file)
+	ExtraFiles []ExtraConfigFile
+
+	// ExtraConfig is raw Tempo configuration merged into the extraConfig of the CR.
+	// It must not set keys that other settings set.
+	ExtraConfig map[string]interface{}
+
+	// Autoscaling creates HorizontalPodAutoscalers for TempoStack components.
+	// Only applies to TempoStack (not monolithic).
+	Autoscaling *AutoscalingConfig
+
+	// ComponentResources sets the resources of TempoStack components by name (see
+	// StackComponents), over their share of the total. Only applies to TempoStack.
+	ComponentResources map[string]*corev1.ResourceRequirements
+
+	// Env sets environment variables of the Tempo containers, e.g. GOGC and
+	// GOMEMLIMIT. Setting it switches the Tempo CR to unmanaged.
+	Env map[string]string
+
+	// IngestionAuth is how the OTel Collector authenticates to Tempo (see the
+	// IngestionAuth constants). Default: IngestionAuthSAToken
+	IngestionAuth string
+
+	// Image pins the image of the Tempo containers, by tag or digest. TempoStack
+	// sets it in spec.images; TempoMonolithic has no image override, so it is
+	// patched into the workloads and the CR switched to unmanaged.
+	Image string
+
+	// MetricsGenerator enables the metrics-generator, which derives metrics from
+	// the ingested spans. Only applies to TempoMonolithic (not stack).
+	MetricsGenerator *MetricsGeneratorConfig
+
+	// Block sets the format of the trace blocks Tempo writes, to compare storage
+	// formats. Unset fields keep the Tempo defaults.
+	Block *BlockConfig
+
+	// Replicas is the number of TempoMonolithic pods, for HA benchmarks. Only
+	// applies to TempoMonolithic (not stack). Default: 1
+	Replicas int
+
+	// Tenants are the tenants of the multitenancy gateway. Default: [DefaultTenant]
+	Tenants []string
 }
 
 // TempoOverrides defines Tempo limits and overrides
@@ -72,9 +117,62 @@ type IngesterConfig struct {
 	ConcurrentFlushes *int
 }
 
-// StorageConfig defines S3-compatible storage configuration
+// AutoscalingConfig defines HorizontalPodAutoscaler settings for TempoStack components.
+// Autoscaling switches the TempoStack to unmanaged, otherwise the operator would
+// reset the replica counts chosen by the HPAs.
+type AutoscalingConfig struct {
+	// Components to autoscale: distributor, querier, query-frontend, compactor
+	// Default: distributor and querier
+	Components []string
+
+	// MinReplicas is the lower replica bound of each HPA
+	// Default: 1
+	MinReplicas int
+
+	// MaxReplicas is the upper replica bound of each HPA
+	MaxReplicas int
+
+	// TargetCPUUtilization is the average CPU utilization target in percent of requests
+	// Default: 80
+	TargetCPUUtilization *int
+
+	// TargetMemoryUtilization is the average memory utilization target in percent of requests.
+	// If nil, only CPU is used.
+	TargetMemoryUtilization *int
+}
+
+// MetricsGeneratorConfig defines the metrics-generator of TempoMonolithic
+type MetricsGeneratorConfig struct {
+	// Processors enabled for all tenants: span-metrics, service-graphs
+	// Default: span-metrics and service-graphs
+	Processors []string
+
+	// RemoteWriteURL is the Prometheus remote write endpoint the generated series
+	// are sent to. If empty, the series are generated but not sent anywhere.
+	RemoteWriteURL string
+}
+
+// BlockConfig defines the storage.trace.block settings of Tempo
+type BlockConfig struct {
+	// Version is the block format: v2, vParquet2, vParquet3 or vParquet4
+	Version string
+
+	// Encoding is the compression of v2 blocks, e.g. zstd or snappy. Only applies
+	// to Version v2, parquet blocks have their own compression.
+	Encoding string
+
+	// BloomFilterFalsePositive is the false positive rate of the bloom filters
+	BloomFilterFalsePositive *float64
+
+	// BloomFilterShardSizeBytes is the size of a bloom filter shard
+	BloomFilterShardSizeBytes *int
+}
+
+// StorageConfig defines the trace storage of Tempo: S3-compatible object storage,
+// or a persistent volume for TempoMonolithic
 type StorageConfig struct {
-	// Type is the storage type: "minio" (default, in-cluster) or "s3" (external AWS S3)
+	// Type is the storage type: "minio" (default, in-cluster), "s3" (external AWS S3),
+	// or "pv" (local persistent volume, monolithic only; no object storage is used)
 	Type string
 
 	// SecretName is the name of the secret containing S3 credentials.
@@ -100,6 +198,14 @@ type StorageConfig struct {
 
 	// Insecure allows insecure (non-TLS) connections to the S3 endpoint
 	Insecure bool
+
+	// Size is the size of the persistent volume of the "pv" type (e.g., "20Gi").
+	// Default: 10Gi (operator default)
+	Size string
+
+	// StorageClassName is the StorageClass of the persistent volume of the "pv" type.
+	// If empty, the default StorageClass of the cluster is used.
+	StorageClassName string
 }
 
 // FrameworkOperations provides access to framework capabilities needed by tempo
@@ -117,6 +223,10 @@ type FrameworkOperations interface {
 // variant: "monolithic" or "stack"
 // resources: optional resource configuration
 func Setup(fw FrameworkOperations, variant string, resources *ResourceConfig) error {
+	if err := validateResources(variant, resources); err != nil {
+		return err
+	}
+
 	// Set up external S3 storage secret if configured
 	if resources != nil && resources.Storage != nil && resources.Storage.Type == "s3" {
 		if err := SetupStorageSecret(fw, resources.Storage); err != nil {
@@ -124,6 +234,13 @@ func Setup(fw FrameworkOperations, variant string, resources *ResourceConfig) er
 		}
 	}
 
+	// Create ConfigMaps/Secrets for extra config files before the CR references them
+	if resources != nil && len(resources.ExtraFiles) > 0 {
+		if err := CreateExtraConfigFiles(fw, resources.ExtraFiles); err != nil {
+			return fmt.Errorf("failed to create extra config files: %w", err)
+		}
+	}
+
 	switch variant {
 	case "monolithic":
 		return SetupMonolithic(fw, resources)
@@ -134,6 +251,66 @@ func Setup(fw FrameworkOperations, variant string, resources *ResourceConfig) er
 	}
 }
 
+// validateResources checks the resource configuration of a Tempo variant
+func validateResources(variant string, resources *ResourceConfig) error {
+	if resources == nil {
+		return nil
+	}
+	if err := validateStorage(variant, resources.Storage); err != nil {
+		return err
+	}
+	if err := validateIngestionAuth(variant, resources.IngestionAuth); err != nil {
+		return err
+	}
+	if err := validateExtraConfig(resources); err != nil {
+		return err
+	}
+	if err := validateMetricsGenerator(variant, resources.MetricsGenerator); err != nil {
+		return err
+	}
+	if err := validateBlock(resources.Block); err != nil {
+		return err
+	}
+	if err := validateComponentResources(variant, resources.ComponentResources); err != nil {
+		return err
+	}
+	return validateReplicas(variant, resources.Replicas, resources.Storage)
+}
+
+// BuildCR returns the Tempo CR Setup creates for variant, labeled with managedLabels.
+// Env, image and extra files are patched into the operator-managed workloads after
+// the CR is created, so they are not part of it.
+func BuildCR(namespace, variant string, resources *ResourceConfig, managedLabels map[string]string) (*unstructured.Unstructured, error) {
+	if err := validateResources(variant, resources); err != nil {
+		return nil, err
+	}
+	switch variant {
+	case "monolithic":
+		return buildMonolithicObject(namespace, resources, managedLabels)
+	case "stack":
+		return buildStackObject(namespace, resources, managedLabels)
+	default:
+		return nil, fmt.Errorf("invalid tempo variant: %s (must be 'monolithic' or 'stack')", variant)
+	}
+}
+
+// withLabels converts a typed CR to unstructured and adds labels to it
+func withLabels(obj interface{}, labels map[string]string) (*unstructured.Unstructured, error) {
+	u, err := toUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	merged := u.GetLabels()
+	if merged == nil {
+		merged = make(map[string]string)
+	}
+	for k, v := range labels {
+		merged[k] = v
+	}
+	u.SetLabels(merged)
+	return u, nil
+}
+
 // SetupStorageSecret creates the S3 storage secret for external S3 storage
 func SetupStorageSecret(fw FrameworkOperations, storage *StorageConfig) error {
 	if storage == nil {
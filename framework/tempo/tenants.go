@@ -0,0 +1,26 @@
+package tempo
+
+import (
+	tempoapi "github.com/grafana/tempo-operator/api/tempo/v1alpha1"
+)
+
+// DefaultTenant is the tenant of the gateway when none are configured (must match
+// k6 and otel packages)
+const DefaultTenant = "tenant-1"
+
+// buildTenantsAuthentication returns the tenants of the gateway. In OpenShift mode
+// the tenant name is also the tenant ID Tempo stores the traces under.
+func buildTenantsAuthentication(resources *ResourceConfig) []tempoapi.AuthenticationSpec {
+	tenants := []string{DefaultTenant}
+	if resources != nil && len(resources.Tenants) > 0 {
+		tenants = resources.Tenants
+	}
+	authentication := make([]tempoapi.AuthenticationSpec, 0, len(tenants))
+	for _, tenant := range tenants {
+		authentication = append(authentication, tempoapi.AuthenticationSpec{
+			TenantName: tenant,
+			TenantID:   tenant,
+		})
+	}
+	return authentication
+}
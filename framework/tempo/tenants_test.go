@@ -0,0 +1,24 @@
+package tempo
+
+import "testing"
+
+func TestBuildTenantsAuthentication_Default(t *testing.T) {
+	for _, resources := range []*ResourceConfig{nil, {}} {
+		auth := buildTenantsAuthentication(resources)
+		if len(auth) != 1 || auth[0].TenantName != DefaultTenant || auth[0].TenantID != DefaultTenant {
+			t.Errorf("expected only %s, got %+v", DefaultTenant, auth)
+		}
+	}
+}
+
+func TestBuildTenantsAuthentication_Tenants(t *testing.T) {
+	cr := buildTempoStackCR("perf", &ResourceConfig{Tenants: []string{"tenant-1", "tenant-2"}})
+
+	auth := cr.Spec.Tenants.Authentication
+	if len(auth) != 2 {
+		t.Fatalf("expected 2 tenants, got %+v", auth)
+	}
+	if auth[1].TenantName != "tenant-2" || auth[1].TenantID != "tenant-2" {
+		t.Errorf("expected tenant-2 as name and ID, got %+v", auth[1])
+	}
+}
@@ -0,0 +1,74 @@
+package tempo
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/gvr"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// GetVersion returns the version of the deployed Tempo.
+// TempoStack reports it in status.tempoVersion; otherwise it is taken from the
+// image tag of the Tempo container.
+func GetVersion(fw FrameworkOperations, variant string) (string, error) {
+	var crGVR schema.GroupVersionResource
+	var crName string
+	switch variant {
+	case "monolithic":
+		crGVR, crName = gvr.TempoMonolithic, "simplest"
+	case "stack":
+		crGVR, crName = gvr.TempoStack, "tempostack"
+	default:
+		return "", fmt.Errorf("invalid tempo variant: %s (must be 'monolithic' or 'stack')", variant)
+	}
+
+	cr, err := fw.DynamicClient().Resource(crGVR).Namespace(fw.Namespace()).Get(fw.Context(), crName, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get %s %s: %w", crGVR.Resource, crName, err)
+	}
+	if version, _, _ := unstructured.NestedString(cr.Object, "status", "tempoVersion"); version != "" {
+		return version, nil
+	}
+
+	// The first StatefulSet runs the tempo container for both variants (ingester for stacks)
+	_, statefulSets := tempoWorkloads(crGVR, crName)
+	sts, err := fw.Client().AppsV1().StatefulSets(fw.Namespace()).Get(fw.Context(), statefulSets[0], metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get statefulset %s: %w", statefulSets[0], err)
+	}
+	for _, c := range sts.Spec.Template.Spec.Containers {
+		if c.Name == tempoContainerName {
+			return imageVersion(c.Image), nil
+		}
+	}
+
+	return "", fmt.Errorf("no %s container in statefulset %s", tempoContainerName, statefulSets[0])
+}
+
+// imageVersion returns the tag of an image reference, or its digest if it has no tag
+func imageVersion(image string) string {
+	if name, digest, ok := strings.Cut(image, "@"); ok {
+		if tag := imageTag(name); tag != "" {
+			return tag
+		}
+		return digest
+	}
+	if tag := imageTag(image); tag != "" {
+		return tag
+	}
+	return "latest"
+}
+
+// imageTag returns the tag of an image name without digest, or an empty string
+func imageTag(name string) string {
+	// A colon before the last slash belongs to the registry port
+	i := strings.LastIndex(name, ":")
+	if i < 0 || i < strings.LastIndex(name, "/") {
+		return ""
+	}
+	return name[i+1:]
+}
@@ -0,0 +1,22 @@
+package tempo
+
+import "testing"
+
+func TestImageVersion(t *testing.T) {
+	tests := []struct {
+		image string
+		want  string
+	}{
+		{"docker.io/grafana/tempo:2.7.2", "2.7.2"},
+		{"registry.example.com:5000/tempo:v2.8.0", "v2.8.0"},
+		{"registry.example.com:5000/tempo", "latest"},
+		{"quay.io/tempo@sha256:abc123", "sha256:abc123"},
+		{"quay.io/tempo:2.7.2@sha256:abc123", "2.7.2"},
+	}
+
+	for _, tt := range tests {
+		if got := imageVersion(tt.image); got != tt.want {
+			t.Errorf("imageVersion(%q) = %q, want %q", tt.image, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package framework
+
+import (
+	"context"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WithTracerProvider records the operations of the framework (setup, wait loops,
+// Prometheus queries, cleanup) as spans of tp. The spans are children of the span
+// of the framework context, if any.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(f *Framework) {
+		f.tracerProvider = tp
+	}
+}
+
+// operation is the view of a Framework inside a traced operation. The subsystems
+// it is passed to get the context of the operation's span from Context, so their
+// waits and queries are recorded as its children.
+type operation struct {
+	*Framework
+	ctx context.Context
+}
+
+// Context returns the context of the operation's span
+func (o operation) Context() context.Context {
+	return o.ctx
+}
+
+// startOperation starts the span of a framework operation, see tracing.Start
+func (f *Framework) startOperation(name string, attrs ...attribute.KeyValue) (operation, trace.Span) {
+	attrs = append(attrs, attribute.String("k8s.namespace.name", f.namespace))
+	if f.runID != "" {
+		attrs = append(attrs, attribute.String("tempo_perf.run_id", f.runID))
+	}
+	ctx, span := tracing.Start(f.ctx, name, attrs...)
+	return operation{Framework: f, ctx: ctx}, span
+}
@@ -0,0 +1,50 @@
+// Package tracing records the operations of the framework itself, such as setup,
+// wait loops and Prometheus queries, as OpenTelemetry spans, so long setup phases
+// and retries can be analyzed as traces.
+//
+// Spans are only recorded when the context carries a TracerProvider, set with
+// ContextWithTracerProvider (framework.WithTracerProvider does it for the
+// framework context), or a recording span. Otherwise they are no-ops.
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope of the spans of the framework
+const ScopeName = "github.com/redhat/perf-tests-tempo/test/framework"
+
+type providerKey struct{}
+
+// ContextWithTracerProvider returns a copy of ctx whose framework operations are
+// recorded with tp
+func ContextWithTracerProvider(ctx context.Context, tp trace.TracerProvider) context.Context {
+	return context.WithValue(ctx, providerKey{}, tp)
+}
+
+// TracerProvider returns the provider set on ctx with ContextWithTracerProvider,
+// or else the provider of the span of ctx, which is a no-op one without a span
+func TracerProvider(ctx context.Context) trace.TracerProvider {
+	if tp, ok := ctx.Value(providerKey{}).(trace.TracerProvider); ok && tp != nil {
+		return tp
+	}
+	return trace.SpanFromContext(ctx).TracerProvider()
+}
+
+// Start starts the span of a framework operation as a child of the span of ctx
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return TracerProvider(ctx).Tracer(ScopeName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records err on span, if any, and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,60 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestStart_ContextProvider(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	ctx := ContextWithTracerProvider(context.Background(), tp)
+
+	parentCtx, parent := Start(ctx, "framework.SetupTempo", attribute.String("tempo.variant", "stack"))
+	_, child := Start(parentCtx, "wait.ForPodsReady")
+	End(child, errors.New("pods not ready"))
+	End(parent, nil)
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	if spans[0].Name() != "wait.ForPodsReady" || spans[0].Parent().SpanID() != spans[1].SpanContext().SpanID() {
+		t.Errorf("expected the wait span to be a child of the setup span")
+	}
+	if spans[0].Status().Code != codes.Error || len(spans[0].Events()) != 1 {
+		t.Errorf("expected the wait span to record its error, got %+v", spans[0].Status())
+	}
+	if spans[1].Status().Code != codes.Unset {
+		t.Errorf("expected the setup span to succeed, got %+v", spans[1].Status())
+	}
+}
+
+func TestStart_SpanProvider(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	// Without a provider on the context, the provider of its span is used
+	ctx, root := tp.Tracer("test").Start(context.Background(), "perf-run")
+	_, span := Start(ctx, "metrics.QueryRange")
+	End(span, nil)
+	root.End()
+
+	if got := len(recorder.Ended()); got != 2 {
+		t.Errorf("expected 2 spans, got %d", got)
+	}
+}
+
+func TestStart_NoProvider(t *testing.T) {
+	_, span := Start(context.Background(), "framework.Cleanup")
+	if span.IsRecording() {
+		t.Errorf("expected a no-op span without a provider")
+	}
+	End(span, errors.New("ignored"))
+}
@@ -60,8 +60,13 @@ const (
 	LabelManagedBy = "tempo-perf-test.io/managed-by"
 	// LabelInstance is the label key used to identify the specific framework instance
 	LabelInstance = "tempo-perf-test.io/instance"
+	// LabelRunID is the label key used to identify the perf-runner invocation that created a resource
+	LabelRunID = "tempo-perf-test.io/run-id"
 	// LabelManagedByValue is the value for the managed-by label
 	LabelManagedByValue = "framework"
+	// AnnotationExpiresAt marks a preserved namespace with the RFC3339 time after which
+	// it is considered stale and may be garbage collected
+	AnnotationExpiresAt = "tempo-perf-test.io/expires-at"
 )
 
 // TrackedResource represents a resource created by the framework
@@ -94,11 +99,171 @@ type ResourceConfig struct {
 	// Storage configures S3-compatible storage for Tempo.
 	// If nil, uses default MinIO setup (requires calling SetupMinIO first).
 	Storage *StorageConfig
+
+	// ExtraFiles are ConfigMap/Secret backed files mounted into Tempo and
+	// referenced from extraConfig (e.g. a per-tenant overrides file)
+	ExtraFiles []ExtraConfigFile
+
+	// ExtraConfig is raw Tempo configuration merged into the extraConfig of the
+	// Tempo CR, for settings without a field of their own. Setting a key that
+	// another field sets (e.g. ingester.max_block_duration with
+	// Overrides.Ingester.MaxBlockDuration) is an error.
+	ExtraConfig map[string]interface{}
+
+	// Autoscaling creates HorizontalPodAutoscalers for TempoStack components.
+	// Only applies to TempoStack (not monolithic).
+	Autoscaling *AutoscalingConfig
+
+	// ComponentResources sets the resources of TempoStack components by name:
+	// distributor, ingester, querier, query-frontend, compactor or gateway. They
+	// take precedence over the share of the component of the Profile or Resources
+	// total. Only applies to TempoStack (not monolithic).
+	ComponentResources map[string]*corev1.ResourceRequirements
+
+	// Env sets environment variables of the Tempo containers, e.g. GOGC and
+	// GOMEMLIMIT. The operator has no API for them, so setting it switches the
+	// Tempo CR to unmanaged for the rest of the run.
+	Env map[string]string
+
+	// IngestionAuth is how the OTel Collector authenticates to Tempo: "sa-token"
+	// (default), "static-token", "mtls" or "none". "mtls" and "none" deploy
+	// TempoMonolithic without the gateway.
+	IngestionAuth string
+
+	// Image pins the image of the Tempo containers, by tag or digest, e.g.
+	// "quay.io/org/tempo@sha256:...". SetupTempo fails if the pods run another image.
+	Image string
+
+	// MetricsGenerator enables the Tempo metrics-generator, which derives span
+	// metrics and service graphs from the ingested spans. Only applies to
+	// TempoMonolithic (not stack), as the operator deploys no generator for TempoStack.
+	MetricsGenerator *MetricsGeneratorConfig
+
+	// Block sets the format of the trace blocks Tempo writes (storage.trace.block),
+	// to compare the performance of storage formats. Unset fields keep the Tempo defaults.
+	Block *BlockConfig
+
+	// Replicas is the number of TempoMonolithic pods, to benchmark an HA monolithic
+	// deployment. Only applies to TempoMonolithic (not stack), requires object
+	// storage and an operator whose TempoMonolithic supports replicas. Default: 1
+	Replicas int
+
+	// Tenants are the tenants of the multitenancy gateway, e.g. to load Tempo with
+	// several tenants at once. Default: ["tenant-1"]
+	Tenants []string
+
+	// CollectorImage pins the image of the OTel Collector deployed by
+	// SetupOTelCollector, which fails if the pods run another image
+	CollectorImage string
+
+	// IngestionProtocol is the OTLP protocol the OTel Collector exports traces to
+	// Tempo with: "grpc" (default) or "http". Set k6.Config.Protocol for the
+	// protocol k6 sends to the collector with.
+	IngestionProtocol string
+
+	// Kafka routes the ingestion through Kafka, see KafkaConfig (optional)
+	Kafka *KafkaConfig
+}
+
+// ExtraConfigFile is a set of files stored in a ConfigMap or Secret, mounted into
+// the Tempo containers and referenced from the Tempo extraConfig.
+// Mounting switches the Tempo CR to unmanaged, since the operator has no volume API.
+// The operator then stops reconciling the whole CR for the rest of the run.
+type ExtraConfigFile struct {
+	// Name is the name of the ConfigMap or Secret
+	Name string
+
+	// Secret stores the files in a Secret instead of a ConfigMap
+	Secret bool
+
+	// Files maps file names to their content
+	Files map[string]string
+
+	// MountPath is the directory the files are mounted at.
+	// Default: /conf/extra/<Name>
+	MountPath string
+
+	// References maps dotted Tempo config keys to a file name in Files.
+	// Example: {"overrides.per_tenant_override_config": "overrides.yaml"}
+	References map[string]string
 }
 
-// StorageConfig defines S3-compatible storage configuration
+// AutoscalingConfig defines HorizontalPodAutoscaler settings for TempoStack components.
+// Autoscaling switches the TempoStack to unmanaged, otherwise the operator would
+// reset the replica counts chosen by the HPAs.
+type AutoscalingConfig struct {
+	// Components to autoscale: distributor, querier, query-frontend, compactor
+	// Default: distributor and querier
+	Components []string
+
+	// MinReplicas is the lower replica bound of each HPA
+	// Default: 1
+	MinReplicas int
+
+	// MaxReplicas is the upper replica bound of each HPA
+	MaxReplicas int
+
+	// TargetCPUUtilization is the average CPU utilization target in percent of requests
+	// Default: 80
+	TargetCPUUtilization *int
+
+	// TargetMemoryUtilization is the average memory utilization target in percent of requests.
+	// If nil, only CPU is used.
+	TargetMemoryUtilization *int
+}
+
+// MetricsGeneratorConfig defines the Tempo metrics-generator, enabled for all tenants
+type MetricsGeneratorConfig struct {
+	// Processors to enable: span-metrics, service-graphs
+	// Default: span-metrics and service-graphs
+	Processors []string
+
+	// RemoteWriteURL is the Prometheus remote write endpoint the generated series
+	// are sent to. If empty, the series are generated but not sent anywhere.
+	RemoteWriteURL string
+}
+
+// BlockConfig defines the format of the trace blocks Tempo writes
+type BlockConfig struct {
+	// Version is the block format: v2, vParquet2, vParquet3 or vParquet4
+	Version string
+
+	// Encoding is the compression of v2 blocks: none, gzip, lz4-64k, lz4-256k,
+	// lz4-1M, lz4, snappy, zstd or s2. Requires Version v2.
+	Encoding string
+
+	// BloomFilterFalsePositive is the false positive rate of the bloom filters, in (0, 1)
+	BloomFilterFalsePositive *float64
+
+	// BloomFilterShardSizeBytes is the size of a bloom filter shard in bytes
+	BloomFilterShardSizeBytes *int
+}
+
+// KafkaConfig routes the ingestion through Kafka: the OTel Collector k6 sends to
+// writes the traces to a Kafka topic, and SetupOTelCollector deploys a second
+// collector consuming the topic and forwarding the traces to Tempo
+type KafkaConfig struct {
+	// Brokers are the bootstrap addresses (host:port) of an existing Kafka cluster.
+	// If empty, SetupOTelCollector deploys a single Kafka broker in the namespace.
+	Brokers []string
+
+	// Topic is the topic of the traces
+	// Default: "otlp_spans", or "otlp_spans-<run ID>" with Brokers
+	Topic string
+
+	// Partitions is the number of partitions of the topics of the deployed broker
+	// Default: 1
+	Partitions int
+
+	// Resources are the resource requirements of the deployed broker (optional)
+	Resources *corev1.ResourceRequirements
+}
+
+// StorageConfig defines the trace storage of Tempo: S3-compatible object storage,
+// or a persistent volume for TempoMonolithic
 type StorageConfig struct {
-	// Type is the storage type: "minio" (default, in-cluster) or "s3" (external AWS S3)
+	// Type is the storage type: "minio" (default, in-cluster), "s3" (external AWS S3),
+	// or "pv" (local persistent volume, monolithic only; no object storage is used)
 	Type string
 
 	// SecretName is the name of the secret containing S3 credentials.
@@ -124,6 +289,14 @@ type StorageConfig struct {
 
 	// Insecure allows insecure (non-TLS) connections to the S3 endpoint
 	Insecure bool
+
+	// Size is the size of the persistent volume of the "pv" type (e.g., "20Gi").
+	// Default: 10Gi (operator default)
+	Size string
+
+	// StorageClassName is the StorageClass of the persistent volume of the "pv" type.
+	// If empty, the default StorageClass of the cluster is used.
+	StorageClassName string
 }
 
 // TempoOverrides defines Tempo limits and overrides
@@ -0,0 +1,349 @@
+// Package upload copies the artifacts of a run to an S3 or GCS bucket.
+//
+// CI workers are ephemeral, so the runner can push the results directory of a run
+// (metrics, dashboards, logs, run metadata) to object storage with one key per file:
+//
+//	<prefix>/<run-id>/<path in the results directory>
+//
+// Requests are signed with AWS Signature Version 4 from static credentials, like
+// the S3 storage of Tempo. gs:// URLs go to the S3-compatible XML API of Google
+// Cloud Storage, with HMAC keys of a service account as credentials. The same
+// client measures the usage of a bucket, e.g. the S3 storage of Tempo.
+package upload
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Environment variable names for upload configuration. The AWS_* variables are
+// used when the TEMPO_PERF_UPLOAD_* ones are not set.
+const (
+	EnvEndpoint        = "TEMPO_PERF_UPLOAD_ENDPOINT"
+	EnvRegion          = "TEMPO_PERF_UPLOAD_REGION"
+	EnvAccessKeyID     = "TEMPO_PERF_UPLOAD_ACCESS_KEY_ID"
+	EnvSecretAccessKey = "TEMPO_PERF_UPLOAD_SECRET_ACCESS_KEY"
+	EnvSessionToken    = "TEMPO_PERF_UPLOAD_SESSION_TOKEN"
+	EnvInsecure        = "TEMPO_PERF_UPLOAD_INSECURE"
+)
+
+// DefaultTimeout is the default timeout of the request uploading one file
+const DefaultTimeout = 5 * time.Minute
+
+// gcsEndpoint is the S3-compatible endpoint of Google Cloud Storage
+const gcsEndpoint = "storage.googleapis.com"
+
+// Target is a parsed upload URL: s3://bucket/prefix or gs://bucket/prefix
+type Target struct {
+	// Scheme is "s3" or "gs"
+	Scheme string
+	Bucket string
+	// Prefix is the key prefix without leading or trailing slashes
+	Prefix string
+}
+
+// ParseURL parses an upload URL of the form s3://bucket/prefix or gs://bucket/prefix.
+// The prefix is optional.
+func ParseURL(raw string) (*Target, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid upload URL %q: %w", raw, err)
+	}
+	if u.Scheme != "s3" && u.Scheme != "gs" {
+		return nil, fmt.Errorf("invalid upload URL %q: scheme must be s3 or gs", raw)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid upload URL %q: no bucket", raw)
+	}
+	if u.RawQuery != "" || u.Fragment != "" || u.User != nil {
+		return nil, fmt.Errorf("invalid upload URL %q: only s3://bucket/prefix or gs://bucket/prefix are supported", raw)
+	}
+	return &Target{Scheme: u.Scheme, Bucket: u.Host, Prefix: strings.Trim(u.Path, "/")}, nil
+}
+
+// String returns the URL of the target
+func (t *Target) String() string {
+	if t.Prefix == "" {
+		return fmt.Sprintf("%s://%s", t.Scheme, t.Bucket)
+	}
+	return fmt.Sprintf("%s://%s/%s", t.Scheme, t.Bucket, t.Prefix)
+}
+
+// Key returns the object key of a file of a run: <prefix>/<run-id>/<name>
+func (t *Target) Key(runID, name string) string {
+	parts := []string{runID, filepath.ToSlash(name)}
+	if t.Prefix != "" {
+		parts = append([]string{t.Prefix}, parts...)
+	}
+	return path.Join(parts...)
+}
+
+// Config holds the credentials and endpoint of the object storage
+type Config struct {
+	// Endpoint is the S3 endpoint, e.g. "https://minio.example.com:9000".
+	// Default: AWS S3 of Region for s3://, storage.googleapis.com for gs://
+	Endpoint string
+	// Region is the region requests are signed for. Default: us-east-1 for
+	// AWS S3, auto for GCS
+	Region string
+
+	AccessKeyID     string
+	SecretAccessKey string
+	// SessionToken is set for temporary credentials (optional)
+	SessionToken string
+
+	// Insecure uses http:// for an endpoint given without a scheme
+	Insecure bool
+
+	// Timeout of the request uploading one file. Default: DefaultTimeout
+	Timeout time.Duration
+}
+
+// FromEnv returns a Config populated from environment variables
+func FromEnv() *Config {
+	insecure, _ := strconv.ParseBool(os.Getenv(EnvInsecure))
+	return &Config{
+		Endpoint:        os.Getenv(EnvEndpoint),
+		Region:          envOr(EnvRegion, "AWS_REGION"),
+		AccessKeyID:     envOr(EnvAccessKeyID, "AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: envOr(EnvSecretAccessKey, "AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    envOr(EnvSessionToken, "AWS_SESSION_TOKEN"),
+		Insecure:        insecure,
+	}
+}
+
+// envOr returns the value of the first environment variable that is set
+func envOr(names ...string) string {
+	for _, name := range names {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// Uploader puts files into the bucket of a Target
+type Uploader struct {
+	target     *Target
+	config     Config
+	httpClient *http.Client
+	endpoint   *url.URL
+	// pathStyle puts the bucket in the path instead of the host name
+	pathStyle bool
+	now       func() time.Time
+}
+
+// New creates an Uploader for a target. It fails when credentials are missing.
+func New(target *Target, cfg *Config) (*Uploader, error) {
+	u := &Uploader{target: target, now: time.Now}
+	if cfg != nil {
+		u.config = *cfg
+	}
+	if u.config.AccessKeyID == "" || u.config.SecretAccessKey == "" {
+		return nil, fmt.Errorf("no upload credentials: set %s and %s", EnvAccessKeyID, EnvSecretAccessKey)
+	}
+	if u.config.Timeout <= 0 {
+		u.config.Timeout = DefaultTimeout
+	}
+
+	endpoint := u.config.Endpoint
+	switch {
+	case endpoint != "":
+		// Custom endpoints, e.g. MinIO, rarely resolve bucket host names
+		u.pathStyle = true
+	case target.Scheme == "gs":
+		endpoint = gcsEndpoint
+		u.pathStyle = true
+		if u.config.Region == "" {
+			u.config.Region = "auto"
+		}
+	}
+	if u.config.Region == "" {
+		u.config.Region = "us-east-1"
+	}
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("s3.%s.amazonaws.com", u.config.Region)
+	}
+	if !strings.Contains(endpoint, "://") {
+		scheme := "https"
+		if u.config.Insecure {
+			scheme = "http"
+		}
+		endpoint = scheme + "://" + endpoint
+	}
+	parsed, err := url.Parse(endpoint)
+	if err != nil || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid upload endpoint %q", u.config.Endpoint)
+	}
+	u.endpoint = parsed
+
+	u.httpClient = &http.Client{Timeout: u.config.Timeout}
+	return u, nil
+}
+
+// RunURL returns the URL of the objects of a run, e.g. s3://bucket/prefix/<run-id>/
+func (u *Uploader) RunURL(runID string) string {
+	return fmt.Sprintf("%s://%s/%s/", u.target.Scheme, u.target.Bucket, u.target.Key(runID, ""))
+}
+
+// objectURL returns the URL of an object key
+func (u *Uploader) objectURL(key string) *url.URL {
+	obj := *u.endpoint
+	base := strings.TrimSuffix(obj.Path, "/")
+	if u.pathStyle {
+		base += "/" + u.target.Bucket
+	} else {
+		obj.Host = u.target.Bucket + "." + obj.Host
+	}
+	obj.Path = base + "/" + key
+	obj.RawPath = escapePath(base) + "/" + escapePath(key)
+	return &obj
+}
+
+// PutFile uploads a local file to an object key
+func (u *Uploader) PutFile(ctx context.Context, key, filePath string) error {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u.objectURL(key).String(), bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.ContentLength = int64(len(data))
+	contentType := mime.TypeByExtension(filepath.Ext(filePath))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	req.Header.Set("Content-Type", contentType)
+	if u.config.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", u.config.SessionToken)
+	}
+	signV4(req, data, u.config.AccessKeyID, u.config.SecretAccessKey, u.config.Region, u.now())
+
+	resp, err := u.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("failed to upload %s: %s: %s", key, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// Result lists the objects written by UploadRun
+type Result struct {
+	// Keys are the object keys of the uploaded files
+	Keys []string
+	// Bytes is the total size of the uploaded files
+	Bytes int64
+}
+
+// UploadRun uploads the files of a run in dir to <prefix>/<run-id>/, keeping their
+// paths relative to dir. The files of a run are the entries of dir whose name
+// contains the run ID as a dash-separated token, e.g. small-a1b2c3-metrics.csv or
+// the log directory tempo-perf-small-a1b2c3/, including the files in such
+// directories. Every file is attempted; the errors are joined.
+func (u *Uploader) UploadRun(ctx context.Context, dir, runID string) (*Result, error) {
+	files, err := RunFiles(dir, runID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{}
+	var errs []string
+	for _, name := range files {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		filePath := filepath.Join(dir, name)
+		key := u.target.Key(runID, name)
+		if err := u.PutFile(ctx, key, filePath); err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		if info, err := os.Stat(filePath); err == nil {
+			result.Bytes += info.Size()
+		}
+		result.Keys = append(result.Keys, key)
+	}
+	if len(errs) > 0 {
+		return result, fmt.Errorf("%d of %d file(s) failed to upload: %s", len(errs), len(files), strings.Join(errs, "; "))
+	}
+	return result, nil
+}
+
+// RunFiles returns the paths, relative to dir, of the files of a run (see UploadRun),
+// sorted
+func RunFiles(dir, runID string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read results directory: %w", err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if !isRunEntry(entry.Name(), runID) {
+			continue
+		}
+		if !entry.IsDir() {
+			if entry.Type().IsRegular() {
+				files = append(files, entry.Name())
+			}
+			continue
+		}
+		err := filepath.WalkDir(filepath.Join(dir, entry.Name()), func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.Type().IsRegular() {
+				rel, err := filepath.Rel(dir, p)
+				if err != nil {
+					return err
+				}
+				files = append(files, rel)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", entry.Name(), err)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// isRunEntry reports whether a file name contains the run ID as a token delimited
+// by dashes, dots or the ends of the name
+func isRunEntry(name, runID string) bool {
+	for i := 0; ; {
+		j := strings.Index(name[i:], runID)
+		if j < 0 {
+			return false
+		}
+		start, end := i+j, i+j+len(runID)
+		before := start == 0 || name[start-1] == '-'
+		after := end == len(name) || name[end] == '-' || name[end] == '.'
+		if before && after {
+			return true
+		}
+		i = start + 1
+	}
+}
@@ -0,0 +1,99 @@
+package upload
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Usage is the size of the objects under the prefix of a Target
+type Usage struct {
+	Bytes   int64
+	Objects int64
+}
+
+// listBucketResult is the response of a ListObjectsV2 request
+type listBucketResult struct {
+	Contents []struct {
+		Size int64 `xml:"Size"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// Usage lists the objects under the prefix of the target, following continuation
+// tokens, and returns their total size and count
+func (u *Uploader) Usage(ctx context.Context) (*Usage, error) {
+	usage := &Usage{}
+	token := ""
+	for {
+		page, err := u.listObjects(ctx, token)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range page.Contents {
+			usage.Bytes += obj.Size
+			usage.Objects++
+		}
+		if !page.IsTruncated || page.NextContinuationToken == "" {
+			return usage, nil
+		}
+		token = page.NextContinuationToken
+	}
+}
+
+// listObjects requests a page of ListObjectsV2, starting at token if set
+func (u *Uploader) listObjects(ctx context.Context, token string) (*listBucketResult, error) {
+	query := url.Values{"list-type": {"2"}}
+	if u.target.Prefix != "" {
+		query.Set("prefix", u.target.Prefix+"/")
+	}
+	if token != "" {
+		query.Set("continuation-token", token)
+	}
+	bucketURL := u.bucketURL()
+	bucketURL.RawQuery = strings.ReplaceAll(query.Encode(), "+", "%20")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, bucketURL.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if u.config.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", u.config.SessionToken)
+	}
+	signV4(req, nil, u.config.AccessKeyID, u.config.SecretAccessKey, u.config.Region, u.now())
+
+	resp, err := u.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", u.target, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("failed to list %s: %s: %s", u.target, resp.Status, strings.TrimSpace(string(body)))
+	}
+	var page listBucketResult
+	if err := xml.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, fmt.Errorf("failed to parse the listing of %s: %w", u.target, err)
+	}
+	return &page, nil
+}
+
+// bucketURL returns the URL of the bucket of the target
+func (u *Uploader) bucketURL() *url.URL {
+	bucket := *u.endpoint
+	base := strings.TrimSuffix(bucket.Path, "/")
+	if u.pathStyle {
+		base += "/" + u.target.Bucket
+	} else {
+		bucket.Host = u.target.Bucket + "." + bucket.Host
+	}
+	bucket.Path = base + "/"
+	bucket.RawPath = escapePath(base) + "/"
+	return &bucket
+}
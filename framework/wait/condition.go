@@ -0,0 +1,151 @@
+package wait
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/config"
+	"github.com/redhat/perf-tests-tempo/test/framework/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// ErrTimeout is returned when a condition is not met before the timeout
+var ErrTimeout = errors.New("timed out waiting for condition")
+
+// ConfigProvider optionally provides the framework configuration, whose readiness
+// timeout and poll interval are used by the wait functions
+type ConfigProvider interface {
+	FrameworkConfig() *config.Config
+}
+
+// CRClients provides access to the clients needed to wait for custom resources
+type CRClients interface {
+	DynamicClient() dynamic.Interface
+	Context() context.Context
+	Namespace() string
+}
+
+// ForCondition calls condition immediately and then every interval until it returns
+// true, returns an error, or timeout passes. It returns the condition's error, an
+// error wrapping ErrTimeout, or the context's error when ctx is done.
+// A non-positive interval uses config.DefaultPodReadyPollInterval.
+func ForCondition(ctx context.Context, interval, timeout time.Duration, condition func() (bool, error)) error {
+	return forCondition(ctx, "wait.ForCondition", interval, timeout, condition)
+}
+
+// forCondition is ForCondition traced as a span called name, which records the
+// number of attempts and the outcome of the wait
+func forCondition(ctx context.Context, name string, interval, timeout time.Duration, condition func() (bool, error), attrs ...attribute.KeyValue) (err error) {
+	if interval <= 0 {
+		interval = config.DefaultPodReadyPollInterval
+	}
+	attrs = append(attrs, attribute.String("wait.interval", interval.String()), attribute.String("wait.timeout", timeout.String()))
+	_, span := tracing.Start(ctx, name, attrs...)
+	attempts := 0
+	defer func() {
+		span.SetAttributes(attribute.Int("wait.attempts", attempts))
+		tracing.End(span, err)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		attempts++
+		done, err := condition()
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			return fmt.Errorf("%w after %v", ErrTimeout, timeout)
+		case <-ticker.C:
+		}
+	}
+}
+
+// ForCR waits until the value at jsonpath of the custom resource name equals expected,
+// e.g. ForCR(fw, gvr.TempoStack, "tempostack", `{.status.conditions[?(@.type=="Ready")].status}`, "True").
+// The braces of the path are optional. A missing resource or field is waited for.
+// The timeout is CRReadyTimeout and the interval PodReadyPollInterval of the
+// framework configuration.
+func ForCR(c CRClients, resource schema.GroupVersionResource, name, path, expected string) error {
+	cfg := configFor(c)
+	parser := jsonpath.New(name).AllowMissingKeys(true)
+	if !strings.HasPrefix(path, "{") {
+		path = "{" + path + "}"
+	}
+	if err := parser.Parse(path); err != nil {
+		return fmt.Errorf("invalid jsonpath %q: %w", path, err)
+	}
+
+	timeout := cfg.CRReadyTimeout
+	if timeout <= 0 {
+		timeout = config.DefaultCRReadyTimeout
+	}
+
+	var last string
+	err := forCondition(c.Context(), "wait.ForCR", cfg.PodReadyPollInterval, timeout, func() (bool, error) {
+		obj, err := c.DynamicClient().Resource(resource).Namespace(c.Namespace()).Get(c.Context(), name, metav1.GetOptions{})
+		if err != nil {
+			return false, nil
+		}
+		var buf bytes.Buffer
+		if err := parser.Execute(&buf, obj.Object); err != nil {
+			return false, fmt.Errorf("failed to evaluate jsonpath %q on %s: %w", path, name, err)
+		}
+		last = buf.String()
+		return last == expected, nil
+	}, attribute.String("k8s.resource", resource.Resource), attribute.String("k8s.resource.name", name))
+	if err != nil {
+		return fmt.Errorf("%s %s: %s is %q, expected %q: %w", resource.Resource, name, path, last, expected, err)
+	}
+	return nil
+}
+
+// configFor returns the framework configuration of c, or the configuration from the
+// environment when c does not provide one
+func configFor(c any) *config.Config {
+	if cp, ok := c.(ConfigProvider); ok {
+		if cfg := cp.FrameworkConfig(); cfg != nil {
+			return cfg
+		}
+	}
+	return config.FromEnv()
+}
+
+// PollInterval returns the readiness poll interval of the framework configuration of c
+func PollInterval(c any) time.Duration {
+	if interval := configFor(c).PodReadyPollInterval; interval > 0 {
+		return interval
+	}
+	return config.DefaultPodReadyPollInterval
+}
+
+// readyTimeout returns timeout, or the PodReadyTimeout of the configuration when it is zero
+func readyTimeout(cfg *config.Config, timeout time.Duration) time.Duration {
+	if timeout > 0 {
+		return timeout
+	}
+	if cfg.PodReadyTimeout > 0 {
+		return cfg.PodReadyTimeout
+	}
+	return config.DefaultPodReadyTimeout
+}
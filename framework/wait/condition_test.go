@@ -0,0 +1,138 @@
+package wait
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/config"
+	"github.com/redhat/perf-tests-tempo/test/framework/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func TestForCondition_Met(t *testing.T) {
+	calls := 0
+	err := ForCondition(context.Background(), time.Millisecond, time.Second, func() (bool, error) {
+		calls++
+		return calls == 3, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 calls, got %d", calls)
+	}
+}
+
+func TestForCondition_Traced(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	ctx := tracing.ContextWithTracerProvider(context.Background(), tp)
+
+	calls := 0
+	err := forCondition(ctx, "wait.ForPodsReady", time.Millisecond, time.Second, func() (bool, error) {
+		calls++
+		return calls == 3, nil
+	}, attribute.String("k8s.selector", "app=tempo"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 || spans[0].Name() != "wait.ForPodsReady" {
+		t.Fatalf("expected a wait.ForPodsReady span, got %d spans", len(spans))
+	}
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range spans[0].Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	if attrs["wait.attempts"].AsInt64() != 3 || attrs["k8s.selector"].AsString() != "app=tempo" {
+		t.Errorf("expected 3 attempts on selector app=tempo, got %v", spans[0].Attributes())
+	}
+}
+
+func TestForCondition_Error(t *testing.T) {
+	want := errors.New("boom")
+	err := ForCondition(context.Background(), time.Millisecond, time.Second, func() (bool, error) {
+		return false, want
+	})
+	if !errors.Is(err, want) {
+		t.Errorf("expected the condition error, got %v", err)
+	}
+}
+
+func TestForCondition_Timeout(t *testing.T) {
+	err := ForCondition(context.Background(), time.Millisecond, 20*time.Millisecond, func() (bool, error) {
+		return false, nil
+	})
+	if !errors.Is(err, ErrTimeout) {
+		t.Errorf("expected ErrTimeout, got %v", err)
+	}
+}
+
+func TestForCondition_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := ForCondition(ctx, time.Millisecond, time.Second, func() (bool, error) {
+		return false, nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+// fakeCRClients serves custom resources from a fake dynamic client
+type fakeCRClients struct {
+	client dynamic.Interface
+	cfg    *config.Config
+}
+
+func (f *fakeCRClients) DynamicClient() dynamic.Interface { return f.client }
+func (f *fakeCRClients) Context() context.Context         { return context.Background() }
+func (f *fakeCRClients) Namespace() string                { return "test" }
+func (f *fakeCRClients) FrameworkConfig() *config.Config  { return f.cfg }
+
+func TestForCR(t *testing.T) {
+	resource := schema.GroupVersionResource{Group: "tempo.grafana.com", Version: "v1alpha1", Resource: "tempostacks"}
+	cr := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "tempo.grafana.com/v1alpha1",
+		"kind":       "TempoStack",
+		"metadata":   map[string]interface{}{"name": "tempostack", "namespace": "test"},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Pending", "status": "False"},
+				map[string]interface{}{"type": "Ready", "status": "True"},
+			},
+		},
+	}}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{resource: "TempoStackList"}, cr)
+	c := &fakeCRClients{
+		client: client,
+		cfg:    config.Default().WithCRReadyTimeout(50 * time.Millisecond),
+	}
+	c.cfg.PodReadyPollInterval = time.Millisecond
+
+	ready := `{.status.conditions[?(@.type=="Ready")].status}`
+	if err := ForCR(c, resource, "tempostack", ready, "True"); err != nil {
+		t.Errorf("expected the CR to be ready, got %v", err)
+	}
+	if err := ForCR(c, resource, "tempostack", ".status.conditions[0].status", "True"); !errors.Is(err, ErrTimeout) {
+		t.Errorf("expected ErrTimeout for a field with another value, got %v", err)
+	}
+	if err := ForCR(c, resource, "missing", ready, "True"); !errors.Is(err, ErrTimeout) {
+		t.Errorf("expected ErrTimeout for a missing CR, got %v", err)
+	}
+	if err := ForCR(c, resource, "tempostack", "{.status[", "True"); err == nil || errors.Is(err, ErrTimeout) {
+		t.Errorf("expected an invalid jsonpath error, got %v", err)
+	}
+}
@@ -0,0 +1,156 @@
+package wait
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// PodProblem is a reason a pod is not ready
+type PodProblem struct {
+	Pod string
+	// Container is empty for problems of the pod itself, e.g. Unschedulable
+	Container string
+	// Reason is e.g. Unschedulable, ImagePullBackOff, CrashLoopBackOff or OOMKilled
+	Reason  string
+	Message string
+	// Hint is the likely fix, if known
+	Hint string
+}
+
+// String describes the problem on one line
+func (p PodProblem) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "pod %s: ", p.Pod)
+	if p.Container != "" {
+		fmt.Fprintf(&b, "container %s ", p.Container)
+	}
+	b.WriteString(p.Reason)
+	if p.Message != "" {
+		fmt.Fprintf(&b, ": %s", p.Message)
+	}
+	if p.Hint != "" {
+		fmt.Fprintf(&b, " (%s)", p.Hint)
+	}
+	return b.String()
+}
+
+// key identifies a problem across polls, whose messages change, e.g. back-off delays
+func (p PodProblem) key() string {
+	return p.Pod + "/" + p.Container + "/" + p.Reason
+}
+
+// DiagnosePods returns the problems of the pods that are not ready: pods that cannot
+// be scheduled, e.g. for insufficient CPU or memory, containers waiting to start,
+// e.g. in ImagePullBackOff or CrashLoopBackOff with their last exit, and containers
+// that were terminated
+func DiagnosePods(pods []corev1.Pod) []PodProblem {
+	var problems []PodProblem
+	for _, pod := range pods {
+		if IsPodReady(&pod) {
+			continue
+		}
+		for _, cond := range pod.Status.Conditions {
+			if cond.Type == corev1.PodScheduled && cond.Status == corev1.ConditionFalse {
+				p := PodProblem{Pod: pod.Name, Reason: cond.Reason, Message: cond.Message}
+				if strings.Contains(cond.Message, "Insufficient") {
+					p.Hint = "insufficient resources: lower the resource requests or add nodes"
+				}
+				problems = append(problems, p)
+			}
+		}
+		for _, cs := range pod.Status.InitContainerStatuses {
+			if p, ok := containerProblem(pod.Name, "init "+cs.Name, cs); ok {
+				problems = append(problems, p)
+			}
+		}
+		for _, cs := range pod.Status.ContainerStatuses {
+			if p, ok := containerProblem(pod.Name, cs.Name, cs); ok {
+				problems = append(problems, p)
+			}
+		}
+	}
+	return problems
+}
+
+// containerProblem returns the problem of a container that is waiting for anything
+// but its creation, or that was terminated and is not ready
+func containerProblem(pod, name string, cs corev1.ContainerStatus) (PodProblem, bool) {
+	last := cs.LastTerminationState.Terminated
+	switch {
+	case cs.State.Waiting != nil && cs.State.Waiting.Reason != "ContainerCreating" && cs.State.Waiting.Reason != "PodInitializing":
+		w := cs.State.Waiting
+		p := PodProblem{Pod: pod, Container: name, Reason: w.Reason, Message: w.Message}
+		switch w.Reason {
+		case "ImagePullBackOff", "ErrImagePull", "InvalidImageName":
+			p.Hint = fmt.Sprintf("check the image %s and the pull secrets", cs.Image)
+		case "CrashLoopBackOff":
+			if last != nil {
+				exit := fmt.Sprintf("last exit: %s (exit code %d), %d restarts", last.Reason, last.ExitCode, cs.RestartCount)
+				if p.Message != "" {
+					exit = p.Message + "; " + exit
+				}
+				p.Message = exit
+			}
+			p.Hint = terminationHint(last)
+		}
+		return p, true
+	case last != nil && !cs.Ready:
+		return PodProblem{
+			Pod:       pod,
+			Container: name,
+			Reason:    last.Reason,
+			Message:   fmt.Sprintf("terminated with exit code %d, %d restarts", last.ExitCode, cs.RestartCount),
+			Hint:      terminationHint(last),
+		}, true
+	}
+	return PodProblem{}, false
+}
+
+// terminationHint returns the likely fix of a container termination
+func terminationHint(terminated *corev1.ContainerStateTerminated) string {
+	if terminated != nil && terminated.Reason == "OOMKilled" {
+		return "raise the memory limit"
+	}
+	return "check the container logs"
+}
+
+// problemStrings returns the descriptions of problems
+func problemStrings(problems []PodProblem) []string {
+	lines := make([]string, len(problems))
+	for i, p := range problems {
+		lines[i] = p.String()
+	}
+	return lines
+}
+
+// problemLogger logs each pod problem once, when it is first seen during a wait
+type problemLogger struct {
+	c    Clients
+	seen map[string]bool
+}
+
+// log logs the problems that were not seen before
+func (l *problemLogger) log(problems []PodProblem) {
+	if l.seen == nil {
+		l.seen = make(map[string]bool)
+	}
+	for _, p := range problems {
+		if l.seen[p.key()] {
+			continue
+		}
+		l.seen[p.key()] = true
+		if logger := l.c.Logger(); logger != nil {
+			logger.Warn("Pod not ready", "pod", p.Pod, "container", p.Container, "reason", p.Reason, "message", p.Message, "hint", p.Hint)
+		}
+	}
+}
+
+// withDiagnosis appends the descriptions of problems to a wait error, one per line
+func withDiagnosis(err error, problems []PodProblem) error {
+	if len(problems) == 0 {
+		return err
+	}
+	return fmt.Errorf("%w:\n  %s", err, strings.Join(problemStrings(problems), "\n  "))
+}
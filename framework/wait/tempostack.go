@@ -0,0 +1,269 @@
+package wait
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/config"
+	"github.com/redhat/perf-tests-tempo/test/framework/gvr"
+
+	tempoapi "github.com/grafana/tempo-operator/api/tempo/v1alpha1"
+	"go.opentelemetry.io/otel/attribute"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+// StackClients provides access to the clients needed to wait for a TempoStack
+type StackClients interface {
+	Client() kubernetes.Interface
+	DynamicClient() dynamic.Interface
+	Context() context.Context
+	Namespace() string
+}
+
+// ComponentStatus is the readiness of a TempoStack component and its workload
+type ComponentStatus struct {
+	// Name is the component, e.g. "ingester"
+	Name string
+	// Kind and Workload identify the StatefulSet or Deployment of the component
+	Kind     string
+	Workload string
+	// Replicas is the desired number of replicas
+	Replicas        int32
+	ReadyReplicas   int32
+	UpdatedReplicas int32
+	Ready           bool
+	// Pods are the pod names by phase, as reported in the TempoStack status
+	Pods map[corev1.PodPhase][]string
+	// Problems are the reasons the component is not ready, e.g. a container in
+	// CrashLoopBackOff
+	Problems []string
+}
+
+// StackReport is the readiness of a TempoStack: its status conditions and the
+// state of each component
+type StackReport struct {
+	Name       string
+	Ready      bool
+	Conditions []metav1.Condition
+	Components []ComponentStatus
+}
+
+// String describes the conditions and the components that are not ready
+func (r *StackReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "TempoStack %s:", r.Name)
+	for _, c := range r.Conditions {
+		if c.Status != metav1.ConditionTrue {
+			continue
+		}
+		fmt.Fprintf(&b, "\n  condition %s (%s)", c.Type, c.Reason)
+		if c.Message != "" {
+			fmt.Fprintf(&b, ": %s", c.Message)
+		}
+	}
+	for _, c := range r.Components {
+		if c.Ready {
+			continue
+		}
+		fmt.Fprintf(&b, "\n  %s %s/%s: %d/%d ready, %d updated", c.Name, c.Kind, c.Workload, c.ReadyReplicas, c.Replicas, c.UpdatedReplicas)
+		for _, phase := range sortedPhases(c.Pods) {
+			if phase != corev1.PodRunning {
+				fmt.Fprintf(&b, "\n    %s pods: %s", phase, strings.Join(c.Pods[phase], ", "))
+			}
+		}
+		for _, p := range c.Problems {
+			fmt.Fprintf(&b, "\n    %s", p)
+		}
+	}
+	return b.String()
+}
+
+// NotReady returns the names of the components that are not ready
+func (r *StackReport) NotReady() []string {
+	var names []string
+	for _, c := range r.Components {
+		if !c.Ready {
+			names = append(names, c.Name)
+		}
+	}
+	return names
+}
+
+// TempoStackStatus reads the status conditions of the TempoStack name and the
+// state of the StatefulSet or Deployment of each of its components
+func TempoStackStatus(c StackClients, name string) (*StackReport, error) {
+	obj, err := c.DynamicClient().Resource(gvr.TempoStack).Namespace(c.Namespace()).Get(c.Context(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get TempoStack %s: %w", name, err)
+	}
+	var stack tempoapi.TempoStack
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &stack); err != nil {
+		return nil, fmt.Errorf("failed to decode TempoStack %s: %w", name, err)
+	}
+
+	report := &StackReport{Name: name, Conditions: stack.Status.Conditions}
+	ready := meta.IsStatusConditionTrue(stack.Status.Conditions, string(tempoapi.ConditionReady))
+
+	pods := map[string]tempoapi.PodStatusMap{
+		"distributor":    stack.Status.Components.Distributor,
+		"ingester":       stack.Status.Components.Ingester,
+		"querier":        stack.Status.Components.Querier,
+		"query-frontend": stack.Status.Components.QueryFrontend,
+		"compactor":      stack.Status.Components.Compactor,
+		"gateway":        stack.Status.Components.Gateway,
+	}
+	components := []string{"distributor", "ingester", "querier", "query-frontend", "compactor"}
+	if stack.Spec.Template.Gateway.Enabled {
+		components = append(components, "gateway")
+	}
+
+	for _, component := range components {
+		status, err := componentStatus(c, name, component)
+		if err != nil {
+			return nil, err
+		}
+		status.Pods = pods[component]
+		if !status.Ready {
+			ready = false
+			status.Problems = append(status.Problems, podProblems(c, name, component)...)
+		}
+		report.Components = append(report.Components, status)
+	}
+	report.Ready = ready
+	return report, nil
+}
+
+// componentStatus returns the state of the workload of a component; the ingester
+// is a StatefulSet and all other components are Deployments
+func componentStatus(c StackClients, stack, component string) (ComponentStatus, error) {
+	status := ComponentStatus{
+		Name:     component,
+		Kind:     "Deployment",
+		Workload: fmt.Sprintf("tempo-%s-%s", stack, component),
+	}
+
+	var (
+		replicas                     *int32
+		generation, observed         int64
+		readyReplicas, updated, have int32
+		err                          error
+	)
+	if component == "ingester" {
+		status.Kind = "StatefulSet"
+		var sts *appsv1.StatefulSet
+		sts, err = c.Client().AppsV1().StatefulSets(c.Namespace()).Get(c.Context(), status.Workload, metav1.GetOptions{})
+		if err == nil {
+			replicas, generation, observed = sts.Spec.Replicas, sts.Generation, sts.Status.ObservedGeneration
+			readyReplicas, updated, have = sts.Status.ReadyReplicas, sts.Status.UpdatedReplicas, sts.Status.Replicas
+		}
+	} else {
+		var deployment *appsv1.Deployment
+		deployment, err = c.Client().AppsV1().Deployments(c.Namespace()).Get(c.Context(), status.Workload, metav1.GetOptions{})
+		if err == nil {
+			replicas, generation, observed = deployment.Spec.Replicas, deployment.Generation, deployment.Status.ObservedGeneration
+			readyReplicas, updated, have = deployment.Status.ReadyReplicas, deployment.Status.UpdatedReplicas, deployment.Status.Replicas
+		}
+	}
+	if apierrors.IsNotFound(err) {
+		status.Problems = []string{"not created yet"}
+		return status, nil
+	}
+	if err != nil {
+		return status, fmt.Errorf("failed to get %s %s: %w", status.Kind, status.Workload, err)
+	}
+
+	status.Replicas = 1
+	if replicas != nil {
+		status.Replicas = *replicas
+	}
+	status.ReadyReplicas = readyReplicas
+	status.UpdatedReplicas = updated
+	status.Ready = observed >= generation &&
+		readyReplicas >= status.Replicas &&
+		updated >= status.Replicas &&
+		// No pods of a previous revision are left
+		have == status.Replicas
+	return status, nil
+}
+
+// podProblems describes the pods of a component that are not ready (see DiagnosePods)
+func podProblems(c StackClients, stack, component string) []string {
+	pods, err := c.Client().CoreV1().Pods(c.Namespace()).List(c.Context(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("app.kubernetes.io/instance=%s,app.kubernetes.io/component=%s", stack, component),
+	})
+	if err != nil {
+		return []string{fmt.Sprintf("failed to list pods: %v", err)}
+	}
+	return problemStrings(DiagnosePods(pods.Items))
+}
+
+// ForTempoStackReady waits until the TempoStack name reports the Ready condition and
+// the workloads of all its components are rolled out and ready. It returns the last
+// report; on timeout the error wraps ErrTimeout and describes the conditions and the
+// components that are not ready. A ConfigurationError condition fails immediately,
+// as it does not resolve without changing the CR. A zero timeout uses the
+// CRReadyTimeout of the framework configuration.
+func ForTempoStackReady(c StackClients, name string, timeout time.Duration) (*StackReport, error) {
+	cfg := configFor(c)
+	if timeout <= 0 {
+		timeout = cfg.CRReadyTimeout
+	}
+	if timeout <= 0 {
+		timeout = config.DefaultCRReadyTimeout
+	}
+
+	var (
+		report  *StackReport
+		lastErr error
+	)
+	err := forCondition(c.Context(), "wait.ForTempoStackReady", cfg.PodReadyPollInterval, timeout, func() (bool, error) {
+		r, err := TempoStackStatus(c, name)
+		if err != nil {
+			// The operator may not have created everything yet
+			lastErr = err
+			return false, nil
+		}
+		report, lastErr = r, nil
+		if cond := meta.FindStatusCondition(r.Conditions, string(tempoapi.ConditionConfigurationError)); cond != nil && cond.Status == metav1.ConditionTrue {
+			return false, fmt.Errorf("TempoStack %s has a configuration error (%s): %s", name, cond.Reason, cond.Message)
+		}
+		return r.Ready, nil
+	}, attribute.String("tempo.stack", name))
+	switch {
+	case err == nil:
+		return report, nil
+	case !errors.Is(err, ErrTimeout):
+		return report, err
+	case report == nil && lastErr != nil:
+		return nil, fmt.Errorf("TempoStack %s not ready: %v: %w", name, lastErr, err)
+	case report == nil:
+		return nil, fmt.Errorf("TempoStack %s not ready: %w", name, err)
+	default:
+		notReady := "none, waiting for the Ready condition"
+		if names := report.NotReady(); len(names) > 0 {
+			notReady = strings.Join(names, ", ")
+		}
+		return report, fmt.Errorf("TempoStack %s not ready (components not ready: %s): %w\n%s", name, notReady, err, report)
+	}
+}
+
+// sortedPhases returns the phases of a pod status map in order
+func sortedPhases(pods map[corev1.PodPhase][]string) []corev1.PodPhase {
+	phases := make([]corev1.PodPhase, 0, len(pods))
+	for phase := range pods {
+		phases = append(phases, phase)
+	}
+	sort.Slice(phases, func(i, j int) bool { return phases[i] < phases[j] })
+	return phases
+}
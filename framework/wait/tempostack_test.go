@@ -0,0 +1,154 @@
+package wait
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/config"
+	"github.com/redhat/perf-tests-tempo/test/framework/gvr"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// fakeStackClients serves a TempoStack and its workloads from fake clients
+type fakeStackClients struct {
+	client  kubernetes.Interface
+	dynamic dynamic.Interface
+	cfg     *config.Config
+}
+
+func (f *fakeStackClients) Client() kubernetes.Interface     { return f.client }
+func (f *fakeStackClients) DynamicClient() dynamic.Interface { return f.dynamic }
+func (f *fakeStackClients) Context() context.Context         { return context.Background() }
+func (f *fakeStackClients) Namespace() string                { return "test" }
+func (f *fakeStackClients) FrameworkConfig() *config.Config  { return f.cfg }
+
+// newFakeStackClients creates a TempoStack with the given status conditions and
+// ready workloads of all components except notReady
+func newFakeStackClients(conditions []interface{}, notReady string, objects ...runtime.Object) *fakeStackClients {
+	cr := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "tempo.grafana.com/v1alpha1",
+		"kind":       "TempoStack",
+		"metadata":   map[string]interface{}{"name": "tempostack", "namespace": "test"},
+		"spec":       map[string]interface{}{"storage": map[string]interface{}{"secret": map[string]interface{}{"name": "s3", "type": "s3"}}},
+		"status": map[string]interface{}{
+			"conditions": conditions,
+			"components": map[string]interface{}{
+				"ingester": map[string]interface{}{"Pending": []interface{}{"tempo-tempostack-ingester-0"}},
+			},
+		},
+	}}
+
+	replicas := int32(1)
+	for _, component := range []string{"distributor", "querier", "query-frontend", "compactor"} {
+		ready := int32(1)
+		if component == notReady {
+			ready = 0
+		}
+		objects = append(objects, &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "tempo-tempostack-" + component, Namespace: "test"},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+			Status:     appsv1.DeploymentStatus{Replicas: 1, ReadyReplicas: ready, UpdatedReplicas: 1},
+		})
+	}
+	ready := int32(1)
+	if notReady == "ingester" {
+		ready = 0
+	}
+	objects = append(objects, &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "tempo-tempostack-ingester", Namespace: "test"},
+		Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+		Status:     appsv1.StatefulSetStatus{Replicas: 1, ReadyReplicas: ready, UpdatedReplicas: 1},
+	})
+
+	cfg := config.Default().WithCRReadyTimeout(50 * time.Millisecond)
+	cfg.PodReadyPollInterval = time.Millisecond
+	return &fakeStackClients{
+		client: fake.NewSimpleClientset(objects...),
+		dynamic: dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+			map[schema.GroupVersionResource]string{gvr.TempoStack: "TempoStackList"}, cr),
+		cfg: cfg,
+	}
+}
+
+func TestForTempoStackReady(t *testing.T) {
+	c := newFakeStackClients([]interface{}{
+		map[string]interface{}{"type": "Ready", "status": "True", "reason": "Ready", "lastTransitionTime": "2024-06-01T12:00:00Z"},
+	}, "")
+
+	report, err := ForTempoStackReady(c, "tempostack", 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !report.Ready || len(report.Components) != 5 || len(report.NotReady()) != 0 {
+		t.Errorf("expected 5 ready components, got %+v", report)
+	}
+}
+
+func TestForTempoStackReady_ComponentNotReady(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "tempo-tempostack-ingester-0",
+			Namespace: "test",
+			Labels:    map[string]string{"app.kubernetes.io/instance": "tempostack", "app.kubernetes.io/component": "ingester"},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  "tempo",
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff", Message: "back-off 5m0s"}},
+			}},
+		},
+	}
+	c := newFakeStackClients([]interface{}{
+		map[string]interface{}{"type": "Ready", "status": "False", "reason": "Ready", "lastTransitionTime": "2024-06-01T12:00:00Z"},
+		map[string]interface{}{"type": "Pending", "status": "True", "reason": "PendingComponents", "message": "Some TempoStack components are pending", "lastTransitionTime": "2024-06-01T12:00:00Z"},
+	}, "ingester", pod)
+
+	report, err := ForTempoStackReady(c, "tempostack", 0)
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("expected ErrTimeout, got %v", err)
+	}
+	if report == nil || report.Ready {
+		t.Fatalf("expected a report of the stack that is not ready, got %+v", report)
+	}
+	if got := report.NotReady(); len(got) != 1 || got[0] != "ingester" {
+		t.Errorf("expected only the ingester not ready, got %v", got)
+	}
+	for _, want := range []string{"ingester StatefulSet/tempo-tempostack-ingester: 0/1 ready", "Pending pods: tempo-tempostack-ingester-0", "CrashLoopBackOff", "PendingComponents"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in the diagnostics, got:\n%v", want, err)
+		}
+	}
+}
+
+func TestForTempoStackReady_ConfigurationError(t *testing.T) {
+	c := newFakeStackClients([]interface{}{
+		map[string]interface{}{"type": "ConfigurationError", "status": "True", "reason": "InvalidStorageConfig", "message": "invalid storage secret", "lastTransitionTime": "2024-06-01T12:00:00Z"},
+	}, "")
+	c.cfg.CRReadyTimeout = time.Minute
+
+	_, err := ForTempoStackReady(c, "tempostack", 0)
+	if err == nil || errors.Is(err, ErrTimeout) || !strings.Contains(err.Error(), "InvalidStorageConfig") {
+		t.Errorf("expected an immediate configuration error, got %v", err)
+	}
+}
+
+func TestForTempoStackReady_MissingStack(t *testing.T) {
+	c := newFakeStackClients(nil, "")
+	if _, err := ForTempoStackReady(c, "missing", 0); !errors.Is(err, ErrTimeout) || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected the lookup error after the timeout, got %v", err)
+	}
+}
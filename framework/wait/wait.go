@@ -2,10 +2,14 @@ package wait
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -20,81 +24,146 @@ type Clients interface {
 	Logger() *slog.Logger
 }
 
-// ForPodsReady waits for pods matching the selector to be ready
+// ForPodsReady waits for pods matching the selector to be ready. Failures to list
+// the pods are retried until the timeout. Problems of the pods that are not ready,
+// like ImagePullBackOff, CrashLoopBackOff or Unschedulable for insufficient
+// resources, are logged when first seen and included in the timeout error.
+// A zero timeout uses the PodReadyTimeout of the framework configuration.
 func ForPodsReady(c Clients, selector labels.Selector, timeout time.Duration, minReady int) error {
-	deadline := time.Now().Add(timeout)
-
-	for time.Now().Before(deadline) {
-		pods, err := c.Client().CoreV1().Pods(c.Namespace()).List(c.Context(), metav1.ListOptions{
+	cfg := configFor(c)
+	timeout = readyTimeout(cfg, timeout)
+
+	var (
+		pods    []corev1.Pod
+		ready   int
+		lastErr error
+	)
+	logger := &problemLogger{c: c}
+	err := forCondition(c.Context(), "wait.ForPodsReady", cfg.PodReadyPollInterval, timeout, func() (bool, error) {
+		list, err := c.Client().CoreV1().Pods(c.Namespace()).List(c.Context(), metav1.ListOptions{
 			LabelSelector: selector.String(),
 		})
 		if err != nil {
-			return fmt.Errorf("failed to list pods: %w", err)
+			lastErr = fmt.Errorf("failed to list pods: %w", err)
+			return false, nil
 		}
+		pods, lastErr = list.Items, nil
 
-		readyCount := 0
-		for _, pod := range pods.Items {
+		ready = 0
+		for _, pod := range pods {
 			if IsPodReady(&pod) {
-				readyCount++
+				ready++
 			}
 		}
-
-		if readyCount >= minReady && len(pods.Items) > 0 {
-			return nil
+		if ready >= minReady && len(pods) > 0 {
+			return true, nil
 		}
-
-		time.Sleep(5 * time.Second)
+		logger.log(DiagnosePods(pods))
+		return false, nil
+	}, attribute.String("k8s.selector", selector.String()), attribute.Int("wait.min_ready", minReady))
+	if !errors.Is(err, ErrTimeout) {
+		return err
 	}
-
-	return fmt.Errorf("pods not ready after %v (expected at least %d ready)", timeout, minReady)
+	if lastErr != nil {
+		return fmt.Errorf("pods not ready after %v (expected at least %d ready): %w", timeout, minReady, lastErr)
+	}
+	return withDiagnosis(fmt.Errorf("pods not ready after %v (expected at least %d ready, %d of %d ready)", timeout, minReady, ready, len(pods)), DiagnosePods(pods))
 }
 
-// ForDeploymentReady waits for a deployment to be ready
+// ForDeploymentReady waits for a deployment to be ready. Problems of its pods are
+// logged and included in the timeout error, as in ForPodsReady.
+// A zero timeout uses the PodReadyTimeout of the framework configuration.
 func ForDeploymentReady(c Clients, name string, timeout time.Duration) error {
-	deadline := time.Now().Add(timeout)
-
-	for time.Now().Before(deadline) {
+	cfg := configFor(c)
+	timeout = readyTimeout(cfg, timeout)
+
+	var (
+		pods    []corev1.Pod
+		lastErr error
+	)
+	logger := &problemLogger{c: c}
+	err := forCondition(c.Context(), "wait.ForDeploymentReady", cfg.PodReadyPollInterval, timeout, func() (bool, error) {
 		deployment, err := c.Client().AppsV1().Deployments(c.Namespace()).Get(c.Context(), name, metav1.GetOptions{})
 		if err != nil {
-			time.Sleep(2 * time.Second)
-			continue
+			lastErr = fmt.Errorf("failed to get deployment %s: %w", name, err)
+			return false, nil
 		}
+		lastErr = nil
 
 		if deployment.Status.ReadyReplicas == deployment.Status.Replicas &&
 			deployment.Status.ReadyReplicas > 0 {
-			return nil
+			return true, nil
 		}
-
-		time.Sleep(5 * time.Second)
+		if selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector); err == nil {
+			list, err := c.Client().CoreV1().Pods(c.Namespace()).List(c.Context(), metav1.ListOptions{
+				LabelSelector: selector.String(),
+			})
+			if err == nil {
+				pods = list.Items
+				logger.log(DiagnosePods(pods))
+			}
+		}
+		return false, nil
+	}, attribute.String("k8s.deployment.name", name))
+	if !errors.Is(err, ErrTimeout) {
+		return err
 	}
-
-	return fmt.Errorf("deployment %s not ready after %v", name, timeout)
+	if lastErr != nil {
+		return fmt.Errorf("deployment %s not ready after %v: %w", name, timeout, lastErr)
+	}
+	return withDiagnosis(fmt.Errorf("deployment %s not ready after %v", name, timeout), DiagnosePods(pods))
 }
 
-// ForPodsTerminated waits for pods matching the selector to be fully terminated
+// ForPodsTerminated waits for pods matching the selector to be fully terminated.
+// A zero timeout uses the PodReadyTimeout of the framework configuration.
 func ForPodsTerminated(c Clients, selector labels.Selector, timeout time.Duration) error {
-	deadline := time.Now().Add(timeout)
+	cfg := configFor(c)
+	timeout = readyTimeout(cfg, timeout)
 
-	for time.Now().Before(deadline) {
+	err := forCondition(c.Context(), "wait.ForPodsTerminated", cfg.PodReadyPollInterval, timeout, func() (bool, error) {
 		pods, err := c.Client().CoreV1().Pods(c.Namespace()).List(c.Context(), metav1.ListOptions{
 			LabelSelector: selector.String(),
 		})
 		if err != nil {
 			// If we can't list pods, they might be gone
-			return nil
-		}
-
-		if len(pods.Items) == 0 {
-			return nil
+			return true, nil
 		}
 
-		time.Sleep(5 * time.Second)
+		return len(pods.Items) == 0, nil
+	}, attribute.String("k8s.selector", selector.String()))
+	if errors.Is(err, ErrTimeout) {
+		return fmt.Errorf("pods not terminated after %v", timeout)
 	}
+	return err
+}
+
+// ForJobComplete waits for a Job to succeed, and fails as soon as it fails.
+// A zero timeout uses the PodReadyTimeout of the framework configuration.
+func ForJobComplete(c Clients, name string, timeout time.Duration) error {
+	cfg := configFor(c)
+	timeout = readyTimeout(cfg, timeout)
 
-	return fmt.Errorf("pods not terminated after %v", timeout)
+	err := forCondition(c.Context(), "wait.ForJobComplete", cfg.PodReadyPollInterval, timeout, func() (bool, error) {
+		job, err := c.Client().BatchV1().Jobs(c.Namespace()).Get(c.Context(), name, metav1.GetOptions{})
+		if err != nil {
+			return false, fmt.Errorf("failed to get job %s: %w", name, err)
+		}
+		for _, cond := range job.Status.Conditions {
+			if cond.Type == batchv1.JobFailed && cond.Status == corev1.ConditionTrue {
+				return false, fmt.Errorf("job %s failed: %s", name, cond.Message)
+			}
+		}
+		return job.Status.Succeeded > 0, nil
+	}, attribute.String("k8s.job.name", name))
+	if errors.Is(err, ErrTimeout) {
+		return fmt.Errorf("job %s not complete after %v", name, timeout)
+	}
+	return err
 }
 
-// ForTempoPodsReady waits for Tempo pods using multiple label selectors
+// ForTempoPodsReady waits for Tempo pods using multiple label selectors. Problems
+// of the Tempo pods are logged and included in the timeout error, as in ForPodsReady.
+// A zero timeout uses the PodReadyTimeout of the framework configuration.
 func ForTempoPodsReady(c Clients, timeout time.Duration) error {
 	// Try multiple label selectors (Tempo Operator uses different labels in different versions)
 	selectors := []string{
@@ -103,17 +172,34 @@ func ForTempoPodsReady(c Clients, timeout time.Duration) error {
 		"tempo.grafana.com/name=simplest",
 	}
 
-	deadline := time.Now().Add(timeout)
-	var lastErr error
+	cfg := configFor(c)
+	timeout = readyTimeout(cfg, timeout)
+	var (
+		lastErr error
+		pods    []corev1.Pod
+	)
+	logger := &problemLogger{c: c}
+
+	err := forCondition(c.Context(), "wait.ForTempoPodsReady", cfg.PodReadyPollInterval, timeout, func() (bool, error) {
+		// The pods found by any selector or by name, once each
+		seen := make(map[string]bool)
+		pods = nil
+		candidates := func(items []corev1.Pod) {
+			for _, pod := range items {
+				if !seen[pod.Name] {
+					seen[pod.Name] = true
+					pods = append(pods, pod)
+				}
+			}
+		}
 
-	for time.Now().Before(deadline) {
 		for _, selectorStr := range selectors {
 			selector, err := labels.Parse(selectorStr)
 			if err != nil {
 				continue
 			}
 
-			pods, err := c.Client().CoreV1().Pods(c.Namespace()).List(c.Context(), metav1.ListOptions{
+			list, err := c.Client().CoreV1().Pods(c.Namespace()).List(c.Context(), metav1.ListOptions{
 				LabelSelector: selector.String(),
 			})
 			if err != nil {
@@ -121,41 +207,40 @@ func ForTempoPodsReady(c Clients, timeout time.Duration) error {
 				continue
 			}
 
-			if len(pods.Items) == 0 {
-				continue
-			}
-
-			readyCount := 0
-			for _, pod := range pods.Items {
+			for _, pod := range list.Items {
 				if IsPodReady(&pod) {
-					readyCount++
+					return true, nil
 				}
 			}
-
-			if readyCount > 0 {
-				return nil
-			}
+			candidates(list.Items)
 		}
 
 		// Also try by name pattern
 		allPods, err := c.Client().CoreV1().Pods(c.Namespace()).List(c.Context(), metav1.ListOptions{})
 		if err == nil {
+			var named []corev1.Pod
 			for _, pod := range allPods.Items {
-				if (pod.Name == "tempo-simplest" ||
-					len(pod.Name) > 13 && pod.Name[:13] == "tempo-simplest") &&
-					IsPodReady(&pod) {
-					return nil
+				if strings.HasPrefix(pod.Name, "tempo-simplest") {
+					if IsPodReady(&pod) {
+						return true, nil
+					}
+					named = append(named, pod)
 				}
 			}
+			candidates(named)
 		}
 
-		time.Sleep(5 * time.Second)
+		logger.log(DiagnosePods(pods))
+		return false, nil
+	})
+	if !errors.Is(err, ErrTimeout) {
+		return err
 	}
-
+	notReady := fmt.Errorf("tempo pods not ready after %v", timeout)
 	if lastErr != nil {
-		return fmt.Errorf("tempo pods not ready after %v: %w", timeout, lastErr)
+		notReady = fmt.Errorf("tempo pods not ready after %v: %w", timeout, lastErr)
 	}
-	return fmt.Errorf("tempo pods not ready after %v", timeout)
+	return withDiagnosis(notReady, DiagnosePods(pods))
 }
 
 // IsPodReady checks if a pod is in Ready state
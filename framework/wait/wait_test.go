@@ -0,0 +1,155 @@
+package wait
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/redhat/perf-tests-tempo/test/framework/config"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// fakeClients serves objects from a fake clientset
+type fakeClients struct {
+	client kubernetes.Interface
+	cfg    *config.Config
+}
+
+func (f *fakeClients) Client() kubernetes.Interface    { return f.client }
+func (f *fakeClients) Context() context.Context        { return context.Background() }
+func (f *fakeClients) Namespace() string               { return "test" }
+func (f *fakeClients) Logger() *slog.Logger            { return slog.Default() }
+func (f *fakeClients) FrameworkConfig() *config.Config { return f.cfg }
+
+func newFakeJobClients(status batchv1.JobStatus) *fakeClients {
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "job", Namespace: "test"},
+		Status:     status,
+	}
+	return &fakeClients{
+		client: fake.NewSimpleClientset(job),
+		cfg:    &config.Config{PodReadyPollInterval: 10 * time.Millisecond, PodReadyTimeout: 50 * time.Millisecond},
+	}
+}
+
+func TestForJobComplete_Succeeded(t *testing.T) {
+	c := newFakeJobClients(batchv1.JobStatus{Succeeded: 1})
+	if err := ForJobComplete(c, "job", 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestForJobComplete_Failed(t *testing.T) {
+	c := newFakeJobClients(batchv1.JobStatus{
+		Failed: 4,
+		Conditions: []batchv1.JobCondition{
+			{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "Job has reached the specified backoff limit"},
+		},
+	})
+	err := ForJobComplete(c, "job", 0)
+	if err == nil || !strings.Contains(err.Error(), "backoff limit") {
+		t.Fatalf("expected the job failure, got %v", err)
+	}
+}
+
+func TestForJobComplete_Timeout(t *testing.T) {
+	c := newFakeJobClients(batchv1.JobStatus{Active: 1, Failed: 1})
+	err := ForJobComplete(c, "job", 0)
+	if err == nil || !strings.Contains(err.Error(), "not complete after") {
+		t.Fatalf("expected a timeout, got %v", err)
+	}
+}
+
+func newFakePodClients(pods ...corev1.Pod) *fakeClients {
+	objects := make([]runtime.Object, len(pods))
+	for i := range pods {
+		pods[i].Namespace = "test"
+		pods[i].Labels = map[string]string{"app": "minio"}
+		objects[i] = &pods[i]
+	}
+	return &fakeClients{
+		client: fake.NewSimpleClientset(objects...),
+		cfg:    &config.Config{PodReadyPollInterval: 10 * time.Millisecond, PodReadyTimeout: 50 * time.Millisecond},
+	}
+}
+
+func TestForPodsReady_Ready(t *testing.T) {
+	c := newFakePodClients(readyPod("minio-0"))
+	if err := ForPodsReady(c, labels.SelectorFromSet(labels.Set{"app": "minio"}), 0, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestForPodsReady_Diagnosis(t *testing.T) {
+	c := newFakePodClients(corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "minio-0"},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodPending,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  "minio",
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff"}},
+			}},
+		},
+	})
+	err := ForPodsReady(c, labels.SelectorFromSet(labels.Set{"app": "minio"}), 0, 1)
+	if err == nil {
+		t.Fatal("expected a timeout")
+	}
+	for _, want := range []string{"0 of 1 ready", "pod minio-0: container minio ImagePullBackOff"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not contain %q", err, want)
+		}
+	}
+}
+
+func TestForPodsReady_RetriesListErrors(t *testing.T) {
+	c := newFakePodClients(readyPod("minio-0"))
+	failures := 2
+	c.client.(*fake.Clientset).PrependReactor("list", "pods", func(k8stesting.Action) (bool, runtime.Object, error) {
+		if failures > 0 {
+			failures--
+			return true, nil, errors.New("connection refused")
+		}
+		return false, nil, nil
+	})
+	if err := ForPodsReady(c, labels.SelectorFromSet(labels.Set{"app": "minio"}), time.Second, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestForDeploymentReady_Diagnosis(t *testing.T) {
+	c := newFakePodClients(corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "minio-abc"},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodPending,
+			Conditions: []corev1.PodCondition{{
+				Type: corev1.PodScheduled, Status: corev1.ConditionFalse, Reason: "Unschedulable",
+				Message: "0/3 nodes are available: 3 Insufficient cpu.",
+			}},
+		},
+	})
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "minio", Namespace: "test"},
+		Spec:       appsv1.DeploymentSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "minio"}}},
+		Status:     appsv1.DeploymentStatus{Replicas: 1},
+	}
+	if err := c.client.(*fake.Clientset).Tracker().Add(deployment); err != nil {
+		t.Fatal(err)
+	}
+	err := ForDeploymentReady(c, "minio", 0)
+	if err == nil || !strings.Contains(err.Error(), "insufficient resources") {
+		t.Fatalf("expected the unschedulable pod in the error, got %v", err)
+	}
+}
@@ -10,7 +10,7 @@
 
 import tempo from 'k6/x/tempo';
 import { Counter } from 'k6/metrics';
-import { getConfig, getEndpoints, getTLSConfig, THRESHOLDS } from './lib/config.js';
+import { getConfig, getEndpoints, getTLSConfig, loadQueries, sampleQueryResult, THRESHOLDS } from './lib/config.js';
 import { getProfile } from './lib/trace-profiles.js';
 
 // Create failure counters - must be initialized before options export
@@ -63,7 +63,7 @@ export const options = {
 // Initialize ingestion client (connects to OTel Collector, no TLS needed)
 const ingestionClient = tempo.IngestClient({
     endpoint: endpoints.ingestion,
-    protocol: 'otlp-grpc',
+    protocol: endpoints.protocol === 'http' ? 'otlp-http' : 'otlp-grpc',
     timeout: 30,
 });
 
@@ -90,15 +90,15 @@ if (tlsConfig.queryTLSEnabled) {
 // Initialize query client
 const queryClient = tempo.QueryClient(queryClientConfig);
 
-// Predefined queries
-const queries = [
+// Predefined queries, unless QUERY_CORPUS_FILE points to a query corpus
+const queries = loadQueries([
     { query: '{ service.name="api-gateway" }', limit: 20 },
     { query: '{ service.name="user-service" }', limit: 20 },
     { query: '{ service.name="order-service" }', limit: 20 },
     { query: '{ status=error }', limit: 50 },
     { query: '{ duration>100ms }', limit: 30 },
     { query: '{ service.name="payment-service" && duration>200ms }', limit: 20 },
-];
+]);
 
 const TRACE_FETCH_PROBABILITY = 0.1;
 
@@ -115,7 +115,7 @@ export function setup() {
     Target Rate:     ${config.ingestion.mbPerSecond} MB/s
     Traces/sec:      ${tracesPerSecond} (${throughput.tracesPerVU.toFixed(2)} per VU)
     Trace Profile:   ${traceProfile.name} (${traceProfile.spans.min}-${traceProfile.spans.max} spans)
-    Endpoint:        ${endpoints.ingestion}
+    Endpoint:        ${endpoints.ingestion} (OTLP ${endpoints.protocol})
 
   QUERIES (via Tempo Gateway):
     Queries/second:  ${config.query.queriesPerSecond}
@@ -166,6 +166,9 @@ export function query() {
         return;
     }
 
+    // Record a sample of the returned traces for the correctness check
+    sampleQueryResult(result);
+
     if (result.traces && result.traces.length > 0) {
         if (Math.random() < TRACE_FETCH_PROBABILITY) {
             const traceId = result.traces[0].traceID;
@@ -50,7 +50,7 @@ export const options = {
 // Initialize ingestion client (connects to OTel Collector, no TLS needed)
 const client = tempo.IngestClient({
     endpoint: endpoints.ingestion,
-    protocol: 'otlp-grpc',
+    protocol: endpoints.protocol === 'http' ? 'otlp-http' : 'otlp-grpc',
     timeout: 30,
 });
 
@@ -67,7 +67,7 @@ export function setup() {
   Trace Profile:     ${traceProfile.name} (${traceProfile.spans.min}-${traceProfile.spans.max} spans)
   Duration:          ${config.duration}
   VUs:               ${config.vus.min} - ${config.vus.max}
-  Endpoint:          ${endpoints.ingestion} (OTel Collector)
+  Endpoint:          ${endpoints.ingestion} (OTel Collector, OTLP ${endpoints.protocol})
 ================================================================================
 `);
 
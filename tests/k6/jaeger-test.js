@@ -0,0 +1,149 @@
+// Jaeger Query Performance Test for Tempo
+// Tests the Jaeger HTTP API served by tempo-query (find traces, get trace,
+// services and operations), as used by the Jaeger UI and Jaeger-compatible tooling
+//
+// Usage:
+//   k6 run jaeger-test.js                                     # Default: medium size
+//   k6 run -e SIZE=small jaeger-test.js                       # Small load (5 QPS)
+//   k6 run -e JAEGER_QUERY_ENDPOINT=http://host:16686 jaeger-test.js
+//   k6 run -e QUERIES_PER_SECOND=30 jaeger-test.js            # Custom rate
+
+import http from 'k6/http';
+import { Counter, Trend } from 'k6/metrics';
+import { getConfig, getEndpoints, getTLSConfig, THRESHOLDS } from './lib/config.js';
+
+// Metrics must be initialized before options export so they exist even without requests.
+// Latency is recorded in seconds, tagged with the Jaeger endpoint.
+const requestDuration = new Trend('jaeger_request_duration_seconds');
+const requests = new Counter('jaeger_requests');
+const failures = new Counter('jaeger_failures');
+
+// Get configuration based on SIZE environment variable
+const config = getConfig();
+const endpoints = getEndpoints();
+const tlsConfig = getTLSConfig();
+
+// k6 options
+export const options = {
+    scenarios: {
+        jaeger: {
+            executor: 'constant-arrival-rate',
+            rate: config.query.queriesPerSecond,
+            timeUnit: '1s',
+            duration: config.duration,
+            preAllocatedVUs: config.vus.min,
+            maxVUs: config.vus.max,
+        },
+    },
+    thresholds: THRESHOLDS.jaeger,
+    // k6/http cannot load the service CA file, so the gateway certificate is only
+    // verified when TLS is disabled or a trusted certificate is used
+    insecureSkipTLSVerify: tlsConfig.queryTLSEnabled,
+};
+
+// Authentication headers for the Tempo gateway
+const headers = {};
+if (tlsConfig.queryTLSEnabled && tlsConfig.tokenFile) {
+    headers['Authorization'] = `Bearer ${open(tlsConfig.tokenFile).trim()}`;
+} else if (endpoints.token) {
+    headers['Authorization'] = `Bearer ${endpoints.token}`;
+}
+if (endpoints.tenant) {
+    headers['X-Scope-OrgID'] = endpoints.tenant;
+}
+
+// Services defined in trace-profiles.js
+const services = ['api-gateway', 'user-service', 'order-service', 'payment-service', 'frontend'];
+
+// Share of iterations per request type; the remainder finds traces
+const SERVICES_PROBABILITY = 0.2;
+const OPERATIONS_PROBABILITY = 0.2;
+
+// Probability of fetching a found trace by ID, as the Jaeger UI does when a result is opened
+const TRACE_FETCH_PROBABILITY = 0.1;
+
+// get sends a GET request to a Jaeger endpoint and records its latency and outcome.
+// It returns the parsed "data" field of the response, or null on failure.
+function get(endpoint, path) {
+    const res = http.get(`${endpoints.jaeger}${path}`, {
+        headers: headers,
+        timeout: '30s',
+        tags: { endpoint: endpoint },
+    });
+
+    requestDuration.add(res.timings.duration / 1000, { endpoint: endpoint });
+    requests.add(1, { endpoint: endpoint });
+
+    if (res.status !== 200) {
+        failures.add(1, { endpoint: endpoint });
+        console.error(`Jaeger ${endpoint} request failed: HTTP ${res.status}`);
+        return null;
+    }
+    try {
+        return res.json('data');
+    } catch (e) {
+        failures.add(1, { endpoint: endpoint });
+        console.error(`Jaeger ${endpoint} returned an invalid response: ${e}`);
+        return null;
+    }
+}
+
+// Setup function - runs once before the test
+export function setup() {
+    console.log(`
+================================================================================
+  TEMPO JAEGER QUERY PERFORMANCE TEST
+================================================================================
+  Size:              ${config.name}
+  Description:       ${config.description}
+  Requests/second:   ${config.query.queriesPerSecond}
+  Duration:          ${config.duration}
+  VUs:               ${config.vus.min} - ${config.vus.max}
+  Endpoint:          ${endpoints.jaeger} (Jaeger HTTP API)
+  Tenant:            ${endpoints.tenant || '(default)'}
+  TLS:               ${tlsConfig.queryTLSEnabled ? 'enabled' : 'disabled'}
+  Trace Fetch Prob:  ${TRACE_FETCH_PROBABILITY * 100}%
+================================================================================
+`);
+
+    return {};
+}
+
+// Main test function - runs for each iteration
+export default function() {
+    const service = services[Math.floor(Math.random() * services.length)];
+    const r = Math.random();
+
+    if (r < SERVICES_PROBABILITY) {
+        get('services', '/api/services');
+        return;
+    }
+    if (r < SERVICES_PROBABILITY + OPERATIONS_PROBABILITY) {
+        get('operations', `/api/services/${encodeURIComponent(service)}/operations`);
+        return;
+    }
+
+    // Find traces of the last hour (the Jaeger API takes microseconds)
+    const end = Date.now() * 1000;
+    const start = end - 3600 * 1000 * 1000;
+    const traces = get('find_traces', `/api/traces?service=${encodeURIComponent(service)}&start=${start}&end=${end}&limit=20`);
+
+    if (traces && traces.length > 0 && Math.random() < TRACE_FETCH_PROBABILITY) {
+        const trace = traces[Math.floor(Math.random() * traces.length)];
+        get('get_trace', `/api/traces/${trace.traceID}`);
+    }
+}
+
+// Teardown function - runs once after the test
+export function teardown(data) {
+    console.log(`
+================================================================================
+  TEST COMPLETE
+================================================================================
+  Check the k6 summary above for detailed metrics:
+  - jaeger_request_duration_seconds: Latency per endpoint (find_traces, get_trace, services, operations)
+  - jaeger_requests: Total Jaeger API requests
+  - jaeger_failures: Failed Jaeger API requests
+================================================================================
+`);
+}
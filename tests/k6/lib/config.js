@@ -111,9 +111,16 @@ export function getConfig(sizeOverride) {
 
 // Get Tempo endpoints from environment
 export function getEndpoints() {
+    // OTLP protocol of the ingestion client: 'grpc' or 'http'
+    const protocol = __ENV.INGESTION_PROTOCOL || 'grpc';
     return {
-        ingestion: __ENV.TEMPO_ENDPOINT || 'http://localhost:4317',
+        ingestion: __ENV.TEMPO_ENDPOINT || (protocol === 'http' ? 'http://localhost:4318' : 'http://localhost:4317'),
+        protocol: protocol,
         query: __ENV.TEMPO_QUERY_ENDPOINT || 'http://localhost:3200',
+        // Base URL of the Jaeger HTTP API served by tempo-query
+        jaeger: __ENV.JAEGER_QUERY_ENDPOINT || 'http://localhost:16686',
+        // Base URL of the OTLP/HTTP receiver of the OTel Collector, used by the replay test
+        otlpHttp: __ENV.OTLP_HTTP_ENDPOINT || 'http://localhost:4318',
         tenant: __ENV.TEMPO_TENANT || '',
         token: __ENV.TEMPO_TOKEN || '',
     };
@@ -132,6 +139,39 @@ export function getTLSConfig() {
     };
 }
 
+// Return the searches of the query corpus at QUERY_CORPUS_FILE (written by
+// k6.WriteQueryCorpus or framework.GenerateQueryCorpus), or the given built-in
+// queries when it is not set. Must be called from the init context.
+export function loadQueries(builtin) {
+    const corpusFile = __ENV.QUERY_CORPUS_FILE;
+    if (!corpusFile) {
+        return builtin;
+    }
+    const corpus = JSON.parse(open(corpusFile));
+    if (!corpus.queries || corpus.queries.length === 0) {
+        throw new Error(`query corpus ${corpusFile} has no queries`);
+    }
+    return corpus.queries;
+}
+
+// Fraction of searches that log one of their traces for the query correctness check
+// (framework.VerifyQueryCorrectness re-fetches a percentage of them after the run)
+export const QUERY_SAMPLE_RATE = __ENV.QUERY_SAMPLE_RATE !== undefined ? parseFloat(__ENV.QUERY_SAMPLE_RATE) : 0.05;
+
+// Log a random trace of a search result as "QUERY_SAMPLE traceID=<id> spans=<count>",
+// with the span count from the serviceStats Tempo returns (0 when absent)
+export function sampleQueryResult(result) {
+    if (!result || !result.traces || result.traces.length === 0 || Math.random() >= QUERY_SAMPLE_RATE) {
+        return;
+    }
+    const trace = result.traces[Math.floor(Math.random() * result.traces.length)];
+    let spans = 0;
+    for (const stats of Object.values(trace.serviceStats || {})) {
+        spans += stats.spanCount || 0;
+    }
+    console.log(`QUERY_SAMPLE traceID=${trace.traceID} spans=${spans}`);
+}
+
 // Thresholds for test validation
 // Note: tempo_query_* metrics are only recorded by QueryWorkload, not direct API calls
 // For direct API usage, we rely on k6's built-in iteration metrics and custom counters
@@ -146,6 +186,18 @@ export const THRESHOLDS = {
         // since direct Search API doesn't record those metrics
         'tempo_query_failures_total': ['rate<1'],
     },
+    jaeger: {
+        'jaeger_failures': ['rate<1'],
+        // Thresholds on the per-endpoint submetrics also export their latency in the summary
+        'jaeger_request_duration_seconds{endpoint:find_traces}': ['p(99)<30'],
+        'jaeger_request_duration_seconds{endpoint:get_trace}': ['p(99)<30'],
+        'jaeger_request_duration_seconds{endpoint:services}': ['p(99)<30'],
+        'jaeger_request_duration_seconds{endpoint:operations}': ['p(99)<30'],
+    },
+    replay: {
+        'tempo_ingestion_traces_total': ['rate>0'],
+        'tempo_ingestion_failures_total': ['rate<1'],
+    },
     combined: {
         'tempo_ingestion_bytes_total': ['rate>0'],
         'tempo_ingestion_traces_total': ['rate>0'],
@@ -154,4 +206,4 @@ export const THRESHOLDS = {
     },
 };
 
-export default { SIZES, getConfig, getEndpoints, getTLSConfig, THRESHOLDS };
+export default { SIZES, getConfig, getEndpoints, getTLSConfig, sampleQueryResult, THRESHOLDS };
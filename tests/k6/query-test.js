@@ -7,10 +7,11 @@
 //   k6 run -e SIZE=large query-test.js                # Large load (50 QPS)
 //   k6 run -e SIZE=xlarge query-test.js               # Extreme load (100 QPS)
 //   k6 run -e QUERIES_PER_SECOND=30 query-test.js     # Custom rate
+//   k6 run -e QUERY_CORPUS_FILE=/query-corpus/queries.json query-test.js  # Query corpus
 
 import tempo from 'k6/x/tempo';
 import { Counter } from 'k6/metrics';
-import { getConfig, getEndpoints, getTLSConfig, THRESHOLDS } from './lib/config.js';
+import { getConfig, getEndpoints, getTLSConfig, loadQueries, sampleQueryResult, QUERY_SAMPLE_RATE, THRESHOLDS } from './lib/config.js';
 
 // Create failure counter - must be initialized before options export
 // so the metric exists even if there are no failures
@@ -60,10 +61,10 @@ if (tlsConfig.queryTLSEnabled) {
 // Initialize query client
 const client = tempo.QueryClient(clientConfig);
 
-// Predefined queries to execute
+// Predefined queries to execute, unless QUERY_CORPUS_FILE points to a query corpus
 // These match the services defined in trace-profiles.js
 // Note: TraceQL uses dot prefix for resource attributes (e.g., .service.name)
-const queries = [
+const queries = loadQueries([
     // Service-based queries (resource attributes use dot prefix)
     { query: '{ resource.service.name = "api-gateway" }', limit: 20 },
     { query: '{ resource.service.name = "user-service" }', limit: 20 },
@@ -82,7 +83,7 @@ const queries = [
     // Combined queries
     { query: '{ resource.service.name = "api-gateway" && status = error }', limit: 20 },
     { query: '{ resource.service.name = "payment-service" && duration > 200ms }', limit: 20 },
-];
+]);
 
 // Probability of fetching full trace details after a search
 const TRACE_FETCH_PROBABILITY = 0.1;
@@ -101,8 +102,9 @@ export function setup() {
   Endpoint:          ${endpoints.query} (Tempo Gateway)
   Tenant:            ${endpoints.tenant || '(default)'}
   TLS:               ${tlsConfig.queryTLSEnabled ? 'enabled' : 'disabled'}
-  Query Count:       ${queries.length} different queries
+  Query Count:       ${queries.length} different queries${__ENV.QUERY_CORPUS_FILE ? ` (corpus ${__ENV.QUERY_CORPUS_FILE})` : ''}
   Trace Fetch Prob:  ${TRACE_FETCH_PROBABILITY * 100}%
+  Result Sampling:   ${QUERY_SAMPLE_RATE * 100}%
 ================================================================================
 `);
 
@@ -131,6 +133,9 @@ export default function() {
         return;
     }
 
+    // Record a sample of the returned traces for the correctness check
+    sampleQueryResult(result);
+
     // Log trace count for debugging (disabled getTrace due to 404 issues with gateway)
     if (result.traces && result.traces.length > 0) {
         // Note: getTrace is disabled because the gateway returns 404 for /api/traces/{id}
@@ -0,0 +1,243 @@
+// Trace Replay Test for Tempo
+// Replays OTLP traces captured from production, preserving the time between traces,
+// so benchmarks see realistic span shapes, attributes and cardinality
+//
+// The capture is OTLP JSON: one ExportTraceServiceRequest document, or one per line
+// as written by the OpenTelemetry Collector file exporter. Traces are sent in the
+// recorded order at REPLAY_SPEEDUP times the recorded pace, with timestamps shifted
+// to the time they are sent. The recording is looped until the test duration ends;
+// each loop gets new trace IDs so replayed traces stay distinct.
+//
+// Usage:
+//   k6 run -e REPLAY_FILE=/replay/traces.json replay-test.js
+//   k6 run -e REPLAY_FILE=traces.json -e REPLAY_SPEEDUP=10 replay-test.js
+//   k6 run -e OTLP_HTTP_ENDPOINT=http://collector:4318 -e REPLAY_FILE=traces.json replay-test.js
+
+import http from 'k6/http';
+import exec from 'k6/execution';
+import { sleep } from 'k6';
+import { SharedArray } from 'k6/data';
+import { Counter, Trend } from 'k6/metrics';
+import { getConfig, getEndpoints, THRESHOLDS } from './lib/config.js';
+
+// Same metric names as the xk6-tempo ingestion test, so summaries, exports and the
+// ingestion completeness check work unchanged
+const ingestionBytes = new Counter('tempo_ingestion_bytes_total');
+const ingestionTraces = new Counter('tempo_ingestion_traces_total');
+const ingestionSpans = new Counter('tempo_ingestion_spans_total');
+const ingestionFailures = new Counter('tempo_ingestion_failures_total');
+const ingestionDuration = new Trend('tempo_ingestion_duration_seconds');
+// How late traces were sent compared to the recorded schedule. A growing lag means
+// the VUs or the collector cannot keep up with the speedup.
+const replayLag = new Trend('replay_lag_seconds');
+
+// Get configuration based on SIZE environment variable
+const config = getConfig();
+const endpoints = getEndpoints();
+
+const replayFile = __ENV.REPLAY_FILE;
+if (!replayFile) {
+    throw new Error('REPLAY_FILE must point to an OTLP JSON trace capture');
+}
+const speedup = parseFloat(__ENV.REPLAY_SPEEDUP) || 1;
+
+// Placeholder of a timestamp relative to the start of its trace, in nanoseconds
+const TIME_FIELDS = ['startTimeUnixNano', 'endTimeUnixNano', 'timeUnixNano'];
+const RELATIVE_TIME = /"__T:(-?\d+)"/g;
+const TRACE_ID = /"traceId":"([0-9a-fA-F]{32})"/g;
+
+// nsDiff returns a - b for nanosecond timestamps encoded as decimal strings, which
+// exceed the precision of JavaScript numbers. The difference itself is small enough.
+function nsDiff(a, b) {
+    const split = s => [Number(s.slice(0, -9) || '0'), Number(s.slice(-9))];
+    const [secA, nsA] = split(String(a));
+    const [secB, nsB] = split(String(b));
+    return (secA - secB) * 1e9 + (nsA - nsB);
+}
+
+// addNs returns the decimal string of base (seconds and nanoseconds) plus offset nanoseconds
+function addNs(baseSec, baseNs, offset) {
+    const total = baseNs + offset;
+    const carry = Math.floor(total / 1e9);
+    return String(baseSec + carry) + String(total - carry * 1e9).padStart(9, '0');
+}
+
+// parseCapture parses a single OTLP JSON document or JSON lines
+function parseCapture(content) {
+    try {
+        return [JSON.parse(content)];
+    } catch (e) {
+        return content.split('\n').filter(line => line.trim() !== '').map(line => JSON.parse(line));
+    }
+}
+
+// relativeTimes replaces the timestamps of a span and its events with placeholders
+// relative to the start of the trace
+function relativeTimes(obj, traceStart) {
+    if (Array.isArray(obj)) {
+        return obj.map(v => relativeTimes(v, traceStart));
+    }
+    if (obj === null || typeof obj !== 'object') {
+        return obj;
+    }
+    const out = {};
+    for (const [key, value] of Object.entries(obj)) {
+        out[key] = TIME_FIELDS.includes(key) && value !== undefined
+            ? `__T:${nsDiff(value, traceStart)}`
+            : relativeTimes(value, traceStart);
+    }
+    return out;
+}
+
+// loadTraces groups the spans of a capture by trace and returns one request body
+// template per trace, ordered by the start of the trace
+function loadTraces(content) {
+    const byTrace = new Map();
+    parseCapture(content).forEach(doc => {
+        (doc.resourceSpans || []).forEach(rs => {
+            (rs.scopeSpans || rs.instrumentationLibrarySpans || []).forEach(ss => {
+                (ss.spans || []).forEach(span => {
+                    let trace = byTrace.get(span.traceId);
+                    if (!trace) {
+                        trace = { start: null, groups: new Map(), spans: 0 };
+                        byTrace.set(span.traceId, trace);
+                    }
+                    const start = String(span.startTimeUnixNano);
+                    if (trace.start === null || nsDiff(start, trace.start) < 0) {
+                        trace.start = start;
+                    }
+                    // Keep each span with its own resource and scope
+                    let group = trace.groups.get(ss);
+                    if (!group) {
+                        group = { resource: rs.resource, schemaUrl: rs.schemaUrl, scope: ss.scope, spans: [] };
+                        trace.groups.set(ss, group);
+                    }
+                    group.spans.push(span);
+                    trace.spans++;
+                });
+            });
+        });
+    });
+
+    const traces = Array.from(byTrace.values()).sort((a, b) => nsDiff(a.start, b.start));
+    if (traces.length === 0) {
+        throw new Error(`No spans found in ${replayFile}`);
+    }
+    const first = traces[0].start;
+    return traces.map(trace => {
+        const resourceSpans = Array.from(trace.groups.values()).map(g => ({
+            resource: g.resource,
+            schemaUrl: g.schemaUrl,
+            scopeSpans: [{ scope: g.scope, spans: g.spans.map(s => relativeTimes(s, trace.start)) }],
+        }));
+        return {
+            offsetMs: nsDiff(trace.start, first) / 1e6,
+            spans: trace.spans,
+            body: JSON.stringify({ resourceSpans }),
+        };
+    });
+}
+
+// Parsed once and shared by all VUs
+const traces = new SharedArray('replay-traces', () => loadTraces(open(replayFile)));
+
+// One loop of the recording lasts from its first trace to its last, plus the average
+// gap between traces so the last and first trace of consecutive loops are spaced out
+const lastOffsetMs = traces[traces.length - 1].offsetMs;
+const loopMs = lastOffsetMs > 0 ? lastOffsetMs * traces.length / (traces.length - 1) : 1000;
+
+// k6 options - each VU sends every VUs-th trace at its recorded time
+export const options = {
+    scenarios: {
+        replay: {
+            executor: 'constant-vus',
+            vus: config.vus.max,
+            duration: config.duration,
+        },
+    },
+    thresholds: THRESHOLDS.replay,
+};
+
+// Setup function - runs once before the test
+export function setup() {
+    // SharedArray only supports indexing and for-of
+    let totalSpans = 0;
+    for (const trace of traces) {
+        totalSpans += trace.spans;
+    }
+    console.log(`
+================================================================================
+  TEMPO TRACE REPLAY TEST
+================================================================================
+  Size:              ${config.name}
+  Capture:           ${replayFile}
+  Traces:            ${traces.length} (${totalSpans} spans)
+  Recording:         ${(loopMs / 1000).toFixed(1)}s, replayed at ${speedup}x (${(loopMs / 1000 / speedup).toFixed(1)}s per loop)
+  Duration:          ${config.duration}
+  VUs:               ${config.vus.max}
+  Endpoint:          ${endpoints.otlpHttp}/v1/traces (OTel Collector, OTLP/HTTP)
+================================================================================
+`);
+}
+
+// Index of the next trace of this VU across loops of the recording
+let next = -1;
+
+// Main test function - sends the next trace of this VU when it is due
+export default function() {
+    if (next < 0) {
+        next = exec.vu.idInTest - 1;
+    }
+    const loop = Math.floor(next / traces.length);
+    const trace = traces[next % traces.length];
+    next += config.vus.max;
+
+    const due = exec.scenario.startTime + (loop * loopMs + trace.offsetMs) / speedup;
+    const wait = due - Date.now();
+    if (wait > 0) {
+        sleep(wait / 1000);
+    } else {
+        replayLag.add(-wait / 1000);
+    }
+
+    // Shift the trace to now and give every loop its own trace IDs
+    const now = Date.now();
+    const baseSec = Math.floor(now / 1000);
+    const baseNs = (now % 1000) * 1e6;
+    let body = trace.body.replace(RELATIVE_TIME, (_, offset) => `"${addNs(baseSec, baseNs, Number(offset))}"`);
+    if (loop > 0) {
+        body = body.replace(TRACE_ID, (_, id) => {
+            const low = ((parseInt(id.slice(24), 16) ^ loop) >>> 0).toString(16).padStart(8, '0');
+            return `"traceId":"${id.slice(0, 24)}${low}"`;
+        });
+    }
+
+    const res = http.post(`${endpoints.otlpHttp}/v1/traces`, body, {
+        headers: { 'Content-Type': 'application/json' },
+        tags: { name: 'otlp_traces' },
+    });
+    ingestionDuration.add(res.timings.duration / 1000);
+    if (res.status !== 200) {
+        ingestionFailures.add(1);
+        console.error(`Failed to push trace: HTTP ${res.status} ${res.body}`);
+        return;
+    }
+    ingestionBytes.add(body.length);
+    ingestionTraces.add(1);
+    ingestionSpans.add(trace.spans);
+}
+
+// Teardown function - runs once after the test
+export function teardown() {
+    console.log(`
+================================================================================
+  TEST COMPLETE
+================================================================================
+  Check the k6 summary above for detailed metrics:
+  - tempo_ingestion_traces_total: Total traces replayed
+  - tempo_ingestion_spans_total: Total spans replayed
+  - tempo_ingestion_failures_total: Failed pushes
+  - replay_lag_seconds: How late traces were sent compared to the recording
+================================================================================
+`);
+}